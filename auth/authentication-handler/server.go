@@ -743,6 +743,10 @@ func acceptHydraLogin(ctx context.Context, loginChallenge string, user *GoogleUs
 	// Create a new map to hold the context data
 	contextData := make(map[string]interface{})
 
+	// app_type and shop_id are copied into the access token at consent so the
+	// backend can tell storefront customers apart and scope them to their shop
+	contextData["app_type"] = appType
+
 	// Only include shop_id if appType is "storefront"
 	if appType == "storefront" {
 		contextData["shop_id"] = shopID
@@ -820,7 +824,17 @@ func handleConsent(c *fiber.Ctx) error {
 		zap.Strings("requested_audience", consentRequest.GetRequestedAccessTokenAudience()),
 	)
 
-	logger.Debug("Accepting consent request")
+	// Expose the login context as access token claims, visible on introspection
+	accessTokenClaims := make(map[string]interface{})
+	if loginContext, ok := consentRequest.Context.(map[string]interface{}); ok {
+		for _, key := range []string{"app_type", "shop_id"} {
+			if value, ok := loginContext[key]; ok {
+				accessTokenClaims[key] = value
+			}
+		}
+	}
+
+	logger.Debug("Accepting consent request", zap.Any("access_token_claims", accessTokenClaims))
 	redirectTo, _, err := hydraAdminClient.OAuth2API.AcceptOAuth2ConsentRequest(ctx).
 		ConsentChallenge(consentChallenge).
 		AcceptOAuth2ConsentRequest(hydra.AcceptOAuth2ConsentRequest{
@@ -828,6 +842,9 @@ func handleConsent(c *fiber.Ctx) error {
 			GrantScope:               consentRequest.RequestedScope,
 			Remember:                 hydra.PtrBool(true),
 			RememberFor:              hydra.PtrInt64(3600),
+			Session: &hydra.AcceptOAuth2ConsentRequestSession{
+				AccessToken: accessTokenClaims,
+			},
 		}).Execute()
 	if err != nil {
 		logger.Error("Failed to accept consent request in Hydra",
@@ -1,11 +1,13 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/go-redis/redis/v8"
@@ -101,8 +103,52 @@ func main() {
 		flutterwaveService,
 	)
 
+	// Initialize image storage for uploads
+	imageConfig := services.ImageProcessorConfigFromEnv()
+	imageStorage, err := services.NewObjectStorage(context.Background())
+	if err != nil {
+		logger.Fatal("Failed to initialize image storage", zap.Error(err))
+	}
+	imageUploadService := services.NewImageUploadService(imageStorage, imageConfig)
+
+	// Initialize private storage for digital products, served only through signed links
+	digitalStorage, err := services.NewDigitalAssetStorage(context.Background())
+	if err != nil {
+		logger.Fatal("Failed to initialize digital asset storage", zap.Error(err))
+	}
+	digitalDelivery, err := services.DigitalDeliveryFromEnv(digitalStorage)
+	if err != nil {
+		logger.Fatal("Failed to initialize digital delivery", zap.Error(err))
+	}
+
+	// Initialize exchange rates for presentment currencies
+	rateSource, err := services.ExchangeRateSourceFromEnv()
+	if err != nil {
+		logger.Fatal("Failed to initialize exchange rate source", zap.Error(err))
+	}
+	currencyService := services.NewCurrencyService(repo, rateSource)
+
+	// Email shops the variants at or below their reorder point once a day
+	go services.RunLowStockDigests(context.Background(), repo)
+
+	// Cancel unpaid pending orders once they stop holding stock
+	go services.RunPendingOrderExpiry(context.Background(), repo)
+
+	// Renew due subscriptions, charging their saved payment methods
+	go services.RunSubscriptionRenewals(context.Background(), repo, paymentProcessorFactory, digitalDelivery)
+
+	// Verifies storefront customers on the public GraphQL API
+	customerAuth, err := services.CustomerAuthFromEnv(retryClient)
+	if err != nil {
+		logger.Fatal("Failed to initialize customer authentication", zap.Error(err))
+	}
+
 	app := fiber.New(fiber.Config{
 		ReadBufferSize: 8192,
+		// Bodies are streamed so that upload routes can accept files larger than
+		// the default body limit, which BodyLimitFiber applies to every other route
+		StreamRequestBody:            true,
+		DisablePreParseMultipartForm: true,
 		// Global custom error handler
 		ErrorHandler: func(c *fiber.Ctx, err error) error {
 			// Default error response
@@ -147,6 +193,13 @@ func main() {
 		return err
 	})
 
+	// Upload routes take larger bodies under their own limit
+	app.Use(middleware.BodyLimitFiber(fiber.DefaultBodyLimit, func(c *fiber.Ctx) bool {
+		path := c.Path()
+		return c.Method() == fiber.MethodPost &&
+			(strings.HasSuffix(path, "/images/upload") || strings.HasSuffix(path, "/digital-assets/upload"))
+	}))
+
 	// Health check endpoints for Kubernetes
 	app.Get("/health", func(c *fiber.Ctx) error {
 		return c.JSON(fiber.Map{
@@ -258,28 +311,45 @@ func main() {
 		OAuth2RedirectUrl: fmt.Sprintf("%s/v1/docs/oauth2-redirect.html", env.API_URL),
 	}))
 
+	// Serve locally stored uploads when using the filesystem storage backend
+	if fsStorage, ok := imageStorage.(*services.FilesystemObjectStorage); ok {
+		app.Static("/uploads", fsStorage.Root)
+	}
+
 	v1 := app.Group("/v1")
 	api := v1.Group("/", middleware.WebMiddlewareFiber())
 	routes.AuthRouter(v1, repo, retryClient)
-	routes.ShopRouter(api, repo, retryClient)
+	routes.ShopRouter(api, repo, retryClient, imageUploadService)
 	routes.ProductTypeRouter(api, repo, retryClient)
-	routes.ProductRouter(api, repo, retryClient)
+	routes.ProductRouter(api, repo, retryClient, imageUploadService)
+	routes.CategoryRouter(api, repo, retryClient, imageUploadService)
+	routes.CollectionRouter(api, repo, retryClient)
+	routes.ReviewRouter(api, repo, retryClient)
 	routes.AttributeRouter(api, repo, retryClient)
+	routes.MetafieldRouter(api, repo, retryClient)
+	routes.TranslationRouter(api, repo, retryClient)
+	routes.CurrencyRouter(api, repo, retryClient, currencyService)
 	routes.UserRouter(api, repo, retryClient)
-	routes.CheckoutRouter(api, repo, retryClient, paymentProcessorFactory)
-	routes.PaymentRouter(api, repo, paymentProcessorFactory)
+	routes.CheckoutRouter(api, repo, retryClient, paymentProcessorFactory, currencyService)
+	routes.PaymentRouter(api, repo, paymentProcessorFactory, currencyService, digitalDelivery, customerAuth)
 	routes.PaymentMethodsRouter(api, repo, retryClient)
 	routes.OrderRouter(api, repo, retryClient)
 	routes.CustomerRouter(api, repo, retryClient)
+	routes.GiftCardRouter(api, repo, retryClient)
+	routes.LoyaltyRouter(api, repo, retryClient)
 	routes.InventoryRouter(api, repo, retryClient)
+	routes.PurchaseOrderRouter(api, repo, retryClient)
+	routes.DigitalDeliveryRouter(api, repo, retryClient, digitalDelivery)
+	routes.SubscriptionRouter(api, repo, retryClient)
 	routes.AnalyticsRouter(api, repo)
 	routes.TemplateRouter(api, repo, retryClient)
-	routes.WebhookRouter(v1, repo, paymentProcessorFactory)
+	routes.WebhookRouter(v1, repo, paymentProcessorFactory, digitalDelivery)
+	routes.DownloadRouter(v1, repo, digitalDelivery)
 
 	app.Get("/graph", publicgraph.NewPlaygroundHandler("/query"))
 
-	graphql := app.Group("/query", middleware.ShopIDMiddlewareFiber(repo))
-	graphql.Post("/", publicgraph.NewHandler(repo)) // public
+	graphql := app.Group("/query", middleware.ShopIDMiddlewareFiber(repo), middleware.CustomerMiddlewareFiber(repo, customerAuth), middleware.LocaleMiddlewareFiber(repo))
+	graphql.Post("/", publicgraph.NewHandler(repo, currencyService, digitalDelivery, paymentProcessorFactory)) // public
 
 	address := ":" + env.PORT
 	fmt.Fprintf(os.Stdout, "🚀 Server ready at port %s\n", address)
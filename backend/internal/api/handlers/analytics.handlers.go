@@ -1,12 +1,15 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
+	"math"
 	"strconv"
 	"time"
 
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/petrejonn/naytife/internal/api"
+	"github.com/petrejonn/naytife/internal/api/models"
 	"github.com/petrejonn/naytife/internal/db"
 
 	"github.com/gofiber/fiber/v2"
@@ -330,12 +333,13 @@ func (h *AnalyticsHandler) GetOrdersOverTime(c *fiber.Ctx) error {
 
 // GetTopProducts handles GET /shops/{shop_id}/analytics/top-products
 // @Summary      Get top products
-// @Description  Returns the top selling products for a shop in a given period
+// @Description  Returns the top selling products for a shop in a given period, ranked by units sold or by gross profit
 // @Tags         analytics
 // @Produce      json
 // @Param        shop_id path int true "Shop ID"
 // @Param        period query string false "Period (today, week, month, custom)" Enums(today, week, month, custom) default(today)
 // @Param        limit query int false "Number of products to return" default(5)
+// @Param        sort_by query string false "Rank by units sold or gross profit" Enums(units, profit) default(units)
 // @Param        start_date query string false "Start date (YYYY-MM-DD, required if period=custom)"
 // @Param        end_date query string false "End date (YYYY-MM-DD, required if period=custom)"
 // @Success      200 {object} models.SuccessResponse{data=[]map[string]interface{}} "Top products fetched successfully."
@@ -355,6 +359,13 @@ func (h *AnalyticsHandler) GetTopProducts(c *fiber.Ctx) error {
 	if err != nil || limit < 1 || limit > 100 {
 		limit = 5
 	}
+	sortBy := c.Query("sort_by", "units")
+	switch sortBy {
+	case "units", "profit":
+		// ok
+	default:
+		return api.ErrorResponse(c, 400, "Invalid sort_by", nil)
+	}
 	var start, end time.Time
 	now := time.Now().UTC()
 	switch period {
@@ -388,10 +399,11 @@ func (h *AnalyticsHandler) GetTopProducts(c *fiber.Ctx) error {
 	}
 
 	params := db.GetTopProductsParams{
-		ShopID:      shopID,
-		CreatedAt:   pgtype.Timestamptz{Time: start, Valid: true},
-		CreatedAt_2: pgtype.Timestamptz{Time: end, Valid: true},
-		Limit:       int32(limit),
+		ShopID:  shopID,
+		StartAt: pgtype.Timestamptz{Time: start, Valid: true},
+		EndAt:   pgtype.Timestamptz{Time: end, Valid: true},
+		SortBy:  sortBy,
+		Limit:   int32(limit),
 	}
 	rows, err := h.Repository.GetTopProducts(c.Context(), params)
 	if err != nil {
@@ -406,6 +418,8 @@ func (h *AnalyticsHandler) GetTopProducts(c *fiber.Ctx) error {
 			"product_name":         row.ProductName,
 			"units_sold":           unitsSold,
 			"revenue":              revenue,
+			"cost_of_goods":        models.NumericToFloat64(row.CostOfGoods),
+			"gross_profit":         models.NumericToFloat64(row.GrossProfit),
 		})
 	}
 	return api.SuccessResponse(c, 200, products, "Top products fetched successfully.")
@@ -511,11 +525,11 @@ func (h *AnalyticsHandler) GetCustomerSummary(c *fiber.Ctx) error {
 
 // GetLowStockProducts handles GET /shops/{shop_id}/analytics/low-stock
 // @Summary      Get low stock products
-// @Description  Returns products with low stock for a shop
+// @Description  Returns products with low stock for a shop: at or below the threshold when one is given, otherwise at or below their reorder point. Variants without a reorder point use a threshold of 5.
 // @Tags         analytics
 // @Produce      json
 // @Param        shop_id path int true "Shop ID"
-// @Param        threshold query int false "Stock threshold" default(5)
+// @Param        threshold query int false "Stock threshold, overriding reorder points"
 // @Success      200 {object} models.SuccessResponse{data=[]map[string]interface{}} "Low stock products fetched successfully."
 // @Failure      400 {object} models.ErrorResponse "Invalid parameters"
 // @Failure      500 {object} models.ErrorResponse "Failed to fetch low stock products"
@@ -527,13 +541,13 @@ func (h *AnalyticsHandler) GetLowStockProducts(c *fiber.Ctx) error {
 	if err != nil {
 		return api.ErrorResponse(c, 400, "Invalid shop_id", nil)
 	}
-	threshold, err := strconv.ParseInt(c.Query("threshold", "5"), 10, 64)
-	if err != nil || threshold < 0 {
-		threshold = 5
+	var threshold *int64
+	if value, err := strconv.ParseInt(c.Query("threshold"), 10, 64); err == nil && value >= 0 {
+		threshold = &value
 	}
 	params := db.GetLowStockProductsParams{
-		ShopID:            shopID,
-		AvailableQuantity: threshold,
+		Threshold: threshold,
+		ShopID:    shopID,
 	}
 	rows, err := h.Repository.GetLowStockProducts(c.Context(), params)
 	if err != nil {
@@ -547,7 +561,297 @@ func (h *AnalyticsHandler) GetLowStockProducts(c *fiber.Ctx) error {
 			"sku":                  row.Sku,
 			"description":          row.Description,
 			"stock":                row.Stock,
+			"reorder_level":        row.ReorderLevel,
 		})
 	}
 	return api.SuccessResponse(c, 200, variants, "Low stock products fetched successfully.")
 }
+
+// analyticsRange resolves the period, start_date and end_date query parameters to
+// the time range they cover, the end exclusive
+func analyticsRange(c *fiber.Ctx) (time.Time, time.Time, error) {
+	var start, end time.Time
+	now := time.Now().UTC()
+	switch c.Query("period", "today") {
+	case "today":
+		start = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+		end = start.Add(24 * time.Hour)
+	case "week":
+		weekday := int(now.Weekday())
+		if weekday == 0 {
+			weekday = 7
+		}
+		start = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, -weekday+1)
+		end = start.AddDate(0, 0, 7)
+	case "month":
+		start = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+		end = start.AddDate(0, 1, 0)
+	case "custom":
+		var err error
+		start, err = time.Parse("2006-01-02", c.Query("start_date"))
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("Invalid start_date")
+		}
+		end, err = time.Parse("2006-01-02", c.Query("end_date"))
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("Invalid end_date")
+		}
+		end = end.Add(24 * time.Hour)
+	default:
+		return time.Time{}, time.Time{}, errors.New("Invalid period")
+	}
+	return start, end, nil
+}
+
+// profitFigures computes gross profit and margin from the revenue of the items whose
+// cost was known when they were sold and that cost. The margin is a percentage of
+// that revenue rounded to two decimals.
+func profitFigures(costedRevenue, costOfGoods pgtype.Numeric) (float64, float64) {
+	revenue := models.NumericToFloat64(costedRevenue)
+	profit := revenue - models.NumericToFloat64(costOfGoods)
+	if revenue == 0 {
+		return profit, 0
+	}
+	return profit, math.Round(profit/revenue*10000) / 100
+}
+
+// GetProfitSummary handles GET /shops/{shop_id}/analytics/profit-summary
+// @Summary      Get profit summary
+// @Description  Returns revenue, cost of goods, gross profit and margin for a shop in a given period. Profit and margin only count the items whose cost price was known when they were sold; units_without_cost counts the rest.
+// @Tags         analytics
+// @Produce      json
+// @Param        shop_id path int true "Shop ID"
+// @Param        period query string false "Period (today, week, month, custom)" Enums(today, week, month, custom) default(today)
+// @Param        start_date query string false "Start date (YYYY-MM-DD, required if period=custom)"
+// @Param        end_date query string false "End date (YYYY-MM-DD, required if period=custom)"
+// @Success      200 {object} models.SuccessResponse{data=map[string]interface{}} "Profit summary fetched successfully."
+// @Failure      400 {object} models.ErrorResponse "Invalid parameters"
+// @Failure      500 {object} models.ErrorResponse "Failed to fetch profit summary"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/analytics/profit-summary [get]
+func (h *AnalyticsHandler) GetProfitSummary(c *fiber.Ctx) error {
+	shopID, err := strconv.ParseInt(c.Params("shop_id"), 10, 64)
+	if err != nil {
+		return api.ErrorResponse(c, 400, "Invalid shop_id", nil)
+	}
+	start, end, err := analyticsRange(c)
+	if err != nil {
+		return api.ErrorResponse(c, 400, err.Error(), nil)
+	}
+
+	row, err := h.Repository.GetProfitSummary(c.Context(), db.GetProfitSummaryParams{
+		ShopID:  shopID,
+		StartAt: pgtype.Timestamptz{Time: start, Valid: true},
+		EndAt:   pgtype.Timestamptz{Time: end, Valid: true},
+	})
+	if err != nil {
+		zap.L().Error("GetProfitSummary: failed to fetch profit summary", zap.Error(err), zap.Int64("shop_id", shopID))
+		return api.ErrorResponse(c, 500, "Failed to fetch profit summary", nil)
+	}
+	profit, margin := profitFigures(row.CostedRevenue, row.CostOfGoods)
+	resp := map[string]interface{}{
+		"revenue":            models.NumericToFloat64(row.Revenue),
+		"cost_of_goods":      models.NumericToFloat64(row.CostOfGoods),
+		"gross_profit":       profit,
+		"margin_pct":         margin,
+		"units_sold":         row.UnitsSold,
+		"units_without_cost": row.UnitsWithoutCost,
+	}
+	return api.SuccessResponse(c, 200, resp, "Profit summary fetched successfully.")
+}
+
+// GetProfitByProduct handles GET /shops/{shop_id}/analytics/profit-by-product
+// @Summary      Get profit by product
+// @Description  Returns the products of a shop ranked by gross profit in a given period
+// @Tags         analytics
+// @Produce      json
+// @Param        shop_id path int true "Shop ID"
+// @Param        period query string false "Period (today, week, month, custom)" Enums(today, week, month, custom) default(today)
+// @Param        limit query int false "Number of products to return" default(10)
+// @Param        start_date query string false "Start date (YYYY-MM-DD, required if period=custom)"
+// @Param        end_date query string false "End date (YYYY-MM-DD, required if period=custom)"
+// @Success      200 {object} models.SuccessResponse{data=[]map[string]interface{}} "Profit by product fetched successfully."
+// @Failure      400 {object} models.ErrorResponse "Invalid parameters"
+// @Failure      500 {object} models.ErrorResponse "Failed to fetch profit by product"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/analytics/profit-by-product [get]
+func (h *AnalyticsHandler) GetProfitByProduct(c *fiber.Ctx) error {
+	shopID, err := strconv.ParseInt(c.Params("shop_id"), 10, 64)
+	if err != nil {
+		return api.ErrorResponse(c, 400, "Invalid shop_id", nil)
+	}
+	limit, err := strconv.Atoi(c.Query("limit", "10"))
+	if err != nil || limit < 1 || limit > 100 {
+		limit = 10
+	}
+	start, end, err := analyticsRange(c)
+	if err != nil {
+		return api.ErrorResponse(c, 400, err.Error(), nil)
+	}
+
+	rows, err := h.Repository.GetProfitByProduct(c.Context(), db.GetProfitByProductParams{
+		ShopID:  shopID,
+		StartAt: pgtype.Timestamptz{Time: start, Valid: true},
+		EndAt:   pgtype.Timestamptz{Time: end, Valid: true},
+		Limit:   int32(limit),
+	})
+	if err != nil {
+		zap.L().Error("GetProfitByProduct: failed to fetch profit by product", zap.Error(err), zap.Int64("shop_id", shopID))
+		return api.ErrorResponse(c, 500, "Failed to fetch profit by product", nil)
+	}
+	products := make([]map[string]interface{}, 0, len(rows))
+	for _, row := range rows {
+		profit, margin := profitFigures(row.CostedRevenue, row.CostOfGoods)
+		products = append(products, map[string]interface{}{
+			"product_id":    row.ProductID,
+			"product_name":  row.ProductName,
+			"units_sold":    row.UnitsSold,
+			"revenue":       models.NumericToFloat64(row.Revenue),
+			"cost_of_goods": models.NumericToFloat64(row.CostOfGoods),
+			"gross_profit":  profit,
+			"margin_pct":    margin,
+		})
+	}
+	return api.SuccessResponse(c, 200, products, "Profit by product fetched successfully.")
+}
+
+// GetProfitByCategory handles GET /shops/{shop_id}/analytics/profit-by-category
+// @Summary      Get profit by category
+// @Description  Returns the gross profit of each category of a shop in a given period. Products without a category are grouped under a null category.
+// @Tags         analytics
+// @Produce      json
+// @Param        shop_id path int true "Shop ID"
+// @Param        period query string false "Period (today, week, month, custom)" Enums(today, week, month, custom) default(today)
+// @Param        start_date query string false "Start date (YYYY-MM-DD, required if period=custom)"
+// @Param        end_date query string false "End date (YYYY-MM-DD, required if period=custom)"
+// @Success      200 {object} models.SuccessResponse{data=[]map[string]interface{}} "Profit by category fetched successfully."
+// @Failure      400 {object} models.ErrorResponse "Invalid parameters"
+// @Failure      500 {object} models.ErrorResponse "Failed to fetch profit by category"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/analytics/profit-by-category [get]
+func (h *AnalyticsHandler) GetProfitByCategory(c *fiber.Ctx) error {
+	shopID, err := strconv.ParseInt(c.Params("shop_id"), 10, 64)
+	if err != nil {
+		return api.ErrorResponse(c, 400, "Invalid shop_id", nil)
+	}
+	start, end, err := analyticsRange(c)
+	if err != nil {
+		return api.ErrorResponse(c, 400, err.Error(), nil)
+	}
+
+	rows, err := h.Repository.GetProfitByCategory(c.Context(), db.GetProfitByCategoryParams{
+		ShopID:  shopID,
+		StartAt: pgtype.Timestamptz{Time: start, Valid: true},
+		EndAt:   pgtype.Timestamptz{Time: end, Valid: true},
+	})
+	if err != nil {
+		zap.L().Error("GetProfitByCategory: failed to fetch profit by category", zap.Error(err), zap.Int64("shop_id", shopID))
+		return api.ErrorResponse(c, 500, "Failed to fetch profit by category", nil)
+	}
+	categories := make([]map[string]interface{}, 0, len(rows))
+	for _, row := range rows {
+		profit, margin := profitFigures(row.CostedRevenue, row.CostOfGoods)
+		categories = append(categories, map[string]interface{}{
+			"category_id":   row.CategoryID,
+			"category_name": row.CategoryName,
+			"units_sold":    row.UnitsSold,
+			"revenue":       models.NumericToFloat64(row.Revenue),
+			"cost_of_goods": models.NumericToFloat64(row.CostOfGoods),
+			"gross_profit":  profit,
+			"margin_pct":    margin,
+		})
+	}
+	return api.SuccessResponse(c, 200, categories, "Profit by category fetched successfully.")
+}
+
+// GetProfitOverTime handles GET /shops/{shop_id}/analytics/profit-over-time
+// @Summary      Get profit over time
+// @Description  Returns revenue, gross profit and margin grouped by interval (day, week, month) for a shop in a given period
+// @Tags         analytics
+// @Produce      json
+// @Param        shop_id path int true "Shop ID"
+// @Param        interval query string false "Interval (day, week, month)" Enums(day, week, month) default(day)
+// @Param        period query string false "Period (today, week, month, custom)" Enums(today, week, month, custom) default(today)
+// @Param        start_date query string false "Start date (YYYY-MM-DD, required if period=custom)"
+// @Param        end_date query string false "End date (YYYY-MM-DD, required if period=custom)"
+// @Success      200 {object} models.SuccessResponse{data=map[string]interface{}} "Profit over time fetched successfully."
+// @Failure      400 {object} models.ErrorResponse "Invalid parameters"
+// @Failure      500 {object} models.ErrorResponse "Failed to fetch profit over time"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/analytics/profit-over-time [get]
+func (h *AnalyticsHandler) GetProfitOverTime(c *fiber.Ctx) error {
+	shopID, err := strconv.ParseInt(c.Params("shop_id"), 10, 64)
+	if err != nil {
+		return api.ErrorResponse(c, 400, "Invalid shop_id", nil)
+	}
+	interval := c.Query("interval", "day")
+	switch interval {
+	case "day", "week", "month":
+		// ok
+	default:
+		return api.ErrorResponse(c, 400, "Invalid interval", nil)
+	}
+	start, end, err := analyticsRange(c)
+	if err != nil {
+		return api.ErrorResponse(c, 400, err.Error(), nil)
+	}
+
+	rows, err := h.Repository.GetProfitOverTime(c.Context(), db.GetProfitOverTimeParams{
+		Interval: interval,
+		ShopID:   shopID,
+		StartAt:  pgtype.Timestamptz{Time: start, Valid: true},
+		EndAt:    pgtype.Timestamptz{Time: end, Valid: true},
+	})
+	if err != nil {
+		zap.L().Error("GetProfitOverTime: failed to fetch profit over time", zap.Error(err), zap.Int64("shop_id", shopID))
+		return api.ErrorResponse(c, 500, "Failed to fetch profit over time", nil)
+	}
+
+	label := func(t time.Time) string {
+		switch interval {
+		case "month":
+			return t.Format("2006-01")
+		case "week":
+			year, week := t.ISOWeek()
+			return fmt.Sprintf("%d-W%02d", year, week)
+		default:
+			return t.Format("2006-01-02")
+		}
+	}
+	byLabel := make(map[string]db.GetProfitOverTimeRow, len(rows))
+	for _, row := range rows {
+		if row.Period.Valid {
+			byLabel[label(row.Period.Time)] = row
+		}
+	}
+
+	labels := []string{}
+	revenue := []float64{}
+	profits := []float64{}
+	margins := []float64{}
+	for cur := start; cur.Before(end); {
+		l := label(cur)
+		labels = append(labels, l)
+		row := byLabel[l]
+		profit, margin := profitFigures(row.CostedRevenue, row.CostOfGoods)
+		revenue = append(revenue, models.NumericToFloat64(row.Revenue))
+		profits = append(profits, profit)
+		margins = append(margins, margin)
+		switch interval {
+		case "day":
+			cur = cur.Add(24 * time.Hour)
+		case "week":
+			cur = cur.AddDate(0, 0, 7)
+		case "month":
+			cur = cur.AddDate(0, 1, 0)
+		}
+	}
+
+	resp := map[string]interface{}{
+		"labels":       labels,
+		"revenue":      revenue,
+		"gross_profit": profits,
+		"margin_pct":   margins,
+	}
+	return api.SuccessResponse(c, 200, resp, "Profit over time fetched successfully.")
+}
@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
+	"github.com/petrejonn/naytife/internal/api"
+	"github.com/petrejonn/naytife/internal/api/models"
+	"github.com/petrejonn/naytife/internal/db"
+	"github.com/petrejonn/naytife/internal/services"
+	"go.uber.org/zap"
+)
+
+// GetBundleComponents fetches the components of a bundle variant
+// @Summary      Get bundle components
+// @Description  Get the variants a bundle variant is made of and the number of bundles their stock makes up. Variants that are not bundles have no components.
+// @Tags         inventory
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        variant_id path string true "Variant ID"
+// @Success      200  {object}   models.SuccessResponse{data=models.Bundle} "Bundle components fetched successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      404  {object}   models.ErrorResponse "Variant not found"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/inventory/variants/{variant_id}/components [get]
+func (h *Handler) GetBundleComponents(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	variantID, err := api.ParseIDParameter(c, "variant_id", "Variant")
+	if err != nil {
+		return err
+	}
+
+	variant, err := h.Repository.GetProductVariation(c.Context(), db.GetProductVariationParams{
+		ProductVariationID: variantID,
+		ShopID:             shopID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return api.NotFoundErrorResponse(c, "Product variant")
+		}
+		return api.SystemErrorResponse(c, err, "Failed to fetch product variant")
+	}
+	components, err := h.Repository.ListBundleComponents(c.Context(), db.ListBundleComponentsParams{
+		BundleVariationID: variantID,
+		ShopID:            shopID,
+	})
+	if err != nil {
+		zap.L().Error("GetBundleComponents: failed to fetch components", zap.Int64("variant_id", variantID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to fetch bundle components")
+	}
+	return api.SuccessResponse(c, fiber.StatusOK, models.NewBundle(variant, components), "Bundle components fetched successfully")
+}
+
+// SetBundleComponents sets the components of a bundle variant
+// @Summary      Set bundle components
+// @Description  Make a variant a bundle of other variants, such as a gift box, or replace its components. A bundle holds no stock of its own: it is available as many times as the stock of its components at the locations that sell online allows, and fulfilling an order takes each component from stock. Variants that hold stock, or are components of other bundles, cannot become bundles. An empty list makes the variant an ordinary one again.
+// @Tags         inventory
+// @Accept       json
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        variant_id path string true "Variant ID"
+// @Param        components body models.BundleComponentsParams true "Bundle components"
+// @Success      200  {object}   models.SuccessResponse{data=models.Bundle} "Bundle components updated successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      404  {object}   models.ErrorResponse "Variant not found"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/inventory/variants/{variant_id}/components [put]
+func (h *Handler) SetBundleComponents(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	variantID, err := api.ParseIDParameter(c, "variant_id", "Variant")
+	if err != nil {
+		return err
+	}
+
+	var param models.BundleComponentsParams
+	if err := c.BodyParser(&param); err != nil {
+		return api.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body", nil)
+	}
+	validator := &models.XValidator{}
+	if errs := validator.Validate(&param); len(errs) > 0 {
+		errMsgs := models.FormatValidationErrors(errs)
+		return api.ErrorResponse(c, fiber.StatusBadRequest, errMsgs, nil)
+	}
+	components := make([]services.BundleComponent, len(param.Components))
+	for i, component := range param.Components {
+		components[i] = services.BundleComponent{VariantID: component.VariantID, Quantity: component.Quantity}
+	}
+
+	var variant db.ProductVariation
+	var stored []db.ListBundleComponentsRow
+	err = h.Repository.WithTx(c.Context(), func(q *db.Queries) error {
+		if err := services.SetBundleComponents(c.Context(), q, shopID, variantID, components); err != nil {
+			return err
+		}
+		var err error
+		if variant, err = q.GetProductVariation(c.Context(), db.GetProductVariationParams{
+			ProductVariationID: variantID,
+			ShopID:             shopID,
+		}); err != nil {
+			return err
+		}
+		stored, err = q.ListBundleComponents(c.Context(), db.ListBundleComponentsParams{
+			BundleVariationID: variantID,
+			ShopID:            shopID,
+		})
+		return err
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return api.NotFoundErrorResponse(c, "Product variant")
+		case errors.Is(err, services.ErrBundleHoldsStock):
+			return api.BusinessLogicErrorResponse(c, "The variant holds stock; deduct or transfer it before making the variant a bundle")
+		case errors.Is(err, services.ErrNestedBundle):
+			return api.BusinessLogicErrorResponse(c, "The variant is a component of another bundle and cannot become a bundle")
+		case errors.Is(err, services.ErrInvalidBundleComponent):
+			return api.BusinessLogicErrorResponse(c, "Components must be other variants of the shop that are not bundles themselves")
+		}
+		zap.L().Error("SetBundleComponents: failed to set components", zap.Int64("variant_id", variantID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to update bundle components")
+	}
+	return api.SuccessResponse(c, fiber.StatusOK, models.NewBundle(variant, stored), "Bundle components updated successfully")
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/petrejonn/naytife/internal/api"
 	"github.com/petrejonn/naytife/internal/api/models"
 	"github.com/petrejonn/naytife/internal/db"
+	"github.com/petrejonn/naytife/internal/money"
 	"go.uber.org/zap"
 )
 
@@ -91,15 +92,26 @@ func (h *Handler) InitiateCheckout(c *fiber.Ctx) error {
 		}
 	}
 
+	currencies, err := h.Repository.GetShopCurrencies(c.Context(), shopID)
+	if err != nil {
+		zap.L().Error("InitiateCheckout: failed to get presentment currencies", zap.Error(err), zap.Int64("shop_id", shopID))
+		return api.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to get presentment currencies", nil)
+	}
+	presentmentCurrencies := make([]string, len(currencies))
+	for i, currency := range currencies {
+		presentmentCurrencies[i] = currency.CurrencyCode
+	}
+
 	response := models.CheckoutResponse{
-		ShopID:          shopID,
-		ShopName:        shop.Title,
-		CurrencyCode:    shop.CurrencyCode,
-		PaymentMethods:  enabledMethods,
-		CustomerInfo:    req.CustomerInfo,
-		ShippingAddress: req.ShippingAddress,
-		BillingAddress:  req.BillingAddress,
-		ExpiresAt:       time.Now().Add(30 * time.Minute), // 30 minutes checkout session
+		ShopID:                shopID,
+		ShopName:              shop.Title,
+		CurrencyCode:          shop.CurrencyCode,
+		PresentmentCurrencies: presentmentCurrencies,
+		PaymentMethods:        enabledMethods,
+		CustomerInfo:          req.CustomerInfo,
+		ShippingAddress:       req.ShippingAddress,
+		BillingAddress:        req.BillingAddress,
+		ExpiresAt:             time.Now().Add(30 * time.Minute), // 30 minutes checkout session
 	}
 
 	return api.SuccessResponse(c, fiber.StatusOK, response, "Checkout initiated successfully")
@@ -154,7 +166,7 @@ func (h *Handler) ProcessPayment(c *fiber.Ctx) error {
 
 	// Use PaymentProcessorFactory for processing payments
 	if h.PaymentProcessorFactory != nil {
-		return h.processPaymentWithFactory(c, shopID, shop, req, amount)
+		return h.processPaymentWithFactory(c, shopID, req, money.FromFloat(amount, shop.CurrencyCode))
 	}
 
 	// If PaymentProcessorFactory is not available, return error
@@ -163,7 +175,7 @@ func (h *Handler) ProcessPayment(c *fiber.Ctx) error {
 
 // CreatePaymentIntent creates a payment intent for checkout
 // @Summary      Create payment intent
-// @Description  Create a payment intent for the checkout session
+// @Description  Create a payment intent for the checkout session. The amount is in the shop currency and is converted when currency_code is an enabled presentment currency.
 // @Tags         payment
 // @Accept       json
 // @Produce      json
@@ -198,6 +210,27 @@ func (h *Handler) CreatePaymentIntent(c *fiber.Ctx) error {
 			return api.ErrorResponse(c, fiber.StatusBadRequest, fmt.Sprintf("Unsupported payment method: %s", req.PaymentMethod), nil)
 		}
 
+		// The amount is in the shop currency; charge it in the requested presentment currency
+		presentment, err := h.CurrencyService.Presentment(c.Context(), shopID, req.CurrencyCode)
+		if err != nil {
+			return api.ErrorResponse(c, fiber.StatusBadRequest, err.Error(), nil)
+		}
+		if req.Metadata == nil {
+			req.Metadata = map[string]interface{}{}
+		}
+		shopAmount, err := req.Amount.In(presentment.ShopCurrency)
+		if err != nil {
+			return api.ErrorResponse(c, fiber.StatusBadRequest, err.Error(), nil)
+		}
+		if shopAmount.IsZero() || shopAmount.IsNegative() {
+			return api.ErrorResponse(c, fiber.StatusBadRequest, "Amount must be greater than zero", nil)
+		}
+		req.Metadata["shop_currency"] = presentment.ShopCurrency
+		req.Metadata["shop_amount"] = shopAmount.String()
+		req.Metadata["exchange_rate"] = presentment.Rate
+		req.Amount = presentment.Convert(shopAmount)
+		req.CurrencyCode = presentment.CurrencyCode
+
 		// Create payment intent with the selected processor
 		response, err := processor.CreatePaymentIntent(c.Context(), shopID, req)
 		if err != nil {
@@ -212,7 +245,7 @@ func (h *Handler) CreatePaymentIntent(c *fiber.Ctx) error {
 }
 
 // processPaymentWithFactory handles payment processing using the PaymentProcessorFactory
-func (h *Handler) processPaymentWithFactory(c *fiber.Ctx, shopID int64, shop db.Shop, req models.PaymentRequest, amount float64) error {
+func (h *Handler) processPaymentWithFactory(c *fiber.Ctx, shopID int64, req models.PaymentRequest, amount money.Money) error {
 	// Get the appropriate payment processor
 	processor := h.PaymentProcessorFactory.GetProcessor(req.PaymentMethod)
 	if processor == nil {
@@ -220,7 +253,7 @@ func (h *Handler) processPaymentWithFactory(c *fiber.Ctx, shopID int64, shop db.
 	}
 
 	// Process the payment using the selected processor
-	response, err := processor.ProcessPayment(c.Context(), shopID, req, amount, shop.CurrencyCode)
+	response, err := processor.ProcessPayment(c.Context(), shopID, req, amount)
 	if err != nil {
 		return api.ErrorResponse(c, fiber.StatusPaymentRequired, fmt.Sprintf("Payment failed: %v", err), nil)
 	}
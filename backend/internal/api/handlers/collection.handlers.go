@@ -0,0 +1,682 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gosimple/slug"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/petrejonn/naytife/internal/api"
+	"github.com/petrejonn/naytife/internal/api/models"
+	"github.com/petrejonn/naytife/internal/db"
+	"github.com/petrejonn/naytife/internal/db/errors"
+	"go.uber.org/zap"
+)
+
+// collectionPricePattern matches the plain decimals a price rule is compared with.
+// It leaves out exponents, hex floats, NaN and Inf, which strconv accepts but the
+// numeric cast in SQL does not or cannot hold.
+var collectionPricePattern = regexp.MustCompile(`^-?[0-9]{1,15}(\.[0-9]{1,4})?$`)
+
+// collectionRuleOperators lists the operators each smart collection rule field accepts
+var collectionRuleOperators = map[string][]string{
+	"price":        {"eq", "gt", "gte", "lt", "lte"},
+	"product_type": {"eq", "neq"},
+	"attribute":    {"eq", "neq"},
+	"created_at":   {"gt", "lt", "within_days"},
+	"sales_rank":   {"lte"},
+	"tag":          {"eq", "neq"},
+}
+
+// CreateCollection creates a manual or smart collection
+// @Summary Create a collection
+// @Description Create a manual collection, or a smart collection whose products are selected by rules
+// @Tags Collection
+// @Accept json
+// @Produce json
+// @Param shop_id path string true "Shop ID"
+// @Param collection body models.CollectionCreateParams true "Collection"
+// @Success 201 {object} models.SuccessResponse{data=models.Collection} "Collection created successfully"
+// @Failure 400 {object} models.ErrorResponse "Bad request"
+// @Failure 409 {object} models.ErrorResponse "Collection handle already exists"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Security OAuth2AccessCode
+// @Router /shops/{shop_id}/collections [post]
+func (h *Handler) CreateCollection(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+
+	var param models.CollectionCreateParams
+	if err := c.BodyParser(&param); err != nil {
+		zap.L().Warn("CreateCollection: failed to parse request body", zap.Int64("shop_id", shopID), zap.Error(err))
+		return api.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body", nil)
+	}
+	validator := &models.XValidator{}
+	if errs := validator.Validate(&param); len(errs) > 0 {
+		errMsgs := models.FormatValidationErrors(errs)
+		zap.L().Warn("CreateCollection: request validation failed", zap.Int64("shop_id", shopID), zap.String("errors", errMsgs))
+		return &fiber.Error{
+			Code:    fiber.ErrBadRequest.Code,
+			Message: errMsgs,
+		}
+	}
+
+	if param.Type == db.CollectionTypeSmart && len(param.Rules) == 0 {
+		return api.ErrorResponse(c, fiber.StatusBadRequest, "Smart collections require at least one rule", nil)
+	}
+	if param.Type == db.CollectionTypeManual && len(param.Rules) > 0 {
+		return api.ErrorResponse(c, fiber.StatusBadRequest, "Manual collections cannot have rules", nil)
+	}
+	if err := validateCollectionRules(param.Rules); err != nil {
+		return api.ErrorResponse(c, fiber.StatusBadRequest, err.Error(), nil)
+	}
+
+	handle := param.Handle
+	if handle == "" {
+		handle = param.Title
+	}
+	handle = slug.Make(handle)
+	if handle == "" {
+		return api.ErrorResponse(c, fiber.StatusBadRequest, "Invalid collection handle", nil)
+	}
+
+	rules := param.Rules
+	if rules == nil {
+		rules = []models.CollectionRule{}
+	}
+	rulesJSON, err := json.Marshal(rules)
+	if err != nil {
+		return api.SystemErrorResponse(c, err, "Failed to encode collection rules")
+	}
+
+	matchAll := true
+	if param.MatchAll != nil {
+		matchAll = *param.MatchAll
+	}
+	sortOrder := param.SortOrder
+	if sortOrder == "" {
+		sortOrder = "manual"
+		if param.Type == db.CollectionTypeSmart {
+			sortOrder = "created_desc"
+		}
+	}
+
+	collection, err := h.Repository.CreateCollection(c.Context(), db.CreateCollectionParams{
+		Handle:         handle,
+		Title:          param.Title,
+		Description:    param.Description,
+		CollectionType: param.Type,
+		Rules:          rulesJSON,
+		MatchAll:       matchAll,
+		SortOrder:      sortOrder,
+		ShopID:         shopID,
+	})
+	if err != nil {
+		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == errors.UniqueViolation {
+			return api.ErrorResponse(c, fiber.StatusConflict, "Collection handle already exists", nil)
+		}
+		zap.L().Error("CreateCollection: failed to create collection", zap.Int64("shop_id", shopID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to create collection")
+	}
+
+	return api.SuccessResponse(c, fiber.StatusCreated, collectionResponse(collection), "Collection created successfully")
+}
+
+// GetCollections lists the collections of a shop
+// @Summary List collections
+// @Description Get the paginated collections of a shop
+// @Tags Collection
+// @Produce json
+// @Param shop_id path string true "Shop ID"
+// @Param limit query int false "Limit" default(20)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} models.SuccessResponse{data=[]models.Collection} "Collections fetched successfully"
+// @Failure 400 {object} models.ErrorResponse "Bad request"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Security OAuth2AccessCode
+// @Router /shops/{shop_id}/collections [get]
+func (h *Handler) GetCollections(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+
+	limit, offset, err := api.ParsePaginationParams(c)
+	if err != nil {
+		return api.BusinessLogicErrorResponse(c, "Invalid pagination parameters")
+	}
+
+	collections, err := h.Repository.ListCollections(c.Context(), db.ListCollectionsParams{
+		ShopID: shopID,
+		Limit:  int32(limit),
+		Offset: int32(offset),
+	})
+	if err != nil {
+		zap.L().Error("GetCollections: failed to fetch collections", zap.Int64("shop_id", shopID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to fetch collections")
+	}
+
+	totalCount, err := h.Repository.CountCollections(c.Context(), shopID)
+	if err != nil {
+		zap.L().Error("GetCollections: failed to count collections", zap.Int64("shop_id", shopID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to count collections")
+	}
+
+	response := make([]models.Collection, len(collections))
+	for i, collection := range collections {
+		response[i] = collectionResponse(collection)
+	}
+
+	page := (offset / limit) + 1
+	return api.PaginatedSuccessResponse(c, fiber.StatusOK, response, totalCount, page, limit, "Collections fetched successfully")
+}
+
+// GetCollection fetches a single collection
+// @Summary Get a collection
+// @Description Get a collection by ID
+// @Tags Collection
+// @Produce json
+// @Param shop_id path string true "Shop ID"
+// @Param collection_id path string true "Collection ID"
+// @Success 200 {object} models.SuccessResponse{data=models.Collection} "Collection fetched successfully"
+// @Failure 404 {object} models.ErrorResponse "Collection not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Security OAuth2AccessCode
+// @Router /shops/{shop_id}/collections/{collection_id} [get]
+func (h *Handler) GetCollection(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	collectionID, err := api.ParseIDParameter(c, "collection_id", "Collection")
+	if err != nil {
+		return err
+	}
+
+	collection, err := h.Repository.GetCollection(c.Context(), db.GetCollectionParams{
+		CollectionID: collectionID,
+		ShopID:       shopID,
+	})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return api.NotFoundErrorResponse(c, "Collection")
+		}
+		zap.L().Error("GetCollection: failed to fetch collection", zap.Int64("shop_id", shopID), zap.Int64("collection_id", collectionID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to fetch collection")
+	}
+
+	return api.SuccessResponse(c, fiber.StatusOK, collectionResponse(collection), "Collection fetched successfully")
+}
+
+// UpdateCollection updates a collection
+// @Summary Update a collection
+// @Description Update the details, rules or sort order of a collection. The collection type cannot be changed.
+// @Tags Collection
+// @Accept json
+// @Produce json
+// @Param shop_id path string true "Shop ID"
+// @Param collection_id path string true "Collection ID"
+// @Param collection body models.CollectionUpdateParams true "Collection"
+// @Success 200 {object} models.SuccessResponse{data=models.Collection} "Collection updated successfully"
+// @Failure 400 {object} models.ErrorResponse "Bad request"
+// @Failure 404 {object} models.ErrorResponse "Collection not found"
+// @Failure 409 {object} models.ErrorResponse "Collection handle already exists"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Security OAuth2AccessCode
+// @Router /shops/{shop_id}/collections/{collection_id} [put]
+func (h *Handler) UpdateCollection(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	collectionID, err := api.ParseIDParameter(c, "collection_id", "Collection")
+	if err != nil {
+		return err
+	}
+
+	var param models.CollectionUpdateParams
+	if err := c.BodyParser(&param); err != nil {
+		zap.L().Warn("UpdateCollection: failed to parse request body", zap.Int64("shop_id", shopID), zap.Error(err))
+		return api.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body", nil)
+	}
+	validator := &models.XValidator{}
+	if errs := validator.Validate(&param); len(errs) > 0 {
+		errMsgs := models.FormatValidationErrors(errs)
+		zap.L().Warn("UpdateCollection: request validation failed", zap.Int64("shop_id", shopID), zap.String("errors", errMsgs))
+		return &fiber.Error{
+			Code:    fiber.ErrBadRequest.Code,
+			Message: errMsgs,
+		}
+	}
+
+	existing, err := h.Repository.GetCollection(c.Context(), db.GetCollectionParams{
+		CollectionID: collectionID,
+		ShopID:       shopID,
+	})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return api.NotFoundErrorResponse(c, "Collection")
+		}
+		zap.L().Error("UpdateCollection: failed to fetch collection", zap.Int64("shop_id", shopID), zap.Int64("collection_id", collectionID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to fetch collection")
+	}
+
+	var rulesJSON []byte
+	if param.Rules != nil {
+		if existing.CollectionType == db.CollectionTypeManual && len(param.Rules) > 0 {
+			return api.ErrorResponse(c, fiber.StatusBadRequest, "Manual collections cannot have rules", nil)
+		}
+		if existing.CollectionType == db.CollectionTypeSmart && len(param.Rules) == 0 {
+			return api.ErrorResponse(c, fiber.StatusBadRequest, "Smart collections require at least one rule", nil)
+		}
+		if err := validateCollectionRules(param.Rules); err != nil {
+			return api.ErrorResponse(c, fiber.StatusBadRequest, err.Error(), nil)
+		}
+		rulesJSON, err = json.Marshal(param.Rules)
+		if err != nil {
+			return api.SystemErrorResponse(c, err, "Failed to encode collection rules")
+		}
+	}
+
+	var handle *string
+	if param.Handle != nil {
+		normalized := slug.Make(*param.Handle)
+		if normalized == "" {
+			return api.ErrorResponse(c, fiber.StatusBadRequest, "Invalid collection handle", nil)
+		}
+		handle = &normalized
+	}
+
+	collection, err := h.Repository.UpdateCollection(c.Context(), db.UpdateCollectionParams{
+		Handle:       handle,
+		Title:        param.Title,
+		Description:  param.Description,
+		Rules:        rulesJSON,
+		MatchAll:     param.MatchAll,
+		SortOrder:    param.SortOrder,
+		CollectionID: collectionID,
+		ShopID:       shopID,
+	})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return api.NotFoundErrorResponse(c, "Collection")
+		}
+		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == errors.UniqueViolation {
+			return api.ErrorResponse(c, fiber.StatusConflict, "Collection handle already exists", nil)
+		}
+		zap.L().Error("UpdateCollection: failed to update collection", zap.Int64("shop_id", shopID), zap.Int64("collection_id", collectionID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to update collection")
+	}
+
+	return api.SuccessResponse(c, fiber.StatusOK, collectionResponse(collection), "Collection updated successfully")
+}
+
+// DeleteCollection deletes a collection
+// @Summary Delete a collection
+// @Description Delete a collection. Products in the collection are not affected.
+// @Tags Collection
+// @Produce json
+// @Param shop_id path string true "Shop ID"
+// @Param collection_id path string true "Collection ID"
+// @Success 200 {object} models.SuccessResponse{data=nil} "Collection deleted successfully"
+// @Failure 404 {object} models.ErrorResponse "Collection not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Security OAuth2AccessCode
+// @Router /shops/{shop_id}/collections/{collection_id} [delete]
+func (h *Handler) DeleteCollection(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	collectionID, err := api.ParseIDParameter(c, "collection_id", "Collection")
+	if err != nil {
+		return err
+	}
+
+	deleted, err := h.Repository.DeleteCollection(c.Context(), db.DeleteCollectionParams{
+		CollectionID: collectionID,
+		ShopID:       shopID,
+	})
+	if err != nil {
+		zap.L().Error("DeleteCollection: failed to delete collection", zap.Int64("shop_id", shopID), zap.Int64("collection_id", collectionID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to delete collection")
+	}
+	if deleted == 0 {
+		return api.NotFoundErrorResponse(c, "Collection")
+	}
+
+	return api.SuccessResponse(c, fiber.StatusOK, nil, "Collection deleted successfully")
+}
+
+// GetCollectionProducts lists the products in a collection
+// @Summary List collection products
+// @Description Get the products in a collection in the collection's sort order. Smart collection rules are evaluated on every request.
+// @Tags Collection
+// @Produce json
+// @Param shop_id path string true "Shop ID"
+// @Param collection_id path string true "Collection ID"
+// @Param limit query int false "Limit" default(20)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} models.SuccessResponse{data=[]models.CollectionProduct} "Collection products fetched successfully"
+// @Failure 400 {object} models.ErrorResponse "Bad request"
+// @Failure 404 {object} models.ErrorResponse "Collection not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Security OAuth2AccessCode
+// @Router /shops/{shop_id}/collections/{collection_id}/products [get]
+func (h *Handler) GetCollectionProducts(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	collectionID, err := api.ParseIDParameter(c, "collection_id", "Collection")
+	if err != nil {
+		return err
+	}
+
+	limit, offset, err := api.ParsePaginationParams(c)
+	if err != nil {
+		return api.BusinessLogicErrorResponse(c, "Invalid pagination parameters")
+	}
+
+	if _, err := h.Repository.GetCollection(c.Context(), db.GetCollectionParams{
+		CollectionID: collectionID,
+		ShopID:       shopID,
+	}); err != nil {
+		if err == pgx.ErrNoRows {
+			return api.NotFoundErrorResponse(c, "Collection")
+		}
+		zap.L().Error("GetCollectionProducts: failed to fetch collection", zap.Int64("shop_id", shopID), zap.Int64("collection_id", collectionID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to fetch collection")
+	}
+
+	products, err := h.Repository.GetCollectionProducts(c.Context(), db.GetCollectionProductsParams{
+		ShopID:       shopID,
+		CollectionID: collectionID,
+		Limit:        int32(limit),
+		Offset:       int32(offset),
+	})
+	if err != nil {
+		zap.L().Error("GetCollectionProducts: failed to fetch products", zap.Int64("shop_id", shopID), zap.Int64("collection_id", collectionID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to fetch collection products")
+	}
+
+	var totalCount int64
+	response := make([]models.CollectionProduct, len(products))
+	for i, product := range products {
+		totalCount = product.TotalCount
+		response[i] = models.CollectionProduct{
+			ProductID: product.ProductID,
+			Title:     product.Title,
+			Slug:      product.Slug,
+			Status:    product.Status,
+			Price:     product.Price,
+			UnitsSold: product.UnitsSold,
+			Position:  product.Position,
+			CreatedAt: product.CreatedAt.Time,
+		}
+	}
+
+	page := (offset / limit) + 1
+	return api.PaginatedSuccessResponse(c, fiber.StatusOK, response, totalCount, page, limit, "Collection products fetched successfully")
+}
+
+// AddCollectionProduct adds a product to a manual collection
+// @Summary Add a product to a collection
+// @Description Add a product to a manual collection, or move it if it is already a member. Without a position the product is appended.
+// @Tags Collection
+// @Accept json
+// @Produce json
+// @Param shop_id path string true "Shop ID"
+// @Param collection_id path string true "Collection ID"
+// @Param product body models.CollectionProductAddParams true "Product"
+// @Success 201 {object} models.SuccessResponse{data=nil} "Product added to collection successfully"
+// @Failure 400 {object} models.ErrorResponse "Bad request"
+// @Failure 404 {object} models.ErrorResponse "Collection or product not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Security OAuth2AccessCode
+// @Router /shops/{shop_id}/collections/{collection_id}/products [post]
+func (h *Handler) AddCollectionProduct(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	collectionID, err := api.ParseIDParameter(c, "collection_id", "Collection")
+	if err != nil {
+		return err
+	}
+
+	var param models.CollectionProductAddParams
+	if err := c.BodyParser(&param); err != nil {
+		zap.L().Warn("AddCollectionProduct: failed to parse request body", zap.Int64("shop_id", shopID), zap.Error(err))
+		return api.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body", nil)
+	}
+	validator := &models.XValidator{}
+	if errs := validator.Validate(&param); len(errs) > 0 {
+		return &fiber.Error{
+			Code:    fiber.ErrBadRequest.Code,
+			Message: models.FormatValidationErrors(errs),
+		}
+	}
+
+	collection, err := h.Repository.GetCollection(c.Context(), db.GetCollectionParams{
+		CollectionID: collectionID,
+		ShopID:       shopID,
+	})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return api.NotFoundErrorResponse(c, "Collection")
+		}
+		zap.L().Error("AddCollectionProduct: failed to fetch collection", zap.Int64("shop_id", shopID), zap.Int64("collection_id", collectionID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to fetch collection")
+	}
+	if collection.CollectionType != db.CollectionTypeManual {
+		return api.BusinessLogicErrorResponse(c, "Products can only be added to manual collections")
+	}
+
+	if _, err := h.Repository.GetProduct(c.Context(), db.GetProductParams{
+		ProductID: param.ProductID,
+		ShopID:    shopID,
+	}); err != nil {
+		if err == pgx.ErrNoRows {
+			return api.NotFoundErrorResponse(c, "Product")
+		}
+		zap.L().Error("AddCollectionProduct: failed to fetch product", zap.Int64("shop_id", shopID), zap.Int64("product_id", param.ProductID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to fetch product")
+	}
+
+	if _, err := h.Repository.AddCollectionProduct(c.Context(), db.AddCollectionProductParams{
+		CollectionID: collectionID,
+		ProductID:    param.ProductID,
+		Position:     param.Position,
+		ShopID:       shopID,
+	}); err != nil {
+		zap.L().Error("AddCollectionProduct: failed to add product", zap.Int64("shop_id", shopID), zap.Int64("collection_id", collectionID), zap.Int64("product_id", param.ProductID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to add product to collection")
+	}
+
+	return api.SuccessResponse(c, fiber.StatusCreated, nil, "Product added to collection successfully")
+}
+
+// RemoveCollectionProduct removes a product from a manual collection
+// @Summary Remove a product from a collection
+// @Description Remove a product from a manual collection
+// @Tags Collection
+// @Produce json
+// @Param shop_id path string true "Shop ID"
+// @Param collection_id path string true "Collection ID"
+// @Param product_id path string true "Product ID"
+// @Success 200 {object} models.SuccessResponse{data=nil} "Product removed from collection successfully"
+// @Failure 404 {object} models.ErrorResponse "Product not in collection"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Security OAuth2AccessCode
+// @Router /shops/{shop_id}/collections/{collection_id}/products/{product_id} [delete]
+func (h *Handler) RemoveCollectionProduct(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	collectionID, err := api.ParseIDParameter(c, "collection_id", "Collection")
+	if err != nil {
+		return err
+	}
+	productID, err := api.ParseIDParameter(c, "product_id", "Product")
+	if err != nil {
+		return err
+	}
+
+	removed, err := h.Repository.RemoveCollectionProduct(c.Context(), db.RemoveCollectionProductParams{
+		CollectionID: collectionID,
+		ProductID:    productID,
+		ShopID:       shopID,
+	})
+	if err != nil {
+		zap.L().Error("RemoveCollectionProduct: failed to remove product", zap.Int64("shop_id", shopID), zap.Int64("collection_id", collectionID), zap.Int64("product_id", productID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to remove product from collection")
+	}
+	if removed == 0 {
+		return api.NotFoundErrorResponse(c, "Collection product")
+	}
+
+	return api.SuccessResponse(c, fiber.StatusOK, nil, "Product removed from collection successfully")
+}
+
+// ReorderCollectionProducts sets the manual order of the products in a collection
+// @Summary Reorder collection products
+// @Description Move the listed products to the top of a manual collection in the given order. Products that are not listed keep their relative order after them.
+// @Tags Collection
+// @Accept json
+// @Produce json
+// @Param shop_id path string true "Shop ID"
+// @Param collection_id path string true "Collection ID"
+// @Param order body models.CollectionProductsReorderParams true "Product order"
+// @Success 200 {object} models.SuccessResponse{data=nil} "Collection products reordered successfully"
+// @Failure 400 {object} models.ErrorResponse "Bad request"
+// @Failure 404 {object} models.ErrorResponse "Collection not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Security OAuth2AccessCode
+// @Router /shops/{shop_id}/collections/{collection_id}/products/order [put]
+func (h *Handler) ReorderCollectionProducts(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	collectionID, err := api.ParseIDParameter(c, "collection_id", "Collection")
+	if err != nil {
+		return err
+	}
+
+	var param models.CollectionProductsReorderParams
+	if err := c.BodyParser(&param); err != nil {
+		zap.L().Warn("ReorderCollectionProducts: failed to parse request body", zap.Int64("shop_id", shopID), zap.Error(err))
+		return api.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body", nil)
+	}
+	validator := &models.XValidator{}
+	if errs := validator.Validate(&param); len(errs) > 0 {
+		return &fiber.Error{
+			Code:    fiber.ErrBadRequest.Code,
+			Message: models.FormatValidationErrors(errs),
+		}
+	}
+	seen := make(map[int64]bool, len(param.ProductIDs))
+	for _, id := range param.ProductIDs {
+		if seen[id] {
+			return api.ErrorResponse(c, fiber.StatusBadRequest, "Duplicate product ID "+strconv.FormatInt(id, 10), nil)
+		}
+		seen[id] = true
+	}
+
+	collection, err := h.Repository.GetCollection(c.Context(), db.GetCollectionParams{
+		CollectionID: collectionID,
+		ShopID:       shopID,
+	})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return api.NotFoundErrorResponse(c, "Collection")
+		}
+		zap.L().Error("ReorderCollectionProducts: failed to fetch collection", zap.Int64("shop_id", shopID), zap.Int64("collection_id", collectionID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to fetch collection")
+	}
+	if collection.CollectionType != db.CollectionTypeManual {
+		return api.BusinessLogicErrorResponse(c, "Only manual collections can be reordered")
+	}
+
+	if err := h.Repository.ReorderCollectionProducts(c.Context(), db.ReorderCollectionProductsParams{
+		ProductIds:   param.ProductIDs,
+		CollectionID: collectionID,
+		ShopID:       shopID,
+	}); err != nil {
+		zap.L().Error("ReorderCollectionProducts: failed to reorder products", zap.Int64("shop_id", shopID), zap.Int64("collection_id", collectionID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to reorder collection products")
+	}
+
+	return api.SuccessResponse(c, fiber.StatusOK, nil, "Collection products reordered successfully")
+}
+
+// validateCollectionRules checks the operator and value of each rule so that
+// the casts made when the rules are evaluated in SQL cannot fail
+func validateCollectionRules(rules []models.CollectionRule) error {
+	for i, rule := range rules {
+		allowed := false
+		for _, op := range collectionRuleOperators[rule.Field] {
+			if op == rule.Operator {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return fmt.Errorf("rule %d: operator %q is not supported for field %q", i+1, rule.Operator, rule.Field)
+		}
+
+		switch {
+		case rule.Field == "price":
+			if !collectionPricePattern.MatchString(rule.Value) {
+				return fmt.Errorf("rule %d: price must be a decimal number", i+1)
+			}
+		case rule.Field == "product_type", rule.Field == "sales_rank":
+			if n, err := strconv.ParseInt(rule.Value, 10, 64); err != nil || n <= 0 {
+				return fmt.Errorf("rule %d: %s must be a positive integer", i+1, rule.Field)
+			}
+		case rule.Field == "attribute":
+			if rule.AttributeID == nil || *rule.AttributeID <= 0 {
+				return fmt.Errorf("rule %d: attribute_id is required for attribute rules", i+1)
+			}
+		case rule.Field == "created_at" && rule.Operator == "within_days":
+			if n, err := strconv.ParseInt(rule.Value, 10, 32); err != nil || n <= 0 {
+				return fmt.Errorf("rule %d: within_days must be a positive number of days", i+1)
+			}
+		case rule.Field == "created_at":
+			if _, err := time.Parse(time.RFC3339, rule.Value); err != nil {
+				return fmt.Errorf("rule %d: created_at must be an RFC 3339 timestamp", i+1)
+			}
+		}
+	}
+	return nil
+}
+
+func collectionResponse(collection db.Collection) models.Collection {
+	rules := []models.CollectionRule{}
+	if len(collection.Rules) > 0 {
+		if err := json.Unmarshal(collection.Rules, &rules); err != nil {
+			zap.L().Warn("collectionResponse: failed to decode collection rules", zap.Int64("collection_id", collection.CollectionID), zap.Error(err))
+		}
+	}
+	return models.Collection{
+		ID:          collection.CollectionID,
+		Handle:      collection.Handle,
+		Title:       collection.Title,
+		Description: collection.Description,
+		Type:        collection.CollectionType,
+		Rules:       rules,
+		MatchAll:    collection.MatchAll,
+		SortOrder:   collection.SortOrder,
+		CreatedAt:   collection.CreatedAt.Time,
+		UpdatedAt:   collection.UpdatedAt.Time,
+	}
+}
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/petrejonn/naytife/internal/api/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateCollectionRules(t *testing.T) {
+	attributeID := int64(4)
+
+	tests := []struct {
+		name    string
+		rule    models.CollectionRule
+		wantErr bool
+	}{
+		{"price integer", models.CollectionRule{Field: "price", Operator: "lt", Value: "5000"}, false},
+		{"price decimal", models.CollectionRule{Field: "price", Operator: "gte", Value: "19.9900"}, false},
+		{"price negative", models.CollectionRule{Field: "price", Operator: "gt", Value: "-1"}, false},
+		{"price hex float", models.CollectionRule{Field: "price", Operator: "lt", Value: "0x1p3"}, true},
+		{"price infinity", models.CollectionRule{Field: "price", Operator: "lt", Value: "Inf"}, true},
+		{"price nan", models.CollectionRule{Field: "price", Operator: "lt", Value: "NaN"}, true},
+		{"price exponent", models.CollectionRule{Field: "price", Operator: "lt", Value: "1e400"}, true},
+		{"price too precise", models.CollectionRule{Field: "price", Operator: "lt", Value: "1.23456"}, true},
+		{"price empty", models.CollectionRule{Field: "price", Operator: "lt", Value: ""}, true},
+		{"price unsupported operator", models.CollectionRule{Field: "price", Operator: "neq", Value: "1"}, true},
+		{"product type", models.CollectionRule{Field: "product_type", Operator: "eq", Value: "3"}, false},
+		{"product type zero", models.CollectionRule{Field: "product_type", Operator: "eq", Value: "0"}, true},
+		{"sales rank not a number", models.CollectionRule{Field: "sales_rank", Operator: "lte", Value: "top"}, true},
+		{"attribute", models.CollectionRule{Field: "attribute", Operator: "eq", Value: "red", AttributeID: &attributeID}, false},
+		{"attribute without id", models.CollectionRule{Field: "attribute", Operator: "eq", Value: "red"}, true},
+		{"created within days", models.CollectionRule{Field: "created_at", Operator: "within_days", Value: "30"}, false},
+		{"created within negative days", models.CollectionRule{Field: "created_at", Operator: "within_days", Value: "-3"}, true},
+		{"created after timestamp", models.CollectionRule{Field: "created_at", Operator: "gt", Value: "2025-01-01T00:00:00Z"}, false},
+		{"created after date only", models.CollectionRule{Field: "created_at", Operator: "gt", Value: "2025-01-01"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateCollectionRules([]models.CollectionRule{tt.rule})
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,360 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/petrejonn/naytife/internal/api"
+	"github.com/petrejonn/naytife/internal/api/models"
+	"github.com/petrejonn/naytife/internal/db"
+	"github.com/petrejonn/naytife/internal/money"
+	"github.com/petrejonn/naytife/internal/services"
+	"go.uber.org/zap"
+)
+
+// GetShopCurrencies lists the presentment currencies of a shop
+// @Summary List shop currencies
+// @Description Get the shop currency and the enabled presentment currencies with the exchange rates currently in use
+// @Tags Currency
+// @Produce json
+// @Param shop_id path string true "Shop ID"
+// @Success 200 {object} models.SuccessResponse{data=models.ShopCurrencies} "Shop currencies fetched successfully"
+// @Failure 404 {object} models.ErrorResponse "Shop not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Security OAuth2AccessCode
+// @Router /shops/{shop_id}/currencies [get]
+func (h *Handler) GetShopCurrencies(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+
+	shop, err := h.Repository.GetShop(c.Context(), shopID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return api.NotFoundErrorResponse(c, "Shop")
+		}
+		zap.L().Error("GetShopCurrencies: failed to fetch shop", zap.Int64("shop_id", shopID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to fetch shop")
+	}
+	rows, err := h.Repository.GetShopCurrencies(c.Context(), shopID)
+	if err != nil {
+		zap.L().Error("GetShopCurrencies: failed to fetch currencies", zap.Int64("shop_id", shopID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to fetch shop currencies")
+	}
+
+	response := models.ShopCurrencies{
+		CurrencyCode:          shop.CurrencyCode,
+		PresentmentCurrencies: make([]models.ShopCurrency, len(rows)),
+	}
+	for i, row := range rows {
+		currency, err := h.shopCurrencyResponse(c, shop, row)
+		if err != nil {
+			zap.L().Error("GetShopCurrencies: failed to resolve exchange rate", zap.Int64("shop_id", shopID), zap.String("currency_code", row.CurrencyCode), zap.Error(err))
+			return api.SystemErrorResponse(c, err, "Failed to resolve exchange rate")
+		}
+		response.PresentmentCurrencies[i] = currency
+	}
+
+	return api.SuccessResponse(c, fiber.StatusOK, response, "Shop currencies fetched successfully")
+}
+
+// SetShopCurrency enables a presentment currency or updates its exchange rate
+// @Summary Enable presentment currency
+// @Description Enable a presentment currency for a shop. A manual exchange rate is used when given, otherwise the configured rate source provides it.
+// @Tags Currency
+// @Accept json
+// @Produce json
+// @Param shop_id path string true "Shop ID"
+// @Param currency_code path string true "ISO 4217 currency code"
+// @Param currency body models.ShopCurrencyParams true "Exchange rate"
+// @Success 200 {object} models.SuccessResponse{data=models.ShopCurrency} "Shop currency saved successfully"
+// @Failure 400 {object} models.ErrorResponse "Bad request"
+// @Failure 404 {object} models.ErrorResponse "Shop not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Security OAuth2AccessCode
+// @Router /shops/{shop_id}/currencies/{currency_code} [put]
+func (h *Handler) SetShopCurrency(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	currencyCode, err := services.NormalizeCurrencyCode(c.Params("currency_code"))
+	if err != nil {
+		return api.ErrorResponse(c, fiber.StatusBadRequest, err.Error(), nil)
+	}
+
+	var param models.ShopCurrencyParams
+	if err := c.BodyParser(&param); err != nil {
+		zap.L().Warn("SetShopCurrency: failed to parse request body", zap.Int64("shop_id", shopID), zap.Error(err))
+		return api.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body", nil)
+	}
+	validator := &models.XValidator{}
+	if errs := validator.Validate(&param); len(errs) > 0 {
+		return &fiber.Error{
+			Code:    fiber.ErrBadRequest.Code,
+			Message: models.FormatValidationErrors(errs),
+		}
+	}
+
+	shop, err := h.Repository.GetShop(c.Context(), shopID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return api.NotFoundErrorResponse(c, "Shop")
+		}
+		zap.L().Error("SetShopCurrency: failed to fetch shop", zap.Int64("shop_id", shopID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to fetch shop")
+	}
+	if currencyCode == shop.CurrencyCode {
+		return api.BusinessLogicErrorResponse(c, "The shop currency cannot be added as a presentment currency")
+	}
+
+	var rate pgtype.Numeric
+	if param.ExchangeRate != nil {
+		rate = services.FloatToNumeric(*param.ExchangeRate)
+	} else if _, err := h.CurrencyService.RateSource.Rate(c.Context(), shop.CurrencyCode, currencyCode); err != nil {
+		return api.BusinessLogicErrorResponse(c, "No exchange rate is available for "+currencyCode+"; set a manual exchange rate")
+	}
+
+	row, err := h.Repository.UpsertShopCurrency(c.Context(), db.UpsertShopCurrencyParams{
+		CurrencyCode: currencyCode,
+		ExchangeRate: rate,
+		ShopID:       shopID,
+	})
+	if err != nil {
+		zap.L().Error("SetShopCurrency: failed to save currency", zap.Int64("shop_id", shopID), zap.String("currency_code", currencyCode), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to save shop currency")
+	}
+	response, err := h.shopCurrencyResponse(c, shop, row)
+	if err != nil {
+		return api.SystemErrorResponse(c, err, "Failed to resolve exchange rate")
+	}
+
+	return api.SuccessResponse(c, fiber.StatusOK, response, "Shop currency saved successfully")
+}
+
+// DeleteShopCurrency disables a presentment currency
+// @Summary Disable presentment currency
+// @Description Disable a presentment currency. Fixed variant prices in the currency are kept.
+// @Tags Currency
+// @Produce json
+// @Param shop_id path string true "Shop ID"
+// @Param currency_code path string true "ISO 4217 currency code"
+// @Success 200 {object} models.SuccessResponse{data=nil} "Shop currency deleted successfully"
+// @Failure 400 {object} models.ErrorResponse "Bad request"
+// @Failure 404 {object} models.ErrorResponse "Currency not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Security OAuth2AccessCode
+// @Router /shops/{shop_id}/currencies/{currency_code} [delete]
+func (h *Handler) DeleteShopCurrency(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	currencyCode, err := services.NormalizeCurrencyCode(c.Params("currency_code"))
+	if err != nil {
+		return api.ErrorResponse(c, fiber.StatusBadRequest, err.Error(), nil)
+	}
+
+	deleted, err := h.Repository.DeleteShopCurrency(c.Context(), db.DeleteShopCurrencyParams{
+		ShopID:       shopID,
+		CurrencyCode: currencyCode,
+	})
+	if err != nil {
+		zap.L().Error("DeleteShopCurrency: failed to delete currency", zap.Int64("shop_id", shopID), zap.String("currency_code", currencyCode), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to delete shop currency")
+	}
+	if deleted == 0 {
+		return api.NotFoundErrorResponse(c, "Currency")
+	}
+
+	return api.SuccessResponse(c, fiber.StatusOK, nil, "Shop currency deleted successfully")
+}
+
+// GetVariantPrices lists the fixed presentment prices of a variant
+// @Summary List variant prices
+// @Description Get the fixed prices of a variant in presentment currencies
+// @Tags Currency
+// @Produce json
+// @Param shop_id path string true "Shop ID"
+// @Param variant_id path string true "Variant ID"
+// @Success 200 {object} models.SuccessResponse{data=[]models.VariantPrice} "Variant prices fetched successfully"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Security OAuth2AccessCode
+// @Router /shops/{shop_id}/variants/{variant_id}/prices [get]
+func (h *Handler) GetVariantPrices(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	variantID, err := api.ParseIDParameter(c, "variant_id", "Variant")
+	if err != nil {
+		return err
+	}
+
+	rows, err := h.Repository.GetProductVariationPrices(c.Context(), db.GetProductVariationPricesParams{
+		ProductVariationID: variantID,
+		ShopID:             shopID,
+	})
+	if err != nil {
+		zap.L().Error("GetVariantPrices: failed to fetch prices", zap.Int64("shop_id", shopID), zap.Int64("variant_id", variantID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to fetch variant prices")
+	}
+
+	response := make([]models.VariantPrice, len(rows))
+	for i, row := range rows {
+		price, err := money.FromNumeric(row.Price, row.CurrencyCode)
+		if err != nil {
+			return api.SystemErrorResponse(c, err, "Failed to read variant price")
+		}
+		response[i] = models.VariantPrice{
+			CurrencyCode: row.CurrencyCode,
+			Price:        price,
+			UpdatedAt:    row.UpdatedAt.Time,
+		}
+	}
+
+	return api.SuccessResponse(c, fiber.StatusOK, response, "Variant prices fetched successfully")
+}
+
+// SetVariantPrice sets a fixed price for a variant in a presentment currency
+// @Summary Set variant price
+// @Description Set a fixed price for a variant in an enabled presentment currency, used instead of the converted price
+// @Tags Currency
+// @Accept json
+// @Produce json
+// @Param shop_id path string true "Shop ID"
+// @Param variant_id path string true "Variant ID"
+// @Param currency_code path string true "ISO 4217 currency code"
+// @Param price body models.VariantPriceParams true "Price"
+// @Success 200 {object} models.SuccessResponse{data=models.VariantPrice} "Variant price saved successfully"
+// @Failure 400 {object} models.ErrorResponse "Bad request"
+// @Failure 404 {object} models.ErrorResponse "Variant not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Security OAuth2AccessCode
+// @Router /shops/{shop_id}/variants/{variant_id}/prices/{currency_code} [put]
+func (h *Handler) SetVariantPrice(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	variantID, err := api.ParseIDParameter(c, "variant_id", "Variant")
+	if err != nil {
+		return err
+	}
+	currencyCode, err := services.NormalizeCurrencyCode(c.Params("currency_code"))
+	if err != nil {
+		return api.ErrorResponse(c, fiber.StatusBadRequest, err.Error(), nil)
+	}
+
+	var param models.VariantPriceParams
+	if err := c.BodyParser(&param); err != nil {
+		zap.L().Warn("SetVariantPrice: failed to parse request body", zap.Int64("shop_id", shopID), zap.Error(err))
+		return api.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body", nil)
+	}
+	price, err := param.Price.In(currencyCode)
+	if err != nil {
+		return api.ErrorResponse(c, fiber.StatusBadRequest, err.Error(), nil)
+	}
+	if price.IsNegative() {
+		return api.ErrorResponse(c, fiber.StatusBadRequest, "Price must not be negative", nil)
+	}
+
+	if _, err := h.Repository.GetShopCurrency(c.Context(), db.GetShopCurrencyParams{ShopID: shopID, CurrencyCode: currencyCode}); err != nil {
+		if err == pgx.ErrNoRows {
+			return api.BusinessLogicErrorResponse(c, currencyCode+" is not enabled as a presentment currency for this shop")
+		}
+		zap.L().Error("SetVariantPrice: failed to fetch currency", zap.Int64("shop_id", shopID), zap.String("currency_code", currencyCode), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to fetch shop currency")
+	}
+	if _, err := h.Repository.GetProductVariation(c.Context(), db.GetProductVariationParams{ProductVariationID: variantID, ShopID: shopID}); err != nil {
+		if err == pgx.ErrNoRows {
+			return api.NotFoundErrorResponse(c, "Variant")
+		}
+		zap.L().Error("SetVariantPrice: failed to fetch variant", zap.Int64("shop_id", shopID), zap.Int64("variant_id", variantID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to fetch variant")
+	}
+
+	row, err := h.Repository.UpsertProductVariationPrice(c.Context(), db.UpsertProductVariationPriceParams{
+		ProductVariationID: variantID,
+		CurrencyCode:       currencyCode,
+		Price:              price.Numeric(),
+		ShopID:             shopID,
+	})
+	if err != nil {
+		zap.L().Error("SetVariantPrice: failed to save price", zap.Int64("shop_id", shopID), zap.Int64("variant_id", variantID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to save variant price")
+	}
+
+	saved, err := money.FromNumeric(row.Price, row.CurrencyCode)
+	if err != nil {
+		return api.SystemErrorResponse(c, err, "Failed to read variant price")
+	}
+
+	return api.SuccessResponse(c, fiber.StatusOK, models.VariantPrice{
+		CurrencyCode: row.CurrencyCode,
+		Price:        saved,
+		UpdatedAt:    row.UpdatedAt.Time,
+	}, "Variant price saved successfully")
+}
+
+// DeleteVariantPrice removes the fixed price of a variant in a presentment currency
+// @Summary Delete variant price
+// @Description Remove a fixed variant price so the converted price is used again
+// @Tags Currency
+// @Produce json
+// @Param shop_id path string true "Shop ID"
+// @Param variant_id path string true "Variant ID"
+// @Param currency_code path string true "ISO 4217 currency code"
+// @Success 200 {object} models.SuccessResponse{data=nil} "Variant price deleted successfully"
+// @Failure 400 {object} models.ErrorResponse "Bad request"
+// @Failure 404 {object} models.ErrorResponse "Variant price not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Security OAuth2AccessCode
+// @Router /shops/{shop_id}/variants/{variant_id}/prices/{currency_code} [delete]
+func (h *Handler) DeleteVariantPrice(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	variantID, err := api.ParseIDParameter(c, "variant_id", "Variant")
+	if err != nil {
+		return err
+	}
+	currencyCode, err := services.NormalizeCurrencyCode(c.Params("currency_code"))
+	if err != nil {
+		return api.ErrorResponse(c, fiber.StatusBadRequest, err.Error(), nil)
+	}
+
+	deleted, err := h.Repository.DeleteProductVariationPrice(c.Context(), db.DeleteProductVariationPriceParams{
+		ProductVariationID: variantID,
+		CurrencyCode:       currencyCode,
+		ShopID:             shopID,
+	})
+	if err != nil {
+		zap.L().Error("DeleteVariantPrice: failed to delete price", zap.Int64("shop_id", shopID), zap.Int64("variant_id", variantID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to delete variant price")
+	}
+	if deleted == 0 {
+		return api.NotFoundErrorResponse(c, "Variant price")
+	}
+
+	return api.SuccessResponse(c, fiber.StatusOK, nil, "Variant price deleted successfully")
+}
+
+func (h *Handler) shopCurrencyResponse(c *fiber.Ctx, shop db.Shop, row db.ShopCurrency) (models.ShopCurrency, error) {
+	presentment, err := h.CurrencyService.Presentment(c.Context(), shop.ShopID, row.CurrencyCode)
+	if err != nil {
+		return models.ShopCurrency{}, err
+	}
+	rateSource := "provider"
+	if row.ExchangeRate.Valid {
+		rateSource = "manual"
+	}
+	return models.ShopCurrency{
+		CurrencyCode: row.CurrencyCode,
+		ExchangeRate: presentment.Rate,
+		RateSource:   rateSource,
+		UpdatedAt:    row.UpdatedAt.Time,
+	}, nil
+}
@@ -359,7 +359,7 @@ func (h *Handler) UpdateCustomer(c *fiber.Ctx) error {
 
 // DeleteCustomer deletes a customer
 // @Summary      Delete a customer
-// @Description  Delete a customer from the shop
+// @Description  Delete a customer from the shop. Their orders are kept with the contact details they were placed with; use the erasure endpoint to remove those as well.
 // @Tags         customer
 // @Produce      json
 // @Param        shop_id path string true "Shop ID"
@@ -462,7 +462,7 @@ func (h *Handler) GetCustomerOrders(c *fiber.Ctx) error {
 				Discount:        models.NumericToFloat64(orderRow.Discount),
 				ShippingCost:    models.NumericToFloat64(orderRow.ShippingCost),
 				Tax:             models.NumericToFloat64(orderRow.Tax),
-				ShippingAddress: orderRow.ShippingAddress,
+				ShippingAddress: models.ShippingAddressFromSnapshot(orderRow.ShippingAddress),
 				PaymentMethod:   string(orderRow.PaymentMethod),
 				PaymentStatus:   string(orderRow.PaymentStatus),
 				ShippingMethod:  orderRow.ShippingMethod,
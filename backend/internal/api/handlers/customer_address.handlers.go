@@ -0,0 +1,235 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/petrejonn/naytife/internal/api"
+	"github.com/petrejonn/naytife/internal/api/models"
+	"github.com/petrejonn/naytife/internal/db"
+	"go.uber.org/zap"
+)
+
+// GetCustomerAddresses lists the address book of a customer
+// @Summary      List customer addresses
+// @Description  Get the saved addresses of a customer, defaults first
+// @Tags         customer
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        customer_id path string true "Customer ID"
+// @Success      200  {object}   models.SuccessResponse{data=[]models.CustomerAddress} "Addresses fetched successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      404  {object}   models.ErrorResponse "Customer not found"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/customers/{customer_id}/addresses [get]
+func (h *Handler) GetCustomerAddresses(c *fiber.Ctx) error {
+	shopID, customerID, err := h.shopCustomerFromPath(c)
+	if err != nil {
+		return err
+	}
+
+	addresses, err := h.Repository.ListCustomerAddresses(c.Context(), db.ListCustomerAddressesParams{
+		ShopCustomerID: customerID,
+		ShopID:         shopID,
+	})
+	if err != nil {
+		zap.L().Error("GetCustomerAddresses: failed to fetch addresses", zap.String("customer_id", customerID.String()), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to fetch addresses")
+	}
+
+	response := make([]models.CustomerAddress, len(addresses))
+	for i, address := range addresses {
+		response[i] = models.NewCustomerAddress(address)
+	}
+	return api.SuccessResponse(c, fiber.StatusOK, response, "Addresses fetched successfully")
+}
+
+// CreateCustomerAddress adds an address to a customer's address book
+// @Summary      Create a customer address
+// @Description  Save a new address for a customer. Setting a default flag replaces the customer's current default.
+// @Tags         customer
+// @Accept       json
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        customer_id path string true "Customer ID"
+// @Param        address body models.CustomerAddressParams true "Address"
+// @Success      201  {object}   models.SuccessResponse{data=models.CustomerAddress} "Address created successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      404  {object}   models.ErrorResponse "Customer not found"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/customers/{customer_id}/addresses [post]
+func (h *Handler) CreateCustomerAddress(c *fiber.Ctx) error {
+	shopID, customerID, err := h.shopCustomerFromPath(c)
+	if err != nil {
+		return err
+	}
+	param, err := parseCustomerAddressParams(c)
+	if err != nil {
+		return err
+	}
+
+	address, err := h.Repository.CreateCustomerAddress(c.Context(), db.CreateCustomerAddressParams{
+		ShopCustomerID:    customerID,
+		FirstName:         param.FirstName,
+		LastName:          param.LastName,
+		Company:           param.Company,
+		AddressLine1:      param.AddressLine1,
+		AddressLine2:      param.AddressLine2,
+		City:              param.City,
+		State:             param.State,
+		PostalCode:        param.PostalCode,
+		Country:           param.Country,
+		Phone:             param.Phone,
+		IsDefaultShipping: param.IsDefaultShipping,
+		IsDefaultBilling:  param.IsDefaultBilling,
+		ShopID:            shopID,
+	})
+	if err != nil {
+		zap.L().Error("CreateCustomerAddress: failed to create address", zap.String("customer_id", customerID.String()), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to create address")
+	}
+
+	return api.SuccessResponse(c, fiber.StatusCreated, models.NewCustomerAddress(address), "Address created successfully")
+}
+
+// UpdateCustomerAddress replaces a saved address
+// @Summary      Update a customer address
+// @Description  Replace a saved address of a customer. Setting a default flag replaces the customer's current default.
+// @Tags         customer
+// @Accept       json
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        customer_id path string true "Customer ID"
+// @Param        address_id path string true "Address ID"
+// @Param        address body models.CustomerAddressParams true "Address"
+// @Success      200  {object}   models.SuccessResponse{data=models.CustomerAddress} "Address updated successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      404  {object}   models.ErrorResponse "Address not found"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/customers/{customer_id}/addresses/{address_id} [put]
+func (h *Handler) UpdateCustomerAddress(c *fiber.Ctx) error {
+	shopID, customerID, err := h.shopCustomerFromPath(c)
+	if err != nil {
+		return err
+	}
+	addressID, err := api.ParseIDParameter(c, "address_id", "Address")
+	if err != nil {
+		return err
+	}
+	param, err := parseCustomerAddressParams(c)
+	if err != nil {
+		return err
+	}
+
+	address, err := h.Repository.UpdateCustomerAddress(c.Context(), db.UpdateCustomerAddressParams{
+		FirstName:         param.FirstName,
+		LastName:          param.LastName,
+		Company:           param.Company,
+		AddressLine1:      param.AddressLine1,
+		AddressLine2:      param.AddressLine2,
+		City:              param.City,
+		State:             param.State,
+		PostalCode:        param.PostalCode,
+		Country:           param.Country,
+		Phone:             param.Phone,
+		IsDefaultShipping: param.IsDefaultShipping,
+		IsDefaultBilling:  param.IsDefaultBilling,
+		AddressID:         addressID,
+		ShopCustomerID:    customerID,
+		ShopID:            shopID,
+	})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return api.NotFoundErrorResponse(c, "Address")
+		}
+		zap.L().Error("UpdateCustomerAddress: failed to update address", zap.Int64("address_id", addressID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to update address")
+	}
+
+	return api.SuccessResponse(c, fiber.StatusOK, models.NewCustomerAddress(address), "Address updated successfully")
+}
+
+// DeleteCustomerAddress removes a saved address
+// @Summary      Delete a customer address
+// @Description  Remove an address from a customer's address book
+// @Tags         customer
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        customer_id path string true "Customer ID"
+// @Param        address_id path string true "Address ID"
+// @Success      200  {object}   models.SuccessResponse "Address deleted successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      404  {object}   models.ErrorResponse "Address not found"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/customers/{customer_id}/addresses/{address_id} [delete]
+func (h *Handler) DeleteCustomerAddress(c *fiber.Ctx) error {
+	shopID, customerID, err := h.shopCustomerFromPath(c)
+	if err != nil {
+		return err
+	}
+	addressID, err := api.ParseIDParameter(c, "address_id", "Address")
+	if err != nil {
+		return err
+	}
+
+	deleted, err := h.Repository.DeleteCustomerAddress(c.Context(), db.DeleteCustomerAddressParams{
+		AddressID:      addressID,
+		ShopCustomerID: customerID,
+		ShopID:         shopID,
+	})
+	if err != nil {
+		zap.L().Error("DeleteCustomerAddress: failed to delete address", zap.Int64("address_id", addressID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to delete address")
+	}
+	if deleted == 0 {
+		return api.NotFoundErrorResponse(c, "Address")
+	}
+
+	return api.SuccessResponse(c, fiber.StatusOK, nil, "Address deleted successfully")
+}
+
+// shopCustomerFromPath parses the shop and customer of an address route and
+// checks that the customer belongs to the shop
+func (h *Handler) shopCustomerFromPath(c *fiber.Ctx) (int64, uuid.UUID, error) {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return 0, uuid.Nil, err
+	}
+	customerID, err := uuid.Parse(c.Params("customer_id"))
+	if err != nil {
+		return 0, uuid.Nil, fiber.NewError(fiber.StatusBadRequest, "Invalid customer ID")
+	}
+
+	if _, err := h.Repository.GetCustomerById(c.Context(), db.GetCustomerByIdParams{
+		ShopCustomerID: customerID,
+		ShopID:         shopID,
+	}); err != nil {
+		if err == pgx.ErrNoRows {
+			return 0, uuid.Nil, fiber.NewError(fiber.StatusNotFound, "Customer not found")
+		}
+		zap.L().Error("shopCustomerFromPath: failed to fetch customer", zap.String("customer_id", customerID.String()), zap.Error(err))
+		return 0, uuid.Nil, fiber.NewError(fiber.StatusInternalServerError, "Failed to fetch customer")
+	}
+	return shopID, customerID, nil
+}
+
+func parseCustomerAddressParams(c *fiber.Ctx) (models.CustomerAddressParams, error) {
+	var param models.CustomerAddressParams
+	if err := c.BodyParser(&param); err != nil {
+		return param, fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+	param.Normalize()
+
+	validator := &models.XValidator{}
+	if errs := validator.Validate(&param); len(errs) > 0 {
+		return param, &fiber.Error{
+			Code:    fiber.ErrBadRequest.Code,
+			Message: models.FormatValidationErrors(errs),
+		}
+	}
+	return param, nil
+}
@@ -0,0 +1,332 @@
+package handlers
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/petrejonn/naytife/internal/api"
+	"github.com/petrejonn/naytife/internal/api/models"
+	"github.com/petrejonn/naytife/internal/db"
+	"go.uber.org/zap"
+)
+
+// ExportCustomerData exports the personal data of a customer
+// @Summary      Export customer data
+// @Description  Package a customer's profile, addresses, orders and reviews for a data subject access request. The export is recorded in the data request log.
+// @Tags         customer
+// @Produce      json
+// @Produce      application/zip
+// @Param        shop_id path string true "Shop ID"
+// @Param        customer_id path string true "Customer ID"
+// @Param        format query string false "json, or zip for one JSON file per section" Enums(json, zip) default(json)
+// @Success      200  {object}   models.SuccessResponse{data=models.CustomerDataExport} "Customer data exported successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      404  {object}   models.ErrorResponse "Customer not found"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/customers/{customer_id}/export [get]
+func (h *Handler) ExportCustomerData(c *fiber.Ctx) error {
+	shopID, customerID, err := h.shopCustomerFromPath(c)
+	if err != nil {
+		return err
+	}
+	format := c.Query("format", "json")
+	if format != "json" && format != "zip" {
+		return api.ErrorResponse(c, fiber.StatusBadRequest, "format must be json or zip", nil)
+	}
+
+	export, err := h.customerDataExport(c, shopID, customerID)
+	if err != nil {
+		zap.L().Error("ExportCustomerData: failed to collect customer data", zap.String("customer_id", customerID.String()), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to export customer data")
+	}
+
+	if _, err := recordCustomerDataRequest(c, h.Repository, db.CustomerDataRequestKindExport, shopID, customerID, map[string]interface{}{
+		"format":    format,
+		"addresses": len(export.Addresses),
+		"orders":    len(export.Orders),
+		"reviews":   len(export.Reviews),
+	}); err != nil {
+		zap.L().Error("ExportCustomerData: failed to record data request", zap.String("customer_id", customerID.String()), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to export customer data")
+	}
+
+	if format == "json" {
+		return api.SuccessResponse(c, fiber.StatusOK, export, "Customer data exported successfully")
+	}
+
+	c.Set(fiber.HeaderContentType, "application/zip")
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="customer-%s.zip"`, customerID))
+	archive := zip.NewWriter(c.Response().BodyWriter())
+	for _, file := range []struct {
+		name string
+		data interface{}
+	}{
+		{"profile.json", export.Customer},
+		{"addresses.json", export.Addresses},
+		{"orders.json", export.Orders},
+		{"reviews.json", export.Reviews},
+	} {
+		w, err := archive.CreateHeader(&zip.FileHeader{Name: file.name, Method: zip.Deflate, Modified: export.ExportedAt})
+		if err == nil {
+			encoder := json.NewEncoder(w)
+			encoder.SetIndent("", "  ")
+			err = encoder.Encode(file.data)
+		}
+		if err != nil {
+			zap.L().Error("ExportCustomerData: failed to write archive", zap.String("customer_id", customerID.String()), zap.Error(err))
+			return api.SystemErrorResponse(c, err, "Failed to export customer data")
+		}
+	}
+	if err := archive.Close(); err != nil {
+		zap.L().Error("ExportCustomerData: failed to write archive", zap.String("customer_id", customerID.String()), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to export customer data")
+	}
+	return nil
+}
+
+// EraseCustomerData erases the personal data of a customer
+// @Summary      Erase customer data
+// @Description  Erase a customer for a data subject erasure request. Orders are kept for accounting with the customer's name, email, phone and address removed, reviews are anonymized and the customer's account, addresses, wishlist and subscriptions are deleted. The erasure is recorded in the data request log.
+// @Tags         customer
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        customer_id path string true "Customer ID"
+// @Success      200  {object}   models.SuccessResponse{data=models.CustomerDataRequest} "Customer data erased successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      404  {object}   models.ErrorResponse "Customer not found"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/customers/{customer_id}/erasure [post]
+func (h *Handler) EraseCustomerData(c *fiber.Ctx) error {
+	shopID, customerID, err := h.shopCustomerFromPath(c)
+	if err != nil {
+		return err
+	}
+	customer, err := h.Repository.GetCustomerById(c.Context(), db.GetCustomerByIdParams{
+		ShopCustomerID: customerID,
+		ShopID:         shopID,
+	})
+	if err != nil {
+		zap.L().Error("EraseCustomerData: failed to fetch customer", zap.String("customer_id", customerID.String()), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to fetch customer")
+	}
+
+	var request db.CustomerDataRequest
+	err = h.Repository.WithTx(c.Context(), func(q *db.Queries) error {
+		// Reviews are matched through the customer's orders, so they go first
+		reviews, err := q.AnonymizeCustomerReviews(c.Context(), db.AnonymizeCustomerReviewsParams{
+			ShopID:         shopID,
+			Email:          customer.Email,
+			ShopCustomerID: customerID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to anonymize reviews: %w", err)
+		}
+		orders, err := q.AnonymizeCustomerOrders(c.Context(), db.AnonymizeCustomerOrdersParams{
+			ShopID:         shopID,
+			ShopCustomerID: customerID,
+			Email:          customer.Email,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to anonymize orders: %w", err)
+		}
+		subscriptions, err := q.DeleteCustomerBackInStockSubscriptions(c.Context(), db.DeleteCustomerBackInStockSubscriptionsParams{
+			ShopID:         shopID,
+			ShopCustomerID: customerID,
+			Email:          customer.Email,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to delete subscriptions: %w", err)
+		}
+		notifications, err := q.DeleteCustomerNotifications(c.Context(), db.DeleteCustomerNotificationsParams{
+			ShopID:    shopID,
+			Recipient: customer.Email,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to delete notifications: %w", err)
+		}
+		// Addresses, wishlist items and group memberships go with the customer
+		if err := q.DeleteCustomer(c.Context(), db.DeleteCustomerParams{
+			ShopCustomerID: customerID,
+			ShopID:         shopID,
+		}); err != nil {
+			return fmt.Errorf("failed to delete customer: %w", err)
+		}
+
+		request, err = recordCustomerDataRequest(c, q, db.CustomerDataRequestKindErasure, shopID, customerID, map[string]interface{}{
+			"orders_anonymized":     orders,
+			"reviews_anonymized":    reviews,
+			"subscriptions_deleted": subscriptions,
+			"notifications_deleted": notifications,
+		})
+		return err
+	})
+	if err != nil {
+		zap.L().Error("EraseCustomerData: failed to erase customer", zap.String("customer_id", customerID.String()), zap.Int64("shop_id", shopID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to erase customer data")
+	}
+
+	return api.SuccessResponse(c, fiber.StatusOK, models.NewCustomerDataRequest(request), "Customer data erased successfully")
+}
+
+// GetCustomerDataRequests lists the data subject requests handled for a shop
+// @Summary      List customer data requests
+// @Description  Get the audit log of customer data exports and erasures, newest first
+// @Tags         customer
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        limit query int false "Limit" default(20)
+// @Param        offset query int false "Offset" default(0)
+// @Success      200  {object}   models.SuccessResponse{data=[]models.CustomerDataRequest} "Customer data requests fetched successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/customer-data-requests [get]
+func (h *Handler) GetCustomerDataRequests(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	limit, offset, err := api.ParsePaginationParams(c)
+	if err != nil {
+		return err
+	}
+
+	requests, err := h.Repository.ListCustomerDataRequests(c.Context(), db.ListCustomerDataRequestsParams{
+		ShopID: shopID,
+		Limit:  int32(limit),
+		Offset: int32(offset),
+	})
+	if err != nil {
+		zap.L().Error("GetCustomerDataRequests: failed to fetch requests", zap.Int64("shop_id", shopID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to fetch customer data requests")
+	}
+	total, err := h.Repository.CountCustomerDataRequests(c.Context(), shopID)
+	if err != nil {
+		zap.L().Error("GetCustomerDataRequests: failed to count requests", zap.Int64("shop_id", shopID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to count customer data requests")
+	}
+
+	response := make([]models.CustomerDataRequest, len(requests))
+	for i, request := range requests {
+		response[i] = models.NewCustomerDataRequest(request)
+	}
+	page := (offset / limit) + 1
+	return api.PaginatedSuccessResponse(c, fiber.StatusOK, response, total, page, limit, "Customer data requests fetched successfully")
+}
+
+// customerDataExport collects everything stored about a customer. Orders placed
+// as a guest with the customer's email are included.
+func (h *Handler) customerDataExport(c *fiber.Ctx, shopID int64, customerID uuid.UUID) (models.CustomerDataExport, error) {
+	ctx := c.Context()
+	customer, err := h.Repository.GetCustomerById(ctx, db.GetCustomerByIdParams{
+		ShopCustomerID: customerID,
+		ShopID:         shopID,
+	})
+	if err != nil {
+		return models.CustomerDataExport{}, err
+	}
+	shop, err := h.Repository.GetShop(ctx, shopID)
+	if err != nil {
+		return models.CustomerDataExport{}, err
+	}
+
+	export := models.CustomerDataExport{
+		ExportedAt: time.Now().UTC(),
+		Customer: models.CustomerResponse{
+			CustomerID:     customer.ShopCustomerID,
+			ShopID:         customer.ShopID,
+			Email:          &customer.Email,
+			Name:           customer.Name,
+			Locale:         customer.Locale,
+			ProfilePicture: customer.ProfilePicture,
+			CreatedAt:      customer.CreatedAt,
+			LastLogin:      customer.LastLogin,
+			VerifiedEmail:  customer.VerifiedEmail,
+			AuthProvider:   customer.AuthProvider,
+			AuthProviderID: customer.AuthProviderID,
+		},
+		Addresses: []models.CustomerAddress{},
+		Orders:    []models.Order{},
+		Reviews:   []models.ProductReview{},
+	}
+
+	addresses, err := h.Repository.ListCustomerAddresses(ctx, db.ListCustomerAddressesParams{
+		ShopCustomerID: customerID,
+		ShopID:         shopID,
+	})
+	if err != nil {
+		return export, err
+	}
+	for _, address := range addresses {
+		export.Addresses = append(export.Addresses, models.NewCustomerAddress(address))
+	}
+
+	orders, err := h.Repository.ExportCustomerOrders(ctx, db.ExportCustomerOrdersParams{
+		ShopID:         shopID,
+		ShopCustomerID: customerID,
+		Email:          customer.Email,
+	})
+	if err != nil {
+		return export, err
+	}
+	for _, order := range orders {
+		items, err := h.Repository.GetOrderItemsByOrder(ctx, db.GetOrderItemsByOrderParams{
+			OrderID: order.OrderID,
+			ShopID:  shopID,
+		})
+		if err != nil {
+			return export, err
+		}
+		mapped, err := models.NewOrder(order, items, shop.CurrencyCode)
+		if err != nil {
+			return export, err
+		}
+		export.Orders = append(export.Orders, mapped)
+	}
+
+	reviews, err := h.Repository.ExportCustomerReviews(ctx, db.ExportCustomerReviewsParams{
+		ShopID:         shopID,
+		Email:          customer.Email,
+		ShopCustomerID: customerID,
+	})
+	if err != nil {
+		return export, err
+	}
+	for _, review := range reviews {
+		export.Reviews = append(export.Reviews, productReviewResponse(review))
+	}
+	return export, nil
+}
+
+// customerDataRequestRecorder is implemented by both the repository and the
+// queries of a transaction
+type customerDataRequestRecorder interface {
+	CreateCustomerDataRequest(ctx context.Context, arg db.CreateCustomerDataRequestParams) (db.CustomerDataRequest, error)
+}
+
+// recordCustomerDataRequest adds a data request to the audit log. details must
+// only hold counts and settings, never the personal data itself.
+func recordCustomerDataRequest(c *fiber.Ctx, q customerDataRequestRecorder, kind db.CustomerDataRequestKind, shopID int64, customerID uuid.UUID, details map[string]interface{}) (db.CustomerDataRequest, error) {
+	encoded, err := json.Marshal(details)
+	if err != nil {
+		return db.CustomerDataRequest{}, fmt.Errorf("failed to encode request details: %w", err)
+	}
+	request, err := q.CreateCustomerDataRequest(c.Context(), db.CreateCustomerDataRequestParams{
+		Kind:           kind,
+		ShopCustomerID: customerID,
+		RequestedBy:    requestUser(c),
+		Details:        encoded,
+		ShopID:         shopID,
+	})
+	if err != nil {
+		return request, fmt.Errorf("failed to record data request: %w", err)
+	}
+	return request, nil
+}
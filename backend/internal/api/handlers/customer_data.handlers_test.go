@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"sort"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/petrejonn/naytife/internal/db"
+	"github.com/petrejonn/naytife/internal/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// customerDataRepository holds one customer with an address, a guest order and a
+// review, and records the data requests logged for them
+type customerDataRepository struct {
+	db.Repository
+	customer db.ShopCustomer
+	requests []db.CreateCustomerDataRequestParams
+}
+
+func (r *customerDataRepository) GetCustomerById(ctx context.Context, arg db.GetCustomerByIdParams) (db.ShopCustomer, error) {
+	if arg.ShopCustomerID != r.customer.ShopCustomerID || arg.ShopID != r.customer.ShopID {
+		return db.ShopCustomer{}, pgx.ErrNoRows
+	}
+	return r.customer, nil
+}
+
+func (r *customerDataRepository) GetShop(ctx context.Context, shopID int64) (db.Shop, error) {
+	return db.Shop{ShopID: shopID, CurrencyCode: "USD"}, nil
+}
+
+func (r *customerDataRepository) ListCustomerAddresses(ctx context.Context, arg db.ListCustomerAddressesParams) ([]db.CustomerAddress, error) {
+	return []db.CustomerAddress{{AddressID: 1, ShopCustomerID: arg.ShopCustomerID, FirstName: "Ada", Country: "NG"}}, nil
+}
+
+func (r *customerDataRepository) ExportCustomerOrders(ctx context.Context, arg db.ExportCustomerOrdersParams) ([]db.Order, error) {
+	return []db.Order{{OrderID: 9, Amount: money.New(2500, "USD").Numeric(), CustomerEmail: &arg.Email, ShopID: arg.ShopID}}, nil
+}
+
+func (r *customerDataRepository) GetOrderItemsByOrder(ctx context.Context, arg db.GetOrderItemsByOrderParams) ([]db.OrderItem, error) {
+	return []db.OrderItem{{OrderItemID: 1, OrderID: arg.OrderID, Quantity: 1, Price: money.New(2500, "USD").Numeric()}}, nil
+}
+
+func (r *customerDataRepository) ExportCustomerReviews(ctx context.Context, arg db.ExportCustomerReviewsParams) ([]db.ProductReview, error) {
+	return []db.ProductReview{{ReviewID: 3, OrderID: 9, Rating: 5, CustomerEmail: arg.Email}}, nil
+}
+
+func (r *customerDataRepository) CreateCustomerDataRequest(ctx context.Context, arg db.CreateCustomerDataRequestParams) (db.CustomerDataRequest, error) {
+	r.requests = append(r.requests, arg)
+	return db.CustomerDataRequest{RequestID: int64(len(r.requests)), Kind: arg.Kind, ShopCustomerID: arg.ShopCustomerID, Details: arg.Details}, nil
+}
+
+func TestExportCustomerData(t *testing.T) {
+	customerID := uuid.New()
+	newApp := func(repo *customerDataRepository) *fiber.App {
+		h := &Handler{Repository: repo}
+		app := fiber.New()
+		app.Get("/shops/:shop_id/customers/:customer_id/export", func(c *fiber.Ctx) error {
+			c.Locals("user_id", "merchant-1")
+			return h.ExportCustomerData(c)
+		})
+		return app
+	}
+	newRepo := func() *customerDataRepository {
+		return &customerDataRepository{customer: db.ShopCustomer{ShopCustomerID: customerID, ShopID: 1, Email: "ada@example.com"}}
+	}
+
+	t.Run("json", func(t *testing.T) {
+		repo := newRepo()
+		resp, err := newApp(repo).Test(httptest.NewRequest(fiber.MethodGet, "/shops/1/customers/"+customerID.String()+"/export", nil))
+		require.NoError(t, err)
+		require.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+		var body struct {
+			Data struct {
+				Customer  struct{ Email string }    `json:"customer"`
+				Addresses []json.RawMessage         `json:"addresses"`
+				Orders    []struct{ Amount string } `json:"orders"`
+				Reviews   []json.RawMessage         `json:"reviews"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		assert.Equal(t, "ada@example.com", body.Data.Customer.Email)
+		assert.Len(t, body.Data.Addresses, 1)
+		require.Len(t, body.Data.Orders, 1)
+		assert.Equal(t, "25.00", body.Data.Orders[0].Amount)
+		assert.Len(t, body.Data.Reviews, 1)
+
+		// The audit log holds counts only, never the exported data itself
+		require.Len(t, repo.requests, 1)
+		request := repo.requests[0]
+		assert.Equal(t, db.CustomerDataRequestKindExport, request.Kind)
+		assert.Equal(t, "merchant-1", *request.RequestedBy)
+		assert.JSONEq(t, `{"format":"json","addresses":1,"orders":1,"reviews":1}`, string(request.Details))
+		assert.NotContains(t, string(request.Details), "ada@example.com")
+	})
+
+	t.Run("zip", func(t *testing.T) {
+		repo := newRepo()
+		resp, err := newApp(repo).Test(httptest.NewRequest(fiber.MethodGet, "/shops/1/customers/"+customerID.String()+"/export?format=zip", nil))
+		require.NoError(t, err)
+		require.Equal(t, fiber.StatusOK, resp.StatusCode)
+		assert.Equal(t, "application/zip", resp.Header.Get(fiber.HeaderContentType))
+
+		data, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		require.NoError(t, err)
+		var names []string
+		for _, file := range archive.File {
+			names = append(names, file.Name)
+		}
+		sort.Strings(names)
+		assert.Equal(t, []string{"addresses.json", "orders.json", "profile.json", "reviews.json"}, names)
+		require.Len(t, repo.requests, 1)
+		assert.JSONEq(t, `{"format":"zip","addresses":1,"orders":1,"reviews":1}`, string(repo.requests[0].Details))
+	})
+
+	t.Run("unknown format", func(t *testing.T) {
+		repo := newRepo()
+		resp, err := newApp(repo).Test(httptest.NewRequest(fiber.MethodGet, "/shops/1/customers/"+customerID.String()+"/export?format=csv", nil))
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+		assert.Empty(t, repo.requests)
+	})
+
+	t.Run("customer of another shop", func(t *testing.T) {
+		repo := newRepo()
+		resp, err := newApp(repo).Test(httptest.NewRequest(fiber.MethodGet, "/shops/2/customers/"+customerID.String()+"/export", nil))
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+		assert.Empty(t, repo.requests)
+	})
+}
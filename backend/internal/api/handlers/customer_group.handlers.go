@@ -0,0 +1,541 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/petrejonn/naytife/internal/api"
+	"github.com/petrejonn/naytife/internal/api/models"
+	"github.com/petrejonn/naytife/internal/db"
+	"github.com/petrejonn/naytife/internal/db/errors"
+	"github.com/petrejonn/naytife/internal/money"
+	"github.com/petrejonn/naytife/internal/services"
+	"go.uber.org/zap"
+)
+
+// GetCustomerGroups lists the customer groups of a shop
+// @Summary      List customer groups
+// @Description  Get the customer groups of a shop with their member counts
+// @Tags         customer
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Success      200  {object}   models.SuccessResponse{data=[]models.CustomerGroup} "Customer groups fetched successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/customer-groups [get]
+func (h *Handler) GetCustomerGroups(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+
+	rows, err := h.Repository.ListCustomerGroups(c.Context(), shopID)
+	if err != nil {
+		zap.L().Error("GetCustomerGroups: failed to fetch groups", zap.Int64("shop_id", shopID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to fetch customer groups")
+	}
+
+	response := make([]models.CustomerGroup, len(rows))
+	for i, row := range rows {
+		response[i] = models.NewCustomerGroup(db.CustomerGroup{
+			GroupID:         row.GroupID,
+			Name:            row.Name,
+			Description:     row.Description,
+			DiscountPercent: row.DiscountPercent,
+			CreatedAt:       row.CreatedAt,
+			UpdatedAt:       row.UpdatedAt,
+			ShopID:          row.ShopID,
+		})
+		response[i].MemberCount = &row.MemberCount
+	}
+	return api.SuccessResponse(c, fiber.StatusOK, response, "Customer groups fetched successfully")
+}
+
+// CreateCustomerGroup creates a customer group
+// @Summary      Create a customer group
+// @Description  Create a manual customer group, optionally with a percentage discount for its members
+// @Tags         customer
+// @Accept       json
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        group body models.CustomerGroupParams true "Customer group"
+// @Success      201  {object}   models.SuccessResponse{data=models.CustomerGroup} "Customer group created successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      409  {object}   models.ErrorResponse "Customer group already exists"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/customer-groups [post]
+func (h *Handler) CreateCustomerGroup(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	param, err := parseCustomerGroupParams(c)
+	if err != nil {
+		return err
+	}
+
+	group, err := h.Repository.CreateCustomerGroup(c.Context(), db.CreateCustomerGroupParams{
+		Name:            param.Name,
+		Description:     param.Description,
+		DiscountPercent: discountPercentNumeric(param.DiscountPercent),
+		ShopID:          shopID,
+	})
+	if err != nil {
+		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == errors.UniqueViolation {
+			return api.ErrorResponse(c, fiber.StatusConflict, "Customer group already exists", nil)
+		}
+		zap.L().Error("CreateCustomerGroup: failed to create group", zap.Int64("shop_id", shopID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to create customer group")
+	}
+
+	return api.SuccessResponse(c, fiber.StatusCreated, models.NewCustomerGroup(group), "Customer group created successfully")
+}
+
+// UpdateCustomerGroup replaces a customer group
+// @Summary      Update a customer group
+// @Description  Replace the name, description and discount of a customer group
+// @Tags         customer
+// @Accept       json
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        group_id path string true "Group ID"
+// @Param        group body models.CustomerGroupParams true "Customer group"
+// @Success      200  {object}   models.SuccessResponse{data=models.CustomerGroup} "Customer group updated successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      404  {object}   models.ErrorResponse "Customer group not found"
+// @Failure      409  {object}   models.ErrorResponse "Customer group already exists"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/customer-groups/{group_id} [put]
+func (h *Handler) UpdateCustomerGroup(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	groupID, err := api.ParseIDParameter(c, "group_id", "Customer group")
+	if err != nil {
+		return err
+	}
+	param, err := parseCustomerGroupParams(c)
+	if err != nil {
+		return err
+	}
+
+	group, err := h.Repository.UpdateCustomerGroup(c.Context(), db.UpdateCustomerGroupParams{
+		Name:            param.Name,
+		Description:     param.Description,
+		DiscountPercent: discountPercentNumeric(param.DiscountPercent),
+		GroupID:         groupID,
+		ShopID:          shopID,
+	})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return api.NotFoundErrorResponse(c, "Customer group")
+		}
+		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == errors.UniqueViolation {
+			return api.ErrorResponse(c, fiber.StatusConflict, "Customer group already exists", nil)
+		}
+		zap.L().Error("UpdateCustomerGroup: failed to update group", zap.Int64("group_id", groupID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to update customer group")
+	}
+
+	return api.SuccessResponse(c, fiber.StatusOK, models.NewCustomerGroup(group), "Customer group updated successfully")
+}
+
+// DeleteCustomerGroup deletes a customer group
+// @Summary      Delete a customer group
+// @Description  Delete a customer group with its memberships and price list. The customers are kept.
+// @Tags         customer
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        group_id path string true "Group ID"
+// @Success      200  {object}   models.SuccessResponse "Customer group deleted successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      404  {object}   models.ErrorResponse "Customer group not found"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/customer-groups/{group_id} [delete]
+func (h *Handler) DeleteCustomerGroup(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	groupID, err := api.ParseIDParameter(c, "group_id", "Customer group")
+	if err != nil {
+		return err
+	}
+
+	deleted, err := h.Repository.DeleteCustomerGroup(c.Context(), db.DeleteCustomerGroupParams{
+		GroupID: groupID,
+		ShopID:  shopID,
+	})
+	if err != nil {
+		zap.L().Error("DeleteCustomerGroup: failed to delete group", zap.Int64("group_id", groupID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to delete customer group")
+	}
+	if deleted == 0 {
+		return api.NotFoundErrorResponse(c, "Customer group")
+	}
+
+	return api.SuccessResponse(c, fiber.StatusOK, nil, "Customer group deleted successfully")
+}
+
+// GetCustomerGroupMembers lists the customers of a group
+// @Summary      List customer group members
+// @Description  Get the customers of a group, most recently added first
+// @Tags         customer
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        group_id path string true "Group ID"
+// @Success      200  {object}   models.SuccessResponse{data=[]models.CustomerResponse} "Customer group members fetched successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      404  {object}   models.ErrorResponse "Customer group not found"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/customer-groups/{group_id}/members [get]
+func (h *Handler) GetCustomerGroupMembers(c *fiber.Ctx) error {
+	shopID, groupID, err := h.customerGroupFromPath(c)
+	if err != nil {
+		return err
+	}
+
+	customers, err := h.Repository.ListCustomerGroupMembers(c.Context(), db.ListCustomerGroupMembersParams{
+		GroupID: groupID,
+		ShopID:  shopID,
+	})
+	if err != nil {
+		zap.L().Error("GetCustomerGroupMembers: failed to fetch members", zap.Int64("group_id", groupID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to fetch customer group members")
+	}
+
+	response := make([]models.CustomerResponse, len(customers))
+	for i, customer := range customers {
+		response[i] = models.CustomerResponse{
+			CustomerID:     customer.ShopCustomerID,
+			ShopID:         customer.ShopID,
+			Email:          &customer.Email,
+			Name:           customer.Name,
+			Locale:         customer.Locale,
+			ProfilePicture: customer.ProfilePicture,
+			CreatedAt:      customer.CreatedAt,
+			LastLogin:      customer.LastLogin,
+			VerifiedEmail:  customer.VerifiedEmail,
+			AuthProvider:   customer.AuthProvider,
+			AuthProviderID: customer.AuthProviderID,
+		}
+	}
+	return api.SuccessResponse(c, fiber.StatusOK, response, "Customer group members fetched successfully")
+}
+
+// AddCustomerGroupMember adds a customer to a group
+// @Summary      Add a customer to a group
+// @Description  Add a customer to a group. Adding an existing member has no effect.
+// @Tags         customer
+// @Accept       json
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        group_id path string true "Group ID"
+// @Param        member body models.CustomerGroupMemberParams true "Customer"
+// @Success      200  {object}   models.SuccessResponse "Customer added to group successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      404  {object}   models.ErrorResponse "Customer group or customer not found"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/customer-groups/{group_id}/members [post]
+func (h *Handler) AddCustomerGroupMember(c *fiber.Ctx) error {
+	shopID, groupID, err := h.customerGroupFromPath(c)
+	if err != nil {
+		return err
+	}
+
+	var param models.CustomerGroupMemberParams
+	if err := c.BodyParser(&param); err != nil {
+		return api.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body", nil)
+	}
+	validator := &models.XValidator{}
+	if errs := validator.Validate(&param); len(errs) > 0 {
+		return &fiber.Error{
+			Code:    fiber.ErrBadRequest.Code,
+			Message: models.FormatValidationErrors(errs),
+		}
+	}
+
+	if _, err := h.Repository.GetCustomerById(c.Context(), db.GetCustomerByIdParams{
+		ShopCustomerID: param.CustomerID,
+		ShopID:         shopID,
+	}); err != nil {
+		if err == pgx.ErrNoRows {
+			return api.NotFoundErrorResponse(c, "Customer")
+		}
+		zap.L().Error("AddCustomerGroupMember: failed to fetch customer", zap.String("customer_id", param.CustomerID.String()), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to fetch customer")
+	}
+
+	if err := h.Repository.AddCustomerGroupMember(c.Context(), db.AddCustomerGroupMemberParams{
+		GroupID:        groupID,
+		ShopCustomerID: param.CustomerID,
+		ShopID:         shopID,
+	}); err != nil {
+		zap.L().Error("AddCustomerGroupMember: failed to add member", zap.Int64("group_id", groupID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to add customer to group")
+	}
+
+	return api.SuccessResponse(c, fiber.StatusOK, nil, "Customer added to group successfully")
+}
+
+// RemoveCustomerGroupMember removes a customer from a group
+// @Summary      Remove a customer from a group
+// @Description  Remove a customer from a group. The customer is kept.
+// @Tags         customer
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        group_id path string true "Group ID"
+// @Param        customer_id path string true "Customer ID"
+// @Success      200  {object}   models.SuccessResponse "Customer removed from group successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      404  {object}   models.ErrorResponse "Customer group member not found"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/customer-groups/{group_id}/members/{customer_id} [delete]
+func (h *Handler) RemoveCustomerGroupMember(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	groupID, err := api.ParseIDParameter(c, "group_id", "Customer group")
+	if err != nil {
+		return err
+	}
+	customerID, err := uuid.Parse(c.Params("customer_id"))
+	if err != nil {
+		return api.ErrorResponse(c, fiber.StatusBadRequest, "Invalid customer ID", nil)
+	}
+
+	removed, err := h.Repository.RemoveCustomerGroupMember(c.Context(), db.RemoveCustomerGroupMemberParams{
+		GroupID:        groupID,
+		ShopCustomerID: customerID,
+		ShopID:         shopID,
+	})
+	if err != nil {
+		zap.L().Error("RemoveCustomerGroupMember: failed to remove member", zap.Int64("group_id", groupID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to remove customer from group")
+	}
+	if removed == 0 {
+		return api.NotFoundErrorResponse(c, "Customer group member")
+	}
+
+	return api.SuccessResponse(c, fiber.StatusOK, nil, "Customer removed from group successfully")
+}
+
+// GetCustomerGroupPrices lists the price list of a group
+// @Summary      List customer group prices
+// @Description  Get the variant prices of a group, in the shop currency
+// @Tags         customer
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        group_id path string true "Group ID"
+// @Success      200  {object}   models.SuccessResponse{data=[]models.CustomerGroupPrice} "Customer group prices fetched successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      404  {object}   models.ErrorResponse "Customer group not found"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/customer-groups/{group_id}/prices [get]
+func (h *Handler) GetCustomerGroupPrices(c *fiber.Ctx) error {
+	shopID, groupID, err := h.customerGroupFromPath(c)
+	if err != nil {
+		return err
+	}
+	shop, err := h.Repository.GetShop(c.Context(), shopID)
+	if err != nil {
+		zap.L().Error("GetCustomerGroupPrices: failed to fetch shop", zap.Int64("shop_id", shopID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to fetch shop")
+	}
+
+	rows, err := h.Repository.ListCustomerGroupPrices(c.Context(), db.ListCustomerGroupPricesParams{
+		GroupID: groupID,
+		ShopID:  shopID,
+	})
+	if err != nil {
+		zap.L().Error("GetCustomerGroupPrices: failed to fetch prices", zap.Int64("group_id", groupID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to fetch customer group prices")
+	}
+
+	response := make([]models.CustomerGroupPrice, len(rows))
+	for i, row := range rows {
+		price, err := money.FromNumeric(row.Price, shop.CurrencyCode)
+		if err != nil {
+			return api.SystemErrorResponse(c, err, "Failed to read customer group price")
+		}
+		response[i] = models.CustomerGroupPrice{
+			VariantID: row.ProductVariationID,
+			Price:     price,
+			UpdatedAt: row.UpdatedAt.Time,
+		}
+	}
+	return api.SuccessResponse(c, fiber.StatusOK, response, "Customer group prices fetched successfully")
+}
+
+// SetCustomerGroupPrice sets the price of a variant for a group
+// @Summary      Set customer group price
+// @Description  Set the price members of a group pay for a variant, in the shop currency. It takes precedence over the group discount.
+// @Tags         customer
+// @Accept       json
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        group_id path string true "Group ID"
+// @Param        variant_id path string true "Variant ID"
+// @Param        price body models.VariantPriceParams true "Price"
+// @Success      200  {object}   models.SuccessResponse{data=models.CustomerGroupPrice} "Customer group price saved successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      404  {object}   models.ErrorResponse "Customer group or variant not found"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/customer-groups/{group_id}/prices/{variant_id} [put]
+func (h *Handler) SetCustomerGroupPrice(c *fiber.Ctx) error {
+	shopID, groupID, err := h.customerGroupFromPath(c)
+	if err != nil {
+		return err
+	}
+	variantID, err := api.ParseIDParameter(c, "variant_id", "Variant")
+	if err != nil {
+		return err
+	}
+
+	var param models.VariantPriceParams
+	if err := c.BodyParser(&param); err != nil {
+		return api.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body", nil)
+	}
+	shop, err := h.Repository.GetShop(c.Context(), shopID)
+	if err != nil {
+		zap.L().Error("SetCustomerGroupPrice: failed to fetch shop", zap.Int64("shop_id", shopID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to fetch shop")
+	}
+	price, err := param.Price.In(shop.CurrencyCode)
+	if err != nil {
+		return api.ErrorResponse(c, fiber.StatusBadRequest, err.Error(), nil)
+	}
+	if price.IsNegative() {
+		return api.ErrorResponse(c, fiber.StatusBadRequest, "Price must not be negative", nil)
+	}
+
+	row, err := h.Repository.UpsertCustomerGroupPrice(c.Context(), db.UpsertCustomerGroupPriceParams{
+		GroupID:            groupID,
+		Price:              price.Numeric(),
+		ProductVariationID: variantID,
+		ShopID:             shopID,
+	})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return api.NotFoundErrorResponse(c, "Variant")
+		}
+		zap.L().Error("SetCustomerGroupPrice: failed to save price", zap.Int64("group_id", groupID), zap.Int64("variant_id", variantID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to save customer group price")
+	}
+
+	saved, err := money.FromNumeric(row.Price, shop.CurrencyCode)
+	if err != nil {
+		return api.SystemErrorResponse(c, err, "Failed to read customer group price")
+	}
+
+	return api.SuccessResponse(c, fiber.StatusOK, models.CustomerGroupPrice{
+		VariantID: row.ProductVariationID,
+		Price:     saved,
+		UpdatedAt: row.UpdatedAt.Time,
+	}, "Customer group price saved successfully")
+}
+
+// DeleteCustomerGroupPrice removes the price of a variant from a group's price list
+// @Summary      Delete customer group price
+// @Description  Remove a variant from a group's price list so the group discount, if any, applies again
+// @Tags         customer
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        group_id path string true "Group ID"
+// @Param        variant_id path string true "Variant ID"
+// @Success      200  {object}   models.SuccessResponse "Customer group price deleted successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      404  {object}   models.ErrorResponse "Customer group price not found"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/customer-groups/{group_id}/prices/{variant_id} [delete]
+func (h *Handler) DeleteCustomerGroupPrice(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	groupID, err := api.ParseIDParameter(c, "group_id", "Customer group")
+	if err != nil {
+		return err
+	}
+	variantID, err := api.ParseIDParameter(c, "variant_id", "Variant")
+	if err != nil {
+		return err
+	}
+
+	deleted, err := h.Repository.DeleteCustomerGroupPrice(c.Context(), db.DeleteCustomerGroupPriceParams{
+		GroupID:            groupID,
+		ProductVariationID: variantID,
+		ShopID:             shopID,
+	})
+	if err != nil {
+		zap.L().Error("DeleteCustomerGroupPrice: failed to delete price", zap.Int64("group_id", groupID), zap.Int64("variant_id", variantID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to delete customer group price")
+	}
+	if deleted == 0 {
+		return api.NotFoundErrorResponse(c, "Customer group price")
+	}
+
+	return api.SuccessResponse(c, fiber.StatusOK, nil, "Customer group price deleted successfully")
+}
+
+// customerGroupFromPath parses the shop and group of a group route and checks
+// that the group belongs to the shop
+func (h *Handler) customerGroupFromPath(c *fiber.Ctx) (int64, int64, error) {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return 0, 0, err
+	}
+	groupID, err := api.ParseIDParameter(c, "group_id", "Customer group")
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if _, err := h.Repository.GetCustomerGroup(c.Context(), db.GetCustomerGroupParams{
+		GroupID: groupID,
+		ShopID:  shopID,
+	}); err != nil {
+		if err == pgx.ErrNoRows {
+			return 0, 0, fiber.NewError(fiber.StatusNotFound, "Customer group not found")
+		}
+		zap.L().Error("customerGroupFromPath: failed to fetch group", zap.Int64("group_id", groupID), zap.Error(err))
+		return 0, 0, fiber.NewError(fiber.StatusInternalServerError, "Failed to fetch customer group")
+	}
+	return shopID, groupID, nil
+}
+
+func parseCustomerGroupParams(c *fiber.Ctx) (models.CustomerGroupParams, error) {
+	var param models.CustomerGroupParams
+	if err := c.BodyParser(&param); err != nil {
+		return param, fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	validator := &models.XValidator{}
+	if errs := validator.Validate(&param); len(errs) > 0 {
+		return param, &fiber.Error{
+			Code:    fiber.ErrBadRequest.Code,
+			Message: models.FormatValidationErrors(errs),
+		}
+	}
+	return param, nil
+}
+
+func discountPercentNumeric(discount *float64) pgtype.Numeric {
+	if discount == nil {
+		return pgtype.Numeric{}
+	}
+	return services.FloatToNumeric(*discount)
+}
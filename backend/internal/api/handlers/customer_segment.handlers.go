@@ -0,0 +1,393 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/petrejonn/naytife/internal/api"
+	"github.com/petrejonn/naytife/internal/api/models"
+	"github.com/petrejonn/naytife/internal/db"
+	"github.com/petrejonn/naytife/internal/db/errors"
+	"github.com/petrejonn/naytife/internal/money"
+	"go.uber.org/zap"
+)
+
+// GetCustomerSegments lists the customer segments of a shop
+// @Summary      List customer segments
+// @Description  Get the rule-based customer segments of a shop
+// @Tags         customer
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Success      200  {object}   models.SuccessResponse{data=[]models.CustomerSegment} "Customer segments fetched successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/customer-segments [get]
+func (h *Handler) GetCustomerSegments(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	shop, err := h.Repository.GetShop(c.Context(), shopID)
+	if err != nil {
+		return api.NotFoundErrorResponse(c, "Shop")
+	}
+
+	segments, err := h.Repository.ListCustomerSegments(c.Context(), shopID)
+	if err != nil {
+		zap.L().Error("GetCustomerSegments: failed to fetch segments", zap.Int64("shop_id", shopID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to fetch customer segments")
+	}
+
+	response := make([]models.CustomerSegment, len(segments))
+	for i, segment := range segments {
+		if response[i], err = models.NewCustomerSegment(segment, shop.CurrencyCode); err != nil {
+			return api.SystemErrorResponse(c, err, "Failed to read customer segment")
+		}
+	}
+	return api.SuccessResponse(c, fiber.StatusOK, response, "Customer segments fetched successfully")
+}
+
+// CreateCustomerSegment creates a customer segment
+// @Summary      Create a customer segment
+// @Description  Create a segment whose members are the customers matching its rules at the time they are listed
+// @Tags         customer
+// @Accept       json
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        segment body models.CustomerSegmentParams true "Customer segment"
+// @Success      201  {object}   models.SuccessResponse{data=models.CustomerSegment} "Customer segment created successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      409  {object}   models.ErrorResponse "Customer segment already exists"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/customer-segments [post]
+func (h *Handler) CreateCustomerSegment(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	shop, err := h.Repository.GetShop(c.Context(), shopID)
+	if err != nil {
+		return api.NotFoundErrorResponse(c, "Shop")
+	}
+	param, minSpent, maxSpent, err := parseCustomerSegmentParams(c, shop.CurrencyCode)
+	if err != nil {
+		return err
+	}
+
+	segment, err := h.Repository.CreateCustomerSegment(c.Context(), db.CreateCustomerSegmentParams{
+		Name:          param.Name,
+		Description:   param.Description,
+		MinTotalSpent: minSpent,
+		MaxTotalSpent: maxSpent,
+		MinOrderCount: param.MinOrderCount,
+		MaxOrderCount: param.MaxOrderCount,
+		WindowDays:    param.WindowDays,
+		InactiveDays:  param.InactiveDays,
+		ShopID:        shopID,
+	})
+	if err != nil {
+		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == errors.UniqueViolation {
+			return api.ErrorResponse(c, fiber.StatusConflict, "Customer segment already exists", nil)
+		}
+		zap.L().Error("CreateCustomerSegment: failed to create segment", zap.Int64("shop_id", shopID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to create customer segment")
+	}
+
+	response, err := models.NewCustomerSegment(segment, shop.CurrencyCode)
+	if err != nil {
+		return api.SystemErrorResponse(c, err, "Failed to read customer segment")
+	}
+	return api.SuccessResponse(c, fiber.StatusCreated, response, "Customer segment created successfully")
+}
+
+// UpdateCustomerSegment replaces a customer segment
+// @Summary      Update a customer segment
+// @Description  Replace the name, description and rules of a customer segment
+// @Tags         customer
+// @Accept       json
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        segment_id path string true "Segment ID"
+// @Param        segment body models.CustomerSegmentParams true "Customer segment"
+// @Success      200  {object}   models.SuccessResponse{data=models.CustomerSegment} "Customer segment updated successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      404  {object}   models.ErrorResponse "Customer segment not found"
+// @Failure      409  {object}   models.ErrorResponse "Customer segment already exists"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/customer-segments/{segment_id} [put]
+func (h *Handler) UpdateCustomerSegment(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	segmentID, err := api.ParseIDParameter(c, "segment_id", "Customer segment")
+	if err != nil {
+		return err
+	}
+	shop, err := h.Repository.GetShop(c.Context(), shopID)
+	if err != nil {
+		return api.NotFoundErrorResponse(c, "Shop")
+	}
+	param, minSpent, maxSpent, err := parseCustomerSegmentParams(c, shop.CurrencyCode)
+	if err != nil {
+		return err
+	}
+
+	segment, err := h.Repository.UpdateCustomerSegment(c.Context(), db.UpdateCustomerSegmentParams{
+		Name:          param.Name,
+		Description:   param.Description,
+		MinTotalSpent: minSpent,
+		MaxTotalSpent: maxSpent,
+		MinOrderCount: param.MinOrderCount,
+		MaxOrderCount: param.MaxOrderCount,
+		WindowDays:    param.WindowDays,
+		InactiveDays:  param.InactiveDays,
+		SegmentID:     segmentID,
+		ShopID:        shopID,
+	})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return api.NotFoundErrorResponse(c, "Customer segment")
+		}
+		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == errors.UniqueViolation {
+			return api.ErrorResponse(c, fiber.StatusConflict, "Customer segment already exists", nil)
+		}
+		zap.L().Error("UpdateCustomerSegment: failed to update segment", zap.Int64("segment_id", segmentID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to update customer segment")
+	}
+
+	response, err := models.NewCustomerSegment(segment, shop.CurrencyCode)
+	if err != nil {
+		return api.SystemErrorResponse(c, err, "Failed to read customer segment")
+	}
+	return api.SuccessResponse(c, fiber.StatusOK, response, "Customer segment updated successfully")
+}
+
+// DeleteCustomerSegment deletes a customer segment
+// @Summary      Delete a customer segment
+// @Description  Delete a customer segment. Its members are not affected.
+// @Tags         customer
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        segment_id path string true "Segment ID"
+// @Success      200  {object}   models.SuccessResponse "Customer segment deleted successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      404  {object}   models.ErrorResponse "Customer segment not found"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/customer-segments/{segment_id} [delete]
+func (h *Handler) DeleteCustomerSegment(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	segmentID, err := api.ParseIDParameter(c, "segment_id", "Customer segment")
+	if err != nil {
+		return err
+	}
+
+	deleted, err := h.Repository.DeleteCustomerSegment(c.Context(), db.DeleteCustomerSegmentParams{
+		SegmentID: segmentID,
+		ShopID:    shopID,
+	})
+	if err != nil {
+		zap.L().Error("DeleteCustomerSegment: failed to delete segment", zap.Int64("segment_id", segmentID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to delete customer segment")
+	}
+	if deleted == 0 {
+		return api.NotFoundErrorResponse(c, "Customer segment")
+	}
+
+	return api.SuccessResponse(c, fiber.StatusOK, nil, "Customer segment deleted successfully")
+}
+
+// GetCustomerSegmentMembers lists the customers currently matching a segment
+// @Summary      List customer segment members
+// @Description  Evaluate a segment's rules and get the matching customers, biggest spenders first
+// @Tags         customer
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        segment_id path string true "Segment ID"
+// @Param        limit query int false "Limit" default(20)
+// @Param        offset query int false "Offset" default(0)
+// @Success      200  {object}   models.SuccessResponse{data=[]models.CustomerSegmentMember} "Customer segment members fetched successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      404  {object}   models.ErrorResponse "Customer segment not found"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/customer-segments/{segment_id}/members [get]
+func (h *Handler) GetCustomerSegmentMembers(c *fiber.Ctx) error {
+	shopID, segmentID, currencyCode, err := h.customerSegmentFromPath(c)
+	if err != nil {
+		return err
+	}
+	limit, offset, err := api.ParsePaginationParams(c)
+	if err != nil {
+		return err
+	}
+
+	pageLimit := int32(limit)
+	rows, err := h.Repository.ListCustomerSegmentMembers(c.Context(), db.ListCustomerSegmentMembersParams{
+		SegmentID: segmentID,
+		ShopID:    shopID,
+		Limit:     &pageLimit,
+		Offset:    int32(offset),
+	})
+	if err != nil {
+		zap.L().Error("GetCustomerSegmentMembers: failed to evaluate segment", zap.Int64("segment_id", segmentID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to fetch customer segment members")
+	}
+
+	var total int64
+	members := make([]models.CustomerSegmentMember, len(rows))
+	for i, row := range rows {
+		total = row.TotalCount
+		if members[i], err = models.NewCustomerSegmentMember(row, currencyCode); err != nil {
+			return api.SystemErrorResponse(c, err, "Failed to read customer segment member")
+		}
+	}
+
+	page := (offset / limit) + 1
+	return api.PaginatedSuccessResponse(c, fiber.StatusOK, members, total, page, limit, "Customer segment members fetched successfully")
+}
+
+// ExportCustomerSegmentMembers exports the customers currently matching a segment as CSV
+// @Summary      Export customer segment members
+// @Description  Evaluate a segment's rules and download every matching customer as a CSV file
+// @Tags         customer
+// @Produce      text/csv
+// @Param        shop_id path string true "Shop ID"
+// @Param        segment_id path string true "Segment ID"
+// @Success      200  {string}   string "CSV with customer_id, email, name, order_count, total_spent and last_order_at columns"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      404  {object}   models.ErrorResponse "Customer segment not found"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/customer-segments/{segment_id}/members/export [get]
+func (h *Handler) ExportCustomerSegmentMembers(c *fiber.Ctx) error {
+	shopID, segmentID, currencyCode, err := h.customerSegmentFromPath(c)
+	if err != nil {
+		return err
+	}
+
+	rows, err := h.Repository.ListCustomerSegmentMembers(c.Context(), db.ListCustomerSegmentMembersParams{
+		SegmentID: segmentID,
+		ShopID:    shopID,
+	})
+	if err != nil {
+		zap.L().Error("ExportCustomerSegmentMembers: failed to evaluate segment", zap.Int64("segment_id", segmentID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to fetch customer segment members")
+	}
+
+	records := [][]string{{"customer_id", "email", "name", "order_count", "total_spent", "last_order_at"}}
+	for _, row := range rows {
+		member, err := models.NewCustomerSegmentMember(row, currencyCode)
+		if err != nil {
+			return api.SystemErrorResponse(c, err, "Failed to read customer segment member")
+		}
+		var name, lastOrderAt string
+		if member.Name != nil {
+			name = *member.Name
+		}
+		if member.LastOrderAt != nil {
+			lastOrderAt = member.LastOrderAt.UTC().Format(time.RFC3339)
+		}
+		records = append(records, []string{
+			member.CustomerID.String(),
+			member.Email,
+			name,
+			strconv.FormatInt(member.OrderCount, 10),
+			member.TotalSpent.String(),
+			lastOrderAt,
+		})
+	}
+
+	c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="segment-%d-members.csv"`, segmentID))
+	writer := csv.NewWriter(c.Response().BodyWriter())
+	if err := writer.WriteAll(records); err != nil {
+		zap.L().Error("ExportCustomerSegmentMembers: failed to write CSV", zap.Int64("segment_id", segmentID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to export customer segment members")
+	}
+	return nil
+}
+
+// customerSegmentFromPath parses the shop and segment of a segment route, checks
+// that the segment belongs to the shop and returns the shop currency
+func (h *Handler) customerSegmentFromPath(c *fiber.Ctx) (int64, int64, string, error) {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return 0, 0, "", err
+	}
+	segmentID, err := api.ParseIDParameter(c, "segment_id", "Customer segment")
+	if err != nil {
+		return 0, 0, "", err
+	}
+
+	if _, err := h.Repository.GetCustomerSegment(c.Context(), db.GetCustomerSegmentParams{
+		SegmentID: segmentID,
+		ShopID:    shopID,
+	}); err != nil {
+		if err == pgx.ErrNoRows {
+			return 0, 0, "", fiber.NewError(fiber.StatusNotFound, "Customer segment not found")
+		}
+		zap.L().Error("customerSegmentFromPath: failed to fetch segment", zap.Int64("segment_id", segmentID), zap.Error(err))
+		return 0, 0, "", fiber.NewError(fiber.StatusInternalServerError, "Failed to fetch customer segment")
+	}
+	shop, err := h.Repository.GetShop(c.Context(), shopID)
+	if err != nil {
+		zap.L().Error("customerSegmentFromPath: failed to fetch shop", zap.Int64("shop_id", shopID), zap.Error(err))
+		return 0, 0, "", fiber.NewError(fiber.StatusInternalServerError, "Failed to fetch shop")
+	}
+	return shopID, segmentID, shop.CurrencyCode, nil
+}
+
+// parseCustomerSegmentParams validates a segment body and returns its spend
+// thresholds as amounts in the shop currency
+func parseCustomerSegmentParams(c *fiber.Ctx, currencyCode string) (models.CustomerSegmentParams, pgtype.Numeric, pgtype.Numeric, error) {
+	var param models.CustomerSegmentParams
+	if err := c.BodyParser(&param); err != nil {
+		return param, pgtype.Numeric{}, pgtype.Numeric{}, fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	validator := &models.XValidator{}
+	if errs := validator.Validate(&param); len(errs) > 0 {
+		return param, pgtype.Numeric{}, pgtype.Numeric{}, &fiber.Error{
+			Code:    fiber.ErrBadRequest.Code,
+			Message: models.FormatValidationErrors(errs),
+		}
+	}
+	if param.MinOrderCount != nil && param.MaxOrderCount != nil && *param.MinOrderCount > *param.MaxOrderCount {
+		return param, pgtype.Numeric{}, pgtype.Numeric{}, fiber.NewError(fiber.StatusBadRequest, "min_order_count must not exceed max_order_count")
+	}
+
+	var thresholds [2]pgtype.Numeric
+	var amounts [2]money.Money
+	for i, amount := range []*money.Money{param.MinTotalSpent, param.MaxTotalSpent} {
+		if amount == nil {
+			continue
+		}
+		converted, err := amount.In(currencyCode)
+		if err != nil {
+			return param, pgtype.Numeric{}, pgtype.Numeric{}, fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
+		if converted.IsNegative() {
+			return param, pgtype.Numeric{}, pgtype.Numeric{}, fiber.NewError(fiber.StatusBadRequest, "Spend thresholds must not be negative")
+		}
+		amounts[i] = converted
+		thresholds[i] = converted.Numeric()
+	}
+	if param.MinTotalSpent != nil && param.MaxTotalSpent != nil && amounts[0].Amount > amounts[1].Amount {
+		return param, pgtype.Numeric{}, pgtype.Numeric{}, fiber.NewError(fiber.StatusBadRequest, "min_total_spent must not exceed max_total_spent")
+	}
+	return param, thresholds[0], thresholds[1], nil
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/petrejonn/naytife/internal/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCustomerSegmentParams(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantMin    string
+		wantMax    string
+	}{
+		{"name only", `{"name":"Everyone"}`, fiber.StatusOK, "", ""},
+		{"spend range", `{"name":"Big spenders","min_total_spent":"1000","max_total_spent":"5000.50"}`, fiber.StatusOK, "1000.00", "5000.50"},
+		{"numeric spend", `{"name":"Big spenders","min_total_spent":1000.5}`, fiber.StatusOK, "1000.50", ""},
+		{"missing name", `{"min_order_count":1}`, fiber.StatusBadRequest, "", ""},
+		{"negative order count", `{"name":"Lapsed","min_order_count":-1}`, fiber.StatusBadRequest, "", ""},
+		{"order count range reversed", `{"name":"Regulars","min_order_count":5,"max_order_count":2}`, fiber.StatusBadRequest, "", ""},
+		{"negative spend", `{"name":"Refunded","min_total_spent":"-1"}`, fiber.StatusBadRequest, "", ""},
+		{"spend too precise", `{"name":"Big spenders","min_total_spent":"10.005"}`, fiber.StatusBadRequest, "", ""},
+		{"spend range reversed", `{"name":"Big spenders","min_total_spent":"500","max_total_spent":"100"}`, fiber.StatusBadRequest, "", ""},
+		{"zero window", `{"name":"Recent","window_days":0,"min_order_count":1}`, fiber.StatusBadRequest, "", ""},
+		{"invalid json", `{"name":`, fiber.StatusBadRequest, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Post("/", func(c *fiber.Ctx) error {
+				_, minSpent, maxSpent, err := parseCustomerSegmentParams(c, "USD")
+				if err != nil {
+					return err
+				}
+				if tt.wantMin != "" {
+					got, err := money.FromNumeric(minSpent, "USD")
+					require.NoError(t, err)
+					assert.Equal(t, tt.wantMin, got.String())
+				} else {
+					assert.False(t, minSpent.Valid)
+				}
+				if tt.wantMax != "" {
+					got, err := money.FromNumeric(maxSpent, "USD")
+					require.NoError(t, err)
+					assert.Equal(t, tt.wantMax, got.String())
+				} else {
+					assert.False(t, maxSpent.Valid)
+				}
+				return c.SendStatus(fiber.StatusOK)
+			})
+
+			req := httptest.NewRequest(fiber.MethodPost, "/", strings.NewReader(tt.body))
+			req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+		})
+	}
+}
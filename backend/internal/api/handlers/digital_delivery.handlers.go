@@ -0,0 +1,720 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/petrejonn/naytife/internal/api"
+	"github.com/petrejonn/naytife/internal/api/models"
+	"github.com/petrejonn/naytife/internal/db"
+	dberrors "github.com/petrejonn/naytife/internal/db/errors"
+	"github.com/petrejonn/naytife/internal/services"
+	"go.uber.org/zap"
+)
+
+// GetDigitalAssets lists the digital assets of a variant
+// @Summary      List digital assets
+// @Description  Get the files and license key pools delivered with a variant of a digital product type once an order for it is paid
+// @Tags         digital delivery
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        variant_id path string true "Variant ID"
+// @Success      200  {object}   models.SuccessResponse{data=[]models.DigitalAsset} "Digital assets fetched successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      404  {object}   models.ErrorResponse "Variant not found"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/variants/{variant_id}/digital-assets [get]
+func (h *Handler) GetDigitalAssets(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	variantID, err := api.ParseIDParameter(c, "variant_id", "Variant")
+	if err != nil {
+		return err
+	}
+
+	if _, err := h.Repository.VariantIsDigital(c.Context(), db.VariantIsDigitalParams{
+		ProductVariationID: variantID,
+		ShopID:             shopID,
+	}); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return api.NotFoundErrorResponse(c, "Product variant")
+		}
+		return api.SystemErrorResponse(c, err, "Failed to fetch product variant")
+	}
+	assets, err := h.Repository.ListDigitalAssets(c.Context(), db.ListDigitalAssetsParams{
+		ProductVariationID: variantID,
+		ShopID:             shopID,
+	})
+	if err != nil {
+		zap.L().Error("GetDigitalAssets: failed to fetch digital assets", zap.Int64("variant_id", variantID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to fetch digital assets")
+	}
+
+	response := make([]models.DigitalAsset, len(assets))
+	for i, asset := range assets {
+		response[i] = models.NewDigitalAsset(db.DigitalAsset{
+			DigitalAssetID:     asset.DigitalAssetID,
+			ProductVariationID: asset.ProductVariationID,
+			Kind:               asset.Kind,
+			Title:              asset.Title,
+			StorageKey:         asset.StorageKey,
+			FileName:           asset.FileName,
+			ContentType:        asset.ContentType,
+			SizeBytes:          asset.SizeBytes,
+			DownloadLimit:      asset.DownloadLimit,
+			ExpiresAfterDays:   asset.ExpiresAfterDays,
+			CreatedAt:          asset.CreatedAt,
+			UpdatedAt:          asset.UpdatedAt,
+			ShopID:             asset.ShopID,
+		}, asset.AvailableKeys)
+	}
+	return api.SuccessResponse(c, fiber.StatusOK, response, "Digital assets fetched successfully")
+}
+
+// UploadDigitalAsset uploads a file delivered with a variant
+// @Summary      Upload a digital file
+// @Description  Upload a file that customers can download once an order for the variant is paid. Files are kept in private storage and only served through expiring signed links.
+// @Tags         digital delivery
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        variant_id path string true "Variant ID"
+// @Param        file formData file true "File"
+// @Param        title formData string false "Title shown to customers; the file name when empty"
+// @Param        download_limit formData int false "Downloads allowed per order line; unlimited when empty"
+// @Param        expires_after_days formData int false "Days the file can be downloaded after payment; forever when empty"
+// @Success      201  {object}   models.SuccessResponse{data=models.DigitalAsset} "Digital file uploaded successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      404  {object}   models.ErrorResponse "Variant not found"
+// @Failure      413  {object}   models.ErrorResponse "File too large"
+// @Failure      422  {object}   models.ErrorResponse "Variant is not digital"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/variants/{variant_id}/digital-assets/upload [post]
+func (h *Handler) UploadDigitalAsset(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	variantID, err := api.ParseIDParameter(c, "variant_id", "Variant")
+	if err != nil {
+		return err
+	}
+	if h.DigitalDelivery == nil {
+		return api.ErrorResponse(c, fiber.StatusServiceUnavailable, "Digital delivery is not configured", nil)
+	}
+
+	downloadLimit, err := optionalPositiveFormValue(c, "download_limit")
+	if err != nil {
+		return api.ErrorResponse(c, fiber.StatusBadRequest, err.Error(), nil)
+	}
+	expiresAfterDays, err := optionalPositiveFormValue(c, "expires_after_days")
+	if err != nil {
+		return api.ErrorResponse(c, fiber.StatusBadRequest, err.Error(), nil)
+	}
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return api.ErrorResponse(c, fiber.StatusBadRequest, "File is required", nil)
+	}
+	if fileHeader.Size > h.DigitalDelivery.MaxBytes {
+		return api.ErrorResponse(c, fiber.StatusRequestEntityTooLarge, services.ErrDigitalFileTooLarge.Error(), nil)
+	}
+	title := c.FormValue("title", fileHeader.Filename)
+	if title == "" || len(title) > 255 {
+		return api.ErrorResponse(c, fiber.StatusBadRequest, "Title must be between 1 and 255 characters", nil)
+	}
+	if err := h.checkDigitalVariant(c.Context(), shopID, variantID); err != nil {
+		return digitalVariantErrorResponse(c, err)
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return api.SystemErrorResponse(c, err, "Failed to read file")
+	}
+	defer file.Close()
+	contentType := fileHeader.Header.Get(fiber.HeaderContentType)
+	if contentType == "" {
+		contentType = fiber.MIMEOctetStream
+	}
+
+	key, err := h.DigitalDelivery.StoreFile(c.Context(), shopID, variantID, fileHeader.Filename, contentType, file, fileHeader.Size)
+	if err != nil {
+		if errors.Is(err, services.ErrDigitalFileTooLarge) {
+			return api.ErrorResponse(c, fiber.StatusRequestEntityTooLarge, err.Error(), nil)
+		}
+		return api.SystemErrorResponse(c, err, "Failed to store file")
+	}
+	size := fileHeader.Size
+	asset, err := h.Repository.CreateDigitalAsset(c.Context(), db.CreateDigitalAssetParams{
+		ProductVariationID: variantID,
+		Kind:               db.DigitalAssetKindFile,
+		Title:              title,
+		StorageKey:         &key,
+		FileName:           &fileHeader.Filename,
+		ContentType:        &contentType,
+		SizeBytes:          &size,
+		DownloadLimit:      downloadLimit,
+		ExpiresAfterDays:   expiresAfterDays,
+		ShopID:             shopID,
+	})
+	if err != nil {
+		if err := h.DigitalDelivery.Storage.Delete(context.Background(), key); err != nil {
+			zap.L().Warn("UploadDigitalAsset: failed to remove stored file", zap.String("key", key), zap.Error(err))
+		}
+		return api.SystemErrorResponse(c, err, "Failed to add digital file")
+	}
+	return api.SuccessResponse(c, fiber.StatusCreated, models.NewDigitalAsset(asset, 0), "Digital file uploaded successfully")
+}
+
+// CreateLicenseKeyAsset adds a pool of license keys to a variant
+// @Summary      Add a license key pool
+// @Description  Add a pool of license keys to a variant. Every unit of the variant sold takes a key from the pool once the order is paid; when the pool runs out, the keys are allocated as soon as more are added.
+// @Tags         digital delivery
+// @Accept       json
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        variant_id path string true "Variant ID"
+// @Param        asset body models.LicenseKeyAssetParams true "License key pool"
+// @Success      201  {object}   models.SuccessResponse{data=models.DigitalAsset} "License key pool created successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      404  {object}   models.ErrorResponse "Variant not found"
+// @Failure      422  {object}   models.ErrorResponse "Variant is not digital"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/variants/{variant_id}/digital-assets/license-keys [post]
+func (h *Handler) CreateLicenseKeyAsset(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	variantID, err := api.ParseIDParameter(c, "variant_id", "Variant")
+	if err != nil {
+		return err
+	}
+
+	var param models.LicenseKeyAssetParams
+	if err := c.BodyParser(&param); err != nil {
+		return api.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body", nil)
+	}
+	validator := &models.XValidator{}
+	if errs := validator.Validate(&param); len(errs) > 0 {
+		errMsgs := models.FormatValidationErrors(errs)
+		return api.ErrorResponse(c, fiber.StatusBadRequest, errMsgs, nil)
+	}
+	if err := h.checkDigitalVariant(c.Context(), shopID, variantID); err != nil {
+		return digitalVariantErrorResponse(c, err)
+	}
+
+	var asset db.DigitalAsset
+	var available int64
+	err = h.Repository.WithTx(c.Context(), func(q *db.Queries) error {
+		var err error
+		if asset, err = q.CreateDigitalAsset(c.Context(), db.CreateDigitalAssetParams{
+			ProductVariationID: variantID,
+			Kind:               db.DigitalAssetKindLicenseKey,
+			Title:              param.Title,
+			ShopID:             shopID,
+		}); err != nil {
+			return err
+		}
+		if len(param.LicenseKeys) > 0 {
+			if _, err := q.CreateLicenseKeys(c.Context(), db.CreateLicenseKeysParams{
+				DigitalAssetID: asset.DigitalAssetID,
+				ShopID:         shopID,
+				LicenseKeys:    param.LicenseKeys,
+			}); err != nil {
+				return err
+			}
+		}
+		available, err = q.CountAvailableLicenseKeys(c.Context(), db.CountAvailableLicenseKeysParams{
+			DigitalAssetID: asset.DigitalAssetID,
+			ShopID:         shopID,
+		})
+		return err
+	})
+	if err != nil {
+		return api.SystemErrorResponse(c, err, "Failed to create license key pool")
+	}
+	return api.SuccessResponse(c, fiber.StatusCreated, models.NewDigitalAsset(asset, available), "License key pool created successfully")
+}
+
+// AddLicenseKeys adds keys to a license key pool
+// @Summary      Add license keys
+// @Description  Add keys to a license key pool. Keys already in the pool are skipped. Orders waiting for keys get them first, oldest first, and are emailed again.
+// @Tags         digital delivery
+// @Accept       json
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        asset_id path string true "Digital asset ID"
+// @Param        keys body models.LicenseKeysParams true "License keys"
+// @Success      200  {object}   models.SuccessResponse{data=models.DigitalAsset} "License keys added successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      404  {object}   models.ErrorResponse "Digital asset not found"
+// @Failure      422  {object}   models.ErrorResponse "Asset is not a license key pool"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/digital-assets/{asset_id}/license-keys [post]
+func (h *Handler) AddLicenseKeys(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	assetID, err := api.ParseIDParameter(c, "asset_id", "Digital asset")
+	if err != nil {
+		return err
+	}
+
+	var param models.LicenseKeysParams
+	if err := c.BodyParser(&param); err != nil {
+		return api.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body", nil)
+	}
+	validator := &models.XValidator{}
+	if errs := validator.Validate(&param); len(errs) > 0 {
+		errMsgs := models.FormatValidationErrors(errs)
+		return api.ErrorResponse(c, fiber.StatusBadRequest, errMsgs, nil)
+	}
+
+	var asset db.DigitalAsset
+	var available int64
+	errNotLicenseKeys := errors.New("not a license key pool")
+	err = h.Repository.WithTx(c.Context(), func(q *db.Queries) error {
+		var err error
+		if asset, err = q.GetDigitalAsset(c.Context(), db.GetDigitalAssetParams{
+			DigitalAssetID: assetID,
+			ShopID:         shopID,
+		}); err != nil {
+			return err
+		}
+		if asset.Kind != db.DigitalAssetKindLicenseKey {
+			return errNotLicenseKeys
+		}
+		if _, err := q.CreateLicenseKeys(c.Context(), db.CreateLicenseKeysParams{
+			DigitalAssetID: assetID,
+			ShopID:         shopID,
+			LicenseKeys:    param.LicenseKeys,
+		}); err != nil {
+			return err
+		}
+		if _, err := services.AllocatePendingLicenseKeys(c.Context(), q, h.DigitalDelivery, shopID, assetID); err != nil {
+			return err
+		}
+		available, err = q.CountAvailableLicenseKeys(c.Context(), db.CountAvailableLicenseKeysParams{
+			DigitalAssetID: assetID,
+			ShopID:         shopID,
+		})
+		return err
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return api.NotFoundErrorResponse(c, "Digital asset")
+		case errors.Is(err, errNotLicenseKeys):
+			return api.BusinessLogicErrorResponse(c, "License keys can only be added to a license key pool")
+		}
+		zap.L().Error("AddLicenseKeys: failed to add license keys", zap.Int64("asset_id", assetID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to add license keys")
+	}
+	return api.SuccessResponse(c, fiber.StatusOK, models.NewDigitalAsset(asset, available), "License keys added successfully")
+}
+
+// UpdateDigitalAsset updates a digital asset
+// @Summary      Update a digital asset
+// @Description  Update the title, download limit or expiry of a digital asset. Orders already delivered keep the limits they were delivered with.
+// @Tags         digital delivery
+// @Accept       json
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        asset_id path string true "Digital asset ID"
+// @Param        asset body models.DigitalAssetUpdateParams true "Digital asset"
+// @Success      200  {object}   models.SuccessResponse{data=models.DigitalAsset} "Digital asset updated successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      404  {object}   models.ErrorResponse "Digital asset not found"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/digital-assets/{asset_id} [patch]
+func (h *Handler) UpdateDigitalAsset(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	assetID, err := api.ParseIDParameter(c, "asset_id", "Digital asset")
+	if err != nil {
+		return err
+	}
+
+	var param models.DigitalAssetUpdateParams
+	if err := c.BodyParser(&param); err != nil {
+		return api.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body", nil)
+	}
+	validator := &models.XValidator{}
+	if errs := validator.Validate(&param); len(errs) > 0 {
+		errMsgs := models.FormatValidationErrors(errs)
+		return api.ErrorResponse(c, fiber.StatusBadRequest, errMsgs, nil)
+	}
+
+	asset, err := h.Repository.UpdateDigitalAsset(c.Context(), db.UpdateDigitalAssetParams{
+		Title:            param.Title,
+		DownloadLimit:    param.DownloadLimit,
+		ExpiresAfterDays: param.ExpiresAfterDays,
+		DigitalAssetID:   assetID,
+		ShopID:           shopID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return api.NotFoundErrorResponse(c, "Digital asset")
+		}
+		return api.SystemErrorResponse(c, err, "Failed to update digital asset")
+	}
+	available, err := h.Repository.CountAvailableLicenseKeys(c.Context(), db.CountAvailableLicenseKeysParams{
+		DigitalAssetID: assetID,
+		ShopID:         shopID,
+	})
+	if err != nil {
+		return api.SystemErrorResponse(c, err, "Failed to count license keys")
+	}
+	return api.SuccessResponse(c, fiber.StatusOK, models.NewDigitalAsset(asset, available), "Digital asset updated successfully")
+}
+
+// DeleteDigitalAsset deletes a digital asset
+// @Summary      Delete a digital asset
+// @Description  Delete a file or license key pool that no order has been delivered yet
+// @Tags         digital delivery
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        asset_id path string true "Digital asset ID"
+// @Success      200  {object}   models.SuccessResponse "Digital asset deleted successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      404  {object}   models.ErrorResponse "Digital asset not found"
+// @Failure      422  {object}   models.ErrorResponse "Digital asset was delivered"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/digital-assets/{asset_id} [delete]
+func (h *Handler) DeleteDigitalAsset(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	assetID, err := api.ParseIDParameter(c, "asset_id", "Digital asset")
+	if err != nil {
+		return err
+	}
+
+	asset, err := h.Repository.DeleteDigitalAsset(c.Context(), db.DeleteDigitalAssetParams{
+		DigitalAssetID: assetID,
+		ShopID:         shopID,
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return api.NotFoundErrorResponse(c, "Digital asset")
+		case errors.As(err, &pgErr) && pgErr.Code == dberrors.ForeignKeyViolation:
+			return api.BusinessLogicErrorResponse(c, "The digital asset was delivered to orders and cannot be deleted")
+		}
+		return api.SystemErrorResponse(c, err, "Failed to delete digital asset")
+	}
+	if asset.StorageKey != nil && h.DigitalDelivery != nil {
+		if err := h.DigitalDelivery.Storage.Delete(c.Context(), *asset.StorageKey); err != nil {
+			zap.L().Warn("DeleteDigitalAsset: failed to remove stored file", zap.String("key", *asset.StorageKey), zap.Error(err))
+		}
+	}
+	return api.SuccessResponse(c, fiber.StatusOK, nil, "Digital asset deleted successfully")
+}
+
+// GetOrderDownloads lists the downloads and license keys an order delivered
+// @Summary      List order downloads
+// @Description  Get the files and license keys a paid order delivered, with their download counts and signed links
+// @Tags         digital delivery
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        order_id path string true "Order ID"
+// @Success      200  {object}   models.SuccessResponse{data=[]models.OrderDownload} "Order downloads fetched successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/orders/{order_id}/downloads [get]
+func (h *Handler) GetOrderDownloads(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	orderID, err := api.ParseIDParameter(c, "order_id", "Order")
+	if err != nil {
+		return err
+	}
+
+	downloads, err := h.Repository.ListOrderDigitalDownloads(c.Context(), db.ListOrderDigitalDownloadsParams{
+		OrderID: orderID,
+		ShopID:  shopID,
+	})
+	if err != nil {
+		zap.L().Error("GetOrderDownloads: failed to fetch downloads", zap.Int64("order_id", orderID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to fetch order downloads")
+	}
+	return api.SuccessResponse(c, fiber.StatusOK, h.orderDownloads(downloads), "Order downloads fetched successfully")
+}
+
+// DeliverOrderDownloads delivers the digital goods of a paid order
+// @Summary      Deliver order downloads
+// @Description  Deliver the files and license keys of a paid order and email them to the customer. Orders are delivered automatically when their payment is confirmed; this is for orders marked paid by hand. Orders already delivered are left alone.
+// @Tags         digital delivery
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        order_id path string true "Order ID"
+// @Success      200  {object}   models.SuccessResponse{data=[]models.OrderDownload} "Order downloads delivered successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      404  {object}   models.ErrorResponse "Order not found"
+// @Failure      422  {object}   models.ErrorResponse "Order is not paid"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/orders/{order_id}/downloads [post]
+func (h *Handler) DeliverOrderDownloads(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	orderID, err := api.ParseIDParameter(c, "order_id", "Order")
+	if err != nil {
+		return err
+	}
+
+	var downloads []db.ListOrderDigitalDownloadsRow
+	errNotPaid := errors.New("order is not paid")
+	err = h.Repository.WithTx(c.Context(), func(q *db.Queries) error {
+		order, err := q.GetOrder(c.Context(), db.GetOrderParams{OrderID: orderID, ShopID: shopID})
+		if err != nil {
+			return err
+		}
+		if order.PaymentStatus != db.PaymentStatusTypePaid {
+			return errNotPaid
+		}
+		if _, err := services.DeliverOrderDigitalGoods(c.Context(), q, h.DigitalDelivery, order); err != nil {
+			return err
+		}
+		downloads, err = q.ListOrderDigitalDownloads(c.Context(), db.ListOrderDigitalDownloadsParams{
+			OrderID: orderID,
+			ShopID:  shopID,
+		})
+		return err
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return api.NotFoundErrorResponse(c, "Order")
+		case errors.Is(err, errNotPaid):
+			return api.BusinessLogicErrorResponse(c, "Only paid orders can be delivered")
+		}
+		zap.L().Error("DeliverOrderDownloads: failed to deliver order", zap.Int64("order_id", orderID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to deliver order downloads")
+	}
+	return api.SuccessResponse(c, fiber.StatusOK, h.orderDownloads(downloads), "Order downloads delivered successfully")
+}
+
+// GetDownloadEvents lists the times a download was fetched
+// @Summary      List download activity
+// @Description  Get the log of a download's file being fetched through its signed link, newest first
+// @Tags         digital delivery
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        download_id path string true "Download ID"
+// @Param        limit query int false "Limit" default(20)
+// @Param        offset query int false "Offset" default(0)
+// @Success      200  {object}   models.SuccessResponse{data=[]models.DownloadEvent} "Download activity fetched successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/downloads/{download_id}/events [get]
+func (h *Handler) GetDownloadEvents(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	downloadID, err := api.ParseIDParameter(c, "download_id", "Download")
+	if err != nil {
+		return err
+	}
+	limit, offset, err := api.ParsePaginationParams(c)
+	if err != nil {
+		return err
+	}
+
+	events, err := h.Repository.ListDigitalDownloadEvents(c.Context(), db.ListDigitalDownloadEventsParams{
+		DownloadID: downloadID,
+		ShopID:     shopID,
+		Limit:      int32(limit),
+		Offset:     int32(offset),
+	})
+	if err != nil {
+		return api.SystemErrorResponse(c, err, "Failed to fetch download activity")
+	}
+	total, err := h.Repository.CountDigitalDownloadEvents(c.Context(), db.CountDigitalDownloadEventsParams{
+		DownloadID: downloadID,
+		ShopID:     shopID,
+	})
+	if err != nil {
+		return api.SystemErrorResponse(c, err, "Failed to count download activity")
+	}
+
+	response := make([]models.DownloadEvent, len(events))
+	for i, event := range events {
+		response[i] = models.NewDownloadEvent(event)
+	}
+	page := (offset / limit) + 1
+	return api.PaginatedSuccessResponse(c, fiber.StatusOK, response, total, page, limit, "Download activity fetched successfully")
+}
+
+// DownloadDigitalFile serves the file of a download through its signed link
+// @Summary      Download a digital file
+// @Description  Serve the file of an order download. The link is signed and expires; every download is counted against the download limit and logged.
+// @Tags         digital delivery
+// @Produce      octet-stream
+// @Param        shop_id path string true "Shop ID"
+// @Param        download_id path string true "Download ID"
+// @Param        expires query int true "Link expiry as a Unix timestamp"
+// @Param        signature query string true "Link signature"
+// @Success      200  {file}     file "File"
+// @Failure      403  {object}   models.ErrorResponse "Invalid or expired link"
+// @Failure      404  {object}   models.ErrorResponse "Download not found"
+// @Failure      410  {object}   models.ErrorResponse "Download expired or reached its limit"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Router       /downloads/{shop_id}/{download_id} [get]
+func (h *Handler) DownloadDigitalFile(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	downloadID, err := api.ParseIDParameter(c, "download_id", "Download")
+	if err != nil {
+		return err
+	}
+	expires, err := strconv.ParseInt(c.Query("expires"), 10, 64)
+	if err != nil || h.DigitalDelivery.VerifyDownloadLink(shopID, downloadID, expires, c.Query("signature")) != nil {
+		return api.ErrorResponse(c, fiber.StatusForbidden, services.ErrInvalidDownloadLink.Error(), nil)
+	}
+
+	download, err := h.Repository.GetDigitalDownloadFile(c.Context(), db.GetDigitalDownloadFileParams{
+		DownloadID: downloadID,
+		ShopID:     shopID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return api.NotFoundErrorResponse(c, "Download")
+		}
+		return api.SystemErrorResponse(c, err, "Failed to fetch download")
+	}
+	if download.Kind != db.DigitalAssetKindFile || download.StorageKey == nil {
+		return api.NotFoundErrorResponse(c, "Download")
+	}
+	if !services.DownloadAvailable(download.DownloadCount, download.DownloadLimit, download.ExpiresAt) {
+		return api.ErrorResponse(c, fiber.StatusGone, services.ErrDownloadUnavailable.Error(), nil)
+	}
+
+	body, err := h.DigitalDelivery.Storage.Get(c.Context(), *download.StorageKey)
+	if err != nil {
+		zap.L().Error("DownloadDigitalFile: failed to open file", zap.Int64("download_id", downloadID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to open file")
+	}
+	ip, userAgent := c.IP(), c.Get(fiber.HeaderUserAgent)
+	err = h.Repository.WithTx(c.Context(), func(q *db.Queries) error {
+		if _, err := q.RecordDigitalDownload(c.Context(), db.RecordDigitalDownloadParams{
+			DownloadID: downloadID,
+			ShopID:     shopID,
+		}); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return services.ErrDownloadUnavailable
+			}
+			return err
+		}
+		_, err := q.CreateDigitalDownloadEvent(c.Context(), db.CreateDigitalDownloadEventParams{
+			DownloadID: downloadID,
+			IpAddress:  &ip,
+			UserAgent:  &userAgent,
+			ShopID:     shopID,
+		})
+		return err
+	})
+	if err != nil {
+		body.Close()
+		if errors.Is(err, services.ErrDownloadUnavailable) {
+			return api.ErrorResponse(c, fiber.StatusGone, err.Error(), nil)
+		}
+		return api.SystemErrorResponse(c, err, "Failed to record download")
+	}
+
+	fileName := fmt.Sprintf("download-%d", downloadID)
+	if download.FileName != nil {
+		fileName = *download.FileName
+	}
+	c.Attachment(fileName)
+	if download.ContentType != nil {
+		c.Set(fiber.HeaderContentType, *download.ContentType)
+	}
+	c.Set(fiber.HeaderCacheControl, "private, no-store")
+	return c.SendStream(body)
+}
+
+// errVariantNotDigital is returned when digital assets are added to a variant of a
+// physical product type
+var errVariantNotDigital = errors.New("variant is not digital")
+
+// checkDigitalVariant checks that a variant exists and is of a digital product type
+func (h *Handler) checkDigitalVariant(ctx context.Context, shopID, variantID int64) error {
+	digital, err := h.Repository.VariantIsDigital(ctx, db.VariantIsDigitalParams{
+		ProductVariationID: variantID,
+		ShopID:             shopID,
+	})
+	if err != nil {
+		return err
+	}
+	if !digital {
+		return errVariantNotDigital
+	}
+	return nil
+}
+
+// digitalVariantErrorResponse responds with the error checkDigitalVariant returned
+func digitalVariantErrorResponse(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return api.NotFoundErrorResponse(c, "Product variant")
+	case errors.Is(err, errVariantNotDigital):
+		return api.BusinessLogicErrorResponse(c, "Digital assets can only be added to variants of a digital product type")
+	}
+	return api.SystemErrorResponse(c, err, "Failed to fetch product variant")
+}
+
+// orderDownloads converts the downloads of an order to their API representation,
+// signing the links of those that can still be downloaded
+func (h *Handler) orderDownloads(downloads []db.ListOrderDigitalDownloadsRow) []models.OrderDownload {
+	response := make([]models.OrderDownload, len(downloads))
+	for i, download := range downloads {
+		var url *string
+		if h.DigitalDelivery != nil {
+			url = h.DigitalDelivery.OrderDownloadURL(download)
+		}
+		response[i] = models.NewOrderDownload(download, url)
+	}
+	return response
+}
+
+// optionalPositiveFormValue parses an optional positive integer form field
+func optionalPositiveFormValue(c *fiber.Ctx, key string) (*int32, error) {
+	value := c.FormValue(key)
+	if value == "" {
+		return nil, nil
+	}
+	n, err := strconv.ParseInt(value, 10, 32)
+	if err != nil || n < 1 {
+		return nil, fmt.Errorf("%s must be a positive number", key)
+	}
+	v := int32(n)
+	return &v, nil
+}
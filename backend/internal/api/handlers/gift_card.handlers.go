@@ -0,0 +1,359 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/petrejonn/naytife/internal/api"
+	"github.com/petrejonn/naytife/internal/api/models"
+	"github.com/petrejonn/naytife/internal/db"
+	dberrors "github.com/petrejonn/naytife/internal/db/errors"
+	"github.com/petrejonn/naytife/internal/services"
+	"go.uber.org/zap"
+)
+
+// GetGiftCards lists the gift cards of a shop
+// @Summary      List gift cards
+// @Description  Get the gift cards of a shop, newest first
+// @Tags         gift-cards
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        code query string false "Code prefix"
+// @Param        limit query int false "Limit" default(20)
+// @Param        offset query int false "Offset" default(0)
+// @Success      200  {object}   models.SuccessResponse{data=[]models.GiftCard} "Gift cards fetched successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/gift-cards [get]
+func (h *Handler) GetGiftCards(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	limit, offset, err := api.ParsePaginationParams(c)
+	if err != nil {
+		return err
+	}
+	var code *string
+	if prefix := services.NormalizeGiftCardCode(c.Query("code")); prefix != "" {
+		code = &prefix
+	}
+
+	cards, err := h.Repository.ListGiftCards(c.Context(), db.ListGiftCardsParams{
+		ShopID: shopID,
+		Code:   code,
+		Limit:  int32(limit),
+		Offset: int32(offset),
+	})
+	if err != nil {
+		zap.L().Error("GetGiftCards: failed to fetch gift cards", zap.Int64("shop_id", shopID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to fetch gift cards")
+	}
+	total, err := h.Repository.CountGiftCards(c.Context(), db.CountGiftCardsParams{ShopID: shopID, Code: code})
+	if err != nil {
+		zap.L().Error("GetGiftCards: failed to count gift cards", zap.Int64("shop_id", shopID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to count gift cards")
+	}
+
+	response := make([]models.GiftCard, len(cards))
+	for i, card := range cards {
+		if response[i], err = models.NewGiftCard(card); err != nil {
+			return api.SystemErrorResponse(c, err, "Failed to read gift card balance")
+		}
+	}
+	page := (offset / limit) + 1
+	return api.PaginatedSuccessResponse(c, fiber.StatusOK, response, total, page, limit, "Gift cards fetched successfully")
+}
+
+// GetGiftCard fetches a gift card
+// @Summary      Get a gift card
+// @Description  Get a gift card with its balance
+// @Tags         gift-cards
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        gift_card_id path string true "Gift card ID"
+// @Success      200  {object}   models.SuccessResponse{data=models.GiftCard} "Gift card fetched successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      404  {object}   models.ErrorResponse "Gift card not found"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/gift-cards/{gift_card_id} [get]
+func (h *Handler) GetGiftCard(c *fiber.Ctx) error {
+	card, err := h.giftCardFromPath(c)
+	if err != nil {
+		return err
+	}
+	response, err := models.NewGiftCard(card)
+	if err != nil {
+		return api.SystemErrorResponse(c, err, "Failed to read gift card balance")
+	}
+	return api.SuccessResponse(c, fiber.StatusOK, response, "Gift card fetched successfully")
+}
+
+// CreateGiftCard issues a gift card
+// @Summary      Issue a gift card
+// @Description  Issue a gift card in the shop currency, e.g. as a goodwill gesture or a giveaway. The code is generated unless given; a recipient email gets the code by email.
+// @Tags         gift-cards
+// @Accept       json
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        gift_card body models.GiftCardCreateParams true "Gift card"
+// @Success      201  {object}   models.SuccessResponse{data=models.GiftCard} "Gift card issued successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      409  {object}   models.ErrorResponse "Gift card code already exists"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/gift-cards [post]
+func (h *Handler) CreateGiftCard(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	var param models.GiftCardCreateParams
+	if err := c.BodyParser(&param); err != nil {
+		return api.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body", nil)
+	}
+	validator := &models.XValidator{}
+	if errs := validator.Validate(&param); len(errs) > 0 {
+		return api.ErrorResponse(c, fiber.StatusBadRequest, models.FormatValidationErrors(errs), nil)
+	}
+
+	shop, err := h.Repository.GetShop(c.Context(), shopID)
+	if err != nil {
+		zap.L().Error("CreateGiftCard: failed to fetch shop", zap.Int64("shop_id", shopID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to fetch shop")
+	}
+	amount, err := param.Amount.In(shop.CurrencyCode)
+	if err != nil {
+		return api.ErrorResponse(c, fiber.StatusBadRequest, err.Error(), nil)
+	}
+	if amount.IsZero() || amount.IsNegative() {
+		return api.ErrorResponse(c, fiber.StatusBadRequest, "Amount must be greater than zero", nil)
+	}
+
+	arg := db.CreateGiftCardParams{
+		Amount:         amount.Numeric(),
+		CurrencyCode:   shop.CurrencyCode,
+		Note:           param.Note,
+		RecipientEmail: param.RecipientEmail,
+		CreatedBy:      requestUser(c),
+		ShopID:         shopID,
+	}
+	if param.Code != nil {
+		arg.Code = *param.Code
+	}
+	if param.ExpiresAt != nil {
+		arg.ExpiresAt = pgtype.Timestamptz{Time: *param.ExpiresAt, Valid: true}
+	}
+	if param.CustomerID != nil {
+		arg.ShopCustomerID = pgtype.UUID{Bytes: *param.CustomerID, Valid: true}
+	}
+
+	var card db.GiftCard
+	err = h.Repository.WithTx(c.Context(), func(q *db.Queries) error {
+		var err error
+		card, err = services.IssueGiftCard(c.Context(), q, arg)
+		return err
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			switch pgErr.Code {
+			case dberrors.UniqueViolation:
+				return api.ErrorResponse(c, fiber.StatusConflict, "Gift card code already exists", nil)
+			case dberrors.ForeignKeyViolation:
+				return api.NotFoundErrorResponse(c, "Customer")
+			}
+		}
+		zap.L().Error("CreateGiftCard: failed to issue gift card", zap.Int64("shop_id", shopID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to issue gift card")
+	}
+
+	response, err := models.NewGiftCard(card)
+	if err != nil {
+		return api.SystemErrorResponse(c, err, "Failed to read gift card balance")
+	}
+	return api.SuccessResponse(c, fiber.StatusCreated, response, "Gift card issued successfully")
+}
+
+// UpdateGiftCard updates a gift card
+// @Summary      Update a gift card
+// @Description  Change the expiry, note or recipient of a gift card, or disable it so it can no longer be redeemed
+// @Tags         gift-cards
+// @Accept       json
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        gift_card_id path string true "Gift card ID"
+// @Param        gift_card body models.GiftCardUpdateParams true "Gift card fields to change"
+// @Success      200  {object}   models.SuccessResponse{data=models.GiftCard} "Gift card updated successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      404  {object}   models.ErrorResponse "Gift card not found"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/gift-cards/{gift_card_id} [patch]
+func (h *Handler) UpdateGiftCard(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	giftCardID, err := api.ParseIDParameter(c, "gift_card_id", "Gift card")
+	if err != nil {
+		return err
+	}
+	var param models.GiftCardUpdateParams
+	if err := c.BodyParser(&param); err != nil {
+		return api.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body", nil)
+	}
+	validator := &models.XValidator{}
+	if errs := validator.Validate(&param); len(errs) > 0 {
+		return api.ErrorResponse(c, fiber.StatusBadRequest, models.FormatValidationErrors(errs), nil)
+	}
+
+	arg := db.UpdateGiftCardParams{
+		Disabled:       param.Disabled,
+		Note:           param.Note,
+		RecipientEmail: param.RecipientEmail,
+		GiftCardID:     giftCardID,
+		ShopID:         shopID,
+	}
+	if param.ExpiresAt != nil {
+		arg.ExpiresAt = pgtype.Timestamptz{Time: *param.ExpiresAt, Valid: true}
+	}
+	card, err := h.Repository.UpdateGiftCard(c.Context(), arg)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return api.NotFoundErrorResponse(c, "Gift card")
+		}
+		zap.L().Error("UpdateGiftCard: failed to update gift card", zap.Int64("gift_card_id", giftCardID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to update gift card")
+	}
+
+	response, err := models.NewGiftCard(card)
+	if err != nil {
+		return api.SystemErrorResponse(c, err, "Failed to read gift card balance")
+	}
+	return api.SuccessResponse(c, fiber.StatusOK, response, "Gift card updated successfully")
+}
+
+// AdjustGiftCard changes the balance of a gift card
+// @Summary      Adjust a gift card balance
+// @Description  Add to or take from the balance of a gift card. The change is recorded in the card's ledger with the note and the staff member who made it.
+// @Tags         gift-cards
+// @Accept       json
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        gift_card_id path string true "Gift card ID"
+// @Param        adjustment body models.GiftCardAdjustParams true "Balance adjustment"
+// @Success      200  {object}   models.SuccessResponse{data=models.GiftCard} "Gift card balance adjusted successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      404  {object}   models.ErrorResponse "Gift card not found"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/gift-cards/{gift_card_id}/adjustments [post]
+func (h *Handler) AdjustGiftCard(c *fiber.Ctx) error {
+	card, err := h.giftCardFromPath(c)
+	if err != nil {
+		return err
+	}
+	var param models.GiftCardAdjustParams
+	if err := c.BodyParser(&param); err != nil {
+		return api.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body", nil)
+	}
+	validator := &models.XValidator{}
+	if errs := validator.Validate(&param); len(errs) > 0 {
+		return api.ErrorResponse(c, fiber.StatusBadRequest, models.FormatValidationErrors(errs), nil)
+	}
+	amount, err := param.Amount.In(card.CurrencyCode)
+	if err != nil {
+		return api.ErrorResponse(c, fiber.StatusBadRequest, err.Error(), nil)
+	}
+	if amount.IsZero() {
+		return api.ErrorResponse(c, fiber.StatusBadRequest, "Amount must not be zero", nil)
+	}
+
+	err = h.Repository.WithTx(c.Context(), func(q *db.Queries) error {
+		var err error
+		card, err = services.AdjustGiftCard(c.Context(), q, card.ShopID, card.GiftCardID, db.CreditTransactionKindAdjust, amount, nil, param.Note, requestUser(c))
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, services.ErrInsufficientGiftCard) {
+			return api.BusinessLogicErrorResponse(c, err.Error())
+		}
+		zap.L().Error("AdjustGiftCard: failed to adjust gift card", zap.Int64("gift_card_id", card.GiftCardID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to adjust gift card")
+	}
+
+	response, err := models.NewGiftCard(card)
+	if err != nil {
+		return api.SystemErrorResponse(c, err, "Failed to read gift card balance")
+	}
+	return api.SuccessResponse(c, fiber.StatusOK, response, "Gift card balance adjusted successfully")
+}
+
+// GetGiftCardTransactions lists the ledger of a gift card
+// @Summary      List gift card transactions
+// @Description  Get the ledger of a gift card, oldest first: its issue, redemptions, refunds and adjustments
+// @Tags         gift-cards
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        gift_card_id path string true "Gift card ID"
+// @Success      200  {object}   models.SuccessResponse{data=[]models.CreditTransaction} "Gift card transactions fetched successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      404  {object}   models.ErrorResponse "Gift card not found"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/gift-cards/{gift_card_id}/transactions [get]
+func (h *Handler) GetGiftCardTransactions(c *fiber.Ctx) error {
+	card, err := h.giftCardFromPath(c)
+	if err != nil {
+		return err
+	}
+
+	entries, err := h.Repository.ListGiftCardTransactions(c.Context(), db.ListGiftCardTransactionsParams{
+		GiftCardID: card.GiftCardID,
+		ShopID:     card.ShopID,
+	})
+	if err != nil {
+		zap.L().Error("GetGiftCardTransactions: failed to fetch transactions", zap.Int64("gift_card_id", card.GiftCardID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to fetch gift card transactions")
+	}
+
+	response := make([]models.CreditTransaction, len(entries))
+	for i, entry := range entries {
+		if response[i], err = models.NewGiftCardTransaction(entry, card.CurrencyCode); err != nil {
+			return api.SystemErrorResponse(c, err, "Failed to read gift card transactions")
+		}
+	}
+	return api.SuccessResponse(c, fiber.StatusOK, response, "Gift card transactions fetched successfully")
+}
+
+// giftCardFromPath loads the gift card addressed by the shop_id and gift_card_id
+// path parameters
+func (h *Handler) giftCardFromPath(c *fiber.Ctx) (db.GiftCard, error) {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return db.GiftCard{}, err
+	}
+	giftCardID, err := api.ParseIDParameter(c, "gift_card_id", "Gift card")
+	if err != nil {
+		return db.GiftCard{}, err
+	}
+
+	card, err := h.Repository.GetGiftCard(c.Context(), db.GetGiftCardParams{
+		GiftCardID: giftCardID,
+		ShopID:     shopID,
+	})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return db.GiftCard{}, fiber.NewError(fiber.StatusNotFound, "Gift card not found")
+		}
+		zap.L().Error("giftCardFromPath: failed to fetch gift card", zap.Int64("gift_card_id", giftCardID), zap.Error(err))
+		return db.GiftCard{}, fiber.NewError(fiber.StatusInternalServerError, "Failed to fetch gift card")
+	}
+	return card, nil
+}
@@ -12,6 +12,9 @@ type Handler struct {
 	PaymentProcessorFactory *services.PaymentProcessorFactory
 	RetryClient             *retryablehttp.Client
 	StoreDeployerClient     *services.StoreDeployerClient
+	ImageUploadService      *services.ImageUploadService
+	CurrencyService         *services.CurrencyService
+	DigitalDelivery         *services.DigitalDelivery
 }
 
 func NewHandler(repo db.Repository, retryClient *retryablehttp.Client) *Handler {
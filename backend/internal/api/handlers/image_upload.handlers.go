@@ -0,0 +1,354 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
+	"github.com/petrejonn/naytife/internal/api"
+	"github.com/petrejonn/naytife/internal/api/models"
+	"github.com/petrejonn/naytife/internal/db"
+	"github.com/petrejonn/naytife/internal/observability"
+	"github.com/petrejonn/naytife/internal/services"
+	"go.uber.org/zap"
+)
+
+// UploadProductImage uploads an image file for a product
+// @Summary Upload a product image
+// @Description Upload an image file (JPEG, PNG or WebP). The original is stored with its metadata stripped and responsive WebP/AVIF renditions are generated.
+// @Tags Product Images
+// @Accept multipart/form-data
+// @Produce json
+// @Param shop_id path string true "Shop ID"
+// @Param product_id path string true "Product ID"
+// @Param file formData file true "Image file"
+// @Param alt formData string true "Alt text"
+// @Success 201 {object} models.SuccessResponse{data=models.ImageUploadResponse} "Image uploaded successfully"
+// @Failure 400 {object} models.ErrorResponse "Bad request"
+// @Failure 404 {object} models.ErrorResponse "Product not found"
+// @Failure 413 {object} models.ErrorResponse "Image too large"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Security OAuth2AccessCode
+// @Router /shops/{shop_id}/products/{product_id}/images/upload [post]
+func (h *Handler) UploadProductImage(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	productID, err := api.ParseIDParameter(c, "product_id", "Product")
+	if err != nil {
+		return err
+	}
+
+	alt := c.FormValue("alt")
+	if alt == "" {
+		return api.ErrorResponse(c, fiber.StatusBadRequest, "Alt text is required", nil)
+	}
+
+	if _, err := h.Repository.GetProductById(c.Context(), db.GetProductByIdParams{
+		ProductID: productID,
+		ShopID:    shopID,
+	}); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return api.NotFoundErrorResponse(c, "Product")
+		}
+		return api.SystemErrorResponse(c, err, "Failed to verify product")
+	}
+
+	uploaded, err := h.uploadImage(c, fmt.Sprintf("products/shop_%d/%d", shopID, productID))
+	if err != nil {
+		return imageUploadErrorResponse(c, err)
+	}
+
+	var createdImage db.ProductImage
+	err = h.Repository.WithTx(c.Context(), func(q *db.Queries) error {
+		if err := createImageAsset(c.Context(), q, shopID, uploaded); err != nil {
+			return err
+		}
+		createdImage, err = q.CreateProductImage(c.Context(), db.CreateProductImageParams{
+			Url:       uploaded.URL,
+			Alt:       alt,
+			ProductID: productID,
+			ShopID:    shopID,
+		})
+		return err
+	})
+	if err != nil {
+		h.ImageUploadService.Remove(context.Background(), uploaded)
+		return api.SystemErrorResponse(c, err, "Failed to add product image")
+	}
+
+	h.autoPublishImages(shopID, "products")
+
+	response := imageUploadResponse(uploaded)
+	response.ID = createdImage.ProductImageID
+	response.Alt = createdImage.Alt
+	return api.SuccessResponse(c, fiber.StatusCreated, response, "Image uploaded successfully")
+}
+
+// UploadCategoryImage uploads the banner image for a category
+// @Summary Upload a category banner
+// @Description Upload an image file (JPEG, PNG or WebP) and set it as the category banner
+// @Tags categories
+// @Accept multipart/form-data
+// @Produce json
+// @Param shop_id path string true "Shop ID"
+// @Param category_id path string true "Category ID"
+// @Param file formData file true "Image file"
+// @Success 201 {object} models.SuccessResponse{data=models.ImageUploadResponse} "Image uploaded successfully"
+// @Failure 400 {object} models.ErrorResponse "Bad request"
+// @Failure 404 {object} models.ErrorResponse "Category not found"
+// @Failure 413 {object} models.ErrorResponse "Image too large"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Security OAuth2AccessCode
+// @Router /shops/{shop_id}/categories/{category_id}/images/upload [post]
+func (h *Handler) UploadCategoryImage(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	categoryID, err := api.ParseIDParameter(c, "category_id", "Category")
+	if err != nil {
+		return err
+	}
+
+	if _, err := h.Repository.GetCategory(c.Context(), db.GetCategoryParams{
+		ShopID:     shopID,
+		CategoryID: categoryID,
+	}); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return api.NotFoundErrorResponse(c, "Category")
+		}
+		return api.SystemErrorResponse(c, err, "Failed to verify category")
+	}
+
+	uploaded, err := h.uploadImage(c, fmt.Sprintf("shops/%d/images/categories/%d", shopID, categoryID))
+	if err != nil {
+		return imageUploadErrorResponse(c, err)
+	}
+
+	err = h.Repository.WithTx(c.Context(), func(q *db.Queries) error {
+		if err := createImageAsset(c.Context(), q, shopID, uploaded); err != nil {
+			return err
+		}
+		_, err := q.UpdateCategoryBanner(c.Context(), db.UpdateCategoryBannerParams{
+			BannerUrl:  &uploaded.URL,
+			CategoryID: categoryID,
+			ShopID:     shopID,
+		})
+		return err
+	})
+	if err != nil {
+		h.ImageUploadService.Remove(context.Background(), uploaded)
+		return api.SystemErrorResponse(c, err, "Failed to update category banner")
+	}
+
+	h.autoPublishImages(shopID, "products")
+
+	return api.SuccessResponse(c, fiber.StatusCreated, imageUploadResponse(uploaded), "Image uploaded successfully")
+}
+
+// shopImageSlots lists the shop image slots accepted by UploadShopImage
+var shopImageSlots = map[string]func(p *db.UpdateShopImagesParams, url *string){
+	"favicon":          func(p *db.UpdateShopImagesParams, url *string) { p.FaviconUrl = url },
+	"logo":             func(p *db.UpdateShopImagesParams, url *string) { p.LogoUrl = url },
+	"logo_dark":        func(p *db.UpdateShopImagesParams, url *string) { p.LogoUrlDark = url },
+	"banner":           func(p *db.UpdateShopImagesParams, url *string) { p.BannerUrl = url },
+	"banner_dark":      func(p *db.UpdateShopImagesParams, url *string) { p.BannerUrlDark = url },
+	"cover_image":      func(p *db.UpdateShopImagesParams, url *string) { p.CoverImageUrl = url },
+	"cover_image_dark": func(p *db.UpdateShopImagesParams, url *string) { p.CoverImageUrlDark = url },
+}
+
+// UploadShopImage uploads one of the shop's branding images
+// @Summary Upload a shop image
+// @Description Upload an image file (JPEG, PNG or WebP) into one of the shop image slots: favicon, logo, logo_dark, banner, banner_dark, cover_image or cover_image_dark
+// @Tags shops
+// @Accept multipart/form-data
+// @Produce json
+// @Param shop_id path string true "Shop ID"
+// @Param slot formData string true "Image slot" Enums(favicon, logo, logo_dark, banner, banner_dark, cover_image, cover_image_dark)
+// @Param file formData file true "Image file"
+// @Success 201 {object} models.SuccessResponse{data=models.ImageUploadResponse} "Image uploaded successfully"
+// @Failure 400 {object} models.ErrorResponse "Bad request"
+// @Failure 404 {object} models.ErrorResponse "Shop not found"
+// @Failure 413 {object} models.ErrorResponse "Image too large"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Security OAuth2AccessCode
+// @Router /shops/{shop_id}/images/upload [post]
+func (h *Handler) UploadShopImage(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+
+	slot := c.FormValue("slot")
+	setSlot, ok := shopImageSlots[slot]
+	if !ok {
+		return api.ErrorResponse(c, fiber.StatusBadRequest, "Invalid image slot", nil)
+	}
+
+	if _, err := h.Repository.GetShop(c.Context(), shopID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return api.NotFoundErrorResponse(c, "Shop")
+		}
+		return api.SystemErrorResponse(c, err, "Failed to verify shop")
+	}
+
+	uploaded, err := h.uploadImage(c, fmt.Sprintf("shops/%d/images/%s", shopID, slot))
+	if err != nil {
+		return imageUploadErrorResponse(c, err)
+	}
+
+	err = h.Repository.WithTx(c.Context(), func(q *db.Queries) error {
+		if err := createImageAsset(c.Context(), q, shopID, uploaded); err != nil {
+			return err
+		}
+
+		params := db.UpdateShopImagesParams{ShopID: shopID}
+		setSlot(&params, &uploaded.URL)
+
+		if _, err := q.GetShopImages(c.Context(), shopID); err != nil {
+			if !errors.Is(err, pgx.ErrNoRows) {
+				return err
+			}
+			_, err = q.CreateShopImages(c.Context(), db.CreateShopImagesParams{
+				FaviconUrl:        params.FaviconUrl,
+				LogoUrl:           params.LogoUrl,
+				LogoUrlDark:       params.LogoUrlDark,
+				BannerUrl:         params.BannerUrl,
+				BannerUrlDark:     params.BannerUrlDark,
+				CoverImageUrl:     params.CoverImageUrl,
+				CoverImageUrlDark: params.CoverImageUrlDark,
+				ShopID:            shopID,
+			})
+			return err
+		}
+		_, err := q.UpdateShopImages(c.Context(), params)
+		return err
+	})
+	if err != nil {
+		h.ImageUploadService.Remove(context.Background(), uploaded)
+		return api.SystemErrorResponse(c, err, "Failed to update shop images")
+	}
+
+	h.autoPublishImages(shopID, "shop")
+
+	response := imageUploadResponse(uploaded)
+	response.Slot = slot
+	return api.SuccessResponse(c, fiber.StatusCreated, response, "Image uploaded successfully")
+}
+
+var (
+	errImageUploadsDisabled = errors.New("image uploads are not configured")
+	errImageFileMissing     = errors.New("image file is required")
+)
+
+// uploadImage reads the "file" form field and stores it under prefix
+func (h *Handler) uploadImage(c *fiber.Ctx, prefix string) (*services.UploadedImage, error) {
+	if h.ImageUploadService == nil {
+		return nil, errImageUploadsDisabled
+	}
+	maxBytes := h.ImageUploadService.Config.MaxBytes
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return nil, errImageFileMissing
+	}
+	if fileHeader.Size > maxBytes {
+		return nil, services.ErrImageTooLarge
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return nil, fmt.Errorf("open uploaded file: %w", err)
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, maxBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("read uploaded file: %w", err)
+	}
+
+	return h.ImageUploadService.Upload(c.Context(), prefix, data)
+}
+
+func imageUploadErrorResponse(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, errImageUploadsDisabled):
+		return api.ErrorResponse(c, fiber.StatusServiceUnavailable, "Image uploads are not configured", nil)
+	case errors.Is(err, errImageFileMissing):
+		return api.ErrorResponse(c, fiber.StatusBadRequest, "Image file is required", nil)
+	case errors.Is(err, services.ErrImageTooLarge):
+		return api.ErrorResponse(c, fiber.StatusRequestEntityTooLarge, err.Error(), nil)
+	case errors.Is(err, services.ErrImageTooManyPixels), errors.Is(err, services.ErrUnsupportedImage):
+		return api.ErrorResponse(c, fiber.StatusBadRequest, err.Error(), nil)
+	}
+	return api.SystemErrorResponse(c, err, "Failed to store image")
+}
+
+func createImageAsset(ctx context.Context, q *db.Queries, shopID int64, uploaded *services.UploadedImage) error {
+	renditions, err := json.Marshal(uploaded.Renditions)
+	if err != nil {
+		return err
+	}
+	_, err = q.CreateImageAsset(ctx, db.CreateImageAssetParams{
+		StorageKey:  uploaded.Key,
+		Url:         uploaded.URL,
+		ContentType: uploaded.ContentType,
+		Width:       int32(uploaded.Width),
+		Height:      int32(uploaded.Height),
+		SizeBytes:   uploaded.SizeBytes,
+		Renditions:  renditions,
+		ShopID:      shopID,
+	})
+	return err
+}
+
+func imageUploadResponse(uploaded *services.UploadedImage) models.ImageUploadResponse {
+	renditions := make([]models.ImageRendition, len(uploaded.Renditions))
+	for i, r := range uploaded.Renditions {
+		renditions[i] = models.ImageRendition{
+			Format:    r.Format,
+			Width:     r.Width,
+			Height:    r.Height,
+			URL:       r.URL,
+			SizeBytes: r.SizeBytes,
+		}
+	}
+	return models.ImageUploadResponse{
+		URL:         uploaded.URL,
+		ContentType: uploaded.ContentType,
+		Width:       uploaded.Width,
+		Height:      uploaded.Height,
+		SizeBytes:   uploaded.SizeBytes,
+		Renditions:  renditions,
+	}
+}
+
+// autoPublishImages pushes the updated data to the store-deployer in the background
+func (h *Handler) autoPublishImages(shopID int64, dataType string) {
+	shop, err := h.Repository.GetShop(context.Background(), shopID)
+	if err != nil {
+		zap.L().Warn("Auto-publish: failed to get shop for auto-publish", zap.Int64("shop_id", shopID), zap.Error(err))
+		return
+	}
+
+	go func(shopID int64, subdomain string) {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		ctx, finish := observability.StartSpan(ctx, "autoPublishUploadedImage", "store-deployer", "POST", "update-data")
+		defer finish(0, nil)
+
+		if err := h.StoreDeployerClient.UpdateData(ctx, subdomain, shopID, dataType); err != nil {
+			zap.L().Warn("auto-publish failed",
+				zap.Int64("shop_id", shopID),
+				zap.String("data_type", dataType),
+				zap.Error(err))
+		}
+	}(shopID, shop.Subdomain)
+}
@@ -1,23 +1,27 @@
 package handlers
 
 import (
+	"context"
+	"errors"
 	"strconv"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
 	"github.com/petrejonn/naytife/internal/api"
 	"github.com/petrejonn/naytife/internal/api/models"
 	"github.com/petrejonn/naytife/internal/db"
+	"github.com/petrejonn/naytife/internal/services"
 	"go.uber.org/zap"
 )
 
 // GetLowStockVariants fetches product variants with low stock
 // @Summary      Get low stock variants
-// @Description  Get product variants that are running low on stock
+// @Description  Get product variants that are running low on stock: at or below the threshold when one is given, otherwise at or below their reorder point or that of their product type. Variants without a reorder point use a threshold of 10.
 // @Tags         inventory
 // @Produce      json
 // @Param        shop_id path string true "Shop ID"
-// @Param        threshold query int false "Stock threshold" default(10)
+// @Param        threshold query int false "Stock threshold, overriding reorder points"
 // @Success      200  {object}   models.SuccessResponse{data=[]models.LowStockVariantResponse} "Low stock variants fetched successfully"
 // @Failure      400  {object}   models.ErrorResponse "Bad request"
 // @Failure      500  {object}   models.ErrorResponse "Internal server error"
@@ -31,12 +35,18 @@ func (h *Handler) GetLowStockVariants(c *fiber.Ctx) error {
 		return api.ErrorResponse(c, fiber.StatusBadRequest, "Invalid shop ID", nil)
 	}
 
-	thresholdStr := c.Query("threshold", "10")
-	threshold, _ := strconv.ParseInt(thresholdStr, 10, 32)
+	var threshold *int64
+	if thresholdStr := c.Query("threshold"); thresholdStr != "" {
+		value, err := strconv.ParseInt(thresholdStr, 10, 64)
+		if err != nil || value < 0 {
+			return api.ErrorResponse(c, fiber.StatusBadRequest, "threshold must be a whole number, zero or more", nil)
+		}
+		threshold = &value
+	}
 
 	variants, err := h.Repository.GetLowStockVariants(c.Context(), db.GetLowStockVariantsParams{
-		ShopID:            shopID,
-		AvailableQuantity: threshold,
+		Threshold: threshold,
+		ShopID:    shopID,
 	})
 	if err != nil {
 		zap.L().Error("GetLowStockVariants: failed to fetch low stock variants", zap.Error(err), zap.Int64("shop_id", shopID))
@@ -53,7 +63,7 @@ func (h *Handler) GetLowStockVariants(c *fiber.Ctx) error {
 			VariantTitle: &variant.Description, // Using description as variant title
 			SKU:          &variant.Sku,
 			CurrentStock: int32(variant.AvailableQuantity),
-			ReorderLevel: int32(threshold),
+			ReorderLevel: int32(variant.ReorderLevel),
 			Price:        models.NumericToFloat64(variant.Price),
 		}
 	}
@@ -63,7 +73,7 @@ func (h *Handler) GetLowStockVariants(c *fiber.Ctx) error {
 
 // UpdateVariantStock updates the stock quantity for a product variant
 // @Summary      Update variant stock
-// @Description  Update the stock quantity for a specific product variant
+// @Description  Set the stock quantity of a product variant at a location, the default location unless one is given, and optionally replace its cost price
 // @Tags         inventory
 // @Accept       json
 // @Produce      json
@@ -72,7 +82,7 @@ func (h *Handler) GetLowStockVariants(c *fiber.Ctx) error {
 // @Param        stock body models.UpdateStockParams true "Stock update parameters"
 // @Success      200  {object}   models.SuccessResponse{data=models.VariantStockResponse} "Stock updated successfully"
 // @Failure      400  {object}   models.ErrorResponse "Bad request"
-// @Failure      404  {object}   models.ErrorResponse "Variant not found"
+// @Failure      404  {object}   models.ErrorResponse "Variant or location not found"
 // @Failure      500  {object}   models.ErrorResponse "Internal server error"
 // @Security     OAuth2AccessCode
 // @Router       /shops/{shop_id}/inventory/variants/{variant_id}/stock [put]
@@ -105,63 +115,46 @@ func (h *Handler) UpdateVariantStock(c *fiber.Ctx) error {
 		return api.ErrorResponse(c, fiber.StatusBadRequest, errMsgs, nil)
 	}
 
-	// Get current stock before update to track the movement
-	currentVariant, err := h.Repository.GetProductVariation(c.Context(), db.GetProductVariationParams{
-		ProductVariationID: variantID,
-		ShopID:             shopID,
+	var result services.StockChangeResult
+	err = h.Repository.WithTx(c.Context(), func(q *db.Queries) error {
+		location, err := services.StockLocation(c.Context(), q, shopID, param.LocationID)
+		if err != nil {
+			return err
+		}
+		result, err = services.ChangeStock(c.Context(), q, services.StockChange{
+			ShopID:       shopID,
+			VariantID:    variantID,
+			LocationID:   location.LocationID,
+			Operation:    services.StockSet,
+			Quantity:     int64(param.Quantity),
+			MovementType: param.MovementType,
+			Notes:        param.Reason,
+		})
+		if err != nil || param.CostPrice == nil {
+			return err
+		}
+		result.Variant, err = q.SetVariantCostPrice(c.Context(), db.SetVariantCostPriceParams{
+			ProductVariationID: variantID,
+			ShopID:             shopID,
+			CostPrice:          services.FloatToNumeric(*param.CostPrice),
+		})
+		return err
 	})
 	if err != nil {
-		zap.L().Warn("UpdateVariantStock: product variant not found", zap.Int64("shop_id", shopID), zap.Int64("variant_id", variantID), zap.Error(err))
-		return api.ErrorResponse(c, fiber.StatusNotFound, "Product variant not found", nil)
+		zap.L().Warn("UpdateVariantStock: failed to update variant stock", zap.Int64("shop_id", shopID), zap.Int64("variant_id", variantID), zap.Error(err))
+		return stockChangeErrorResponse(c, err, "Failed to update stock")
 	}
 
-	quantityBefore := int32(currentVariant.AvailableQuantity)
-	quantityAfter := param.Quantity
-	quantityChange := quantityAfter - quantityBefore
+	h.queueBackInStockNotifications(c.Context(), shopID, variantID, result.OnlineBefore, result.Variant.AvailableQuantity)
 
-	updatedVariant, err := h.Repository.UpdateVariantStock(c.Context(), db.UpdateVariantStockParams{
-		ProductVariationID: variantID,
-		ShopID:             shopID,
-		AvailableQuantity:  int64(param.Quantity),
-	})
-	if err != nil {
-		zap.L().Error("UpdateVariantStock: failed to update variant stock", zap.Int64("shop_id", shopID), zap.Int64("variant_id", variantID), zap.Error(err))
-		return api.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to update stock", nil)
-	}
-
-	// Create stock movement record
-	var notes *string
-	if param.Reason != nil {
-		notes = param.Reason
-	}
-
-	_, err = h.Repository.CreateStockMovement(c.Context(), db.CreateStockMovementParams{
-		ProductVariationID: variantID,
-		ShopID:             shopID,
-		MovementType:       param.MovementType,
-		QuantityChange:     quantityChange,
-		QuantityBefore:     quantityBefore,
-		QuantityAfter:      quantityAfter,
-		ReferenceID:        nil, // No reference for manual adjustments
-		Notes:              notes,
-	})
-	if err != nil {
-		// Log error but don't fail the request since stock was already updated
-		zap.L().Warn("UpdateVariantStock: failed to create stock movement record", zap.Int64("shop_id", shopID), zap.Int64("variant_id", variantID), zap.Error(err))
-	}
-
-	response := models.VariantStockResponse{
-		VariantID: updatedVariant.ProductVariationID,
-		Stock:     int32(updatedVariant.AvailableQuantity),
-		UpdatedAt: models.TimestamptzToTime(updatedVariant.UpdatedAt),
-	}
+	response := newVariantStockResponse(result)
 
 	return api.SuccessResponse(c, fiber.StatusOK, response, "Stock updated successfully")
 }
 
 // AddVariantStock adds stock to a product variant
 // @Summary      Add stock to variant
-// @Description  Add stock quantity to a specific product variant
+// @Description  Add stock to a product variant at a location, the default location unless one is given. Stock of product types that track lots is received into the lot given.
 // @Tags         inventory
 // @Accept       json
 // @Produce      json
@@ -170,7 +163,7 @@ func (h *Handler) UpdateVariantStock(c *fiber.Ctx) error {
 // @Param        stock body models.AddStockParams true "Stock addition parameters"
 // @Success      200  {object}   models.SuccessResponse{data=models.VariantStockResponse} "Stock added successfully"
 // @Failure      400  {object}   models.ErrorResponse "Bad request"
-// @Failure      404  {object}   models.ErrorResponse "Variant not found"
+// @Failure      404  {object}   models.ErrorResponse "Variant or location not found"
 // @Failure      500  {object}   models.ErrorResponse "Internal server error"
 // @Security     OAuth2AccessCode
 // @Router       /shops/{shop_id}/inventory/variants/{variant_id}/add-stock [post]
@@ -202,31 +195,44 @@ func (h *Handler) AddVariantStock(c *fiber.Ctx) error {
 		return api.ErrorResponse(c, fiber.StatusBadRequest, errMsgs, nil)
 	}
 
-	updatedVariant, err := h.Repository.AddVariantStock(c.Context(), db.AddVariantStockParams{
-		ProductVariationID: variantID,
-		ShopID:             shopID,
-		AvailableQuantity:  int64(param.Quantity),
+	lot := stockLot(param.StockLotParams)
+	var result services.StockChangeResult
+	err = h.Repository.WithTx(c.Context(), func(q *db.Queries) error {
+		location, err := services.StockLocation(c.Context(), q, shopID, param.LocationID)
+		if err != nil {
+			return err
+		}
+		if err := services.CheckStockLot(c.Context(), q, shopID, variantID, lot); err != nil {
+			return err
+		}
+		result, err = services.ChangeStock(c.Context(), q, services.StockChange{
+			ShopID:       shopID,
+			VariantID:    variantID,
+			LocationID:   location.LocationID,
+			Operation:    services.StockAdd,
+			Quantity:     int64(param.Quantity),
+			MovementType: "restock",
+			ReferenceID:  parseReferenceID(param.ReferenceID),
+			Notes:        &param.Reason,
+			Lot:          lot,
+		})
+		return err
 	})
 	if err != nil {
-		zap.L().Error("AddVariantStock: failed to add stock", zap.Int64("shop_id", shopID), zap.Int64("variant_id", variantID), zap.Error(err))
-		return api.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to add stock", nil)
+		zap.L().Warn("AddVariantStock: failed to add stock", zap.Int64("shop_id", shopID), zap.Int64("variant_id", variantID), zap.Error(err))
+		return stockChangeErrorResponse(c, err, "Failed to add stock")
 	}
 
-	// Create stock movement record
-	// TODO: Implement stock movement tracking
+	h.queueBackInStockNotifications(c.Context(), shopID, variantID, result.OnlineBefore, result.Variant.AvailableQuantity)
 
-	response := models.VariantStockResponse{
-		VariantID: updatedVariant.ProductVariationID,
-		Stock:     int32(updatedVariant.AvailableQuantity),
-		UpdatedAt: models.TimestamptzToTime(updatedVariant.UpdatedAt),
-	}
+	response := newVariantStockResponse(result)
 
 	return api.SuccessResponse(c, fiber.StatusOK, response, "Stock added successfully")
 }
 
 // DeductVariantStock deducts stock from a product variant
 // @Summary      Deduct stock from variant
-// @Description  Deduct stock quantity from a specific product variant
+// @Description  Deduct stock from a product variant at a location, the default location unless one is given. Deducting more than the location holds fails. Stock kept in lots is taken earliest expiry first.
 // @Tags         inventory
 // @Accept       json
 // @Produce      json
@@ -235,7 +241,7 @@ func (h *Handler) AddVariantStock(c *fiber.Ctx) error {
 // @Param        stock body models.DeductStockParams true "Stock deduction parameters"
 // @Success      200  {object}   models.SuccessResponse{data=models.VariantStockResponse} "Stock deducted successfully"
 // @Failure      400  {object}   models.ErrorResponse "Bad request"
-// @Failure      404  {object}   models.ErrorResponse "Variant not found"
+// @Failure      404  {object}   models.ErrorResponse "Variant or location not found"
 // @Failure      500  {object}   models.ErrorResponse "Internal server error"
 // @Security     OAuth2AccessCode
 // @Router       /shops/{shop_id}/inventory/variants/{variant_id}/deduct-stock [post]
@@ -267,44 +273,30 @@ func (h *Handler) DeductVariantStock(c *fiber.Ctx) error {
 		return api.ErrorResponse(c, fiber.StatusBadRequest, errMsgs, nil)
 	}
 
-	updatedVariant, err := h.Repository.DeductVariantStock(c.Context(), db.DeductVariantStockParams{
-		ProductVariationID: variantID,
-		ShopID:             shopID,
-		AvailableQuantity:  int64(param.Quantity),
-	})
-	if err != nil {
-		zap.L().Error("DeductVariantStock: failed to deduct stock", zap.Int64("shop_id", shopID), zap.Int64("variant_id", variantID), zap.Error(err))
-		return api.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to deduct stock", nil)
-	}
-
-	// Create stock movement record
-	var referenceID *int64
-	if param.ReferenceID != nil {
-		id, err := strconv.ParseInt(*param.ReferenceID, 10, 64)
-		if err == nil {
-			referenceID = &id
+	var result services.StockChangeResult
+	err = h.Repository.WithTx(c.Context(), func(q *db.Queries) error {
+		location, err := services.StockLocation(c.Context(), q, shopID, param.LocationID)
+		if err != nil {
+			return err
 		}
-	}
-
-	_, err = h.Repository.CreateStockMovement(c.Context(), db.CreateStockMovementParams{
-		ProductVariationID: variantID,
-		ShopID:             shopID,
-		MovementType:       "sale",
-		QuantityChange:     -param.Quantity,                                                 // Negative for deduction
-		QuantityBefore:     int32(updatedVariant.AvailableQuantity + int64(param.Quantity)), // Previous stock
-		QuantityAfter:      int32(updatedVariant.AvailableQuantity),                         // Current stock
-		ReferenceID:        referenceID,
-		Notes:              &param.Reason,
+		result, err = services.ChangeStock(c.Context(), q, services.StockChange{
+			ShopID:       shopID,
+			VariantID:    variantID,
+			LocationID:   location.LocationID,
+			Operation:    services.StockDeduct,
+			Quantity:     int64(param.Quantity),
+			MovementType: "sale",
+			ReferenceID:  parseReferenceID(param.ReferenceID),
+			Notes:        &param.Reason,
+		})
+		return err
 	})
 	if err != nil {
-		zap.L().Warn("DeductVariantStock: failed to create stock movement record", zap.Int64("shop_id", shopID), zap.Int64("variant_id", variantID), zap.Error(err))
+		zap.L().Warn("DeductVariantStock: failed to deduct stock", zap.Int64("shop_id", shopID), zap.Int64("variant_id", variantID), zap.Error(err))
+		return stockChangeErrorResponse(c, err, "Failed to deduct stock")
 	}
 
-	response := models.VariantStockResponse{
-		VariantID: updatedVariant.ProductVariationID,
-		Stock:     int32(updatedVariant.AvailableQuantity),
-		UpdatedAt: models.TimestamptzToTime(updatedVariant.UpdatedAt),
-	}
+	response := newVariantStockResponse(result)
 
 	return api.SuccessResponse(c, fiber.StatusOK, response, "Stock deducted successfully")
 }
@@ -329,8 +321,8 @@ func (h *Handler) GetInventoryReport(c *fiber.Ctx) error {
 	}
 
 	report, err := h.Repository.GetInventoryReport(c.Context(), db.GetInventoryReportParams{
-		ShopID:            shopID,
-		AvailableQuantity: 10, // Default threshold for low stock
+		DefaultThreshold: 10, // Low stock threshold of variants without a reorder point
+		ShopID:           shopID,
 	})
 	if err != nil {
 		zap.L().Error("GetInventoryReport: failed to generate inventory report", zap.Error(err), zap.Int64("shop_id", shopID))
@@ -366,7 +358,7 @@ func (h *Handler) GetInventoryReport(c *fiber.Ctx) error {
 			CurrentStock:      item.AvailableQuantity,
 			ReservedStock:     int64(item.ReservedQuantity),
 			AvailableStock:    item.AvailableStock,
-			LowStockThreshold: 10,  // TODO: Get from product variation settings
+			LowStockThreshold: item.LowStockThreshold,
 			Location:          nil, // TODO: Add location field
 			LastUpdated:       models.TimestamptzToTime(item.UpdatedAt).Format("2006-01-02T15:04:05Z07:00"),
 		}
@@ -450,6 +442,7 @@ func (h *Handler) GetStockMovements(c *fiber.Ctx) error {
 		movementResponses[i] = models.StockMovementResponse{
 			MovementID:    movement.MovementID,
 			VariantID:     movement.ProductVariationID,
+			LocationID:    movement.LocationID,
 			MovementType:  movement.MovementType,
 			Quantity:      movement.QuantityChange,
 			PreviousStock: previousStock,
@@ -473,3 +466,88 @@ func (h *Handler) GetStockMovements(c *fiber.Ctx) error {
 
 	return api.SuccessResponse(c, fiber.StatusOK, response, "Stock movements fetched successfully")
 }
+
+// newVariantStockResponse converts the result of a stock change to its API representation
+func newVariantStockResponse(result services.StockChangeResult) models.VariantStockResponse {
+	response := models.VariantStockResponse{
+		VariantID:     result.Variant.ProductVariationID,
+		Stock:         int32(result.Variant.AvailableQuantity),
+		LocationID:    result.Level.LocationID,
+		LocationStock: int32(result.Level.Available),
+		UpdatedAt:     models.TimestamptzToTime(result.Level.UpdatedAt),
+	}
+	if result.Variant.CostPrice.Valid {
+		costPrice := models.NumericToFloat64(result.Variant.CostPrice)
+		response.CostPrice = &costPrice
+	}
+	return response
+}
+
+// stockChangeErrorResponse maps the errors of a stock change to a response
+func stockChangeErrorResponse(c *fiber.Ctx, err error, message string) error {
+	switch {
+	case errors.Is(err, services.ErrLocationNotFound):
+		return api.ErrorResponse(c, fiber.StatusNotFound, "Location not found", nil)
+	case errors.Is(err, pgx.ErrNoRows):
+		return api.ErrorResponse(c, fiber.StatusNotFound, "Product variant not found", nil)
+	case errors.Is(err, services.ErrInsufficientStock):
+		return api.BusinessLogicErrorResponse(c, "Not enough stock at the location")
+	case errors.Is(err, services.ErrInsufficientLotStock):
+		return api.BusinessLogicErrorResponse(c, "Not enough stock in the lot")
+	case errors.Is(err, services.ErrLotRequired):
+		return api.BusinessLogicErrorResponse(c, "A lot number is required for products that track lots")
+	case errors.Is(err, services.ErrLotsNotTracked):
+		return api.BusinessLogicErrorResponse(c, "The product does not track lots")
+	case errors.Is(err, services.ErrBundleStock):
+		return api.BusinessLogicErrorResponse(c, "Bundles hold no stock of their own; change the stock of their components")
+	}
+	return api.ErrorResponse(c, fiber.StatusInternalServerError, message, nil)
+}
+
+// stockLot reads the optional lot stock is received into. The date has been
+// validated as YYYY-MM-DD.
+func stockLot(param models.StockLotParams) *services.StockLot {
+	if param.LotNumber == nil {
+		return nil
+	}
+	lot := &services.StockLot{Number: *param.LotNumber}
+	if param.ExpiresOn != nil {
+		expiresOn, err := time.Parse(time.DateOnly, *param.ExpiresOn)
+		if err == nil {
+			lot.ExpiresOn = &expiresOn
+		}
+	}
+	return lot
+}
+
+// parseReferenceID reads the optional numeric reference of a stock movement
+func parseReferenceID(referenceID *string) *int64 {
+	if referenceID == nil {
+		return nil
+	}
+	id, err := strconv.ParseInt(*referenceID, 10, 64)
+	if err != nil {
+		return nil
+	}
+	return &id
+}
+
+// queueBackInStockNotifications queues notifications for the back in stock
+// subscribers of a variant when its stock goes from zero to positive. Failures are
+// logged rather than returned because the stock change has already been saved.
+func (h *Handler) queueBackInStockNotifications(ctx context.Context, shopID, variantID, quantityBefore, quantityAfter int64) {
+	if quantityBefore > 0 || quantityAfter <= 0 {
+		return
+	}
+	queued, err := h.Repository.QueueBackInStockNotifications(ctx, db.QueueBackInStockNotificationsParams{
+		ProductVariationID: variantID,
+		ShopID:             shopID,
+	})
+	if err != nil {
+		zap.L().Warn("queueBackInStockNotifications: failed to queue notifications", zap.Int64("shop_id", shopID), zap.Int64("variant_id", variantID), zap.Error(err))
+		return
+	}
+	if queued > 0 {
+		zap.L().Info("queueBackInStockNotifications: queued notifications", zap.Int64("shop_id", shopID), zap.Int64("variant_id", variantID), zap.Int64("count", queued))
+	}
+}
@@ -0,0 +1,234 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
+	"github.com/petrejonn/naytife/internal/api"
+	"github.com/petrejonn/naytife/internal/api/models"
+	"github.com/petrejonn/naytife/internal/db"
+	"github.com/petrejonn/naytife/internal/services"
+	"go.uber.org/zap"
+)
+
+// GetExpiringLots fetches the lots with stock that expires soon
+// @Summary      Get expiring stock
+// @Description  Get the lots holding stock that expires within the given number of days, earliest expiry first. Lots that have already expired are included.
+// @Tags         inventory
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        days query int false "Days ahead" default(30)
+// @Param        location_id query int false "Only lots at this location"
+// @Success      200  {object}   models.SuccessResponse{data=[]models.ExpiringLotResponse} "Expiring stock fetched successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/inventory/expiring [get]
+func (h *Handler) GetExpiringLots(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	days, err := strconv.ParseInt(c.Query("days", "30"), 10, 32)
+	if err != nil || days < 0 {
+		return api.ErrorResponse(c, fiber.StatusBadRequest, "days must be a whole number of days, zero or more", nil)
+	}
+	var locationID *int64
+	if locationIDStr := c.Query("location_id"); locationIDStr != "" {
+		id, err := strconv.ParseInt(locationIDStr, 10, 64)
+		if err != nil {
+			return api.ErrorResponse(c, fiber.StatusBadRequest, "Invalid location ID", nil)
+		}
+		locationID = &id
+	}
+
+	lots, err := h.Repository.ListExpiringLots(c.Context(), db.ListExpiringLotsParams{
+		ShopID:     shopID,
+		Days:       int32(days),
+		LocationID: locationID,
+	})
+	if err != nil {
+		zap.L().Error("GetExpiringLots: failed to fetch expiring lots", zap.Int64("shop_id", shopID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to fetch expiring stock")
+	}
+
+	response := make([]models.ExpiringLotResponse, len(lots))
+	for i, lot := range lots {
+		response[i] = models.NewExpiringLot(lot)
+	}
+	return api.SuccessResponse(c, fiber.StatusOK, response, "Expiring stock fetched successfully")
+}
+
+// GetVariantLots lists the lots holding stock of a variant
+// @Summary      Get variant lots
+// @Description  Get the lots holding stock of a product variant at every location, earliest expiry first
+// @Tags         inventory
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        variant_id path string true "Variant ID"
+// @Success      200  {object}   models.SuccessResponse{data=[]models.InventoryLot} "Variant lots fetched successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      404  {object}   models.ErrorResponse "Variant not found"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/inventory/variants/{variant_id}/lots [get]
+func (h *Handler) GetVariantLots(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	variantID, err := api.ParseIDParameter(c, "variant_id", "Variant")
+	if err != nil {
+		return err
+	}
+
+	if _, err := h.Repository.GetProductVariation(c.Context(), db.GetProductVariationParams{
+		ProductVariationID: variantID,
+		ShopID:             shopID,
+	}); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return api.NotFoundErrorResponse(c, "Product variant")
+		}
+		return api.SystemErrorResponse(c, err, "Failed to fetch product variant")
+	}
+	lots, err := h.Repository.ListVariantLots(c.Context(), db.ListVariantLotsParams{
+		ProductVariationID: variantID,
+		ShopID:             shopID,
+	})
+	if err != nil {
+		zap.L().Error("GetVariantLots: failed to fetch lots", zap.Int64("variant_id", variantID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to fetch variant lots")
+	}
+
+	response := make([]models.InventoryLot, len(lots))
+	for i, lot := range lots {
+		response[i] = models.NewInventoryLot(lot)
+	}
+	return api.SuccessResponse(c, fiber.StatusOK, response, "Variant lots fetched successfully")
+}
+
+// WriteOffLot writes off stock of a lot
+// @Summary      Write off a lot
+// @Description  Take stock of a lot out of its location as a write_off movement that references the lot, such as when it has expired or is damaged. Without a quantity everything left in the lot is written off.
+// @Tags         inventory
+// @Accept       json
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        lot_id path string true "Lot ID"
+// @Param        write_off body models.WriteOffLotParams false "Write-off details"
+// @Success      200  {object}   models.SuccessResponse{data=models.VariantStockResponse} "Lot written off successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request or not enough stock in the lot"
+// @Failure      404  {object}   models.ErrorResponse "Lot not found"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/inventory/lots/{lot_id}/write-off [post]
+func (h *Handler) WriteOffLot(c *fiber.Ctx) error {
+	lot, err := h.inventoryLotFromPath(c)
+	if err != nil {
+		return err
+	}
+	var param models.WriteOffLotParams
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&param); err != nil {
+			return api.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body", nil)
+		}
+	}
+	validator := &models.XValidator{}
+	if errs := validator.Validate(&param); len(errs) > 0 {
+		return api.ErrorResponse(c, fiber.StatusBadRequest, models.FormatValidationErrors(errs), nil)
+	}
+	quantity := lot.Quantity
+	if param.Quantity != nil {
+		quantity = *param.Quantity
+	}
+	if quantity == 0 {
+		return api.BusinessLogicErrorResponse(c, "The lot holds no stock to write off")
+	}
+
+	var result services.StockChangeResult
+	err = h.Repository.WithTx(c.Context(), func(q *db.Queries) error {
+		var err error
+		result, err = services.WriteOffLot(c.Context(), q, lot, quantity, param.Reason)
+		return err
+	})
+	if err != nil {
+		zap.L().Warn("WriteOffLot: failed to write off lot", zap.Int64("lot_id", lot.LotID), zap.Error(err))
+		return stockChangeErrorResponse(c, err, "Failed to write off lot")
+	}
+
+	return api.SuccessResponse(c, fiber.StatusOK, newVariantStockResponse(result), "Lot written off successfully")
+}
+
+// WriteOffExpiredLots writes off the stock of every expired lot
+// @Summary      Write off expired lots
+// @Description  Take the stock left in every lot that has expired out of its location as write_off movements that reference the lots, only at the given location when one is given
+// @Tags         inventory
+// @Accept       json
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        write_off body models.WriteOffExpiredLotsParams false "Write-off details"
+// @Success      200  {object}   models.SuccessResponse{data=[]models.VariantStockResponse} "Expired lots written off successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/inventory/lots/write-off-expired [post]
+func (h *Handler) WriteOffExpiredLots(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	var param models.WriteOffExpiredLotsParams
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&param); err != nil {
+			return api.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body", nil)
+		}
+	}
+	validator := &models.XValidator{}
+	if errs := validator.Validate(&param); len(errs) > 0 {
+		return api.ErrorResponse(c, fiber.StatusBadRequest, models.FormatValidationErrors(errs), nil)
+	}
+
+	var results []services.StockChangeResult
+	err = h.Repository.WithTx(c.Context(), func(q *db.Queries) error {
+		var err error
+		results, err = services.WriteOffExpiredLots(c.Context(), q, shopID, param.LocationID, param.Reason)
+		return err
+	})
+	if err != nil {
+		zap.L().Warn("WriteOffExpiredLots: failed to write off expired lots", zap.Int64("shop_id", shopID), zap.Error(err))
+		return stockChangeErrorResponse(c, err, "Failed to write off expired lots")
+	}
+
+	response := make([]models.VariantStockResponse, len(results))
+	for i, result := range results {
+		response[i] = newVariantStockResponse(result)
+	}
+	return api.SuccessResponse(c, fiber.StatusOK, response, "Expired lots written off successfully")
+}
+
+// inventoryLotFromPath fetches the lot named by the shop_id and lot_id path parameters
+func (h *Handler) inventoryLotFromPath(c *fiber.Ctx) (db.InventoryLot, error) {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return db.InventoryLot{}, err
+	}
+	lotID, err := api.ParseIDParameter(c, "lot_id", "Lot")
+	if err != nil {
+		return db.InventoryLot{}, err
+	}
+
+	lot, err := h.Repository.GetInventoryLot(c.Context(), db.GetInventoryLotParams{
+		LotID:  lotID,
+		ShopID: shopID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return db.InventoryLot{}, fiber.NewError(fiber.StatusNotFound, "Lot not found")
+		}
+		zap.L().Error("inventoryLotFromPath: failed to fetch lot", zap.Int64("lot_id", lotID), zap.Error(err))
+		return db.InventoryLot{}, fiber.NewError(fiber.StatusInternalServerError, "Failed to fetch lot")
+	}
+	return lot, nil
+}
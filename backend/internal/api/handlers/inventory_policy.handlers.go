@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/petrejonn/naytife/internal/api"
+	"github.com/petrejonn/naytife/internal/api/models"
+	"github.com/petrejonn/naytife/internal/db"
+	"go.uber.org/zap"
+)
+
+// GetVariantInventoryPolicy fetches what happens when a variant is ordered beyond its stock
+// @Summary      Get variant inventory policy
+// @Description  Get whether a product variant can be ordered beyond its stock, as a backorder or preorder, and how many units are backordered
+// @Tags         inventory
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        variant_id path string true "Variant ID"
+// @Success      200  {object}   models.SuccessResponse{data=models.VariantInventoryPolicy} "Inventory policy fetched successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      404  {object}   models.ErrorResponse "Variant not found"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/inventory/variants/{variant_id}/policy [get]
+func (h *Handler) GetVariantInventoryPolicy(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	variantID, err := api.ParseIDParameter(c, "variant_id", "Variant")
+	if err != nil {
+		return err
+	}
+
+	variant, err := h.Repository.GetProductVariation(c.Context(), db.GetProductVariationParams{
+		ProductVariationID: variantID,
+		ShopID:             shopID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return api.NotFoundErrorResponse(c, "Product variant")
+		}
+		return api.SystemErrorResponse(c, err, "Failed to fetch product variant")
+	}
+	backordered, err := h.Repository.GetVariantBackorderedQuantity(c.Context(), db.GetVariantBackorderedQuantityParams{
+		ProductVariationID: variantID,
+		ShopID:             shopID,
+	})
+	if err != nil {
+		zap.L().Error("GetVariantInventoryPolicy: failed to fetch backorders", zap.Int64("variant_id", variantID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to fetch backordered quantity")
+	}
+	return api.SuccessResponse(c, fiber.StatusOK, models.NewVariantInventoryPolicy(variant, backordered), "Inventory policy fetched successfully")
+}
+
+// SetVariantInventoryPolicy sets what happens when a variant is ordered beyond its stock
+// @Summary      Set variant inventory policy
+// @Description  Set whether a product variant can be ordered beyond its stock. deny refuses the order, backorder takes it to ship once restocked, and preorder takes it ahead of release with an expected ship date and an optional cap on the units preordered.
+// @Tags         inventory
+// @Accept       json
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        variant_id path string true "Variant ID"
+// @Param        policy body models.InventoryPolicyParams true "Inventory policy"
+// @Success      200  {object}   models.SuccessResponse{data=models.VariantInventoryPolicy} "Inventory policy updated successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      404  {object}   models.ErrorResponse "Variant not found"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/inventory/variants/{variant_id}/policy [put]
+func (h *Handler) SetVariantInventoryPolicy(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	variantID, err := api.ParseIDParameter(c, "variant_id", "Variant")
+	if err != nil {
+		return err
+	}
+
+	var param models.InventoryPolicyParams
+	if err := c.BodyParser(&param); err != nil {
+		return api.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body", nil)
+	}
+	validator := &models.XValidator{}
+	if errs := validator.Validate(&param); len(errs) > 0 {
+		errMsgs := models.FormatValidationErrors(errs)
+		return api.ErrorResponse(c, fiber.StatusBadRequest, errMsgs, nil)
+	}
+	policy := db.InventoryPolicy(param.Policy)
+	if policy != db.InventoryPolicyPreorder && (param.PreorderShipsOn != nil || param.PreorderLimit != nil) {
+		return api.BusinessLogicErrorResponse(c, "A ship date and limit can only be set for preorders")
+	}
+	var shipsOn pgtype.Date
+	if param.PreorderShipsOn != nil {
+		date, err := time.Parse(time.DateOnly, *param.PreorderShipsOn)
+		if err != nil {
+			return api.ErrorResponse(c, fiber.StatusBadRequest, "preorder_ships_on must be a date in YYYY-MM-DD format", nil)
+		}
+		shipsOn = pgtype.Date{Time: date, Valid: true}
+	}
+
+	variant, err := h.Repository.SetVariantInventoryPolicy(c.Context(), db.SetVariantInventoryPolicyParams{
+		ProductVariationID: variantID,
+		ShopID:             shopID,
+		InventoryPolicy:    policy,
+		PreorderShipsOn:    shipsOn,
+		PreorderLimit:      param.PreorderLimit,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return api.NotFoundErrorResponse(c, "Product variant")
+		}
+		zap.L().Error("SetVariantInventoryPolicy: failed to update policy", zap.Int64("variant_id", variantID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to update inventory policy")
+	}
+	backordered, err := h.Repository.GetVariantBackorderedQuantity(c.Context(), db.GetVariantBackorderedQuantityParams{
+		ProductVariationID: variantID,
+		ShopID:             shopID,
+	})
+	if err != nil {
+		zap.L().Error("SetVariantInventoryPolicy: failed to fetch backorders", zap.Int64("variant_id", variantID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to fetch backordered quantity")
+	}
+	return api.SuccessResponse(c, fiber.StatusOK, models.NewVariantInventoryPolicy(variant, backordered), "Inventory policy updated successfully")
+}
+
+// GetBackorderedOrderItems lists the order lines waiting for stock
+// @Summary      List backordered order items
+// @Description  Get the items of unfulfilled orders that were ordered beyond stock as backorders or preorders, oldest order first, with the stock now available for them
+// @Tags         inventory
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        limit query int false "Limit" default(20)
+// @Param        offset query int false "Offset" default(0)
+// @Success      200  {object}   models.SuccessResponse{data=[]models.BackorderedOrderItem} "Backordered items fetched successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/inventory/backorders [get]
+func (h *Handler) GetBackorderedOrderItems(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	limit, offset, err := api.ParsePaginationParams(c)
+	if err != nil {
+		return err
+	}
+
+	items, err := h.Repository.ListBackorderedOrderItems(c.Context(), db.ListBackorderedOrderItemsParams{
+		ShopID: shopID,
+		Limit:  int32(limit),
+		Offset: int32(offset),
+	})
+	if err != nil {
+		zap.L().Error("GetBackorderedOrderItems: failed to fetch backorders", zap.Int64("shop_id", shopID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to fetch backordered items")
+	}
+	total, err := h.Repository.CountBackorderedOrderItems(c.Context(), shopID)
+	if err != nil {
+		zap.L().Error("GetBackorderedOrderItems: failed to count backorders", zap.Int64("shop_id", shopID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to count backordered items")
+	}
+
+	response := make([]models.BackorderedOrderItem, len(items))
+	for i, item := range items {
+		response[i] = models.NewBackorderedOrderItem(item)
+	}
+	page := (offset / limit) + 1
+	return api.PaginatedSuccessResponse(c, fiber.StatusOK, response, total, page, limit, "Backordered items fetched successfully")
+}
@@ -0,0 +1,245 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
+	"github.com/petrejonn/naytife/internal/api"
+	"github.com/petrejonn/naytife/internal/api/models"
+	"github.com/petrejonn/naytife/internal/db"
+	"github.com/petrejonn/naytife/internal/services"
+	"go.uber.org/zap"
+)
+
+// GetInventoryTransfers lists the inventory transfers of a shop
+// @Summary      List inventory transfers
+// @Description  Get the stock transfers between the locations of a shop, newest first
+// @Tags         inventory
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        limit query int false "Limit" default(20)
+// @Param        offset query int false "Offset" default(0)
+// @Success      200  {object}   models.SuccessResponse{data=[]models.InventoryTransfer} "Inventory transfers fetched successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/inventory/transfers [get]
+func (h *Handler) GetInventoryTransfers(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	limit, offset, err := api.ParsePaginationParams(c)
+	if err != nil {
+		return err
+	}
+
+	transfers, err := h.Repository.ListInventoryTransfers(c.Context(), db.ListInventoryTransfersParams{
+		ShopID: shopID,
+		Limit:  int32(limit),
+		Offset: int32(offset),
+	})
+	if err != nil {
+		zap.L().Error("GetInventoryTransfers: failed to fetch transfers", zap.Int64("shop_id", shopID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to fetch inventory transfers")
+	}
+	total, err := h.Repository.CountInventoryTransfers(c.Context(), shopID)
+	if err != nil {
+		zap.L().Error("GetInventoryTransfers: failed to count transfers", zap.Int64("shop_id", shopID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to count inventory transfers")
+	}
+
+	response := make([]models.InventoryTransfer, len(transfers))
+	for i, transfer := range transfers {
+		response[i] = models.NewInventoryTransfer(transfer, nil)
+	}
+	page := (offset / limit) + 1
+	return api.PaginatedSuccessResponse(c, fiber.StatusOK, response, total, page, limit, "Inventory transfers fetched successfully")
+}
+
+// GetInventoryTransfer fetches an inventory transfer
+// @Summary      Get an inventory transfer
+// @Description  Get a stock transfer between two locations with its items
+// @Tags         inventory
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        transfer_id path string true "Transfer ID"
+// @Success      200  {object}   models.SuccessResponse{data=models.InventoryTransfer} "Inventory transfer fetched successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      404  {object}   models.ErrorResponse "Transfer not found"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/inventory/transfers/{transfer_id} [get]
+func (h *Handler) GetInventoryTransfer(c *fiber.Ctx) error {
+	transfer, err := h.inventoryTransferFromPath(c)
+	if err != nil {
+		return err
+	}
+	return h.inventoryTransferResponse(c, fiber.StatusOK, transfer, "Inventory transfer fetched successfully")
+}
+
+// CreateInventoryTransfer moves stock between two locations
+// @Summary      Create an inventory transfer
+// @Description  Send stock from one location to another. The items leave the origin straight away and count as incoming at the destination until the transfer is received.
+// @Tags         inventory
+// @Accept       json
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        transfer body models.InventoryTransferParams true "Inventory transfer"
+// @Success      201  {object}   models.SuccessResponse{data=models.InventoryTransfer} "Inventory transfer created successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      404  {object}   models.ErrorResponse "Variant or location not found"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/inventory/transfers [post]
+func (h *Handler) CreateInventoryTransfer(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	var param models.InventoryTransferParams
+	if err := c.BodyParser(&param); err != nil {
+		return api.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body", nil)
+	}
+	validator := &models.XValidator{}
+	if errs := validator.Validate(&param); len(errs) > 0 {
+		return api.ErrorResponse(c, fiber.StatusBadRequest, models.FormatValidationErrors(errs), nil)
+	}
+	items := make([]services.TransferItem, len(param.Items))
+	for i, item := range param.Items {
+		items[i] = services.TransferItem{VariantID: item.VariantID, Quantity: item.Quantity}
+	}
+
+	var transfer db.InventoryTransfer
+	err = h.Repository.WithTx(c.Context(), func(q *db.Queries) error {
+		for _, locationID := range []int64{param.OriginLocationID, param.DestinationLocationID} {
+			if _, err := services.StockLocation(c.Context(), q, shopID, &locationID); err != nil {
+				return err
+			}
+		}
+		var err error
+		transfer, _, err = services.CreateInventoryTransfer(c.Context(), q, db.CreateInventoryTransferParams{
+			OriginLocationID:      param.OriginLocationID,
+			DestinationLocationID: param.DestinationLocationID,
+			Note:                  param.Note,
+			CreatedBy:             requestUser(c),
+			ShopID:                shopID,
+		}, items)
+		return err
+	})
+	if err != nil {
+		zap.L().Warn("CreateInventoryTransfer: failed to create transfer", zap.Int64("shop_id", shopID), zap.Error(err))
+		return stockChangeErrorResponse(c, err, "Failed to create inventory transfer")
+	}
+
+	return h.inventoryTransferResponse(c, fiber.StatusCreated, transfer, "Inventory transfer created successfully")
+}
+
+// ReceiveInventoryTransfer receives the stock of a transfer at its destination
+// @Summary      Receive an inventory transfer
+// @Description  Make the stock of a transfer that is in transit available at its destination
+// @Tags         inventory
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        transfer_id path string true "Transfer ID"
+// @Success      200  {object}   models.SuccessResponse{data=models.InventoryTransfer} "Inventory transfer received successfully"
+// @Failure      400  {object}   models.ErrorResponse "Transfer is not in transit"
+// @Failure      404  {object}   models.ErrorResponse "Transfer not found"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/inventory/transfers/{transfer_id}/receive [post]
+func (h *Handler) ReceiveInventoryTransfer(c *fiber.Ctx) error {
+	return h.settleInventoryTransfer(c, services.ReceiveInventoryTransfer, "Inventory transfer received successfully")
+}
+
+// CancelInventoryTransfer returns the stock of a transfer to its origin
+// @Summary      Cancel an inventory transfer
+// @Description  Return the stock of a transfer that is in transit to its origin
+// @Tags         inventory
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        transfer_id path string true "Transfer ID"
+// @Success      200  {object}   models.SuccessResponse{data=models.InventoryTransfer} "Inventory transfer cancelled successfully"
+// @Failure      400  {object}   models.ErrorResponse "Transfer is not in transit"
+// @Failure      404  {object}   models.ErrorResponse "Transfer not found"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/inventory/transfers/{transfer_id}/cancel [post]
+func (h *Handler) CancelInventoryTransfer(c *fiber.Ctx) error {
+	return h.settleInventoryTransfer(c, services.CancelInventoryTransfer, "Inventory transfer cancelled successfully")
+}
+
+type settleTransferFunc func(ctx context.Context, q *db.Queries, shopID, transferID int64) (db.InventoryTransfer, []services.StockChangeResult, error)
+
+// settleInventoryTransfer receives or cancels the transfer named by the path
+func (h *Handler) settleInventoryTransfer(c *fiber.Ctx, settle settleTransferFunc, message string) error {
+	transfer, err := h.inventoryTransferFromPath(c)
+	if err != nil {
+		return err
+	}
+
+	var results []services.StockChangeResult
+	err = h.Repository.WithTx(c.Context(), func(q *db.Queries) error {
+		var err error
+		transfer, results, err = settle(c.Context(), q, transfer.ShopID, transfer.TransferID)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, services.ErrTransferNotInTransit) {
+			return api.BusinessLogicErrorResponse(c, "The transfer is no longer in transit")
+		}
+		zap.L().Error("settleInventoryTransfer: failed to settle transfer", zap.Int64("transfer_id", transfer.TransferID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to update inventory transfer")
+	}
+	h.queueStockChangeNotifications(c, results)
+
+	return h.inventoryTransferResponse(c, fiber.StatusOK, transfer, message)
+}
+
+// queueStockChangeNotifications notifies the subscribers of the variants stock
+// changes brought back in stock online
+func (h *Handler) queueStockChangeNotifications(c *fiber.Ctx, results []services.StockChangeResult) {
+	for _, result := range results {
+		h.queueBackInStockNotifications(c.Context(), result.Variant.ShopID, result.Variant.ProductVariationID, result.OnlineBefore, result.Variant.AvailableQuantity)
+	}
+}
+
+// inventoryTransferResponse responds with a transfer and its items
+func (h *Handler) inventoryTransferResponse(c *fiber.Ctx, status int, transfer db.InventoryTransfer, message string) error {
+	items, err := h.Repository.ListInventoryTransferItems(c.Context(), db.ListInventoryTransferItemsParams{
+		TransferID: transfer.TransferID,
+		ShopID:     transfer.ShopID,
+	})
+	if err != nil {
+		zap.L().Error("inventoryTransferResponse: failed to fetch transfer items", zap.Int64("transfer_id", transfer.TransferID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to fetch inventory transfer items")
+	}
+	return api.SuccessResponse(c, status, models.NewInventoryTransfer(transfer, items), message)
+}
+
+// inventoryTransferFromPath fetches the transfer named by the shop_id and transfer_id path parameters
+func (h *Handler) inventoryTransferFromPath(c *fiber.Ctx) (db.InventoryTransfer, error) {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return db.InventoryTransfer{}, err
+	}
+	transferID, err := api.ParseIDParameter(c, "transfer_id", "Transfer")
+	if err != nil {
+		return db.InventoryTransfer{}, err
+	}
+
+	transfer, err := h.Repository.GetInventoryTransfer(c.Context(), db.GetInventoryTransferParams{
+		TransferID: transferID,
+		ShopID:     shopID,
+	})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return db.InventoryTransfer{}, fiber.NewError(fiber.StatusNotFound, "Inventory transfer not found")
+		}
+		zap.L().Error("inventoryTransferFromPath: failed to fetch transfer", zap.Int64("transfer_id", transferID), zap.Error(err))
+		return db.InventoryTransfer{}, fiber.NewError(fiber.StatusInternalServerError, "Failed to fetch inventory transfer")
+	}
+	return transfer, nil
+}
@@ -0,0 +1,396 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/petrejonn/naytife/internal/api"
+	"github.com/petrejonn/naytife/internal/api/models"
+	"github.com/petrejonn/naytife/internal/db"
+	dberrors "github.com/petrejonn/naytife/internal/db/errors"
+	"github.com/petrejonn/naytife/internal/services"
+	"go.uber.org/zap"
+)
+
+// GetLocations lists the locations of a shop
+// @Summary      List locations
+// @Description  Get the places that hold stock for a shop, the default location first. Shops get a default location holding their stock the first time they list them.
+// @Tags         locations
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Success      200  {object}   models.SuccessResponse{data=[]models.Location} "Locations fetched successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/locations [get]
+func (h *Handler) GetLocations(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	if err := h.Repository.EnsureDefaultLocation(c.Context(), shopID); err != nil {
+		zap.L().Error("GetLocations: failed to create default location", zap.Int64("shop_id", shopID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to fetch locations")
+	}
+	locations, err := h.Repository.ListLocations(c.Context(), shopID)
+	if err != nil {
+		zap.L().Error("GetLocations: failed to fetch locations", zap.Int64("shop_id", shopID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to fetch locations")
+	}
+
+	response := make([]models.Location, len(locations))
+	for i, location := range locations {
+		response[i] = models.NewLocation(location)
+	}
+	return api.SuccessResponse(c, fiber.StatusOK, response, "Locations fetched successfully")
+}
+
+// GetLocation fetches a location
+// @Summary      Get a location
+// @Description  Get a place that holds stock
+// @Tags         locations
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        location_id path string true "Location ID"
+// @Success      200  {object}   models.SuccessResponse{data=models.Location} "Location fetched successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      404  {object}   models.ErrorResponse "Location not found"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/locations/{location_id} [get]
+func (h *Handler) GetLocation(c *fiber.Ctx) error {
+	location, err := h.locationFromPath(c)
+	if err != nil {
+		return err
+	}
+	return api.SuccessResponse(c, fiber.StatusOK, models.NewLocation(location), "Location fetched successfully")
+}
+
+// CreateLocation adds a location to a shop
+// @Summary      Create a location
+// @Description  Add a place that holds stock, e.g. a warehouse. Stock at locations that sell online counts towards the storefront's available quantity.
+// @Tags         locations
+// @Accept       json
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        location body models.LocationParams true "Location"
+// @Success      201  {object}   models.SuccessResponse{data=models.Location} "Location created successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      409  {object}   models.ErrorResponse "Location name already exists"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/locations [post]
+func (h *Handler) CreateLocation(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	var param models.LocationParams
+	if err := c.BodyParser(&param); err != nil {
+		return api.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body", nil)
+	}
+	validator := &models.XValidator{}
+	if errs := validator.Validate(&param); len(errs) > 0 {
+		return api.ErrorResponse(c, fiber.StatusBadRequest, models.FormatValidationErrors(errs), nil)
+	}
+	sellsOnline := param.SellsOnline == nil || *param.SellsOnline
+	if param.IsDefault && !sellsOnline {
+		return api.BusinessLogicErrorResponse(c, "The default location must sell online")
+	}
+
+	var location db.Location
+	err = h.Repository.WithTx(c.Context(), func(q *db.Queries) error {
+		// The default location is created first so that it keeps the shop's stock
+		if _, err := services.DefaultLocation(c.Context(), q, shopID); err != nil {
+			return err
+		}
+		var err error
+		location, err = q.CreateLocation(c.Context(), db.CreateLocationParams{
+			Name:        param.Name,
+			Address:     param.Address,
+			SellsOnline: sellsOnline,
+			ShopID:      shopID,
+		})
+		if err != nil || !param.IsDefault {
+			return err
+		}
+		location, err = makeDefaultLocation(c.Context(), q, location)
+		return err
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == dberrors.UniqueViolation {
+			return api.ErrorResponse(c, fiber.StatusConflict, "A location with this name already exists", nil)
+		}
+		zap.L().Error("CreateLocation: failed to create location", zap.Int64("shop_id", shopID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to create location")
+	}
+	return api.SuccessResponse(c, fiber.StatusCreated, models.NewLocation(location), "Location created successfully")
+}
+
+// UpdateLocation updates a location
+// @Summary      Update a location
+// @Description  Rename a location, change whether it sells online or make it the default. The default location always sells online and stays the default until another location takes its place.
+// @Tags         locations
+// @Accept       json
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        location_id path string true "Location ID"
+// @Param        location body models.LocationParams true "Location"
+// @Success      200  {object}   models.SuccessResponse{data=models.Location} "Location updated successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      404  {object}   models.ErrorResponse "Location not found"
+// @Failure      409  {object}   models.ErrorResponse "Location name already exists"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/locations/{location_id} [put]
+func (h *Handler) UpdateLocation(c *fiber.Ctx) error {
+	current, err := h.locationFromPath(c)
+	if err != nil {
+		return err
+	}
+	var param models.LocationParams
+	if err := c.BodyParser(&param); err != nil {
+		return api.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body", nil)
+	}
+	validator := &models.XValidator{}
+	if errs := validator.Validate(&param); len(errs) > 0 {
+		return api.ErrorResponse(c, fiber.StatusBadRequest, models.FormatValidationErrors(errs), nil)
+	}
+	sellsOnline := current.SellsOnline
+	if param.SellsOnline != nil {
+		sellsOnline = *param.SellsOnline
+	}
+	if (current.IsDefault || param.IsDefault) && !sellsOnline {
+		return api.BusinessLogicErrorResponse(c, "The default location must sell online")
+	}
+
+	var location db.Location
+	err = h.Repository.WithTx(c.Context(), func(q *db.Queries) error {
+		var err error
+		location, err = q.UpdateLocation(c.Context(), db.UpdateLocationParams{
+			LocationID:  current.LocationID,
+			ShopID:      current.ShopID,
+			Name:        param.Name,
+			Address:     param.Address,
+			SellsOnline: sellsOnline,
+		})
+		if err != nil {
+			return err
+		}
+		if param.IsDefault && !current.IsDefault {
+			if location, err = makeDefaultLocation(c.Context(), q, location); err != nil {
+				return err
+			}
+		}
+		if sellsOnline != current.SellsOnline {
+			if err := q.SyncShopAvailableQuantities(c.Context(), current.ShopID); err != nil {
+				return err
+			}
+			return q.SyncBundleAvailableQuantities(c.Context(), db.SyncBundleAvailableQuantitiesParams{ShopID: current.ShopID})
+		}
+		return nil
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == dberrors.UniqueViolation {
+			return api.ErrorResponse(c, fiber.StatusConflict, "A location with this name already exists", nil)
+		}
+		zap.L().Error("UpdateLocation: failed to update location", zap.Int64("location_id", current.LocationID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to update location")
+	}
+	return api.SuccessResponse(c, fiber.StatusOK, models.NewLocation(location), "Location updated successfully")
+}
+
+// DeleteLocation removes a location
+// @Summary      Delete a location
+// @Description  Remove a location that holds no stock and has none in transit. The default location cannot be deleted.
+// @Tags         locations
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        location_id path string true "Location ID"
+// @Success      200  {object}   models.SuccessResponse "Location deleted successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      404  {object}   models.ErrorResponse "Location not found"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/locations/{location_id} [delete]
+func (h *Handler) DeleteLocation(c *fiber.Ctx) error {
+	location, err := h.locationFromPath(c)
+	if err != nil {
+		return err
+	}
+	if location.IsDefault {
+		return api.BusinessLogicErrorResponse(c, "The default location cannot be deleted")
+	}
+
+	deleted, err := h.Repository.DeleteLocation(c.Context(), db.DeleteLocationParams{
+		LocationID: location.LocationID,
+		ShopID:     location.ShopID,
+	})
+	if err != nil {
+		zap.L().Error("DeleteLocation: failed to delete location", zap.Int64("location_id", location.LocationID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to delete location")
+	}
+	if deleted == 0 {
+		return api.BusinessLogicErrorResponse(c, "The location still holds stock; transfer it to another location first")
+	}
+	return api.SuccessResponse(c, fiber.StatusOK, nil, "Location deleted successfully")
+}
+
+// GetLocationInventory lists the stock held at a location
+// @Summary      List location inventory
+// @Description  Get the stock of every variant held at a location, including stock in transit to it
+// @Tags         locations
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        location_id path string true "Location ID"
+// @Param        limit query int false "Limit" default(20)
+// @Param        offset query int false "Offset" default(0)
+// @Success      200  {object}   models.SuccessResponse{data=[]models.LocationInventoryLevel} "Location inventory fetched successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      404  {object}   models.ErrorResponse "Location not found"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/locations/{location_id}/inventory [get]
+func (h *Handler) GetLocationInventory(c *fiber.Ctx) error {
+	location, err := h.locationFromPath(c)
+	if err != nil {
+		return err
+	}
+	limit, offset, err := api.ParsePaginationParams(c)
+	if err != nil {
+		return err
+	}
+
+	levels, err := h.Repository.ListLocationInventoryLevels(c.Context(), db.ListLocationInventoryLevelsParams{
+		LocationID: location.LocationID,
+		ShopID:     location.ShopID,
+		Limit:      int32(limit),
+		Offset:     int32(offset),
+	})
+	if err != nil {
+		zap.L().Error("GetLocationInventory: failed to fetch inventory levels", zap.Int64("location_id", location.LocationID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to fetch location inventory")
+	}
+	total, err := h.Repository.CountLocationInventoryLevels(c.Context(), db.CountLocationInventoryLevelsParams{
+		LocationID: location.LocationID,
+		ShopID:     location.ShopID,
+	})
+	if err != nil {
+		zap.L().Error("GetLocationInventory: failed to count inventory levels", zap.Int64("location_id", location.LocationID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to count location inventory")
+	}
+
+	response := make([]models.LocationInventoryLevel, len(levels))
+	for i, level := range levels {
+		response[i] = models.LocationInventoryLevel{
+			VariantID:    level.ProductVariationID,
+			ProductTitle: level.ProductTitle,
+			VariantTitle: level.VariantTitle,
+			SKU:          level.Sku,
+			Available:    level.Available,
+			Incoming:     level.Incoming,
+			UpdatedAt:    level.UpdatedAt.Time,
+		}
+	}
+	page := (offset / limit) + 1
+	return api.PaginatedSuccessResponse(c, fiber.StatusOK, response, total, page, limit, "Location inventory fetched successfully")
+}
+
+// GetVariantInventoryLevels lists the stock of a variant at every location
+// @Summary      Get variant stock by location
+// @Description  Get the stock of a product variant at every location of the shop, including stock in transit
+// @Tags         inventory
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        variant_id path string true "Variant ID"
+// @Success      200  {object}   models.SuccessResponse{data=[]models.VariantInventoryLevel} "Variant stock fetched successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      404  {object}   models.ErrorResponse "Variant not found"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/inventory/variants/{variant_id}/levels [get]
+func (h *Handler) GetVariantInventoryLevels(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	variantID, err := api.ParseIDParameter(c, "variant_id", "Variant")
+	if err != nil {
+		return err
+	}
+
+	if _, err := h.Repository.GetProductVariation(c.Context(), db.GetProductVariationParams{
+		ProductVariationID: variantID,
+		ShopID:             shopID,
+	}); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return api.NotFoundErrorResponse(c, "Product variant")
+		}
+		return api.SystemErrorResponse(c, err, "Failed to fetch product variant")
+	}
+	if err := h.Repository.EnsureDefaultLocation(c.Context(), shopID); err != nil {
+		zap.L().Error("GetVariantInventoryLevels: failed to create default location", zap.Int64("shop_id", shopID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to fetch variant stock")
+	}
+	levels, err := h.Repository.ListVariantInventoryLevels(c.Context(), db.ListVariantInventoryLevelsParams{
+		ProductVariationID: variantID,
+		ShopID:             shopID,
+	})
+	if err != nil {
+		zap.L().Error("GetVariantInventoryLevels: failed to fetch inventory levels", zap.Int64("variant_id", variantID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to fetch variant stock")
+	}
+
+	response := make([]models.VariantInventoryLevel, len(levels))
+	for i, level := range levels {
+		response[i] = models.VariantInventoryLevel{
+			LocationID:   level.LocationID,
+			LocationName: level.LocationName,
+			SellsOnline:  level.SellsOnline,
+			Available:    level.Available,
+			Incoming:     level.Incoming,
+		}
+	}
+	return api.SuccessResponse(c, fiber.StatusOK, response, "Variant stock fetched successfully")
+}
+
+// locationFromPath fetches the location named by the shop_id and location_id path parameters
+func (h *Handler) locationFromPath(c *fiber.Ctx) (db.Location, error) {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return db.Location{}, err
+	}
+	locationID, err := api.ParseIDParameter(c, "location_id", "Location")
+	if err != nil {
+		return db.Location{}, err
+	}
+
+	location, err := h.Repository.GetLocation(c.Context(), db.GetLocationParams{
+		LocationID: locationID,
+		ShopID:     shopID,
+	})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return db.Location{}, fiber.NewError(fiber.StatusNotFound, "Location not found")
+		}
+		zap.L().Error("locationFromPath: failed to fetch location", zap.Int64("location_id", locationID), zap.Error(err))
+		return db.Location{}, fiber.NewError(fiber.StatusInternalServerError, "Failed to fetch location")
+	}
+	return location, nil
+}
+
+// makeDefaultLocation moves the default of a shop to the given location
+func makeDefaultLocation(ctx context.Context, q *db.Queries, location db.Location) (db.Location, error) {
+	if err := q.ClearDefaultLocation(ctx, location.ShopID); err != nil {
+		return db.Location{}, err
+	}
+	return q.MarkDefaultLocation(ctx, db.MarkDefaultLocationParams{
+		LocationID: location.LocationID,
+		ShopID:     location.ShopID,
+	})
+}
@@ -0,0 +1,224 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/petrejonn/naytife/internal/api"
+	"github.com/petrejonn/naytife/internal/api/models"
+	"github.com/petrejonn/naytife/internal/db"
+	"github.com/petrejonn/naytife/internal/services"
+	"go.uber.org/zap"
+)
+
+// GetLoyaltyProgram fetches the loyalty program of a shop
+// @Summary      Get loyalty program
+// @Description  Get the loyalty points settings of a shop. Shops that never set one up get a disabled program with the default rates.
+// @Tags         loyalty
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Success      200  {object}   models.SuccessResponse{data=models.LoyaltyProgram} "Loyalty program fetched successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/loyalty-program [get]
+func (h *Handler) GetLoyaltyProgram(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	program, err := services.LoyaltyProgram(c.Context(), h.Repository, shopID)
+	if err != nil {
+		zap.L().Error("GetLoyaltyProgram: failed to fetch loyalty program", zap.Int64("shop_id", shopID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to fetch loyalty program")
+	}
+	return api.SuccessResponse(c, fiber.StatusOK, models.NewLoyaltyProgram(program), "Loyalty program fetched successfully")
+}
+
+// UpdateLoyaltyProgram sets up the loyalty program of a shop
+// @Summary      Update loyalty program
+// @Description  Set the earn and burn rates, the minimum redemption and the expiry of loyalty points. Customers earn points when their orders are completed and redeem them as a discount at checkout.
+// @Tags         loyalty
+// @Accept       json
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        program body models.LoyaltyProgramParams true "Loyalty program"
+// @Success      200  {object}   models.SuccessResponse{data=models.LoyaltyProgram} "Loyalty program updated successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/loyalty-program [put]
+func (h *Handler) UpdateLoyaltyProgram(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	var param models.LoyaltyProgramParams
+	if err := c.BodyParser(&param); err != nil {
+		return api.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body", nil)
+	}
+	validator := &models.XValidator{}
+	if errs := validator.Validate(&param); len(errs) > 0 {
+		return api.ErrorResponse(c, fiber.StatusBadRequest, models.FormatValidationErrors(errs), nil)
+	}
+
+	program, err := h.Repository.UpsertLoyaltyProgram(c.Context(), db.UpsertLoyaltyProgramParams{
+		ShopID:          shopID,
+		Enabled:         param.Enabled,
+		EarnRate:        services.FloatToNumeric(param.EarnRate),
+		BurnRate:        services.FloatToNumeric(param.BurnRate),
+		MinRedeemPoints: param.MinRedeemPoints,
+		ExpiryDays:      param.ExpiryDays,
+	})
+	if err != nil {
+		zap.L().Error("UpdateLoyaltyProgram: failed to save loyalty program", zap.Int64("shop_id", shopID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to update loyalty program")
+	}
+	return api.SuccessResponse(c, fiber.StatusOK, models.NewLoyaltyProgram(program), "Loyalty program updated successfully")
+}
+
+// GetCustomerLoyalty fetches the loyalty points balance of a customer
+// @Summary      Get customer loyalty points
+// @Description  Get the points balance of a customer, what it is worth as a discount and when it expires
+// @Tags         loyalty
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        customer_id path string true "Customer ID"
+// @Success      200  {object}   models.SuccessResponse{data=models.LoyaltyBalance} "Loyalty points fetched successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      404  {object}   models.ErrorResponse "Customer not found"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/customers/{customer_id}/loyalty [get]
+func (h *Handler) GetCustomerLoyalty(c *fiber.Ctx) error {
+	shopID, customerID, err := h.shopCustomerFromPath(c)
+	if err != nil {
+		return err
+	}
+	shop, err := h.Repository.GetShop(c.Context(), shopID)
+	if err != nil {
+		zap.L().Error("GetCustomerLoyalty: failed to fetch shop", zap.Int64("shop_id", shopID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to fetch shop")
+	}
+	program, err := services.LoyaltyProgram(c.Context(), h.Repository, shopID)
+	if err != nil {
+		zap.L().Error("GetCustomerLoyalty: failed to fetch loyalty program", zap.Int64("shop_id", shopID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to fetch loyalty program")
+	}
+
+	balance, err := services.CustomerLoyaltyBalance(c.Context(), h.Repository, program, customerID, shop.CurrencyCode)
+	if err != nil {
+		zap.L().Error("GetCustomerLoyalty: failed to fetch balance", zap.String("customer_id", customerID.String()), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to fetch loyalty points")
+	}
+
+	return api.SuccessResponse(c, fiber.StatusOK, models.LoyaltyBalance{
+		CustomerID:   customerID,
+		Points:       balance.Points,
+		Value:        balance.Value,
+		CurrencyCode: shop.CurrencyCode,
+		ExpiresAt:    balance.ExpiresAt,
+	}, "Loyalty points fetched successfully")
+}
+
+// GetCustomerLoyaltyTransactions lists the loyalty points ledger of a customer
+// @Summary      List customer loyalty transactions
+// @Description  Get the points ledger of a customer, newest first
+// @Tags         loyalty
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        customer_id path string true "Customer ID"
+// @Param        limit query int false "Limit" default(20)
+// @Param        offset query int false "Offset" default(0)
+// @Success      200  {object}   models.SuccessResponse{data=[]models.LoyaltyTransaction} "Loyalty transactions fetched successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      404  {object}   models.ErrorResponse "Customer not found"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/customers/{customer_id}/loyalty/transactions [get]
+func (h *Handler) GetCustomerLoyaltyTransactions(c *fiber.Ctx) error {
+	shopID, customerID, err := h.shopCustomerFromPath(c)
+	if err != nil {
+		return err
+	}
+	limit, offset, err := api.ParsePaginationParams(c)
+	if err != nil {
+		return err
+	}
+
+	entries, err := h.Repository.ListLoyaltyTransactions(c.Context(), db.ListLoyaltyTransactionsParams{
+		ShopCustomerID: customerID,
+		ShopID:         shopID,
+		Limit:          int32(limit),
+		Offset:         int32(offset),
+	})
+	if err != nil {
+		zap.L().Error("GetCustomerLoyaltyTransactions: failed to fetch transactions", zap.String("customer_id", customerID.String()), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to fetch loyalty transactions")
+	}
+	total, err := h.Repository.CountLoyaltyTransactions(c.Context(), db.CountLoyaltyTransactionsParams{
+		ShopCustomerID: customerID,
+		ShopID:         shopID,
+	})
+	if err != nil {
+		zap.L().Error("GetCustomerLoyaltyTransactions: failed to count transactions", zap.String("customer_id", customerID.String()), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to count loyalty transactions")
+	}
+
+	response := make([]models.LoyaltyTransaction, len(entries))
+	for i, entry := range entries {
+		response[i] = models.NewLoyaltyTransaction(entry)
+	}
+	page := (offset / limit) + 1
+	return api.PaginatedSuccessResponse(c, fiber.StatusOK, response, total, page, limit, "Loyalty transactions fetched successfully")
+}
+
+// AdjustCustomerLoyalty gives or takes loyalty points
+// @Summary      Adjust customer loyalty points
+// @Description  Give points to a customer, or take them with negative points. The entry records the staff member who made it.
+// @Tags         loyalty
+// @Accept       json
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        customer_id path string true "Customer ID"
+// @Param        adjustment body models.LoyaltyAdjustParams true "Points adjustment"
+// @Success      201  {object}   models.SuccessResponse{data=models.LoyaltyTransaction} "Loyalty points adjusted successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      404  {object}   models.ErrorResponse "Customer not found"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/customers/{customer_id}/loyalty/adjustments [post]
+func (h *Handler) AdjustCustomerLoyalty(c *fiber.Ctx) error {
+	shopID, customerID, err := h.shopCustomerFromPath(c)
+	if err != nil {
+		return err
+	}
+	var param models.LoyaltyAdjustParams
+	if err := c.BodyParser(&param); err != nil {
+		return api.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body", nil)
+	}
+	validator := &models.XValidator{}
+	if errs := validator.Validate(&param); len(errs) > 0 {
+		return api.ErrorResponse(c, fiber.StatusBadRequest, models.FormatValidationErrors(errs), nil)
+	}
+	program, err := services.LoyaltyProgram(c.Context(), h.Repository, shopID)
+	if err != nil {
+		zap.L().Error("AdjustCustomerLoyalty: failed to fetch loyalty program", zap.Int64("shop_id", shopID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to fetch loyalty program")
+	}
+
+	var entry db.LoyaltyTransaction
+	err = h.Repository.WithTx(c.Context(), func(q *db.Queries) error {
+		var err error
+		entry, err = services.AdjustLoyaltyPoints(c.Context(), q, program, customerID, db.LoyaltyTransactionKindAdjust, param.Points, nil, &param.Note, requestUser(c))
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, services.ErrInsufficientPoints) {
+			return api.BusinessLogicErrorResponse(c, err.Error())
+		}
+		zap.L().Error("AdjustCustomerLoyalty: failed to adjust points", zap.String("customer_id", customerID.String()), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to adjust loyalty points")
+	}
+	return api.SuccessResponse(c, fiber.StatusCreated, models.NewLoyaltyTransaction(entry), "Loyalty points adjusted successfully")
+}
@@ -0,0 +1,665 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/petrejonn/naytife/internal/api"
+	"github.com/petrejonn/naytife/internal/api/models"
+	"github.com/petrejonn/naytife/internal/db"
+	"github.com/petrejonn/naytife/internal/db/errors"
+	"go.uber.org/zap"
+)
+
+var (
+	metafieldKeyPattern   = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+	metafieldColorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+)
+
+// CreateMetafieldDefinition defines the type and validations of a metafield
+// @Summary Create a metafield definition
+// @Description Define the type and validations of a namespace and key for one owner type. Values set afterwards are validated against the definition.
+// @Tags Metafield
+// @Accept json
+// @Produce json
+// @Param shop_id path string true "Shop ID"
+// @Param definition body models.MetafieldDefinitionCreateParams true "Metafield definition"
+// @Success 201 {object} models.SuccessResponse{data=models.MetafieldDefinition} "Metafield definition created successfully"
+// @Failure 400 {object} models.ErrorResponse "Bad request"
+// @Failure 409 {object} models.ErrorResponse "Metafield definition already exists"
+// @Failure 422 {object} models.ErrorResponse "Existing values have a different type"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Security OAuth2AccessCode
+// @Router /shops/{shop_id}/metafield-definitions [post]
+func (h *Handler) CreateMetafieldDefinition(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+
+	var param models.MetafieldDefinitionCreateParams
+	if err := c.BodyParser(&param); err != nil {
+		zap.L().Warn("CreateMetafieldDefinition: failed to parse request body", zap.Int64("shop_id", shopID), zap.Error(err))
+		return api.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body", nil)
+	}
+	validator := &models.XValidator{}
+	if errs := validator.Validate(&param); len(errs) > 0 {
+		errMsgs := models.FormatValidationErrors(errs)
+		zap.L().Warn("CreateMetafieldDefinition: request validation failed", zap.Int64("shop_id", shopID), zap.String("errors", errMsgs))
+		return &fiber.Error{
+			Code:    fiber.ErrBadRequest.Code,
+			Message: errMsgs,
+		}
+	}
+	if !metafieldKeyPattern.MatchString(param.Namespace) || !metafieldKeyPattern.MatchString(param.Key) {
+		return api.ErrorResponse(c, fiber.StatusBadRequest, "Namespace and key may only contain letters, digits, underscores and dashes", nil)
+	}
+
+	validations := models.MetafieldValidations{}
+	if param.Validations != nil {
+		validations = *param.Validations
+	}
+	if err := checkMetafieldValidations(validations); err != nil {
+		return api.ErrorResponse(c, fiber.StatusBadRequest, err.Error(), nil)
+	}
+	validationsJSON, err := json.Marshal(validations)
+	if err != nil {
+		return api.SystemErrorResponse(c, err, "Failed to encode validations")
+	}
+
+	conflicting, err := h.Repository.CountMetafieldsWithOtherType(c.Context(), db.CountMetafieldsWithOtherTypeParams{
+		OwnerType: param.OwnerType,
+		Namespace: param.Namespace,
+		Key:       param.Key,
+		Type:      param.Type,
+		ShopID:    shopID,
+	})
+	if err != nil {
+		zap.L().Error("CreateMetafieldDefinition: failed to check existing values", zap.Int64("shop_id", shopID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to check existing metafields")
+	}
+	if conflicting > 0 {
+		return api.BusinessLogicErrorResponse(c, fmt.Sprintf("%d existing metafields use a different type for %s.%s", conflicting, param.Namespace, param.Key))
+	}
+
+	definition, err := h.Repository.CreateMetafieldDefinition(c.Context(), db.CreateMetafieldDefinitionParams{
+		OwnerType:   param.OwnerType,
+		Namespace:   param.Namespace,
+		Key:         param.Key,
+		Name:        param.Name,
+		Description: param.Description,
+		Type:        param.Type,
+		Validations: validationsJSON,
+		ShopID:      shopID,
+	})
+	if err != nil {
+		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == errors.UniqueViolation {
+			return api.ErrorResponse(c, fiber.StatusConflict, "Metafield definition already exists", nil)
+		}
+		zap.L().Error("CreateMetafieldDefinition: failed to create definition", zap.Int64("shop_id", shopID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to create metafield definition")
+	}
+
+	return api.SuccessResponse(c, fiber.StatusCreated, metafieldDefinitionResponse(definition), "Metafield definition created successfully")
+}
+
+// GetMetafieldDefinitions lists the metafield definitions of a shop
+// @Summary List metafield definitions
+// @Description Get the metafield definitions of a shop, optionally for a single owner type
+// @Tags Metafield
+// @Produce json
+// @Param shop_id path string true "Shop ID"
+// @Param owner_type query string false "Owner type" Enums(product, product_variation, category, shop, customer)
+// @Success 200 {object} models.SuccessResponse{data=[]models.MetafieldDefinition} "Metafield definitions fetched successfully"
+// @Failure 400 {object} models.ErrorResponse "Bad request"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Security OAuth2AccessCode
+// @Router /shops/{shop_id}/metafield-definitions [get]
+func (h *Handler) GetMetafieldDefinitions(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+
+	var ownerType db.NullMetafieldOwnerType
+	if v := c.Query("owner_type"); v != "" {
+		switch db.MetafieldOwnerType(v) {
+		case db.MetafieldOwnerTypeProduct, db.MetafieldOwnerTypeProductVariation, db.MetafieldOwnerTypeCategory,
+			db.MetafieldOwnerTypeShop, db.MetafieldOwnerTypeCustomer:
+			ownerType = db.NullMetafieldOwnerType{MetafieldOwnerType: db.MetafieldOwnerType(v), Valid: true}
+		default:
+			return api.ErrorResponse(c, fiber.StatusBadRequest, "Invalid owner type", nil)
+		}
+	}
+
+	definitions, err := h.Repository.ListMetafieldDefinitions(c.Context(), db.ListMetafieldDefinitionsParams{
+		ShopID:    shopID,
+		OwnerType: ownerType,
+	})
+	if err != nil {
+		zap.L().Error("GetMetafieldDefinitions: failed to fetch definitions", zap.Int64("shop_id", shopID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to fetch metafield definitions")
+	}
+
+	response := make([]models.MetafieldDefinition, len(definitions))
+	for i, definition := range definitions {
+		response[i] = metafieldDefinitionResponse(definition)
+	}
+
+	return api.SuccessResponse(c, fiber.StatusOK, response, "Metafield definitions fetched successfully")
+}
+
+// GetMetafieldDefinition fetches a metafield definition
+// @Summary Get a metafield definition
+// @Description Get a metafield definition by ID
+// @Tags Metafield
+// @Produce json
+// @Param shop_id path string true "Shop ID"
+// @Param definition_id path string true "Metafield definition ID"
+// @Success 200 {object} models.SuccessResponse{data=models.MetafieldDefinition} "Metafield definition fetched successfully"
+// @Failure 404 {object} models.ErrorResponse "Metafield definition not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Security OAuth2AccessCode
+// @Router /shops/{shop_id}/metafield-definitions/{definition_id} [get]
+func (h *Handler) GetMetafieldDefinition(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	definitionID, err := api.ParseIDParameter(c, "definition_id", "Metafield definition")
+	if err != nil {
+		return err
+	}
+
+	definition, err := h.Repository.GetMetafieldDefinition(c.Context(), db.GetMetafieldDefinitionParams{
+		MetafieldDefinitionID: definitionID,
+		ShopID:                shopID,
+	})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return api.NotFoundErrorResponse(c, "Metafield definition")
+		}
+		zap.L().Error("GetMetafieldDefinition: failed to fetch definition", zap.Int64("shop_id", shopID), zap.Int64("definition_id", definitionID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to fetch metafield definition")
+	}
+
+	return api.SuccessResponse(c, fiber.StatusOK, metafieldDefinitionResponse(definition), "Metafield definition fetched successfully")
+}
+
+// UpdateMetafieldDefinition updates a metafield definition
+// @Summary Update a metafield definition
+// @Description Update the name, description or validations of a metafield definition. The owner type, namespace, key and type cannot be changed.
+// @Tags Metafield
+// @Accept json
+// @Produce json
+// @Param shop_id path string true "Shop ID"
+// @Param definition_id path string true "Metafield definition ID"
+// @Param definition body models.MetafieldDefinitionUpdateParams true "Metafield definition"
+// @Success 200 {object} models.SuccessResponse{data=models.MetafieldDefinition} "Metafield definition updated successfully"
+// @Failure 400 {object} models.ErrorResponse "Bad request"
+// @Failure 404 {object} models.ErrorResponse "Metafield definition not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Security OAuth2AccessCode
+// @Router /shops/{shop_id}/metafield-definitions/{definition_id} [put]
+func (h *Handler) UpdateMetafieldDefinition(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	definitionID, err := api.ParseIDParameter(c, "definition_id", "Metafield definition")
+	if err != nil {
+		return err
+	}
+
+	var param models.MetafieldDefinitionUpdateParams
+	if err := c.BodyParser(&param); err != nil {
+		zap.L().Warn("UpdateMetafieldDefinition: failed to parse request body", zap.Int64("shop_id", shopID), zap.Error(err))
+		return api.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body", nil)
+	}
+	validator := &models.XValidator{}
+	if errs := validator.Validate(&param); len(errs) > 0 {
+		return &fiber.Error{
+			Code:    fiber.ErrBadRequest.Code,
+			Message: models.FormatValidationErrors(errs),
+		}
+	}
+
+	var validationsJSON []byte
+	if param.Validations != nil {
+		if err := checkMetafieldValidations(*param.Validations); err != nil {
+			return api.ErrorResponse(c, fiber.StatusBadRequest, err.Error(), nil)
+		}
+		validationsJSON, err = json.Marshal(param.Validations)
+		if err != nil {
+			return api.SystemErrorResponse(c, err, "Failed to encode validations")
+		}
+	}
+
+	definition, err := h.Repository.UpdateMetafieldDefinition(c.Context(), db.UpdateMetafieldDefinitionParams{
+		Name:                  param.Name,
+		Description:           param.Description,
+		Validations:           validationsJSON,
+		MetafieldDefinitionID: definitionID,
+		ShopID:                shopID,
+	})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return api.NotFoundErrorResponse(c, "Metafield definition")
+		}
+		zap.L().Error("UpdateMetafieldDefinition: failed to update definition", zap.Int64("shop_id", shopID), zap.Int64("definition_id", definitionID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to update metafield definition")
+	}
+
+	return api.SuccessResponse(c, fiber.StatusOK, metafieldDefinitionResponse(definition), "Metafield definition updated successfully")
+}
+
+// DeleteMetafieldDefinition deletes a metafield definition
+// @Summary Delete a metafield definition
+// @Description Delete a metafield definition. Values already set are kept but are no longer validated.
+// @Tags Metafield
+// @Produce json
+// @Param shop_id path string true "Shop ID"
+// @Param definition_id path string true "Metafield definition ID"
+// @Success 200 {object} models.SuccessResponse{data=nil} "Metafield definition deleted successfully"
+// @Failure 404 {object} models.ErrorResponse "Metafield definition not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Security OAuth2AccessCode
+// @Router /shops/{shop_id}/metafield-definitions/{definition_id} [delete]
+func (h *Handler) DeleteMetafieldDefinition(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	definitionID, err := api.ParseIDParameter(c, "definition_id", "Metafield definition")
+	if err != nil {
+		return err
+	}
+
+	deleted, err := h.Repository.DeleteMetafieldDefinition(c.Context(), db.DeleteMetafieldDefinitionParams{
+		MetafieldDefinitionID: definitionID,
+		ShopID:                shopID,
+	})
+	if err != nil {
+		zap.L().Error("DeleteMetafieldDefinition: failed to delete definition", zap.Int64("shop_id", shopID), zap.Int64("definition_id", definitionID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to delete metafield definition")
+	}
+	if deleted == 0 {
+		return api.NotFoundErrorResponse(c, "Metafield definition")
+	}
+
+	return api.SuccessResponse(c, fiber.StatusOK, nil, "Metafield definition deleted successfully")
+}
+
+// GetMetafields lists the metafields of the owner identified by idParam
+// @Summary List metafields
+// @Description Get the metafields of a product, variant, category, shop or customer, optionally for a single namespace
+// @Tags Metafield
+// @Produce json
+// @Param shop_id path string true "Shop ID"
+// @Param owner_id path string true "Product, variant, category or customer ID"
+// @Param namespace query string false "Namespace"
+// @Success 200 {object} models.SuccessResponse{data=[]models.Metafield} "Metafields fetched successfully"
+// @Failure 400 {object} models.ErrorResponse "Bad request"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Security OAuth2AccessCode
+// @Router /shops/{shop_id}/products/{owner_id}/metafields [get]
+// @Router /shops/{shop_id}/variants/{owner_id}/metafields [get]
+// @Router /shops/{shop_id}/categories/{owner_id}/metafields [get]
+// @Router /shops/{shop_id}/customers/{owner_id}/metafields [get]
+// @Router /shops/{shop_id}/metafields [get]
+func (h *Handler) GetMetafields(ownerType db.MetafieldOwnerType, idParam string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+		if err != nil {
+			return err
+		}
+		ownerID, err := parseMetafieldOwnerID(ownerType, c.Params(idParam))
+		if err != nil {
+			return api.ErrorResponse(c, fiber.StatusBadRequest, err.Error(), nil)
+		}
+
+		var namespace *string
+		if v := c.Query("namespace"); v != "" {
+			namespace = &v
+		}
+
+		metafields, err := h.Repository.GetMetafields(c.Context(), db.GetMetafieldsParams{
+			OwnerType: ownerType,
+			OwnerID:   ownerID,
+			ShopID:    shopID,
+			Namespace: namespace,
+		})
+		if err != nil {
+			zap.L().Error("GetMetafields: failed to fetch metafields", zap.Int64("shop_id", shopID), zap.String("owner_type", string(ownerType)), zap.String("owner_id", ownerID), zap.Error(err))
+			return api.SystemErrorResponse(c, err, "Failed to fetch metafields")
+		}
+
+		response := make([]models.Metafield, len(metafields))
+		for i, metafield := range metafields {
+			response[i] = metafieldResponse(metafield)
+		}
+
+		return api.SuccessResponse(c, fiber.StatusOK, response, "Metafields fetched successfully")
+	}
+}
+
+// SetMetafield creates or replaces a metafield of the owner identified by idParam
+// @Summary Set a metafield
+// @Description Create or replace a metafield value. When a definition exists for the namespace and key the value must match its type and validations.
+// @Tags Metafield
+// @Accept json
+// @Produce json
+// @Param shop_id path string true "Shop ID"
+// @Param owner_id path string true "Product, variant, category or customer ID"
+// @Param metafield body models.MetafieldSetParams true "Metafield"
+// @Success 200 {object} models.SuccessResponse{data=models.Metafield} "Metafield saved successfully"
+// @Failure 400 {object} models.ErrorResponse "Bad request"
+// @Failure 404 {object} models.ErrorResponse "Owner not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Security OAuth2AccessCode
+// @Router /shops/{shop_id}/products/{owner_id}/metafields [put]
+// @Router /shops/{shop_id}/variants/{owner_id}/metafields [put]
+// @Router /shops/{shop_id}/categories/{owner_id}/metafields [put]
+// @Router /shops/{shop_id}/customers/{owner_id}/metafields [put]
+// @Router /shops/{shop_id}/metafields [put]
+func (h *Handler) SetMetafield(ownerType db.MetafieldOwnerType, idParam string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+		if err != nil {
+			return err
+		}
+		ownerID, err := parseMetafieldOwnerID(ownerType, c.Params(idParam))
+		if err != nil {
+			return api.ErrorResponse(c, fiber.StatusBadRequest, err.Error(), nil)
+		}
+
+		var param models.MetafieldSetParams
+		if err := c.BodyParser(&param); err != nil {
+			zap.L().Warn("SetMetafield: failed to parse request body", zap.Int64("shop_id", shopID), zap.Error(err))
+			return api.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body", nil)
+		}
+		validator := &models.XValidator{}
+		if errs := validator.Validate(&param); len(errs) > 0 {
+			return &fiber.Error{
+				Code:    fiber.ErrBadRequest.Code,
+				Message: models.FormatValidationErrors(errs),
+			}
+		}
+		if !metafieldKeyPattern.MatchString(param.Namespace) || !metafieldKeyPattern.MatchString(param.Key) {
+			return api.ErrorResponse(c, fiber.StatusBadRequest, "Namespace and key may only contain letters, digits, underscores and dashes", nil)
+		}
+
+		exists, err := h.Repository.MetafieldOwnerExists(c.Context(), db.MetafieldOwnerExistsParams{
+			OwnerType: ownerType,
+			OwnerID:   ownerID,
+			ShopID:    shopID,
+		})
+		if err != nil {
+			zap.L().Error("SetMetafield: failed to check owner", zap.Int64("shop_id", shopID), zap.String("owner_type", string(ownerType)), zap.String("owner_id", ownerID), zap.Error(err))
+			return api.SystemErrorResponse(c, err, "Failed to check metafield owner")
+		}
+		if !exists {
+			return api.NotFoundErrorResponse(c, metafieldOwnerNames[ownerType])
+		}
+
+		validations := models.MetafieldValidations{}
+		definition, err := h.Repository.GetMetafieldDefinitionByKey(c.Context(), db.GetMetafieldDefinitionByKeyParams{
+			OwnerType: ownerType,
+			Namespace: param.Namespace,
+			Key:       param.Key,
+			ShopID:    shopID,
+		})
+		switch {
+		case err == nil:
+			if param.Type != "" && param.Type != definition.Type {
+				return api.ErrorResponse(c, fiber.StatusBadRequest, fmt.Sprintf("%s.%s is defined as %s", param.Namespace, param.Key, definition.Type), nil)
+			}
+			param.Type = definition.Type
+			if err := json.Unmarshal(definition.Validations, &validations); err != nil {
+				zap.L().Warn("SetMetafield: failed to decode validations", zap.Int64("definition_id", definition.MetafieldDefinitionID), zap.Error(err))
+			}
+		case err == pgx.ErrNoRows:
+			if param.Type == "" {
+				return api.ErrorResponse(c, fiber.StatusBadRequest, "Type is required for metafields without a definition", nil)
+			}
+		default:
+			zap.L().Error("SetMetafield: failed to fetch definition", zap.Int64("shop_id", shopID), zap.Error(err))
+			return api.SystemErrorResponse(c, err, "Failed to fetch metafield definition")
+		}
+
+		if err := validateMetafieldValue(param.Type, param.Value, validations); err != nil {
+			return api.ErrorResponse(c, fiber.StatusBadRequest, fmt.Sprintf("Invalid value for %s.%s: %s", param.Namespace, param.Key, err.Error()), nil)
+		}
+
+		metafield, err := h.Repository.UpsertMetafield(c.Context(), db.UpsertMetafieldParams{
+			OwnerType: ownerType,
+			OwnerID:   ownerID,
+			Namespace: param.Namespace,
+			Key:       param.Key,
+			Type:      param.Type,
+			Value:     param.Value,
+			ShopID:    shopID,
+		})
+		if err != nil {
+			zap.L().Error("SetMetafield: failed to save metafield", zap.Int64("shop_id", shopID), zap.String("owner_type", string(ownerType)), zap.String("owner_id", ownerID), zap.Error(err))
+			return api.SystemErrorResponse(c, err, "Failed to save metafield")
+		}
+
+		return api.SuccessResponse(c, fiber.StatusOK, metafieldResponse(metafield), "Metafield saved successfully")
+	}
+}
+
+// DeleteMetafield deletes a metafield of the owner identified by idParam
+// @Summary Delete a metafield
+// @Description Delete a metafield value
+// @Tags Metafield
+// @Produce json
+// @Param shop_id path string true "Shop ID"
+// @Param owner_id path string true "Product, variant, category or customer ID"
+// @Param namespace path string true "Namespace"
+// @Param key path string true "Key"
+// @Success 200 {object} models.SuccessResponse{data=nil} "Metafield deleted successfully"
+// @Failure 404 {object} models.ErrorResponse "Metafield not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Security OAuth2AccessCode
+// @Router /shops/{shop_id}/products/{owner_id}/metafields/{namespace}/{key} [delete]
+// @Router /shops/{shop_id}/variants/{owner_id}/metafields/{namespace}/{key} [delete]
+// @Router /shops/{shop_id}/categories/{owner_id}/metafields/{namespace}/{key} [delete]
+// @Router /shops/{shop_id}/customers/{owner_id}/metafields/{namespace}/{key} [delete]
+// @Router /shops/{shop_id}/metafields/{namespace}/{key} [delete]
+func (h *Handler) DeleteMetafield(ownerType db.MetafieldOwnerType, idParam string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+		if err != nil {
+			return err
+		}
+		ownerID, err := parseMetafieldOwnerID(ownerType, c.Params(idParam))
+		if err != nil {
+			return api.ErrorResponse(c, fiber.StatusBadRequest, err.Error(), nil)
+		}
+
+		deleted, err := h.Repository.DeleteMetafield(c.Context(), db.DeleteMetafieldParams{
+			OwnerType: ownerType,
+			OwnerID:   ownerID,
+			Namespace: c.Params("namespace"),
+			Key:       c.Params("key"),
+			ShopID:    shopID,
+		})
+		if err != nil {
+			zap.L().Error("DeleteMetafield: failed to delete metafield", zap.Int64("shop_id", shopID), zap.String("owner_type", string(ownerType)), zap.String("owner_id", ownerID), zap.Error(err))
+			return api.SystemErrorResponse(c, err, "Failed to delete metafield")
+		}
+		if deleted == 0 {
+			return api.NotFoundErrorResponse(c, "Metafield")
+		}
+
+		return api.SuccessResponse(c, fiber.StatusOK, nil, "Metafield deleted successfully")
+	}
+}
+
+var metafieldOwnerNames = map[db.MetafieldOwnerType]string{
+	db.MetafieldOwnerTypeProduct:          "Product",
+	db.MetafieldOwnerTypeProductVariation: "Product variant",
+	db.MetafieldOwnerTypeCategory:         "Category",
+	db.MetafieldOwnerTypeShop:             "Shop",
+	db.MetafieldOwnerTypeCustomer:         "Customer",
+}
+
+// parseMetafieldOwnerID returns the canonical string form of an owner ID so
+// that the same owner always maps to the same metafield rows
+func parseMetafieldOwnerID(ownerType db.MetafieldOwnerType, raw string) (string, error) {
+	if ownerType == db.MetafieldOwnerTypeCustomer {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return "", fmt.Errorf("invalid customer ID")
+		}
+		return id.String(), nil
+	}
+	id, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || id <= 0 {
+		return "", fmt.Errorf("invalid %s ID", strings.ToLower(metafieldOwnerNames[ownerType]))
+	}
+	return strconv.FormatInt(id, 10), nil
+}
+
+// checkMetafieldValidations rejects validations that can never be satisfied or cannot be evaluated
+func checkMetafieldValidations(v models.MetafieldValidations) error {
+	if v.Min != nil && v.Max != nil && *v.Min > *v.Max {
+		return fmt.Errorf("min must not be greater than max")
+	}
+	if v.Pattern != nil {
+		if _, err := regexp.Compile(*v.Pattern); err != nil {
+			return fmt.Errorf("invalid pattern: %w", err)
+		}
+	}
+	return nil
+}
+
+// validateMetafieldValue checks that value is a valid literal of the given type
+// and satisfies the validations that apply to it
+func validateMetafieldValue(typ db.MetafieldType, value string, v models.MetafieldValidations) error {
+	var number *float64
+	switch typ {
+	case db.MetafieldTypeSingleLineText:
+		if strings.ContainsAny(value, "\r\n") {
+			return fmt.Errorf("must be a single line")
+		}
+	case db.MetafieldTypeMultiLineText:
+	case db.MetafieldTypeInteger:
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("must be an integer")
+		}
+		f := float64(n)
+		number = &f
+	case db.MetafieldTypeDecimal:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+			return fmt.Errorf("must be a decimal number")
+		}
+		number = &f
+	case db.MetafieldTypeBoolean:
+		if value != "true" && value != "false" {
+			return fmt.Errorf("must be true or false")
+		}
+	case db.MetafieldTypeDate:
+		if _, err := time.Parse(time.DateOnly, value); err != nil {
+			return fmt.Errorf("must be a date in YYYY-MM-DD format")
+		}
+	case db.MetafieldTypeDateTime:
+		if _, err := time.Parse(time.RFC3339, value); err != nil {
+			return fmt.Errorf("must be an RFC 3339 timestamp")
+		}
+	case db.MetafieldTypeJson:
+		if !json.Valid([]byte(value)) {
+			return fmt.Errorf("must be valid JSON")
+		}
+	case db.MetafieldTypeUrl:
+		u, err := url.Parse(value)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("must be an http or https URL")
+		}
+	case db.MetafieldTypeColor:
+		if !metafieldColorPattern.MatchString(value) {
+			return fmt.Errorf("must be a hex color such as #1a2b3c")
+		}
+	default:
+		return fmt.Errorf("unsupported type %q", typ)
+	}
+
+	if number != nil {
+		if v.Min != nil && *number < *v.Min {
+			return fmt.Errorf("must be at least %v", *v.Min)
+		}
+		if v.Max != nil && *number > *v.Max {
+			return fmt.Errorf("must be at most %v", *v.Max)
+		}
+	}
+	if typ == db.MetafieldTypeSingleLineText || typ == db.MetafieldTypeMultiLineText {
+		if v.MaxLength != nil && utf8.RuneCountInString(value) > *v.MaxLength {
+			return fmt.Errorf("must be at most %d characters", *v.MaxLength)
+		}
+		if v.Pattern != nil {
+			if re, err := regexp.Compile(*v.Pattern); err == nil && !re.MatchString(value) {
+				return fmt.Errorf("must match %s", *v.Pattern)
+			}
+		}
+	}
+	if len(v.Choices) > 0 {
+		allowed := false
+		for _, choice := range v.Choices {
+			if choice == value {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return fmt.Errorf("must be one of %s", strings.Join(v.Choices, ", "))
+		}
+	}
+	return nil
+}
+
+func metafieldDefinitionResponse(definition db.MetafieldDefinition) models.MetafieldDefinition {
+	var validations models.MetafieldValidations
+	if len(definition.Validations) > 0 {
+		if err := json.Unmarshal(definition.Validations, &validations); err != nil {
+			zap.L().Warn("metafieldDefinitionResponse: failed to decode validations", zap.Int64("definition_id", definition.MetafieldDefinitionID), zap.Error(err))
+		}
+	}
+	return models.MetafieldDefinition{
+		ID:          definition.MetafieldDefinitionID,
+		OwnerType:   definition.OwnerType,
+		Namespace:   definition.Namespace,
+		Key:         definition.Key,
+		Name:        definition.Name,
+		Description: definition.Description,
+		Type:        definition.Type,
+		Validations: validations,
+		CreatedAt:   definition.CreatedAt.Time,
+		UpdatedAt:   definition.UpdatedAt.Time,
+	}
+}
+
+func metafieldResponse(metafield db.Metafield) models.Metafield {
+	return models.Metafield{
+		ID:        metafield.MetafieldID,
+		Namespace: metafield.Namespace,
+		Key:       metafield.Key,
+		Type:      metafield.Type,
+		Value:     metafield.Value,
+		CreatedAt: metafield.CreatedAt.Time,
+		UpdatedAt: metafield.UpdatedAt.Time,
+	}
+}
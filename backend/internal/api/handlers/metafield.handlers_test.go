@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/petrejonn/naytife/internal/api/models"
+	"github.com/petrejonn/naytife/internal/db"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateMetafieldValue(t *testing.T) {
+	minimum, maximum := 1.0, 10.0
+	maxLength := 5
+	pattern := "^[A-Z]+$"
+	bounded := models.MetafieldValidations{Min: &minimum, Max: &maximum}
+
+	tests := []struct {
+		name        string
+		typ         db.MetafieldType
+		value       string
+		validations models.MetafieldValidations
+		wantErr     bool
+	}{
+		{"single line text", db.MetafieldTypeSingleLineText, "Cotton", models.MetafieldValidations{}, false},
+		{"single line text with newline", db.MetafieldTypeSingleLineText, "Cotton\nblend", models.MetafieldValidations{}, true},
+		{"multi line text", db.MetafieldTypeMultiLineText, "Hand wash\nDry flat", models.MetafieldValidations{}, false},
+		{"text at max length", db.MetafieldTypeSingleLineText, "héllo", models.MetafieldValidations{MaxLength: &maxLength}, false},
+		{"text over max length", db.MetafieldTypeMultiLineText, "hello!", models.MetafieldValidations{MaxLength: &maxLength}, true},
+		{"text matching pattern", db.MetafieldTypeSingleLineText, "ABC", models.MetafieldValidations{Pattern: &pattern}, false},
+		{"text not matching pattern", db.MetafieldTypeSingleLineText, "abc", models.MetafieldValidations{Pattern: &pattern}, true},
+		{"integer", db.MetafieldTypeInteger, "42", models.MetafieldValidations{}, false},
+		{"integer with decimals", db.MetafieldTypeInteger, "4.2", models.MetafieldValidations{}, true},
+		{"integer in range", db.MetafieldTypeInteger, "10", bounded, false},
+		{"integer below min", db.MetafieldTypeInteger, "0", bounded, true},
+		{"decimal", db.MetafieldTypeDecimal, "9.75", bounded, false},
+		{"decimal over max", db.MetafieldTypeDecimal, "10.01", bounded, true},
+		{"decimal nan", db.MetafieldTypeDecimal, "NaN", models.MetafieldValidations{}, true},
+		{"decimal infinity", db.MetafieldTypeDecimal, "Inf", models.MetafieldValidations{}, true},
+		{"boolean", db.MetafieldTypeBoolean, "false", models.MetafieldValidations{}, false},
+		{"boolean spelled out", db.MetafieldTypeBoolean, "yes", models.MetafieldValidations{}, true},
+		{"date", db.MetafieldTypeDate, "2025-02-28", models.MetafieldValidations{}, false},
+		{"date out of range", db.MetafieldTypeDate, "2025-02-30", models.MetafieldValidations{}, true},
+		{"date time", db.MetafieldTypeDateTime, "2025-02-28T10:00:00+01:00", models.MetafieldValidations{}, false},
+		{"date time without zone", db.MetafieldTypeDateTime, "2025-02-28T10:00:00", models.MetafieldValidations{}, true},
+		{"json", db.MetafieldTypeJson, `{"size":"M"}`, models.MetafieldValidations{}, false},
+		{"json truncated", db.MetafieldTypeJson, `{"size":`, models.MetafieldValidations{}, true},
+		{"url", db.MetafieldTypeUrl, "https://example.com/care", models.MetafieldValidations{}, false},
+		{"url without http scheme", db.MetafieldTypeUrl, "javascript:alert(1)", models.MetafieldValidations{}, true},
+		{"url without host", db.MetafieldTypeUrl, "https:///care", models.MetafieldValidations{}, true},
+		{"color", db.MetafieldTypeColor, "#1a2B3c", models.MetafieldValidations{}, false},
+		{"short color", db.MetafieldTypeColor, "#fff", models.MetafieldValidations{}, true},
+		{"one of the choices", db.MetafieldTypeSingleLineText, "M", models.MetafieldValidations{Choices: []string{"S", "M", "L"}}, false},
+		{"not one of the choices", db.MetafieldTypeSingleLineText, "XL", models.MetafieldValidations{Choices: []string{"S", "M", "L"}}, true},
+		{"unknown type", db.MetafieldType("file"), "a.pdf", models.MetafieldValidations{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateMetafieldValue(tt.typ, tt.value, tt.validations)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCheckMetafieldValidations(t *testing.T) {
+	low, high := 1.0, 5.0
+	valid, invalid := "^[a-z]+$", "[a-z"
+
+	assert.NoError(t, checkMetafieldValidations(models.MetafieldValidations{Min: &low, Max: &high, Pattern: &valid}))
+	assert.NoError(t, checkMetafieldValidations(models.MetafieldValidations{Min: &low, Max: &low}))
+	assert.Error(t, checkMetafieldValidations(models.MetafieldValidations{Min: &high, Max: &low}))
+	assert.Error(t, checkMetafieldValidations(models.MetafieldValidations{Pattern: &invalid}))
+}
+
+func TestParseMetafieldOwnerID(t *testing.T) {
+	tests := []struct {
+		name      string
+		ownerType db.MetafieldOwnerType
+		raw       string
+		want      string
+		wantErr   bool
+	}{
+		{"product", db.MetafieldOwnerTypeProduct, "12", "12", false},
+		{"leading zeros", db.MetafieldOwnerTypeCategory, "007", "7", false},
+		{"zero", db.MetafieldOwnerTypeProductVariation, "0", "", true},
+		{"not a number", db.MetafieldOwnerTypeShop, "abc", "", true},
+		{"customer", db.MetafieldOwnerTypeCustomer, "6BA7B810-9DAD-11D1-80B4-00C04FD430C8", "6ba7b810-9dad-11d1-80b4-00c04fd430c8", false},
+		{"customer by number", db.MetafieldOwnerTypeCustomer, "12", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseMetafieldOwnerID(tt.ownerType, tt.raw)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -1,8 +1,8 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
-	"math/big"
 	"strconv"
 
 	"github.com/gofiber/fiber/v2"
@@ -12,6 +12,8 @@ import (
 	"github.com/petrejonn/naytife/internal/api"
 	"github.com/petrejonn/naytife/internal/api/models"
 	"github.com/petrejonn/naytife/internal/db"
+	"github.com/petrejonn/naytife/internal/money"
+	"github.com/petrejonn/naytife/internal/services"
 	"go.uber.org/zap"
 )
 
@@ -64,6 +66,11 @@ func (h *Handler) GetOrders(c *fiber.Ctx) error {
 		return api.SystemErrorResponse(c, err, "Failed to count orders")
 	}
 
+	shop, err := h.Repository.GetShop(c.Context(), shopID)
+	if err != nil {
+		return api.SystemErrorResponse(c, err, "Failed to fetch shop")
+	}
+
 	// Map database models to API models
 	var result []models.Order
 	for _, order := range orders {
@@ -77,45 +84,12 @@ func (h *Handler) GetOrders(c *fiber.Ctx) error {
 			return api.SystemErrorResponse(c, err, "Failed to fetch order items")
 		}
 
-		// Map items
-		var orderItems []models.OrderItem
-		for _, item := range items {
-			orderItems = append(orderItems, models.OrderItem{
-				ID:                 item.OrderItemID,
-				Quantity:           item.Quantity,
-				Price:              item.Price,
-				CreatedAt:          item.CreatedAt,
-				UpdatedAt:          item.UpdatedAt,
-				ProductVariationID: item.ProductVariationID,
-				OrderID:            item.OrderID,
-				ShopID:             item.ShopID,
-			})
-		}
-
 		// Add order with items to result
-		result = append(result, models.Order{
-			ID:              order.OrderID,
-			Status:          order.Status,
-			CreatedAt:       order.CreatedAt,
-			UpdatedAt:       order.UpdatedAt,
-			CustomerID:      order.ShopCustomerID.Bytes,
-			Amount:          order.Amount,
-			Discount:        order.Discount,
-			ShippingCost:    order.ShippingCost,
-			Tax:             order.Tax,
-			ShippingAddress: order.ShippingAddress,
-			PaymentMethod:   order.PaymentMethod,
-			PaymentStatus:   order.PaymentStatus,
-			ShippingMethod:  order.ShippingMethod,
-			ShippingStatus:  order.ShippingStatus,
-			TransactionID:   order.TransactionID,
-			Username:        order.Username,
-			ShopID:          order.ShopID,
-			Items:           orderItems,
-			CustomerName:    order.CustomerName,
-			CustomerEmail:   order.CustomerEmail,
-			CustomerPhone:   order.CustomerPhone,
-		})
+		mapped, err := models.NewOrder(order, items, shop.CurrencyCode)
+		if err != nil {
+			return api.SystemErrorResponse(c, err, "Failed to read order amounts")
+		}
+		result = append(result, mapped)
 	}
 
 	totalPages := (int(total) + limit - 1) / limit
@@ -183,44 +157,36 @@ func (h *Handler) GetOrder(c *fiber.Ctx) error {
 		return api.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to fetch order items", nil)
 	}
 
-	// Map items
-	var orderItems []models.OrderItem
-	for _, item := range items {
-		orderItems = append(orderItems, models.OrderItem{
-			ID:                 item.OrderItemID,
-			Quantity:           item.Quantity,
-			Price:              item.Price,
-			CreatedAt:          item.CreatedAt,
-			UpdatedAt:          item.UpdatedAt,
-			ProductVariationID: item.ProductVariationID,
-			OrderID:            item.OrderID,
-			ShopID:             item.ShopID,
-		})
+	shop, err := h.Repository.GetShop(c.Context(), shopID)
+	if err != nil {
+		return api.SystemErrorResponse(c, err, "Failed to fetch shop")
 	}
 
 	// Map order to API model
-	result := models.Order{
-		ID:              order.OrderID,
-		Status:          order.Status,
-		CreatedAt:       order.CreatedAt,
-		UpdatedAt:       order.UpdatedAt,
-		CustomerID:      order.ShopCustomerID.Bytes,
-		Amount:          order.Amount,
-		Discount:        order.Discount,
-		ShippingCost:    order.ShippingCost,
-		Tax:             order.Tax,
-		ShippingAddress: order.ShippingAddress,
-		PaymentMethod:   order.PaymentMethod,
-		PaymentStatus:   order.PaymentStatus,
-		ShippingMethod:  order.ShippingMethod,
-		ShippingStatus:  order.ShippingStatus,
-		TransactionID:   order.TransactionID,
-		Username:        order.Username,
-		ShopID:          order.ShopID,
-		Items:           orderItems,
-		CustomerName:    order.CustomerName,
-		CustomerEmail:   order.CustomerEmail,
-		CustomerPhone:   order.CustomerPhone,
+	result, err := models.NewOrder(order, items, shop.CurrencyCode)
+	if err != nil {
+		return api.SystemErrorResponse(c, err, "Failed to read order amounts")
+	}
+
+	presentment, err := h.Repository.GetOrderPresentment(c.Context(), db.GetOrderPresentmentParams{
+		OrderID: order.OrderID,
+		ShopID:  shopID,
+	})
+	if err != nil && err != pgx.ErrNoRows {
+		zap.L().Error("GetOrder: failed to fetch order presentment", zap.Error(err), zap.Int64("shop_id", shopID), zap.Int64("order_id", order.OrderID))
+		return api.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to fetch order", nil)
+	}
+	if err == nil {
+		presentmentAmount, err := money.FromNumeric(presentment.Amount, presentment.CurrencyCode)
+		if err != nil {
+			return api.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to fetch order", nil)
+		}
+		result.Presentment = &models.OrderPresentment{
+			CurrencyCode: presentment.CurrencyCode,
+			ExchangeRate: presentment.ExchangeRate,
+			Amount:       presentmentAmount,
+			CreatedAt:    presentment.CreatedAt.Time,
+		}
 	}
 
 	return api.SuccessResponse(c, fiber.StatusOK, result, "Order fetched successfully")
@@ -228,15 +194,16 @@ func (h *Handler) GetOrder(c *fiber.Ctx) error {
 
 // CreateOrder creates a new order from cart items
 // @Summary      Create a new order
-// @Description  Create a new order with items from cart
+// @Description  Create a new order with items from cart. Items of a customer in a customer group are charged at the group price. Items beyond the stock of their variant are refused unless the variant takes backorders or preorders, in which case they are flagged as backordered.
 // @Tags         orders
 // @Accept       json
 // @Produce      json
 // @Param        shop_id path string true "Shop ID"
 // @Param        order body models.CreateOrderRequest true "Order object that needs to be created"
 // @Success      201  {object}  models.SuccessResponse{data=models.Order}  "Order created successfully"
-// @Failure      400  {object}  models.ErrorResponse "Invalid request body or shop ID"
+// @Failure      400  {object}  models.ErrorResponse "Invalid request body or shop ID, or not enough stock"
 // @Failure      401  {object}  models.ErrorResponse "Unauthorized"
+// @Failure      404  {object}  models.ErrorResponse "Product variant not found"
 // @Failure      500  {object}  models.ErrorResponse "Internal server error"
 // @Security     OAuth2AccessCode
 // @Router       /shops/{shop_id}/orders [post]
@@ -251,6 +218,7 @@ func (h *Handler) CreateOrder(c *fiber.Ctx) error {
 	if err := c.BodyParser(&orderReq); err != nil {
 		return api.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body", nil)
 	}
+	orderReq.ShippingAddress.Normalize()
 
 	// Validate request body
 	validator := &models.XValidator{}
@@ -262,15 +230,45 @@ func (h *Handler) CreateOrder(c *fiber.Ctx) error {
 		}
 	}
 
+	shop, err := h.Repository.GetShop(c.Context(), shopID)
+	if err != nil {
+		return api.ErrorResponse(c, fiber.StatusNotFound, "Shop not found", nil)
+	}
+
+	// Amounts are given in the shop currency and must fit its minor unit
+	amounts := []*money.Money{&orderReq.Discount, &orderReq.ShippingCost, &orderReq.Tax}
+	for i := range orderReq.Items {
+		amounts = append(amounts, &orderReq.Items[i].Price)
+	}
+	for _, amount := range amounts {
+		converted, err := amount.In(shop.CurrencyCode)
+		if err != nil {
+			return api.ErrorResponse(c, fiber.StatusBadRequest, err.Error(), nil)
+		}
+		if converted.IsNegative() {
+			return api.ErrorResponse(c, fiber.StatusBadRequest, "Amounts must not be negative", nil)
+		}
+		*amount = converted
+	}
+
+	// Customers in a group pay their group prices
+	if orderReq.CustomerID != nil {
+		if customerID, parseErr := uuid.Parse(*orderReq.CustomerID); parseErr == nil {
+			if err := h.applyCustomerPrices(c, shopID, shop.CurrencyCode, customerID, orderReq.Items); err != nil {
+				return err
+			}
+		}
+	}
+
 	// Create order in a transaction to ensure consistency
 	var order db.Order
 	var orderItems []db.OrderItem
 
 	err = h.Repository.WithTx(c.Context(), func(q *db.Queries) error {
 		// Calculate totals
-		var totalAmount float64
+		totalAmount := money.Zero(shop.CurrencyCode)
 		for _, item := range orderReq.Items {
-			totalAmount += item.Price * float64(item.Quantity)
+			totalAmount = totalAmount.Add(item.Price.Mul(int64(item.Quantity)))
 		}
 
 		// Create the order
@@ -282,13 +280,18 @@ func (h *Handler) CreateOrder(c *fiber.Ctx) error {
 			}
 		}
 
+		shippingAddress, err := orderReq.ShippingAddress.Snapshot()
+		if err != nil {
+			return err
+		}
+
 		createOrderParams := db.CreateOrderParams{
 			Status:          db.OrderStatusType("pending"),
-			Amount:          pgtype.Numeric{Int: big.NewInt(int64(totalAmount * 100)), Valid: true}, // Store as cents
-			Discount:        pgtype.Numeric{Int: big.NewInt(int64(orderReq.Discount * 100)), Valid: true},
-			ShippingCost:    pgtype.Numeric{Int: big.NewInt(int64(orderReq.ShippingCost * 100)), Valid: true},
-			Tax:             pgtype.Numeric{Int: big.NewInt(int64(orderReq.Tax * 100)), Valid: true},
-			ShippingAddress: orderReq.ShippingAddress,
+			Amount:          totalAmount.Numeric(),
+			Discount:        orderReq.Discount.Numeric(),
+			ShippingCost:    orderReq.ShippingCost.Numeric(),
+			Tax:             orderReq.Tax.Numeric(),
+			ShippingAddress: shippingAddress,
 			PaymentMethod:   db.PaymentMethodType(orderReq.PaymentMethod),
 			PaymentStatus:   db.PaymentStatusType("pending"),
 			ShippingMethod:  orderReq.ShippingMethod,
@@ -308,20 +311,35 @@ func (h *Handler) CreateOrder(c *fiber.Ctx) error {
 		}
 		order = createdOrder
 
-		// Create order items
-		for _, item := range orderReq.Items {
-			productVariationID, err := strconv.ParseInt(item.ProductVariationID, 10, 64)
-			if err != nil {
+		variantIDs := make([]int64, len(orderReq.Items))
+		for i, item := range orderReq.Items {
+			if variantIDs[i], err = strconv.ParseInt(item.ProductVariationID, 10, 64); err != nil {
 				zap.L().Error("CreateOrder: invalid product variation ID", zap.String("product_variation_id", item.ProductVariationID), zap.Int64("shop_id", shopID))
 				return fmt.Errorf("invalid product variation ID: %s", item.ProductVariationID)
 			}
+		}
+		if err := services.LockOrderVariants(c.Context(), q, shopID, variantIDs); err != nil {
+			return err
+		}
+
+		// Create order items
+		for i, item := range orderReq.Items {
+			productVariationID := variantIDs[i]
+
+			// Items beyond the stock are taken on backorder or preorder when the
+			// variant allows it
+			backordered, err := services.OrderLineBackorder(c.Context(), q, shopID, productVariationID, int64(item.Quantity))
+			if err != nil {
+				return err
+			}
 
 			createItemParams := db.CreateOrderItemParams{
-				Quantity:           int64(item.Quantity),
-				Price:              pgtype.Numeric{Int: big.NewInt(int64(item.Price * 100)), Valid: true},
-				ProductVariationID: productVariationID,
-				OrderID:            order.OrderID,
-				ShopID:             shopID,
+				Quantity:            int64(item.Quantity),
+				Price:               item.Price.Numeric(),
+				ProductVariationID:  productVariationID,
+				OrderID:             order.OrderID,
+				ShopID:              shopID,
+				BackorderedQuantity: backordered,
 			}
 
 			orderItem, err := q.CreateOrderItem(c.Context(), createItemParams)
@@ -336,47 +354,21 @@ func (h *Handler) CreateOrder(c *fiber.Ctx) error {
 	})
 
 	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrOutOfStock):
+			return api.BusinessLogicErrorResponse(c, "Not enough stock for an item of the order")
+		case errors.Is(err, services.ErrPreorderLimitReached):
+			return api.BusinessLogicErrorResponse(c, "An item of the order can no longer be preordered")
+		case errors.Is(err, pgx.ErrNoRows):
+			return api.NotFoundErrorResponse(c, "Product variant")
+		}
 		return api.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to create order", nil)
 	}
 
-	// Map order items to API models
-	var apiOrderItems []models.OrderItem
-	for _, item := range orderItems {
-		apiOrderItems = append(apiOrderItems, models.OrderItem{
-			ID:                 item.OrderItemID,
-			Quantity:           item.Quantity,
-			Price:              item.Price,
-			CreatedAt:          item.CreatedAt,
-			UpdatedAt:          item.UpdatedAt,
-			ProductVariationID: item.ProductVariationID,
-			OrderID:            item.OrderID,
-			ShopID:             item.ShopID,
-		})
-	}
-
 	// Map order to API model
-	result := models.Order{
-		ID:              order.OrderID,
-		Status:          order.Status,
-		CreatedAt:       order.CreatedAt,
-		UpdatedAt:       order.UpdatedAt,
-		CustomerID:      order.ShopCustomerID.Bytes,
-		Amount:          order.Amount,
-		Discount:        order.Discount,
-		ShippingCost:    order.ShippingCost,
-		Tax:             order.Tax,
-		ShippingAddress: order.ShippingAddress,
-		PaymentMethod:   order.PaymentMethod,
-		PaymentStatus:   order.PaymentStatus,
-		ShippingMethod:  order.ShippingMethod,
-		ShippingStatus:  order.ShippingStatus,
-		TransactionID:   order.TransactionID,
-		Username:        order.Username,
-		ShopID:          order.ShopID,
-		Items:           apiOrderItems,
-		CustomerName:    order.CustomerName,
-		CustomerEmail:   order.CustomerEmail,
-		CustomerPhone:   order.CustomerPhone,
+	result, err := models.NewOrder(order, orderItems, shop.CurrencyCode)
+	if err != nil {
+		return api.SystemErrorResponse(c, err, "Failed to read order amounts")
 	}
 
 	return api.SuccessResponse(c, fiber.StatusCreated, result, "Order created successfully")
@@ -414,6 +406,7 @@ func (h *Handler) UpdateOrder(c *fiber.Ctx) error {
 	if err := c.BodyParser(&orderParams); err != nil {
 		return api.BusinessLogicErrorResponse(c, "Invalid request body")
 	}
+	orderParams.ShippingAddress.Normalize()
 
 	// Validate request body
 	if err := api.ValidateRequest(c, &orderParams); err != nil {
@@ -432,6 +425,11 @@ func (h *Handler) UpdateOrder(c *fiber.Ctx) error {
 		return api.SystemErrorResponse(c, err, "Failed to fetch order")
 	}
 
+	shippingAddress, err := orderParams.ShippingAddress.Snapshot()
+	if err != nil {
+		return api.SystemErrorResponse(c, err, "Failed to encode shipping address")
+	}
+
 	// Update order
 	err = h.Repository.UpdateOrder(c.Context(), db.UpdateOrderParams{
 		Status:          orderParams.Status,
@@ -439,7 +437,7 @@ func (h *Handler) UpdateOrder(c *fiber.Ctx) error {
 		Discount:        orderParams.Discount,
 		ShippingCost:    orderParams.ShippingCost,
 		Tax:             orderParams.Tax,
-		ShippingAddress: orderParams.ShippingAddress,
+		ShippingAddress: shippingAddress,
 		PaymentMethod:   orderParams.PaymentMethod,
 		PaymentStatus:   orderParams.PaymentStatus,
 		ShippingMethod:  orderParams.ShippingMethod,
@@ -474,44 +472,15 @@ func (h *Handler) UpdateOrder(c *fiber.Ctx) error {
 		return api.SystemErrorResponse(c, err, "Failed to fetch order items")
 	}
 
-	// Map items
-	var orderItems []models.OrderItem
-	for _, item := range items {
-		orderItems = append(orderItems, models.OrderItem{
-			ID:                 item.OrderItemID,
-			Quantity:           item.Quantity,
-			Price:              item.Price,
-			CreatedAt:          item.CreatedAt,
-			UpdatedAt:          item.UpdatedAt,
-			ProductVariationID: item.ProductVariationID,
-			OrderID:            item.OrderID,
-			ShopID:             item.ShopID,
-		})
+	shop, err := h.Repository.GetShop(c.Context(), shopID)
+	if err != nil {
+		return api.SystemErrorResponse(c, err, "Failed to fetch shop")
 	}
 
 	// Map order to API model
-	result := models.Order{
-		ID:              order.OrderID,
-		Status:          order.Status,
-		CreatedAt:       order.CreatedAt,
-		UpdatedAt:       order.UpdatedAt,
-		CustomerID:      order.ShopCustomerID.Bytes,
-		Amount:          order.Amount,
-		Discount:        order.Discount,
-		ShippingCost:    order.ShippingCost,
-		Tax:             order.Tax,
-		ShippingAddress: order.ShippingAddress,
-		PaymentMethod:   order.PaymentMethod,
-		PaymentStatus:   order.PaymentStatus,
-		ShippingMethod:  order.ShippingMethod,
-		ShippingStatus:  order.ShippingStatus,
-		TransactionID:   order.TransactionID,
-		Username:        order.Username,
-		ShopID:          order.ShopID,
-		Items:           orderItems,
-		CustomerName:    order.CustomerName,
-		CustomerEmail:   order.CustomerEmail,
-		CustomerPhone:   order.CustomerPhone,
+	result, err := models.NewOrder(order, items, shop.CurrencyCode)
+	if err != nil {
+		return api.SystemErrorResponse(c, err, "Failed to read order amounts")
 	}
 
 	return api.SuccessResponse(c, fiber.StatusOK, result, "Order updated successfully")
@@ -567,25 +536,50 @@ func (h *Handler) UpdateOrderStatus(c *fiber.Ctx) error {
 		return api.SystemErrorResponse(c, err, "Failed to fetch order")
 	}
 
-	// Update only the status
-	err = h.Repository.UpdateOrder(c.Context(), db.UpdateOrderParams{
-		Status:          statusParams.Status,
-		Amount:          currentOrder.Amount,
-		Discount:        currentOrder.Discount,
-		ShippingCost:    currentOrder.ShippingCost,
-		Tax:             currentOrder.Tax,
-		ShippingAddress: currentOrder.ShippingAddress,
-		PaymentMethod:   currentOrder.PaymentMethod,
-		PaymentStatus:   currentOrder.PaymentStatus,
-		ShippingMethod:  currentOrder.ShippingMethod,
-		ShippingStatus:  currentOrder.ShippingStatus,
-		TransactionID:   currentOrder.TransactionID,
-		Username:        currentOrder.Username,
-		CustomerName:    currentOrder.CustomerName,
-		CustomerEmail:   currentOrder.CustomerEmail,
-		CustomerPhone:   currentOrder.CustomerPhone,
-		OrderID:         orderID,
-		ShopID:          shopID,
+	shop, err := h.Repository.GetShop(c.Context(), shopID)
+	if err != nil {
+		return api.SystemErrorResponse(c, err, "Failed to fetch shop")
+	}
+
+	// Update only the status; completed orders earn their customer loyalty points,
+	// cancelled and refunded orders give back what gift cards, store credit and
+	// points paid towards them and claw back the points they earned
+	err = h.Repository.WithTx(c.Context(), func(q *db.Queries) error {
+		if err := q.UpdateOrder(c.Context(), db.UpdateOrderParams{
+			Status:          statusParams.Status,
+			Amount:          currentOrder.Amount,
+			Discount:        currentOrder.Discount,
+			ShippingCost:    currentOrder.ShippingCost,
+			Tax:             currentOrder.Tax,
+			ShippingAddress: currentOrder.ShippingAddress,
+			PaymentMethod:   currentOrder.PaymentMethod,
+			PaymentStatus:   currentOrder.PaymentStatus,
+			ShippingMethod:  currentOrder.ShippingMethod,
+			ShippingStatus:  currentOrder.ShippingStatus,
+			TransactionID:   currentOrder.TransactionID,
+			Username:        currentOrder.Username,
+			CustomerName:    currentOrder.CustomerName,
+			CustomerEmail:   currentOrder.CustomerEmail,
+			CustomerPhone:   currentOrder.CustomerPhone,
+			OrderID:         orderID,
+			ShopID:          shopID,
+		}); err != nil {
+			return err
+		}
+		switch statusParams.Status {
+		case db.OrderStatusTypeCompleted:
+			if _, err := services.AwardOrderLoyaltyPoints(c.Context(), q, currentOrder, shop.CurrencyCode); err != nil {
+				return err
+			}
+		case db.OrderStatusTypeCancelled, db.OrderStatusTypeRefunded:
+			if _, err := services.ReleaseOrderTender(c.Context(), q, shopID, orderID, shop.CurrencyCode); err != nil {
+				return err
+			}
+			if err := services.ReverseOrderLoyaltyPoints(c.Context(), q, shopID, orderID); err != nil {
+				return err
+			}
+		}
+		return nil
 	})
 	if err != nil {
 		return api.SystemErrorResponse(c, err, "Failed to update order status")
@@ -609,44 +603,10 @@ func (h *Handler) UpdateOrderStatus(c *fiber.Ctx) error {
 		return api.SystemErrorResponse(c, err, "Failed to fetch order items")
 	}
 
-	// Map items
-	var orderItems []models.OrderItem
-	for _, item := range items {
-		orderItems = append(orderItems, models.OrderItem{
-			ID:                 item.OrderItemID,
-			Quantity:           item.Quantity,
-			Price:              item.Price,
-			CreatedAt:          item.CreatedAt,
-			UpdatedAt:          item.UpdatedAt,
-			ProductVariationID: item.ProductVariationID,
-			OrderID:            item.OrderID,
-			ShopID:             item.ShopID,
-		})
-	}
-
 	// Map order to API model
-	result := models.Order{
-		ID:              order.OrderID,
-		Status:          order.Status,
-		CreatedAt:       order.CreatedAt,
-		UpdatedAt:       order.UpdatedAt,
-		CustomerID:      order.ShopCustomerID.Bytes,
-		Amount:          order.Amount,
-		Discount:        order.Discount,
-		ShippingCost:    order.ShippingCost,
-		Tax:             order.Tax,
-		ShippingAddress: order.ShippingAddress,
-		PaymentMethod:   order.PaymentMethod,
-		PaymentStatus:   order.PaymentStatus,
-		ShippingMethod:  order.ShippingMethod,
-		ShippingStatus:  order.ShippingStatus,
-		TransactionID:   order.TransactionID,
-		Username:        order.Username,
-		ShopID:          order.ShopID,
-		Items:           orderItems,
-		CustomerName:    order.CustomerName,
-		CustomerEmail:   order.CustomerEmail,
-		CustomerPhone:   order.CustomerPhone,
+	result, err := models.NewOrder(order, items, shop.CurrencyCode)
+	if err != nil {
+		return api.SystemErrorResponse(c, err, "Failed to read order amounts")
 	}
 
 	return api.SuccessResponse(c, fiber.StatusOK, result, "Order status updated successfully")
@@ -689,8 +649,21 @@ func (h *Handler) DeleteOrder(c *fiber.Ctx) error {
 		return api.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to fetch order", nil)
 	}
 
+	shop, err := h.Repository.GetShop(c.Context(), shopID)
+	if err != nil {
+		return api.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to fetch shop", nil)
+	}
+
 	// Delete order and its items in a transaction
 	err = h.Repository.WithTx(c.Context(), func(q *db.Queries) error {
+		// Give back what gift cards, store credit and points paid towards the order
+		if _, txErr := services.ReleaseOrderTender(c.Context(), q, shopID, orderID, shop.CurrencyCode); txErr != nil {
+			return txErr
+		}
+		if txErr := services.ReverseOrderLoyaltyPoints(c.Context(), q, shopID, orderID); txErr != nil {
+			return txErr
+		}
+
 		// Delete order items first (due to foreign key constraints)
 		txErr := q.DeleteOrderItemsByOrder(c.Context(), db.DeleteOrderItemsByOrderParams{
 			OrderID: orderID,
@@ -714,3 +687,24 @@ func (h *Handler) DeleteOrder(c *fiber.Ctx) error {
 
 	return api.SuccessResponse(c, fiber.StatusOK, nil, fmt.Sprintf("Order %d deleted successfully", orderID))
 }
+
+// applyCustomerPrices replaces the prices of items that have a group price for
+// the customer. Items with invalid variant IDs are rejected later on.
+func (h *Handler) applyCustomerPrices(c *fiber.Ctx, shopID int64, currency string, customerID uuid.UUID, items []models.CreateOrderRequestItem) error {
+	variantIDs := make([]int64, len(items))
+	for i, item := range items {
+		variantIDs[i], _ = strconv.ParseInt(item.ProductVariationID, 10, 64)
+	}
+
+	prices, err := services.CustomerPrices(c.Context(), h.Repository, shopID, currency, customerID, variantIDs)
+	if err != nil {
+		zap.L().Error("CreateOrder: failed to fetch customer prices", zap.Error(err), zap.String("customer_id", customerID.String()), zap.Int64("shop_id", shopID))
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to create order")
+	}
+	for i, variantID := range variantIDs {
+		if price, ok := prices[variantID]; ok {
+			items[i].Price = price
+		}
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
+	"github.com/petrejonn/naytife/internal/api"
+	"github.com/petrejonn/naytife/internal/api/models"
+	"github.com/petrejonn/naytife/internal/db"
+	"github.com/petrejonn/naytife/internal/services"
+	"go.uber.org/zap"
+)
+
+// FulfillOrder takes the items of an order from a location
+// @Summary      Fulfill an order
+// @Description  Take the stock of an order from the location it ships from. Without a location, the first location that sells online and holds every item is picked, starting with the default location. Orders with backordered or preordered items can only be fulfilled once the stock for them has arrived.
+// @Tags         orders
+// @Accept       json
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        order_id path string true "Order ID"
+// @Param        fulfillment body models.FulfillOrderParams false "Fulfillment location"
+// @Success      201  {object}   models.SuccessResponse{data=models.OrderFulfillment} "Order fulfilled successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      404  {object}   models.ErrorResponse "Order or location not found"
+// @Failure      409  {object}   models.ErrorResponse "Order already fulfilled"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/orders/{order_id}/fulfillment [post]
+func (h *Handler) FulfillOrder(c *fiber.Ctx) error {
+	order, err := h.orderFromPath(c)
+	if err != nil {
+		return err
+	}
+	var param models.FulfillOrderParams
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&param); err != nil {
+			return api.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body", nil)
+		}
+	}
+	if order.Status == db.OrderStatusTypeCancelled || order.Status == db.OrderStatusTypeRefunded {
+		return api.BusinessLogicErrorResponse(c, "Cancelled and refunded orders cannot be fulfilled")
+	}
+
+	var fulfillment db.OrderFulfillment
+	err = h.Repository.WithTx(c.Context(), func(q *db.Queries) error {
+		if param.LocationID != nil {
+			if _, err := services.StockLocation(c.Context(), q, order.ShopID, param.LocationID); err != nil {
+				return err
+			}
+		}
+		var err error
+		fulfillment, err = services.FulfillOrder(c.Context(), q, order, param.LocationID, requestUser(c))
+		return err
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrOrderAlreadyFulfilled):
+			return api.ErrorResponse(c, fiber.StatusConflict, "The order has already been fulfilled", nil)
+		case errors.Is(err, services.ErrNoFulfillmentLocation):
+			return api.BusinessLogicErrorResponse(c, "No location that sells online has enough stock for the order")
+		}
+		zap.L().Warn("FulfillOrder: failed to fulfill order", zap.Int64("order_id", order.OrderID), zap.Error(err))
+		return stockChangeErrorResponse(c, err, "Failed to fulfill order")
+	}
+	return api.SuccessResponse(c, fiber.StatusCreated, models.NewOrderFulfillment(fulfillment), "Order fulfilled successfully")
+}
+
+// GetOrderFulfillment fetches the fulfillment of an order
+// @Summary      Get order fulfillment
+// @Description  Get the location an order's stock was taken from
+// @Tags         orders
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        order_id path string true "Order ID"
+// @Success      200  {object}   models.SuccessResponse{data=models.OrderFulfillment} "Order fulfillment fetched successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      404  {object}   models.ErrorResponse "Order not found or not fulfilled"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/orders/{order_id}/fulfillment [get]
+func (h *Handler) GetOrderFulfillment(c *fiber.Ctx) error {
+	order, err := h.orderFromPath(c)
+	if err != nil {
+		return err
+	}
+	fulfillment, err := h.Repository.GetOrderFulfillment(c.Context(), db.GetOrderFulfillmentParams{
+		OrderID: order.OrderID,
+		ShopID:  order.ShopID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return api.ErrorResponse(c, fiber.StatusNotFound, "The order has not been fulfilled", nil)
+		}
+		zap.L().Error("GetOrderFulfillment: failed to fetch fulfillment", zap.Int64("order_id", order.OrderID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to fetch order fulfillment")
+	}
+	return api.SuccessResponse(c, fiber.StatusOK, models.NewOrderFulfillment(fulfillment), "Order fulfillment fetched successfully")
+}
+
+// orderFromPath fetches the order named by the shop_id and order_id path parameters
+func (h *Handler) orderFromPath(c *fiber.Ctx) (db.Order, error) {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return db.Order{}, err
+	}
+	orderID, err := api.ParseIDParameter(c, "order_id", "Order")
+	if err != nil {
+		return db.Order{}, err
+	}
+
+	order, err := h.Repository.GetOrder(c.Context(), db.GetOrderParams{
+		OrderID: orderID,
+		ShopID:  shopID,
+	})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return db.Order{}, fiber.NewError(fiber.StatusNotFound, "Order not found")
+		}
+		zap.L().Error("orderFromPath: failed to fetch order", zap.Int64("order_id", orderID), zap.Error(err))
+		return db.Order{}, fiber.NewError(fiber.StatusInternalServerError, "Failed to fetch order")
+	}
+	return order, nil
+}
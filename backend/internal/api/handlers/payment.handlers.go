@@ -1,14 +1,17 @@
 package handlers
 
 import (
-	"fmt"
+	"context"
+	"errors"
 	"strconv"
 	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
 	"github.com/petrejonn/naytife/internal/api/models"
 	"github.com/petrejonn/naytife/internal/db"
+	"github.com/petrejonn/naytife/internal/money"
 	"github.com/petrejonn/naytife/internal/services"
 	"go.uber.org/zap"
 )
@@ -17,15 +20,19 @@ var validate = validator.New()
 
 // PaymentHandler handles payment-related API endpoints
 type PaymentHandler struct {
-	paymentFactory *services.PaymentProcessorFactory
-	repository     db.Repository
+	paymentFactory  *services.PaymentProcessorFactory
+	repository      db.Repository
+	currencyService *services.CurrencyService
+	digitalDelivery *services.DigitalDelivery
 }
 
 // NewPaymentHandler creates a new payment handler
-func NewPaymentHandler(paymentFactory *services.PaymentProcessorFactory, repo db.Repository) *PaymentHandler {
+func NewPaymentHandler(paymentFactory *services.PaymentProcessorFactory, repo db.Repository, currencyService *services.CurrencyService, digitalDelivery *services.DigitalDelivery) *PaymentHandler {
 	return &PaymentHandler{
-		paymentFactory: paymentFactory,
-		repository:     repo,
+		paymentFactory:  paymentFactory,
+		repository:      repo,
+		currencyService: currencyService,
+		digitalDelivery: digitalDelivery,
 	}
 }
 
@@ -41,6 +48,13 @@ type CreateCheckoutSessionRequest struct {
 	OrderID           int64  `json:"order_id" validate:"required"`
 	ShopID            int64  `json:"shop_id" validate:"required"`
 	PaymentMethodType string `json:"payment_method_type" validate:"required,oneof=stripe paystack flutterwave paypal pay_on_delivery"`
+	// CurrencyCode is the presentment currency to charge in; defaults to the shop currency
+	CurrencyCode string `json:"currency_code,omitempty" example:"GHS"`
+	// GiftCardCodes are redeemed towards the order, in order, before the payment method is charged
+	GiftCardCodes []string `json:"gift_card_codes,omitempty" example:"ABCD-EFGH-JKLM-NPQR"`
+	// UseStoreCredit also applies the store credit of the order's customer, who must
+	// be signed in
+	UseStoreCredit bool `json:"use_store_credit,omitempty"`
 }
 
 // CreateCheckoutSessionResponse represents the response from creating a checkout session
@@ -50,17 +64,27 @@ type CreateCheckoutSessionResponse struct {
 	Status             string                    `json:"status"`
 	NextAction         *models.PaymentNextAction `json:"next_action,omitempty"`
 	PaymentMethodTypes []string                  `json:"payment_method_types"`
+	Amount             money.Money               `json:"amount" swaggertype:"string" example:"12.50"`
+	CurrencyCode       string                    `json:"currency_code"`
+	ExchangeRate       float64                   `json:"exchange_rate"`
+	// GiftCardAmount and StoreCreditAmount are what gift cards and store credit pay,
+	// in the shop currency; Amount is the rest, charged through the payment method
+	GiftCardAmount    money.Money `json:"gift_card_amount" swaggertype:"string" example:"10.00"`
+	StoreCreditAmount money.Money `json:"store_credit_amount" swaggertype:"string" example:"0.00"`
 }
 
 // CreateCheckoutSession creates a payment intent for checkout using the configured payment method
 // @Summary Create checkout session
-// @Description Creates a payment intent for processing payments using the shop's configured payment method
+// @Description Creates a payment intent for processing payments using the shop's configured payment method.
+// @Description Gift cards and store credit are redeemed first (split tender) and the payment method is charged the rest;
+// @Description an order they cover in full is paid straight away with status "succeeded".
 // @Tags payments
 // @Accept json
 // @Produce json
 // @Param request body CreateCheckoutSessionRequest true "Checkout session request"
 // @Success 200 {object} CreateCheckoutSessionResponse
 // @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
 // @Router /payments/checkout [post]
 func (h *PaymentHandler) CreateCheckoutSession(c *fiber.Ctx) error {
@@ -104,6 +128,85 @@ func (h *PaymentHandler) CreateCheckoutSession(c *fiber.Ctx) error {
 		})
 	}
 
+	// Only the order's customer may spend their store credit on it
+	if req.UseStoreCredit {
+		customerID, ok := c.Locals("shop_customer_id").(uuid.UUID)
+		if !ok || !order.ShopCustomerID.Valid || uuid.UUID(order.ShopCustomerID.Bytes) != customerID {
+			return c.Status(fiber.StatusForbidden).JSON(models.ErrorResponse{
+				Status:  "error",
+				Message: "Sign in as the order's customer to use store credit",
+				Code:    fiber.StatusForbidden,
+			})
+		}
+	}
+
+	// Resolve the presentment currency the rest of the order is charged in
+	presentment, err := h.currencyService.Presentment(c.Context(), req.ShopID, req.CurrencyCode)
+	if err != nil {
+		zap.L().Warn("CreateCheckoutSession: failed to resolve presentment currency", zap.Error(err), zap.Int64("shop_id", req.ShopID), zap.String("currency_code", req.CurrencyCode))
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Status:  "error",
+			Message: err.Error(),
+			Code:    fiber.StatusBadRequest,
+		})
+	}
+	orderAmount, err := money.FromNumeric(order.Amount, presentment.ShopCurrency)
+	if err != nil {
+		zap.L().Error("CreateCheckoutSession: invalid order amount", zap.Error(err), zap.Int64("shop_id", req.ShopID), zap.Int64("order_id", req.OrderID))
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Status:  "error",
+			Message: "Invalid order amount",
+			Code:    fiber.StatusInternalServerError,
+		})
+	}
+
+	// Redeem gift cards and store credit first; the payment method charges the rest
+	var tender services.OrderTender
+	err = h.repository.WithTx(c.Context(), func(q *db.Queries) error {
+		var err error
+		tender, err = services.ApplyOrderTender(c.Context(), q, order, presentment.ShopCurrency, req.GiftCardCodes, req.UseStoreCredit)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, services.ErrGiftCardNotFound) || errors.Is(err, services.ErrGiftCardUnusable) || errors.Is(err, services.ErrInsufficientStoreCredit) || errors.Is(err, services.ErrOrderNotPayable) {
+			return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+				Status:  "error",
+				Message: err.Error(),
+				Code:    fiber.StatusBadRequest,
+			})
+		}
+		zap.L().Error("CreateCheckoutSession: failed to redeem gift cards and store credit", zap.Error(err), zap.Int64("shop_id", req.ShopID), zap.Int64("order_id", req.OrderID))
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Status:  "error",
+			Message: "Failed to redeem gift cards",
+			Code:    fiber.StatusInternalServerError,
+		})
+	}
+	due := orderAmount.Sub(tender.Total())
+
+	// Gift cards and store credit cover the whole order
+	if due.IsZero() || due.IsNegative() {
+		transactionID := "tender_" + strconv.FormatInt(order.OrderID, 10)
+		if err := completeOrderPayment(c.Context(), h.repository, h.digitalDelivery, order, presentment.ShopCurrency, transactionID); err != nil {
+			zap.L().Error("CreateCheckoutSession: failed to complete order paid by gift cards", zap.Error(err), zap.Int64("shop_id", req.ShopID), zap.Int64("order_id", req.OrderID))
+			return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+				Status:  "error",
+				Message: "Failed to update order status",
+				Code:    fiber.StatusInternalServerError,
+			})
+		}
+		return c.JSON(CreateCheckoutSessionResponse{
+			PaymentIntentID:    transactionID,
+			Status:             "succeeded",
+			PaymentMethodTypes: []string{},
+			Amount:             money.Zero(presentment.CurrencyCode),
+			CurrencyCode:       presentment.CurrencyCode,
+			ExchangeRate:       presentment.Rate,
+			GiftCardAmount:     tender.GiftCards,
+			StoreCreditAmount:  tender.StoreCredit,
+		})
+	}
+
 	// Handle Pay on Delivery
 	if req.PaymentMethodType == "pay_on_delivery" {
 		// Create a simple response for Pay on Delivery
@@ -112,6 +215,11 @@ func (h *PaymentHandler) CreateCheckoutSession(c *fiber.Ctx) error {
 			ClientSecret:       "",
 			Status:             "requires_confirmation",
 			PaymentMethodTypes: []string{"pay_on_delivery"},
+			Amount:             due,
+			CurrencyCode:       presentment.ShopCurrency,
+			ExchangeRate:       1,
+			GiftCardAmount:     tender.GiftCards,
+			StoreCreditAmount:  tender.StoreCredit,
 		}
 		return c.JSON(response)
 	}
@@ -155,21 +263,37 @@ func (h *PaymentHandler) CreateCheckoutSession(c *fiber.Ctx) error {
 		})
 	}
 
-	// Fetch order amount and currency from DB
-	// Convert pgtype.Numeric to float64
-	amountVal, _ := order.Amount.Value()
-	amountFloat, _ := strconv.ParseFloat(fmt.Sprintf("%v", amountVal), 64)
-	currency := "USD" // TODO: fetch from shop if needed
+	// Convert what is still due into the presentment currency
+	presentmentAmount := presentment.Convert(due)
+
+	// Record the currency and rate the order is charged with
+	if _, err := h.repository.UpsertOrderPresentment(c.Context(), db.UpsertOrderPresentmentParams{
+		OrderID:      order.OrderID,
+		CurrencyCode: presentment.CurrencyCode,
+		ExchangeRate: services.FloatToNumeric(presentment.Rate),
+		Amount:       presentmentAmount.Numeric(),
+		ShopID:       req.ShopID,
+	}); err != nil {
+		zap.L().Error("CreateCheckoutSession: failed to record order presentment", zap.Error(err), zap.Int64("shop_id", req.ShopID), zap.Int64("order_id", req.OrderID))
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Status:  "error",
+			Message: "Failed to record order currency",
+			Code:    fiber.StatusInternalServerError,
+		})
+	}
 
 	// Create payment intent request
 	paymentReq := models.PaymentIntentRequest{
-		Amount:            amountFloat,
-		CurrencyCode:      currency,
+		Amount:            presentmentAmount,
+		CurrencyCode:      presentment.CurrencyCode,
 		PaymentMethod:     req.PaymentMethodType,
 		CheckoutSessionID: "checkout_" + strconv.FormatInt(req.ShopID, 10) + "_" + generateOrderID(),
 		Description:       "Order payment",
 		Metadata: map[string]interface{}{
-			"order_id": req.OrderID,
+			"order_id":      req.OrderID,
+			"shop_currency": presentment.ShopCurrency,
+			"shop_amount":   due.String(),
+			"exchange_rate": presentment.Rate,
 		},
 	}
 
@@ -189,6 +313,11 @@ func (h *PaymentHandler) CreateCheckoutSession(c *fiber.Ctx) error {
 		ClientSecret:       paymentResp.ClientSecret,
 		Status:             paymentResp.Status,
 		PaymentMethodTypes: []string{req.PaymentMethodType},
+		Amount:             presentmentAmount,
+		CurrencyCode:       presentment.CurrencyCode,
+		ExchangeRate:       presentment.Rate,
+		GiftCardAmount:     tender.GiftCards,
+		StoreCreditAmount:  tender.StoreCredit,
 	}
 
 	if paymentResp.NextAction != nil {
@@ -283,26 +412,17 @@ func (h *PaymentHandler) ConfirmPayment(c *fiber.Ctx) error {
 		})
 	}
 
+	shop, err := h.repository.GetShop(c.Context(), shopID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Status:  "error",
+			Message: "Failed to fetch shop",
+			Code:    fiber.StatusInternalServerError,
+		})
+	}
+
 	// Update order status to paid/processing
-	err = h.repository.UpdateOrder(c.Context(), db.UpdateOrderParams{
-		Status:          db.OrderStatusType("processing"),
-		Amount:          order.Amount,
-		Discount:        order.Discount,
-		ShippingCost:    order.ShippingCost,
-		Tax:             order.Tax,
-		ShippingAddress: order.ShippingAddress,
-		PaymentMethod:   order.PaymentMethod,
-		PaymentStatus:   db.PaymentStatusType("paid"),
-		ShippingMethod:  order.ShippingMethod,
-		ShippingStatus:  order.ShippingStatus,
-		TransactionID:   &paymentResp.TransactionID,
-		Username:        order.Username,
-		CustomerName:    order.CustomerName,
-		CustomerEmail:   order.CustomerEmail,
-		CustomerPhone:   order.CustomerPhone,
-		OrderID:         req.OrderID,
-		ShopID:          shopID,
-	})
+	err = completeOrderPayment(c.Context(), h.repository, h.digitalDelivery, order, shop.CurrencyCode, paymentResp.TransactionID)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
 			Status:  "error",
@@ -319,6 +439,39 @@ func (h *PaymentHandler) ConfirmPayment(c *fiber.Ctx) error {
 	return c.JSON(response)
 }
 
+// completeOrderPayment marks an order paid and ready for fulfillment, issues
+// the gift cards it bought and delivers its digital goods
+func completeOrderPayment(ctx context.Context, repo db.Repository, digitalDelivery *services.DigitalDelivery, order db.Order, currency, transactionID string) error {
+	return repo.WithTx(ctx, func(q *db.Queries) error {
+		if err := q.UpdateOrder(ctx, db.UpdateOrderParams{
+			Status:          db.OrderStatusTypeProcessing,
+			Amount:          order.Amount,
+			Discount:        order.Discount,
+			ShippingCost:    order.ShippingCost,
+			Tax:             order.Tax,
+			ShippingAddress: order.ShippingAddress,
+			PaymentMethod:   order.PaymentMethod,
+			PaymentStatus:   db.PaymentStatusTypePaid,
+			ShippingMethod:  order.ShippingMethod,
+			ShippingStatus:  order.ShippingStatus,
+			TransactionID:   &transactionID,
+			Username:        order.Username,
+			CustomerName:    order.CustomerName,
+			CustomerEmail:   order.CustomerEmail,
+			CustomerPhone:   order.CustomerPhone,
+			OrderID:         order.OrderID,
+			ShopID:          order.ShopID,
+		}); err != nil {
+			return err
+		}
+		if _, err := services.IssueOrderGiftCards(ctx, q, order, currency); err != nil {
+			return err
+		}
+		_, err := services.DeliverOrderDigitalGoods(ctx, q, digitalDelivery, order)
+		return err
+	})
+}
+
 // GetPaymentStatus retrieves the status of a payment intent
 // @Summary Get payment status
 // @Description Retrieves the current status of a Stripe payment intent
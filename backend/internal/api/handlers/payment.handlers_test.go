@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/petrejonn/naytife/internal/db"
+	"github.com/petrejonn/naytife/internal/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// paymentRepository holds the pending orders checkouts are started for
+type paymentRepository struct {
+	db.Repository
+	orders map[int64]db.Order
+}
+
+func (r *paymentRepository) GetOrder(ctx context.Context, arg db.GetOrderParams) (db.Order, error) {
+	return r.orders[arg.OrderID], nil
+}
+
+func TestCreateCheckoutSessionStoreCredit(t *testing.T) {
+	customerID := uuid.New()
+	repo := &paymentRepository{orders: map[int64]db.Order{
+		7: {OrderID: 7, Status: db.OrderStatusTypePending, Amount: money.New(5000, "USD").Numeric(), ShopCustomerID: pgtype.UUID{Bytes: customerID, Valid: true}, ShopID: 1},
+		8: {OrderID: 8, Status: db.OrderStatusTypePending, Amount: money.New(5000, "USD").Numeric(), ShopID: 1},
+	}}
+
+	tests := []struct {
+		name     string
+		orderID  string
+		customer *uuid.UUID
+	}{
+		{"anonymous request", "7", nil},
+		{"another customer", "7", ptrUUID(uuid.New())},
+		{"guest order", "8", &customerID},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewPaymentHandler(nil, repo, nil, nil)
+			app := fiber.New()
+			app.Post("/payments/checkout", func(c *fiber.Ctx) error {
+				if tt.customer != nil {
+					c.Locals("shop_customer_id", *tt.customer)
+				}
+				return h.CreateCheckoutSession(c)
+			})
+
+			body := `{"order_id": ` + tt.orderID + `, "shop_id": 1, "payment_method_type": "stripe", "use_store_credit": true}`
+			req := httptest.NewRequest(fiber.MethodPost, "/payments/checkout", strings.NewReader(body))
+			req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+		})
+	}
+}
+
+func ptrUUID(id uuid.UUID) *uuid.UUID {
+	return &id
+}
@@ -108,6 +108,7 @@ func (h *Handler) CreateProductTypeFromTemplate(c *fiber.Ctx) error {
 			Title:        selectedTemplate.Title,
 			Shippable:    selectedTemplate.Shippable,
 			Digital:      selectedTemplate.Digital,
+			GiftCard:     selectedTemplate.GiftCard,
 			SkuSubstring: &selectedTemplate.SkuSubstring,
 			ShopID:       shopID,
 		}
@@ -122,6 +123,7 @@ func (h *Handler) CreateProductTypeFromTemplate(c *fiber.Ctx) error {
 			Title:        productTypeDB.Title,
 			Shippable:    productTypeDB.Shippable,
 			Digital:      productTypeDB.Digital,
+			GiftCard:     productTypeDB.GiftCard,
 			SkuSubstring: productTypeDB.SkuSubstring,
 		}
 
@@ -48,6 +48,12 @@ func (h *Handler) CreateProductType(c *fiber.Ctx) error {
 		}
 	}
 
+	// Gift cards are delivered by email
+	if productType.GiftCard {
+		productType.Digital = true
+		productType.Shippable = false
+	}
+
 	// Use first 3 characters of title (uppercase) if sku_substring is not provided
 	var skuSubstring string
 	if productType.SkuSubstring != nil {
@@ -59,11 +65,15 @@ func (h *Handler) CreateProductType(c *fiber.Ctx) error {
 	}
 
 	param := db.CreateProductTypeParams{
-		Title:        productType.Title,
-		Shippable:    productType.Shippable,
-		Digital:      productType.Digital,
-		SkuSubstring: &skuSubstring,
-		ShopID:       shopID,
+		Title:           productType.Title,
+		Shippable:       productType.Shippable,
+		Digital:         productType.Digital,
+		GiftCard:        productType.GiftCard,
+		TracksLots:      productType.TracksLots,
+		SkuSubstring:    &skuSubstring,
+		ReorderPoint:    productType.ReorderPoint,
+		ReorderQuantity: productType.ReorderQuantity,
+		ShopID:          shopID,
 	}
 
 	objDB, err := h.Repository.CreateProductType(c.Context(), param)
@@ -79,11 +89,15 @@ func (h *Handler) CreateProductType(c *fiber.Ctx) error {
 	}
 
 	resp := models.ProductType{
-		ID:           objDB.ProductTypeID,
-		Title:        objDB.Title,
-		Shippable:    objDB.Shippable,
-		Digital:      objDB.Digital,
-		SkuSubstring: objDB.SkuSubstring,
+		ID:              objDB.ProductTypeID,
+		Title:           objDB.Title,
+		Shippable:       objDB.Shippable,
+		Digital:         objDB.Digital,
+		GiftCard:        objDB.GiftCard,
+		TracksLots:      objDB.TracksLots,
+		SkuSubstring:    objDB.SkuSubstring,
+		ReorderPoint:    objDB.ReorderPoint,
+		ReorderQuantity: objDB.ReorderQuantity,
 	}
 	return api.SuccessResponse(c, fiber.StatusCreated, resp, "Product type created")
 }
@@ -111,11 +125,15 @@ func (h *Handler) GetProductTypes(c *fiber.Ctx) error {
 	resp := make([]models.ProductType, len(objsDB))
 	for i, objDB := range objsDB {
 		resp[i] = models.ProductType{
-			ID:           objDB.ProductTypeID,
-			Title:        objDB.Title,
-			Shippable:    objDB.Shippable,
-			Digital:      objDB.Digital,
-			SkuSubstring: objDB.SkuSubstring,
+			ID:              objDB.ProductTypeID,
+			Title:           objDB.Title,
+			Shippable:       objDB.Shippable,
+			Digital:         objDB.Digital,
+			GiftCard:        objDB.GiftCard,
+			TracksLots:      objDB.TracksLots,
+			SkuSubstring:    objDB.SkuSubstring,
+			ReorderPoint:    objDB.ReorderPoint,
+			ReorderQuantity: objDB.ReorderQuantity,
 		}
 	}
 	return api.SuccessResponse(c, fiber.StatusOK, resp, "Product types fetched successfully")
@@ -155,11 +173,15 @@ func (h *Handler) GetProductType(c *fiber.Ctx) error {
 	}
 
 	resp := models.ProductType{
-		ID:           objDB.ProductTypeID,
-		Title:        objDB.Title,
-		Shippable:    objDB.Shippable,
-		Digital:      objDB.Digital,
-		SkuSubstring: objDB.SkuSubstring,
+		ID:              objDB.ProductTypeID,
+		Title:           objDB.Title,
+		Shippable:       objDB.Shippable,
+		Digital:         objDB.Digital,
+		GiftCard:        objDB.GiftCard,
+		TracksLots:      objDB.TracksLots,
+		SkuSubstring:    objDB.SkuSubstring,
+		ReorderPoint:    objDB.ReorderPoint,
+		ReorderQuantity: objDB.ReorderQuantity,
 	}
 	return api.SuccessResponse(c, fiber.StatusOK, resp, "Product type fetched successfully")
 }
@@ -200,13 +222,24 @@ func (h *Handler) UpdateProductType(c *fiber.Ctx) error {
 		}
 	}
 
+	// Gift cards are delivered by email
+	if productType.GiftCard != nil && *productType.GiftCard {
+		digital, shippable := true, false
+		productType.Digital = &digital
+		productType.Shippable = &shippable
+	}
+
 	param := db.UpdateProductTypeParams{
-		Title:         productType.Title,
-		Shippable:     productType.Shippable,
-		Digital:       productType.Digital,
-		SkuSubstring:  productType.SkuSubstring,
-		ProductTypeID: productTypeID,
-		ShopID:        shopID,
+		Title:           productType.Title,
+		Shippable:       productType.Shippable,
+		Digital:         productType.Digital,
+		GiftCard:        productType.GiftCard,
+		TracksLots:      productType.TracksLots,
+		SkuSubstring:    productType.SkuSubstring,
+		ReorderPoint:    productType.ReorderPoint,
+		ReorderQuantity: productType.ReorderQuantity,
+		ProductTypeID:   productTypeID,
+		ShopID:          shopID,
 	}
 
 	objDB, err := h.Repository.UpdateProductType(c.Context(), param)
@@ -226,11 +259,15 @@ func (h *Handler) UpdateProductType(c *fiber.Ctx) error {
 	}
 
 	resp := models.ProductType{
-		ID:           objDB.ProductTypeID,
-		Title:        objDB.Title,
-		Shippable:    objDB.Shippable,
-		Digital:      objDB.Digital,
-		SkuSubstring: objDB.SkuSubstring,
+		ID:              objDB.ProductTypeID,
+		Title:           objDB.Title,
+		Shippable:       objDB.Shippable,
+		Digital:         objDB.Digital,
+		GiftCard:        objDB.GiftCard,
+		TracksLots:      objDB.TracksLots,
+		SkuSubstring:    objDB.SkuSubstring,
+		ReorderPoint:    objDB.ReorderPoint,
+		ReorderQuantity: objDB.ReorderQuantity,
 	}
 	return api.SuccessResponse(c, fiber.StatusOK, resp, "Product type updated successfully")
 }
@@ -269,11 +306,15 @@ func (h *Handler) DeleteProductType(c *fiber.Ctx) error {
 	}
 
 	resp := models.ProductType{
-		ID:           objDB.ProductTypeID,
-		Title:        objDB.Title,
-		Shippable:    objDB.Shippable,
-		Digital:      objDB.Digital,
-		SkuSubstring: objDB.SkuSubstring,
+		ID:              objDB.ProductTypeID,
+		Title:           objDB.Title,
+		Shippable:       objDB.Shippable,
+		Digital:         objDB.Digital,
+		GiftCard:        objDB.GiftCard,
+		TracksLots:      objDB.TracksLots,
+		SkuSubstring:    objDB.SkuSubstring,
+		ReorderPoint:    objDB.ReorderPoint,
+		ReorderQuantity: objDB.ReorderQuantity,
 	}
 	return api.SuccessResponse(c, fiber.StatusOK, resp, "Product type deleted successfully")
 }
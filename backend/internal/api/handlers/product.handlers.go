@@ -17,6 +17,7 @@ import (
 	"github.com/petrejonn/naytife/internal/db"
 	"github.com/petrejonn/naytife/internal/db/errors"
 	"github.com/petrejonn/naytife/internal/observability"
+	"github.com/petrejonn/naytife/internal/services"
 	"go.uber.org/zap"
 	// ic and observability not required here; updateStoreDataWithCtx in shop.handlers.go provides tracing
 )
@@ -243,7 +244,14 @@ func (h *Handler) CreateProduct(c *fiber.Ctx) error {
 			}
 		}
 
-		return nil
+		// Stock set on the variants is held at the default location
+		if err := services.ReconcileProductStock(c.Context(), q, shopID, product.ProductID); err != nil {
+			return fmt.Errorf("failed to reconcile product stock: %w", err)
+		}
+
+		// Record the initial state as the first revision
+		_, err = recordProductRevision(c.Context(), q, shopID, product.ProductID, requestUser(c), nil)
+		return err
 	})
 
 	if err != nil {
@@ -522,7 +530,13 @@ func (h *Handler) UpdateProduct(c *fiber.Ctx) error {
 	}
 
 	// Execute transaction
+	author := requestUser(c)
 	err = h.Repository.WithTx(c.Context(), func(q *db.Queries) error {
+		// Capture the pre-update state so the first update can be rolled back
+		if err := ensureBaselineRevision(c.Context(), q, shopID, productID, author); err != nil {
+			return err
+		}
+
 		// Update base product
 		err := q.UpdateProduct(c.Context(), db.UpdateProductParams{
 			Title:       product.Title,
@@ -724,7 +738,13 @@ func (h *Handler) UpdateProduct(c *fiber.Ctx) error {
 			}
 		}
 
-		return nil
+		if err := services.ReconcileProductStock(c.Context(), q, shopID, productID); err != nil {
+			return fmt.Errorf("failed to reconcile product stock: %w", err)
+		}
+
+		// Record the updated state as a new revision
+		_, err = recordProductRevision(c.Context(), q, shopID, productID, author, nil)
+		return err
 	})
 
 	if err != nil {
@@ -0,0 +1,643 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/petrejonn/naytife/internal/api"
+	"github.com/petrejonn/naytife/internal/api/models"
+	"github.com/petrejonn/naytife/internal/db"
+	"github.com/petrejonn/naytife/internal/db/errors"
+	"github.com/petrejonn/naytife/internal/observability"
+	"github.com/petrejonn/naytife/internal/services"
+	"go.uber.org/zap"
+)
+
+// GetProductRevisions lists the recorded revisions of a product
+// @Summary List product revisions
+// @Description Get the paginated revision history of a product, newest first
+// @Tags Product
+// @Produce json
+// @Param shop_id path string true "Shop ID"
+// @Param product_id path string true "Product ID"
+// @Param limit query int false "Limit" default(20)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} models.SuccessResponse{data=[]models.ProductRevision} "Product revisions fetched successfully"
+// @Failure 400 {object} models.ErrorResponse "Bad request"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Security OAuth2AccessCode
+// @Router /shops/{shop_id}/products/{product_id}/revisions [get]
+func (h *Handler) GetProductRevisions(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	productID, err := api.ParseIDParameter(c, "product_id", "Product")
+	if err != nil {
+		return err
+	}
+
+	limit, offset, err := api.ParsePaginationParams(c)
+	if err != nil {
+		return api.BusinessLogicErrorResponse(c, "Invalid pagination parameters")
+	}
+
+	revisions, err := h.Repository.GetProductRevisions(c.Context(), db.GetProductRevisionsParams{
+		ProductID: productID,
+		ShopID:    shopID,
+		Limit:     int32(limit),
+		Offset:    int32(offset),
+	})
+	if err != nil {
+		zap.L().Error("GetProductRevisions: failed to fetch revisions", zap.Int64("shop_id", shopID), zap.Int64("product_id", productID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to fetch product revisions")
+	}
+
+	totalCount, err := h.Repository.CountProductRevisions(c.Context(), db.CountProductRevisionsParams{
+		ProductID: productID,
+		ShopID:    shopID,
+	})
+	if err != nil {
+		zap.L().Error("GetProductRevisions: failed to count revisions", zap.Int64("shop_id", shopID), zap.Int64("product_id", productID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to count product revisions")
+	}
+
+	response := make([]models.ProductRevision, len(revisions))
+	for i, revision := range revisions {
+		response[i] = models.ProductRevision{
+			RevisionID:          revision.ProductRevisionID,
+			ProductID:           revision.ProductID,
+			Version:             revision.Version,
+			CreatedBy:           revision.CreatedBy,
+			RestoredFromVersion: revision.RestoredFromVersion,
+			CreatedAt:           revision.CreatedAt.Time,
+		}
+	}
+
+	page := (offset / limit) + 1
+	return api.PaginatedSuccessResponse(c, fiber.StatusOK, response, totalCount, page, limit, "Product revisions fetched successfully")
+}
+
+// GetProductRevision fetches a single revision of a product including its snapshot
+// @Summary Get a product revision
+// @Description Get a revision of a product with the full snapshot recorded for it
+// @Tags Product
+// @Produce json
+// @Param shop_id path string true "Shop ID"
+// @Param product_id path string true "Product ID"
+// @Param version path int true "Revision version"
+// @Success 200 {object} models.SuccessResponse{data=models.ProductRevision} "Product revision fetched successfully"
+// @Failure 400 {object} models.ErrorResponse "Bad request"
+// @Failure 404 {object} models.ErrorResponse "Revision not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Security OAuth2AccessCode
+// @Router /shops/{shop_id}/products/{product_id}/revisions/{version} [get]
+func (h *Handler) GetProductRevision(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	productID, err := api.ParseIDParameter(c, "product_id", "Product")
+	if err != nil {
+		return err
+	}
+	version, err := strconv.ParseInt(c.Params("version"), 10, 32)
+	if err != nil || version < 1 {
+		return api.BusinessLogicErrorResponse(c, "Invalid revision version")
+	}
+
+	revision, snapshot, err := h.loadProductRevision(c.Context(), shopID, productID, int32(version))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return api.NotFoundErrorResponse(c, "Revision")
+		}
+		zap.L().Error("GetProductRevision: failed to load revision", zap.Int64("shop_id", shopID), zap.Int64("product_id", productID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to fetch product revision")
+	}
+
+	response := models.ProductRevision{
+		RevisionID:          revision.ProductRevisionID,
+		ProductID:           revision.ProductID,
+		Version:             revision.Version,
+		CreatedBy:           revision.CreatedBy,
+		RestoredFromVersion: revision.RestoredFromVersion,
+		CreatedAt:           revision.CreatedAt.Time,
+		Snapshot:            &snapshot,
+	}
+	return api.SuccessResponse(c, fiber.StatusOK, response, "Product revision fetched successfully")
+}
+
+// DiffProductRevisions compares two revisions of a product
+// @Summary Diff two product revisions
+// @Description List the values added, removed or modified between two revisions of a product
+// @Tags Product
+// @Produce json
+// @Param shop_id path string true "Shop ID"
+// @Param product_id path string true "Product ID"
+// @Param from query int true "Version to compare from"
+// @Param to query int true "Version to compare to"
+// @Success 200 {object} models.SuccessResponse{data=models.ProductRevisionDiff} "Product revisions compared successfully"
+// @Failure 400 {object} models.ErrorResponse "Bad request"
+// @Failure 404 {object} models.ErrorResponse "Revision not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Security OAuth2AccessCode
+// @Router /shops/{shop_id}/products/{product_id}/revisions/diff [get]
+func (h *Handler) DiffProductRevisions(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	productID, err := api.ParseIDParameter(c, "product_id", "Product")
+	if err != nil {
+		return err
+	}
+	fromVersion, err := strconv.ParseInt(c.Query("from"), 10, 32)
+	if err != nil || fromVersion < 1 {
+		return api.BusinessLogicErrorResponse(c, "Invalid 'from' version")
+	}
+	toVersion, err := strconv.ParseInt(c.Query("to"), 10, 32)
+	if err != nil || toVersion < 1 {
+		return api.BusinessLogicErrorResponse(c, "Invalid 'to' version")
+	}
+
+	_, fromSnapshot, err := h.loadProductRevision(c.Context(), shopID, productID, int32(fromVersion))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return api.NotFoundErrorResponse(c, fmt.Sprintf("Revision %d", fromVersion))
+		}
+		zap.L().Error("DiffProductRevisions: failed to load revision", zap.Int64("shop_id", shopID), zap.Int64("product_id", productID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to fetch product revision")
+	}
+	_, toSnapshot, err := h.loadProductRevision(c.Context(), shopID, productID, int32(toVersion))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return api.NotFoundErrorResponse(c, fmt.Sprintf("Revision %d", toVersion))
+		}
+		zap.L().Error("DiffProductRevisions: failed to load revision", zap.Int64("shop_id", shopID), zap.Int64("product_id", productID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to fetch product revision")
+	}
+
+	changes, err := diffProductSnapshots(fromSnapshot, toSnapshot)
+	if err != nil {
+		zap.L().Error("DiffProductRevisions: failed to compare revisions", zap.Int64("shop_id", shopID), zap.Int64("product_id", productID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to compare product revisions")
+	}
+
+	response := models.ProductRevisionDiff{
+		ProductID:   productID,
+		FromVersion: int32(fromVersion),
+		ToVersion:   int32(toVersion),
+		Changes:     changes,
+	}
+	return api.SuccessResponse(c, fiber.StatusOK, response, "Product revisions compared successfully")
+}
+
+// RestoreProductRevision rolls a product back to a previous revision
+// @Summary Restore a product revision
+// @Description Restore the product, its attribute values, variants and images to the state recorded in a revision.
+// @Description Stock levels of variants that still exist are left untouched. The restore is recorded as a new revision.
+// @Tags Product
+// @Produce json
+// @Param shop_id path string true "Shop ID"
+// @Param product_id path string true "Product ID"
+// @Param version path int true "Revision version"
+// @Success 200 {object} models.SuccessResponse{data=models.ProductRevision} "Product revision restored successfully"
+// @Failure 400 {object} models.ErrorResponse "Bad request"
+// @Failure 404 {object} models.ErrorResponse "Revision not found"
+// @Failure 409 {object} models.ErrorResponse "Revision conflicts with the current catalog"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Security OAuth2AccessCode
+// @Router /shops/{shop_id}/products/{product_id}/revisions/{version}/restore [post]
+func (h *Handler) RestoreProductRevision(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	productID, err := api.ParseIDParameter(c, "product_id", "Product")
+	if err != nil {
+		return err
+	}
+	version, err := strconv.ParseInt(c.Params("version"), 10, 32)
+	if err != nil || version < 1 {
+		return api.BusinessLogicErrorResponse(c, "Invalid revision version")
+	}
+	restoredFrom := int32(version)
+
+	_, snapshot, err := h.loadProductRevision(c.Context(), shopID, productID, restoredFrom)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return api.NotFoundErrorResponse(c, "Revision")
+		}
+		zap.L().Error("RestoreProductRevision: failed to load revision", zap.Int64("shop_id", shopID), zap.Int64("product_id", productID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to fetch product revision")
+	}
+
+	var restored db.ProductRevision
+	err = h.Repository.WithTx(c.Context(), func(q *db.Queries) error {
+		if err := restoreProductSnapshot(c.Context(), q, shopID, productID, snapshot); err != nil {
+			return err
+		}
+		if err := services.ReconcileProductStock(c.Context(), q, shopID, productID); err != nil {
+			return fmt.Errorf("failed to reconcile product stock: %w", err)
+		}
+		var err error
+		restored, err = recordProductRevision(c.Context(), q, shopID, productID, requestUser(c), &restoredFrom)
+		return err
+	})
+	if err != nil {
+		zap.L().Error("RestoreProductRevision: transaction failed", zap.Int64("shop_id", shopID), zap.Int64("product_id", productID), zap.Int64("version", version), zap.Error(err))
+		if pgErr, ok := err.(*pgconn.PgError); ok {
+			if pgErr.Code == errors.UniqueViolation {
+				return api.ErrorResponse(c, fiber.StatusConflict, fmt.Sprintf("Revision conflicts with the current catalog: %s", pgErr.ConstraintName), nil)
+			}
+			if pgErr.Code == errors.ForeignKeyViolation {
+				return api.ErrorResponse(c, fiber.StatusConflict, fmt.Sprintf("Revision references data that no longer exists: %s", pgErr.ConstraintName), nil)
+			}
+		}
+		if fiberErr, ok := err.(*fiber.Error); ok {
+			return api.ErrorResponse(c, fiberErr.Code, fiberErr.Message, nil)
+		}
+		return api.SystemErrorResponse(c, err, "Failed to restore product revision")
+	}
+
+	// Fetch shop for subdomain
+	shop, err := h.Repository.GetShop(c.Context(), shopID)
+	if err != nil {
+		zap.L().Warn("RestoreProductRevision: auto-publish - failed to get shop for auto-publish", zap.Int64("shop_id", shopID), zap.Error(err))
+		return api.ErrorResponse(c, fiber.StatusInternalServerError, "Product restored, but failed to fetch shop for auto-publish", nil)
+	}
+	// Auto-publish asynchronously via StoreDeployerClient
+	go func(shopID int64, subdomain string) {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		ctx, finish := observability.StartSpan(ctx, "autoPublishProductChanges", "store-deployer", "POST", "update-data")
+		defer finish(0, nil)
+
+		if err := h.StoreDeployerClient.UpdateData(ctx, subdomain, shopID, "products"); err != nil {
+			zap.L().Warn("RestoreProductRevision: auto-publish - update failed", zap.Int64("shop_id", shopID), zap.Error(err))
+		}
+	}(shopID, shop.Subdomain)
+
+	response := models.ProductRevision{
+		RevisionID:          restored.ProductRevisionID,
+		ProductID:           restored.ProductID,
+		Version:             restored.Version,
+		CreatedBy:           restored.CreatedBy,
+		RestoredFromVersion: restored.RestoredFromVersion,
+		CreatedAt:           restored.CreatedAt.Time,
+	}
+	return api.SuccessResponse(c, fiber.StatusOK, response, fmt.Sprintf("Product restored to version %d", version))
+}
+
+// loadProductRevision fetches a revision and decodes its snapshot
+func (h *Handler) loadProductRevision(ctx context.Context, shopID, productID int64, version int32) (db.ProductRevision, models.ProductSnapshot, error) {
+	var snapshot models.ProductSnapshot
+	revision, err := h.Repository.GetProductRevision(ctx, db.GetProductRevisionParams{
+		ProductID: productID,
+		ShopID:    shopID,
+		Version:   version,
+	})
+	if err != nil {
+		return revision, snapshot, err
+	}
+	if err := json.Unmarshal(revision.Snapshot, &snapshot); err != nil {
+		return revision, snapshot, fmt.Errorf("failed to decode revision snapshot: %w", err)
+	}
+	return revision, snapshot, nil
+}
+
+// requestUser returns the signed-in user of a request, recorded as the author of
+// revisions and data requests
+func requestUser(c *fiber.Ctx) *string {
+	userID, _ := c.Locals("user_id").(string)
+	if userID == "" {
+		return nil
+	}
+	return &userID
+}
+
+// recordProductRevision snapshots the current state of a product as its next revision
+func recordProductRevision(ctx context.Context, q *db.Queries, shopID, productID int64, author *string, restoredFrom *int32) (db.ProductRevision, error) {
+	if err := lockProductRevisions(ctx, q, shopID, productID); err != nil {
+		return db.ProductRevision{}, err
+	}
+	revision, err := q.CreateProductRevision(ctx, db.CreateProductRevisionParams{
+		CreatedBy:           author,
+		RestoredFromVersion: restoredFrom,
+		ProductID:           productID,
+		ShopID:              shopID,
+	})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return revision, fiber.NewError(fiber.StatusNotFound, "Product not found")
+		}
+		return revision, fmt.Errorf("failed to record product revision: %w", err)
+	}
+	return revision, nil
+}
+
+// ensureBaselineRevision records the current state of a product that predates
+// revision tracking, so its first update can still be rolled back
+func ensureBaselineRevision(ctx context.Context, q *db.Queries, shopID, productID int64, author *string) error {
+	if err := lockProductRevisions(ctx, q, shopID, productID); err != nil {
+		return err
+	}
+	count, err := q.CountProductRevisions(ctx, db.CountProductRevisionsParams{
+		ProductID: productID,
+		ShopID:    shopID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to count product revisions: %w", err)
+	}
+	if count > 0 {
+		return nil
+	}
+	_, err = recordProductRevision(ctx, q, shopID, productID, author, nil)
+	return err
+}
+
+// lockProductRevisions locks a product until the transaction ends, so that
+// concurrent saves number their revisions one after the other. The version is
+// read by the next statement, which sees revisions committed while waiting.
+func lockProductRevisions(ctx context.Context, q *db.Queries, shopID, productID int64) error {
+	_, err := q.LockProductRevisions(ctx, db.LockProductRevisionsParams{
+		ProductID: productID,
+		ShopID:    shopID,
+	})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return fiber.NewError(fiber.StatusNotFound, "Product not found")
+		}
+		return fmt.Errorf("failed to lock product revisions: %w", err)
+	}
+	return nil
+}
+
+// restoreProductSnapshot overwrites a product and its children with a snapshot.
+// Variants removed since the snapshot are recreated with their original IDs.
+func restoreProductSnapshot(ctx context.Context, q *db.Queries, shopID, productID int64, snapshot models.ProductSnapshot) error {
+	err := q.RestoreProduct(ctx, db.RestoreProductParams{
+		Slug:          snapshot.Product.Slug,
+		Title:         snapshot.Product.Title,
+		Description:   snapshot.Product.Description,
+		Status:        snapshot.Product.Status,
+		ProductTypeID: snapshot.Product.ProductTypeID,
+		CategoryID:    snapshot.Product.CategoryID,
+		ProductID:     productID,
+		ShopID:        shopID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to restore product: %w", err)
+	}
+
+	// Attribute values
+	err = q.DeleteProductAttributeValuesByProduct(ctx, db.DeleteProductAttributeValuesByProductParams{
+		ProductID: productID,
+		ShopID:    shopID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to clear product attributes: %w", err)
+	}
+	if len(snapshot.Attributes) > 0 {
+		attributeParams := make([]db.BatchUpsertProductAttributeValuesParams, len(snapshot.Attributes))
+		for i, attr := range snapshot.Attributes {
+			attributeParams[i] = db.BatchUpsertProductAttributeValuesParams{
+				Value:             attr.Value,
+				AttributeOptionID: attr.AttributeOptionID,
+				ProductID:         productID,
+				AttributeID:       attr.AttributeID,
+				ShopID:            shopID,
+			}
+		}
+		batch := q.BatchUpsertProductAttributeValues(ctx, attributeParams)
+		var batchErr error
+		batch.Exec(func(_ int, err error) {
+			if err != nil && batchErr == nil {
+				batchErr = err
+			}
+		})
+		if batchErr != nil {
+			return batchErr
+		}
+		if err := batch.Close(); err != nil {
+			return fmt.Errorf("failed to restore product attributes: %w", err)
+		}
+	}
+
+	// Variants
+	variantIDs := make([]int64, 0, len(snapshot.Variants))
+	for _, variant := range snapshot.Variants {
+		err := q.RestoreProductVariation(ctx, db.RestoreProductVariationParams{
+			ProductVariationID: variant.ProductVariationID,
+			Sku:                variant.Sku,
+			Description:        variant.Description,
+			Price:              variant.Price,
+			AvailableQuantity:  variant.AvailableQuantity,
+			SeoDescription:     variant.SeoDescription,
+			SeoKeywords:        variant.SeoKeywords,
+			SeoTitle:           variant.SeoTitle,
+			IsDefault:          variant.IsDefault,
+			ProductID:          productID,
+			ShopID:             shopID,
+		})
+		if err != nil {
+			return err
+		}
+		variantIDs = append(variantIDs, variant.ProductVariationID)
+	}
+	err = q.DeleteProductVariants(ctx, db.DeleteProductVariantsParams{
+		ShopID:              shopID,
+		ProductID:           productID,
+		ProductVariationIds: variantIDs,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete variants added after the revision: %w", err)
+	}
+
+	// Variant attribute values
+	err = q.DeleteProductVariationAttributeValuesByProduct(ctx, db.DeleteProductVariationAttributeValuesByProductParams{
+		ProductID: productID,
+		ShopID:    shopID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to clear variant attributes: %w", err)
+	}
+	var variantAttrParams []db.BatchUpsertProductVariationAttributeValuesParams
+	for _, variant := range snapshot.Variants {
+		for _, attr := range variant.Attributes {
+			variantAttrParams = append(variantAttrParams, db.BatchUpsertProductVariationAttributeValuesParams{
+				Value:              attr.Value,
+				AttributeOptionID:  attr.AttributeOptionID,
+				ProductVariationID: variant.ProductVariationID,
+				AttributeID:        attr.AttributeID,
+				ShopID:             shopID,
+			})
+		}
+	}
+	if len(variantAttrParams) > 0 {
+		batch := q.BatchUpsertProductVariationAttributeValues(ctx, variantAttrParams)
+		var batchErr error
+		batch.Exec(func(_ int, err error) {
+			if err != nil && batchErr == nil {
+				batchErr = err
+			}
+		})
+		if batchErr != nil {
+			return batchErr
+		}
+		if err := batch.Close(); err != nil {
+			return fmt.Errorf("failed to restore variant attributes: %w", err)
+		}
+	}
+
+	// Images
+	err = q.DeleteAllProductImages(ctx, db.DeleteAllProductImagesParams{
+		ProductID: productID,
+		ShopID:    shopID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to clear product images: %w", err)
+	}
+	for _, image := range snapshot.Images {
+		err := q.RestoreProductImage(ctx, db.RestoreProductImageParams{
+			ProductImageID: image.ProductImageID,
+			Url:            image.Url,
+			Alt:            image.Alt,
+			ProductID:      productID,
+			ShopID:         shopID,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// revisionIgnoredFields are bookkeeping columns that are not reported in diffs
+var revisionIgnoredFields = map[string]bool{
+	"created_at": true,
+	"updated_at": true,
+	"shop_id":    true,
+	"product_id": true,
+}
+
+// diffProductSnapshots lists the changes needed to turn one snapshot into another.
+// Rows are matched by ID; a row present on one side only is reported as a whole.
+func diffProductSnapshots(from, to models.ProductSnapshot) ([]models.ProductRevisionChange, error) {
+	fromEntities, err := flattenProductSnapshot(from)
+	if err != nil {
+		return nil, err
+	}
+	toEntities, err := flattenProductSnapshot(to)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]bool)
+	for key := range fromEntities {
+		keys[key] = true
+	}
+	for key := range toEntities {
+		keys[key] = true
+	}
+	sortedKeys := make([]string, 0, len(keys))
+	for key := range keys {
+		sortedKeys = append(sortedKeys, key)
+	}
+	sort.Strings(sortedKeys)
+
+	changes := []models.ProductRevisionChange{}
+	for _, key := range sortedKeys {
+		fromEntity, inFrom := fromEntities[key]
+		toEntity, inTo := toEntities[key]
+		switch {
+		case !inFrom:
+			changes = append(changes, models.ProductRevisionChange{Path: key, Change: "added", To: toEntity})
+		case !inTo:
+			changes = append(changes, models.ProductRevisionChange{Path: key, Change: "removed", From: fromEntity})
+		default:
+			fields := make([]string, 0, len(fromEntity))
+			for field := range fromEntity {
+				fields = append(fields, field)
+			}
+			for field := range toEntity {
+				if _, ok := fromEntity[field]; !ok {
+					fields = append(fields, field)
+				}
+			}
+			sort.Strings(fields)
+			for _, field := range fields {
+				if !reflect.DeepEqual(fromEntity[field], toEntity[field]) {
+					changes = append(changes, models.ProductRevisionChange{
+						Path:   key + "." + field,
+						Change: "modified",
+						From:   fromEntity[field],
+						To:     toEntity[field],
+					})
+				}
+			}
+		}
+	}
+	return changes, nil
+}
+
+// flattenProductSnapshot indexes every row of a snapshot by a stable path
+func flattenProductSnapshot(snapshot models.ProductSnapshot) (map[string]map[string]interface{}, error) {
+	entities := make(map[string]map[string]interface{})
+	add := func(key string, row interface{}, skip ...string) error {
+		raw, err := json.Marshal(row)
+		if err != nil {
+			return err
+		}
+		var fields map[string]interface{}
+		if err := json.Unmarshal(raw, &fields); err != nil {
+			return err
+		}
+		for field := range fields {
+			if revisionIgnoredFields[field] {
+				delete(fields, field)
+			}
+		}
+		for _, field := range skip {
+			delete(fields, field)
+		}
+		entities[key] = fields
+		return nil
+	}
+
+	if err := add("product", snapshot.Product); err != nil {
+		return nil, err
+	}
+	for _, attr := range snapshot.Attributes {
+		if err := add(fmt.Sprintf("attributes[%d]", attr.AttributeID), attr, "product_attribute_value_id"); err != nil {
+			return nil, err
+		}
+	}
+	for _, variant := range snapshot.Variants {
+		key := fmt.Sprintf("variants[%d]", variant.ProductVariationID)
+		if err := add(key, variant.ProductVariation, "product_variation_id"); err != nil {
+			return nil, err
+		}
+		for _, attr := range variant.Attributes {
+			attrKey := fmt.Sprintf("%s.attributes[%d]", key, attr.AttributeID)
+			if err := add(attrKey, attr, "product_variation_attribute_value_id", "product_variation_id"); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, image := range snapshot.Images {
+		if err := add(fmt.Sprintf("images[%d]", image.ProductImageID), image, "product_image_id"); err != nil {
+			return nil, err
+		}
+	}
+	return entities, nil
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/petrejonn/naytife/internal/api/models"
+	"github.com/petrejonn/naytife/internal/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffProductSnapshots(t *testing.T) {
+	red := "red"
+	blue := "blue"
+	base := func() models.ProductSnapshot {
+		return models.ProductSnapshot{
+			Product: db.Product{ProductID: 1, Slug: "tee", Title: "Tee", Status: db.ProductStatusPUBLISHED, ShopID: 7},
+			Variants: []models.ProductVariantSnapshot{{
+				ProductVariation: db.ProductVariation{ProductVariationID: 10, Sku: "TEE-S", AvailableQuantity: 5, ProductID: 1, ShopID: 7},
+				Attributes:       []db.ProductVariationAttributeValue{{AttributeID: 3, Value: &red, ProductVariationID: 10}},
+			}},
+			Images: []db.ProductImage{{ProductImageID: 20, Url: "https://cdn/a.jpg", Alt: "front", ProductID: 1}},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		change func(*models.ProductSnapshot)
+		want   []models.ProductRevisionChange
+	}{
+		{
+			name:   "unchanged",
+			change: func(*models.ProductSnapshot) {},
+			want:   []models.ProductRevisionChange{},
+		},
+		{
+			name: "bookkeeping fields are ignored",
+			change: func(s *models.ProductSnapshot) {
+				s.Product.ShopID = 8
+				s.Variants[0].UpdatedAt.Valid = true
+			},
+			want: []models.ProductRevisionChange{},
+		},
+		{
+			name:   "product field modified",
+			change: func(s *models.ProductSnapshot) { s.Product.Title = "T-Shirt" },
+			want: []models.ProductRevisionChange{
+				{Path: "product.title", Change: "modified", From: "Tee", To: "T-Shirt"},
+			},
+		},
+		{
+			name: "variant and variant attribute modified",
+			change: func(s *models.ProductSnapshot) {
+				s.Variants[0].AvailableQuantity = 2
+				s.Variants[0].Attributes[0].Value = &blue
+			},
+			want: []models.ProductRevisionChange{
+				{Path: "variants[10].available_quantity", Change: "modified", From: float64(5), To: float64(2)},
+				{Path: "variants[10].attributes[3].value", Change: "modified", From: "red", To: "blue"},
+			},
+		},
+		{
+			name:   "image removed",
+			change: func(s *models.ProductSnapshot) { s.Images = nil },
+			want: []models.ProductRevisionChange{
+				{Path: "images[20]", Change: "removed", From: map[string]interface{}{"url": "https://cdn/a.jpg", "alt": "front"}},
+			},
+		},
+		{
+			name: "image added",
+			change: func(s *models.ProductSnapshot) {
+				s.Images = append(s.Images, db.ProductImage{ProductImageID: 21, Url: "https://cdn/b.jpg", Alt: "back", ProductID: 1})
+			},
+			want: []models.ProductRevisionChange{
+				{Path: "images[21]", Change: "added", To: map[string]interface{}{"url": "https://cdn/b.jpg", "alt": "back"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			to := base()
+			tt.change(&to)
+			changes, err := diffProductSnapshots(base(), to)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, changes)
+		})
+	}
+}
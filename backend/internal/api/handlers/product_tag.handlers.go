@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
+	"github.com/petrejonn/naytife/internal/api"
+	"github.com/petrejonn/naytife/internal/api/models"
+	"github.com/petrejonn/naytife/internal/db"
+	"go.uber.org/zap"
+)
+
+// GetProductTags lists the tags of a product
+// @Summary List product tags
+// @Description Get the tags attached to a product
+// @Tags Product
+// @Produce json
+// @Param shop_id path string true "Shop ID"
+// @Param product_id path string true "Product ID"
+// @Success 200 {object} models.SuccessResponse{data=[]string} "Product tags fetched successfully"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Security OAuth2AccessCode
+// @Router /shops/{shop_id}/products/{product_id}/tags [get]
+func (h *Handler) GetProductTags(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	productID, err := api.ParseIDParameter(c, "product_id", "Product")
+	if err != nil {
+		return err
+	}
+
+	tags, err := h.Repository.GetProductTags(c.Context(), db.GetProductTagsParams{
+		ProductID: productID,
+		ShopID:    shopID,
+	})
+	if err != nil {
+		zap.L().Error("GetProductTags: failed to fetch tags", zap.Int64("shop_id", shopID), zap.Int64("product_id", productID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to fetch product tags")
+	}
+	if tags == nil {
+		tags = []string{}
+	}
+
+	return api.SuccessResponse(c, fiber.StatusOK, tags, "Product tags fetched successfully")
+}
+
+// SetProductTags replaces the tags of a product
+// @Summary Set product tags
+// @Description Replace the tags of a product. Tags are trimmed and duplicates that differ only by case are dropped.
+// @Tags Product
+// @Accept json
+// @Produce json
+// @Param shop_id path string true "Shop ID"
+// @Param product_id path string true "Product ID"
+// @Param tags body models.ProductTagsParams true "Tags"
+// @Success 200 {object} models.SuccessResponse{data=[]string} "Product tags updated successfully"
+// @Failure 400 {object} models.ErrorResponse "Bad request"
+// @Failure 404 {object} models.ErrorResponse "Product not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Security OAuth2AccessCode
+// @Router /shops/{shop_id}/products/{product_id}/tags [put]
+func (h *Handler) SetProductTags(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	productID, err := api.ParseIDParameter(c, "product_id", "Product")
+	if err != nil {
+		return err
+	}
+
+	var param models.ProductTagsParams
+	if err := c.BodyParser(&param); err != nil {
+		zap.L().Warn("SetProductTags: failed to parse request body", zap.Int64("shop_id", shopID), zap.Error(err))
+		return api.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body", nil)
+	}
+	validator := &models.XValidator{}
+	if errs := validator.Validate(&param); len(errs) > 0 {
+		return &fiber.Error{
+			Code:    fiber.ErrBadRequest.Code,
+			Message: models.FormatValidationErrors(errs),
+		}
+	}
+
+	if _, err := h.Repository.GetProduct(c.Context(), db.GetProductParams{
+		ProductID: productID,
+		ShopID:    shopID,
+	}); err != nil {
+		if err == pgx.ErrNoRows {
+			return api.NotFoundErrorResponse(c, "Product")
+		}
+		zap.L().Error("SetProductTags: failed to fetch product", zap.Int64("shop_id", shopID), zap.Int64("product_id", productID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to fetch product")
+	}
+
+	tags := normalizeTags(param.Tags)
+	err = h.Repository.WithTx(c.Context(), func(q *db.Queries) error {
+		if err := q.DeleteProductTags(c.Context(), db.DeleteProductTagsParams{
+			ProductID: productID,
+			ShopID:    shopID,
+		}); err != nil {
+			return err
+		}
+		if len(tags) == 0 {
+			return nil
+		}
+		return q.AddProductTags(c.Context(), db.AddProductTagsParams{
+			ProductID: productID,
+			ShopID:    shopID,
+			Tags:      tags,
+		})
+	})
+	if err != nil {
+		zap.L().Error("SetProductTags: failed to update tags", zap.Int64("shop_id", shopID), zap.Int64("product_id", productID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to update product tags")
+	}
+
+	return api.SuccessResponse(c, fiber.StatusOK, tags, "Product tags updated successfully")
+}
+
+// GetShopTags lists every tag used by the products of a shop
+// @Summary List shop tags
+// @Description Get the tags used across the products of a shop with the number of products carrying each
+// @Tags Product
+// @Produce json
+// @Param shop_id path string true "Shop ID"
+// @Success 200 {object} models.SuccessResponse{data=[]models.ShopTag} "Tags fetched successfully"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Security OAuth2AccessCode
+// @Router /shops/{shop_id}/tags [get]
+func (h *Handler) GetShopTags(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+
+	rows, err := h.Repository.GetShopTags(c.Context(), shopID)
+	if err != nil {
+		zap.L().Error("GetShopTags: failed to fetch tags", zap.Int64("shop_id", shopID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to fetch tags")
+	}
+
+	response := make([]models.ShopTag, len(rows))
+	for i, row := range rows {
+		response[i] = models.ShopTag{Tag: row.Tag, ProductCount: row.ProductCount}
+	}
+
+	return api.SuccessResponse(c, fiber.StatusOK, response, "Tags fetched successfully")
+}
+
+// normalizeTags trims tags, drops empty ones and keeps the first spelling of
+// tags that differ only by case
+func normalizeTags(tags []string) []string {
+	seen := make(map[string]bool, len(tags))
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || seen[strings.ToLower(tag)] {
+			continue
+		}
+		seen[strings.ToLower(tag)] = true
+		normalized = append(normalized, tag)
+	}
+	return normalized
+}
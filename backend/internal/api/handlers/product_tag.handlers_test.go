@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeTags(t *testing.T) {
+	tests := []struct {
+		name string
+		tags []string
+		want []string
+	}{
+		{"nothing", nil, []string{}},
+		{"trims", []string{"  summer ", "linen"}, []string{"summer", "linen"}},
+		{"drops empty", []string{"", "   ", "sale"}, []string{"sale"}},
+		{"keeps first spelling", []string{"Sale", "sale", "SALE", "new"}, []string{"Sale", "new"}},
+		{"duplicate after trimming", []string{"gift ", " Gift"}, []string{"gift"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, normalizeTags(tt.tags))
+		})
+	}
+}
@@ -0,0 +1,462 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/petrejonn/naytife/internal/api"
+	"github.com/petrejonn/naytife/internal/api/models"
+	"github.com/petrejonn/naytife/internal/db"
+	"github.com/petrejonn/naytife/internal/services"
+	"go.uber.org/zap"
+)
+
+// GetPurchaseOrders lists the purchase orders of a shop
+// @Summary      List purchase orders
+// @Description  Get the purchase orders of a shop, newest first
+// @Tags         purchase-orders
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        status query string false "Status" Enums(draft, ordered, partially_received, received)
+// @Param        supplier_id query int false "Supplier ID"
+// @Param        limit query int false "Limit" default(20)
+// @Param        offset query int false "Offset" default(0)
+// @Success      200  {object}   models.SuccessResponse{data=[]models.PurchaseOrder} "Purchase orders fetched successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/purchase-orders [get]
+func (h *Handler) GetPurchaseOrders(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	limit, offset, err := api.ParsePaginationParams(c)
+	if err != nil {
+		return err
+	}
+	var status db.NullPurchaseOrderStatus
+	if s := c.Query("status"); s != "" {
+		switch db.PurchaseOrderStatus(s) {
+		case db.PurchaseOrderStatusDraft, db.PurchaseOrderStatusOrdered, db.PurchaseOrderStatusPartiallyReceived, db.PurchaseOrderStatusReceived:
+			status = db.NullPurchaseOrderStatus{PurchaseOrderStatus: db.PurchaseOrderStatus(s), Valid: true}
+		default:
+			return api.ErrorResponse(c, fiber.StatusBadRequest, "Invalid purchase order status", nil)
+		}
+	}
+	var supplierID *int64
+	if s := c.Query("supplier_id"); s != "" {
+		id, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return api.ErrorResponse(c, fiber.StatusBadRequest, "Invalid supplier ID", nil)
+		}
+		supplierID = &id
+	}
+
+	orders, err := h.Repository.ListPurchaseOrders(c.Context(), db.ListPurchaseOrdersParams{
+		ShopID:     shopID,
+		Status:     status,
+		SupplierID: supplierID,
+		Limit:      int32(limit),
+		Offset:     int32(offset),
+	})
+	if err != nil {
+		zap.L().Error("GetPurchaseOrders: failed to fetch purchase orders", zap.Int64("shop_id", shopID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to fetch purchase orders")
+	}
+	total, err := h.Repository.CountPurchaseOrders(c.Context(), db.CountPurchaseOrdersParams{
+		ShopID:     shopID,
+		Status:     status,
+		SupplierID: supplierID,
+	})
+	if err != nil {
+		zap.L().Error("GetPurchaseOrders: failed to count purchase orders", zap.Int64("shop_id", shopID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to count purchase orders")
+	}
+
+	response := make([]models.PurchaseOrder, len(orders))
+	for i, order := range orders {
+		response[i], _ = models.NewPurchaseOrder(order, nil, "")
+	}
+	page := (offset / limit) + 1
+	return api.PaginatedSuccessResponse(c, fiber.StatusOK, response, total, page, limit, "Purchase orders fetched successfully")
+}
+
+// GetPurchaseOrder fetches a purchase order
+// @Summary      Get a purchase order
+// @Description  Get a purchase order with its items and what has been received of them
+// @Tags         purchase-orders
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        purchase_order_id path string true "Purchase order ID"
+// @Success      200  {object}   models.SuccessResponse{data=models.PurchaseOrder} "Purchase order fetched successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      404  {object}   models.ErrorResponse "Purchase order not found"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/purchase-orders/{purchase_order_id} [get]
+func (h *Handler) GetPurchaseOrder(c *fiber.Ctx) error {
+	order, err := h.purchaseOrderFromPath(c)
+	if err != nil {
+		return err
+	}
+	return h.purchaseOrderResponse(c, fiber.StatusOK, order, "Purchase order fetched successfully")
+}
+
+// CreatePurchaseOrder drafts a purchase order
+// @Summary      Create a purchase order
+// @Description  Draft an order of stock from a supplier. Unit costs are in the shop currency.
+// @Tags         purchase-orders
+// @Accept       json
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        purchase_order body models.PurchaseOrderParams true "Purchase order"
+// @Success      201  {object}   models.SuccessResponse{data=models.PurchaseOrder} "Purchase order created successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      404  {object}   models.ErrorResponse "Supplier, location or variant not found"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/purchase-orders [post]
+func (h *Handler) CreatePurchaseOrder(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	var param models.PurchaseOrderParams
+	if err := c.BodyParser(&param); err != nil {
+		return api.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body", nil)
+	}
+	validator := &models.XValidator{}
+	if errs := validator.Validate(&param); len(errs) > 0 {
+		return api.ErrorResponse(c, fiber.StatusBadRequest, models.FormatValidationErrors(errs), nil)
+	}
+	lines, err := h.purchaseOrderLines(c, shopID, param.Items)
+	if err != nil {
+		return err
+	}
+	if err := h.checkSupplier(c, shopID, param.SupplierID); err != nil {
+		return err
+	}
+
+	var order db.PurchaseOrder
+	err = h.Repository.WithTx(c.Context(), func(q *db.Queries) error {
+		if param.LocationID != nil {
+			if _, err := services.StockLocation(c.Context(), q, shopID, param.LocationID); err != nil {
+				return err
+			}
+		}
+		arg := db.CreatePurchaseOrderParams{
+			SupplierID: param.SupplierID,
+			LocationID: param.LocationID,
+			Note:       param.Note,
+			CreatedBy:  requestUser(c),
+			ShopID:     shopID,
+		}
+		if param.ExpectedAt != nil {
+			arg.ExpectedAt = pgtype.Timestamptz{Time: *param.ExpectedAt, Valid: true}
+		}
+		var err error
+		if order, err = q.CreatePurchaseOrder(c.Context(), arg); err != nil {
+			return err
+		}
+		return services.SetPurchaseOrderItems(c.Context(), q, order, lines)
+	})
+	if err != nil {
+		zap.L().Warn("CreatePurchaseOrder: failed to create purchase order", zap.Int64("shop_id", shopID), zap.Error(err))
+		return stockChangeErrorResponse(c, err, "Failed to create purchase order")
+	}
+	return h.purchaseOrderResponse(c, fiber.StatusCreated, order, "Purchase order created successfully")
+}
+
+// UpdatePurchaseOrder updates a purchase order
+// @Summary      Update a purchase order
+// @Description  Change the delivery location, expected date or note of a purchase order that has not been fully received. The supplier and items can only change while it is a draft; items left out are kept.
+// @Tags         purchase-orders
+// @Accept       json
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        purchase_order_id path string true "Purchase order ID"
+// @Param        purchase_order body models.PurchaseOrderUpdateParams true "Purchase order"
+// @Success      200  {object}   models.SuccessResponse{data=models.PurchaseOrder} "Purchase order updated successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      404  {object}   models.ErrorResponse "Purchase order, supplier, location or variant not found"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/purchase-orders/{purchase_order_id} [put]
+func (h *Handler) UpdatePurchaseOrder(c *fiber.Ctx) error {
+	current, err := h.purchaseOrderFromPath(c)
+	if err != nil {
+		return err
+	}
+	var param models.PurchaseOrderUpdateParams
+	if err := c.BodyParser(&param); err != nil {
+		return api.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body", nil)
+	}
+	validator := &models.XValidator{}
+	if errs := validator.Validate(&param); len(errs) > 0 {
+		return api.ErrorResponse(c, fiber.StatusBadRequest, models.FormatValidationErrors(errs), nil)
+	}
+	if current.Status == db.PurchaseOrderStatusReceived {
+		return api.BusinessLogicErrorResponse(c, "Received purchase orders cannot be changed")
+	}
+	if current.Status != db.PurchaseOrderStatusDraft && (param.SupplierID != current.SupplierID || len(param.Items) > 0) {
+		return api.BusinessLogicErrorResponse(c, "The supplier and items can only change while the purchase order is a draft")
+	}
+	var lines []services.PurchaseOrderLine
+	if len(param.Items) > 0 {
+		if lines, err = h.purchaseOrderLines(c, current.ShopID, param.Items); err != nil {
+			return err
+		}
+	}
+	if param.SupplierID != current.SupplierID {
+		if err := h.checkSupplier(c, current.ShopID, param.SupplierID); err != nil {
+			return err
+		}
+	}
+
+	var order db.PurchaseOrder
+	err = h.Repository.WithTx(c.Context(), func(q *db.Queries) error {
+		if param.LocationID != nil {
+			if _, err := services.StockLocation(c.Context(), q, current.ShopID, param.LocationID); err != nil {
+				return err
+			}
+		}
+		arg := db.UpdatePurchaseOrderParams{
+			PurchaseOrderID: current.PurchaseOrderID,
+			ShopID:          current.ShopID,
+			SupplierID:      param.SupplierID,
+			LocationID:      param.LocationID,
+			Note:            param.Note,
+		}
+		if param.ExpectedAt != nil {
+			arg.ExpectedAt = pgtype.Timestamptz{Time: *param.ExpectedAt, Valid: true}
+		}
+		var err error
+		if order, err = q.UpdatePurchaseOrder(c.Context(), arg); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return services.ErrPurchaseOrderReceived
+			}
+			return err
+		}
+		if lines == nil {
+			return nil
+		}
+		return services.SetPurchaseOrderItems(c.Context(), q, order, lines)
+	})
+	if err != nil {
+		if errors.Is(err, services.ErrPurchaseOrderReceived) {
+			return api.BusinessLogicErrorResponse(c, "Received purchase orders cannot be changed")
+		}
+		zap.L().Warn("UpdatePurchaseOrder: failed to update purchase order", zap.Int64("purchase_order_id", current.PurchaseOrderID), zap.Error(err))
+		return stockChangeErrorResponse(c, err, "Failed to update purchase order")
+	}
+	return h.purchaseOrderResponse(c, fiber.StatusOK, order, "Purchase order updated successfully")
+}
+
+// DeletePurchaseOrder removes a draft purchase order
+// @Summary      Delete a purchase order
+// @Description  Remove a purchase order that has not been placed with the supplier yet
+// @Tags         purchase-orders
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        purchase_order_id path string true "Purchase order ID"
+// @Success      200  {object}   models.SuccessResponse "Purchase order deleted successfully"
+// @Failure      400  {object}   models.ErrorResponse "Purchase order is not a draft"
+// @Failure      404  {object}   models.ErrorResponse "Purchase order not found"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/purchase-orders/{purchase_order_id} [delete]
+func (h *Handler) DeletePurchaseOrder(c *fiber.Ctx) error {
+	order, err := h.purchaseOrderFromPath(c)
+	if err != nil {
+		return err
+	}
+	deleted, err := h.Repository.DeletePurchaseOrder(c.Context(), db.DeletePurchaseOrderParams{
+		PurchaseOrderID: order.PurchaseOrderID,
+		ShopID:          order.ShopID,
+	})
+	if err != nil {
+		zap.L().Error("DeletePurchaseOrder: failed to delete purchase order", zap.Int64("purchase_order_id", order.PurchaseOrderID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to delete purchase order")
+	}
+	if deleted == 0 {
+		return api.BusinessLogicErrorResponse(c, "Only draft purchase orders can be deleted")
+	}
+	return api.SuccessResponse(c, fiber.StatusOK, nil, "Purchase order deleted successfully")
+}
+
+// OrderPurchaseOrder marks a draft purchase order as placed with the supplier
+// @Summary      Place a purchase order
+// @Description  Mark a draft purchase order as sent to the supplier, after which its stock can be received
+// @Tags         purchase-orders
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        purchase_order_id path string true "Purchase order ID"
+// @Success      200  {object}   models.SuccessResponse{data=models.PurchaseOrder} "Purchase order placed successfully"
+// @Failure      400  {object}   models.ErrorResponse "Purchase order is not a draft"
+// @Failure      404  {object}   models.ErrorResponse "Purchase order not found"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/purchase-orders/{purchase_order_id}/order [post]
+func (h *Handler) OrderPurchaseOrder(c *fiber.Ctx) error {
+	current, err := h.purchaseOrderFromPath(c)
+	if err != nil {
+		return err
+	}
+	order, err := h.Repository.MarkPurchaseOrderOrdered(c.Context(), db.MarkPurchaseOrderOrderedParams{
+		PurchaseOrderID: current.PurchaseOrderID,
+		ShopID:          current.ShopID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return api.BusinessLogicErrorResponse(c, "Only draft purchase orders can be placed")
+		}
+		zap.L().Error("OrderPurchaseOrder: failed to place purchase order", zap.Int64("purchase_order_id", current.PurchaseOrderID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to place purchase order")
+	}
+	return h.purchaseOrderResponse(c, fiber.StatusOK, order, "Purchase order placed successfully")
+}
+
+// ReceivePurchaseOrder adds the stock of a purchase order that arrived
+// @Summary      Receive a purchase order
+// @Description  Add the items of a placed purchase order that arrived to the stock of its location, the default location when it has none, as restock movements that reference the purchase order. Without items everything still outstanding is received. Items of product types that track lots are received into the lot given with them. The unit costs are averaged into the cost prices of the variants.
+// @Tags         purchase-orders
+// @Accept       json
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        purchase_order_id path string true "Purchase order ID"
+// @Param        receipt body models.ReceivePurchaseOrderParams false "Items received"
+// @Success      200  {object}   models.SuccessResponse{data=models.PurchaseOrder} "Purchase order received successfully"
+// @Failure      400  {object}   models.ErrorResponse "Purchase order is not awaiting stock, more was received than ordered or a lot is missing"
+// @Failure      404  {object}   models.ErrorResponse "Purchase order or item not found"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/purchase-orders/{purchase_order_id}/receive [post]
+func (h *Handler) ReceivePurchaseOrder(c *fiber.Ctx) error {
+	order, err := h.purchaseOrderFromPath(c)
+	if err != nil {
+		return err
+	}
+	var param models.ReceivePurchaseOrderParams
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&param); err != nil {
+			return api.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body", nil)
+		}
+	}
+	validator := &models.XValidator{}
+	if errs := validator.Validate(&param); len(errs) > 0 {
+		return api.ErrorResponse(c, fiber.StatusBadRequest, models.FormatValidationErrors(errs), nil)
+	}
+	receipts := make([]services.PurchaseOrderReceipt, len(param.Items))
+	for i, item := range param.Items {
+		receipts[i] = services.PurchaseOrderReceipt{ItemID: item.ItemID, Quantity: item.Quantity, Lot: stockLot(item.StockLotParams)}
+	}
+
+	var results []services.StockChangeResult
+	err = h.Repository.WithTx(c.Context(), func(q *db.Queries) error {
+		var err error
+		order, results, err = services.ReceivePurchaseOrder(c.Context(), q, order, receipts)
+		return err
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrPurchaseOrderNotReceivable):
+			return api.BusinessLogicErrorResponse(c, "Only placed purchase orders that are still awaiting stock can be received")
+		case errors.Is(err, services.ErrPurchaseOrderItemNotFound):
+			return api.NotFoundErrorResponse(c, "Purchase order item")
+		case errors.Is(err, services.ErrPurchaseOrderOverReceipt):
+			return api.BusinessLogicErrorResponse(c, "More of an item cannot be received than was ordered")
+		}
+		zap.L().Warn("ReceivePurchaseOrder: failed to receive purchase order", zap.Int64("purchase_order_id", order.PurchaseOrderID), zap.Error(err))
+		return stockChangeErrorResponse(c, err, "Failed to receive purchase order")
+	}
+	h.queueStockChangeNotifications(c, results)
+
+	return h.purchaseOrderResponse(c, fiber.StatusOK, order, "Purchase order received successfully")
+}
+
+// purchaseOrderLines converts the items of a purchase order request to lines with
+// unit costs in the shop currency
+func (h *Handler) purchaseOrderLines(c *fiber.Ctx, shopID int64, items []models.PurchaseOrderItemParams) ([]services.PurchaseOrderLine, error) {
+	shop, err := h.Repository.GetShop(c.Context(), shopID)
+	if err != nil {
+		zap.L().Error("purchaseOrderLines: failed to fetch shop", zap.Int64("shop_id", shopID), zap.Error(err))
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "Failed to fetch shop")
+	}
+	lines := make([]services.PurchaseOrderLine, len(items))
+	for i, item := range items {
+		unitCost, err := item.UnitCost.In(shop.CurrencyCode)
+		if err != nil {
+			return nil, fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
+		if unitCost.IsNegative() {
+			return nil, fiber.NewError(fiber.StatusBadRequest, "Unit costs cannot be negative")
+		}
+		lines[i] = services.PurchaseOrderLine{VariantID: item.VariantID, Quantity: item.Quantity, UnitCost: unitCost.Numeric()}
+	}
+	return lines, nil
+}
+
+// checkSupplier makes sure a supplier belongs to the shop
+func (h *Handler) checkSupplier(c *fiber.Ctx, shopID, supplierID int64) error {
+	if _, err := h.Repository.GetSupplier(c.Context(), db.GetSupplierParams{SupplierID: supplierID, ShopID: shopID}); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fiber.NewError(fiber.StatusNotFound, "Supplier not found")
+		}
+		zap.L().Error("checkSupplier: failed to fetch supplier", zap.Int64("supplier_id", supplierID), zap.Error(err))
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to fetch supplier")
+	}
+	return nil
+}
+
+// purchaseOrderResponse responds with a purchase order and its items, with costs in
+// the shop currency
+func (h *Handler) purchaseOrderResponse(c *fiber.Ctx, status int, order db.PurchaseOrder, message string) error {
+	shop, err := h.Repository.GetShop(c.Context(), order.ShopID)
+	if err != nil {
+		zap.L().Error("purchaseOrderResponse: failed to fetch shop", zap.Int64("shop_id", order.ShopID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to fetch shop")
+	}
+	items, err := h.Repository.ListPurchaseOrderItems(c.Context(), db.ListPurchaseOrderItemsParams{
+		PurchaseOrderID: order.PurchaseOrderID,
+		ShopID:          order.ShopID,
+	})
+	if err != nil {
+		zap.L().Error("purchaseOrderResponse: failed to fetch purchase order items", zap.Int64("purchase_order_id", order.PurchaseOrderID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to fetch purchase order items")
+	}
+	response, err := models.NewPurchaseOrder(order, items, shop.CurrencyCode)
+	if err != nil {
+		return api.SystemErrorResponse(c, err, "Failed to read purchase order costs")
+	}
+	return api.SuccessResponse(c, status, response, message)
+}
+
+// purchaseOrderFromPath fetches the purchase order named by the shop_id and
+// purchase_order_id path parameters
+func (h *Handler) purchaseOrderFromPath(c *fiber.Ctx) (db.PurchaseOrder, error) {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return db.PurchaseOrder{}, err
+	}
+	orderID, err := api.ParseIDParameter(c, "purchase_order_id", "Purchase order")
+	if err != nil {
+		return db.PurchaseOrder{}, err
+	}
+
+	order, err := h.Repository.GetPurchaseOrder(c.Context(), db.GetPurchaseOrderParams{
+		PurchaseOrderID: orderID,
+		ShopID:          shopID,
+	})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return db.PurchaseOrder{}, fiber.NewError(fiber.StatusNotFound, "Purchase order not found")
+		}
+		zap.L().Error("purchaseOrderFromPath: failed to fetch purchase order", zap.Int64("purchase_order_id", orderID), zap.Error(err))
+		return db.PurchaseOrder{}, fiber.NewError(fiber.StatusInternalServerError, "Failed to fetch purchase order")
+	}
+	return order, nil
+}
@@ -0,0 +1,268 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
+	"github.com/petrejonn/naytife/internal/api"
+	"github.com/petrejonn/naytife/internal/api/models"
+	"github.com/petrejonn/naytife/internal/db"
+	"go.uber.org/zap"
+)
+
+// GetProductReviews lists the reviews of a shop for moderation
+// @Summary List product reviews
+// @Description Get the paginated product reviews of a shop, oldest first, optionally filtered by status
+// @Tags Review
+// @Produce json
+// @Param shop_id path string true "Shop ID"
+// @Param status query string false "Review status" Enums(pending, approved, rejected)
+// @Param limit query int false "Limit" default(20)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} models.SuccessResponse{data=[]models.ProductReview} "Reviews fetched successfully"
+// @Failure 400 {object} models.ErrorResponse "Bad request"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Security OAuth2AccessCode
+// @Router /shops/{shop_id}/reviews [get]
+func (h *Handler) GetProductReviews(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+
+	limit, offset, err := api.ParsePaginationParams(c)
+	if err != nil {
+		return api.BusinessLogicErrorResponse(c, "Invalid pagination parameters")
+	}
+
+	var status db.NullReviewStatus
+	if s := c.Query("status"); s != "" {
+		switch db.ReviewStatus(s) {
+		case db.ReviewStatusPending, db.ReviewStatusApproved, db.ReviewStatusRejected:
+			status = db.NullReviewStatus{ReviewStatus: db.ReviewStatus(s), Valid: true}
+		default:
+			return api.ErrorResponse(c, fiber.StatusBadRequest, "Invalid review status", nil)
+		}
+	}
+
+	reviews, err := h.Repository.ListProductReviews(c.Context(), db.ListProductReviewsParams{
+		ShopID: shopID,
+		Status: status,
+		Limit:  int32(limit),
+		Offset: int32(offset),
+	})
+	if err != nil {
+		zap.L().Error("GetProductReviews: failed to fetch reviews", zap.Int64("shop_id", shopID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to fetch reviews")
+	}
+
+	totalCount, err := h.Repository.CountProductReviews(c.Context(), db.CountProductReviewsParams{
+		ShopID: shopID,
+		Status: status,
+	})
+	if err != nil {
+		zap.L().Error("GetProductReviews: failed to count reviews", zap.Int64("shop_id", shopID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to count reviews")
+	}
+
+	response := make([]models.ProductReview, len(reviews))
+	for i, review := range reviews {
+		response[i] = productReviewResponse(review)
+	}
+
+	page := (offset / limit) + 1
+	return api.PaginatedSuccessResponse(c, fiber.StatusOK, response, totalCount, page, limit, "Reviews fetched successfully")
+}
+
+// GetProductReview fetches a single review
+// @Summary Get a product review
+// @Description Get a product review by ID
+// @Tags Review
+// @Produce json
+// @Param shop_id path string true "Shop ID"
+// @Param review_id path string true "Review ID"
+// @Success 200 {object} models.SuccessResponse{data=models.ProductReview} "Review fetched successfully"
+// @Failure 404 {object} models.ErrorResponse "Review not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Security OAuth2AccessCode
+// @Router /shops/{shop_id}/reviews/{review_id} [get]
+func (h *Handler) GetProductReview(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	reviewID, err := api.ParseIDParameter(c, "review_id", "Review")
+	if err != nil {
+		return err
+	}
+
+	review, err := h.Repository.GetProductReview(c.Context(), db.GetProductReviewParams{
+		ReviewID: reviewID,
+		ShopID:   shopID,
+	})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return api.NotFoundErrorResponse(c, "Review")
+		}
+		zap.L().Error("GetProductReview: failed to fetch review", zap.Int64("shop_id", shopID), zap.Int64("review_id", reviewID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to fetch review")
+	}
+
+	return api.SuccessResponse(c, fiber.StatusOK, productReviewResponse(review), "Review fetched successfully")
+}
+
+// ApproveProductReview publishes a review
+// @Summary Approve a product review
+// @Description Approve a review so it is shown on the storefront and counted in the product rating
+// @Tags Review
+// @Produce json
+// @Param shop_id path string true "Shop ID"
+// @Param review_id path string true "Review ID"
+// @Success 200 {object} models.SuccessResponse{data=models.ProductReview} "Review approved successfully"
+// @Failure 404 {object} models.ErrorResponse "Review not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Security OAuth2AccessCode
+// @Router /shops/{shop_id}/reviews/{review_id}/approve [post]
+func (h *Handler) ApproveProductReview(c *fiber.Ctx) error {
+	return h.moderateProductReview(c, db.ReviewStatusApproved, "Review approved successfully")
+}
+
+// RejectProductReview hides a review
+// @Summary Reject a product review
+// @Description Reject a review so it is hidden from the storefront and left out of the product rating
+// @Tags Review
+// @Produce json
+// @Param shop_id path string true "Shop ID"
+// @Param review_id path string true "Review ID"
+// @Success 200 {object} models.SuccessResponse{data=models.ProductReview} "Review rejected successfully"
+// @Failure 404 {object} models.ErrorResponse "Review not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Security OAuth2AccessCode
+// @Router /shops/{shop_id}/reviews/{review_id}/reject [post]
+func (h *Handler) RejectProductReview(c *fiber.Ctx) error {
+	return h.moderateProductReview(c, db.ReviewStatusRejected, "Review rejected successfully")
+}
+
+// ReplyToProductReview sets the merchant's public reply to a review
+// @Summary Reply to a product review
+// @Description Set or replace the merchant reply shown under a review
+// @Tags Review
+// @Accept json
+// @Produce json
+// @Param shop_id path string true "Shop ID"
+// @Param review_id path string true "Review ID"
+// @Param reply body models.ProductReviewReplyParams true "Reply"
+// @Success 200 {object} models.SuccessResponse{data=models.ProductReview} "Reply saved successfully"
+// @Failure 400 {object} models.ErrorResponse "Bad request"
+// @Failure 404 {object} models.ErrorResponse "Review not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Security OAuth2AccessCode
+// @Router /shops/{shop_id}/reviews/{review_id}/reply [put]
+func (h *Handler) ReplyToProductReview(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	reviewID, err := api.ParseIDParameter(c, "review_id", "Review")
+	if err != nil {
+		return err
+	}
+
+	var param models.ProductReviewReplyParams
+	if err := c.BodyParser(&param); err != nil {
+		zap.L().Warn("ReplyToProductReview: failed to parse request body", zap.Int64("shop_id", shopID), zap.Error(err))
+		return api.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body", nil)
+	}
+	validator := &models.XValidator{}
+	if errs := validator.Validate(&param); len(errs) > 0 {
+		errMsgs := models.FormatValidationErrors(errs)
+		zap.L().Warn("ReplyToProductReview: request validation failed", zap.Int64("shop_id", shopID), zap.String("errors", errMsgs))
+		return &fiber.Error{
+			Code:    fiber.ErrBadRequest.Code,
+			Message: errMsgs,
+		}
+	}
+
+	review, err := h.Repository.ReplyToProductReview(c.Context(), db.ReplyToProductReviewParams{
+		MerchantReply: &param.Reply,
+		ReviewID:      reviewID,
+		ShopID:        shopID,
+	})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return api.NotFoundErrorResponse(c, "Review")
+		}
+		zap.L().Error("ReplyToProductReview: failed to save reply", zap.Int64("shop_id", shopID), zap.Int64("review_id", reviewID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to save reply")
+	}
+
+	return api.SuccessResponse(c, fiber.StatusOK, productReviewResponse(review), "Reply saved successfully")
+}
+
+// moderateProductReview sets the status of a review and recomputes the rating of
+// its product in the same transaction
+func (h *Handler) moderateProductReview(c *fiber.Ctx, status db.ReviewStatus, message string) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	reviewID, err := api.ParseIDParameter(c, "review_id", "Review")
+	if err != nil {
+		return err
+	}
+
+	var review db.ProductReview
+	err = h.Repository.WithTx(c.Context(), func(tx *db.Queries) error {
+		review, err = tx.UpdateProductReviewStatus(c.Context(), db.UpdateProductReviewStatusParams{
+			Status:   status,
+			ReviewID: reviewID,
+			ShopID:   shopID,
+		})
+		if err != nil {
+			return err
+		}
+		_, err = tx.RefreshProductRating(c.Context(), db.RefreshProductRatingParams{
+			ProductID: review.ProductID,
+			ShopID:    shopID,
+		})
+		return err
+	})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return api.NotFoundErrorResponse(c, "Review")
+		}
+		zap.L().Error("moderateProductReview: failed to update review", zap.Int64("shop_id", shopID), zap.Int64("review_id", reviewID), zap.String("status", string(status)), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to update review")
+	}
+
+	return api.SuccessResponse(c, fiber.StatusOK, productReviewResponse(review), message)
+}
+
+func productReviewResponse(review db.ProductReview) models.ProductReview {
+	var repliedAt *time.Time
+	if review.RepliedAt.Valid {
+		repliedAt = &review.RepliedAt.Time
+	}
+	photos := review.Photos
+	if photos == nil {
+		photos = []string{}
+	}
+	return models.ProductReview{
+		ID:                 review.ReviewID,
+		ProductID:          review.ProductID,
+		ProductVariationID: review.ProductVariationID,
+		OrderID:            review.OrderID,
+		Rating:             int(review.Rating),
+		Title:              review.Title,
+		Body:               review.Body,
+		Photos:             photos,
+		CustomerName:       review.CustomerName,
+		CustomerEmail:      review.CustomerEmail,
+		Status:             review.Status,
+		MerchantReply:      review.MerchantReply,
+		RepliedAt:          repliedAt,
+		CreatedAt:          review.CreatedAt.Time,
+		UpdatedAt:          review.UpdatedAt.Time,
+	}
+}
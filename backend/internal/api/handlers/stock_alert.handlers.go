@@ -0,0 +1,253 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
+	"github.com/petrejonn/naytife/internal/api"
+	"github.com/petrejonn/naytife/internal/api/models"
+	"github.com/petrejonn/naytife/internal/db"
+	"go.uber.org/zap"
+)
+
+// GetStockAlerts lists the stock alerts of a shop
+// @Summary      List stock alerts
+// @Description  Get the alerts raised when variants fell to their reorder point or ran out of stock, newest first
+// @Tags         inventory
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        unread query bool false "Only alerts that have not been read"
+// @Param        limit query int false "Limit" default(20)
+// @Param        offset query int false "Offset" default(0)
+// @Success      200  {object}   models.SuccessResponse{data=[]models.StockAlert} "Stock alerts fetched successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/inventory/alerts [get]
+func (h *Handler) GetStockAlerts(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	limit, offset, err := api.ParsePaginationParams(c)
+	if err != nil {
+		return err
+	}
+	unreadOnly := c.QueryBool("unread")
+
+	alerts, err := h.Repository.ListStockAlerts(c.Context(), db.ListStockAlertsParams{
+		ShopID:     shopID,
+		UnreadOnly: unreadOnly,
+		Limit:      int32(limit),
+		Offset:     int32(offset),
+	})
+	if err != nil {
+		zap.L().Error("GetStockAlerts: failed to fetch stock alerts", zap.Int64("shop_id", shopID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to fetch stock alerts")
+	}
+	total, err := h.Repository.CountStockAlerts(c.Context(), db.CountStockAlertsParams{
+		ShopID:     shopID,
+		UnreadOnly: unreadOnly,
+	})
+	if err != nil {
+		zap.L().Error("GetStockAlerts: failed to count stock alerts", zap.Int64("shop_id", shopID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to count stock alerts")
+	}
+
+	response := make([]models.StockAlert, len(alerts))
+	for i, alert := range alerts {
+		response[i] = models.NewStockAlert(alert)
+	}
+	page := (offset / limit) + 1
+	return api.PaginatedSuccessResponse(c, fiber.StatusOK, response, total, page, limit, "Stock alerts fetched successfully")
+}
+
+// MarkStockAlertRead marks a stock alert as read
+// @Summary      Mark a stock alert as read
+// @Description  Mark a stock alert as read so that it no longer shows among the unread alerts
+// @Tags         inventory
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        alert_id path string true "Alert ID"
+// @Success      200  {object}   models.SuccessResponse "Stock alert marked as read"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      404  {object}   models.ErrorResponse "Stock alert not found"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/inventory/alerts/{alert_id}/read [post]
+func (h *Handler) MarkStockAlertRead(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	alertID, err := api.ParseIDParameter(c, "alert_id", "Alert")
+	if err != nil {
+		return err
+	}
+
+	if _, err := h.Repository.MarkStockAlertRead(c.Context(), db.MarkStockAlertReadParams{
+		AlertID: alertID,
+		ShopID:  shopID,
+	}); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return api.NotFoundErrorResponse(c, "Stock alert")
+		}
+		zap.L().Error("MarkStockAlertRead: failed to mark alert as read", zap.Int64("alert_id", alertID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to mark stock alert as read")
+	}
+	return api.SuccessResponse(c, fiber.StatusOK, nil, "Stock alert marked as read")
+}
+
+// MarkAllStockAlertsRead marks every stock alert of a shop as read
+// @Summary      Mark all stock alerts as read
+// @Description  Mark every unread stock alert of a shop as read
+// @Tags         inventory
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Success      200  {object}   models.SuccessResponse "Stock alerts marked as read"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/inventory/alerts/read [post]
+func (h *Handler) MarkAllStockAlertsRead(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	if _, err := h.Repository.MarkAllStockAlertsRead(c.Context(), shopID); err != nil {
+		zap.L().Error("MarkAllStockAlertsRead: failed to mark alerts as read", zap.Int64("shop_id", shopID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to mark stock alerts as read")
+	}
+	return api.SuccessResponse(c, fiber.StatusOK, nil, "Stock alerts marked as read")
+}
+
+// GetReorderReport lists the variants that need reordering
+// @Summary      Get reorder report
+// @Description  Get the variants at or below their reorder point, or that of their product type, lowest stock first, with how many units to reorder. Shops are emailed the same list once a day.
+// @Tags         inventory
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Success      200  {object}   models.SuccessResponse{data=[]models.ReorderItem} "Reorder report fetched successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/inventory/reorder [get]
+func (h *Handler) GetReorderReport(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	items, err := h.Repository.ListReorderVariants(c.Context(), shopID)
+	if err != nil {
+		zap.L().Error("GetReorderReport: failed to fetch variants to reorder", zap.Int64("shop_id", shopID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to fetch reorder report")
+	}
+
+	response := make([]models.ReorderItem, len(items))
+	for i, item := range items {
+		response[i] = models.NewReorderItem(item)
+	}
+	return api.SuccessResponse(c, fiber.StatusOK, response, "Reorder report fetched successfully")
+}
+
+// GetVariantReorderPoint fetches the reorder point of a variant
+// @Summary      Get variant reorder point
+// @Description  Get the stock at which a product variant raises a low stock alert and how much of it to reorder, as set on the variant and as applied after falling back to its product type
+// @Tags         inventory
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        variant_id path string true "Variant ID"
+// @Success      200  {object}   models.SuccessResponse{data=models.VariantReorderPoint} "Reorder point fetched successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      404  {object}   models.ErrorResponse "Variant not found"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/inventory/variants/{variant_id}/reorder-point [get]
+func (h *Handler) GetVariantReorderPoint(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	variantID, err := api.ParseIDParameter(c, "variant_id", "Variant")
+	if err != nil {
+		return err
+	}
+
+	variant, err := h.Repository.GetProductVariation(c.Context(), db.GetProductVariationParams{
+		ProductVariationID: variantID,
+		ShopID:             shopID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return api.NotFoundErrorResponse(c, "Product variant")
+		}
+		return api.SystemErrorResponse(c, err, "Failed to fetch product variant")
+	}
+	return h.variantReorderPointResponse(c, variant, "Reorder point fetched successfully")
+}
+
+// SetVariantReorderPoint sets the reorder point of a variant
+// @Summary      Set variant reorder point
+// @Description  Set the stock at which a product variant raises a low stock alert and how much of it to reorder. Values left empty fall back to those of the product type.
+// @Tags         inventory
+// @Accept       json
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        variant_id path string true "Variant ID"
+// @Param        reorder_point body models.ReorderPointParams true "Reorder point"
+// @Success      200  {object}   models.SuccessResponse{data=models.VariantReorderPoint} "Reorder point updated successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      404  {object}   models.ErrorResponse "Variant not found"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/inventory/variants/{variant_id}/reorder-point [put]
+func (h *Handler) SetVariantReorderPoint(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	variantID, err := api.ParseIDParameter(c, "variant_id", "Variant")
+	if err != nil {
+		return err
+	}
+
+	var param models.ReorderPointParams
+	if err := c.BodyParser(&param); err != nil {
+		return api.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body", nil)
+	}
+	validator := &models.XValidator{}
+	if errs := validator.Validate(&param); len(errs) > 0 {
+		errMsgs := models.FormatValidationErrors(errs)
+		return api.ErrorResponse(c, fiber.StatusBadRequest, errMsgs, nil)
+	}
+
+	variant, err := h.Repository.SetVariantReorderPoint(c.Context(), db.SetVariantReorderPointParams{
+		ProductVariationID: variantID,
+		ShopID:             shopID,
+		ReorderPoint:       param.ReorderPoint,
+		ReorderQuantity:    param.ReorderQuantity,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return api.NotFoundErrorResponse(c, "Product variant")
+		}
+		zap.L().Error("SetVariantReorderPoint: failed to update reorder point", zap.Int64("variant_id", variantID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to update reorder point")
+	}
+	return h.variantReorderPointResponse(c, variant, "Reorder point updated successfully")
+}
+
+// variantReorderPointResponse responds with the reorder point of a variant and the
+// one that applies to it
+func (h *Handler) variantReorderPointResponse(c *fiber.Ctx, variant db.ProductVariation, message string) error {
+	effective, err := h.Repository.GetVariantReorderPoint(c.Context(), db.GetVariantReorderPointParams{
+		ProductVariationID: variant.ProductVariationID,
+		ShopID:             variant.ShopID,
+	})
+	if err != nil {
+		zap.L().Error("variantReorderPointResponse: failed to fetch reorder point", zap.Int64("variant_id", variant.ProductVariationID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to fetch reorder point")
+	}
+	return api.SuccessResponse(c, fiber.StatusOK, models.NewVariantReorderPoint(variant, effective), message)
+}
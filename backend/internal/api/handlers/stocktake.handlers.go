@@ -0,0 +1,503 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
+	"github.com/petrejonn/naytife/internal/api"
+	"github.com/petrejonn/naytife/internal/api/models"
+	"github.com/petrejonn/naytife/internal/db"
+	"github.com/petrejonn/naytife/internal/services"
+	"go.uber.org/zap"
+)
+
+// GetStocktakes lists the stocktakes of a shop
+// @Summary      List stocktakes
+// @Description  Get the stocktakes of a shop, newest first
+// @Tags         inventory
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        status query string false "Status" Enums(open, committed, cancelled)
+// @Param        limit query int false "Limit" default(20)
+// @Param        offset query int false "Offset" default(0)
+// @Success      200  {object}   models.SuccessResponse{data=[]models.Stocktake} "Stocktakes fetched successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/inventory/stocktakes [get]
+func (h *Handler) GetStocktakes(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	limit, offset, err := api.ParsePaginationParams(c)
+	if err != nil {
+		return err
+	}
+	var status db.NullStocktakeStatus
+	if s := c.Query("status"); s != "" {
+		switch db.StocktakeStatus(s) {
+		case db.StocktakeStatusOpen, db.StocktakeStatusCommitted, db.StocktakeStatusCancelled:
+			status = db.NullStocktakeStatus{StocktakeStatus: db.StocktakeStatus(s), Valid: true}
+		default:
+			return api.ErrorResponse(c, fiber.StatusBadRequest, "Invalid stocktake status", nil)
+		}
+	}
+
+	stocktakes, err := h.Repository.ListStocktakes(c.Context(), db.ListStocktakesParams{
+		ShopID: shopID,
+		Status: status,
+		Limit:  int32(limit),
+		Offset: int32(offset),
+	})
+	if err != nil {
+		zap.L().Error("GetStocktakes: failed to fetch stocktakes", zap.Int64("shop_id", shopID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to fetch stocktakes")
+	}
+	total, err := h.Repository.CountStocktakes(c.Context(), db.CountStocktakesParams{
+		ShopID: shopID,
+		Status: status,
+	})
+	if err != nil {
+		zap.L().Error("GetStocktakes: failed to count stocktakes", zap.Int64("shop_id", shopID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to count stocktakes")
+	}
+
+	response := make([]models.Stocktake, len(stocktakes))
+	for i, stocktake := range stocktakes {
+		response[i], _ = models.NewStocktake(stocktake, nil, "")
+	}
+	page := (offset / limit) + 1
+	return api.PaginatedSuccessResponse(c, fiber.StatusOK, response, total, page, limit, "Stocktakes fetched successfully")
+}
+
+// GetStocktake fetches a stocktake
+// @Summary      Get a stocktake
+// @Description  Get a stocktake with how many of its variants have been counted and the variances found so far
+// @Tags         inventory
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        stocktake_id path string true "Stocktake ID"
+// @Success      200  {object}   models.SuccessResponse{data=models.Stocktake} "Stocktake fetched successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      404  {object}   models.ErrorResponse "Stocktake not found"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/inventory/stocktakes/{stocktake_id} [get]
+func (h *Handler) GetStocktake(c *fiber.Ctx) error {
+	stocktake, err := h.stocktakeFromPath(c)
+	if err != nil {
+		return err
+	}
+	return h.stocktakeResponse(c, fiber.StatusOK, stocktake, "Stocktake fetched successfully")
+}
+
+// CreateStocktake opens a stocktake
+// @Summary      Open a stocktake
+// @Description  Start counting the stock at a location, of every variant or of those in a category or of a product type. The stock each variant is expected to have is taken as it is counted.
+// @Tags         inventory
+// @Accept       json
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        stocktake body models.StocktakeParams true "Stocktake"
+// @Success      201  {object}   models.SuccessResponse{data=models.Stocktake} "Stocktake opened successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request or no variants to count"
+// @Failure      404  {object}   models.ErrorResponse "Location not found"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/inventory/stocktakes [post]
+func (h *Handler) CreateStocktake(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	var param models.StocktakeParams
+	if err := c.BodyParser(&param); err != nil {
+		return api.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body", nil)
+	}
+	validator := &models.XValidator{}
+	if errs := validator.Validate(&param); len(errs) > 0 {
+		return api.ErrorResponse(c, fiber.StatusBadRequest, models.FormatValidationErrors(errs), nil)
+	}
+
+	var stocktake db.Stocktake
+	err = h.Repository.WithTx(c.Context(), func(q *db.Queries) error {
+		location, err := services.StockLocation(c.Context(), q, shopID, param.LocationID)
+		if err != nil {
+			return err
+		}
+		stocktake, err = services.OpenStocktake(c.Context(), q, db.CreateStocktakeParams{
+			LocationID:    location.LocationID,
+			CategoryID:    param.CategoryID,
+			ProductTypeID: param.ProductTypeID,
+			Note:          param.Note,
+			CreatedBy:     requestUser(c),
+			ShopID:        shopID,
+		})
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, services.ErrStocktakeEmpty) {
+			return api.BusinessLogicErrorResponse(c, "No variants match the stocktake")
+		}
+		zap.L().Warn("CreateStocktake: failed to open stocktake", zap.Int64("shop_id", shopID), zap.Error(err))
+		return stockChangeErrorResponse(c, err, "Failed to open stocktake")
+	}
+	return h.stocktakeResponse(c, fiber.StatusCreated, stocktake, "Stocktake opened successfully")
+}
+
+// GetStocktakeItems lists the variants a stocktake counts
+// @Summary      List stocktake items
+// @Description  Get the variants a stocktake counts with the stock expected, the quantity counted and the variance
+// @Tags         inventory
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        stocktake_id path string true "Stocktake ID"
+// @Param        status query string false "Only the variants counted, or those still to count" Enums(counted, uncounted)
+// @Param        limit query int false "Limit" default(20)
+// @Param        offset query int false "Offset" default(0)
+// @Success      200  {object}   models.SuccessResponse{data=[]models.StocktakeItem} "Stocktake items fetched successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      404  {object}   models.ErrorResponse "Stocktake not found"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/inventory/stocktakes/{stocktake_id}/items [get]
+func (h *Handler) GetStocktakeItems(c *fiber.Ctx) error {
+	stocktake, err := h.stocktakeFromPath(c)
+	if err != nil {
+		return err
+	}
+	limit, offset, err := api.ParsePaginationParams(c)
+	if err != nil {
+		return err
+	}
+	var counted *bool
+	if s := c.Query("status"); s != "" {
+		if s != "counted" && s != "uncounted" {
+			return api.ErrorResponse(c, fiber.StatusBadRequest, "status must be counted or uncounted", nil)
+		}
+		isCounted := s == "counted"
+		counted = &isCounted
+	}
+	shop, err := h.Repository.GetShop(c.Context(), stocktake.ShopID)
+	if err != nil {
+		return api.NotFoundErrorResponse(c, "Shop")
+	}
+
+	items, err := h.Repository.ListStocktakeItems(c.Context(), db.ListStocktakeItemsParams{
+		StocktakeID: stocktake.StocktakeID,
+		ShopID:      stocktake.ShopID,
+		Counted:     counted,
+		Limit:       int32(limit),
+		Offset:      int32(offset),
+	})
+	if err != nil {
+		zap.L().Error("GetStocktakeItems: failed to fetch stocktake items", zap.Int64("stocktake_id", stocktake.StocktakeID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to fetch stocktake items")
+	}
+	total, err := h.Repository.CountStocktakeItems(c.Context(), db.CountStocktakeItemsParams{
+		StocktakeID: stocktake.StocktakeID,
+		ShopID:      stocktake.ShopID,
+		Counted:     counted,
+	})
+	if err != nil {
+		zap.L().Error("GetStocktakeItems: failed to count stocktake items", zap.Int64("stocktake_id", stocktake.StocktakeID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to count stocktake items")
+	}
+
+	response := make([]models.StocktakeItem, len(items))
+	for i, item := range items {
+		if response[i], err = models.NewStocktakeItem(item, shop.CurrencyCode); err != nil {
+			return api.SystemErrorResponse(c, err, "Failed to read stocktake item costs")
+		}
+	}
+	page := (offset / limit) + 1
+	return api.PaginatedSuccessResponse(c, fiber.StatusOK, response, total, page, limit, "Stocktake items fetched successfully")
+}
+
+// RecordStocktakeCounts enters a batch of counts
+// @Summary      Enter stocktake counts
+// @Description  Record the quantities counted of variants of an open stocktake, named by ID or SKU. A count replaces what was counted of the variant before.
+// @Tags         inventory
+// @Accept       json
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        stocktake_id path string true "Stocktake ID"
+// @Param        counts body models.StocktakeCountsParams true "Counts"
+// @Success      200  {object}   models.SuccessResponse{data=models.Stocktake} "Counts recorded successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request or stocktake not open"
+// @Failure      404  {object}   models.ErrorResponse "Stocktake not found or variant not counted by it"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/inventory/stocktakes/{stocktake_id}/counts [post]
+func (h *Handler) RecordStocktakeCounts(c *fiber.Ctx) error {
+	stocktake, err := h.stocktakeFromPath(c)
+	if err != nil {
+		return err
+	}
+	var param models.StocktakeCountsParams
+	if err := c.BodyParser(&param); err != nil {
+		return api.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body", nil)
+	}
+	validator := &models.XValidator{}
+	if errs := validator.Validate(&param); len(errs) > 0 {
+		return api.ErrorResponse(c, fiber.StatusBadRequest, models.FormatValidationErrors(errs), nil)
+	}
+	counts := make([]services.StocktakeCount, len(param.Items))
+	for i, item := range param.Items {
+		counts[i] = services.StocktakeCount{VariantID: item.VariantID, SKU: item.SKU, Quantity: item.Quantity}
+	}
+	return h.recordStocktakeCounts(c, stocktake, counts, false)
+}
+
+// ScanStocktakeItem counts a scanned variant
+// @Summary      Scan a stocktake item
+// @Description  Add a scanned unit, or several, to what has been counted of the variant whose SKU the barcode encodes
+// @Tags         inventory
+// @Accept       json
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        stocktake_id path string true "Stocktake ID"
+// @Param        scan body models.StocktakeScanParams true "Scan"
+// @Success      200  {object}   models.SuccessResponse{data=models.Stocktake} "Scan recorded successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request or stocktake not open"
+// @Failure      404  {object}   models.ErrorResponse "Stocktake not found or variant not counted by it"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/inventory/stocktakes/{stocktake_id}/scan [post]
+func (h *Handler) ScanStocktakeItem(c *fiber.Ctx) error {
+	stocktake, err := h.stocktakeFromPath(c)
+	if err != nil {
+		return err
+	}
+	var param models.StocktakeScanParams
+	if err := c.BodyParser(&param); err != nil {
+		return api.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body", nil)
+	}
+	validator := &models.XValidator{}
+	if errs := validator.Validate(&param); len(errs) > 0 {
+		return api.ErrorResponse(c, fiber.StatusBadRequest, models.FormatValidationErrors(errs), nil)
+	}
+	if param.Quantity == 0 {
+		param.Quantity = 1
+	}
+	counts := []services.StocktakeCount{{SKU: &param.Code, Quantity: param.Quantity}}
+	return h.recordStocktakeCounts(c, stocktake, counts, true)
+}
+
+// GetStocktakeVariances reports the variances of a stocktake
+// @Summary      Get stocktake variances
+// @Description  Get the counted variants whose count differs from the stock expected, as JSON or as a CSV file
+// @Tags         inventory
+// @Produce      json
+// @Produce      text/csv
+// @Param        shop_id path string true "Shop ID"
+// @Param        stocktake_id path string true "Stocktake ID"
+// @Param        format query string false "Format" Enums(json, csv) default(json)
+// @Success      200  {object}   models.SuccessResponse{data=[]models.StocktakeItem} "Stocktake variances fetched successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      404  {object}   models.ErrorResponse "Stocktake not found"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/inventory/stocktakes/{stocktake_id}/variances [get]
+func (h *Handler) GetStocktakeVariances(c *fiber.Ctx) error {
+	stocktake, err := h.stocktakeFromPath(c)
+	if err != nil {
+		return err
+	}
+	format := c.Query("format", "json")
+	if format != "json" && format != "csv" {
+		return api.ErrorResponse(c, fiber.StatusBadRequest, "format must be json or csv", nil)
+	}
+	shop, err := h.Repository.GetShop(c.Context(), stocktake.ShopID)
+	if err != nil {
+		return api.NotFoundErrorResponse(c, "Shop")
+	}
+
+	rows, err := h.Repository.ListStocktakeVariances(c.Context(), db.ListStocktakeVariancesParams{
+		StocktakeID: stocktake.StocktakeID,
+		ShopID:      stocktake.ShopID,
+	})
+	if err != nil {
+		zap.L().Error("GetStocktakeVariances: failed to fetch variances", zap.Int64("stocktake_id", stocktake.StocktakeID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to fetch stocktake variances")
+	}
+	items := make([]models.StocktakeItem, len(rows))
+	for i, row := range rows {
+		if items[i], err = models.NewStocktakeItem(db.ListStocktakeItemsRow(row), shop.CurrencyCode); err != nil {
+			return api.SystemErrorResponse(c, err, "Failed to read stocktake item costs")
+		}
+	}
+	if format == "json" {
+		return api.SuccessResponse(c, fiber.StatusOK, items, "Stocktake variances fetched successfully")
+	}
+
+	records := [][]string{{"variant_id", "sku", "product_title", "variant_title", "expected_quantity", "counted_quantity", "variance", "unit_cost", "variance_value"}}
+	for _, item := range items {
+		var unitCost, value string
+		if item.UnitCost != nil {
+			unitCost = item.UnitCost.String()
+			value = item.VarianceValue.String()
+		}
+		records = append(records, []string{
+			strconv.FormatInt(item.VariantID, 10),
+			item.SKU,
+			item.ProductTitle,
+			item.VariantTitle,
+			strconv.FormatInt(item.ExpectedQuantity, 10),
+			strconv.FormatInt(*item.CountedQuantity, 10),
+			strconv.FormatInt(*item.Variance, 10),
+			unitCost,
+			value,
+		})
+	}
+
+	c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="stocktake-%d-variances.csv"`, stocktake.StocktakeID))
+	writer := csv.NewWriter(c.Response().BodyWriter())
+	if err := writer.WriteAll(records); err != nil {
+		zap.L().Error("GetStocktakeVariances: failed to write CSV", zap.Int64("stocktake_id", stocktake.StocktakeID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to export stocktake variances")
+	}
+	return nil
+}
+
+// CommitStocktake commits a stocktake
+// @Summary      Commit a stocktake
+// @Description  Close an open stocktake and adjust the stock at its location by every variance counted, recording an adjustment stock movement for each. Variants not counted keep their stock.
+// @Tags         inventory
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        stocktake_id path string true "Stocktake ID"
+// @Success      200  {object}   models.SuccessResponse{data=models.Stocktake} "Stocktake committed successfully"
+// @Failure      400  {object}   models.ErrorResponse "Stocktake not open"
+// @Failure      404  {object}   models.ErrorResponse "Stocktake not found"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/inventory/stocktakes/{stocktake_id}/commit [post]
+func (h *Handler) CommitStocktake(c *fiber.Ctx) error {
+	stocktake, err := h.stocktakeFromPath(c)
+	if err != nil {
+		return err
+	}
+
+	var results []services.StockChangeResult
+	err = h.Repository.WithTx(c.Context(), func(q *db.Queries) error {
+		var err error
+		stocktake, results, err = services.CommitStocktake(c.Context(), q, stocktake)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, services.ErrStocktakeNotOpen) {
+			return api.BusinessLogicErrorResponse(c, "Only open stocktakes can be committed")
+		}
+		zap.L().Warn("CommitStocktake: failed to commit stocktake", zap.Int64("stocktake_id", stocktake.StocktakeID), zap.Error(err))
+		return stockChangeErrorResponse(c, err, "Failed to commit stocktake")
+	}
+	h.queueStockChangeNotifications(c, results)
+
+	return h.stocktakeResponse(c, fiber.StatusOK, stocktake, "Stocktake committed successfully")
+}
+
+// CancelStocktake cancels a stocktake
+// @Summary      Cancel a stocktake
+// @Description  Close an open stocktake without changing any stock
+// @Tags         inventory
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        stocktake_id path string true "Stocktake ID"
+// @Success      200  {object}   models.SuccessResponse{data=models.Stocktake} "Stocktake cancelled successfully"
+// @Failure      400  {object}   models.ErrorResponse "Stocktake not open"
+// @Failure      404  {object}   models.ErrorResponse "Stocktake not found"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/inventory/stocktakes/{stocktake_id}/cancel [post]
+func (h *Handler) CancelStocktake(c *fiber.Ctx) error {
+	current, err := h.stocktakeFromPath(c)
+	if err != nil {
+		return err
+	}
+	stocktake, err := h.Repository.CancelStocktake(c.Context(), db.CancelStocktakeParams{
+		StocktakeID: current.StocktakeID,
+		ShopID:      current.ShopID,
+	})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return api.BusinessLogicErrorResponse(c, "Only open stocktakes can be cancelled")
+		}
+		zap.L().Error("CancelStocktake: failed to cancel stocktake", zap.Int64("stocktake_id", current.StocktakeID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to cancel stocktake")
+	}
+	return h.stocktakeResponse(c, fiber.StatusOK, stocktake, "Stocktake cancelled successfully")
+}
+
+// recordStocktakeCounts records counts against a stocktake and responds with it
+func (h *Handler) recordStocktakeCounts(c *fiber.Ctx, stocktake db.Stocktake, counts []services.StocktakeCount, add bool) error {
+	err := h.Repository.WithTx(c.Context(), func(q *db.Queries) error {
+		_, err := services.RecordStocktakeCounts(c.Context(), q, stocktake, counts, add)
+		return err
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrStocktakeNotOpen):
+			return api.BusinessLogicErrorResponse(c, "Counts can only be entered while the stocktake is open")
+		case errors.Is(err, services.ErrStocktakeItemNotFound):
+			return api.ErrorResponse(c, fiber.StatusNotFound, "The stocktake does not count this variant", nil)
+		}
+		zap.L().Error("recordStocktakeCounts: failed to record counts", zap.Int64("stocktake_id", stocktake.StocktakeID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to record stocktake counts")
+	}
+	return h.stocktakeResponse(c, fiber.StatusOK, stocktake, "Counts recorded successfully")
+}
+
+// stocktakeResponse responds with a stocktake and its summary, with values in the
+// shop currency
+func (h *Handler) stocktakeResponse(c *fiber.Ctx, status int, stocktake db.Stocktake, message string) error {
+	shop, err := h.Repository.GetShop(c.Context(), stocktake.ShopID)
+	if err != nil {
+		zap.L().Error("stocktakeResponse: failed to fetch shop", zap.Int64("shop_id", stocktake.ShopID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to fetch shop")
+	}
+	summary, err := h.Repository.GetStocktakeSummary(c.Context(), db.GetStocktakeSummaryParams{
+		StocktakeID: stocktake.StocktakeID,
+		ShopID:      stocktake.ShopID,
+	})
+	if err != nil {
+		zap.L().Error("stocktakeResponse: failed to summarise stocktake", zap.Int64("stocktake_id", stocktake.StocktakeID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to summarise stocktake")
+	}
+	response, err := models.NewStocktake(stocktake, &summary, shop.CurrencyCode)
+	if err != nil {
+		return api.SystemErrorResponse(c, err, "Failed to read stocktake variance value")
+	}
+	return api.SuccessResponse(c, status, response, message)
+}
+
+// stocktakeFromPath fetches the stocktake named by the shop_id and stocktake_id path parameters
+func (h *Handler) stocktakeFromPath(c *fiber.Ctx) (db.Stocktake, error) {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return db.Stocktake{}, err
+	}
+	stocktakeID, err := api.ParseIDParameter(c, "stocktake_id", "Stocktake")
+	if err != nil {
+		return db.Stocktake{}, err
+	}
+
+	stocktake, err := h.Repository.GetStocktake(c.Context(), db.GetStocktakeParams{
+		StocktakeID: stocktakeID,
+		ShopID:      shopID,
+	})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return db.Stocktake{}, fiber.NewError(fiber.StatusNotFound, "Stocktake not found")
+		}
+		zap.L().Error("stocktakeFromPath: failed to fetch stocktake", zap.Int64("stocktake_id", stocktakeID), zap.Error(err))
+		return db.Stocktake{}, fiber.NewError(fiber.StatusInternalServerError, "Failed to fetch stocktake")
+	}
+	return stocktake, nil
+}
@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/petrejonn/naytife/internal/api"
+	"github.com/petrejonn/naytife/internal/api/models"
+	"github.com/petrejonn/naytife/internal/db"
+	"github.com/petrejonn/naytife/internal/services"
+	"go.uber.org/zap"
+)
+
+// GetCustomerStoreCredit fetches the store credit balance of a customer
+// @Summary      Get customer store credit
+// @Description  Get the store credit balance of a customer, in the shop currency
+// @Tags         customer
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        customer_id path string true "Customer ID"
+// @Success      200  {object}   models.SuccessResponse{data=models.StoreCredit} "Store credit fetched successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      404  {object}   models.ErrorResponse "Customer not found"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/customers/{customer_id}/store-credit [get]
+func (h *Handler) GetCustomerStoreCredit(c *fiber.Ctx) error {
+	shopID, customerID, err := h.shopCustomerFromPath(c)
+	if err != nil {
+		return err
+	}
+	shop, err := h.Repository.GetShop(c.Context(), shopID)
+	if err != nil {
+		zap.L().Error("GetCustomerStoreCredit: failed to fetch shop", zap.Int64("shop_id", shopID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to fetch shop")
+	}
+
+	balance, err := services.StoreCreditBalance(c.Context(), h.Repository, shopID, customerID, shop.CurrencyCode)
+	if err != nil {
+		zap.L().Error("GetCustomerStoreCredit: failed to fetch balance", zap.String("customer_id", customerID.String()), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to fetch store credit")
+	}
+
+	return api.SuccessResponse(c, fiber.StatusOK, models.StoreCredit{
+		CustomerID:   customerID,
+		Balance:      balance,
+		CurrencyCode: shop.CurrencyCode,
+	}, "Store credit fetched successfully")
+}
+
+// GetCustomerStoreCreditTransactions lists the store credit ledger of a customer
+// @Summary      List customer store credit transactions
+// @Description  Get the store credit ledger of a customer, newest first
+// @Tags         customer
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        customer_id path string true "Customer ID"
+// @Param        limit query int false "Limit" default(20)
+// @Param        offset query int false "Offset" default(0)
+// @Success      200  {object}   models.SuccessResponse{data=[]models.CreditTransaction} "Store credit transactions fetched successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      404  {object}   models.ErrorResponse "Customer not found"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/customers/{customer_id}/store-credit/transactions [get]
+func (h *Handler) GetCustomerStoreCreditTransactions(c *fiber.Ctx) error {
+	shopID, customerID, err := h.shopCustomerFromPath(c)
+	if err != nil {
+		return err
+	}
+	limit, offset, err := api.ParsePaginationParams(c)
+	if err != nil {
+		return err
+	}
+	shop, err := h.Repository.GetShop(c.Context(), shopID)
+	if err != nil {
+		zap.L().Error("GetCustomerStoreCreditTransactions: failed to fetch shop", zap.Int64("shop_id", shopID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to fetch shop")
+	}
+
+	entries, err := h.Repository.ListStoreCreditTransactions(c.Context(), db.ListStoreCreditTransactionsParams{
+		ShopCustomerID: customerID,
+		ShopID:         shopID,
+		Limit:          int32(limit),
+		Offset:         int32(offset),
+	})
+	if err != nil {
+		zap.L().Error("GetCustomerStoreCreditTransactions: failed to fetch transactions", zap.String("customer_id", customerID.String()), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to fetch store credit transactions")
+	}
+	total, err := h.Repository.CountStoreCreditTransactions(c.Context(), db.CountStoreCreditTransactionsParams{
+		ShopCustomerID: customerID,
+		ShopID:         shopID,
+	})
+	if err != nil {
+		zap.L().Error("GetCustomerStoreCreditTransactions: failed to count transactions", zap.String("customer_id", customerID.String()), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to count store credit transactions")
+	}
+
+	response := make([]models.CreditTransaction, len(entries))
+	for i, entry := range entries {
+		if response[i], err = models.NewStoreCreditTransaction(entry, shop.CurrencyCode); err != nil {
+			return api.SystemErrorResponse(c, err, "Failed to read store credit transactions")
+		}
+	}
+	page := (offset / limit) + 1
+	return api.PaginatedSuccessResponse(c, fiber.StatusOK, response, total, page, limit, "Store credit transactions fetched successfully")
+}
+
+// AdjustCustomerStoreCredit issues or takes back store credit
+// @Summary      Adjust customer store credit
+// @Description  Issue store credit to a customer, e.g. for a return or as goodwill, or take it back with a negative amount. The entry records the staff member who made it.
+// @Tags         customer
+// @Accept       json
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        customer_id path string true "Customer ID"
+// @Param        adjustment body models.StoreCreditAdjustParams true "Store credit adjustment"
+// @Success      201  {object}   models.SuccessResponse{data=models.CreditTransaction} "Store credit adjusted successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      404  {object}   models.ErrorResponse "Customer not found"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/customers/{customer_id}/store-credit/adjustments [post]
+func (h *Handler) AdjustCustomerStoreCredit(c *fiber.Ctx) error {
+	shopID, customerID, err := h.shopCustomerFromPath(c)
+	if err != nil {
+		return err
+	}
+	var param models.StoreCreditAdjustParams
+	if err := c.BodyParser(&param); err != nil {
+		return api.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body", nil)
+	}
+	validator := &models.XValidator{}
+	if errs := validator.Validate(&param); len(errs) > 0 {
+		return api.ErrorResponse(c, fiber.StatusBadRequest, models.FormatValidationErrors(errs), nil)
+	}
+	shop, err := h.Repository.GetShop(c.Context(), shopID)
+	if err != nil {
+		zap.L().Error("AdjustCustomerStoreCredit: failed to fetch shop", zap.Int64("shop_id", shopID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to fetch shop")
+	}
+	amount, err := param.Amount.In(shop.CurrencyCode)
+	if err != nil {
+		return api.ErrorResponse(c, fiber.StatusBadRequest, err.Error(), nil)
+	}
+	if amount.IsZero() {
+		return api.ErrorResponse(c, fiber.StatusBadRequest, "Amount must not be zero", nil)
+	}
+
+	kind := db.CreditTransactionKindIssue
+	if amount.IsNegative() {
+		kind = db.CreditTransactionKindAdjust
+	}
+	var entry db.StoreCreditTransaction
+	err = h.Repository.WithTx(c.Context(), func(q *db.Queries) error {
+		var err error
+		entry, err = services.AdjustStoreCredit(c.Context(), q, shopID, customerID, kind, amount, &param.Reason, param.OrderID, param.Note, requestUser(c))
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, services.ErrInsufficientStoreCredit) {
+			return api.BusinessLogicErrorResponse(c, err.Error())
+		}
+		zap.L().Error("AdjustCustomerStoreCredit: failed to adjust store credit", zap.String("customer_id", customerID.String()), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to adjust store credit")
+	}
+
+	response, err := models.NewStoreCreditTransaction(entry, shop.CurrencyCode)
+	if err != nil {
+		return api.SystemErrorResponse(c, err, "Failed to read store credit transaction")
+	}
+	return api.SuccessResponse(c, fiber.StatusCreated, response, "Store credit adjusted successfully")
+}
@@ -0,0 +1,548 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/petrejonn/naytife/internal/api"
+	"github.com/petrejonn/naytife/internal/api/models"
+	"github.com/petrejonn/naytife/internal/db"
+	dberrors "github.com/petrejonn/naytife/internal/db/errors"
+	"github.com/petrejonn/naytife/internal/services"
+	"go.uber.org/zap"
+)
+
+// GetSubscriptionPlans lists the subscription plans of a variant
+// @Summary      List subscription plans
+// @Description  Get the plans customers can subscribe to a variant on, including inactive ones
+// @Tags         subscriptions
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        variant_id path string true "Variant ID"
+// @Success      200  {object}   models.SuccessResponse{data=[]models.SubscriptionPlan} "Subscription plans fetched successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/variants/{variant_id}/subscription-plans [get]
+func (h *Handler) GetSubscriptionPlans(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	variantID, err := api.ParseIDParameter(c, "variant_id", "Variant")
+	if err != nil {
+		return err
+	}
+
+	plans, err := h.Repository.ListSubscriptionPlans(c.Context(), db.ListSubscriptionPlansParams{
+		ProductVariationID: variantID,
+		ShopID:             shopID,
+	})
+	if err != nil {
+		zap.L().Error("GetSubscriptionPlans: failed to fetch subscription plans", zap.Int64("variant_id", variantID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to fetch subscription plans")
+	}
+
+	response := make([]models.SubscriptionPlan, len(plans))
+	for i, plan := range plans {
+		response[i] = models.NewSubscriptionPlan(plan)
+	}
+	return api.SuccessResponse(c, fiber.StatusOK, response, "Subscription plans fetched successfully")
+}
+
+// CreateSubscriptionPlan adds a subscription plan to a variant
+// @Summary      Create a subscription plan
+// @Description  Add a plan customers can subscribe to a variant on, renewing every interval at the variant price less the plan discount
+// @Tags         subscriptions
+// @Accept       json
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        variant_id path string true "Variant ID"
+// @Param        plan body models.SubscriptionPlanParams true "Subscription plan"
+// @Success      201  {object}   models.SuccessResponse{data=models.SubscriptionPlan} "Subscription plan created successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      404  {object}   models.ErrorResponse "Variant not found"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/variants/{variant_id}/subscription-plans [post]
+func (h *Handler) CreateSubscriptionPlan(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	variantID, err := api.ParseIDParameter(c, "variant_id", "Variant")
+	if err != nil {
+		return err
+	}
+
+	var param models.SubscriptionPlanParams
+	if err := c.BodyParser(&param); err != nil {
+		return api.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body", nil)
+	}
+	validator := &models.XValidator{}
+	if errs := validator.Validate(&param); len(errs) > 0 {
+		errMsgs := models.FormatValidationErrors(errs)
+		return api.ErrorResponse(c, fiber.StatusBadRequest, errMsgs, nil)
+	}
+
+	if _, err := h.Repository.GetProductVariation(c.Context(), db.GetProductVariationParams{
+		ProductVariationID: variantID,
+		ShopID:             shopID,
+	}); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return api.NotFoundErrorResponse(c, "Product variant")
+		}
+		return api.SystemErrorResponse(c, err, "Failed to fetch product variant")
+	}
+
+	arg := db.CreateSubscriptionPlanParams{
+		ProductVariationID: variantID,
+		Name:               param.Name,
+		IntervalUnit:       db.SubscriptionInterval(param.IntervalUnit),
+		IntervalCount:      1,
+		DiscountPercent:    services.FloatToNumeric(0),
+		Active:             true,
+		ShopID:             shopID,
+	}
+	if param.IntervalCount != nil {
+		arg.IntervalCount = *param.IntervalCount
+	}
+	if param.DiscountPercent != nil {
+		arg.DiscountPercent = services.FloatToNumeric(*param.DiscountPercent)
+	}
+	if param.TrialDays != nil {
+		arg.TrialDays = *param.TrialDays
+	}
+	if param.Active != nil {
+		arg.Active = *param.Active
+	}
+
+	plan, err := h.Repository.CreateSubscriptionPlan(c.Context(), arg)
+	if err != nil {
+		return api.SystemErrorResponse(c, err, "Failed to create subscription plan")
+	}
+	return api.SuccessResponse(c, fiber.StatusCreated, models.NewSubscriptionPlan(plan), "Subscription plan created successfully")
+}
+
+// UpdateSubscriptionPlan updates a subscription plan
+// @Summary      Update a subscription plan
+// @Description  Update a subscription plan. Existing subscriptions renew on the new terms from their next renewal; deactivated plans take no new subscriptions.
+// @Tags         subscriptions
+// @Accept       json
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        plan_id path string true "Subscription plan ID"
+// @Param        plan body models.SubscriptionPlanUpdateParams true "Subscription plan"
+// @Success      200  {object}   models.SuccessResponse{data=models.SubscriptionPlan} "Subscription plan updated successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      404  {object}   models.ErrorResponse "Subscription plan not found"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/subscription-plans/{plan_id} [patch]
+func (h *Handler) UpdateSubscriptionPlan(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	planID, err := api.ParseIDParameter(c, "plan_id", "Subscription plan")
+	if err != nil {
+		return err
+	}
+
+	var param models.SubscriptionPlanUpdateParams
+	if err := c.BodyParser(&param); err != nil {
+		return api.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body", nil)
+	}
+	validator := &models.XValidator{}
+	if errs := validator.Validate(&param); len(errs) > 0 {
+		errMsgs := models.FormatValidationErrors(errs)
+		return api.ErrorResponse(c, fiber.StatusBadRequest, errMsgs, nil)
+	}
+
+	arg := db.UpdateSubscriptionPlanParams{
+		Name:          param.Name,
+		IntervalCount: param.IntervalCount,
+		TrialDays:     param.TrialDays,
+		Active:        param.Active,
+		PlanID:        planID,
+		ShopID:        shopID,
+	}
+	if param.IntervalUnit != nil {
+		arg.IntervalUnit = db.NullSubscriptionInterval{SubscriptionInterval: db.SubscriptionInterval(*param.IntervalUnit), Valid: true}
+	}
+	if param.DiscountPercent != nil {
+		arg.DiscountPercent = services.FloatToNumeric(*param.DiscountPercent)
+	}
+
+	plan, err := h.Repository.UpdateSubscriptionPlan(c.Context(), arg)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return api.NotFoundErrorResponse(c, "Subscription plan")
+		}
+		return api.SystemErrorResponse(c, err, "Failed to update subscription plan")
+	}
+	return api.SuccessResponse(c, fiber.StatusOK, models.NewSubscriptionPlan(plan), "Subscription plan updated successfully")
+}
+
+// DeleteSubscriptionPlan deletes a subscription plan
+// @Summary      Delete a subscription plan
+// @Description  Delete a subscription plan nobody has subscribed to. Plans with subscriptions can be deactivated instead.
+// @Tags         subscriptions
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        plan_id path string true "Subscription plan ID"
+// @Success      200  {object}   models.SuccessResponse "Subscription plan deleted successfully"
+// @Failure      400  {object}   models.ErrorResponse "Plan has subscriptions"
+// @Failure      404  {object}   models.ErrorResponse "Subscription plan not found"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/subscription-plans/{plan_id} [delete]
+func (h *Handler) DeleteSubscriptionPlan(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	planID, err := api.ParseIDParameter(c, "plan_id", "Subscription plan")
+	if err != nil {
+		return err
+	}
+
+	if _, err := h.Repository.DeleteSubscriptionPlan(c.Context(), db.DeleteSubscriptionPlanParams{
+		PlanID: planID,
+		ShopID: shopID,
+	}); err != nil {
+		var pgErr *pgconn.PgError
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return api.NotFoundErrorResponse(c, "Subscription plan")
+		case errors.As(err, &pgErr) && pgErr.Code == dberrors.ForeignKeyViolation:
+			return api.BusinessLogicErrorResponse(c, "The subscription plan has subscriptions; deactivate it instead")
+		}
+		return api.SystemErrorResponse(c, err, "Failed to delete subscription plan")
+	}
+	return api.SuccessResponse(c, fiber.StatusOK, nil, "Subscription plan deleted successfully")
+}
+
+// GetSubscriptions lists the subscriptions of a shop
+// @Summary      List subscriptions
+// @Description  Get the paginated subscriptions of a shop, newest first, optionally filtered by status
+// @Tags         subscriptions
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        status query string false "Subscription status" Enums(trialing, active, paused, past_due, cancelled)
+// @Param        limit query int false "Limit" default(20)
+// @Param        offset query int false "Offset" default(0)
+// @Success      200  {object}   models.SuccessResponse{data=[]models.Subscription} "Subscriptions fetched successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/subscriptions [get]
+func (h *Handler) GetSubscriptions(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	limit, offset, err := api.ParsePaginationParams(c)
+	if err != nil {
+		return err
+	}
+
+	var status db.NullSubscriptionStatus
+	if s := c.Query("status"); s != "" {
+		switch db.SubscriptionStatus(s) {
+		case db.SubscriptionStatusTrialing, db.SubscriptionStatusActive, db.SubscriptionStatusPaused,
+			db.SubscriptionStatusPastDue, db.SubscriptionStatusCancelled:
+			status = db.NullSubscriptionStatus{SubscriptionStatus: db.SubscriptionStatus(s), Valid: true}
+		default:
+			return api.ErrorResponse(c, fiber.StatusBadRequest, "Invalid subscription status", nil)
+		}
+	}
+
+	subscriptions, err := h.Repository.ListSubscriptions(c.Context(), db.ListSubscriptionsParams{
+		ShopID: shopID,
+		Status: status,
+		Limit:  int32(limit),
+		Offset: int32(offset),
+	})
+	if err != nil {
+		zap.L().Error("GetSubscriptions: failed to fetch subscriptions", zap.Int64("shop_id", shopID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to fetch subscriptions")
+	}
+	total, err := h.Repository.CountSubscriptions(c.Context(), db.CountSubscriptionsParams{
+		ShopID: shopID,
+		Status: status,
+	})
+	if err != nil {
+		return api.SystemErrorResponse(c, err, "Failed to count subscriptions")
+	}
+
+	response := make([]models.Subscription, len(subscriptions))
+	for i, subscription := range subscriptions {
+		response[i] = models.NewSubscription(db.GetSubscriptionRow(subscription))
+	}
+	page := (offset / limit) + 1
+	return api.PaginatedSuccessResponse(c, fiber.StatusOK, response, total, page, limit, "Subscriptions fetched successfully")
+}
+
+// GetSubscription fetches a subscription
+// @Summary      Get a subscription
+// @Description  Get a subscription with its plan and renewal schedule
+// @Tags         subscriptions
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        subscription_id path string true "Subscription ID"
+// @Success      200  {object}   models.SuccessResponse{data=models.Subscription} "Subscription fetched successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      404  {object}   models.ErrorResponse "Subscription not found"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/subscriptions/{subscription_id} [get]
+func (h *Handler) GetSubscription(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	subscriptionID, err := api.ParseIDParameter(c, "subscription_id", "Subscription")
+	if err != nil {
+		return err
+	}
+	return h.subscriptionResponse(c, shopID, subscriptionID, "Subscription fetched successfully")
+}
+
+// GetSubscriptionRenewals lists the renewals of a subscription
+// @Summary      List subscription renewals
+// @Description  Get the paginated renewal attempts of a subscription, newest first, with the orders they placed and why failed ones failed
+// @Tags         subscriptions
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        subscription_id path string true "Subscription ID"
+// @Param        limit query int false "Limit" default(20)
+// @Param        offset query int false "Offset" default(0)
+// @Success      200  {object}   models.SuccessResponse{data=[]models.SubscriptionRenewal} "Subscription renewals fetched successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/subscriptions/{subscription_id}/renewals [get]
+func (h *Handler) GetSubscriptionRenewals(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	subscriptionID, err := api.ParseIDParameter(c, "subscription_id", "Subscription")
+	if err != nil {
+		return err
+	}
+	limit, offset, err := api.ParsePaginationParams(c)
+	if err != nil {
+		return err
+	}
+
+	renewals, err := h.Repository.ListSubscriptionRenewals(c.Context(), db.ListSubscriptionRenewalsParams{
+		SubscriptionID: subscriptionID,
+		ShopID:         shopID,
+		Limit:          int32(limit),
+		Offset:         int32(offset),
+	})
+	if err != nil {
+		return api.SystemErrorResponse(c, err, "Failed to fetch subscription renewals")
+	}
+	total, err := h.Repository.CountSubscriptionRenewals(c.Context(), db.CountSubscriptionRenewalsParams{
+		SubscriptionID: subscriptionID,
+		ShopID:         shopID,
+	})
+	if err != nil {
+		return api.SystemErrorResponse(c, err, "Failed to count subscription renewals")
+	}
+
+	response := make([]models.SubscriptionRenewal, len(renewals))
+	for i, renewal := range renewals {
+		response[i] = models.NewSubscriptionRenewal(renewal)
+	}
+	page := (offset / limit) + 1
+	return api.PaginatedSuccessResponse(c, fiber.StatusOK, response, total, page, limit, "Subscription renewals fetched successfully")
+}
+
+// PauseSubscription pauses a subscription
+// @Summary      Pause a subscription
+// @Description  Stop a trialing or active subscription from renewing, until the given date or until it is resumed
+// @Tags         subscriptions
+// @Accept       json
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        subscription_id path string true "Subscription ID"
+// @Param        pause body models.PauseSubscriptionParams false "Pause"
+// @Success      200  {object}   models.SuccessResponse{data=models.Subscription} "Subscription paused successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      404  {object}   models.ErrorResponse "Subscription not found"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/subscriptions/{subscription_id}/pause [post]
+func (h *Handler) PauseSubscription(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	subscriptionID, err := api.ParseIDParameter(c, "subscription_id", "Subscription")
+	if err != nil {
+		return err
+	}
+
+	var param models.PauseSubscriptionParams
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&param); err != nil {
+			return api.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body", nil)
+		}
+	}
+	if err := h.checkSubscription(c, shopID, subscriptionID); err != nil {
+		return subscriptionErrorResponse(c, err)
+	}
+
+	if _, err := services.PauseSubscription(c.Context(), h.Repository, shopID, subscriptionID, param.ResumeOn); err != nil {
+		return subscriptionErrorResponse(c, err)
+	}
+	return h.subscriptionResponse(c, shopID, subscriptionID, "Subscription paused successfully")
+}
+
+// ResumeSubscription resumes a paused subscription
+// @Summary      Resume a subscription
+// @Description  Resume a paused subscription. A renewal that fell due while it was paused is placed straight away.
+// @Tags         subscriptions
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        subscription_id path string true "Subscription ID"
+// @Success      200  {object}   models.SuccessResponse{data=models.Subscription} "Subscription resumed successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      404  {object}   models.ErrorResponse "Subscription not found"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/subscriptions/{subscription_id}/resume [post]
+func (h *Handler) ResumeSubscription(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	subscriptionID, err := api.ParseIDParameter(c, "subscription_id", "Subscription")
+	if err != nil {
+		return err
+	}
+	if err := h.checkSubscription(c, shopID, subscriptionID); err != nil {
+		return subscriptionErrorResponse(c, err)
+	}
+
+	if _, err := services.ResumeSubscription(c.Context(), h.Repository, shopID, subscriptionID); err != nil {
+		return subscriptionErrorResponse(c, err)
+	}
+	return h.subscriptionResponse(c, shopID, subscriptionID, "Subscription resumed successfully")
+}
+
+// SkipSubscriptionRenewal skips the next renewal of a subscription
+// @Summary      Skip a subscription renewal
+// @Description  Move the next renewal of a trialing or active subscription on by one interval of its plan
+// @Tags         subscriptions
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        subscription_id path string true "Subscription ID"
+// @Success      200  {object}   models.SuccessResponse{data=models.Subscription} "Subscription renewal skipped successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      404  {object}   models.ErrorResponse "Subscription not found"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/subscriptions/{subscription_id}/skip [post]
+func (h *Handler) SkipSubscriptionRenewal(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	subscriptionID, err := api.ParseIDParameter(c, "subscription_id", "Subscription")
+	if err != nil {
+		return err
+	}
+
+	if _, err := services.SkipSubscriptionRenewal(c.Context(), h.Repository, shopID, subscriptionID); err != nil {
+		return subscriptionErrorResponse(c, err)
+	}
+	return h.subscriptionResponse(c, shopID, subscriptionID, "Subscription renewal skipped successfully")
+}
+
+// CancelSubscription cancels a subscription
+// @Summary      Cancel a subscription
+// @Description  Cancel a subscription so that it never renews again. A renewal order waiting for a failed payment to be retried is cancelled with it.
+// @Tags         subscriptions
+// @Accept       json
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        subscription_id path string true "Subscription ID"
+// @Param        cancel body models.CancelSubscriptionParams false "Cancellation"
+// @Success      200  {object}   models.SuccessResponse{data=models.Subscription} "Subscription cancelled successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      404  {object}   models.ErrorResponse "Subscription not found"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/subscriptions/{subscription_id}/cancel [post]
+func (h *Handler) CancelSubscription(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	subscriptionID, err := api.ParseIDParameter(c, "subscription_id", "Subscription")
+	if err != nil {
+		return err
+	}
+
+	var param models.CancelSubscriptionParams
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&param); err != nil {
+			return api.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body", nil)
+		}
+	}
+	validator := &models.XValidator{}
+	if errs := validator.Validate(&param); len(errs) > 0 {
+		errMsgs := models.FormatValidationErrors(errs)
+		return api.ErrorResponse(c, fiber.StatusBadRequest, errMsgs, nil)
+	}
+
+	if _, err := services.CancelSubscription(c.Context(), h.Repository, shopID, subscriptionID, param.Reason); err != nil {
+		return subscriptionErrorResponse(c, err)
+	}
+	return h.subscriptionResponse(c, shopID, subscriptionID, "Subscription cancelled successfully")
+}
+
+// checkSubscription returns pgx.ErrNoRows if the shop has no such subscription
+func (h *Handler) checkSubscription(c *fiber.Ctx, shopID, subscriptionID int64) error {
+	_, err := h.Repository.GetSubscription(c.Context(), db.GetSubscriptionParams{
+		SubscriptionID: subscriptionID,
+		ShopID:         shopID,
+	})
+	return err
+}
+
+// subscriptionResponse responds with a subscription as it now is
+func (h *Handler) subscriptionResponse(c *fiber.Ctx, shopID, subscriptionID int64, message string) error {
+	subscription, err := h.Repository.GetSubscription(c.Context(), db.GetSubscriptionParams{
+		SubscriptionID: subscriptionID,
+		ShopID:         shopID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return api.NotFoundErrorResponse(c, "Subscription")
+		}
+		return api.SystemErrorResponse(c, err, "Failed to fetch subscription")
+	}
+	return api.SuccessResponse(c, fiber.StatusOK, models.NewSubscription(subscription), message)
+}
+
+// subscriptionErrorResponse responds to an error changing a subscription
+func subscriptionErrorResponse(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return api.NotFoundErrorResponse(c, "Subscription")
+	case errors.Is(err, services.ErrSubscriptionNotPausable),
+		errors.Is(err, services.ErrSubscriptionNotPaused),
+		errors.Is(err, services.ErrSubscriptionNotSkippable),
+		errors.Is(err, services.ErrSubscriptionCancelled):
+		return api.BusinessLogicErrorResponse(c, err.Error())
+	}
+	return api.SystemErrorResponse(c, err, "Failed to update subscription")
+}
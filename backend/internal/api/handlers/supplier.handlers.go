@@ -0,0 +1,237 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/petrejonn/naytife/internal/api"
+	"github.com/petrejonn/naytife/internal/api/models"
+	"github.com/petrejonn/naytife/internal/db"
+	dberrors "github.com/petrejonn/naytife/internal/db/errors"
+	"go.uber.org/zap"
+)
+
+// GetSuppliers lists the suppliers of a shop
+// @Summary      List suppliers
+// @Description  Get the businesses a shop buys stock from, by name
+// @Tags         suppliers
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        limit query int false "Limit" default(20)
+// @Param        offset query int false "Offset" default(0)
+// @Success      200  {object}   models.SuccessResponse{data=[]models.Supplier} "Suppliers fetched successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/suppliers [get]
+func (h *Handler) GetSuppliers(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	limit, offset, err := api.ParsePaginationParams(c)
+	if err != nil {
+		return err
+	}
+
+	suppliers, err := h.Repository.ListSuppliers(c.Context(), db.ListSuppliersParams{
+		ShopID: shopID,
+		Limit:  int32(limit),
+		Offset: int32(offset),
+	})
+	if err != nil {
+		zap.L().Error("GetSuppliers: failed to fetch suppliers", zap.Int64("shop_id", shopID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to fetch suppliers")
+	}
+	total, err := h.Repository.CountSuppliers(c.Context(), shopID)
+	if err != nil {
+		zap.L().Error("GetSuppliers: failed to count suppliers", zap.Int64("shop_id", shopID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to count suppliers")
+	}
+
+	response := make([]models.Supplier, len(suppliers))
+	for i, supplier := range suppliers {
+		response[i] = models.NewSupplier(supplier)
+	}
+	page := (offset / limit) + 1
+	return api.PaginatedSuccessResponse(c, fiber.StatusOK, response, total, page, limit, "Suppliers fetched successfully")
+}
+
+// GetSupplier fetches a supplier
+// @Summary      Get a supplier
+// @Description  Get a business the shop buys stock from
+// @Tags         suppliers
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        supplier_id path string true "Supplier ID"
+// @Success      200  {object}   models.SuccessResponse{data=models.Supplier} "Supplier fetched successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      404  {object}   models.ErrorResponse "Supplier not found"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/suppliers/{supplier_id} [get]
+func (h *Handler) GetSupplier(c *fiber.Ctx) error {
+	supplier, err := h.supplierFromPath(c)
+	if err != nil {
+		return err
+	}
+	return api.SuccessResponse(c, fiber.StatusOK, models.NewSupplier(supplier), "Supplier fetched successfully")
+}
+
+// CreateSupplier adds a supplier
+// @Summary      Create a supplier
+// @Description  Add a business the shop buys stock from
+// @Tags         suppliers
+// @Accept       json
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        supplier body models.SupplierParams true "Supplier"
+// @Success      201  {object}   models.SuccessResponse{data=models.Supplier} "Supplier created successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      409  {object}   models.ErrorResponse "Supplier name already exists"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/suppliers [post]
+func (h *Handler) CreateSupplier(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	var param models.SupplierParams
+	if err := c.BodyParser(&param); err != nil {
+		return api.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body", nil)
+	}
+	validator := &models.XValidator{}
+	if errs := validator.Validate(&param); len(errs) > 0 {
+		return api.ErrorResponse(c, fiber.StatusBadRequest, models.FormatValidationErrors(errs), nil)
+	}
+
+	supplier, err := h.Repository.CreateSupplier(c.Context(), db.CreateSupplierParams{
+		Name:        param.Name,
+		ContactName: param.ContactName,
+		Email:       param.Email,
+		Phone:       param.Phone,
+		Address:     param.Address,
+		Notes:       param.Notes,
+		ShopID:      shopID,
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == dberrors.UniqueViolation {
+			return api.ErrorResponse(c, fiber.StatusConflict, "A supplier with this name already exists", nil)
+		}
+		zap.L().Error("CreateSupplier: failed to create supplier", zap.Int64("shop_id", shopID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to create supplier")
+	}
+	return api.SuccessResponse(c, fiber.StatusCreated, models.NewSupplier(supplier), "Supplier created successfully")
+}
+
+// UpdateSupplier updates a supplier
+// @Summary      Update a supplier
+// @Description  Change the name and contact details of a supplier
+// @Tags         suppliers
+// @Accept       json
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        supplier_id path string true "Supplier ID"
+// @Param        supplier body models.SupplierParams true "Supplier"
+// @Success      200  {object}   models.SuccessResponse{data=models.Supplier} "Supplier updated successfully"
+// @Failure      400  {object}   models.ErrorResponse "Bad request"
+// @Failure      404  {object}   models.ErrorResponse "Supplier not found"
+// @Failure      409  {object}   models.ErrorResponse "Supplier name already exists"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/suppliers/{supplier_id} [put]
+func (h *Handler) UpdateSupplier(c *fiber.Ctx) error {
+	current, err := h.supplierFromPath(c)
+	if err != nil {
+		return err
+	}
+	var param models.SupplierParams
+	if err := c.BodyParser(&param); err != nil {
+		return api.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body", nil)
+	}
+	validator := &models.XValidator{}
+	if errs := validator.Validate(&param); len(errs) > 0 {
+		return api.ErrorResponse(c, fiber.StatusBadRequest, models.FormatValidationErrors(errs), nil)
+	}
+
+	supplier, err := h.Repository.UpdateSupplier(c.Context(), db.UpdateSupplierParams{
+		SupplierID:  current.SupplierID,
+		ShopID:      current.ShopID,
+		Name:        param.Name,
+		ContactName: param.ContactName,
+		Email:       param.Email,
+		Phone:       param.Phone,
+		Address:     param.Address,
+		Notes:       param.Notes,
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == dberrors.UniqueViolation {
+			return api.ErrorResponse(c, fiber.StatusConflict, "A supplier with this name already exists", nil)
+		}
+		zap.L().Error("UpdateSupplier: failed to update supplier", zap.Int64("supplier_id", current.SupplierID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to update supplier")
+	}
+	return api.SuccessResponse(c, fiber.StatusOK, models.NewSupplier(supplier), "Supplier updated successfully")
+}
+
+// DeleteSupplier removes a supplier
+// @Summary      Delete a supplier
+// @Description  Remove a supplier that has no purchase orders
+// @Tags         suppliers
+// @Produce      json
+// @Param        shop_id path string true "Shop ID"
+// @Param        supplier_id path string true "Supplier ID"
+// @Success      200  {object}   models.SuccessResponse "Supplier deleted successfully"
+// @Failure      400  {object}   models.ErrorResponse "Supplier has purchase orders"
+// @Failure      404  {object}   models.ErrorResponse "Supplier not found"
+// @Failure      500  {object}   models.ErrorResponse "Internal server error"
+// @Security     OAuth2AccessCode
+// @Router       /shops/{shop_id}/suppliers/{supplier_id} [delete]
+func (h *Handler) DeleteSupplier(c *fiber.Ctx) error {
+	supplier, err := h.supplierFromPath(c)
+	if err != nil {
+		return err
+	}
+	if _, err := h.Repository.DeleteSupplier(c.Context(), db.DeleteSupplierParams{
+		SupplierID: supplier.SupplierID,
+		ShopID:     supplier.ShopID,
+	}); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == dberrors.ForeignKeyViolation {
+			return api.BusinessLogicErrorResponse(c, "The supplier has purchase orders and cannot be deleted")
+		}
+		zap.L().Error("DeleteSupplier: failed to delete supplier", zap.Int64("supplier_id", supplier.SupplierID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to delete supplier")
+	}
+	return api.SuccessResponse(c, fiber.StatusOK, nil, "Supplier deleted successfully")
+}
+
+// supplierFromPath fetches the supplier named by the shop_id and supplier_id path parameters
+func (h *Handler) supplierFromPath(c *fiber.Ctx) (db.Supplier, error) {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return db.Supplier{}, err
+	}
+	supplierID, err := api.ParseIDParameter(c, "supplier_id", "Supplier")
+	if err != nil {
+		return db.Supplier{}, err
+	}
+
+	supplier, err := h.Repository.GetSupplier(c.Context(), db.GetSupplierParams{
+		SupplierID: supplierID,
+		ShopID:     shopID,
+	})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return db.Supplier{}, fiber.NewError(fiber.StatusNotFound, "Supplier not found")
+		}
+		zap.L().Error("supplierFromPath: failed to fetch supplier", zap.Int64("supplier_id", supplierID), zap.Error(err))
+		return db.Supplier{}, fiber.NewError(fiber.StatusInternalServerError, "Failed to fetch supplier")
+	}
+	return supplier, nil
+}
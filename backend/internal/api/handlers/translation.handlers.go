@@ -0,0 +1,470 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/petrejonn/naytife/internal/api"
+	"github.com/petrejonn/naytife/internal/api/models"
+	"github.com/petrejonn/naytife/internal/db"
+	"github.com/petrejonn/naytife/internal/services"
+	"go.uber.org/zap"
+)
+
+// translatableFields lists the fields that can be translated for each resource type.
+// GetMissingTranslations in queries/translation.sql must be kept in sync with it.
+var translatableFields = map[db.TranslatableResourceType][]string{
+	db.TranslatableResourceTypeProduct:          {"title", "description"},
+	db.TranslatableResourceTypeProductVariation: {"description", "seo_title", "seo_description"},
+	db.TranslatableResourceTypeCategory:         {"title", "description"},
+	db.TranslatableResourceTypeShop:             {"about", "seo_title", "seo_description"},
+	db.TranslatableResourceTypeCollection:       {"title", "description"},
+}
+
+// GetShopLocales lists the locales enabled for a shop
+// @Summary List shop locales
+// @Description Get the default locale and all enabled locales of a shop. Shops without configured locales use "en".
+// @Tags Translation
+// @Produce json
+// @Param shop_id path string true "Shop ID"
+// @Success 200 {object} models.SuccessResponse{data=models.ShopLocales} "Shop locales fetched successfully"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Security OAuth2AccessCode
+// @Router /shops/{shop_id}/locales [get]
+func (h *Handler) GetShopLocales(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+
+	rows, err := h.Repository.GetShopLocales(c.Context(), shopID)
+	if err != nil {
+		zap.L().Error("GetShopLocales: failed to fetch locales", zap.Int64("shop_id", shopID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to fetch shop locales")
+	}
+	defaultLocale, locales := services.ShopLocales(rows)
+
+	return api.SuccessResponse(c, fiber.StatusOK, models.ShopLocales{
+		DefaultLocale: defaultLocale,
+		Locales:       locales,
+	}, "Shop locales fetched successfully")
+}
+
+// UpdateShopLocales replaces the locales enabled for a shop
+// @Summary Update shop locales
+// @Description Replace the enabled locales of a shop and set the default locale. Translations of removed locales are kept.
+// @Tags Translation
+// @Accept json
+// @Produce json
+// @Param shop_id path string true "Shop ID"
+// @Param locales body models.ShopLocalesUpdateParams true "Shop locales"
+// @Success 200 {object} models.SuccessResponse{data=models.ShopLocales} "Shop locales updated successfully"
+// @Failure 400 {object} models.ErrorResponse "Bad request"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Security OAuth2AccessCode
+// @Router /shops/{shop_id}/locales [put]
+func (h *Handler) UpdateShopLocales(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+
+	var param models.ShopLocalesUpdateParams
+	if err := c.BodyParser(&param); err != nil {
+		zap.L().Warn("UpdateShopLocales: failed to parse request body", zap.Int64("shop_id", shopID), zap.Error(err))
+		return api.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body", nil)
+	}
+	validator := &models.XValidator{}
+	if errs := validator.Validate(&param); len(errs) > 0 {
+		return &fiber.Error{
+			Code:    fiber.ErrBadRequest.Code,
+			Message: models.FormatValidationErrors(errs),
+		}
+	}
+
+	defaultLocale, err := services.NormalizeLocale(param.DefaultLocale)
+	if err != nil {
+		return api.ErrorResponse(c, fiber.StatusBadRequest, err.Error(), nil)
+	}
+	locales := []string{defaultLocale}
+	seen := map[string]bool{defaultLocale: true}
+	for _, raw := range param.Locales {
+		locale, err := services.NormalizeLocale(raw)
+		if err != nil {
+			return api.ErrorResponse(c, fiber.StatusBadRequest, err.Error(), nil)
+		}
+		if !seen[locale] {
+			seen[locale] = true
+			locales = append(locales, locale)
+		}
+	}
+
+	err = h.Repository.WithTx(c.Context(), func(q *db.Queries) error {
+		if err := q.DeleteShopLocales(c.Context(), shopID); err != nil {
+			return err
+		}
+		for _, locale := range locales {
+			if err := q.AddShopLocale(c.Context(), db.AddShopLocaleParams{
+				Locale:    locale,
+				IsDefault: locale == defaultLocale,
+				ShopID:    shopID,
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		zap.L().Error("UpdateShopLocales: failed to update locales", zap.Int64("shop_id", shopID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to update shop locales")
+	}
+
+	return api.SuccessResponse(c, fiber.StatusOK, models.ShopLocales{
+		DefaultLocale: defaultLocale,
+		Locales:       locales,
+	}, "Shop locales updated successfully")
+}
+
+// GetTranslations lists the translations of a shop
+// @Summary List translations
+// @Description Get the paginated translations of a shop, optionally filtered by resource and locale
+// @Tags Translation
+// @Produce json
+// @Param shop_id path string true "Shop ID"
+// @Param resource_type query string false "Resource type" Enums(product, product_variation, category, shop, collection)
+// @Param resource_id query int false "Resource ID"
+// @Param locale query string false "Locale"
+// @Param limit query int false "Limit" default(20)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} models.SuccessResponse{data=[]models.Translation} "Translations fetched successfully"
+// @Failure 400 {object} models.ErrorResponse "Bad request"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Security OAuth2AccessCode
+// @Router /shops/{shop_id}/translations [get]
+func (h *Handler) GetTranslations(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+
+	limit, offset, err := api.ParsePaginationParams(c)
+	if err != nil {
+		return api.BusinessLogicErrorResponse(c, "Invalid pagination parameters")
+	}
+	resourceType, err := parseTranslatableResourceType(c.Query("resource_type"))
+	if err != nil {
+		return api.ErrorResponse(c, fiber.StatusBadRequest, err.Error(), nil)
+	}
+
+	params := db.GetTranslationsParams{
+		ShopID:       shopID,
+		ResourceType: resourceType,
+		Limit:        int32(limit),
+		Offset:       int32(offset),
+	}
+	if v := c.Query("resource_id"); v != "" {
+		resourceID, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return api.ErrorResponse(c, fiber.StatusBadRequest, "Invalid resource ID", nil)
+		}
+		params.ResourceID = &resourceID
+	}
+	if v := c.Query("locale"); v != "" {
+		locale, err := services.NormalizeLocale(v)
+		if err != nil {
+			return api.ErrorResponse(c, fiber.StatusBadRequest, err.Error(), nil)
+		}
+		params.Locale = &locale
+	}
+
+	rows, err := h.Repository.GetTranslations(c.Context(), params)
+	if err != nil {
+		zap.L().Error("GetTranslations: failed to fetch translations", zap.Int64("shop_id", shopID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to fetch translations")
+	}
+
+	var totalCount int64
+	response := make([]models.Translation, len(rows))
+	for i, row := range rows {
+		totalCount = row.TotalCount
+		response[i] = models.Translation{
+			ID:           row.TranslationID,
+			ResourceType: row.ResourceType,
+			ResourceID:   row.ResourceID,
+			Field:        row.Field,
+			Locale:       row.Locale,
+			Value:        row.Value,
+			UpdatedAt:    row.UpdatedAt.Time,
+		}
+	}
+
+	page := (offset / limit) + 1
+	return api.PaginatedSuccessResponse(c, fiber.StatusOK, response, totalCount, page, limit, "Translations fetched successfully")
+}
+
+// SetTranslations creates, replaces or removes translations of a resource in one locale
+// @Summary Set translations
+// @Description Set the translated values of one or more fields of a resource in a locale. An empty value removes the translation of that field. The locale must be enabled for the shop and must not be its default locale.
+// @Tags Translation
+// @Accept json
+// @Produce json
+// @Param shop_id path string true "Shop ID"
+// @Param translations body models.TranslationsSetParams true "Translations"
+// @Success 200 {object} models.SuccessResponse{data=[]models.Translation} "Translations saved successfully"
+// @Failure 400 {object} models.ErrorResponse "Bad request"
+// @Failure 404 {object} models.ErrorResponse "Resource not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Security OAuth2AccessCode
+// @Router /shops/{shop_id}/translations [put]
+func (h *Handler) SetTranslations(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+
+	var param models.TranslationsSetParams
+	if err := c.BodyParser(&param); err != nil {
+		zap.L().Warn("SetTranslations: failed to parse request body", zap.Int64("shop_id", shopID), zap.Error(err))
+		return api.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body", nil)
+	}
+	validator := &models.XValidator{}
+	if errs := validator.Validate(&param); len(errs) > 0 {
+		return &fiber.Error{
+			Code:    fiber.ErrBadRequest.Code,
+			Message: models.FormatValidationErrors(errs),
+		}
+	}
+
+	allowed := translatableFields[param.ResourceType]
+	for field := range param.Fields {
+		if !containsString(allowed, field) {
+			return api.ErrorResponse(c, fiber.StatusBadRequest, fmt.Sprintf("Field %q of %s cannot be translated; translatable fields are %s", field, param.ResourceType, strings.Join(allowed, ", ")), nil)
+		}
+	}
+
+	locale, err := h.translationLocale(c.Context(), shopID, param.Locale)
+	if err != nil {
+		return api.ErrorResponse(c, fiber.StatusBadRequest, err.Error(), nil)
+	}
+
+	exists, err := h.Repository.TranslatableResourceExists(c.Context(), db.TranslatableResourceExistsParams{
+		ResourceType: param.ResourceType,
+		ResourceID:   param.ResourceID,
+		ShopID:       shopID,
+	})
+	if err != nil {
+		zap.L().Error("SetTranslations: failed to check resource", zap.Int64("shop_id", shopID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to check translated resource")
+	}
+	if !exists {
+		return api.NotFoundErrorResponse(c, "Resource")
+	}
+
+	response := []models.Translation{}
+	err = h.Repository.WithTx(c.Context(), func(q *db.Queries) error {
+		for _, field := range allowed {
+			value, ok := param.Fields[field]
+			if !ok {
+				continue
+			}
+			if strings.TrimSpace(value) == "" {
+				if _, err := q.DeleteTranslation(c.Context(), db.DeleteTranslationParams{
+					ResourceType: param.ResourceType,
+					ResourceID:   param.ResourceID,
+					Field:        field,
+					Locale:       locale,
+					ShopID:       shopID,
+				}); err != nil {
+					return err
+				}
+				continue
+			}
+			translation, err := q.UpsertTranslation(c.Context(), db.UpsertTranslationParams{
+				ResourceType: param.ResourceType,
+				ResourceID:   param.ResourceID,
+				Field:        field,
+				Locale:       locale,
+				Value:        value,
+				ShopID:       shopID,
+			})
+			if err != nil {
+				return err
+			}
+			response = append(response, models.Translation{
+				ID:           translation.TranslationID,
+				ResourceType: translation.ResourceType,
+				ResourceID:   translation.ResourceID,
+				Field:        translation.Field,
+				Locale:       translation.Locale,
+				Value:        translation.Value,
+				UpdatedAt:    translation.UpdatedAt.Time,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		zap.L().Error("SetTranslations: failed to save translations", zap.Int64("shop_id", shopID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to save translations")
+	}
+
+	return api.SuccessResponse(c, fiber.StatusOK, response, "Translations saved successfully")
+}
+
+// DeleteTranslations removes all translations of a resource in one locale
+// @Summary Delete translations
+// @Description Remove every translated field of a resource in a locale
+// @Tags Translation
+// @Produce json
+// @Param shop_id path string true "Shop ID"
+// @Param resource_type path string true "Resource type" Enums(product, product_variation, category, shop, collection)
+// @Param resource_id path int true "Resource ID"
+// @Param locale path string true "Locale"
+// @Success 200 {object} models.SuccessResponse{data=nil} "Translations deleted successfully"
+// @Failure 400 {object} models.ErrorResponse "Bad request"
+// @Failure 404 {object} models.ErrorResponse "Translations not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Security OAuth2AccessCode
+// @Router /shops/{shop_id}/translations/{resource_type}/{resource_id}/{locale} [delete]
+func (h *Handler) DeleteTranslations(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+	resourceType, err := parseTranslatableResourceType(c.Params("resource_type"))
+	if err != nil || !resourceType.Valid {
+		return api.ErrorResponse(c, fiber.StatusBadRequest, "Invalid resource type", nil)
+	}
+	resourceID, err := api.ParseIDParameter(c, "resource_id", "Resource")
+	if err != nil {
+		return err
+	}
+	locale, err := services.NormalizeLocale(c.Params("locale"))
+	if err != nil {
+		return api.ErrorResponse(c, fiber.StatusBadRequest, err.Error(), nil)
+	}
+
+	deleted, err := h.Repository.DeleteTranslationsByLocale(c.Context(), db.DeleteTranslationsByLocaleParams{
+		ResourceType: resourceType.TranslatableResourceType,
+		ResourceID:   resourceID,
+		Locale:       locale,
+		ShopID:       shopID,
+	})
+	if err != nil {
+		zap.L().Error("DeleteTranslations: failed to delete translations", zap.Int64("shop_id", shopID), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to delete translations")
+	}
+	if deleted == 0 {
+		return api.NotFoundErrorResponse(c, "Translations")
+	}
+
+	return api.SuccessResponse(c, fiber.StatusOK, nil, "Translations deleted successfully")
+}
+
+// GetMissingTranslations reports translatable fields that have no translation in a locale
+// @Summary Missing translations report
+// @Description List the translatable fields with content in the default locale that have not been translated into the given locale
+// @Tags Translation
+// @Produce json
+// @Param shop_id path string true "Shop ID"
+// @Param locale query string true "Locale"
+// @Param resource_type query string false "Resource type" Enums(product, product_variation, category, shop, collection)
+// @Param limit query int false "Limit" default(20)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} models.SuccessResponse{data=[]models.MissingTranslation} "Missing translations fetched successfully"
+// @Failure 400 {object} models.ErrorResponse "Bad request"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Security OAuth2AccessCode
+// @Router /shops/{shop_id}/translations/missing [get]
+func (h *Handler) GetMissingTranslations(c *fiber.Ctx) error {
+	shopID, err := api.ParseIDParameter(c, "shop_id", "Shop")
+	if err != nil {
+		return err
+	}
+
+	limit, offset, err := api.ParsePaginationParams(c)
+	if err != nil {
+		return api.BusinessLogicErrorResponse(c, "Invalid pagination parameters")
+	}
+	resourceType, err := parseTranslatableResourceType(c.Query("resource_type"))
+	if err != nil {
+		return api.ErrorResponse(c, fiber.StatusBadRequest, err.Error(), nil)
+	}
+	locale, err := h.translationLocale(c.Context(), shopID, c.Query("locale"))
+	if err != nil {
+		return api.ErrorResponse(c, fiber.StatusBadRequest, err.Error(), nil)
+	}
+
+	rows, err := h.Repository.GetMissingTranslations(c.Context(), db.GetMissingTranslationsParams{
+		ShopID:       shopID,
+		ResourceType: resourceType,
+		Locale:       locale,
+		Limit:        int32(limit),
+		Offset:       int32(offset),
+	})
+	if err != nil {
+		zap.L().Error("GetMissingTranslations: failed to build report", zap.Int64("shop_id", shopID), zap.String("locale", locale), zap.Error(err))
+		return api.SystemErrorResponse(c, err, "Failed to fetch missing translations")
+	}
+
+	var totalCount int64
+	response := make([]models.MissingTranslation, len(rows))
+	for i, row := range rows {
+		totalCount = row.TotalCount
+		response[i] = models.MissingTranslation{
+			ResourceType: row.ResourceType,
+			ResourceID:   row.ResourceID,
+			Field:        row.Field,
+			SourceValue:  row.SourceValue,
+		}
+	}
+
+	page := (offset / limit) + 1
+	return api.PaginatedSuccessResponse(c, fiber.StatusOK, response, totalCount, page, limit, "Missing translations fetched successfully")
+}
+
+// translationLocale normalizes a locale and checks that it is enabled for the
+// shop and is not the default locale, which holds the source content
+func (h *Handler) translationLocale(ctx context.Context, shopID int64, raw string) (string, error) {
+	if raw == "" {
+		return "", fmt.Errorf("locale is required")
+	}
+	locale, err := services.NormalizeLocale(raw)
+	if err != nil {
+		return "", err
+	}
+	rows, err := h.Repository.GetShopLocales(ctx, shopID)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch shop locales")
+	}
+	defaultLocale, locales := services.ShopLocales(rows)
+	if locale == defaultLocale {
+		return "", fmt.Errorf("%s is the default locale of the shop and cannot be translated", locale)
+	}
+	if !containsString(locales, locale) {
+		return "", fmt.Errorf("locale %s is not enabled for the shop", locale)
+	}
+	return locale, nil
+}
+
+func parseTranslatableResourceType(v string) (db.NullTranslatableResourceType, error) {
+	if v == "" {
+		return db.NullTranslatableResourceType{}, nil
+	}
+	resourceType := db.TranslatableResourceType(v)
+	if _, ok := translatableFields[resourceType]; !ok {
+		return db.NullTranslatableResourceType{}, fmt.Errorf("invalid resource type %q", v)
+	}
+	return db.NullTranslatableResourceType{TranslatableResourceType: resourceType, Valid: true}, nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -17,13 +17,15 @@ import (
 type WebhookHandler struct {
 	paymentProcessorFactory *services.PaymentProcessorFactory
 	repository              db.Repository
+	digitalDelivery         *services.DigitalDelivery
 }
 
 // NewWebhookHandler creates a new webhook handler with PaymentProcessorFactory
-func NewWebhookHandler(paymentProcessorFactory *services.PaymentProcessorFactory, repo db.Repository) *WebhookHandler {
+func NewWebhookHandler(paymentProcessorFactory *services.PaymentProcessorFactory, repo db.Repository, digitalDelivery *services.DigitalDelivery) *WebhookHandler {
 	return &WebhookHandler{
 		paymentProcessorFactory: paymentProcessorFactory,
 		repository:              repo,
+		digitalDelivery:         digitalDelivery,
 	}
 }
 
@@ -234,6 +236,11 @@ func (h *WebhookHandler) processWebhookPayload(ctx context.Context, shopID int64
 				return fmt.Errorf("failed to update order status: %w", err)
 			}
 
+			if err := h.settleOrderTender(ctx, shopID, updatedOrder); err != nil {
+				zap.L().Error("processWebhookPayload: failed to settle gift cards and store credit", zap.Int64("order_id", updatedOrder.OrderID), zap.Error(err))
+				return fmt.Errorf("failed to settle gift cards and store credit: %w", err)
+			}
+
 			zap.L().Info("processWebhookPayload: successfully updated order status",
 				zap.Int64("order_id", updatedOrder.OrderID),
 				zap.String("status", string(updatedOrder.Status)),
@@ -257,7 +264,7 @@ func (h *WebhookHandler) processWebhookPayload(ctx context.Context, shopID int64
 
 		// For now, log the webhook data for manual investigation
 		zap.L().Warn("processWebhookPayload: webhook received for payment id but no order found", zap.String("payment_id", payload.PaymentID))
-		zap.L().Info("processWebhookPayload: payment details", zap.Float64("amount", payload.Amount), zap.String("currency", payload.Currency), zap.String("provider", payload.Provider))
+		zap.L().Info("processWebhookPayload: payment details", zap.Stringer("amount", payload.Amount), zap.String("currency", payload.Currency), zap.String("provider", payload.Provider))
 
 		// Store webhook data for later processing or investigation
 		// In a production system, you might want to store unmatched webhooks in a separate table
@@ -274,3 +281,29 @@ func (h *WebhookHandler) processWebhookPayload(ctx context.Context, shopID int64
 
 	return nil
 }
+
+// settleOrderTender issues the gift cards and delivers the digital goods bought by
+// a paid order, and gives back what gift cards, store credit and loyalty points
+// paid towards an order whose payment failed or was refunded
+func (h *WebhookHandler) settleOrderTender(ctx context.Context, shopID int64, order db.Order) error {
+	if order.PaymentStatus != db.PaymentStatusTypePaid && order.Status != db.OrderStatusTypeCancelled && order.Status != db.OrderStatusTypeRefunded {
+		return nil
+	}
+	shop, err := h.repository.GetShop(ctx, shopID)
+	if err != nil {
+		return err
+	}
+	return h.repository.WithTx(ctx, func(q *db.Queries) error {
+		if order.Status == db.OrderStatusTypeCancelled || order.Status == db.OrderStatusTypeRefunded {
+			if _, err := services.ReleaseOrderTender(ctx, q, shopID, order.OrderID, shop.CurrencyCode); err != nil {
+				return err
+			}
+			return services.ReverseOrderLoyaltyPoints(ctx, q, shopID, order.OrderID)
+		}
+		if _, err := services.IssueOrderGiftCards(ctx, q, order, shop.CurrencyCode); err != nil {
+			return err
+		}
+		_, err := services.DeliverOrderDigitalGoods(ctx, q, h.digitalDelivery, order)
+		return err
+	})
+}
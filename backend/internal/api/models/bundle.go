@@ -0,0 +1,57 @@
+package models
+
+import "github.com/petrejonn/naytife/internal/db"
+
+// BundleComponentParams is a quantity of a variant that a unit of a bundle is made of
+type BundleComponentParams struct {
+	VariantID int64 `json:"variant_id" validate:"required"`
+	Quantity  int64 `json:"quantity" validate:"required,min=1" example:"2"`
+}
+
+// BundleComponentsParams represents the request body for setting the components
+// of a bundle variant. An empty list makes the variant an ordinary one again.
+type BundleComponentsParams struct {
+	Components []BundleComponentParams `json:"components" validate:"unique=VariantID,dive"`
+}
+
+// BundleComponent is a variant a bundle is made of
+type BundleComponent struct {
+	VariantID    int64  `json:"variant_id"`
+	ProductID    int64  `json:"product_id"`
+	ProductTitle string `json:"product_title"`
+	VariantTitle string `json:"variant_title"`
+	SKU          string `json:"sku"`
+	// Quantity is the number of units of the variant in a bundle
+	Quantity int64 `json:"quantity"`
+	// AvailableQuantity is the stock of the variant at the locations that sell online
+	AvailableQuantity int64 `json:"available_quantity"`
+}
+
+// Bundle is a variant made of other variants. Its available quantity is the number
+// of bundles the stock of its components makes up.
+type Bundle struct {
+	VariantID         int64             `json:"variant_id"`
+	AvailableQuantity int64             `json:"available_quantity"`
+	Components        []BundleComponent `json:"components"`
+}
+
+// NewBundle converts a stored variant and its components to their API representation
+func NewBundle(variant db.ProductVariation, components []db.ListBundleComponentsRow) Bundle {
+	response := Bundle{
+		VariantID:         variant.ProductVariationID,
+		AvailableQuantity: variant.AvailableQuantity,
+		Components:        make([]BundleComponent, len(components)),
+	}
+	for i, component := range components {
+		response.Components[i] = BundleComponent{
+			VariantID:         component.ComponentVariationID,
+			ProductID:         component.ProductID,
+			ProductTitle:      component.ProductTitle,
+			VariantTitle:      component.VariantTitle,
+			SKU:               component.Sku,
+			Quantity:          component.Quantity,
+			AvailableQuantity: component.AvailableQuantity,
+		}
+	}
+	return response
+}
@@ -1,6 +1,10 @@
 package models
 
-import "time"
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
 
 // CheckoutRequest represents a checkout initiation request
 type CheckoutRequest struct {
@@ -16,20 +20,21 @@ type CheckoutRequest struct {
 
 // CheckoutResponse represents the checkout response
 type CheckoutResponse struct {
-	ShopID          int64               `json:"shop_id"`
-	ShopName        string              `json:"shop_name"`
-	CurrencyCode    string              `json:"currency_code"`
-	Subtotal        float64             `json:"subtotal"`
-	Tax             float64             `json:"tax"`
-	Shipping        float64             `json:"shipping"`
-	Discount        float64             `json:"discount"`
-	Total           float64             `json:"total"`
-	PaymentMethods  []PaymentMethodInfo `json:"payment_methods"`
-	CustomerInfo    CustomerInfo        `json:"customer_info"`
-	ShippingAddress ShippingAddress     `json:"shipping_address"`
-	BillingAddress  *ShippingAddress    `json:"billing_address,omitempty"`
-	SessionID       *string             `json:"session_id,omitempty"`
-	ExpiresAt       time.Time           `json:"expires_at"`
+	ShopID                int64               `json:"shop_id"`
+	ShopName              string              `json:"shop_name"`
+	CurrencyCode          string              `json:"currency_code"`
+	PresentmentCurrencies []string            `json:"presentment_currencies"`
+	Subtotal              float64             `json:"subtotal"`
+	Tax                   float64             `json:"tax"`
+	Shipping              float64             `json:"shipping"`
+	Discount              float64             `json:"discount"`
+	Total                 float64             `json:"total"`
+	PaymentMethods        []PaymentMethodInfo `json:"payment_methods"`
+	CustomerInfo          CustomerInfo        `json:"customer_info"`
+	ShippingAddress       ShippingAddress     `json:"shipping_address"`
+	BillingAddress        *ShippingAddress    `json:"billing_address,omitempty"`
+	SessionID             *string             `json:"session_id,omitempty"`
+	ExpiresAt             time.Time           `json:"expires_at"`
 }
 
 // CustomerInfo represents customer information for checkout
@@ -50,6 +55,30 @@ type ShippingAddress struct {
 	City         string  `json:"city" validate:"required"`
 	State        *string `json:"state,omitempty"`
 	PostalCode   string  `json:"postal_code" validate:"required"`
-	Country      string  `json:"country" validate:"required"`
+	Country      string  `json:"country" validate:"required,iso3166_1_alpha2"`
 	Phone        *string `json:"phone,omitempty"`
 }
+
+// Normalize trims the address fields and upper-cases the country code so it
+// validates as ISO 3166-1 alpha-2
+func (a *ShippingAddress) Normalize() {
+	a.FirstName = strings.TrimSpace(a.FirstName)
+	a.LastName = strings.TrimSpace(a.LastName)
+	a.AddressLine1 = strings.TrimSpace(a.AddressLine1)
+	a.City = strings.TrimSpace(a.City)
+	a.PostalCode = strings.TrimSpace(a.PostalCode)
+	a.Country = strings.ToUpper(strings.TrimSpace(a.Country))
+}
+
+// Snapshot encodes the address as stored in orders.shipping_address
+func (a ShippingAddress) Snapshot() ([]byte, error) {
+	return json.Marshal(a)
+}
+
+// ShippingAddressFromSnapshot decodes orders.shipping_address. Orders placed
+// before addresses were structured only carry address_line_1.
+func ShippingAddressFromSnapshot(snapshot []byte) ShippingAddress {
+	var address ShippingAddress
+	_ = json.Unmarshal(snapshot, &address)
+	return address
+}
@@ -0,0 +1,75 @@
+package models
+
+import (
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/petrejonn/naytife/internal/db"
+)
+
+type Collection struct {
+	ID          int64             `json:"collection_id"`
+	Handle      string            `json:"handle"`
+	Title       string            `json:"title"`
+	Description *string           `json:"description"`
+	Type        db.CollectionType `json:"collection_type" example:"smart"`
+	Rules       []CollectionRule  `json:"rules"`
+	MatchAll    bool              `json:"match_all"`
+	SortOrder   string            `json:"sort_order" example:"best_selling"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+}
+
+// CollectionRule is a single condition of a smart collection.
+// Supported fields and operators:
+//   - price: eq, gt, gte, lt, lte (default variant price)
+//   - product_type: eq, neq (product type ID)
+//   - attribute: eq, neq (requires attribute_id; matches product or variant values)
+//   - created_at: gt, lt (RFC 3339 timestamp), within_days (number of days)
+//   - sales_rank: lte (top N best sellers)
+//   - tag: eq, neq (case-insensitive)
+type CollectionRule struct {
+	Field       string `json:"field" validate:"required,oneof=price product_type attribute created_at sales_rank tag" example:"price"`
+	Operator    string `json:"operator" validate:"required,oneof=eq neq gt gte lt lte within_days" example:"lt"`
+	Value       string `json:"value" validate:"required" example:"5000"`
+	AttributeID *int64 `json:"attribute_id,omitempty"`
+}
+
+type CollectionCreateParams struct {
+	Title       string            `json:"title" validate:"required,min=1,max=255" example:"Summer Sale"`
+	Handle      string            `json:"handle,omitempty" validate:"omitempty,max=255" example:"summer-sale"`
+	Description *string           `json:"description,omitempty"`
+	Type        db.CollectionType `json:"collection_type" validate:"required,oneof=manual smart" example:"smart"`
+	Rules       []CollectionRule  `json:"rules,omitempty" validate:"omitempty,dive"`
+	MatchAll    *bool             `json:"match_all,omitempty"`
+	SortOrder   string            `json:"sort_order,omitempty" validate:"omitempty,oneof=manual best_selling created_desc price_asc price_desc title_asc" example:"manual"`
+}
+
+type CollectionUpdateParams struct {
+	Title       *string          `json:"title,omitempty" validate:"omitempty,min=1,max=255" example:"Summer Sale"`
+	Handle      *string          `json:"handle,omitempty" validate:"omitempty,min=1,max=255" example:"summer-sale"`
+	Description *string          `json:"description,omitempty"`
+	Rules       []CollectionRule `json:"rules,omitempty" validate:"omitempty,dive"`
+	MatchAll    *bool            `json:"match_all,omitempty"`
+	SortOrder   *string          `json:"sort_order,omitempty" validate:"omitempty,oneof=manual best_selling created_desc price_asc price_desc title_asc" example:"best_selling"`
+}
+
+type CollectionProduct struct {
+	ProductID int64            `json:"product_id"`
+	Title     string           `json:"title"`
+	Slug      string           `json:"slug"`
+	Status    db.ProductStatus `json:"status"`
+	Price     pgtype.Numeric   `json:"price" swaggertype:"primitive,number"`
+	UnitsSold int64            `json:"units_sold"`
+	Position  *int32           `json:"position,omitempty"`
+	CreatedAt time.Time        `json:"created_at"`
+}
+
+type CollectionProductAddParams struct {
+	ProductID int64  `json:"product_id" validate:"required,gt=0"`
+	Position  *int32 `json:"position,omitempty" validate:"omitempty,gt=0"`
+}
+
+type CollectionProductsReorderParams struct {
+	ProductIDs []int64 `json:"product_ids" validate:"required,min=1"`
+}
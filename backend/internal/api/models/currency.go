@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/petrejonn/naytife/internal/money"
+)
+
+type ShopCurrency struct {
+	CurrencyCode string `json:"currency_code" example:"GHS"`
+	// ExchangeRate converts one unit of the shop currency into this currency
+	ExchangeRate float64 `json:"exchange_rate" example:"0.0098"`
+	// RateSource is "manual" when the merchant set the rate and "provider" when it comes from the rate source
+	RateSource string    `json:"rate_source" example:"manual"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+type ShopCurrencies struct {
+	CurrencyCode          string         `json:"currency_code" example:"NGN"`
+	PresentmentCurrencies []ShopCurrency `json:"presentment_currencies"`
+}
+
+// ShopCurrencyParams enables a presentment currency. Leaving exchange_rate out uses the rate source.
+type ShopCurrencyParams struct {
+	ExchangeRate *float64 `json:"exchange_rate,omitempty" validate:"omitempty,gt=0" example:"0.0098"`
+}
+
+type VariantPrice struct {
+	CurrencyCode string      `json:"currency_code" example:"KES"`
+	Price        money.Money `json:"price" swaggertype:"string" example:"2500.00"`
+	UpdatedAt    time.Time   `json:"updated_at"`
+}
+
+type VariantPriceParams struct {
+	Price money.Money `json:"price" swaggertype:"string" example:"2500.00"`
+}
+
+type OrderPresentment struct {
+	CurrencyCode string         `json:"currency_code" example:"GHS"`
+	ExchangeRate pgtype.Numeric `json:"exchange_rate" swaggertype:"primitive,number"`
+	Amount       money.Money    `json:"amount" swaggertype:"string" example:"12.50"`
+	CreatedAt    time.Time      `json:"created_at"`
+}
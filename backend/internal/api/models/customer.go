@@ -31,7 +31,7 @@ type OrderResponse struct {
 	Discount        float64             `json:"discount"`
 	ShippingCost    float64             `json:"shipping_cost"`
 	Tax             float64             `json:"tax"`
-	ShippingAddress string              `json:"shipping_address"`
+	ShippingAddress ShippingAddress     `json:"shipping_address"`
 	PaymentMethod   string              `json:"payment_method"`
 	PaymentStatus   string              `json:"payment_status"`
 	ShippingMethod  string              `json:"shipping_method"`
@@ -98,3 +98,43 @@ func PaymentStatusToString(status db.PaymentStatusType) string {
 func ShippingStatusToString(status db.ShippingStatusType) string {
 	return string(status)
 }
+
+// CustomerAddress represents a saved address of a customer
+type CustomerAddress struct {
+	ID int64 `json:"id"`
+	ShippingAddress
+	IsDefaultShipping bool      `json:"is_default_shipping"`
+	IsDefaultBilling  bool      `json:"is_default_billing"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// CustomerAddressParams represents the request body for creating or replacing a customer address
+type CustomerAddressParams struct {
+	ShippingAddress
+	IsDefaultShipping bool `json:"is_default_shipping"`
+	IsDefaultBilling  bool `json:"is_default_billing"`
+}
+
+// NewCustomerAddress converts a stored address to its API representation
+func NewCustomerAddress(address db.CustomerAddress) CustomerAddress {
+	return CustomerAddress{
+		ID: address.AddressID,
+		ShippingAddress: ShippingAddress{
+			FirstName:    address.FirstName,
+			LastName:     address.LastName,
+			Company:      address.Company,
+			AddressLine1: address.AddressLine1,
+			AddressLine2: address.AddressLine2,
+			City:         address.City,
+			State:        address.State,
+			PostalCode:   address.PostalCode,
+			Country:      address.Country,
+			Phone:        address.Phone,
+		},
+		IsDefaultShipping: address.IsDefaultShipping,
+		IsDefaultBilling:  address.IsDefaultBilling,
+		CreatedAt:         address.CreatedAt.Time,
+		UpdatedAt:         address.UpdatedAt.Time,
+	}
+}
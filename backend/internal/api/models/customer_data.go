@@ -0,0 +1,42 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/petrejonn/naytife/internal/db"
+)
+
+// CustomerDataExport is everything a shop stores about a customer, returned for
+// data subject access requests
+type CustomerDataExport struct {
+	ExportedAt time.Time         `json:"exported_at"`
+	Customer   CustomerResponse  `json:"customer"`
+	Addresses  []CustomerAddress `json:"addresses"`
+	Orders     []Order           `json:"orders"`
+	Reviews    []ProductReview   `json:"reviews"`
+}
+
+// CustomerDataRequest is the audit record of an export or erasure of a customer's data
+type CustomerDataRequest struct {
+	ID          int64                      `json:"id"`
+	Kind        db.CustomerDataRequestKind `json:"kind" example:"erasure"`
+	CustomerID  uuid.UUID                  `json:"customer_id"`
+	RequestedBy *string                    `json:"requested_by"`
+	// Details holds the number of records exported or anonymized
+	Details   json.RawMessage `json:"details" swaggertype:"object"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// NewCustomerDataRequest converts a stored data request to its API representation
+func NewCustomerDataRequest(request db.CustomerDataRequest) CustomerDataRequest {
+	return CustomerDataRequest{
+		ID:          request.RequestID,
+		Kind:        request.Kind,
+		CustomerID:  request.ShopCustomerID,
+		RequestedBy: request.RequestedBy,
+		Details:     request.Details,
+		CreatedAt:   request.CreatedAt.Time,
+	}
+}
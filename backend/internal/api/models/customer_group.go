@@ -0,0 +1,152 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/petrejonn/naytife/internal/db"
+	"github.com/petrejonn/naytife/internal/money"
+)
+
+// CustomerGroup represents a manual group of customers, e.g. wholesale or VIP
+type CustomerGroup struct {
+	ID          int64   `json:"id"`
+	Name        string  `json:"name" example:"Wholesale"`
+	Description *string `json:"description"`
+	// DiscountPercent is taken off the base price of variants without a group price
+	DiscountPercent *float64  `json:"discount_percent" example:"10"`
+	MemberCount     *int64    `json:"member_count,omitempty"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// CustomerGroupParams represents the request body for creating or replacing a customer group
+type CustomerGroupParams struct {
+	Name            string   `json:"name" validate:"required,max=100" example:"Wholesale"`
+	Description     *string  `json:"description"`
+	DiscountPercent *float64 `json:"discount_percent" validate:"omitempty,gt=0,lte=100" example:"10"`
+}
+
+// CustomerGroupMemberParams adds a customer to a group
+type CustomerGroupMemberParams struct {
+	CustomerID uuid.UUID `json:"customer_id" validate:"required"`
+}
+
+// CustomerGroupPrice is the price of a variant for members of a group, in the shop currency
+type CustomerGroupPrice struct {
+	VariantID int64       `json:"variant_id"`
+	Price     money.Money `json:"price" swaggertype:"string" example:"2000.00"`
+	UpdatedAt time.Time   `json:"updated_at"`
+}
+
+// CustomerSegment represents a rule-based segment. Unset rules match every customer.
+type CustomerSegment struct {
+	ID          int64   `json:"id"`
+	Name        string  `json:"name" example:"Lapsed big spenders"`
+	Description *string `json:"description"`
+	CustomerSegmentRules
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// CustomerSegmentRules are evaluated against the completed orders of each customer.
+// Spend and order count only include orders of the last window_days when it is set;
+// inactive_days matches customers without any order in that many days.
+type CustomerSegmentRules struct {
+	MinTotalSpent *money.Money `json:"min_total_spent,omitempty" swaggertype:"string" example:"100000.00"`
+	MaxTotalSpent *money.Money `json:"max_total_spent,omitempty" swaggertype:"string"`
+	MinOrderCount *int32       `json:"min_order_count,omitempty" validate:"omitempty,gte=0"`
+	MaxOrderCount *int32       `json:"max_order_count,omitempty" validate:"omitempty,gte=0"`
+	WindowDays    *int32       `json:"window_days,omitempty" validate:"omitempty,gt=0" example:"90"`
+	InactiveDays  *int32       `json:"inactive_days,omitempty" validate:"omitempty,gt=0" example:"180"`
+}
+
+// CustomerSegmentParams represents the request body for creating or replacing a customer segment
+type CustomerSegmentParams struct {
+	Name        string  `json:"name" validate:"required,max=100" example:"Lapsed big spenders"`
+	Description *string `json:"description"`
+	CustomerSegmentRules
+}
+
+// CustomerSegmentMember is a customer matching a segment, with the order aggregates the rules were evaluated on
+type CustomerSegmentMember struct {
+	CustomerID  uuid.UUID   `json:"customer_id"`
+	Email       string      `json:"email"`
+	Name        *string     `json:"name"`
+	OrderCount  int64       `json:"order_count"`
+	TotalSpent  money.Money `json:"total_spent" swaggertype:"string" example:"125000.00"`
+	LastOrderAt *time.Time  `json:"last_order_at"`
+}
+
+// NewCustomerGroup converts a stored customer group to its API representation
+func NewCustomerGroup(group db.CustomerGroup) CustomerGroup {
+	response := CustomerGroup{
+		ID:          group.GroupID,
+		Name:        group.Name,
+		Description: group.Description,
+		CreatedAt:   group.CreatedAt.Time,
+		UpdatedAt:   group.UpdatedAt.Time,
+	}
+	if group.DiscountPercent.Valid {
+		discount := NumericToFloat64(group.DiscountPercent)
+		response.DiscountPercent = &discount
+	}
+	return response
+}
+
+// NewCustomerSegment converts a stored customer segment to its API representation.
+// Spend thresholds are amounts in the shop currency.
+func NewCustomerSegment(segment db.CustomerSegment, currencyCode string) (CustomerSegment, error) {
+	response := CustomerSegment{
+		ID:          segment.SegmentID,
+		Name:        segment.Name,
+		Description: segment.Description,
+		CustomerSegmentRules: CustomerSegmentRules{
+			MinOrderCount: segment.MinOrderCount,
+			MaxOrderCount: segment.MaxOrderCount,
+			WindowDays:    segment.WindowDays,
+			InactiveDays:  segment.InactiveDays,
+		},
+		CreatedAt: segment.CreatedAt.Time,
+		UpdatedAt: segment.UpdatedAt.Time,
+	}
+	var err error
+	if response.MinTotalSpent, err = optionalMoney(segment.MinTotalSpent, currencyCode); err != nil {
+		return CustomerSegment{}, err
+	}
+	if response.MaxTotalSpent, err = optionalMoney(segment.MaxTotalSpent, currencyCode); err != nil {
+		return CustomerSegment{}, err
+	}
+	return response, nil
+}
+
+// NewCustomerSegmentMember converts an evaluated segment member to its API representation
+func NewCustomerSegmentMember(row db.ListCustomerSegmentMembersRow, currencyCode string) (CustomerSegmentMember, error) {
+	totalSpent, err := money.FromNumeric(row.TotalSpent, currencyCode)
+	if err != nil {
+		return CustomerSegmentMember{}, err
+	}
+	member := CustomerSegmentMember{
+		CustomerID: row.ShopCustomerID,
+		Email:      row.Email,
+		Name:       row.Name,
+		OrderCount: row.OrderCount,
+		TotalSpent: totalSpent,
+	}
+	if row.LastOrderAt.Valid {
+		member.LastOrderAt = &row.LastOrderAt.Time
+	}
+	return member, nil
+}
+
+func optionalMoney(n pgtype.Numeric, currencyCode string) (*money.Money, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	amount, err := money.FromNumeric(n, currencyCode)
+	if err != nil {
+		return nil, err
+	}
+	return &amount, nil
+}
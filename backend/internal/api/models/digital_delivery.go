@@ -0,0 +1,130 @@
+package models
+
+import (
+	"time"
+
+	"github.com/petrejonn/naytife/internal/db"
+)
+
+// LicenseKeyAssetParams represents the request body for adding a pool of license
+// keys to a digital variant. Every unit sold takes a key from the pool.
+type LicenseKeyAssetParams struct {
+	Title       string   `json:"title" validate:"required,max=255" example:"Activation key"`
+	LicenseKeys []string `json:"license_keys" validate:"omitempty,dive,required,max=255"`
+}
+
+// LicenseKeysParams represents the request body for adding keys to a license key pool
+type LicenseKeysParams struct {
+	LicenseKeys []string `json:"license_keys" validate:"required,min=1,dive,required,max=255"`
+}
+
+// DigitalAssetUpdateParams represents the request body for updating a digital asset.
+// Limits apply to orders delivered after the update.
+type DigitalAssetUpdateParams struct {
+	Title            *string `json:"title" validate:"omitempty,min=1,max=255"`
+	DownloadLimit    *int32  `json:"download_limit" validate:"omitempty,min=1" example:"5"`
+	ExpiresAfterDays *int32  `json:"expires_after_days" validate:"omitempty,min=1" example:"30"`
+}
+
+// DigitalAsset is a file or a pool of license keys delivered with a digital variant
+type DigitalAsset struct {
+	ID          int64   `json:"id"`
+	VariantID   int64   `json:"variant_id"`
+	Kind        string  `json:"kind" example:"file"`
+	Title       string  `json:"title"`
+	FileName    *string `json:"file_name"`
+	ContentType *string `json:"content_type"`
+	SizeBytes   *int64  `json:"size_bytes"`
+	// DownloadLimit is the number of downloads of the file per order line; unlimited when empty
+	DownloadLimit *int32 `json:"download_limit"`
+	// ExpiresAfterDays is the number of days the file can be downloaded after payment; forever when empty
+	ExpiresAfterDays *int32 `json:"expires_after_days"`
+	// AvailableKeys is the number of keys left in the pool of a license key asset
+	AvailableKeys *int64    `json:"available_keys,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// OrderDownload is a file or license key an order delivered
+type OrderDownload struct {
+	ID          int64   `json:"id"`
+	OrderItemID int64   `json:"order_item_id"`
+	VariantID   int64   `json:"variant_id"`
+	AssetID     int64   `json:"asset_id"`
+	Kind        string  `json:"kind" example:"file"`
+	Title       string  `json:"title"`
+	FileName    *string `json:"file_name"`
+	SizeBytes   *int64  `json:"size_bytes"`
+	// LicenseKey is empty for files, and for license keys waiting for the pool to be topped up
+	LicenseKey    *string    `json:"license_key"`
+	DownloadCount int32      `json:"download_count"`
+	DownloadLimit *int32     `json:"download_limit"`
+	ExpiresAt     *time.Time `json:"expires_at"`
+	// URL is a signed link to the file while it can still be downloaded
+	URL       *string   `json:"url"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// DownloadEvent is a download of a file through its signed link
+type DownloadEvent struct {
+	ID        int64     `json:"id"`
+	IPAddress *string   `json:"ip_address"`
+	UserAgent *string   `json:"user_agent"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NewDigitalAsset converts a stored digital asset to its API representation. The
+// number of available keys is only given for license key assets.
+func NewDigitalAsset(asset db.DigitalAsset, availableKeys int64) DigitalAsset {
+	response := DigitalAsset{
+		ID:               asset.DigitalAssetID,
+		VariantID:        asset.ProductVariationID,
+		Kind:             string(asset.Kind),
+		Title:            asset.Title,
+		FileName:         asset.FileName,
+		ContentType:      asset.ContentType,
+		SizeBytes:        asset.SizeBytes,
+		DownloadLimit:    asset.DownloadLimit,
+		ExpiresAfterDays: asset.ExpiresAfterDays,
+		CreatedAt:        asset.CreatedAt.Time,
+		UpdatedAt:        asset.UpdatedAt.Time,
+	}
+	if asset.Kind == db.DigitalAssetKindLicenseKey {
+		response.AvailableKeys = &availableKeys
+	}
+	return response
+}
+
+// NewOrderDownload converts a stored download to its API representation with its
+// signed link, if it has one
+func NewOrderDownload(download db.ListOrderDigitalDownloadsRow, url *string) OrderDownload {
+	response := OrderDownload{
+		ID:            download.DownloadID,
+		OrderItemID:   download.OrderItemID,
+		VariantID:     download.ProductVariationID,
+		AssetID:       download.DigitalAssetID,
+		Kind:          string(download.Kind),
+		Title:         download.Title,
+		FileName:      download.FileName,
+		SizeBytes:     download.SizeBytes,
+		LicenseKey:    download.LicenseKey,
+		DownloadCount: download.DownloadCount,
+		DownloadLimit: download.DownloadLimit,
+		URL:           url,
+		CreatedAt:     download.CreatedAt.Time,
+	}
+	if download.ExpiresAt.Valid {
+		response.ExpiresAt = &download.ExpiresAt.Time
+	}
+	return response
+}
+
+// NewDownloadEvent converts a stored download event to its API representation
+func NewDownloadEvent(event db.DigitalDownloadEvent) DownloadEvent {
+	return DownloadEvent{
+		ID:        event.EventID,
+		IPAddress: event.IpAddress,
+		UserAgent: event.UserAgent,
+		CreatedAt: event.CreatedAt.Time,
+	}
+}
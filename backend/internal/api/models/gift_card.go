@@ -0,0 +1,165 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/petrejonn/naytife/internal/db"
+	"github.com/petrejonn/naytife/internal/money"
+)
+
+// GiftCard represents a gift card and its remaining balance
+type GiftCard struct {
+	ID             int64       `json:"id"`
+	Code           string      `json:"code" example:"K7QM3XPR9TWA2HCE"`
+	InitialAmount  money.Money `json:"initial_amount" swaggertype:"string" example:"50.00"`
+	Balance        money.Money `json:"balance" swaggertype:"string" example:"32.50"`
+	CurrencyCode   string      `json:"currency_code" example:"NGN"`
+	ExpiresAt      *time.Time  `json:"expires_at"`
+	Disabled       bool        `json:"disabled"`
+	Note           *string     `json:"note"`
+	RecipientEmail *string     `json:"recipient_email"`
+	CustomerID     *uuid.UUID  `json:"customer_id"`
+	// OrderID is the order that bought the card; empty for cards issued by staff
+	OrderID   *int64    `json:"order_id"`
+	CreatedBy *string   `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// GiftCardCreateParams represents the request body for issuing a gift card
+type GiftCardCreateParams struct {
+	// Code is generated when left out
+	Code *string `json:"code" validate:"omitempty,min=4,max=32" example:"SUMMER-2025"`
+	// Amount is in the shop currency
+	Amount         money.Money `json:"amount" swaggertype:"string" example:"50.00"`
+	ExpiresAt      *time.Time  `json:"expires_at"`
+	Note           *string     `json:"note"`
+	RecipientEmail *string     `json:"recipient_email" validate:"omitempty,email"`
+	CustomerID     *uuid.UUID  `json:"customer_id"`
+}
+
+// GiftCardUpdateParams represents the request body for updating a gift card. Fields
+// left out keep their current value.
+type GiftCardUpdateParams struct {
+	ExpiresAt      *time.Time `json:"expires_at"`
+	Disabled       *bool      `json:"disabled"`
+	Note           *string    `json:"note"`
+	RecipientEmail *string    `json:"recipient_email" validate:"omitempty,email"`
+}
+
+// GiftCardAdjustParams represents a manual change to the balance of a gift card
+type GiftCardAdjustParams struct {
+	// Amount is added to the balance; negative amounts take from it
+	Amount money.Money `json:"amount" swaggertype:"string" example:"-5.00"`
+	Note   *string     `json:"note" validate:"required"`
+}
+
+// CreditTransaction is an entry of a gift card or store credit ledger. Amounts are
+// signed: issue and refund add to the balance, redeem takes from it.
+type CreditTransaction struct {
+	ID           int64       `json:"id"`
+	Kind         string      `json:"kind" example:"redeem"`
+	Amount       money.Money `json:"amount" swaggertype:"string" example:"-17.50"`
+	BalanceAfter money.Money `json:"balance_after" swaggertype:"string" example:"32.50"`
+	Reason       *string     `json:"reason,omitempty" example:"goodwill"`
+	OrderID      *int64      `json:"order_id"`
+	Note         *string     `json:"note"`
+	CreatedBy    *string     `json:"created_by"`
+	CreatedAt    time.Time   `json:"created_at"`
+}
+
+// StoreCredit is the store credit balance of a customer
+type StoreCredit struct {
+	CustomerID   uuid.UUID   `json:"customer_id"`
+	Balance      money.Money `json:"balance" swaggertype:"string" example:"20.00"`
+	CurrencyCode string      `json:"currency_code" example:"NGN"`
+}
+
+// StoreCreditAdjustParams represents store credit issued or taken back by staff
+type StoreCreditAdjustParams struct {
+	// Amount is added to the balance; negative amounts take from it
+	Amount  money.Money `json:"amount" swaggertype:"string" example:"20.00"`
+	Reason  string      `json:"reason" validate:"required,oneof=return goodwill correction" example:"return"`
+	OrderID *int64      `json:"order_id"`
+	Note    *string     `json:"note"`
+}
+
+// NewGiftCard converts a stored gift card to its API representation
+func NewGiftCard(card db.GiftCard) (GiftCard, error) {
+	initial, err := money.FromNumeric(card.InitialAmount, card.CurrencyCode)
+	if err != nil {
+		return GiftCard{}, err
+	}
+	balance, err := money.FromNumeric(card.Balance, card.CurrencyCode)
+	if err != nil {
+		return GiftCard{}, err
+	}
+	response := GiftCard{
+		ID:             card.GiftCardID,
+		Code:           card.Code,
+		InitialAmount:  initial,
+		Balance:        balance,
+		CurrencyCode:   card.CurrencyCode,
+		Disabled:       card.Disabled,
+		Note:           card.Note,
+		RecipientEmail: card.RecipientEmail,
+		OrderID:        card.OrderID,
+		CreatedBy:      card.CreatedBy,
+		CreatedAt:      card.CreatedAt.Time,
+		UpdatedAt:      card.UpdatedAt.Time,
+	}
+	if card.ExpiresAt.Valid {
+		response.ExpiresAt = &card.ExpiresAt.Time
+	}
+	if card.ShopCustomerID.Valid {
+		customerID := uuid.UUID(card.ShopCustomerID.Bytes)
+		response.CustomerID = &customerID
+	}
+	return response, nil
+}
+
+// NewGiftCardTransaction converts a gift card ledger entry to its API representation
+func NewGiftCardTransaction(entry db.GiftCardTransaction, currencyCode string) (CreditTransaction, error) {
+	amount, err := money.FromNumeric(entry.Amount, currencyCode)
+	if err != nil {
+		return CreditTransaction{}, err
+	}
+	balance, err := money.FromNumeric(entry.BalanceAfter, currencyCode)
+	if err != nil {
+		return CreditTransaction{}, err
+	}
+	return CreditTransaction{
+		ID:           entry.TransactionID,
+		Kind:         string(entry.Kind),
+		Amount:       amount,
+		BalanceAfter: balance,
+		OrderID:      entry.OrderID,
+		Note:         entry.Note,
+		CreatedBy:    entry.CreatedBy,
+		CreatedAt:    entry.CreatedAt.Time,
+	}, nil
+}
+
+// NewStoreCreditTransaction converts a store credit ledger entry to its API representation
+func NewStoreCreditTransaction(entry db.StoreCreditTransaction, currencyCode string) (CreditTransaction, error) {
+	amount, err := money.FromNumeric(entry.Amount, currencyCode)
+	if err != nil {
+		return CreditTransaction{}, err
+	}
+	balance, err := money.FromNumeric(entry.BalanceAfter, currencyCode)
+	if err != nil {
+		return CreditTransaction{}, err
+	}
+	return CreditTransaction{
+		ID:           entry.TransactionID,
+		Kind:         string(entry.Kind),
+		Amount:       amount,
+		BalanceAfter: balance,
+		Reason:       entry.Reason,
+		OrderID:      entry.OrderID,
+		Note:         entry.Note,
+		CreatedBy:    entry.CreatedBy,
+		CreatedAt:    entry.CreatedAt.Time,
+	}, nil
+}
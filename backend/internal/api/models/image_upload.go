@@ -0,0 +1,23 @@
+package models
+
+// ImageRendition is a resized copy of an uploaded image
+type ImageRendition struct {
+	Format    string `json:"format" example:"webp"`
+	Width     int    `json:"width" example:"640"`
+	Height    int    `json:"height" example:"480"`
+	URL       string `json:"url"`
+	SizeBytes int64  `json:"size_bytes"`
+}
+
+// ImageUploadResponse describes a stored image and its renditions
+type ImageUploadResponse struct {
+	ID          int64            `json:"id,omitempty"`
+	URL         string           `json:"url"`
+	Alt         string           `json:"alt,omitempty"`
+	Slot        string           `json:"slot,omitempty" example:"logo"`
+	ContentType string           `json:"content_type" example:"image/jpeg"`
+	Width       int              `json:"width"`
+	Height      int              `json:"height"`
+	SizeBytes   int64            `json:"size_bytes"`
+	Renditions  []ImageRendition `json:"renditions"`
+}
@@ -18,10 +18,13 @@ type LowStockVariantResponse struct {
 
 // UpdateStockParams represents parameters for updating stock
 type UpdateStockParams struct {
-	Quantity     int32    `json:"quantity" validate:"required,min=0"`
-	MovementType string   `json:"movement_type" validate:"required,oneof=adjustment purchase damage transfer"`
-	Reason       *string  `json:"reason,omitempty" validate:"omitempty,min=3,max=255"`
-	CostPrice    *float64 `json:"cost_price,omitempty" validate:"omitempty,min=0"`
+	Quantity     int32   `json:"quantity" validate:"required,min=0"`
+	MovementType string  `json:"movement_type" validate:"required,oneof=adjustment purchase damage transfer"`
+	Reason       *string `json:"reason,omitempty" validate:"omitempty,min=3,max=255"`
+	// CostPrice replaces the cost price of the variant, which purchase order receipts otherwise maintain
+	CostPrice *float64 `json:"cost_price,omitempty" validate:"omitempty,min=0"`
+	// LocationID is the location whose stock is set; the default location when empty
+	LocationID *int64 `json:"location_id,omitempty"`
 }
 
 // AddStockParams represents parameters for adding stock
@@ -30,6 +33,9 @@ type AddStockParams struct {
 	Reason        string  `json:"reason" validate:"required,min=3,max=255"`
 	ReferenceType string  `json:"reference_type" validate:"required"`
 	ReferenceID   *string `json:"reference_id"`
+	// LocationID is the location receiving the stock; the default location when empty
+	LocationID *int64 `json:"location_id,omitempty"`
+	StockLotParams
 }
 
 // DeductStockParams represents parameters for deducting stock
@@ -38,12 +44,19 @@ type DeductStockParams struct {
 	Reason        string  `json:"reason" validate:"required,min=3,max=255"`
 	ReferenceType string  `json:"reference_type" validate:"required"`
 	ReferenceID   *string `json:"reference_id"`
+	// LocationID is the location the stock is taken from; the default location when empty
+	LocationID *int64 `json:"location_id,omitempty"`
 }
 
 // VariantStockResponse represents the response after stock operations
 type VariantStockResponse struct {
-	VariantID int64     `json:"variant_id"`
-	Stock     int32     `json:"stock"`
+	VariantID int64 `json:"variant_id"`
+	// Stock is the total at the locations that sell online
+	Stock         int32 `json:"stock"`
+	LocationID    int64 `json:"location_id"`
+	LocationStock int32 `json:"location_stock"`
+	// CostPrice is what a unit costs the shop, averaged over purchase order receipts
+	CostPrice *float64  `json:"cost_price"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
@@ -77,6 +90,7 @@ type InventoryItemResponse struct {
 type StockMovementResponse struct {
 	MovementID    int64     `json:"movement_id"`
 	VariantID     int64     `json:"variant_id"`
+	LocationID    *int64    `json:"location_id"`
 	MovementType  string    `json:"movement_type"`
 	Quantity      int32     `json:"quantity"`
 	PreviousStock *int32    `json:"previous_stock"`
@@ -0,0 +1,105 @@
+package models
+
+import (
+	"time"
+
+	"github.com/petrejonn/naytife/internal/db"
+)
+
+// StockLotParams names the lot stock is received into. Product types that track
+// lots need one; other types take none.
+type StockLotParams struct {
+	LotNumber *string `json:"lot_number,omitempty" validate:"required_with=ExpiresOn,omitempty,max=100" example:"LOT-2025-08"`
+	// ExpiresOn is the date the lot expires, as YYYY-MM-DD
+	ExpiresOn *string `json:"expires_on,omitempty" validate:"omitempty,datetime=2006-01-02" example:"2026-03-31"`
+}
+
+// WriteOffLotParams represents the request body for writing off stock of a lot
+type WriteOffLotParams struct {
+	// Quantity is the number of units written off; everything left in the lot when empty
+	Quantity *int64  `json:"quantity" validate:"omitempty,min=1"`
+	Reason   *string `json:"reason" validate:"omitempty,max=255"`
+}
+
+// WriteOffExpiredLotsParams represents the request body for writing off every
+// expired lot of a shop
+type WriteOffExpiredLotsParams struct {
+	// LocationID limits the write-off to the lots at a location
+	LocationID *int64  `json:"location_id"`
+	Reason     *string `json:"reason" validate:"omitempty,max=255"`
+}
+
+// InventoryLot is stock of a variant at a location received under a lot number
+type InventoryLot struct {
+	ID           int64     `json:"id"`
+	VariantID    int64     `json:"variant_id"`
+	LocationID   int64     `json:"location_id"`
+	LocationName string    `json:"location_name"`
+	LotNumber    string    `json:"lot_number"`
+	ExpiresOn    *string   `json:"expires_on" example:"2026-03-31"`
+	Quantity     int64     `json:"quantity"`
+	ReceivedAt   time.Time `json:"received_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// ExpiringLotResponse represents a lot with stock that expires soon or has expired
+type ExpiringLotResponse struct {
+	LotID        int64  `json:"lot_id"`
+	VariantID    int64  `json:"variant_id"`
+	ProductID    int64  `json:"product_id"`
+	ProductTitle string `json:"product_title"`
+	VariantTitle string `json:"variant_title"`
+	SKU          string `json:"sku"`
+	LocationID   int64  `json:"location_id"`
+	LocationName string `json:"location_name"`
+	LotNumber    string `json:"lot_number"`
+	ExpiresOn    string `json:"expires_on" example:"2026-03-31"`
+	// DaysUntilExpiry is negative for lots that have expired
+	DaysUntilExpiry int32 `json:"days_until_expiry"`
+	Quantity        int64 `json:"quantity"`
+	// CostValue is the quantity at cost price, when the variant has one
+	CostValue *float64 `json:"cost_value"`
+}
+
+// NewInventoryLot converts a stored lot and the name of its location to their API
+// representation
+func NewInventoryLot(lot db.ListVariantLotsRow) InventoryLot {
+	response := InventoryLot{
+		ID:           lot.LotID,
+		VariantID:    lot.ProductVariationID,
+		LocationID:   lot.LocationID,
+		LocationName: lot.LocationName,
+		LotNumber:    lot.LotNumber,
+		Quantity:     lot.Quantity,
+		ReceivedAt:   lot.ReceivedAt.Time,
+		UpdatedAt:    lot.UpdatedAt.Time,
+	}
+	if lot.ExpiresOn.Valid {
+		expiresOn := lot.ExpiresOn.Time.Format(time.DateOnly)
+		response.ExpiresOn = &expiresOn
+	}
+	return response
+}
+
+// NewExpiringLot converts a lot of the expiring stock report to its API representation
+func NewExpiringLot(lot db.ListExpiringLotsRow) ExpiringLotResponse {
+	response := ExpiringLotResponse{
+		LotID:           lot.LotID,
+		VariantID:       lot.ProductVariationID,
+		ProductID:       lot.ProductID,
+		ProductTitle:    lot.ProductTitle,
+		VariantTitle:    lot.VariantTitle,
+		SKU:             lot.Sku,
+		LocationID:      lot.LocationID,
+		LocationName:    lot.LocationName,
+		LotNumber:       lot.LotNumber,
+		ExpiresOn:       lot.ExpiresOn.Time.Format(time.DateOnly),
+		DaysUntilExpiry: lot.DaysUntilExpiry,
+		Quantity:        lot.Quantity,
+	}
+	if lot.CostPrice.Valid {
+		costValue := NumericToFloat64(lot.CostPrice) * float64(lot.Quantity)
+		response.CostValue = &costValue
+	}
+	return response
+}
@@ -0,0 +1,86 @@
+package models
+
+import (
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/petrejonn/naytife/internal/db"
+)
+
+// InventoryPolicyParams represents the request body for setting what happens when
+// a variant is ordered beyond its stock
+type InventoryPolicyParams struct {
+	// Policy is deny to refuse the order, backorder to take it and ship once restocked,
+	// or preorder to take it ahead of the variant's release
+	Policy string `json:"policy" validate:"required,oneof=deny backorder preorder" example:"preorder"`
+	// PreorderShipsOn is the date preorders are expected to ship, as YYYY-MM-DD
+	PreorderShipsOn *string `json:"preorder_ships_on" validate:"omitempty,datetime=2006-01-02" example:"2025-11-01"`
+	// PreorderLimit caps the units that can be preordered; no cap when empty
+	PreorderLimit *int64 `json:"preorder_limit" validate:"omitempty,min=0"`
+}
+
+// VariantInventoryPolicy is what happens when a variant is ordered beyond its stock
+type VariantInventoryPolicy struct {
+	VariantID       int64   `json:"variant_id"`
+	Policy          string  `json:"policy" example:"preorder"`
+	PreorderShipsOn *string `json:"preorder_ships_on" example:"2025-11-01"`
+	PreorderLimit   *int64  `json:"preorder_limit"`
+	// BackorderedQuantity is the units ordered beyond stock that are waiting to be fulfilled
+	BackorderedQuantity int64 `json:"backordered_quantity"`
+}
+
+// BackorderedOrderItem is an order line taken beyond stock that is waiting to be fulfilled
+type BackorderedOrderItem struct {
+	OrderItemID  int64  `json:"order_item_id"`
+	OrderID      int64  `json:"order_id"`
+	VariantID    int64  `json:"variant_id"`
+	ProductTitle string `json:"product_title"`
+	VariantTitle string `json:"variant_title"`
+	SKU          string `json:"sku"`
+	Quantity     int64  `json:"quantity"`
+	// BackorderedQuantity is the part of the quantity that was not in stock when ordered
+	BackorderedQuantity int64     `json:"backordered_quantity"`
+	AvailableQuantity   int64     `json:"available_quantity"`
+	Policy              string    `json:"policy" example:"backorder"`
+	PreorderShipsOn     *string   `json:"preorder_ships_on" example:"2025-11-01"`
+	OrderedAt           time.Time `json:"ordered_at"`
+}
+
+// NewVariantInventoryPolicy converts the inventory policy of a stored variant and its
+// outstanding backorders to their API representation
+func NewVariantInventoryPolicy(variant db.ProductVariation, backordered int64) VariantInventoryPolicy {
+	return VariantInventoryPolicy{
+		VariantID:           variant.ProductVariationID,
+		Policy:              string(variant.InventoryPolicy),
+		PreorderShipsOn:     dateString(variant.PreorderShipsOn),
+		PreorderLimit:       variant.PreorderLimit,
+		BackorderedQuantity: backordered,
+	}
+}
+
+// NewBackorderedOrderItem converts a backordered order line to its API representation
+func NewBackorderedOrderItem(item db.ListBackorderedOrderItemsRow) BackorderedOrderItem {
+	return BackorderedOrderItem{
+		OrderItemID:         item.OrderItemID,
+		OrderID:             item.OrderID,
+		VariantID:           item.ProductVariationID,
+		ProductTitle:        item.ProductTitle,
+		VariantTitle:        item.VariantTitle,
+		SKU:                 item.Sku,
+		Quantity:            item.Quantity,
+		BackorderedQuantity: item.BackorderedQuantity,
+		AvailableQuantity:   item.AvailableQuantity,
+		Policy:              string(item.InventoryPolicy),
+		PreorderShipsOn:     dateString(item.PreorderShipsOn),
+		OrderedAt:           item.OrderedAt.Time,
+	}
+}
+
+// dateString formats a stored date as YYYY-MM-DD, or returns nil when it is not set
+func dateString(date pgtype.Date) *string {
+	if !date.Valid {
+		return nil
+	}
+	formatted := date.Time.Format(time.DateOnly)
+	return &formatted
+}
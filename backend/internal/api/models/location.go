@@ -0,0 +1,150 @@
+package models
+
+import (
+	"time"
+
+	"github.com/petrejonn/naytife/internal/db"
+)
+
+// Location represents a place that holds stock, e.g. a shop floor or a warehouse
+type Location struct {
+	ID      int64   `json:"id"`
+	Name    string  `json:"name" example:"Lekki warehouse"`
+	Address *string `json:"address"`
+	// SellsOnline is whether stock here counts towards the storefront's available quantity
+	SellsOnline bool      `json:"sells_online"`
+	IsDefault   bool      `json:"is_default"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// LocationParams represents the request body for creating or updating a location
+type LocationParams struct {
+	Name        string  `json:"name" validate:"required,max=100" example:"Lekki warehouse"`
+	Address     *string `json:"address" validate:"omitempty,max=500"`
+	SellsOnline *bool   `json:"sells_online"`
+	// IsDefault makes this the location that receives stock set without a location
+	IsDefault bool `json:"is_default"`
+}
+
+// VariantInventoryLevel is the stock of a variant at one location
+type VariantInventoryLevel struct {
+	LocationID   int64  `json:"location_id"`
+	LocationName string `json:"location_name"`
+	SellsOnline  bool   `json:"sells_online"`
+	Available    int64  `json:"available"`
+	// Incoming is stock in transit to the location
+	Incoming int64 `json:"incoming"`
+}
+
+// LocationInventoryLevel is the stock of one variant held at a location
+type LocationInventoryLevel struct {
+	VariantID    int64     `json:"variant_id"`
+	ProductTitle string    `json:"product_title"`
+	VariantTitle string    `json:"variant_title"`
+	SKU          string    `json:"sku"`
+	Available    int64     `json:"available"`
+	Incoming     int64     `json:"incoming"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// InventoryTransferItemParams is a quantity of a variant to move
+type InventoryTransferItemParams struct {
+	VariantID int64 `json:"variant_id" validate:"required"`
+	Quantity  int64 `json:"quantity" validate:"required,min=1"`
+}
+
+// InventoryTransferParams represents the request body for moving stock between locations
+type InventoryTransferParams struct {
+	OriginLocationID      int64                         `json:"origin_location_id" validate:"required"`
+	DestinationLocationID int64                         `json:"destination_location_id" validate:"required,nefield=OriginLocationID"`
+	Note                  *string                       `json:"note" validate:"omitempty,max=255"`
+	Items                 []InventoryTransferItemParams `json:"items" validate:"required,min=1,unique=VariantID,dive"`
+}
+
+// InventoryTransferItem is a quantity of a variant moved by a transfer
+type InventoryTransferItem struct {
+	VariantID    int64  `json:"variant_id"`
+	ProductTitle string `json:"product_title"`
+	VariantTitle string `json:"variant_title"`
+	SKU          string `json:"sku"`
+	Quantity     int64  `json:"quantity"`
+}
+
+// InventoryTransfer represents stock moving between two locations. Its items count
+// as incoming at the destination while it is in transit.
+type InventoryTransfer struct {
+	ID                    int64                   `json:"id"`
+	OriginLocationID      int64                   `json:"origin_location_id"`
+	DestinationLocationID int64                   `json:"destination_location_id"`
+	Status                string                  `json:"status" example:"in_transit"`
+	Note                  *string                 `json:"note"`
+	CreatedBy             *string                 `json:"created_by"`
+	CreatedAt             time.Time               `json:"created_at"`
+	ReceivedAt            *time.Time              `json:"received_at"`
+	Items                 []InventoryTransferItem `json:"items,omitempty"`
+}
+
+// FulfillOrderParams represents the request body for fulfilling an order
+type FulfillOrderParams struct {
+	// LocationID is the location the order ships from; when empty the first location
+	// that sells online and holds every item is picked, starting with the default
+	LocationID *int64 `json:"location_id"`
+}
+
+// OrderFulfillment records the location an order's stock was taken from
+type OrderFulfillment struct {
+	OrderID    int64     `json:"order_id"`
+	LocationID *int64    `json:"location_id"`
+	CreatedBy  *string   `json:"created_by"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// NewLocation converts a stored location to its API representation
+func NewLocation(location db.Location) Location {
+	return Location{
+		ID:          location.LocationID,
+		Name:        location.Name,
+		Address:     location.Address,
+		SellsOnline: location.SellsOnline,
+		IsDefault:   location.IsDefault,
+		CreatedAt:   location.CreatedAt.Time,
+		UpdatedAt:   location.UpdatedAt.Time,
+	}
+}
+
+// NewInventoryTransfer converts a stored transfer and its items to their API representation
+func NewInventoryTransfer(transfer db.InventoryTransfer, items []db.ListInventoryTransferItemsRow) InventoryTransfer {
+	response := InventoryTransfer{
+		ID:                    transfer.TransferID,
+		OriginLocationID:      transfer.OriginLocationID,
+		DestinationLocationID: transfer.DestinationLocationID,
+		Status:                string(transfer.Status),
+		Note:                  transfer.Note,
+		CreatedBy:             transfer.CreatedBy,
+		CreatedAt:             transfer.CreatedAt.Time,
+	}
+	if transfer.ReceivedAt.Valid {
+		response.ReceivedAt = &transfer.ReceivedAt.Time
+	}
+	for _, item := range items {
+		response.Items = append(response.Items, InventoryTransferItem{
+			VariantID:    item.ProductVariationID,
+			ProductTitle: item.ProductTitle,
+			VariantTitle: item.VariantTitle,
+			SKU:          item.Sku,
+			Quantity:     item.Quantity,
+		})
+	}
+	return response
+}
+
+// NewOrderFulfillment converts a stored fulfillment to its API representation
+func NewOrderFulfillment(fulfillment db.OrderFulfillment) OrderFulfillment {
+	return OrderFulfillment{
+		OrderID:    fulfillment.OrderID,
+		LocationID: fulfillment.LocationID,
+		CreatedBy:  fulfillment.CreatedBy,
+		CreatedAt:  fulfillment.CreatedAt.Time,
+	}
+}
@@ -0,0 +1,91 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/petrejonn/naytife/internal/db"
+	"github.com/petrejonn/naytife/internal/money"
+)
+
+// LoyaltyProgram represents the loyalty points settings of a shop
+type LoyaltyProgram struct {
+	Enabled bool `json:"enabled"`
+	// EarnRate is the points earned per shop currency unit spent on merchandise
+	EarnRate float64 `json:"earn_rate" example:"1"`
+	// BurnRate is the points redeemed per shop currency unit of discount
+	BurnRate        float64 `json:"burn_rate" example:"100"`
+	MinRedeemPoints int32   `json:"min_redeem_points" example:"500"`
+	// ExpiryDays is how long points last without being earned or redeemed; empty
+	// when they never expire
+	ExpiryDays *int32     `json:"expiry_days" example:"365"`
+	UpdatedAt  *time.Time `json:"updated_at,omitempty"`
+}
+
+// LoyaltyProgramParams represents the request body for setting up a loyalty program
+type LoyaltyProgramParams struct {
+	Enabled         bool    `json:"enabled"`
+	EarnRate        float64 `json:"earn_rate" validate:"gte=0" example:"1"`
+	BurnRate        float64 `json:"burn_rate" validate:"gt=0" example:"100"`
+	MinRedeemPoints int32   `json:"min_redeem_points" validate:"gte=0" example:"500"`
+	ExpiryDays      *int32  `json:"expiry_days" validate:"omitempty,gt=0" example:"365"`
+}
+
+// LoyaltyBalance is the points balance of a customer
+type LoyaltyBalance struct {
+	CustomerID uuid.UUID `json:"customer_id"`
+	Points     int32     `json:"points" example:"1250"`
+	// Value is what the points are worth as a discount, in the shop currency
+	Value        money.Money `json:"value" swaggertype:"string" example:"12.50"`
+	CurrencyCode string      `json:"currency_code" example:"NGN"`
+	ExpiresAt    *time.Time  `json:"expires_at"`
+}
+
+// LoyaltyAdjustParams represents points given or taken by staff
+type LoyaltyAdjustParams struct {
+	// Points are added to the balance; negative points take from it
+	Points int32  `json:"points" validate:"required" example:"200"`
+	Note   string `json:"note" validate:"required" example:"Apology for late delivery"`
+}
+
+// LoyaltyTransaction is an entry of a customer's points ledger. Points are signed:
+// earn and refund add to the balance, redeem, clawback and expire take from it.
+type LoyaltyTransaction struct {
+	ID           int64     `json:"id"`
+	Kind         string    `json:"kind" example:"earn"`
+	Points       int32     `json:"points" example:"120"`
+	BalanceAfter int32     `json:"balance_after" example:"1250"`
+	OrderID      *int64    `json:"order_id"`
+	Note         *string   `json:"note"`
+	CreatedBy    *string   `json:"created_by"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// NewLoyaltyProgram converts a stored loyalty program to its API representation
+func NewLoyaltyProgram(program db.LoyaltyProgram) LoyaltyProgram {
+	response := LoyaltyProgram{
+		Enabled:         program.Enabled,
+		EarnRate:        NumericToFloat64(program.EarnRate),
+		BurnRate:        NumericToFloat64(program.BurnRate),
+		MinRedeemPoints: program.MinRedeemPoints,
+		ExpiryDays:      program.ExpiryDays,
+	}
+	if program.UpdatedAt.Valid {
+		response.UpdatedAt = &program.UpdatedAt.Time
+	}
+	return response
+}
+
+// NewLoyaltyTransaction converts a points ledger entry to its API representation
+func NewLoyaltyTransaction(entry db.LoyaltyTransaction) LoyaltyTransaction {
+	return LoyaltyTransaction{
+		ID:           entry.TransactionID,
+		Kind:         string(entry.Kind),
+		Points:       entry.Points,
+		BalanceAfter: entry.BalanceAfter,
+		OrderID:      entry.OrderID,
+		Note:         entry.Note,
+		CreatedBy:    entry.CreatedBy,
+		CreatedAt:    entry.CreatedAt.Time,
+	}
+}
@@ -0,0 +1,65 @@
+package models
+
+import (
+	"time"
+
+	"github.com/petrejonn/naytife/internal/db"
+)
+
+type MetafieldDefinition struct {
+	ID          int64                 `json:"metafield_definition_id"`
+	OwnerType   db.MetafieldOwnerType `json:"owner_type" example:"product"`
+	Namespace   string                `json:"namespace" example:"custom"`
+	Key         string                `json:"key" example:"care_instructions"`
+	Name        string                `json:"name" example:"Care instructions"`
+	Description *string               `json:"description"`
+	Type        db.MetafieldType      `json:"type" example:"multi_line_text"`
+	Validations MetafieldValidations  `json:"validations"`
+	CreatedAt   time.Time             `json:"created_at"`
+	UpdatedAt   time.Time             `json:"updated_at"`
+}
+
+// MetafieldValidations constrains the values accepted for a defined metafield.
+// Min and Max apply to integer and decimal values, MaxLength and Pattern to text values.
+type MetafieldValidations struct {
+	Min       *float64 `json:"min,omitempty"`
+	Max       *float64 `json:"max,omitempty"`
+	MaxLength *int     `json:"max_length,omitempty" validate:"omitempty,gt=0"`
+	Pattern   *string  `json:"pattern,omitempty" example:"^[A-Z]{3}$"`
+	Choices   []string `json:"choices,omitempty"`
+}
+
+type MetafieldDefinitionCreateParams struct {
+	OwnerType   db.MetafieldOwnerType `json:"owner_type" validate:"required,oneof=product product_variation category shop customer" example:"product"`
+	Namespace   string                `json:"namespace" validate:"required,min=2,max=64" example:"custom"`
+	Key         string                `json:"key" validate:"required,min=1,max=64" example:"care_instructions"`
+	Name        string                `json:"name" validate:"required,max=255" example:"Care instructions"`
+	Description *string               `json:"description,omitempty"`
+	Type        db.MetafieldType      `json:"type" validate:"required,oneof=single_line_text multi_line_text integer decimal boolean date date_time json url color" example:"multi_line_text"`
+	Validations *MetafieldValidations `json:"validations,omitempty"`
+}
+
+type MetafieldDefinitionUpdateParams struct {
+	Name        *string               `json:"name,omitempty" validate:"omitempty,max=255"`
+	Description *string               `json:"description,omitempty"`
+	Validations *MetafieldValidations `json:"validations,omitempty"`
+}
+
+type Metafield struct {
+	ID        int64            `json:"metafield_id"`
+	Namespace string           `json:"namespace" example:"custom"`
+	Key       string           `json:"key" example:"care_instructions"`
+	Type      db.MetafieldType `json:"type" example:"multi_line_text"`
+	Value     string           `json:"value" example:"Hand wash only"`
+	CreatedAt time.Time        `json:"created_at"`
+	UpdatedAt time.Time        `json:"updated_at"`
+}
+
+// MetafieldSetParams sets a metafield value. Type may be omitted when a
+// definition exists for the namespace and key.
+type MetafieldSetParams struct {
+	Namespace string           `json:"namespace" validate:"required,min=2,max=64" example:"custom"`
+	Key       string           `json:"key" validate:"required,min=1,max=64" example:"care_instructions"`
+	Type      db.MetafieldType `json:"type,omitempty" validate:"omitempty,oneof=single_line_text multi_line_text integer decimal boolean date date_time json url color" example:"multi_line_text"`
+	Value     string           `json:"value" validate:"required" example:"Hand wash only"`
+}
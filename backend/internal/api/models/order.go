@@ -4,6 +4,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/petrejonn/naytife/internal/db"
+	"github.com/petrejonn/naytife/internal/money"
 )
 
 // Order represents the API order response
@@ -13,11 +14,12 @@ type Order struct {
 	CreatedAt       pgtype.Timestamptz    `json:"created_at" swaggertype:"primitive,string" format:"date-time" example:"2025-02-09T09:38:25Z"`
 	UpdatedAt       pgtype.Timestamptz    `json:"updated_at" swaggertype:"primitive,string" format:"date-time" example:"2025-02-09T09:38:25Z"`
 	CustomerID      uuid.UUID             `json:"customer_id"`
-	Amount          pgtype.Numeric        `json:"amount" swaggertype:"primitive,number"`
-	Discount        pgtype.Numeric        `json:"discount" swaggertype:"primitive,number"`
-	ShippingCost    pgtype.Numeric        `json:"shipping_cost" swaggertype:"primitive,number"`
-	Tax             pgtype.Numeric        `json:"tax" swaggertype:"primitive,number"`
-	ShippingAddress string                `json:"shipping_address"`
+	Amount          money.Money           `json:"amount" swaggertype:"string" example:"12500.00"`
+	Discount        money.Money           `json:"discount" swaggertype:"string" example:"0.00"`
+	ShippingCost    money.Money           `json:"shipping_cost" swaggertype:"string" example:"1500.00"`
+	Tax             money.Money           `json:"tax" swaggertype:"string" example:"0.00"`
+	CurrencyCode    string                `json:"currency_code" example:"NGN"`
+	ShippingAddress ShippingAddress       `json:"shipping_address"`
 	PaymentMethod   db.PaymentMethodType  `json:"payment_method"`
 	PaymentStatus   db.PaymentStatusType  `json:"payment_status"`
 	ShippingMethod  string                `json:"shipping_method"`
@@ -30,18 +32,80 @@ type Order struct {
 	CustomerName  string  `json:"customer_name"`
 	CustomerEmail *string `json:"customer_email,omitempty"`
 	CustomerPhone *string `json:"customer_phone,omitempty"`
+	// Presentment is set when the order was charged in a presentment currency
+	Presentment *OrderPresentment `json:"presentment,omitempty"`
 }
 
 // OrderItem represents the API order item response
 type OrderItem struct {
 	ID                 int64              `json:"order_item_id"`
 	Quantity           int64              `json:"quantity"`
-	Price              pgtype.Numeric     `json:"price" swaggertype:"primitive,number"`
+	Price              money.Money        `json:"price" swaggertype:"string" example:"2500.00"`
 	CreatedAt          pgtype.Timestamptz `json:"created_at" swaggertype:"primitive,string" format:"date-time" example:"2025-02-09T09:38:25Z"`
 	UpdatedAt          pgtype.Timestamptz `json:"updated_at" swaggertype:"primitive,string" format:"date-time" example:"2025-02-09T09:38:25Z"`
 	ProductVariationID int64              `json:"product_variation_id"`
 	OrderID            int64              `json:"order_id"`
 	ShopID             int64              `json:"shop_id"`
+	// BackorderedQuantity is the units ordered beyond the stock of the variant,
+	// shipped once it is restocked
+	BackorderedQuantity int64 `json:"backordered_quantity"`
+}
+
+// NewOrder maps an order and its items to the API model, reading amounts in the
+// shop currency
+func NewOrder(order db.Order, items []db.OrderItem, currencyCode string) (Order, error) {
+	amounts := make([]money.Money, 4)
+	for i, n := range []pgtype.Numeric{order.Amount, order.Discount, order.ShippingCost, order.Tax} {
+		amount, err := money.FromNumeric(n, currencyCode)
+		if err != nil {
+			return Order{}, err
+		}
+		amounts[i] = amount
+	}
+
+	var orderItems []OrderItem
+	for _, item := range items {
+		price, err := money.FromNumeric(item.Price, currencyCode)
+		if err != nil {
+			return Order{}, err
+		}
+		orderItems = append(orderItems, OrderItem{
+			ID:                  item.OrderItemID,
+			Quantity:            item.Quantity,
+			Price:               price,
+			CreatedAt:           item.CreatedAt,
+			UpdatedAt:           item.UpdatedAt,
+			ProductVariationID:  item.ProductVariationID,
+			OrderID:             item.OrderID,
+			ShopID:              item.ShopID,
+			BackorderedQuantity: item.BackorderedQuantity,
+		})
+	}
+
+	return Order{
+		ID:              order.OrderID,
+		Status:          order.Status,
+		CreatedAt:       order.CreatedAt,
+		UpdatedAt:       order.UpdatedAt,
+		CustomerID:      order.ShopCustomerID.Bytes,
+		Amount:          amounts[0],
+		Discount:        amounts[1],
+		ShippingCost:    amounts[2],
+		Tax:             amounts[3],
+		CurrencyCode:    currencyCode,
+		ShippingAddress: ShippingAddressFromSnapshot(order.ShippingAddress),
+		PaymentMethod:   order.PaymentMethod,
+		PaymentStatus:   order.PaymentStatus,
+		ShippingMethod:  order.ShippingMethod,
+		ShippingStatus:  order.ShippingStatus,
+		TransactionID:   order.TransactionID,
+		Username:        order.Username,
+		ShopID:          order.ShopID,
+		Items:           orderItems,
+		CustomerName:    order.CustomerName,
+		CustomerEmail:   order.CustomerEmail,
+		CustomerPhone:   order.CustomerPhone,
+	}, nil
 }
 
 // CreateOrderParams represents the request body for creating an order
@@ -51,7 +115,7 @@ type CreateOrderParams struct {
 	Discount        pgtype.Numeric          `json:"discount" swaggertype:"primitive,number"`
 	ShippingCost    pgtype.Numeric          `json:"shipping_cost" swaggertype:"primitive,number"`
 	Tax             pgtype.Numeric          `json:"tax" swaggertype:"primitive,number"`
-	ShippingAddress string                  `json:"shipping_address" validate:"required"`
+	ShippingAddress ShippingAddress         `json:"shipping_address" validate:"required"`
 	PaymentMethod   db.PaymentMethodType    `json:"payment_method" validate:"required"`
 	PaymentStatus   db.PaymentStatusType    `json:"payment_status" validate:"required"`
 	ShippingMethod  string                  `json:"shipping_method" validate:"required"`
@@ -75,7 +139,7 @@ type UpdateOrderParams struct {
 	Discount        pgtype.Numeric        `json:"discount" swaggertype:"primitive,number"`
 	ShippingCost    pgtype.Numeric        `json:"shipping_cost" swaggertype:"primitive,number"`
 	Tax             pgtype.Numeric        `json:"tax" swaggertype:"primitive,number"`
-	ShippingAddress string                `json:"shipping_address" validate:"required"`
+	ShippingAddress ShippingAddress       `json:"shipping_address" validate:"required"`
 	PaymentMethod   db.PaymentMethodType  `json:"payment_method" validate:"required"`
 	PaymentStatus   db.PaymentStatusType  `json:"payment_status" validate:"required"`
 	ShippingMethod  string                `json:"shipping_method" validate:"required"`
@@ -99,19 +163,20 @@ type CreateOrderRequest struct {
 	CustomerName    string                   `json:"customer_name" validate:"required"`
 	CustomerEmail   *string                  `json:"customer_email,omitempty"`
 	CustomerPhone   *string                  `json:"customer_phone,omitempty"`
-	ShippingAddress string                   `json:"shipping_address" validate:"required"`
+	ShippingAddress ShippingAddress          `json:"shipping_address" validate:"required"`
 	ShippingMethod  string                   `json:"shipping_method" validate:"required"`
 	PaymentMethod   string                   `json:"payment_method" validate:"required,oneof=flutterwave paystack paypal stripe"`
 	TransactionID   *string                  `json:"transaction_id,omitempty"`
-	Discount        float64                  `json:"discount,omitempty"`
-	ShippingCost    float64                  `json:"shipping_cost,omitempty"`
-	Tax             float64                  `json:"tax,omitempty"`
-	Items           []CreateOrderRequestItem `json:"items" validate:"required,min=1"`
+	Discount        money.Money              `json:"discount" swaggertype:"string" example:"0.00"`
+	ShippingCost    money.Money              `json:"shipping_cost" swaggertype:"string" example:"1500.00"`
+	Tax             money.Money              `json:"tax" swaggertype:"string" example:"0.00"`
+	Items           []CreateOrderRequestItem `json:"items" validate:"required,min=1,dive"`
 }
 
-// CreateOrderRequestItem represents an item in the order creation request
+// CreateOrderRequestItem represents an item in the order creation request. Price is a
+// decimal string in the shop currency.
 type CreateOrderRequestItem struct {
-	ProductVariationID string  `json:"product_variation_id" validate:"required"`
-	Quantity           int     `json:"quantity" validate:"required,min=1"`
-	Price              float64 `json:"price" validate:"required,min=0"`
+	ProductVariationID string      `json:"product_variation_id" validate:"required"`
+	Quantity           int         `json:"quantity" validate:"required,min=1"`
+	Price              money.Money `json:"price" validate:"required" swaggertype:"string" example:"2500.00"`
 }
@@ -2,6 +2,8 @@ package models
 
 import (
 	"time"
+
+	"github.com/petrejonn/naytife/internal/money"
 )
 
 // PaymentRequest represents a payment request from the client
@@ -17,7 +19,7 @@ type PaymentResponse struct {
 	PaymentID      string                 `json:"payment_id"`
 	OrderID        int64                  `json:"order_id"`
 	Status         string                 `json:"status"`
-	Amount         float64                `json:"amount"`
+	Amount         money.Money            `json:"amount" swaggertype:"string" example:"12.50"`
 	CurrencyCode   string                 `json:"currency_code"`
 	PaymentMethod  string                 `json:"payment_method"`
 	TransactionID  string                 `json:"transaction_id,omitempty"`
@@ -47,7 +49,7 @@ type PaymentWebhookPayload struct {
 	PaymentID     string                 `json:"payment_id"`
 	TransactionID string                 `json:"transaction_id,omitempty"`
 	Status        string                 `json:"status"`
-	Amount        float64                `json:"amount,omitempty"`
+	Amount        money.Money            `json:"amount" swaggertype:"string" example:"12.50"`
 	Currency      string                 `json:"currency,omitempty"`
 	Metadata      map[string]interface{} `json:"metadata,omitempty"`
 	RawPayload    interface{}            `json:"raw_payload,omitempty"`
@@ -56,7 +58,7 @@ type PaymentWebhookPayload struct {
 
 // PaymentIntentRequest represents a request to create a payment intent
 type PaymentIntentRequest struct {
-	Amount            float64                `json:"amount" validate:"required,min=0.01"`
+	Amount            money.Money            `json:"amount" swaggertype:"string" example:"12.50"`
 	CurrencyCode      string                 `json:"currency_code" validate:"required"`
 	PaymentMethod     string                 `json:"payment_method" validate:"required,oneof=stripe paypal paystack flutterwave cash_on_delivery"`
 	CheckoutSessionID string                 `json:"checkout_session_id" validate:"required"`
@@ -71,7 +73,36 @@ type PaymentIntentResponse struct {
 	PaymentIntentID string             `json:"payment_intent_id"`
 	ClientSecret    string             `json:"client_secret,omitempty"`
 	Status          string             `json:"status"`
-	Amount          float64            `json:"amount"`
+	Amount          money.Money        `json:"amount" swaggertype:"string" example:"12.50"`
 	CurrencyCode    string             `json:"currency_code"`
 	NextAction      *PaymentNextAction `json:"next_action,omitempty"`
 }
+
+// SavePaymentMethodRequest represents a payment method a customer saves for recurring charges.
+// Token is a Stripe payment method ID or the reference of a successful Paystack transaction.
+type SavePaymentMethodRequest struct {
+	Email string `json:"email"`
+	Name  string `json:"name,omitempty"`
+	Token string `json:"token"`
+}
+
+// SavedPaymentMethod represents a payment method the provider can charge without the customer present
+type SavedPaymentMethod struct {
+	CustomerReference string `json:"customer_reference"`
+	Token             string `json:"token"`
+	Brand             string `json:"brand,omitempty"`
+	Last4             string `json:"last4,omitempty"`
+	ExpMonth          int32  `json:"exp_month,omitempty"`
+	ExpYear           int32  `json:"exp_year,omitempty"`
+}
+
+// SavedPaymentChargeRequest represents a charge to a saved payment method.
+// Charges with the same IdempotencyKey are made once.
+type SavedPaymentChargeRequest struct {
+	Amount            money.Money       `json:"amount" swaggertype:"string" example:"12.50"`
+	CustomerReference string            `json:"customer_reference"`
+	Token             string            `json:"token"`
+	IdempotencyKey    string            `json:"idempotency_key"`
+	Description       string            `json:"description,omitempty"`
+	Metadata          map[string]string `json:"metadata,omitempty"`
+}
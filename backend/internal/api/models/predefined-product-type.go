@@ -8,6 +8,7 @@ type PredefinedProductType struct {
 	SkuSubstring string                        `json:"sku_substring"`
 	Shippable    bool                          `json:"shippable"`
 	Digital      bool                          `json:"digital"`
+	GiftCard     bool                          `json:"gift_card"`
 	Category     string                        `json:"category"`
 	Icon         string                        `json:"icon"`
 	Attributes   []PredefinedAttributeTemplate `json:"attributes"`
@@ -5,19 +5,37 @@ type ProductType struct {
 	Title        string  `json:"title"`
 	Shippable    bool    `json:"shippable"`
 	Digital      bool    `json:"digital"`
+	GiftCard     bool    `json:"gift_card"`
+	TracksLots   bool    `json:"tracks_lots"`
 	SkuSubstring *string `json:"sku_substring,omitempty"`
+	// ReorderPoint and ReorderQuantity apply to the type's variants that set none themselves
+	ReorderPoint    *int64 `json:"reorder_point"`
+	ReorderQuantity *int64 `json:"reorder_quantity"`
 }
 
 type ProductTypeCreateParams struct {
-	Title        string  `json:"title" example:"Book"`
-	Shippable    bool    `json:"shippable"`
-	Digital      bool    `json:"digital" example:"false"`
+	Title     string `json:"title" example:"Book"`
+	Shippable bool   `json:"shippable"`
+	Digital   bool   `json:"digital" example:"false"`
+	// GiftCard products issue a gift card worth their price once paid for; they are always digital
+	GiftCard bool `json:"gift_card" example:"false"`
+	// TracksLots products receive stock into lots with expiry dates, sold earliest expiry first
+	TracksLots   bool    `json:"tracks_lots" example:"false"`
 	SkuSubstring *string `json:"sku_substring,omitempty" example:"BK"`
+	// ReorderPoint is the stock at which the type's variants raise a low stock alert,
+	// unless they set their own
+	ReorderPoint *int64 `json:"reorder_point,omitempty" validate:"omitempty,min=0" example:"5"`
+	// ReorderQuantity is the number of units suggested for reordering
+	ReorderQuantity *int64 `json:"reorder_quantity,omitempty" validate:"omitempty,min=1" example:"20"`
 }
 
 type ProductTypeUpdateParams struct {
-	Title        *string `json:"title" example:"Book"`
-	Shippable    *bool   `json:"shippable"`
-	Digital      *bool   `json:"digital" example:"false"`
-	SkuSubstring *string `json:"sku_substring,omitempty" example:"BK"`
+	Title           *string `json:"title" example:"Book"`
+	Shippable       *bool   `json:"shippable"`
+	Digital         *bool   `json:"digital" example:"false"`
+	GiftCard        *bool   `json:"gift_card" example:"false"`
+	TracksLots      *bool   `json:"tracks_lots" example:"false"`
+	SkuSubstring    *string `json:"sku_substring,omitempty" example:"BK"`
+	ReorderPoint    *int64  `json:"reorder_point,omitempty" validate:"omitempty,min=0" example:"5"`
+	ReorderQuantity *int64  `json:"reorder_quantity,omitempty" validate:"omitempty,min=1" example:"20"`
 }
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"time"
+
+	"github.com/petrejonn/naytife/internal/db"
+)
+
+// ProductRevision represents a recorded version of a product
+type ProductRevision struct {
+	RevisionID          int64            `json:"revision_id"`
+	ProductID           int64            `json:"product_id"`
+	Version             int32            `json:"version"`
+	CreatedBy           *string          `json:"created_by"`
+	RestoredFromVersion *int32           `json:"restored_from_version,omitempty"`
+	CreatedAt           time.Time        `json:"created_at"`
+	Snapshot            *ProductSnapshot `json:"snapshot,omitempty"`
+}
+
+// ProductSnapshot is the full state of a product captured in a revision
+type ProductSnapshot struct {
+	Product    db.Product                 `json:"product"`
+	Attributes []db.ProductAttributeValue `json:"attributes"`
+	Variants   []ProductVariantSnapshot   `json:"variants"`
+	Images     []db.ProductImage          `json:"images"`
+}
+
+// ProductVariantSnapshot is a variant row together with its attribute values
+type ProductVariantSnapshot struct {
+	db.ProductVariation
+	Attributes []db.ProductVariationAttributeValue `json:"attributes"`
+}
+
+// ProductRevisionDiff lists the changes between two revisions of a product
+type ProductRevisionDiff struct {
+	ProductID   int64                   `json:"product_id"`
+	FromVersion int32                   `json:"from_version"`
+	ToVersion   int32                   `json:"to_version"`
+	Changes     []ProductRevisionChange `json:"changes"`
+}
+
+// ProductRevisionChange describes a single added, removed or modified value.
+// Path identifies the value, e.g. "product.title", "variants[12].price" or "images[4]".
+type ProductRevisionChange struct {
+	Path   string      `json:"path" example:"variants[12].price"`
+	Change string      `json:"change" example:"modified"` // added, removed, modified
+	From   interface{} `json:"from,omitempty" swaggertype:"object"`
+	To     interface{} `json:"to,omitempty" swaggertype:"object"`
+}
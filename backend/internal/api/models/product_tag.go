@@ -0,0 +1,10 @@
+package models
+
+type ProductTagsParams struct {
+	Tags []string `json:"tags" validate:"required,max=250,dive,required,max=255" example:"summer,sale"`
+}
+
+type ShopTag struct {
+	Tag          string `json:"tag" example:"summer"`
+	ProductCount int64  `json:"product_count"`
+}
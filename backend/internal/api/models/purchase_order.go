@@ -0,0 +1,167 @@
+package models
+
+import (
+	"time"
+
+	"github.com/petrejonn/naytife/internal/db"
+	"github.com/petrejonn/naytife/internal/money"
+)
+
+// Supplier represents a business the shop buys stock from
+type Supplier struct {
+	ID          int64     `json:"id"`
+	Name        string    `json:"name" example:"Ikeja Wholesale Ltd"`
+	ContactName *string   `json:"contact_name"`
+	Email       *string   `json:"email"`
+	Phone       *string   `json:"phone"`
+	Address     *string   `json:"address"`
+	Notes       *string   `json:"notes"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// SupplierParams represents the request body for creating or updating a supplier
+type SupplierParams struct {
+	Name        string  `json:"name" validate:"required,max=100" example:"Ikeja Wholesale Ltd"`
+	ContactName *string `json:"contact_name" validate:"omitempty,max=100"`
+	Email       *string `json:"email" validate:"omitempty,email,max=255"`
+	Phone       *string `json:"phone" validate:"omitempty,max=50"`
+	Address     *string `json:"address" validate:"omitempty,max=500"`
+	Notes       *string `json:"notes"`
+}
+
+// PurchaseOrderItemParams is a quantity of a variant to order
+type PurchaseOrderItemParams struct {
+	VariantID int64 `json:"variant_id" validate:"required"`
+	Quantity  int64 `json:"quantity" validate:"required,min=1"`
+	// UnitCost is what the supplier charges for one unit, in the shop currency
+	UnitCost money.Money `json:"unit_cost" swaggertype:"string" example:"1500.00"`
+}
+
+// PurchaseOrderParams represents the request body for creating a purchase order
+type PurchaseOrderParams struct {
+	SupplierID int64 `json:"supplier_id" validate:"required"`
+	// LocationID is the location the stock is delivered to; the default location when empty
+	LocationID *int64                    `json:"location_id"`
+	ExpectedAt *time.Time                `json:"expected_at"`
+	Note       *string                   `json:"note" validate:"omitempty,max=255"`
+	Items      []PurchaseOrderItemParams `json:"items" validate:"required,min=1,unique=VariantID,dive"`
+}
+
+// PurchaseOrderUpdateParams represents the request body for updating a purchase
+// order. The supplier and items can only change while the order is a draft; items
+// left out are kept.
+type PurchaseOrderUpdateParams struct {
+	SupplierID int64                     `json:"supplier_id" validate:"required"`
+	LocationID *int64                    `json:"location_id"`
+	ExpectedAt *time.Time                `json:"expected_at"`
+	Note       *string                   `json:"note" validate:"omitempty,max=255"`
+	Items      []PurchaseOrderItemParams `json:"items" validate:"omitempty,min=1,unique=VariantID,dive"`
+}
+
+// PurchaseOrderReceiptParams is a quantity of a purchase order item that arrived,
+// with the lot it arrived in for product types that track lots
+type PurchaseOrderReceiptParams struct {
+	ItemID   int64 `json:"item_id" validate:"required"`
+	Quantity int64 `json:"quantity" validate:"required,min=1"`
+	StockLotParams
+}
+
+// ReceivePurchaseOrderParams represents the request body for receiving a purchase
+// order. Without items everything still outstanding is received.
+type ReceivePurchaseOrderParams struct {
+	Items []PurchaseOrderReceiptParams `json:"items" validate:"omitempty,unique=ItemID,dive"`
+}
+
+// PurchaseOrderItem is a quantity of a variant ordered from a supplier
+type PurchaseOrderItem struct {
+	ID               int64       `json:"id"`
+	VariantID        int64       `json:"variant_id"`
+	ProductTitle     string      `json:"product_title"`
+	VariantTitle     string      `json:"variant_title"`
+	SKU              string      `json:"sku"`
+	QuantityOrdered  int64       `json:"quantity_ordered"`
+	QuantityReceived int64       `json:"quantity_received"`
+	UnitCost         money.Money `json:"unit_cost" swaggertype:"string" example:"1500.00"`
+}
+
+// PurchaseOrder represents stock ordered from a supplier
+type PurchaseOrder struct {
+	ID         int64      `json:"id"`
+	SupplierID int64      `json:"supplier_id"`
+	LocationID *int64     `json:"location_id"`
+	Status     string     `json:"status" example:"ordered"`
+	ExpectedAt *time.Time `json:"expected_at"`
+	Note       *string    `json:"note"`
+	CreatedBy  *string    `json:"created_by"`
+	OrderedAt  *time.Time `json:"ordered_at"`
+	ReceivedAt *time.Time `json:"received_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	// Total is the cost of every item ordered; only set alongside the items
+	Total *money.Money        `json:"total,omitempty" swaggertype:"string" example:"45000.00"`
+	Items []PurchaseOrderItem `json:"items,omitempty"`
+}
+
+// NewSupplier converts a stored supplier to its API representation
+func NewSupplier(supplier db.Supplier) Supplier {
+	return Supplier{
+		ID:          supplier.SupplierID,
+		Name:        supplier.Name,
+		ContactName: supplier.ContactName,
+		Email:       supplier.Email,
+		Phone:       supplier.Phone,
+		Address:     supplier.Address,
+		Notes:       supplier.Notes,
+		CreatedAt:   supplier.CreatedAt.Time,
+		UpdatedAt:   supplier.UpdatedAt.Time,
+	}
+}
+
+// NewPurchaseOrder converts a stored purchase order and its items to their API
+// representation, with costs in currency
+func NewPurchaseOrder(order db.PurchaseOrder, items []db.ListPurchaseOrderItemsRow, currency string) (PurchaseOrder, error) {
+	response := PurchaseOrder{
+		ID:         order.PurchaseOrderID,
+		SupplierID: order.SupplierID,
+		LocationID: order.LocationID,
+		Status:     string(order.Status),
+		Note:       order.Note,
+		CreatedBy:  order.CreatedBy,
+		CreatedAt:  order.CreatedAt.Time,
+		UpdatedAt:  order.UpdatedAt.Time,
+	}
+	if order.ExpectedAt.Valid {
+		response.ExpectedAt = &order.ExpectedAt.Time
+	}
+	if order.OrderedAt.Valid {
+		response.OrderedAt = &order.OrderedAt.Time
+	}
+	if order.ReceivedAt.Valid {
+		response.ReceivedAt = &order.ReceivedAt.Time
+	}
+	if items == nil {
+		return response, nil
+	}
+
+	total := money.Zero(currency)
+	for _, item := range items {
+		unitCost, err := money.FromNumeric(item.UnitCost, currency)
+		if err != nil {
+			return PurchaseOrder{}, err
+		}
+		total = total.Add(unitCost.Mul(item.QuantityOrdered))
+		response.Items = append(response.Items, PurchaseOrderItem{
+			ID:               item.PurchaseOrderItemID,
+			VariantID:        item.ProductVariationID,
+			ProductTitle:     item.ProductTitle,
+			VariantTitle:     item.VariantTitle,
+			SKU:              item.Sku,
+			QuantityOrdered:  item.QuantityOrdered,
+			QuantityReceived: item.QuantityReceived,
+			UnitCost:         unitCost,
+		})
+	}
+	response.Total = &total
+	return response, nil
+}
@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+
+	"github.com/petrejonn/naytife/internal/db"
+)
+
+type ProductReview struct {
+	ID                 int64           `json:"review_id"`
+	ProductID          int64           `json:"product_id"`
+	ProductVariationID int64           `json:"product_variation_id"`
+	OrderID            int64           `json:"order_id"`
+	Rating             int             `json:"rating" example:"5"`
+	Title              *string         `json:"title"`
+	Body               *string         `json:"body"`
+	Photos             []string        `json:"photos"`
+	CustomerName       string          `json:"customer_name"`
+	CustomerEmail      string          `json:"customer_email"`
+	Status             db.ReviewStatus `json:"status" example:"pending"`
+	MerchantReply      *string         `json:"merchant_reply"`
+	RepliedAt          *time.Time      `json:"replied_at"`
+	CreatedAt          time.Time       `json:"created_at"`
+	UpdatedAt          time.Time       `json:"updated_at"`
+}
+
+type ProductReviewReplyParams struct {
+	Reply string `json:"reply" validate:"required,min=1,max=5000" example:"Thanks for the kind words!"`
+}
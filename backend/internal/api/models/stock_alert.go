@@ -0,0 +1,113 @@
+package models
+
+import (
+	"time"
+
+	"github.com/petrejonn/naytife/internal/db"
+)
+
+// ReorderPointParams represents the request body for setting the reorder point of
+// a variant. Leaving a value empty falls back to that of the product type.
+type ReorderPointParams struct {
+	// ReorderPoint is the stock at which the variant raises a low stock alert
+	ReorderPoint *int64 `json:"reorder_point" validate:"omitempty,min=0" example:"5"`
+	// ReorderQuantity is the number of units suggested for reordering
+	ReorderQuantity *int64 `json:"reorder_quantity" validate:"omitempty,min=1" example:"20"`
+}
+
+// VariantReorderPoint is the stock at which a variant raises a low stock alert and
+// how much of it to reorder
+type VariantReorderPoint struct {
+	VariantID       int64  `json:"variant_id"`
+	ReorderPoint    *int64 `json:"reorder_point"`
+	ReorderQuantity *int64 `json:"reorder_quantity"`
+	// EffectiveReorderPoint and EffectiveReorderQuantity fall back to those of the
+	// product type when the variant sets none
+	EffectiveReorderPoint    *int64 `json:"effective_reorder_point"`
+	EffectiveReorderQuantity *int64 `json:"effective_reorder_quantity"`
+}
+
+// StockAlert is raised when the stock of a variant falls to its reorder point
+type StockAlert struct {
+	ID           int64  `json:"id"`
+	VariantID    int64  `json:"variant_id"`
+	ProductID    int64  `json:"product_id"`
+	ProductTitle string `json:"product_title"`
+	VariantTitle string `json:"variant_title"`
+	SKU          string `json:"sku"`
+	// Kind is low_stock, or out_of_stock when nothing is left
+	Kind string `json:"kind" example:"low_stock"`
+	// AvailableQuantity is the stock left when the alert was raised
+	AvailableQuantity int64      `json:"available_quantity"`
+	ReorderPoint      int64      `json:"reorder_point"`
+	ReorderQuantity   *int64     `json:"reorder_quantity"`
+	ReadAt            *time.Time `json:"read_at"`
+	CreatedAt         time.Time  `json:"created_at"`
+}
+
+// ReorderItem is a variant at or below its reorder point
+type ReorderItem struct {
+	VariantID         int64  `json:"variant_id"`
+	ProductID         int64  `json:"product_id"`
+	ProductTitle      string `json:"product_title"`
+	VariantTitle      string `json:"variant_title"`
+	SKU               string `json:"sku"`
+	AvailableQuantity int64  `json:"available_quantity"`
+	ReorderPoint      int64  `json:"reorder_point"`
+	// SuggestedQuantity is the reorder quantity, or enough to get back above the reorder point
+	SuggestedQuantity int64 `json:"suggested_quantity"`
+	// SuggestedCost is the suggested quantity at cost price, when the variant has one
+	SuggestedCost *float64 `json:"suggested_cost"`
+}
+
+// NewVariantReorderPoint converts the reorder point of a stored variant and the one
+// that applies to it to their API representation
+func NewVariantReorderPoint(variant db.ProductVariation, effective db.GetVariantReorderPointRow) VariantReorderPoint {
+	return VariantReorderPoint{
+		VariantID:                variant.ProductVariationID,
+		ReorderPoint:             variant.ReorderPoint,
+		ReorderQuantity:          variant.ReorderQuantity,
+		EffectiveReorderPoint:    effective.ReorderPoint,
+		EffectiveReorderQuantity: effective.ReorderQuantity,
+	}
+}
+
+// NewStockAlert converts a stored stock alert to its API representation
+func NewStockAlert(alert db.ListStockAlertsRow) StockAlert {
+	response := StockAlert{
+		ID:                alert.AlertID,
+		VariantID:         alert.ProductVariationID,
+		ProductID:         alert.ProductID,
+		ProductTitle:      alert.ProductTitle,
+		VariantTitle:      alert.VariantTitle,
+		SKU:               alert.Sku,
+		Kind:              string(alert.Kind),
+		AvailableQuantity: alert.AvailableQuantity,
+		ReorderPoint:      alert.ReorderPoint,
+		ReorderQuantity:   alert.ReorderQuantity,
+		CreatedAt:         alert.CreatedAt.Time,
+	}
+	if alert.ReadAt.Valid {
+		response.ReadAt = &alert.ReadAt.Time
+	}
+	return response
+}
+
+// NewReorderItem converts a variant of the reorder report to its API representation
+func NewReorderItem(item db.ListReorderVariantsRow) ReorderItem {
+	response := ReorderItem{
+		VariantID:         item.ProductVariationID,
+		ProductID:         item.ProductID,
+		ProductTitle:      item.ProductTitle,
+		VariantTitle:      item.VariantTitle,
+		SKU:               item.Sku,
+		AvailableQuantity: item.AvailableQuantity,
+		ReorderPoint:      item.ReorderPoint,
+		SuggestedQuantity: item.SuggestedQuantity,
+	}
+	if item.CostPrice.Valid {
+		cost := NumericToFloat64(item.CostPrice) * float64(item.SuggestedQuantity)
+		response.SuggestedCost = &cost
+	}
+	return response
+}
@@ -0,0 +1,152 @@
+package models
+
+import (
+	"time"
+
+	"github.com/petrejonn/naytife/internal/db"
+	"github.com/petrejonn/naytife/internal/money"
+)
+
+// StocktakeParams represents the request body for opening a stocktake. Without a
+// category or product type every variant of the shop is counted.
+type StocktakeParams struct {
+	// LocationID is the location whose stock is counted; the default location when empty
+	LocationID *int64 `json:"location_id"`
+	// CategoryID counts the products of the category and its subcategories
+	CategoryID    *int64  `json:"category_id"`
+	ProductTypeID *int64  `json:"product_type_id"`
+	Note          *string `json:"note" validate:"omitempty,max=255"`
+}
+
+// StocktakeCountParams is the quantity counted of a variant, named by its ID or SKU
+type StocktakeCountParams struct {
+	VariantID *int64  `json:"variant_id" validate:"required_without=SKU"`
+	SKU       *string `json:"sku" validate:"required_without=VariantID,omitempty,max=50" example:"TSHIRT-RED-M"`
+	Quantity  int64   `json:"quantity" validate:"min=0"`
+}
+
+// StocktakeCountsParams represents the request body for entering a batch of counts.
+// A count replaces what was counted of the variant before.
+type StocktakeCountsParams struct {
+	Items []StocktakeCountParams `json:"items" validate:"required,min=1,dive"`
+}
+
+// StocktakeScanParams represents the request body for a barcode scan. Each scan adds
+// to what was counted of the variant.
+type StocktakeScanParams struct {
+	// Code is the SKU the scanned barcode encodes
+	Code string `json:"code" validate:"required,max=50" example:"TSHIRT-RED-M"`
+	// Quantity is the number of units scanned; 1 when empty
+	Quantity int64 `json:"quantity" validate:"omitempty,min=1"`
+}
+
+// StocktakeSummary is the progress of a stocktake and the variances it found
+type StocktakeSummary struct {
+	Items     int64 `json:"items"`
+	Counted   int64 `json:"counted"`
+	Variances int64 `json:"variances"`
+	// NetVariance is the units counted less the units expected, over the variants counted
+	NetVariance int64 `json:"net_variance"`
+	// VarianceValue is the net variance at cost price, in the shop currency
+	VarianceValue money.Money `json:"variance_value" swaggertype:"string" example:"-4500.00"`
+}
+
+// Stocktake represents a count of the stock at a location
+type Stocktake struct {
+	ID            int64             `json:"id"`
+	LocationID    int64             `json:"location_id"`
+	CategoryID    *int64            `json:"category_id"`
+	ProductTypeID *int64            `json:"product_type_id"`
+	Status        string            `json:"status" example:"open"`
+	Note          *string           `json:"note"`
+	CreatedBy     *string           `json:"created_by"`
+	CommittedAt   *time.Time        `json:"committed_at"`
+	CreatedAt     time.Time         `json:"created_at"`
+	UpdatedAt     time.Time         `json:"updated_at"`
+	Summary       *StocktakeSummary `json:"summary,omitempty"`
+}
+
+// StocktakeItem is a variant counted by a stocktake. Its variance is only set once
+// it has been counted.
+type StocktakeItem struct {
+	ID           int64  `json:"id"`
+	VariantID    int64  `json:"variant_id"`
+	ProductTitle string `json:"product_title"`
+	VariantTitle string `json:"variant_title"`
+	SKU          string `json:"sku"`
+	// ExpectedQuantity is the stock the location held when the variant was last counted
+	ExpectedQuantity int64      `json:"expected_quantity"`
+	CountedQuantity  *int64     `json:"counted_quantity"`
+	Variance         *int64     `json:"variance"`
+	CountedAt        *time.Time `json:"counted_at"`
+	// UnitCost is the cost price of the variant, when it has one
+	UnitCost      *money.Money `json:"unit_cost" swaggertype:"string" example:"1500.00"`
+	VarianceValue *money.Money `json:"variance_value" swaggertype:"string" example:"-3000.00"`
+}
+
+// NewStocktake converts a stored stocktake and, when given, its summary to their API
+// representation, with values in currency
+func NewStocktake(stocktake db.Stocktake, summary *db.GetStocktakeSummaryRow, currency string) (Stocktake, error) {
+	response := Stocktake{
+		ID:            stocktake.StocktakeID,
+		LocationID:    stocktake.LocationID,
+		CategoryID:    stocktake.CategoryID,
+		ProductTypeID: stocktake.ProductTypeID,
+		Status:        string(stocktake.Status),
+		Note:          stocktake.Note,
+		CreatedBy:     stocktake.CreatedBy,
+		CreatedAt:     stocktake.CreatedAt.Time,
+		UpdatedAt:     stocktake.UpdatedAt.Time,
+	}
+	if stocktake.CommittedAt.Valid {
+		response.CommittedAt = &stocktake.CommittedAt.Time
+	}
+	if summary == nil {
+		return response, nil
+	}
+	value, err := money.FromNumeric(summary.VarianceValue, currency)
+	if err != nil {
+		return Stocktake{}, err
+	}
+	response.Summary = &StocktakeSummary{
+		Items:         summary.Items,
+		Counted:       summary.Counted,
+		Variances:     summary.Variances,
+		NetVariance:   summary.NetVariance,
+		VarianceValue: value,
+	}
+	return response, nil
+}
+
+// NewStocktakeItem converts a stored stocktake item to its API representation, with
+// costs in currency
+func NewStocktakeItem(item db.ListStocktakeItemsRow, currency string) (StocktakeItem, error) {
+	response := StocktakeItem{
+		ID:               item.StocktakeItemID,
+		VariantID:        item.ProductVariationID,
+		ProductTitle:     item.ProductTitle,
+		VariantTitle:     item.VariantTitle,
+		SKU:              item.Sku,
+		ExpectedQuantity: item.ExpectedQuantity,
+		CountedQuantity:  item.CountedQuantity,
+	}
+	if item.CountedAt.Valid {
+		response.CountedAt = &item.CountedAt.Time
+	}
+	if item.CostPrice.Valid {
+		unitCost, err := money.FromNumeric(item.CostPrice, currency)
+		if err != nil {
+			return StocktakeItem{}, err
+		}
+		response.UnitCost = &unitCost
+	}
+	if item.CountedQuantity != nil {
+		variance := *item.CountedQuantity - item.ExpectedQuantity
+		response.Variance = &variance
+		if response.UnitCost != nil {
+			value := response.UnitCost.Mul(variance)
+			response.VarianceValue = &value
+		}
+	}
+	return response, nil
+}
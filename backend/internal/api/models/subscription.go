@@ -0,0 +1,163 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/petrejonn/naytife/internal/db"
+)
+
+// SubscriptionPlanParams represents the request body for adding a subscription plan
+// to a variant
+type SubscriptionPlanParams struct {
+	Name         string `json:"name" validate:"required,max=255" example:"Monthly delivery"`
+	IntervalUnit string `json:"interval_unit" validate:"required,oneof=day week month year" example:"month"`
+	// IntervalCount is the number of interval units between renewals; 1 when empty
+	IntervalCount   *int32   `json:"interval_count" validate:"omitempty,min=1" example:"1"`
+	DiscountPercent *float64 `json:"discount_percent" validate:"omitempty,gte=0,lte=100" example:"10"`
+	// TrialDays delays the first order and charge of new subscriptions
+	TrialDays *int32 `json:"trial_days" validate:"omitempty,min=0" example:"14"`
+	Active    *bool  `json:"active" example:"true"`
+}
+
+// SubscriptionPlanUpdateParams represents the request body for updating a subscription
+// plan. Changes apply to existing subscriptions from their next renewal.
+type SubscriptionPlanUpdateParams struct {
+	Name            *string  `json:"name" validate:"omitempty,min=1,max=255"`
+	IntervalUnit    *string  `json:"interval_unit" validate:"omitempty,oneof=day week month year" example:"month"`
+	IntervalCount   *int32   `json:"interval_count" validate:"omitempty,min=1" example:"1"`
+	DiscountPercent *float64 `json:"discount_percent" validate:"omitempty,gte=0,lte=100" example:"10"`
+	TrialDays       *int32   `json:"trial_days" validate:"omitempty,min=0" example:"14"`
+	// Active plans take new subscriptions; inactive ones keep renewing the subscriptions they have
+	Active *bool `json:"active"`
+}
+
+// PauseSubscriptionParams represents the request body for pausing a subscription
+type PauseSubscriptionParams struct {
+	// ResumeOn is when the subscription resumes by itself; it stays paused until resumed when empty
+	ResumeOn *time.Time `json:"resume_on" example:"2025-10-01T00:00:00Z"`
+}
+
+// CancelSubscriptionParams represents the request body for cancelling a subscription
+type CancelSubscriptionParams struct {
+	Reason *string `json:"reason" validate:"omitempty,max=1000"`
+}
+
+// SubscriptionPlan is a recurring purchase customers can subscribe to on a variant
+type SubscriptionPlan struct {
+	ID              int64     `json:"id"`
+	VariantID       int64     `json:"variant_id"`
+	Name            string    `json:"name"`
+	IntervalUnit    string    `json:"interval_unit" example:"month"`
+	IntervalCount   int32     `json:"interval_count" example:"1"`
+	DiscountPercent float64   `json:"discount_percent" example:"10"`
+	TrialDays       int32     `json:"trial_days"`
+	Active          bool      `json:"active"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// Subscription is a customer's recurring purchase of a variant on a plan
+type Subscription struct {
+	ID              int64     `json:"id"`
+	CustomerID      uuid.UUID `json:"customer_id"`
+	CustomerEmail   string    `json:"customer_email"`
+	PlanID          int64     `json:"plan_id"`
+	PlanName        string    `json:"plan_name"`
+	VariantID       int64     `json:"variant_id"`
+	IntervalUnit    string    `json:"interval_unit" example:"month"`
+	IntervalCount   int32     `json:"interval_count" example:"1"`
+	DiscountPercent float64   `json:"discount_percent" example:"10"`
+	Quantity        int64     `json:"quantity"`
+	Status          string    `json:"status" example:"active"`
+	PaymentMethodID int64     `json:"payment_method_id"`
+	// ShippingAddress is where every renewal order is shipped
+	ShippingAddress ShippingAddress `json:"shipping_address"`
+	ShippingMethod  string          `json:"shipping_method"`
+	NextRenewalAt   time.Time       `json:"next_renewal_at"`
+	PausedUntil     *time.Time      `json:"paused_until"`
+	// PendingOrderID is the renewal order waiting for a failed payment to be retried
+	PendingOrderID *int64     `json:"pending_order_id"`
+	FailedAttempts int32      `json:"failed_attempts"`
+	NextRetryAt    *time.Time `json:"next_retry_at"`
+	CancelledAt    *time.Time `json:"cancelled_at"`
+	CancelReason   *string    `json:"cancel_reason"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// SubscriptionRenewal is an attempt to renew a subscription
+type SubscriptionRenewal struct {
+	ID               int64     `json:"id"`
+	OrderID          *int64    `json:"order_id"`
+	Status           string    `json:"status" example:"paid"`
+	RenewalDate      time.Time `json:"renewal_date"`
+	PaymentReference *string   `json:"payment_reference"`
+	Error            *string   `json:"error"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// NewSubscriptionPlan converts a stored subscription plan to its API representation
+func NewSubscriptionPlan(plan db.SubscriptionPlan) SubscriptionPlan {
+	return SubscriptionPlan{
+		ID:              plan.PlanID,
+		VariantID:       plan.ProductVariationID,
+		Name:            plan.Name,
+		IntervalUnit:    string(plan.IntervalUnit),
+		IntervalCount:   plan.IntervalCount,
+		DiscountPercent: NumericToFloat64(plan.DiscountPercent),
+		TrialDays:       plan.TrialDays,
+		Active:          plan.Active,
+		CreatedAt:       plan.CreatedAt.Time,
+		UpdatedAt:       plan.UpdatedAt.Time,
+	}
+}
+
+// NewSubscription converts a stored subscription with its plan to its API representation
+func NewSubscription(subscription db.GetSubscriptionRow) Subscription {
+	response := Subscription{
+		ID:              subscription.SubscriptionID,
+		CustomerID:      subscription.ShopCustomerID,
+		CustomerEmail:   subscription.CustomerEmail,
+		PlanID:          subscription.PlanID,
+		PlanName:        subscription.PlanName,
+		VariantID:       subscription.ProductVariationID,
+		IntervalUnit:    string(subscription.IntervalUnit),
+		IntervalCount:   subscription.IntervalCount,
+		DiscountPercent: NumericToFloat64(subscription.DiscountPercent),
+		Quantity:        subscription.Quantity,
+		Status:          string(subscription.Status),
+		PaymentMethodID: subscription.CustomerPaymentMethodID,
+		ShippingAddress: ShippingAddressFromSnapshot(subscription.ShippingAddress),
+		ShippingMethod:  subscription.ShippingMethod,
+		NextRenewalAt:   subscription.NextRenewalAt.Time,
+		PendingOrderID:  subscription.PendingOrderID,
+		FailedAttempts:  subscription.FailedAttempts,
+		CancelReason:    subscription.CancelReason,
+		CreatedAt:       subscription.CreatedAt.Time,
+		UpdatedAt:       subscription.UpdatedAt.Time,
+	}
+	if subscription.PausedUntil.Valid {
+		response.PausedUntil = &subscription.PausedUntil.Time
+	}
+	if subscription.NextRetryAt.Valid {
+		response.NextRetryAt = &subscription.NextRetryAt.Time
+	}
+	if subscription.CancelledAt.Valid {
+		response.CancelledAt = &subscription.CancelledAt.Time
+	}
+	return response
+}
+
+// NewSubscriptionRenewal converts a stored renewal to its API representation
+func NewSubscriptionRenewal(renewal db.SubscriptionRenewal) SubscriptionRenewal {
+	return SubscriptionRenewal{
+		ID:               renewal.RenewalID,
+		OrderID:          renewal.OrderID,
+		Status:           string(renewal.Status),
+		RenewalDate:      renewal.RenewalDate.Time,
+		PaymentReference: renewal.PaymentReference,
+		Error:            renewal.Error,
+		CreatedAt:        renewal.CreatedAt.Time,
+	}
+}
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+
+	"github.com/petrejonn/naytife/internal/db"
+)
+
+type ShopLocales struct {
+	DefaultLocale string   `json:"default_locale" example:"en"`
+	Locales       []string `json:"locales" example:"en,fr"`
+}
+
+type ShopLocalesUpdateParams struct {
+	DefaultLocale string   `json:"default_locale" validate:"required" example:"en"`
+	Locales       []string `json:"locales" validate:"required,min=1,max=20" example:"en,fr"`
+}
+
+type Translation struct {
+	ID           int64                       `json:"translation_id"`
+	ResourceType db.TranslatableResourceType `json:"resource_type" example:"product"`
+	ResourceID   int64                       `json:"resource_id"`
+	Field        string                      `json:"field" example:"title"`
+	Locale       string                      `json:"locale" example:"fr"`
+	Value        string                      `json:"value"`
+	UpdatedAt    time.Time                   `json:"updated_at"`
+}
+
+// TranslationsSetParams sets the translations of several fields of a resource in one locale.
+// An empty value removes the translation of that field.
+type TranslationsSetParams struct {
+	ResourceType db.TranslatableResourceType `json:"resource_type" validate:"required,oneof=product product_variation category shop collection" example:"product"`
+	ResourceID   int64                       `json:"resource_id" validate:"required,gt=0"`
+	Locale       string                      `json:"locale" validate:"required" example:"fr"`
+	Fields       map[string]string           `json:"fields" validate:"required,min=1" example:"title:Chemise en lin"`
+}
+
+type MissingTranslation struct {
+	ResourceType db.TranslatableResourceType `json:"resource_type" example:"product"`
+	ResourceID   int64                       `json:"resource_id"`
+	Field        string                      `json:"field" example:"description"`
+	SourceValue  string                      `json:"source_value"`
+}
@@ -2,15 +2,21 @@ package models
 
 import (
 	"fmt"
+	"reflect"
 	"strings"
 
 	"github.com/go-playground/validator/v10"
+	"github.com/petrejonn/naytife/internal/money"
 )
 
 var validate *validator.Validate
 
 func init() {
 	validate = validator.New()
+	// Money fields validate by their minor unit amount, so required means non-zero
+	validate.RegisterCustomTypeFunc(func(v reflect.Value) interface{} {
+		return v.Interface().(money.Money).Amount
+	}, money.Money{})
 }
 
 type XValidator struct{}
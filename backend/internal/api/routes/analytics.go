@@ -14,4 +14,8 @@ func AnalyticsRouter(app fiber.Router, repo db.Repository) {
 	app.Get("/shops/:shop_id/analytics/top-products", handler.GetTopProducts)
 	app.Get("/shops/:shop_id/analytics/customers-summary", handler.GetCustomerSummary)
 	app.Get("/shops/:shop_id/analytics/low-stock", handler.GetLowStockProducts)
+	app.Get("/shops/:shop_id/analytics/profit-summary", handler.GetProfitSummary)
+	app.Get("/shops/:shop_id/analytics/profit-by-product", handler.GetProfitByProduct)
+	app.Get("/shops/:shop_id/analytics/profit-by-category", handler.GetProfitByCategory)
+	app.Get("/shops/:shop_id/analytics/profit-over-time", handler.GetProfitOverTime)
 }
@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	retryablehttp "github.com/hashicorp/go-retryablehttp"
+	"github.com/petrejonn/naytife/internal/api/handlers"
+	"github.com/petrejonn/naytife/internal/db"
+	"github.com/petrejonn/naytife/internal/middleware"
+	"github.com/petrejonn/naytife/internal/services"
+)
+
+func CategoryRouter(app fiber.Router, repo db.Repository, retryClient *retryablehttp.Client, imageUploadService *services.ImageUploadService) {
+	storeDeployerClient := services.NewStoreDeployerClient(retryClient)
+	handler := handlers.NewHandlerWithStoreDeployerClient(repo, retryClient, storeDeployerClient)
+	handler.ImageUploadService = imageUploadService
+
+	app.Post("/shops/:shop_id/categories/:category_id/images/upload", middleware.UploadLimitFiber(imageUploadService.UploadBodyLimit()), handler.UploadCategoryImage)
+}
@@ -8,8 +8,9 @@ import (
 	"github.com/petrejonn/naytife/internal/services"
 )
 
-func CheckoutRouter(app fiber.Router, repo db.Repository, retryClient *retryablehttp.Client, paymentProcessorFactory *services.PaymentProcessorFactory) {
+func CheckoutRouter(app fiber.Router, repo db.Repository, retryClient *retryablehttp.Client, paymentProcessorFactory *services.PaymentProcessorFactory, currencyService *services.CurrencyService) {
 	handler := handlers.NewHandlerWithPaymentFactory(repo, retryClient, paymentProcessorFactory)
+	handler.CurrencyService = currencyService
 
 	// Checkout endpoints
 	app.Post("/shops/:shop_id/checkout", handler.InitiateCheckout)
@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	retryablehttp "github.com/hashicorp/go-retryablehttp"
+	"github.com/petrejonn/naytife/internal/api/handlers"
+	"github.com/petrejonn/naytife/internal/db"
+)
+
+func CollectionRouter(app fiber.Router, repo db.Repository, retryClient *retryablehttp.Client) {
+	handler := handlers.NewHandler(repo, retryClient)
+
+	app.Post("/shops/:shop_id/collections", handler.CreateCollection)
+	app.Get("/shops/:shop_id/collections", handler.GetCollections)
+	app.Get("/shops/:shop_id/collections/:collection_id", handler.GetCollection)
+	app.Put("/shops/:shop_id/collections/:collection_id", handler.UpdateCollection)
+	app.Delete("/shops/:shop_id/collections/:collection_id", handler.DeleteCollection)
+	app.Get("/shops/:shop_id/collections/:collection_id/products", handler.GetCollectionProducts)
+	app.Post("/shops/:shop_id/collections/:collection_id/products", handler.AddCollectionProduct)
+	app.Put("/shops/:shop_id/collections/:collection_id/products/order", handler.ReorderCollectionProducts)
+	app.Delete("/shops/:shop_id/collections/:collection_id/products/:product_id", handler.RemoveCollectionProduct)
+}
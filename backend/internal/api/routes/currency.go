@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	retryablehttp "github.com/hashicorp/go-retryablehttp"
+	"github.com/petrejonn/naytife/internal/api/handlers"
+	"github.com/petrejonn/naytife/internal/db"
+	"github.com/petrejonn/naytife/internal/services"
+)
+
+func CurrencyRouter(app fiber.Router, repo db.Repository, retryClient *retryablehttp.Client, currencyService *services.CurrencyService) {
+	handler := handlers.NewHandler(repo, retryClient)
+	handler.CurrencyService = currencyService
+
+	app.Get("/shops/:shop_id/currencies", handler.GetShopCurrencies)
+	app.Put("/shops/:shop_id/currencies/:currency_code", handler.SetShopCurrency)
+	app.Delete("/shops/:shop_id/currencies/:currency_code", handler.DeleteShopCurrency)
+	app.Get("/shops/:shop_id/variants/:variant_id/prices", handler.GetVariantPrices)
+	app.Put("/shops/:shop_id/variants/:variant_id/prices/:currency_code", handler.SetVariantPrice)
+	app.Delete("/shops/:shop_id/variants/:variant_id/prices/:currency_code", handler.DeleteVariantPrice)
+}
@@ -17,4 +17,40 @@ func CustomerRouter(app fiber.Router, repo db.Repository, retryClient *retryable
 	app.Put("/shops/:shop_id/customers/:customer_id", handler.UpdateCustomer)
 	app.Delete("/shops/:shop_id/customers/:customer_id", handler.DeleteCustomer)
 	app.Get("/shops/:shop_id/customers/:customer_id/orders", handler.GetCustomerOrders)
+
+	// Data subject requests
+	app.Get("/shops/:shop_id/customers/:customer_id/export", handler.ExportCustomerData)
+	app.Post("/shops/:shop_id/customers/:customer_id/erasure", handler.EraseCustomerData)
+	app.Get("/shops/:shop_id/customer-data-requests", handler.GetCustomerDataRequests)
+
+	// Customer address book
+	app.Get("/shops/:shop_id/customers/:customer_id/addresses", handler.GetCustomerAddresses)
+	app.Post("/shops/:shop_id/customers/:customer_id/addresses", handler.CreateCustomerAddress)
+	app.Put("/shops/:shop_id/customers/:customer_id/addresses/:address_id", handler.UpdateCustomerAddress)
+	app.Delete("/shops/:shop_id/customers/:customer_id/addresses/:address_id", handler.DeleteCustomerAddress)
+
+	// Store credit
+	app.Get("/shops/:shop_id/customers/:customer_id/store-credit", handler.GetCustomerStoreCredit)
+	app.Get("/shops/:shop_id/customers/:customer_id/store-credit/transactions", handler.GetCustomerStoreCreditTransactions)
+	app.Post("/shops/:shop_id/customers/:customer_id/store-credit/adjustments", handler.AdjustCustomerStoreCredit)
+
+	// Customer groups and group pricing
+	app.Get("/shops/:shop_id/customer-groups", handler.GetCustomerGroups)
+	app.Post("/shops/:shop_id/customer-groups", handler.CreateCustomerGroup)
+	app.Put("/shops/:shop_id/customer-groups/:group_id", handler.UpdateCustomerGroup)
+	app.Delete("/shops/:shop_id/customer-groups/:group_id", handler.DeleteCustomerGroup)
+	app.Get("/shops/:shop_id/customer-groups/:group_id/members", handler.GetCustomerGroupMembers)
+	app.Post("/shops/:shop_id/customer-groups/:group_id/members", handler.AddCustomerGroupMember)
+	app.Delete("/shops/:shop_id/customer-groups/:group_id/members/:customer_id", handler.RemoveCustomerGroupMember)
+	app.Get("/shops/:shop_id/customer-groups/:group_id/prices", handler.GetCustomerGroupPrices)
+	app.Put("/shops/:shop_id/customer-groups/:group_id/prices/:variant_id", handler.SetCustomerGroupPrice)
+	app.Delete("/shops/:shop_id/customer-groups/:group_id/prices/:variant_id", handler.DeleteCustomerGroupPrice)
+
+	// Customer segments
+	app.Get("/shops/:shop_id/customer-segments", handler.GetCustomerSegments)
+	app.Post("/shops/:shop_id/customer-segments", handler.CreateCustomerSegment)
+	app.Put("/shops/:shop_id/customer-segments/:segment_id", handler.UpdateCustomerSegment)
+	app.Delete("/shops/:shop_id/customer-segments/:segment_id", handler.DeleteCustomerSegment)
+	app.Get("/shops/:shop_id/customer-segments/:segment_id/members", handler.GetCustomerSegmentMembers)
+	app.Get("/shops/:shop_id/customer-segments/:segment_id/members/export", handler.ExportCustomerSegmentMembers)
 }
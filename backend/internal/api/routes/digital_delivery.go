@@ -0,0 +1,34 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	retryablehttp "github.com/hashicorp/go-retryablehttp"
+	"github.com/petrejonn/naytife/internal/api/handlers"
+	"github.com/petrejonn/naytife/internal/db"
+	"github.com/petrejonn/naytife/internal/middleware"
+	"github.com/petrejonn/naytife/internal/services"
+)
+
+func DigitalDeliveryRouter(app fiber.Router, repo db.Repository, retryClient *retryablehttp.Client, digitalDelivery *services.DigitalDelivery) {
+	handler := handlers.NewHandler(repo, retryClient)
+	handler.DigitalDelivery = digitalDelivery
+
+	app.Get("/shops/:shop_id/variants/:variant_id/digital-assets", handler.GetDigitalAssets)
+	app.Post("/shops/:shop_id/variants/:variant_id/digital-assets/upload", middleware.UploadLimitFiber(digitalDelivery.UploadBodyLimit()), handler.UploadDigitalAsset)
+	app.Post("/shops/:shop_id/variants/:variant_id/digital-assets/license-keys", handler.CreateLicenseKeyAsset)
+	app.Patch("/shops/:shop_id/digital-assets/:asset_id", handler.UpdateDigitalAsset)
+	app.Delete("/shops/:shop_id/digital-assets/:asset_id", handler.DeleteDigitalAsset)
+	app.Post("/shops/:shop_id/digital-assets/:asset_id/license-keys", handler.AddLicenseKeys)
+	app.Get("/shops/:shop_id/orders/:order_id/downloads", handler.GetOrderDownloads)
+	app.Post("/shops/:shop_id/orders/:order_id/downloads", handler.DeliverOrderDownloads)
+	app.Get("/shops/:shop_id/downloads/:download_id/events", handler.GetDownloadEvents)
+}
+
+// DownloadRouter sets up the public route serving digital files through their
+// signed links
+func DownloadRouter(app fiber.Router, repo db.Repository, digitalDelivery *services.DigitalDelivery) {
+	handler := handlers.NewHandler(repo, nil)
+	handler.DigitalDelivery = digitalDelivery
+
+	app.Get("/downloads/:shop_id/:download_id", handler.DownloadDigitalFile)
+}
@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	retryablehttp "github.com/hashicorp/go-retryablehttp"
+	"github.com/petrejonn/naytife/internal/api/handlers"
+	"github.com/petrejonn/naytife/internal/db"
+)
+
+func GiftCardRouter(app fiber.Router, repo db.Repository, retryClient *retryablehttp.Client) {
+	handler := handlers.NewHandler(repo, retryClient)
+
+	app.Get("/shops/:shop_id/gift-cards", handler.GetGiftCards)
+	app.Post("/shops/:shop_id/gift-cards", handler.CreateGiftCard)
+	app.Get("/shops/:shop_id/gift-cards/:gift_card_id", handler.GetGiftCard)
+	app.Patch("/shops/:shop_id/gift-cards/:gift_card_id", handler.UpdateGiftCard)
+	app.Post("/shops/:shop_id/gift-cards/:gift_card_id/adjustments", handler.AdjustGiftCard)
+	app.Get("/shops/:shop_id/gift-cards/:gift_card_id/transactions", handler.GetGiftCardTransactions)
+}
@@ -13,9 +13,59 @@ func InventoryRouter(app fiber.Router, repo db.Repository, retryClient *retryabl
 	// Inventory management endpoints
 	app.Get("/shops/:shop_id/inventory", handler.GetInventoryReport) // General inventory endpoint
 	app.Get("/shops/:shop_id/inventory/low-stock", handler.GetLowStockVariants)
+	app.Get("/shops/:shop_id/inventory/expiring", handler.GetExpiringLots)
 	app.Put("/shops/:shop_id/inventory/variants/:variant_id/stock", handler.UpdateVariantStock)
 	app.Post("/shops/:shop_id/inventory/variants/:variant_id/add-stock", handler.AddVariantStock)
 	app.Post("/shops/:shop_id/inventory/variants/:variant_id/deduct-stock", handler.DeductVariantStock)
 	app.Get("/shops/:shop_id/inventory/report", handler.GetInventoryReport)
 	app.Get("/shops/:shop_id/inventory/movements", handler.GetStockMovements)
+	app.Get("/shops/:shop_id/inventory/variants/:variant_id/levels", handler.GetVariantInventoryLevels)
+	app.Get("/shops/:shop_id/inventory/variants/:variant_id/lots", handler.GetVariantLots)
+
+	// Backorders and preorders
+	app.Get("/shops/:shop_id/inventory/variants/:variant_id/policy", handler.GetVariantInventoryPolicy)
+	app.Put("/shops/:shop_id/inventory/variants/:variant_id/policy", handler.SetVariantInventoryPolicy)
+	app.Get("/shops/:shop_id/inventory/backorders", handler.GetBackorderedOrderItems)
+
+	// Reorder points and stock alerts
+	app.Get("/shops/:shop_id/inventory/variants/:variant_id/reorder-point", handler.GetVariantReorderPoint)
+	app.Put("/shops/:shop_id/inventory/variants/:variant_id/reorder-point", handler.SetVariantReorderPoint)
+	app.Get("/shops/:shop_id/inventory/reorder", handler.GetReorderReport)
+	app.Get("/shops/:shop_id/inventory/alerts", handler.GetStockAlerts)
+	app.Post("/shops/:shop_id/inventory/alerts/read", handler.MarkAllStockAlertsRead)
+	app.Post("/shops/:shop_id/inventory/alerts/:alert_id/read", handler.MarkStockAlertRead)
+
+	// Bundles
+	app.Get("/shops/:shop_id/inventory/variants/:variant_id/components", handler.GetBundleComponents)
+	app.Put("/shops/:shop_id/inventory/variants/:variant_id/components", handler.SetBundleComponents)
+
+	// Lots
+	app.Post("/shops/:shop_id/inventory/lots/write-off-expired", handler.WriteOffExpiredLots)
+	app.Post("/shops/:shop_id/inventory/lots/:lot_id/write-off", handler.WriteOffLot)
+
+	// Locations
+	app.Get("/shops/:shop_id/locations", handler.GetLocations)
+	app.Post("/shops/:shop_id/locations", handler.CreateLocation)
+	app.Get("/shops/:shop_id/locations/:location_id", handler.GetLocation)
+	app.Put("/shops/:shop_id/locations/:location_id", handler.UpdateLocation)
+	app.Delete("/shops/:shop_id/locations/:location_id", handler.DeleteLocation)
+	app.Get("/shops/:shop_id/locations/:location_id/inventory", handler.GetLocationInventory)
+
+	// Transfers between locations
+	app.Get("/shops/:shop_id/inventory/transfers", handler.GetInventoryTransfers)
+	app.Post("/shops/:shop_id/inventory/transfers", handler.CreateInventoryTransfer)
+	app.Get("/shops/:shop_id/inventory/transfers/:transfer_id", handler.GetInventoryTransfer)
+	app.Post("/shops/:shop_id/inventory/transfers/:transfer_id/receive", handler.ReceiveInventoryTransfer)
+	app.Post("/shops/:shop_id/inventory/transfers/:transfer_id/cancel", handler.CancelInventoryTransfer)
+
+	// Stocktakes
+	app.Get("/shops/:shop_id/inventory/stocktakes", handler.GetStocktakes)
+	app.Post("/shops/:shop_id/inventory/stocktakes", handler.CreateStocktake)
+	app.Get("/shops/:shop_id/inventory/stocktakes/:stocktake_id", handler.GetStocktake)
+	app.Get("/shops/:shop_id/inventory/stocktakes/:stocktake_id/items", handler.GetStocktakeItems)
+	app.Post("/shops/:shop_id/inventory/stocktakes/:stocktake_id/counts", handler.RecordStocktakeCounts)
+	app.Post("/shops/:shop_id/inventory/stocktakes/:stocktake_id/scan", handler.ScanStocktakeItem)
+	app.Get("/shops/:shop_id/inventory/stocktakes/:stocktake_id/variances", handler.GetStocktakeVariances)
+	app.Post("/shops/:shop_id/inventory/stocktakes/:stocktake_id/commit", handler.CommitStocktake)
+	app.Post("/shops/:shop_id/inventory/stocktakes/:stocktake_id/cancel", handler.CancelStocktake)
 }
@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	retryablehttp "github.com/hashicorp/go-retryablehttp"
+	"github.com/petrejonn/naytife/internal/api/handlers"
+	"github.com/petrejonn/naytife/internal/db"
+)
+
+func LoyaltyRouter(app fiber.Router, repo db.Repository, retryClient *retryablehttp.Client) {
+	handler := handlers.NewHandler(repo, retryClient)
+
+	app.Get("/shops/:shop_id/loyalty-program", handler.GetLoyaltyProgram)
+	app.Put("/shops/:shop_id/loyalty-program", handler.UpdateLoyaltyProgram)
+	app.Get("/shops/:shop_id/customers/:customer_id/loyalty", handler.GetCustomerLoyalty)
+	app.Get("/shops/:shop_id/customers/:customer_id/loyalty/transactions", handler.GetCustomerLoyaltyTransactions)
+	app.Post("/shops/:shop_id/customers/:customer_id/loyalty/adjustments", handler.AdjustCustomerLoyalty)
+}
@@ -0,0 +1,35 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	retryablehttp "github.com/hashicorp/go-retryablehttp"
+	"github.com/petrejonn/naytife/internal/api/handlers"
+	"github.com/petrejonn/naytife/internal/db"
+)
+
+func MetafieldRouter(app fiber.Router, repo db.Repository, retryClient *retryablehttp.Client) {
+	handler := handlers.NewHandler(repo, retryClient)
+
+	app.Post("/shops/:shop_id/metafield-definitions", handler.CreateMetafieldDefinition)
+	app.Get("/shops/:shop_id/metafield-definitions", handler.GetMetafieldDefinitions)
+	app.Get("/shops/:shop_id/metafield-definitions/:definition_id", handler.GetMetafieldDefinition)
+	app.Put("/shops/:shop_id/metafield-definitions/:definition_id", handler.UpdateMetafieldDefinition)
+	app.Delete("/shops/:shop_id/metafield-definitions/:definition_id", handler.DeleteMetafieldDefinition)
+
+	owners := []struct {
+		path      string
+		ownerType db.MetafieldOwnerType
+		idParam   string
+	}{
+		{"/shops/:shop_id/products/:product_id/metafields", db.MetafieldOwnerTypeProduct, "product_id"},
+		{"/shops/:shop_id/variants/:variant_id/metafields", db.MetafieldOwnerTypeProductVariation, "variant_id"},
+		{"/shops/:shop_id/categories/:category_id/metafields", db.MetafieldOwnerTypeCategory, "category_id"},
+		{"/shops/:shop_id/customers/:customer_id/metafields", db.MetafieldOwnerTypeCustomer, "customer_id"},
+		{"/shops/:shop_id/metafields", db.MetafieldOwnerTypeShop, "shop_id"},
+	}
+	for _, owner := range owners {
+		app.Get(owner.path, handler.GetMetafields(owner.ownerType, owner.idParam))
+		app.Put(owner.path, handler.SetMetafield(owner.ownerType, owner.idParam))
+		app.Delete(owner.path+"/:namespace/:key", handler.DeleteMetafield(owner.ownerType, owner.idParam))
+	}
+}
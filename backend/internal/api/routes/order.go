@@ -17,4 +17,6 @@ func OrderRouter(app fiber.Router, repo db.Repository, retryClient *retryablehtt
 	app.Put("/shops/:shop_id/orders/:order_id", handler.UpdateOrder)
 	app.Patch("/shops/:shop_id/orders/:order_id/status", handler.UpdateOrderStatus)
 	app.Delete("/shops/:shop_id/orders/:order_id", handler.DeleteOrder)
+	app.Post("/shops/:shop_id/orders/:order_id/fulfillment", handler.FulfillOrder)
+	app.Get("/shops/:shop_id/orders/:order_id/fulfillment", handler.GetOrderFulfillment)
 }
@@ -4,19 +4,21 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/petrejonn/naytife/internal/api/handlers"
 	"github.com/petrejonn/naytife/internal/db"
+	"github.com/petrejonn/naytife/internal/middleware"
 	"github.com/petrejonn/naytife/internal/services"
 )
 
 // PaymentRouter sets up payment routes
-func PaymentRouter(app fiber.Router, repo db.Repository, paymentProcessorFactory *services.PaymentProcessorFactory) {
+func PaymentRouter(app fiber.Router, repo db.Repository, paymentProcessorFactory *services.PaymentProcessorFactory, currencyService *services.CurrencyService, digitalDelivery *services.DigitalDelivery, customerAuth *services.CustomerAuth) {
 	// Create payment handler
-	paymentHandler := handlers.NewPaymentHandler(paymentProcessorFactory, repo)
+	paymentHandler := handlers.NewPaymentHandler(paymentProcessorFactory, repo, currencyService, digitalDelivery)
 
 	// Payment routes group
 	payments := app.Group("/payments")
 
 	// Payment session endpoints
-	payments.Post("/checkout", paymentHandler.CreateCheckoutSession)
+	// The signed-in customer is needed to spend the store credit of an order
+	payments.Post("/checkout", middleware.BodyShopIDMiddlewareFiber(), middleware.CustomerMiddlewareFiber(repo, customerAuth), paymentHandler.CreateCheckoutSession)
 	payments.Post("/:shop_id/confirm", paymentHandler.ConfirmPayment)
 	payments.Get("/:shop_id/status/:payment_intent_id", paymentHandler.GetPaymentStatus)
 }
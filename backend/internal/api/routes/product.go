@@ -5,12 +5,14 @@ import (
 	retryablehttp "github.com/hashicorp/go-retryablehttp"
 	"github.com/petrejonn/naytife/internal/api/handlers"
 	"github.com/petrejonn/naytife/internal/db"
+	"github.com/petrejonn/naytife/internal/middleware"
 	"github.com/petrejonn/naytife/internal/services"
 )
 
-func ProductRouter(app fiber.Router, repo db.Repository, retryClient *retryablehttp.Client) {
+func ProductRouter(app fiber.Router, repo db.Repository, retryClient *retryablehttp.Client, imageUploadService *services.ImageUploadService) {
 	storeDeployerClient := services.NewStoreDeployerClient(retryClient)
 	handler := handlers.NewHandlerWithStoreDeployerClient(repo, retryClient, storeDeployerClient)
+	handler.ImageUploadService = imageUploadService
 
 	app.Post("/shops/:shop_id/product-types/:product_type_id/products", handler.CreateProduct)
 	app.Get("/shops/:shop_id/product-types/:product_type_id/products", handler.GetProductsByType)
@@ -19,8 +21,20 @@ func ProductRouter(app fiber.Router, repo db.Repository, retryClient *retryableh
 	app.Put("/shops/:shop_id/products/:product_id", handler.UpdateProduct)
 	app.Delete("/shops/:shop_id/products/:product_id", handler.DeleteProduct)
 
+	// Product revision routes
+	app.Get("/shops/:shop_id/products/:product_id/revisions", handler.GetProductRevisions)
+	app.Get("/shops/:shop_id/products/:product_id/revisions/diff", handler.DiffProductRevisions)
+	app.Get("/shops/:shop_id/products/:product_id/revisions/:version", handler.GetProductRevision)
+	app.Post("/shops/:shop_id/products/:product_id/revisions/:version/restore", handler.RestoreProductRevision)
+
 	// Product images routes
 	app.Post("/shops/:shop_id/products/:product_id/images", handler.AddProductImage)
+	app.Post("/shops/:shop_id/products/:product_id/images/upload", middleware.UploadLimitFiber(imageUploadService.UploadBodyLimit()), handler.UploadProductImage)
 	app.Get("/shops/:shop_id/products/:product_id/images", handler.GetProductImages)
 	app.Delete("/shops/:shop_id/products/:product_id/images/:image_id", handler.DeleteProductImage)
+
+	// Product tag routes
+	app.Get("/shops/:shop_id/products/:product_id/tags", handler.GetProductTags)
+	app.Put("/shops/:shop_id/products/:product_id/tags", handler.SetProductTags)
+	app.Get("/shops/:shop_id/tags", handler.GetShopTags)
 }
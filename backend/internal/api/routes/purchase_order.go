@@ -0,0 +1,28 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	retryablehttp "github.com/hashicorp/go-retryablehttp"
+	"github.com/petrejonn/naytife/internal/api/handlers"
+	"github.com/petrejonn/naytife/internal/db"
+)
+
+func PurchaseOrderRouter(app fiber.Router, repo db.Repository, retryClient *retryablehttp.Client) {
+	handler := handlers.NewHandler(repo, retryClient)
+
+	// Suppliers
+	app.Get("/shops/:shop_id/suppliers", handler.GetSuppliers)
+	app.Post("/shops/:shop_id/suppliers", handler.CreateSupplier)
+	app.Get("/shops/:shop_id/suppliers/:supplier_id", handler.GetSupplier)
+	app.Put("/shops/:shop_id/suppliers/:supplier_id", handler.UpdateSupplier)
+	app.Delete("/shops/:shop_id/suppliers/:supplier_id", handler.DeleteSupplier)
+
+	// Purchase orders
+	app.Get("/shops/:shop_id/purchase-orders", handler.GetPurchaseOrders)
+	app.Post("/shops/:shop_id/purchase-orders", handler.CreatePurchaseOrder)
+	app.Get("/shops/:shop_id/purchase-orders/:purchase_order_id", handler.GetPurchaseOrder)
+	app.Put("/shops/:shop_id/purchase-orders/:purchase_order_id", handler.UpdatePurchaseOrder)
+	app.Delete("/shops/:shop_id/purchase-orders/:purchase_order_id", handler.DeletePurchaseOrder)
+	app.Post("/shops/:shop_id/purchase-orders/:purchase_order_id/order", handler.OrderPurchaseOrder)
+	app.Post("/shops/:shop_id/purchase-orders/:purchase_order_id/receive", handler.ReceivePurchaseOrder)
+}
@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	retryablehttp "github.com/hashicorp/go-retryablehttp"
+	"github.com/petrejonn/naytife/internal/api/handlers"
+	"github.com/petrejonn/naytife/internal/db"
+)
+
+func ReviewRouter(app fiber.Router, repo db.Repository, retryClient *retryablehttp.Client) {
+	handler := handlers.NewHandler(repo, retryClient)
+
+	app.Get("/shops/:shop_id/reviews", handler.GetProductReviews)
+	app.Get("/shops/:shop_id/reviews/:review_id", handler.GetProductReview)
+	app.Post("/shops/:shop_id/reviews/:review_id/approve", handler.ApproveProductReview)
+	app.Post("/shops/:shop_id/reviews/:review_id/reject", handler.RejectProductReview)
+	app.Put("/shops/:shop_id/reviews/:review_id/reply", handler.ReplyToProductReview)
+}
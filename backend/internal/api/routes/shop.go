@@ -5,12 +5,14 @@ import (
 	retryablehttp "github.com/hashicorp/go-retryablehttp"
 	"github.com/petrejonn/naytife/internal/api/handlers"
 	"github.com/petrejonn/naytife/internal/db"
+	"github.com/petrejonn/naytife/internal/middleware"
 	"github.com/petrejonn/naytife/internal/services"
 )
 
-func ShopRouter(app fiber.Router, repo db.Repository, retryClient *retryablehttp.Client) {
+func ShopRouter(app fiber.Router, repo db.Repository, retryClient *retryablehttp.Client, imageUploadService *services.ImageUploadService) {
 	storeDeployerClient := services.NewStoreDeployerClient(retryClient)
 	handler := handlers.NewHandlerWithStoreDeployerClient(repo, retryClient, storeDeployerClient)
+	handler.ImageUploadService = imageUploadService
 
 	app.Post("/shops", handler.CreateShop)
 	app.Get("/shops", handler.GetShops)
@@ -18,6 +20,7 @@ func ShopRouter(app fiber.Router, repo db.Repository, retryClient *retryablehttp
 	app.Get("/shops/:shop_id", handler.GetShop)
 	app.Put("/shops/:shop_id", handler.UpdateShop)
 	app.Put("/shops/:shop_id/images", handler.UpdateShopImages)
+	app.Post("/shops/:shop_id/images/upload", middleware.UploadLimitFiber(imageUploadService.UploadBodyLimit()), handler.UploadShopImage)
 	app.Get("/subdomains/:subdomain", handler.GetShopBySubDomain)
 	app.Get("/subdomains/:subdomain/check", handler.CheckSubdomainAvailability)
 	app.Get("/customerinfo", handler.GetCustomerByEmail)
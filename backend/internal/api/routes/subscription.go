@@ -0,0 +1,24 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	retryablehttp "github.com/hashicorp/go-retryablehttp"
+	"github.com/petrejonn/naytife/internal/api/handlers"
+	"github.com/petrejonn/naytife/internal/db"
+)
+
+func SubscriptionRouter(app fiber.Router, repo db.Repository, retryClient *retryablehttp.Client) {
+	handler := handlers.NewHandler(repo, retryClient)
+
+	app.Get("/shops/:shop_id/variants/:variant_id/subscription-plans", handler.GetSubscriptionPlans)
+	app.Post("/shops/:shop_id/variants/:variant_id/subscription-plans", handler.CreateSubscriptionPlan)
+	app.Patch("/shops/:shop_id/subscription-plans/:plan_id", handler.UpdateSubscriptionPlan)
+	app.Delete("/shops/:shop_id/subscription-plans/:plan_id", handler.DeleteSubscriptionPlan)
+	app.Get("/shops/:shop_id/subscriptions", handler.GetSubscriptions)
+	app.Get("/shops/:shop_id/subscriptions/:subscription_id", handler.GetSubscription)
+	app.Get("/shops/:shop_id/subscriptions/:subscription_id/renewals", handler.GetSubscriptionRenewals)
+	app.Post("/shops/:shop_id/subscriptions/:subscription_id/pause", handler.PauseSubscription)
+	app.Post("/shops/:shop_id/subscriptions/:subscription_id/resume", handler.ResumeSubscription)
+	app.Post("/shops/:shop_id/subscriptions/:subscription_id/skip", handler.SkipSubscriptionRenewal)
+	app.Post("/shops/:shop_id/subscriptions/:subscription_id/cancel", handler.CancelSubscription)
+}
@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	retryablehttp "github.com/hashicorp/go-retryablehttp"
+	"github.com/petrejonn/naytife/internal/api/handlers"
+	"github.com/petrejonn/naytife/internal/db"
+)
+
+func TranslationRouter(app fiber.Router, repo db.Repository, retryClient *retryablehttp.Client) {
+	handler := handlers.NewHandler(repo, retryClient)
+
+	app.Get("/shops/:shop_id/locales", handler.GetShopLocales)
+	app.Put("/shops/:shop_id/locales", handler.UpdateShopLocales)
+	app.Get("/shops/:shop_id/translations", handler.GetTranslations)
+	app.Put("/shops/:shop_id/translations", handler.SetTranslations)
+	app.Get("/shops/:shop_id/translations/missing", handler.GetMissingTranslations)
+	app.Delete("/shops/:shop_id/translations/:resource_type/:resource_id/:locale", handler.DeleteTranslations)
+}
@@ -8,9 +8,9 @@ import (
 )
 
 // WebhookRouter sets up webhook routes for all payment providers
-func WebhookRouter(app fiber.Router, repo db.Repository, paymentProcessorFactory *services.PaymentProcessorFactory) {
+func WebhookRouter(app fiber.Router, repo db.Repository, paymentProcessorFactory *services.PaymentProcessorFactory, digitalDelivery *services.DigitalDelivery) {
 	// Create webhook handler
-	webhookHandler := handlers.NewWebhookHandler(paymentProcessorFactory, repo)
+	webhookHandler := handlers.NewWebhookHandler(paymentProcessorFactory, repo, digitalDelivery)
 
 	// Webhook routes group
 	webhooks := app.Group("/webhooks")
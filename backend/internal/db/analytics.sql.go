@@ -98,16 +98,18 @@ SELECT
   p.title AS product_name,
   pv.sku,
   pv.description,
-  pv.available_quantity AS stock
+  pv.available_quantity AS stock,
+  COALESCE($1::bigint, pv.reorder_point, pt.reorder_point, 5)::bigint AS reorder_level
 FROM product_variations pv
 JOIN products p ON pv.product_id = p.product_id
-WHERE pv.shop_id = $1 AND pv.available_quantity <= $2
+JOIN product_types pt ON pt.product_type_id = p.product_type_id
+WHERE pv.shop_id = $2 AND pv.available_quantity <= COALESCE($1::bigint, pv.reorder_point, pt.reorder_point, 5)
 ORDER BY pv.available_quantity ASC
 `
 
 type GetLowStockProductsParams struct {
-	ShopID            int64 `json:"shop_id"`
-	AvailableQuantity int64 `json:"available_quantity"`
+	Threshold *int64 `json:"threshold"`
+	ShopID    int64  `json:"shop_id"`
 }
 
 type GetLowStockProductsRow struct {
@@ -116,10 +118,13 @@ type GetLowStockProductsRow struct {
 	Sku                string `json:"sku"`
 	Description        string `json:"description"`
 	Stock              int64  `json:"stock"`
+	ReorderLevel       int64  `json:"reorder_level"`
 }
 
+// Variants at or below the threshold, or at or below their reorder point when no
+// threshold is given. Variants without a reorder point use a threshold of 5.
 func (q *Queries) GetLowStockProducts(ctx context.Context, arg GetLowStockProductsParams) ([]GetLowStockProductsRow, error) {
-	rows, err := q.db.Query(ctx, getLowStockProducts, arg.ShopID, arg.AvailableQuantity)
+	rows, err := q.db.Query(ctx, getLowStockProducts, arg.Threshold, arg.ShopID)
 	if err != nil {
 		return nil, err
 	}
@@ -133,6 +138,7 @@ func (q *Queries) GetLowStockProducts(ctx context.Context, arg GetLowStockProduc
 			&i.Sku,
 			&i.Description,
 			&i.Stock,
+			&i.ReorderLevel,
 		); err != nil {
 			return nil, err
 		}
@@ -191,6 +197,232 @@ func (q *Queries) GetOrdersOverTime(ctx context.Context, arg GetOrdersOverTimePa
 	return items, nil
 }
 
+const getProfitByCategory = `-- name: GetProfitByCategory :many
+SELECT
+  c.category_id,
+  c.title AS category_name,
+  COALESCE(SUM(oi.quantity), 0)::bigint AS units_sold,
+  COALESCE(SUM(oi.quantity * oi.price), 0)::numeric AS revenue,
+  COALESCE(SUM(oi.quantity * oi.price) FILTER (WHERE oi.cost_price IS NOT NULL), 0)::numeric AS costed_revenue,
+  COALESCE(SUM(oi.quantity * oi.cost_price), 0)::numeric AS cost_of_goods
+FROM order_items oi
+JOIN orders o ON oi.order_id = o.order_id
+JOIN product_variations pv ON oi.product_variation_id = pv.product_variation_id
+JOIN products p ON pv.product_id = p.product_id
+LEFT JOIN categories c ON p.category_id = c.category_id
+WHERE o.shop_id = $1 AND o.created_at BETWEEN $2 AND $3 AND o.status = 'completed'
+GROUP BY c.category_id, c.title
+ORDER BY COALESCE(SUM(oi.quantity * (oi.price - oi.cost_price)), 0) DESC, c.category_id
+`
+
+type GetProfitByCategoryParams struct {
+	ShopID  int64              `json:"shop_id"`
+	StartAt pgtype.Timestamptz `json:"start_at"`
+	EndAt   pgtype.Timestamptz `json:"end_at"`
+}
+
+type GetProfitByCategoryRow struct {
+	CategoryID    *int64         `json:"category_id"`
+	CategoryName  *string        `json:"category_name"`
+	UnitsSold     int64          `json:"units_sold"`
+	Revenue       pgtype.Numeric `json:"revenue"`
+	CostedRevenue pgtype.Numeric `json:"costed_revenue"`
+	CostOfGoods   pgtype.Numeric `json:"cost_of_goods"`
+}
+
+// Groups profit by the category of each product; products without a category are
+// grouped under a NULL category
+func (q *Queries) GetProfitByCategory(ctx context.Context, arg GetProfitByCategoryParams) ([]GetProfitByCategoryRow, error) {
+	rows, err := q.db.Query(ctx, getProfitByCategory, arg.ShopID, arg.StartAt, arg.EndAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetProfitByCategoryRow
+	for rows.Next() {
+		var i GetProfitByCategoryRow
+		if err := rows.Scan(
+			&i.CategoryID,
+			&i.CategoryName,
+			&i.UnitsSold,
+			&i.Revenue,
+			&i.CostedRevenue,
+			&i.CostOfGoods,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getProfitByProduct = `-- name: GetProfitByProduct :many
+SELECT
+  p.product_id,
+  p.title AS product_name,
+  COALESCE(SUM(oi.quantity), 0)::bigint AS units_sold,
+  COALESCE(SUM(oi.quantity * oi.price), 0)::numeric AS revenue,
+  COALESCE(SUM(oi.quantity * oi.price) FILTER (WHERE oi.cost_price IS NOT NULL), 0)::numeric AS costed_revenue,
+  COALESCE(SUM(oi.quantity * oi.cost_price), 0)::numeric AS cost_of_goods
+FROM order_items oi
+JOIN orders o ON oi.order_id = o.order_id
+JOIN product_variations pv ON oi.product_variation_id = pv.product_variation_id
+JOIN products p ON pv.product_id = p.product_id
+WHERE o.shop_id = $1 AND o.created_at BETWEEN $2 AND $3 AND o.status = 'completed'
+GROUP BY p.product_id, p.title
+ORDER BY COALESCE(SUM(oi.quantity * (oi.price - oi.cost_price)), 0) DESC, p.product_id
+LIMIT $4
+`
+
+type GetProfitByProductParams struct {
+	ShopID  int64              `json:"shop_id"`
+	StartAt pgtype.Timestamptz `json:"start_at"`
+	EndAt   pgtype.Timestamptz `json:"end_at"`
+	Limit   int32              `json:"limit"`
+}
+
+type GetProfitByProductRow struct {
+	ProductID     int64          `json:"product_id"`
+	ProductName   string         `json:"product_name"`
+	UnitsSold     int64          `json:"units_sold"`
+	Revenue       pgtype.Numeric `json:"revenue"`
+	CostedRevenue pgtype.Numeric `json:"costed_revenue"`
+	CostOfGoods   pgtype.Numeric `json:"cost_of_goods"`
+}
+
+func (q *Queries) GetProfitByProduct(ctx context.Context, arg GetProfitByProductParams) ([]GetProfitByProductRow, error) {
+	rows, err := q.db.Query(ctx, getProfitByProduct,
+		arg.ShopID,
+		arg.StartAt,
+		arg.EndAt,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetProfitByProductRow
+	for rows.Next() {
+		var i GetProfitByProductRow
+		if err := rows.Scan(
+			&i.ProductID,
+			&i.ProductName,
+			&i.UnitsSold,
+			&i.Revenue,
+			&i.CostedRevenue,
+			&i.CostOfGoods,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getProfitOverTime = `-- name: GetProfitOverTime :many
+SELECT
+  DATE_TRUNC($1, o.created_at)::date AS period,
+  COALESCE(SUM(oi.quantity * oi.price), 0)::numeric AS revenue,
+  COALESCE(SUM(oi.quantity * oi.price) FILTER (WHERE oi.cost_price IS NOT NULL), 0)::numeric AS costed_revenue,
+  COALESCE(SUM(oi.quantity * oi.cost_price), 0)::numeric AS cost_of_goods
+FROM order_items oi
+JOIN orders o ON oi.order_id = o.order_id
+WHERE o.shop_id = $2 AND o.created_at BETWEEN $3 AND $4 AND o.status = 'completed'
+GROUP BY period
+ORDER BY period ASC
+`
+
+type GetProfitOverTimeParams struct {
+	Interval string             `json:"interval"`
+	ShopID   int64              `json:"shop_id"`
+	StartAt  pgtype.Timestamptz `json:"start_at"`
+	EndAt    pgtype.Timestamptz `json:"end_at"`
+}
+
+type GetProfitOverTimeRow struct {
+	Period        pgtype.Date    `json:"period"`
+	Revenue       pgtype.Numeric `json:"revenue"`
+	CostedRevenue pgtype.Numeric `json:"costed_revenue"`
+	CostOfGoods   pgtype.Numeric `json:"cost_of_goods"`
+}
+
+func (q *Queries) GetProfitOverTime(ctx context.Context, arg GetProfitOverTimeParams) ([]GetProfitOverTimeRow, error) {
+	rows, err := q.db.Query(ctx, getProfitOverTime,
+		arg.Interval,
+		arg.ShopID,
+		arg.StartAt,
+		arg.EndAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetProfitOverTimeRow
+	for rows.Next() {
+		var i GetProfitOverTimeRow
+		if err := rows.Scan(
+			&i.Period,
+			&i.Revenue,
+			&i.CostedRevenue,
+			&i.CostOfGoods,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getProfitSummary = `-- name: GetProfitSummary :one
+SELECT
+  COALESCE(SUM(oi.quantity * oi.price), 0)::numeric AS revenue,
+  COALESCE(SUM(oi.quantity * oi.price) FILTER (WHERE oi.cost_price IS NOT NULL), 0)::numeric AS costed_revenue,
+  COALESCE(SUM(oi.quantity * oi.cost_price), 0)::numeric AS cost_of_goods,
+  COALESCE(SUM(oi.quantity), 0)::bigint AS units_sold,
+  COALESCE(SUM(oi.quantity) FILTER (WHERE oi.cost_price IS NULL), 0)::bigint AS units_without_cost
+FROM order_items oi
+JOIN orders o ON oi.order_id = o.order_id
+WHERE o.shop_id = $1 AND o.created_at BETWEEN $2 AND $3 AND o.status = 'completed'
+`
+
+type GetProfitSummaryParams struct {
+	ShopID  int64              `json:"shop_id"`
+	StartAt pgtype.Timestamptz `json:"start_at"`
+	EndAt   pgtype.Timestamptz `json:"end_at"`
+}
+
+type GetProfitSummaryRow struct {
+	Revenue          pgtype.Numeric `json:"revenue"`
+	CostedRevenue    pgtype.Numeric `json:"costed_revenue"`
+	CostOfGoods      pgtype.Numeric `json:"cost_of_goods"`
+	UnitsSold        int64          `json:"units_sold"`
+	UnitsWithoutCost int64          `json:"units_without_cost"`
+}
+
+// Revenue and cost of the items of completed orders. Gross profit and the revenue it
+// is earned on only count the items whose cost price was known when they were sold.
+func (q *Queries) GetProfitSummary(ctx context.Context, arg GetProfitSummaryParams) (GetProfitSummaryRow, error) {
+	row := q.db.QueryRow(ctx, getProfitSummary, arg.ShopID, arg.StartAt, arg.EndAt)
+	var i GetProfitSummaryRow
+	err := row.Scan(
+		&i.Revenue,
+		&i.CostedRevenue,
+		&i.CostOfGoods,
+		&i.UnitsSold,
+		&i.UnitsWithoutCost,
+	)
+	return i, err
+}
+
 const getSalesSummary = `-- name: GetSalesSummary :one
 SELECT
   COALESCE(SUM(revenue), 0) AS total_sales,
@@ -224,36 +456,46 @@ SELECT
   oi.product_variation_id,
   p.title AS product_name,
   COALESCE(SUM(oi.quantity), 0) AS units_sold,
-  COALESCE(SUM(oi.quantity * oi.price), 0) AS revenue
+  COALESCE(SUM(oi.quantity * oi.price), 0) AS revenue,
+  COALESCE(SUM(oi.quantity * oi.cost_price), 0)::numeric AS cost_of_goods,
+  COALESCE(SUM(oi.quantity * (oi.price - oi.cost_price)), 0)::numeric AS gross_profit
 FROM order_items oi
 JOIN orders o ON oi.order_id = o.order_id
 JOIN product_variations pv ON oi.product_variation_id = pv.product_variation_id
 JOIN products p ON pv.product_id = p.product_id
 WHERE o.shop_id = $1 AND o.created_at BETWEEN $2 AND $3 AND o.status = 'completed'
 GROUP BY oi.product_variation_id, p.title
-ORDER BY units_sold DESC
-LIMIT $4
+ORDER BY
+  CASE WHEN $4::text = 'profit' THEN COALESCE(SUM(oi.quantity * (oi.price - oi.cost_price)), 0) END DESC,
+  units_sold DESC
+LIMIT $5
 `
 
 type GetTopProductsParams struct {
-	ShopID      int64              `json:"shop_id"`
-	CreatedAt   pgtype.Timestamptz `json:"created_at"`
-	CreatedAt_2 pgtype.Timestamptz `json:"created_at_2"`
-	Limit       int32              `json:"limit"`
+	ShopID  int64              `json:"shop_id"`
+	StartAt pgtype.Timestamptz `json:"start_at"`
+	EndAt   pgtype.Timestamptz `json:"end_at"`
+	SortBy  string             `json:"sort_by"`
+	Limit   int32              `json:"limit"`
 }
 
 type GetTopProductsRow struct {
-	ProductVariationID int64       `json:"product_variation_id"`
-	ProductName        string      `json:"product_name"`
-	UnitsSold          interface{} `json:"units_sold"`
-	Revenue            interface{} `json:"revenue"`
+	ProductVariationID int64          `json:"product_variation_id"`
+	ProductName        string         `json:"product_name"`
+	UnitsSold          interface{}    `json:"units_sold"`
+	Revenue            interface{}    `json:"revenue"`
+	CostOfGoods        pgtype.Numeric `json:"cost_of_goods"`
+	GrossProfit        pgtype.Numeric `json:"gross_profit"`
 }
 
+// Ranks the variants sold by units sold, or by gross profit when sort_by is 'profit'.
+// Profit only counts the items whose cost price was known when they were sold.
 func (q *Queries) GetTopProducts(ctx context.Context, arg GetTopProductsParams) ([]GetTopProductsRow, error) {
 	rows, err := q.db.Query(ctx, getTopProducts,
 		arg.ShopID,
-		arg.CreatedAt,
-		arg.CreatedAt_2,
+		arg.StartAt,
+		arg.EndAt,
+		arg.SortBy,
 		arg.Limit,
 	)
 	if err != nil {
@@ -268,6 +510,8 @@ func (q *Queries) GetTopProducts(ctx context.Context, arg GetTopProductsParams)
 			&i.ProductName,
 			&i.UnitsSold,
 			&i.Revenue,
+			&i.CostOfGoods,
+			&i.GrossProfit,
 		); err != nil {
 			return nil, err
 		}
@@ -401,7 +401,7 @@ DO UPDATE SET
     seo_description = EXCLUDED.seo_description,
     seo_keywords = EXCLUDED.seo_keywords,
     seo_title = EXCLUDED.seo_title
-RETURNING product_variation_id, sku, description, price, available_quantity, seo_description, seo_keywords, seo_title, is_default, created_at, updated_at, product_id, shop_id
+RETURNING product_variation_id, sku, description, price, available_quantity, seo_description, seo_keywords, seo_title, is_default, created_at, updated_at, product_id, shop_id, cost_price
 `
 
 type UpsertProductVariantsBatchResults struct {
@@ -476,6 +476,7 @@ func (b *UpsertProductVariantsBatchResults) Query(f func(int, []ProductVariation
 					&i.UpdatedAt,
 					&i.ProductID,
 					&i.ShopID,
+					&i.CostPrice,
 				); err != nil {
 					return err
 				}
@@ -12,7 +12,7 @@ import (
 const createCategory = `-- name: CreateCategory :one
 INSERT INTO categories (slug, title, description, parent_id, shop_id)
 VALUES ($1, $2, $3, $4, $5)
-RETURNING category_id, slug, title, description, parent_id, shop_id
+RETURNING category_id, slug, title, description, parent_id, shop_id, banner_url
 `
 
 type CreateCategoryParams struct {
@@ -39,6 +39,7 @@ func (q *Queries) CreateCategory(ctx context.Context, arg CreateCategoryParams)
 		&i.Description,
 		&i.ParentID,
 		&i.ShopID,
+		&i.BannerUrl,
 	)
 	return i, err
 }
@@ -89,7 +90,7 @@ func (q *Queries) GetCategories(ctx context.Context, arg GetCategoriesParams) ([
 }
 
 const getCategory = `-- name: GetCategory :one
-SELECT category_id, slug, title, description, parent_id
+SELECT category_id, slug, title, description, parent_id, banner_url
 FROM categories
 WHERE shop_id = $1 AND category_id = $2
 `
@@ -105,6 +106,7 @@ type GetCategoryRow struct {
 	Title       string  `json:"title"`
 	Description *string `json:"description"`
 	ParentID    *int64  `json:"parent_id"`
+	BannerUrl   *string `json:"banner_url"`
 }
 
 func (q *Queries) GetCategory(ctx context.Context, arg GetCategoryParams) (GetCategoryRow, error) {
@@ -116,6 +118,7 @@ func (q *Queries) GetCategory(ctx context.Context, arg GetCategoryParams) (GetCa
 		&i.Title,
 		&i.Description,
 		&i.ParentID,
+		&i.BannerUrl,
 	)
 	return i, err
 }
@@ -178,7 +181,7 @@ SET
     description = COALESCE($2, description),
     parent_id = COALESCE($3, parent_id)
 WHERE category_id = $4
-RETURNING category_id, slug, title, description, parent_id, shop_id
+RETURNING category_id, slug, title, description, parent_id, shop_id, banner_url
 `
 
 type UpdateCategoryParams struct {
@@ -203,6 +206,35 @@ func (q *Queries) UpdateCategory(ctx context.Context, arg UpdateCategoryParams)
 		&i.Description,
 		&i.ParentID,
 		&i.ShopID,
+		&i.BannerUrl,
+	)
+	return i, err
+}
+
+const updateCategoryBanner = `-- name: UpdateCategoryBanner :one
+UPDATE categories
+SET banner_url = $1
+WHERE category_id = $2 AND shop_id = $3
+RETURNING category_id, slug, title, description, parent_id, shop_id, banner_url
+`
+
+type UpdateCategoryBannerParams struct {
+	BannerUrl  *string `json:"banner_url"`
+	CategoryID int64   `json:"category_id"`
+	ShopID     int64   `json:"shop_id"`
+}
+
+func (q *Queries) UpdateCategoryBanner(ctx context.Context, arg UpdateCategoryBannerParams) (Category, error) {
+	row := q.db.QueryRow(ctx, updateCategoryBanner, arg.BannerUrl, arg.CategoryID, arg.ShopID)
+	var i Category
+	err := row.Scan(
+		&i.CategoryID,
+		&i.Slug,
+		&i.Title,
+		&i.Description,
+		&i.ParentID,
+		&i.ShopID,
+		&i.BannerUrl,
 	)
 	return i, err
 }
@@ -0,0 +1,9 @@
+-- Create "product_revisions" table
+CREATE TABLE product_revisions ("product_revision_id" bigserial NOT NULL, "version" integer NOT NULL, "snapshot" jsonb NOT NULL, "created_by" character varying(255) NULL, "restored_from_version" integer NULL, "created_at" timestamptz NOT NULL DEFAULT now(), "product_id" bigint NOT NULL, "shop_id" bigint NOT NULL, PRIMARY KEY ("product_revision_id"), CONSTRAINT "product_revisions_product_id_version_key" UNIQUE ("product_id", "version"), CONSTRAINT "fk_product" FOREIGN KEY ("product_id") REFERENCES products ("product_id") ON UPDATE NO ACTION ON DELETE CASCADE, CONSTRAINT "fk_shop" FOREIGN KEY ("shop_id") REFERENCES shops ("shop_id") ON UPDATE NO ACTION ON DELETE CASCADE);
+
+-- SET RLS for product_revisions
+ALTER TABLE product_revisions ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON product_revisions
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
@@ -0,0 +1,11 @@
+-- Modify "categories" table
+ALTER TABLE "categories" ADD COLUMN "banner_url" text NULL;
+-- Create "image_assets" table
+CREATE TABLE image_assets ("image_asset_id" bigserial NOT NULL, "storage_key" text NOT NULL, "url" text NOT NULL, "content_type" character varying(50) NOT NULL, "width" integer NOT NULL, "height" integer NOT NULL, "size_bytes" bigint NOT NULL, "renditions" jsonb NOT NULL DEFAULT '[]', "created_at" timestamptz NOT NULL DEFAULT now(), "shop_id" bigint NOT NULL, PRIMARY KEY ("image_asset_id"), CONSTRAINT "image_assets_url_key" UNIQUE ("url"), CONSTRAINT "fk_shop" FOREIGN KEY ("shop_id") REFERENCES shops ("shop_id") ON UPDATE NO ACTION ON DELETE CASCADE);
+
+-- SET RLS for image_assets
+ALTER TABLE image_assets ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON image_assets
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
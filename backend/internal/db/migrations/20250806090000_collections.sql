@@ -0,0 +1,22 @@
+-- Create enum type "collection_type"
+CREATE TYPE collection_type AS ENUM ('manual', 'smart');
+-- Create "collections" table
+CREATE TABLE collections ("collection_id" bigserial NOT NULL, "handle" character varying(100) NOT NULL, "title" character varying(100) NOT NULL, "description" text NULL, "collection_type" collection_type NOT NULL DEFAULT 'manual', "rules" jsonb NOT NULL DEFAULT '[]', "match_all" boolean NOT NULL DEFAULT true, "sort_order" character varying(20) NOT NULL DEFAULT 'manual', "created_at" timestamptz NOT NULL DEFAULT now(), "updated_at" timestamptz NOT NULL DEFAULT now(), "shop_id" bigint NOT NULL, PRIMARY KEY ("collection_id"), CONSTRAINT "collections_handle_shop_id_key" UNIQUE ("handle", "shop_id"), CONSTRAINT "fk_shop" FOREIGN KEY ("shop_id") REFERENCES shops ("shop_id") ON UPDATE NO ACTION ON DELETE CASCADE);
+-- Create "collection_products" table
+CREATE TABLE collection_products ("collection_id" bigint NOT NULL, "product_id" bigint NOT NULL, "position" integer NOT NULL, "created_at" timestamptz NOT NULL DEFAULT now(), "shop_id" bigint NOT NULL, PRIMARY KEY ("collection_id", "product_id"), CONSTRAINT "fk_collection" FOREIGN KEY ("collection_id") REFERENCES collections ("collection_id") ON UPDATE NO ACTION ON DELETE CASCADE, CONSTRAINT "fk_product" FOREIGN KEY ("product_id") REFERENCES products ("product_id") ON UPDATE NO ACTION ON DELETE CASCADE, CONSTRAINT "fk_shop" FOREIGN KEY ("shop_id") REFERENCES shops ("shop_id") ON UPDATE NO ACTION ON DELETE CASCADE);
+-- Create index "idx_collection_products_position" to table: "collection_products"
+CREATE INDEX idx_collection_products_position ON collection_products ("collection_id", "position");
+
+-- SET RLS for collections
+ALTER TABLE collections ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON collections
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
+
+-- SET RLS for collection_products
+ALTER TABLE collection_products ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON collection_products
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
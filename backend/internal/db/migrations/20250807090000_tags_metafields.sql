@@ -0,0 +1,33 @@
+-- Create "product_tags" table
+CREATE TABLE product_tags ("product_id" bigint NOT NULL, "tag" character varying(255) NOT NULL, "created_at" timestamptz NOT NULL DEFAULT now(), "shop_id" bigint NOT NULL, PRIMARY KEY ("product_id", "tag"), CONSTRAINT "fk_product" FOREIGN KEY ("product_id") REFERENCES products ("product_id") ON UPDATE NO ACTION ON DELETE CASCADE, CONSTRAINT "fk_shop" FOREIGN KEY ("shop_id") REFERENCES shops ("shop_id") ON UPDATE NO ACTION ON DELETE CASCADE);
+-- Create index "idx_product_tags_shop_tag" to table: "product_tags"
+CREATE INDEX idx_product_tags_shop_tag ON product_tags ("shop_id", (lower((tag)::text)));
+-- Create enum type "metafield_owner_type"
+CREATE TYPE metafield_owner_type AS ENUM ('product', 'product_variation', 'category', 'shop', 'customer');
+-- Create enum type "metafield_type"
+CREATE TYPE metafield_type AS ENUM ('single_line_text', 'multi_line_text', 'integer', 'decimal', 'boolean', 'date', 'date_time', 'json', 'url', 'color');
+-- Create "metafield_definitions" table
+CREATE TABLE metafield_definitions ("metafield_definition_id" bigserial NOT NULL, "owner_type" metafield_owner_type NOT NULL, "namespace" character varying(64) NOT NULL, "key" character varying(64) NOT NULL, "name" character varying(255) NOT NULL, "description" text NULL, "type" metafield_type NOT NULL, "validations" jsonb NOT NULL DEFAULT '{}', "created_at" timestamptz NOT NULL DEFAULT now(), "updated_at" timestamptz NOT NULL DEFAULT now(), "shop_id" bigint NOT NULL, PRIMARY KEY ("metafield_definition_id"), CONSTRAINT "metafield_definitions_owner_type_namespace_key_shop_id_key" UNIQUE ("owner_type", "namespace", "key", "shop_id"), CONSTRAINT "fk_shop" FOREIGN KEY ("shop_id") REFERENCES shops ("shop_id") ON UPDATE NO ACTION ON DELETE CASCADE);
+-- Create "metafields" table
+CREATE TABLE metafields ("metafield_id" bigserial NOT NULL, "owner_type" metafield_owner_type NOT NULL, "owner_id" character varying(64) NOT NULL, "namespace" character varying(64) NOT NULL, "key" character varying(64) NOT NULL, "type" metafield_type NOT NULL, "value" text NOT NULL, "created_at" timestamptz NOT NULL DEFAULT now(), "updated_at" timestamptz NOT NULL DEFAULT now(), "shop_id" bigint NOT NULL, PRIMARY KEY ("metafield_id"), CONSTRAINT "metafields_owner_type_owner_id_namespace_key_shop_id_key" UNIQUE ("owner_type", "owner_id", "namespace", "key", "shop_id"), CONSTRAINT "fk_shop" FOREIGN KEY ("shop_id") REFERENCES shops ("shop_id") ON UPDATE NO ACTION ON DELETE CASCADE);
+
+-- SET RLS for product_tags
+ALTER TABLE product_tags ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON product_tags
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
+
+-- SET RLS for metafield_definitions
+ALTER TABLE metafield_definitions ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON metafield_definitions
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
+
+-- SET RLS for metafields
+ALTER TABLE metafields ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON metafields
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
@@ -0,0 +1,22 @@
+-- Create "shop_locales" table
+CREATE TABLE shop_locales ("locale" character varying(20) NOT NULL, "is_default" boolean NOT NULL DEFAULT false, "created_at" timestamptz NOT NULL DEFAULT now(), "shop_id" bigint NOT NULL, PRIMARY KEY ("shop_id", "locale"), CONSTRAINT "fk_shop" FOREIGN KEY ("shop_id") REFERENCES shops ("shop_id") ON UPDATE NO ACTION ON DELETE CASCADE);
+-- Create index "idx_shop_locales_default" to table: "shop_locales"
+CREATE UNIQUE INDEX idx_shop_locales_default ON shop_locales ("shop_id") WHERE is_default;
+-- Create enum type "translatable_resource_type"
+CREATE TYPE translatable_resource_type AS ENUM ('product', 'product_variation', 'category', 'shop', 'collection');
+-- Create "translations" table
+CREATE TABLE translations ("translation_id" bigserial NOT NULL, "resource_type" translatable_resource_type NOT NULL, "resource_id" bigint NOT NULL, "field" character varying(50) NOT NULL, "locale" character varying(20) NOT NULL, "value" text NOT NULL, "created_at" timestamptz NOT NULL DEFAULT now(), "updated_at" timestamptz NOT NULL DEFAULT now(), "shop_id" bigint NOT NULL, PRIMARY KEY ("translation_id"), CONSTRAINT "translations_resource_type_resource_id_field_locale_shop_id_key" UNIQUE ("resource_type", "resource_id", "field", "locale", "shop_id"), CONSTRAINT "fk_shop" FOREIGN KEY ("shop_id") REFERENCES shops ("shop_id") ON UPDATE NO ACTION ON DELETE CASCADE);
+
+-- SET RLS for shop_locales
+ALTER TABLE shop_locales ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON shop_locales
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
+
+-- SET RLS for translations
+ALTER TABLE translations ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON translations
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
@@ -0,0 +1,27 @@
+-- Create "shop_currencies" table
+CREATE TABLE shop_currencies ("currency_code" character varying(3) NOT NULL, "exchange_rate" numeric(18,8) NULL, "created_at" timestamptz NOT NULL DEFAULT now(), "updated_at" timestamptz NOT NULL DEFAULT now(), "shop_id" bigint NOT NULL, PRIMARY KEY ("shop_id", "currency_code"), CONSTRAINT "fk_shop" FOREIGN KEY ("shop_id") REFERENCES shops ("shop_id") ON UPDATE NO ACTION ON DELETE CASCADE);
+-- Create "product_variation_prices" table
+CREATE TABLE product_variation_prices ("product_variation_id" bigint NOT NULL, "currency_code" character varying(3) NOT NULL, "price" numeric(10,2) NOT NULL, "updated_at" timestamptz NOT NULL DEFAULT now(), "shop_id" bigint NOT NULL, PRIMARY KEY ("product_variation_id", "currency_code"), CONSTRAINT "fk_product_variation" FOREIGN KEY ("product_variation_id") REFERENCES product_variations ("product_variation_id") ON UPDATE NO ACTION ON DELETE CASCADE, CONSTRAINT "fk_shop" FOREIGN KEY ("shop_id") REFERENCES shops ("shop_id") ON UPDATE NO ACTION ON DELETE CASCADE);
+-- Create "order_presentments" table
+CREATE TABLE order_presentments ("order_id" bigint NOT NULL, "currency_code" character varying(3) NOT NULL, "exchange_rate" numeric(18,8) NOT NULL, "amount" numeric(10,2) NOT NULL, "created_at" timestamptz NOT NULL DEFAULT now(), "shop_id" bigint NOT NULL, PRIMARY KEY ("order_id"), CONSTRAINT "fk_order" FOREIGN KEY ("order_id") REFERENCES orders ("order_id") ON UPDATE NO ACTION ON DELETE CASCADE, CONSTRAINT "fk_shop" FOREIGN KEY ("shop_id") REFERENCES shops ("shop_id") ON UPDATE NO ACTION ON DELETE CASCADE);
+
+-- SET RLS for shop_currencies
+ALTER TABLE shop_currencies ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON shop_currencies
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
+
+-- SET RLS for product_variation_prices
+ALTER TABLE product_variation_prices ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON product_variation_prices
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
+
+-- SET RLS for order_presentments
+ALTER TABLE order_presentments ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON order_presentments
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
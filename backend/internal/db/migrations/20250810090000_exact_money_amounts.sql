@@ -0,0 +1,25 @@
+-- Drop "daily_sales" materialized view, which depends on "orders"."amount"
+DROP INDEX IF EXISTS idx_daily_sales_shop_day;
+DROP MATERIALIZED VIEW IF EXISTS daily_sales;
+-- Modify "orders" table
+ALTER TABLE orders ALTER COLUMN "amount" TYPE numeric(19,4), ALTER COLUMN "discount" TYPE numeric(19,4), ALTER COLUMN "shipping_cost" TYPE numeric(19,4), ALTER COLUMN "tax" TYPE numeric(19,4);
+-- Modify "order_items" table
+ALTER TABLE order_items ALTER COLUMN "price" TYPE numeric(19,4);
+-- Modify "order_presentments" table
+ALTER TABLE order_presentments ALTER COLUMN "amount" TYPE numeric(19,4);
+-- Order amounts used to be written in cents into columns that hold major units
+UPDATE orders SET "amount" = "amount" / 100, "discount" = "discount" / 100, "shipping_cost" = "shipping_cost" / 100, "tax" = "tax" / 100;
+UPDATE order_items SET "price" = "price" / 100;
+UPDATE order_presentments SET "amount" = "amount" / 100;
+-- Recreate "daily_sales" materialized view over the corrected amounts
+CREATE MATERIALIZED VIEW daily_sales AS
+SELECT
+  shop_id,
+  DATE(created_at) AS day,
+  COUNT(*) AS total_orders,
+  SUM(amount) AS revenue
+FROM orders
+WHERE status = 'completed'
+GROUP BY shop_id, day;
+CREATE UNIQUE INDEX idx_daily_sales_shop_day ON daily_sales(shop_id, day);
+REFRESH MATERIALIZED VIEW daily_sales;
@@ -0,0 +1,24 @@
+-- Create enum type "review_status"
+CREATE TYPE review_status AS ENUM ('pending', 'approved', 'rejected');
+-- Create "product_reviews" table
+CREATE TABLE product_reviews ("review_id" bigserial NOT NULL, "product_id" bigint NOT NULL, "product_variation_id" bigint NOT NULL, "order_id" bigint NOT NULL, "rating" smallint NOT NULL, "title" character varying(255) NULL, "body" text NULL, "photos" text[] NOT NULL DEFAULT '{}', "customer_name" character varying(100) NOT NULL, "customer_email" character varying(100) NOT NULL, "status" review_status NOT NULL DEFAULT 'pending', "merchant_reply" text NULL, "replied_at" timestamptz NULL, "created_at" timestamptz NOT NULL DEFAULT now(), "updated_at" timestamptz NOT NULL DEFAULT now(), "shop_id" bigint NOT NULL, PRIMARY KEY ("review_id"), CONSTRAINT "product_reviews_order_id_product_variation_id_key" UNIQUE ("order_id", "product_variation_id"), CONSTRAINT "fk_order" FOREIGN KEY ("order_id") REFERENCES orders ("order_id") ON UPDATE NO ACTION ON DELETE CASCADE, CONSTRAINT "fk_product" FOREIGN KEY ("product_id") REFERENCES products ("product_id") ON UPDATE NO ACTION ON DELETE CASCADE, CONSTRAINT "fk_product_variation" FOREIGN KEY ("product_variation_id") REFERENCES product_variations ("product_variation_id") ON UPDATE NO ACTION ON DELETE CASCADE, CONSTRAINT "fk_shop" FOREIGN KEY ("shop_id") REFERENCES shops ("shop_id") ON UPDATE NO ACTION ON DELETE CASCADE, CONSTRAINT "product_reviews_rating_check" CHECK ((rating >= 1) AND (rating <= 5)));
+-- Create index "idx_product_reviews_product_status" to table: "product_reviews"
+CREATE INDEX idx_product_reviews_product_status ON product_reviews ("product_id", "status");
+-- Create index "idx_product_reviews_shop_status" to table: "product_reviews"
+CREATE INDEX idx_product_reviews_shop_status ON product_reviews ("shop_id", "status", "created_at");
+-- Create "product_ratings" table
+CREATE TABLE product_ratings ("product_id" bigint NOT NULL, "rating_count" integer NOT NULL DEFAULT 0, "rating_sum" integer NOT NULL DEFAULT 0, "updated_at" timestamptz NOT NULL DEFAULT now(), "shop_id" bigint NOT NULL, PRIMARY KEY ("product_id"), CONSTRAINT "fk_product" FOREIGN KEY ("product_id") REFERENCES products ("product_id") ON UPDATE NO ACTION ON DELETE CASCADE, CONSTRAINT "fk_shop" FOREIGN KEY ("shop_id") REFERENCES shops ("shop_id") ON UPDATE NO ACTION ON DELETE CASCADE);
+
+-- SET RLS for product_reviews
+ALTER TABLE product_reviews ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON product_reviews
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
+
+-- SET RLS for product_ratings
+ALTER TABLE product_ratings ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON product_ratings
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
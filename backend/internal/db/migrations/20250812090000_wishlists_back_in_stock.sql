@@ -0,0 +1,33 @@
+-- Create "wishlist_items" table
+CREATE TABLE wishlist_items ("shop_customer_id" uuid NOT NULL, "product_variation_id" bigint NOT NULL, "created_at" timestamptz NOT NULL DEFAULT now(), "shop_id" bigint NOT NULL, PRIMARY KEY ("shop_customer_id", "product_variation_id"), CONSTRAINT "fk_product_variation" FOREIGN KEY ("product_variation_id") REFERENCES product_variations ("product_variation_id") ON UPDATE NO ACTION ON DELETE CASCADE, CONSTRAINT "fk_shop" FOREIGN KEY ("shop_id") REFERENCES shops ("shop_id") ON UPDATE NO ACTION ON DELETE CASCADE, CONSTRAINT "fk_shop_customer" FOREIGN KEY ("shop_customer_id") REFERENCES shop_customers ("shop_customer_id") ON UPDATE NO ACTION ON DELETE CASCADE);
+-- Create "back_in_stock_subscriptions" table
+CREATE TABLE back_in_stock_subscriptions ("subscription_id" bigserial NOT NULL, "product_variation_id" bigint NOT NULL, "email" character varying(255) NOT NULL, "shop_customer_id" uuid NULL, "notified_at" timestamptz NULL, "created_at" timestamptz NOT NULL DEFAULT now(), "shop_id" bigint NOT NULL, PRIMARY KEY ("subscription_id"), CONSTRAINT "back_in_stock_subscriptions_product_variation_id_email_key" UNIQUE ("product_variation_id", "email"), CONSTRAINT "fk_product_variation" FOREIGN KEY ("product_variation_id") REFERENCES product_variations ("product_variation_id") ON UPDATE NO ACTION ON DELETE CASCADE, CONSTRAINT "fk_shop" FOREIGN KEY ("shop_id") REFERENCES shops ("shop_id") ON UPDATE NO ACTION ON DELETE CASCADE, CONSTRAINT "fk_shop_customer" FOREIGN KEY ("shop_customer_id") REFERENCES shop_customers ("shop_customer_id") ON UPDATE NO ACTION ON DELETE SET NULL);
+-- Create index "idx_back_in_stock_subscriptions_pending" to table: "back_in_stock_subscriptions"
+CREATE INDEX idx_back_in_stock_subscriptions_pending ON back_in_stock_subscriptions ("product_variation_id") WHERE (notified_at IS NULL);
+-- Create enum type "notification_status"
+CREATE TYPE notification_status AS ENUM ('pending', 'sent', 'failed');
+-- Create "notifications" table
+CREATE TABLE notifications ("notification_id" bigserial NOT NULL, "kind" character varying(50) NOT NULL, "recipient" character varying(255) NOT NULL, "payload" jsonb NOT NULL DEFAULT '{}', "status" notification_status NOT NULL DEFAULT 'pending', "attempts" integer NOT NULL DEFAULT 0, "created_at" timestamptz NOT NULL DEFAULT now(), "sent_at" timestamptz NULL, "shop_id" bigint NOT NULL, PRIMARY KEY ("notification_id"), CONSTRAINT "fk_shop" FOREIGN KEY ("shop_id") REFERENCES shops ("shop_id") ON UPDATE NO ACTION ON DELETE CASCADE);
+-- Create index "idx_notifications_pending" to table: "notifications"
+CREATE INDEX idx_notifications_pending ON notifications ("created_at") WHERE (status = 'pending'::notification_status);
+
+-- SET RLS for wishlist_items
+ALTER TABLE wishlist_items ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON wishlist_items
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
+
+-- SET RLS for back_in_stock_subscriptions
+ALTER TABLE back_in_stock_subscriptions ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON back_in_stock_subscriptions
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
+
+-- SET RLS for notifications
+ALTER TABLE notifications ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON notifications
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
@@ -0,0 +1,17 @@
+-- Create "customer_addresses" table
+CREATE TABLE customer_addresses ("address_id" bigserial NOT NULL, "shop_customer_id" uuid NOT NULL, "first_name" character varying(100) NOT NULL, "last_name" character varying(100) NOT NULL, "company" character varying(255) NULL, "address_line_1" character varying(255) NOT NULL, "address_line_2" character varying(255) NULL, "city" character varying(100) NOT NULL, "state" character varying(100) NULL, "postal_code" character varying(20) NOT NULL, "country" character(2) NOT NULL, "phone" character varying(50) NULL, "is_default_shipping" boolean NOT NULL DEFAULT false, "is_default_billing" boolean NOT NULL DEFAULT false, "created_at" timestamptz NOT NULL DEFAULT now(), "updated_at" timestamptz NOT NULL DEFAULT now(), "shop_id" bigint NOT NULL, PRIMARY KEY ("address_id"), CONSTRAINT "fk_shop" FOREIGN KEY ("shop_id") REFERENCES shops ("shop_id") ON UPDATE NO ACTION ON DELETE CASCADE, CONSTRAINT "fk_shop_customer" FOREIGN KEY ("shop_customer_id") REFERENCES shop_customers ("shop_customer_id") ON UPDATE NO ACTION ON DELETE CASCADE);
+-- Create index "idx_customer_addresses_customer" to table: "customer_addresses"
+CREATE INDEX idx_customer_addresses_customer ON customer_addresses ("shop_customer_id");
+-- Create index "idx_customer_addresses_default_shipping" to table: "customer_addresses"
+CREATE UNIQUE INDEX idx_customer_addresses_default_shipping ON customer_addresses ("shop_customer_id") WHERE is_default_shipping;
+-- Create index "idx_customer_addresses_default_billing" to table: "customer_addresses"
+CREATE UNIQUE INDEX idx_customer_addresses_default_billing ON customer_addresses ("shop_customer_id") WHERE is_default_billing;
+-- Modify "orders" table
+ALTER TABLE orders ALTER COLUMN "shipping_address" TYPE jsonb USING (CASE WHEN shipping_address ~ '^\s*\{' THEN shipping_address::jsonb ELSE jsonb_build_object('address_line_1', shipping_address) END);
+
+-- SET RLS for customer_addresses
+ALTER TABLE customer_addresses ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON customer_addresses
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
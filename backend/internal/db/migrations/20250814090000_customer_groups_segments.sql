@@ -0,0 +1,38 @@
+-- Create "customer_groups" table
+CREATE TABLE customer_groups ("group_id" bigserial NOT NULL, "name" character varying(100) NOT NULL, "description" text NULL, "discount_percent" numeric(5,2) NULL, "created_at" timestamptz NOT NULL DEFAULT now(), "updated_at" timestamptz NOT NULL DEFAULT now(), "shop_id" bigint NOT NULL, PRIMARY KEY ("group_id"), CONSTRAINT "customer_groups_shop_id_name_key" UNIQUE ("shop_id", "name"), CONSTRAINT "fk_shop" FOREIGN KEY ("shop_id") REFERENCES shops ("shop_id") ON UPDATE NO ACTION ON DELETE CASCADE, CONSTRAINT "customer_groups_discount_percent_check" CHECK ((discount_percent > (0)::numeric) AND (discount_percent <= (100)::numeric)));
+-- Create "customer_group_members" table
+CREATE TABLE customer_group_members ("group_id" bigint NOT NULL, "shop_customer_id" uuid NOT NULL, "created_at" timestamptz NOT NULL DEFAULT now(), "shop_id" bigint NOT NULL, PRIMARY KEY ("group_id", "shop_customer_id"), CONSTRAINT "fk_customer_group" FOREIGN KEY ("group_id") REFERENCES customer_groups ("group_id") ON UPDATE NO ACTION ON DELETE CASCADE, CONSTRAINT "fk_shop" FOREIGN KEY ("shop_id") REFERENCES shops ("shop_id") ON UPDATE NO ACTION ON DELETE CASCADE, CONSTRAINT "fk_shop_customer" FOREIGN KEY ("shop_customer_id") REFERENCES shop_customers ("shop_customer_id") ON UPDATE NO ACTION ON DELETE CASCADE);
+-- Create index "idx_customer_group_members_customer" to table: "customer_group_members"
+CREATE INDEX idx_customer_group_members_customer ON customer_group_members ("shop_customer_id");
+-- Create "customer_group_prices" table
+CREATE TABLE customer_group_prices ("group_id" bigint NOT NULL, "product_variation_id" bigint NOT NULL, "price" numeric(10,2) NOT NULL, "updated_at" timestamptz NOT NULL DEFAULT now(), "shop_id" bigint NOT NULL, PRIMARY KEY ("group_id", "product_variation_id"), CONSTRAINT "fk_customer_group" FOREIGN KEY ("group_id") REFERENCES customer_groups ("group_id") ON UPDATE NO ACTION ON DELETE CASCADE, CONSTRAINT "fk_product_variation" FOREIGN KEY ("product_variation_id") REFERENCES product_variations ("product_variation_id") ON UPDATE NO ACTION ON DELETE CASCADE, CONSTRAINT "fk_shop" FOREIGN KEY ("shop_id") REFERENCES shops ("shop_id") ON UPDATE NO ACTION ON DELETE CASCADE, CONSTRAINT "customer_group_prices_price_check" CHECK (price >= (0)::numeric));
+-- Create "customer_segments" table
+CREATE TABLE customer_segments ("segment_id" bigserial NOT NULL, "name" character varying(100) NOT NULL, "description" text NULL, "min_total_spent" numeric(19,4) NULL, "max_total_spent" numeric(19,4) NULL, "min_order_count" integer NULL, "max_order_count" integer NULL, "window_days" integer NULL, "inactive_days" integer NULL, "created_at" timestamptz NOT NULL DEFAULT now(), "updated_at" timestamptz NOT NULL DEFAULT now(), "shop_id" bigint NOT NULL, PRIMARY KEY ("segment_id"), CONSTRAINT "customer_segments_shop_id_name_key" UNIQUE ("shop_id", "name"), CONSTRAINT "fk_shop" FOREIGN KEY ("shop_id") REFERENCES shops ("shop_id") ON UPDATE NO ACTION ON DELETE CASCADE);
+
+-- SET RLS for customer_groups
+ALTER TABLE customer_groups ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON customer_groups
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
+
+-- SET RLS for customer_group_members
+ALTER TABLE customer_group_members ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON customer_group_members
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
+
+-- SET RLS for customer_group_prices
+ALTER TABLE customer_group_prices ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON customer_group_prices
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
+
+-- SET RLS for customer_segments
+ALTER TABLE customer_segments ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON customer_segments
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
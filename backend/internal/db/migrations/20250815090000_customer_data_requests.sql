@@ -0,0 +1,15 @@
+-- Modify "orders" table
+ALTER TABLE orders DROP CONSTRAINT "fk_shop_customer", ADD CONSTRAINT "fk_shop_customer" FOREIGN KEY ("shop_customer_id") REFERENCES shop_customers ("shop_customer_id") ON UPDATE NO ACTION ON DELETE SET NULL;
+-- Create enum type "customer_data_request_kind"
+CREATE TYPE customer_data_request_kind AS ENUM ('export', 'erasure');
+-- Create "customer_data_requests" table
+CREATE TABLE customer_data_requests ("request_id" bigserial NOT NULL, "kind" customer_data_request_kind NOT NULL, "shop_customer_id" uuid NOT NULL, "requested_by" character varying(255) NULL, "details" jsonb NOT NULL DEFAULT '{}', "created_at" timestamptz NOT NULL DEFAULT now(), "shop_id" bigint NOT NULL, PRIMARY KEY ("request_id"), CONSTRAINT "fk_shop" FOREIGN KEY ("shop_id") REFERENCES shops ("shop_id") ON UPDATE NO ACTION ON DELETE CASCADE);
+-- Create index "idx_customer_data_requests_shop" to table: "customer_data_requests"
+CREATE INDEX idx_customer_data_requests_shop ON customer_data_requests ("shop_id", "created_at");
+
+-- SET RLS for customer_data_requests
+ALTER TABLE customer_data_requests ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON customer_data_requests
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
@@ -0,0 +1,39 @@
+-- Modify "product_types" table
+ALTER TABLE product_types ADD COLUMN "gift_card" boolean NOT NULL DEFAULT false;
+-- Create enum type "credit_transaction_kind"
+CREATE TYPE credit_transaction_kind AS ENUM ('issue', 'redeem', 'refund', 'adjust');
+-- Create "gift_cards" table
+CREATE TABLE gift_cards ("gift_card_id" bigserial NOT NULL, "code" character varying(32) NOT NULL, "initial_amount" numeric(19,4) NOT NULL, "balance" numeric(19,4) NOT NULL, "currency_code" character varying(3) NOT NULL, "expires_at" timestamptz NULL, "disabled" boolean NOT NULL DEFAULT false, "note" text NULL, "recipient_email" character varying(255) NULL, "shop_customer_id" uuid NULL, "order_id" bigint NULL, "order_item_id" bigint NULL, "order_item_unit" bigint NULL, "created_by" character varying(255) NULL, "created_at" timestamptz NOT NULL DEFAULT now(), "updated_at" timestamptz NOT NULL DEFAULT now(), "shop_id" bigint NOT NULL, PRIMARY KEY ("gift_card_id"), CONSTRAINT "gift_cards_shop_id_code_key" UNIQUE ("shop_id", "code"), CONSTRAINT "gift_cards_order_item_id_order_item_unit_key" UNIQUE ("order_item_id", "order_item_unit"), CONSTRAINT "fk_order" FOREIGN KEY ("order_id") REFERENCES orders ("order_id") ON UPDATE NO ACTION ON DELETE SET NULL, CONSTRAINT "fk_order_item" FOREIGN KEY ("order_item_id") REFERENCES order_items ("order_item_id") ON UPDATE NO ACTION ON DELETE SET NULL, CONSTRAINT "fk_shop" FOREIGN KEY ("shop_id") REFERENCES shops ("shop_id") ON UPDATE NO ACTION ON DELETE CASCADE, CONSTRAINT "fk_shop_customer" FOREIGN KEY ("shop_customer_id") REFERENCES shop_customers ("shop_customer_id") ON UPDATE NO ACTION ON DELETE SET NULL, CONSTRAINT "gift_cards_balance_check" CHECK (balance >= (0)::numeric));
+-- Create "gift_card_transactions" table
+CREATE TABLE gift_card_transactions ("transaction_id" bigserial NOT NULL, "gift_card_id" bigint NOT NULL, "kind" credit_transaction_kind NOT NULL, "amount" numeric(19,4) NOT NULL, "balance_after" numeric(19,4) NOT NULL, "order_id" bigint NULL, "note" text NULL, "created_by" character varying(255) NULL, "created_at" timestamptz NOT NULL DEFAULT now(), "shop_id" bigint NOT NULL, PRIMARY KEY ("transaction_id"), CONSTRAINT "fk_gift_card" FOREIGN KEY ("gift_card_id") REFERENCES gift_cards ("gift_card_id") ON UPDATE NO ACTION ON DELETE CASCADE, CONSTRAINT "fk_order" FOREIGN KEY ("order_id") REFERENCES orders ("order_id") ON UPDATE NO ACTION ON DELETE SET NULL, CONSTRAINT "fk_shop" FOREIGN KEY ("shop_id") REFERENCES shops ("shop_id") ON UPDATE NO ACTION ON DELETE CASCADE);
+-- Create index "idx_gift_card_transactions_card" to table: "gift_card_transactions"
+CREATE INDEX idx_gift_card_transactions_card ON gift_card_transactions ("gift_card_id", "created_at");
+-- Create index "idx_gift_card_transactions_order" to table: "gift_card_transactions"
+CREATE INDEX idx_gift_card_transactions_order ON gift_card_transactions ("order_id");
+-- Create "store_credit_transactions" table
+CREATE TABLE store_credit_transactions ("transaction_id" bigserial NOT NULL, "shop_customer_id" uuid NOT NULL, "kind" credit_transaction_kind NOT NULL, "amount" numeric(19,4) NOT NULL, "balance_after" numeric(19,4) NOT NULL, "reason" character varying(50) NULL, "order_id" bigint NULL, "note" text NULL, "created_by" character varying(255) NULL, "created_at" timestamptz NOT NULL DEFAULT now(), "shop_id" bigint NOT NULL, PRIMARY KEY ("transaction_id"), CONSTRAINT "fk_order" FOREIGN KEY ("order_id") REFERENCES orders ("order_id") ON UPDATE NO ACTION ON DELETE SET NULL, CONSTRAINT "fk_shop" FOREIGN KEY ("shop_id") REFERENCES shops ("shop_id") ON UPDATE NO ACTION ON DELETE CASCADE, CONSTRAINT "fk_shop_customer" FOREIGN KEY ("shop_customer_id") REFERENCES shop_customers ("shop_customer_id") ON UPDATE NO ACTION ON DELETE CASCADE, CONSTRAINT "store_credit_transactions_balance_after_check" CHECK (balance_after >= (0)::numeric));
+-- Create index "idx_store_credit_transactions_customer" to table: "store_credit_transactions"
+CREATE INDEX idx_store_credit_transactions_customer ON store_credit_transactions ("shop_customer_id", "transaction_id");
+-- Create index "idx_store_credit_transactions_order" to table: "store_credit_transactions"
+CREATE INDEX idx_store_credit_transactions_order ON store_credit_transactions ("order_id");
+
+-- SET RLS for gift_cards
+ALTER TABLE gift_cards ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON gift_cards
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
+
+-- SET RLS for gift_card_transactions
+ALTER TABLE gift_card_transactions ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON gift_card_transactions
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
+
+-- SET RLS for store_credit_transactions
+ALTER TABLE store_credit_transactions ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON store_credit_transactions
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
@@ -0,0 +1,24 @@
+-- Create enum type "loyalty_transaction_kind"
+CREATE TYPE loyalty_transaction_kind AS ENUM ('earn', 'redeem', 'refund', 'clawback', 'expire', 'adjust');
+-- Create "loyalty_programs" table
+CREATE TABLE loyalty_programs ("shop_id" bigint NOT NULL, "enabled" boolean NOT NULL DEFAULT false, "earn_rate" numeric(10,4) NOT NULL DEFAULT 1, "burn_rate" numeric(10,4) NOT NULL DEFAULT 100, "min_redeem_points" integer NOT NULL DEFAULT 0, "expiry_days" integer NULL, "created_at" timestamptz NOT NULL DEFAULT now(), "updated_at" timestamptz NOT NULL DEFAULT now(), PRIMARY KEY ("shop_id"), CONSTRAINT "fk_shop" FOREIGN KEY ("shop_id") REFERENCES shops ("shop_id") ON UPDATE NO ACTION ON DELETE CASCADE, CONSTRAINT "loyalty_programs_burn_rate_check" CHECK (burn_rate > (0)::numeric), CONSTRAINT "loyalty_programs_earn_rate_check" CHECK (earn_rate >= (0)::numeric), CONSTRAINT "loyalty_programs_expiry_days_check" CHECK (expiry_days > 0), CONSTRAINT "loyalty_programs_min_redeem_points_check" CHECK (min_redeem_points >= 0));
+-- Create "loyalty_transactions" table
+CREATE TABLE loyalty_transactions ("transaction_id" bigserial NOT NULL, "shop_customer_id" uuid NOT NULL, "kind" loyalty_transaction_kind NOT NULL, "points" integer NOT NULL, "balance_after" integer NOT NULL, "order_id" bigint NULL, "note" text NULL, "created_by" character varying(255) NULL, "created_at" timestamptz NOT NULL DEFAULT now(), "shop_id" bigint NOT NULL, PRIMARY KEY ("transaction_id"), CONSTRAINT "fk_order" FOREIGN KEY ("order_id") REFERENCES orders ("order_id") ON UPDATE NO ACTION ON DELETE SET NULL, CONSTRAINT "fk_shop" FOREIGN KEY ("shop_id") REFERENCES shops ("shop_id") ON UPDATE NO ACTION ON DELETE CASCADE, CONSTRAINT "fk_shop_customer" FOREIGN KEY ("shop_customer_id") REFERENCES shop_customers ("shop_customer_id") ON UPDATE NO ACTION ON DELETE CASCADE, CONSTRAINT "loyalty_transactions_balance_after_check" CHECK (balance_after >= 0));
+-- Create index "idx_loyalty_transactions_customer" to table: "loyalty_transactions"
+CREATE INDEX idx_loyalty_transactions_customer ON loyalty_transactions ("shop_customer_id", "transaction_id");
+-- Create index "idx_loyalty_transactions_order" to table: "loyalty_transactions"
+CREATE INDEX idx_loyalty_transactions_order ON loyalty_transactions ("order_id");
+
+-- SET RLS for loyalty_programs
+ALTER TABLE loyalty_programs ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON loyalty_programs
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
+
+-- SET RLS for loyalty_transactions
+ALTER TABLE loyalty_transactions ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON loyalty_transactions
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
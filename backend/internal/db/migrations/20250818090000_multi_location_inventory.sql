@@ -0,0 +1,59 @@
+-- Create enum type "inventory_transfer_status"
+CREATE TYPE inventory_transfer_status AS ENUM ('in_transit', 'received', 'cancelled');
+-- Create "locations" table
+CREATE TABLE locations ("location_id" bigserial NOT NULL, "name" character varying(100) NOT NULL, "address" text NULL, "sells_online" boolean NOT NULL DEFAULT true, "is_default" boolean NOT NULL DEFAULT false, "created_at" timestamptz NOT NULL DEFAULT now(), "updated_at" timestamptz NOT NULL DEFAULT now(), "shop_id" bigint NOT NULL, PRIMARY KEY ("location_id"), CONSTRAINT "locations_shop_id_name_key" UNIQUE ("shop_id", "name"), CONSTRAINT "fk_shop" FOREIGN KEY ("shop_id") REFERENCES shops ("shop_id") ON UPDATE NO ACTION ON DELETE CASCADE);
+-- Create index "locations_shop_id_default_key" to table: "locations"
+CREATE UNIQUE INDEX locations_shop_id_default_key ON locations ("shop_id") WHERE is_default;
+-- Create "inventory_levels" table
+CREATE TABLE inventory_levels ("product_variation_id" bigint NOT NULL, "location_id" bigint NOT NULL, "available" bigint NOT NULL DEFAULT 0, "incoming" bigint NOT NULL DEFAULT 0, "updated_at" timestamptz NOT NULL DEFAULT now(), "shop_id" bigint NOT NULL, PRIMARY KEY ("product_variation_id", "location_id"), CONSTRAINT "fk_location" FOREIGN KEY ("location_id") REFERENCES locations ("location_id") ON UPDATE NO ACTION ON DELETE CASCADE, CONSTRAINT "fk_product_variation" FOREIGN KEY ("product_variation_id") REFERENCES product_variations ("product_variation_id") ON UPDATE NO ACTION ON DELETE CASCADE, CONSTRAINT "fk_shop" FOREIGN KEY ("shop_id") REFERENCES shops ("shop_id") ON UPDATE NO ACTION ON DELETE CASCADE, CONSTRAINT "inventory_levels_available_check" CHECK (available >= 0), CONSTRAINT "inventory_levels_incoming_check" CHECK (incoming >= 0));
+-- Create index "idx_inventory_levels_location" to table: "inventory_levels"
+CREATE INDEX idx_inventory_levels_location ON inventory_levels ("location_id");
+-- Modify "stock_movements" table
+ALTER TABLE stock_movements ADD COLUMN "location_id" bigint NULL, ADD CONSTRAINT "fk_location" FOREIGN KEY ("location_id") REFERENCES locations ("location_id") ON UPDATE NO ACTION ON DELETE SET NULL;
+-- Create "inventory_transfers" table
+CREATE TABLE inventory_transfers ("transfer_id" bigserial NOT NULL, "origin_location_id" bigint NOT NULL, "destination_location_id" bigint NOT NULL, "status" inventory_transfer_status NOT NULL DEFAULT 'in_transit', "note" text NULL, "created_by" character varying(255) NULL, "created_at" timestamptz NOT NULL DEFAULT now(), "updated_at" timestamptz NOT NULL DEFAULT now(), "received_at" timestamptz NULL, "shop_id" bigint NOT NULL, PRIMARY KEY ("transfer_id"), CONSTRAINT "fk_destination_location" FOREIGN KEY ("destination_location_id") REFERENCES locations ("location_id") ON UPDATE NO ACTION ON DELETE CASCADE, CONSTRAINT "fk_origin_location" FOREIGN KEY ("origin_location_id") REFERENCES locations ("location_id") ON UPDATE NO ACTION ON DELETE CASCADE, CONSTRAINT "fk_shop" FOREIGN KEY ("shop_id") REFERENCES shops ("shop_id") ON UPDATE NO ACTION ON DELETE CASCADE, CONSTRAINT "inventory_transfers_check" CHECK (origin_location_id <> destination_location_id));
+-- Create index "idx_inventory_transfers_shop" to table: "inventory_transfers"
+CREATE INDEX idx_inventory_transfers_shop ON inventory_transfers ("shop_id", "created_at");
+-- Create "inventory_transfer_items" table
+CREATE TABLE inventory_transfer_items ("transfer_id" bigint NOT NULL, "product_variation_id" bigint NOT NULL, "quantity" bigint NOT NULL, "shop_id" bigint NOT NULL, PRIMARY KEY ("transfer_id", "product_variation_id"), CONSTRAINT "fk_inventory_transfer" FOREIGN KEY ("transfer_id") REFERENCES inventory_transfers ("transfer_id") ON UPDATE NO ACTION ON DELETE CASCADE, CONSTRAINT "fk_product_variation" FOREIGN KEY ("product_variation_id") REFERENCES product_variations ("product_variation_id") ON UPDATE NO ACTION ON DELETE CASCADE, CONSTRAINT "fk_shop" FOREIGN KEY ("shop_id") REFERENCES shops ("shop_id") ON UPDATE NO ACTION ON DELETE CASCADE, CONSTRAINT "inventory_transfer_items_quantity_check" CHECK (quantity > 0));
+-- Create "order_fulfillments" table
+CREATE TABLE order_fulfillments ("fulfillment_id" bigserial NOT NULL, "order_id" bigint NOT NULL, "location_id" bigint NULL, "created_by" character varying(255) NULL, "created_at" timestamptz NOT NULL DEFAULT now(), "shop_id" bigint NOT NULL, PRIMARY KEY ("fulfillment_id"), CONSTRAINT "order_fulfillments_order_id_key" UNIQUE ("order_id"), CONSTRAINT "fk_location" FOREIGN KEY ("location_id") REFERENCES locations ("location_id") ON UPDATE NO ACTION ON DELETE SET NULL, CONSTRAINT "fk_order" FOREIGN KEY ("order_id") REFERENCES orders ("order_id") ON UPDATE NO ACTION ON DELETE CASCADE, CONSTRAINT "fk_shop" FOREIGN KEY ("shop_id") REFERENCES shops ("shop_id") ON UPDATE NO ACTION ON DELETE CASCADE);
+-- Every shop starts with a default location that holds the stock it had so far
+INSERT INTO locations ("name", "is_default", "shop_id") SELECT 'Default location', true, "shop_id" FROM shops;
+INSERT INTO inventory_levels ("product_variation_id", "location_id", "available", "shop_id") SELECT pv."product_variation_id", l."location_id", GREATEST(pv."available_quantity", 0), pv."shop_id" FROM product_variations pv JOIN locations l ON l."shop_id" = pv."shop_id" AND l."is_default";
+UPDATE stock_movements sm SET "location_id" = l."location_id" FROM locations l WHERE l."shop_id" = sm."shop_id" AND l."is_default";
+
+-- SET RLS for locations
+ALTER TABLE locations ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON locations
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
+
+-- SET RLS for inventory_levels
+ALTER TABLE inventory_levels ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON inventory_levels
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
+
+-- SET RLS for inventory_transfers
+ALTER TABLE inventory_transfers ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON inventory_transfers
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
+
+-- SET RLS for inventory_transfer_items
+ALTER TABLE inventory_transfer_items ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON inventory_transfer_items
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
+
+-- SET RLS for order_fulfillments
+ALTER TABLE order_fulfillments ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON order_fulfillments
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
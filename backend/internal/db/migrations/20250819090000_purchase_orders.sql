@@ -0,0 +1,35 @@
+-- Create enum type "purchase_order_status"
+CREATE TYPE purchase_order_status AS ENUM ('draft', 'ordered', 'partially_received', 'received');
+-- Modify "product_variations" table
+ALTER TABLE product_variations ADD COLUMN "cost_price" numeric(19,4) NULL;
+-- Create "suppliers" table
+CREATE TABLE suppliers ("supplier_id" bigserial NOT NULL, "name" character varying(100) NOT NULL, "contact_name" character varying(100) NULL, "email" character varying(255) NULL, "phone" character varying(50) NULL, "address" text NULL, "notes" text NULL, "created_at" timestamptz NOT NULL DEFAULT now(), "updated_at" timestamptz NOT NULL DEFAULT now(), "shop_id" bigint NOT NULL, PRIMARY KEY ("supplier_id"), CONSTRAINT "suppliers_shop_id_name_key" UNIQUE ("shop_id", "name"), CONSTRAINT "fk_shop" FOREIGN KEY ("shop_id") REFERENCES shops ("shop_id") ON UPDATE NO ACTION ON DELETE CASCADE);
+-- Create "purchase_orders" table
+CREATE TABLE purchase_orders ("purchase_order_id" bigserial NOT NULL, "supplier_id" bigint NOT NULL, "location_id" bigint NULL, "status" purchase_order_status NOT NULL DEFAULT 'draft', "expected_at" timestamptz NULL, "note" text NULL, "created_by" character varying(255) NULL, "ordered_at" timestamptz NULL, "received_at" timestamptz NULL, "created_at" timestamptz NOT NULL DEFAULT now(), "updated_at" timestamptz NOT NULL DEFAULT now(), "shop_id" bigint NOT NULL, PRIMARY KEY ("purchase_order_id"), CONSTRAINT "fk_location" FOREIGN KEY ("location_id") REFERENCES locations ("location_id") ON UPDATE NO ACTION ON DELETE SET NULL, CONSTRAINT "fk_shop" FOREIGN KEY ("shop_id") REFERENCES shops ("shop_id") ON UPDATE NO ACTION ON DELETE CASCADE, CONSTRAINT "fk_supplier" FOREIGN KEY ("supplier_id") REFERENCES suppliers ("supplier_id") ON UPDATE NO ACTION ON DELETE RESTRICT);
+-- Create index "idx_purchase_orders_shop" to table: "purchase_orders"
+CREATE INDEX idx_purchase_orders_shop ON purchase_orders ("shop_id", "created_at");
+-- Create index "idx_purchase_orders_supplier" to table: "purchase_orders"
+CREATE INDEX idx_purchase_orders_supplier ON purchase_orders ("supplier_id");
+-- Create "purchase_order_items" table
+CREATE TABLE purchase_order_items ("purchase_order_item_id" bigserial NOT NULL, "purchase_order_id" bigint NOT NULL, "product_variation_id" bigint NOT NULL, "quantity_ordered" bigint NOT NULL, "quantity_received" bigint NOT NULL DEFAULT 0, "unit_cost" numeric(19,4) NOT NULL, "shop_id" bigint NOT NULL, PRIMARY KEY ("purchase_order_item_id"), CONSTRAINT "purchase_order_items_purchase_order_id_product_variation_id_key" UNIQUE ("purchase_order_id", "product_variation_id"), CONSTRAINT "fk_product_variation" FOREIGN KEY ("product_variation_id") REFERENCES product_variations ("product_variation_id") ON UPDATE NO ACTION ON DELETE CASCADE, CONSTRAINT "fk_purchase_order" FOREIGN KEY ("purchase_order_id") REFERENCES purchase_orders ("purchase_order_id") ON UPDATE NO ACTION ON DELETE CASCADE, CONSTRAINT "fk_shop" FOREIGN KEY ("shop_id") REFERENCES shops ("shop_id") ON UPDATE NO ACTION ON DELETE CASCADE, CONSTRAINT "purchase_order_items_quantity_ordered_check" CHECK (quantity_ordered > 0), CONSTRAINT "purchase_order_items_quantity_received_check" CHECK ((quantity_received >= 0) AND (quantity_received <= quantity_ordered)), CONSTRAINT "purchase_order_items_unit_cost_check" CHECK (unit_cost >= (0)::numeric));
+
+-- SET RLS for suppliers
+ALTER TABLE suppliers ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON suppliers
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
+
+-- SET RLS for purchase_orders
+ALTER TABLE purchase_orders ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON purchase_orders
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
+
+-- SET RLS for purchase_order_items
+ALTER TABLE purchase_order_items ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON purchase_order_items
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
@@ -0,0 +1,2 @@
+-- Modify "order_items" table
+ALTER TABLE order_items ADD COLUMN "cost_price" numeric(19,4) NULL;
@@ -0,0 +1,22 @@
+-- Create enum type "stocktake_status"
+CREATE TYPE stocktake_status AS ENUM ('open', 'committed', 'cancelled');
+-- Create "stocktakes" table
+CREATE TABLE stocktakes ("stocktake_id" bigserial NOT NULL, "location_id" bigint NOT NULL, "category_id" bigint NULL, "product_type_id" bigint NULL, "status" stocktake_status NOT NULL DEFAULT 'open', "note" text NULL, "created_by" character varying(255) NULL, "committed_at" timestamptz NULL, "created_at" timestamptz NOT NULL DEFAULT now(), "updated_at" timestamptz NOT NULL DEFAULT now(), "shop_id" bigint NOT NULL, PRIMARY KEY ("stocktake_id"), CONSTRAINT "fk_category" FOREIGN KEY ("category_id") REFERENCES categories ("category_id") ON UPDATE NO ACTION ON DELETE SET NULL, CONSTRAINT "fk_location" FOREIGN KEY ("location_id") REFERENCES locations ("location_id") ON UPDATE NO ACTION ON DELETE CASCADE, CONSTRAINT "fk_product_type" FOREIGN KEY ("product_type_id") REFERENCES product_types ("product_type_id") ON UPDATE NO ACTION ON DELETE SET NULL, CONSTRAINT "fk_shop" FOREIGN KEY ("shop_id") REFERENCES shops ("shop_id") ON UPDATE NO ACTION ON DELETE CASCADE);
+-- Create index "idx_stocktakes_shop" to table: "stocktakes"
+CREATE INDEX idx_stocktakes_shop ON stocktakes ("shop_id", "created_at");
+-- Create "stocktake_items" table
+CREATE TABLE stocktake_items ("stocktake_item_id" bigserial NOT NULL, "stocktake_id" bigint NOT NULL, "product_variation_id" bigint NOT NULL, "expected_quantity" bigint NOT NULL DEFAULT 0, "counted_quantity" bigint NULL, "counted_at" timestamptz NULL, "shop_id" bigint NOT NULL, PRIMARY KEY ("stocktake_item_id"), CONSTRAINT "stocktake_items_stocktake_id_product_variation_id_key" UNIQUE ("stocktake_id", "product_variation_id"), CONSTRAINT "fk_product_variation" FOREIGN KEY ("product_variation_id") REFERENCES product_variations ("product_variation_id") ON UPDATE NO ACTION ON DELETE CASCADE, CONSTRAINT "fk_shop" FOREIGN KEY ("shop_id") REFERENCES shops ("shop_id") ON UPDATE NO ACTION ON DELETE CASCADE, CONSTRAINT "fk_stocktake" FOREIGN KEY ("stocktake_id") REFERENCES stocktakes ("stocktake_id") ON UPDATE NO ACTION ON DELETE CASCADE, CONSTRAINT "stocktake_items_counted_quantity_check" CHECK (counted_quantity >= 0));
+
+-- SET RLS for stocktakes
+ALTER TABLE stocktakes ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON stocktakes
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
+
+-- SET RLS for stocktake_items
+ALTER TABLE stocktake_items ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON stocktake_items
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
@@ -0,0 +1,13 @@
+-- Modify "product_types" table
+ALTER TABLE product_types ADD COLUMN "tracks_lots" boolean NOT NULL DEFAULT false;
+-- Create "inventory_lots" table
+CREATE TABLE inventory_lots ("lot_id" bigserial NOT NULL, "product_variation_id" bigint NOT NULL, "location_id" bigint NOT NULL, "lot_number" character varying(100) NOT NULL, "expires_on" date NULL, "quantity" bigint NOT NULL DEFAULT 0, "received_at" timestamptz NOT NULL DEFAULT now(), "updated_at" timestamptz NOT NULL DEFAULT now(), "shop_id" bigint NOT NULL, PRIMARY KEY ("lot_id"), CONSTRAINT "inventory_lots_product_variation_id_location_id_lot_number_key" UNIQUE ("product_variation_id", "location_id", "lot_number"), CONSTRAINT "fk_location" FOREIGN KEY ("location_id") REFERENCES locations ("location_id") ON UPDATE NO ACTION ON DELETE CASCADE, CONSTRAINT "fk_product_variation" FOREIGN KEY ("product_variation_id") REFERENCES product_variations ("product_variation_id") ON UPDATE NO ACTION ON DELETE CASCADE, CONSTRAINT "fk_shop" FOREIGN KEY ("shop_id") REFERENCES shops ("shop_id") ON UPDATE NO ACTION ON DELETE CASCADE, CONSTRAINT "inventory_lots_quantity_check" CHECK (quantity >= 0));
+-- Create index "idx_inventory_lots_expiry" to table: "inventory_lots"
+CREATE INDEX idx_inventory_lots_expiry ON inventory_lots ("shop_id", "expires_on");
+
+-- SET RLS for inventory_lots
+ALTER TABLE inventory_lots ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON inventory_lots
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
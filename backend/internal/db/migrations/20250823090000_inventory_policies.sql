@@ -0,0 +1,23 @@
+-- Create enum type "inventory_policy"
+CREATE TYPE inventory_policy AS ENUM ('deny', 'backorder', 'preorder');
+-- Modify "product_variations" table
+ALTER TABLE product_variations ADD COLUMN "inventory_policy" inventory_policy NOT NULL DEFAULT 'deny', ADD COLUMN "preorder_ships_on" date NULL, ADD COLUMN "preorder_limit" bigint NULL, ADD CONSTRAINT "product_variations_preorder_limit_check" CHECK (preorder_limit >= 0);
+-- Modify "order_items" table
+ALTER TABLE order_items ADD COLUMN "backordered_quantity" bigint NOT NULL DEFAULT 0;
+-- Create "open_order_items" view
+CREATE VIEW open_order_items WITH (security_invoker = true) AS SELECT oi.order_item_id, oi.order_id, oi.product_variation_id, oi.quantity, oi.backordered_quantity, o.created_at AS ordered_at, oi.shop_id FROM order_items oi JOIN orders o ON o.order_id = oi.order_id WHERE o.status IN ('pending', 'processing') AND NOT EXISTS (SELECT 1 FROM order_fulfillments f WHERE f.order_id = o.order_id);
+-- Create "variant_stock_status" function
+CREATE FUNCTION variant_stock_status(variant product_variations) RETURNS text LANGUAGE sql STABLE AS $$
+    SELECT CASE
+        WHEN variant.available_quantity > open.quantity THEN 'IN_STOCK'
+        WHEN variant.inventory_policy = 'backorder' THEN 'BACKORDER'
+        WHEN variant.inventory_policy = 'preorder'
+            AND (variant.preorder_limit IS NULL OR variant.preorder_limit > open.backordered_quantity) THEN 'PREORDER'
+        ELSE 'OUT_OF_STOCK'
+    END
+    FROM (
+        SELECT COALESCE(SUM(quantity), 0) AS quantity, COALESCE(SUM(backordered_quantity), 0) AS backordered_quantity
+        FROM open_order_items
+        WHERE product_variation_id = variant.product_variation_id
+    ) open
+$$;
@@ -0,0 +1,17 @@
+-- Modify "product_types" table
+ALTER TABLE product_types ADD COLUMN "reorder_point" bigint NULL, ADD COLUMN "reorder_quantity" bigint NULL, ADD CONSTRAINT "product_types_reorder_point_check" CHECK (reorder_point >= 0), ADD CONSTRAINT "product_types_reorder_quantity_check" CHECK (reorder_quantity > 0);
+-- Modify "product_variations" table
+ALTER TABLE product_variations ADD COLUMN "reorder_point" bigint NULL, ADD COLUMN "reorder_quantity" bigint NULL, ADD CONSTRAINT "product_variations_reorder_point_check" CHECK (reorder_point >= 0), ADD CONSTRAINT "product_variations_reorder_quantity_check" CHECK (reorder_quantity > 0);
+-- Create enum type "stock_alert_kind"
+CREATE TYPE stock_alert_kind AS ENUM ('low_stock', 'out_of_stock');
+-- Create "stock_alerts" table
+CREATE TABLE stock_alerts ("alert_id" bigserial NOT NULL, "product_variation_id" bigint NOT NULL, "kind" stock_alert_kind NOT NULL, "available_quantity" bigint NOT NULL, "reorder_point" bigint NOT NULL, "reorder_quantity" bigint NULL, "read_at" timestamptz NULL, "created_at" timestamptz NOT NULL DEFAULT now(), "shop_id" bigint NOT NULL, PRIMARY KEY ("alert_id"), CONSTRAINT "fk_product_variation" FOREIGN KEY ("product_variation_id") REFERENCES product_variations ("product_variation_id") ON UPDATE NO ACTION ON DELETE CASCADE, CONSTRAINT "fk_shop" FOREIGN KEY ("shop_id") REFERENCES shops ("shop_id") ON UPDATE NO ACTION ON DELETE CASCADE);
+-- Create index "idx_stock_alerts_shop" to table: "stock_alerts"
+CREATE INDEX idx_stock_alerts_shop ON stock_alerts ("shop_id", "created_at");
+
+-- SET RLS for stock_alerts
+ALTER TABLE stock_alerts ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON stock_alerts
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
@@ -0,0 +1,11 @@
+-- Create "bundle_components" table
+CREATE TABLE bundle_components ("bundle_variation_id" bigint NOT NULL, "component_variation_id" bigint NOT NULL, "quantity" bigint NOT NULL, "shop_id" bigint NOT NULL, PRIMARY KEY ("bundle_variation_id", "component_variation_id"), CONSTRAINT "fk_bundle_variation" FOREIGN KEY ("bundle_variation_id") REFERENCES product_variations ("product_variation_id") ON UPDATE NO ACTION ON DELETE CASCADE, CONSTRAINT "fk_component_variation" FOREIGN KEY ("component_variation_id") REFERENCES product_variations ("product_variation_id") ON UPDATE NO ACTION ON DELETE CASCADE, CONSTRAINT "fk_shop" FOREIGN KEY ("shop_id") REFERENCES shops ("shop_id") ON UPDATE NO ACTION ON DELETE CASCADE, CONSTRAINT "bundle_components_check" CHECK (bundle_variation_id <> component_variation_id), CONSTRAINT "bundle_components_quantity_check" CHECK (quantity > 0));
+-- Create index "idx_bundle_components_component" to table: "bundle_components"
+CREATE INDEX idx_bundle_components_component ON bundle_components ("component_variation_id");
+
+-- SET RLS for bundle_components
+ALTER TABLE bundle_components ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON bundle_components
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
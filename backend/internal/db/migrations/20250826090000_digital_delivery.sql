@@ -0,0 +1,48 @@
+-- Create enum type "digital_asset_kind"
+CREATE TYPE digital_asset_kind AS ENUM ('file', 'license_key');
+-- Create "digital_assets" table
+CREATE TABLE digital_assets ("digital_asset_id" bigserial NOT NULL, "product_variation_id" bigint NOT NULL, "kind" digital_asset_kind NOT NULL, "title" character varying(255) NOT NULL, "storage_key" text NULL, "file_name" character varying(255) NULL, "content_type" character varying(255) NULL, "size_bytes" bigint NULL, "download_limit" integer NULL, "expires_after_days" integer NULL, "created_at" timestamptz NOT NULL DEFAULT now(), "updated_at" timestamptz NOT NULL DEFAULT now(), "shop_id" bigint NOT NULL, PRIMARY KEY ("digital_asset_id"), CONSTRAINT "fk_product_variation" FOREIGN KEY ("product_variation_id") REFERENCES product_variations ("product_variation_id") ON UPDATE NO ACTION ON DELETE CASCADE, CONSTRAINT "fk_shop" FOREIGN KEY ("shop_id") REFERENCES shops ("shop_id") ON UPDATE NO ACTION ON DELETE CASCADE, CONSTRAINT "digital_assets_check" CHECK ((kind <> 'file'::digital_asset_kind) OR (storage_key IS NOT NULL)), CONSTRAINT "digital_assets_download_limit_check" CHECK (download_limit > 0), CONSTRAINT "digital_assets_expires_after_days_check" CHECK (expires_after_days > 0));
+-- Create index "idx_digital_assets_variation" to table: "digital_assets"
+CREATE INDEX idx_digital_assets_variation ON digital_assets ("product_variation_id");
+-- Create "license_keys" table
+CREATE TABLE license_keys ("license_key_id" bigserial NOT NULL, "digital_asset_id" bigint NOT NULL, "license_key" character varying(255) NOT NULL, "allocated_at" timestamptz NULL, "created_at" timestamptz NOT NULL DEFAULT now(), "shop_id" bigint NOT NULL, PRIMARY KEY ("license_key_id"), CONSTRAINT "license_keys_digital_asset_id_license_key_key" UNIQUE ("digital_asset_id", "license_key"), CONSTRAINT "fk_digital_asset" FOREIGN KEY ("digital_asset_id") REFERENCES digital_assets ("digital_asset_id") ON UPDATE NO ACTION ON DELETE CASCADE, CONSTRAINT "fk_shop" FOREIGN KEY ("shop_id") REFERENCES shops ("shop_id") ON UPDATE NO ACTION ON DELETE CASCADE);
+-- Create index "idx_license_keys_available" to table: "license_keys"
+CREATE INDEX idx_license_keys_available ON license_keys ("digital_asset_id", "license_key_id") WHERE (allocated_at IS NULL);
+-- Create "digital_downloads" table
+CREATE TABLE digital_downloads ("download_id" bigserial NOT NULL, "order_id" bigint NOT NULL, "order_item_id" bigint NOT NULL, "digital_asset_id" bigint NOT NULL, "license_key_id" bigint NULL, "download_count" integer NOT NULL DEFAULT 0, "download_limit" integer NULL, "expires_at" timestamptz NULL, "created_at" timestamptz NOT NULL DEFAULT now(), "shop_id" bigint NOT NULL, PRIMARY KEY ("download_id"), CONSTRAINT "digital_downloads_license_key_id_key" UNIQUE ("license_key_id"), CONSTRAINT "fk_order" FOREIGN KEY ("order_id") REFERENCES orders ("order_id") ON UPDATE NO ACTION ON DELETE CASCADE, CONSTRAINT "fk_order_item" FOREIGN KEY ("order_item_id") REFERENCES order_items ("order_item_id") ON UPDATE NO ACTION ON DELETE CASCADE, CONSTRAINT "fk_digital_asset" FOREIGN KEY ("digital_asset_id") REFERENCES digital_assets ("digital_asset_id") ON UPDATE NO ACTION ON DELETE NO ACTION, CONSTRAINT "fk_license_key" FOREIGN KEY ("license_key_id") REFERENCES license_keys ("license_key_id") ON UPDATE NO ACTION ON DELETE NO ACTION, CONSTRAINT "fk_shop" FOREIGN KEY ("shop_id") REFERENCES shops ("shop_id") ON UPDATE NO ACTION ON DELETE CASCADE);
+-- Create index "idx_digital_downloads_order" to table: "digital_downloads"
+CREATE INDEX idx_digital_downloads_order ON digital_downloads ("order_id");
+-- Create index "idx_digital_downloads_asset" to table: "digital_downloads"
+CREATE INDEX idx_digital_downloads_asset ON digital_downloads ("digital_asset_id");
+-- Create "digital_download_events" table
+CREATE TABLE digital_download_events ("event_id" bigserial NOT NULL, "download_id" bigint NOT NULL, "ip_address" character varying(45) NULL, "user_agent" text NULL, "created_at" timestamptz NOT NULL DEFAULT now(), "shop_id" bigint NOT NULL, PRIMARY KEY ("event_id"), CONSTRAINT "fk_download" FOREIGN KEY ("download_id") REFERENCES digital_downloads ("download_id") ON UPDATE NO ACTION ON DELETE CASCADE, CONSTRAINT "fk_shop" FOREIGN KEY ("shop_id") REFERENCES shops ("shop_id") ON UPDATE NO ACTION ON DELETE CASCADE);
+-- Create index "idx_digital_download_events_download" to table: "digital_download_events"
+CREATE INDEX idx_digital_download_events_download ON digital_download_events ("download_id", "created_at");
+
+-- SET RLS for digital_assets
+ALTER TABLE digital_assets ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON digital_assets
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
+
+-- SET RLS for license_keys
+ALTER TABLE license_keys ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON license_keys
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
+
+-- SET RLS for digital_downloads
+ALTER TABLE digital_downloads ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON digital_downloads
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
+
+-- SET RLS for digital_download_events
+ALTER TABLE digital_download_events ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON digital_download_events
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
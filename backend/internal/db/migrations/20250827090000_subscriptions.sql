@@ -0,0 +1,54 @@
+-- Create enum type "subscription_interval"
+CREATE TYPE subscription_interval AS ENUM ('day', 'week', 'month', 'year');
+-- Create enum type "subscription_status"
+CREATE TYPE subscription_status AS ENUM ('trialing', 'active', 'paused', 'past_due', 'cancelled');
+-- Create enum type "subscription_renewal_status"
+CREATE TYPE subscription_renewal_status AS ENUM ('paid', 'failed', 'skipped');
+-- Create "subscription_plans" table
+CREATE TABLE subscription_plans ("plan_id" bigserial NOT NULL, "product_variation_id" bigint NOT NULL, "name" character varying(255) NOT NULL, "interval_unit" subscription_interval NOT NULL, "interval_count" integer NOT NULL DEFAULT 1, "discount_percent" numeric(5,2) NOT NULL DEFAULT 0, "trial_days" integer NOT NULL DEFAULT 0, "active" boolean NOT NULL DEFAULT true, "created_at" timestamptz NOT NULL DEFAULT now(), "updated_at" timestamptz NOT NULL DEFAULT now(), "shop_id" bigint NOT NULL, PRIMARY KEY ("plan_id"), CONSTRAINT "fk_product_variation" FOREIGN KEY ("product_variation_id") REFERENCES product_variations ("product_variation_id") ON UPDATE NO ACTION ON DELETE CASCADE, CONSTRAINT "fk_shop" FOREIGN KEY ("shop_id") REFERENCES shops ("shop_id") ON UPDATE NO ACTION ON DELETE CASCADE, CONSTRAINT "subscription_plans_interval_count_check" CHECK (interval_count > 0), CONSTRAINT "subscription_plans_discount_percent_check" CHECK ((discount_percent >= (0)::numeric) AND (discount_percent <= (100)::numeric)), CONSTRAINT "subscription_plans_trial_days_check" CHECK (trial_days >= 0));
+-- Create index "idx_subscription_plans_variation" to table: "subscription_plans"
+CREATE INDEX idx_subscription_plans_variation ON subscription_plans ("product_variation_id");
+-- Create "customer_payment_methods" table
+CREATE TABLE customer_payment_methods ("customer_payment_method_id" bigserial NOT NULL, "shop_customer_id" uuid NOT NULL, "provider" payment_method_type NOT NULL, "customer_reference" character varying(255) NOT NULL, "token" character varying(255) NOT NULL, "brand" character varying(50) NULL, "last4" character varying(4) NULL, "exp_month" integer NULL, "exp_year" integer NULL, "created_at" timestamptz NOT NULL DEFAULT now(), "shop_id" bigint NOT NULL, PRIMARY KEY ("customer_payment_method_id"), CONSTRAINT "customer_payment_methods_provider_token_shop_id_key" UNIQUE ("provider", "token", "shop_id"), CONSTRAINT "fk_shop_customer" FOREIGN KEY ("shop_customer_id") REFERENCES shop_customers ("shop_customer_id") ON UPDATE NO ACTION ON DELETE CASCADE, CONSTRAINT "fk_shop" FOREIGN KEY ("shop_id") REFERENCES shops ("shop_id") ON UPDATE NO ACTION ON DELETE CASCADE);
+-- Create index "idx_customer_payment_methods_customer" to table: "customer_payment_methods"
+CREATE INDEX idx_customer_payment_methods_customer ON customer_payment_methods ("shop_customer_id");
+-- Create "subscriptions" table
+CREATE TABLE subscriptions ("subscription_id" bigserial NOT NULL, "shop_customer_id" uuid NOT NULL, "plan_id" bigint NOT NULL, "quantity" bigint NOT NULL DEFAULT 1, "status" subscription_status NOT NULL, "customer_payment_method_id" bigint NOT NULL, "shipping_address" jsonb NOT NULL, "shipping_method" character varying(10) NOT NULL, "next_renewal_at" timestamptz NOT NULL, "paused_until" timestamptz NULL, "pending_order_id" bigint NULL, "failed_attempts" integer NOT NULL DEFAULT 0, "next_retry_at" timestamptz NULL, "claimed_until" timestamptz NULL, "cancelled_at" timestamptz NULL, "cancel_reason" text NULL, "created_at" timestamptz NOT NULL DEFAULT now(), "updated_at" timestamptz NOT NULL DEFAULT now(), "shop_id" bigint NOT NULL, PRIMARY KEY ("subscription_id"), CONSTRAINT "fk_shop_customer" FOREIGN KEY ("shop_customer_id") REFERENCES shop_customers ("shop_customer_id") ON UPDATE NO ACTION ON DELETE CASCADE, CONSTRAINT "fk_plan" FOREIGN KEY ("plan_id") REFERENCES subscription_plans ("plan_id") ON UPDATE NO ACTION ON DELETE NO ACTION, CONSTRAINT "fk_customer_payment_method" FOREIGN KEY ("customer_payment_method_id") REFERENCES customer_payment_methods ("customer_payment_method_id") ON UPDATE NO ACTION ON DELETE NO ACTION, CONSTRAINT "fk_pending_order" FOREIGN KEY ("pending_order_id") REFERENCES orders ("order_id") ON UPDATE NO ACTION ON DELETE SET NULL, CONSTRAINT "fk_shop" FOREIGN KEY ("shop_id") REFERENCES shops ("shop_id") ON UPDATE NO ACTION ON DELETE CASCADE, CONSTRAINT "subscriptions_quantity_check" CHECK (quantity > 0));
+-- Create index "idx_subscriptions_customer" to table: "subscriptions"
+CREATE INDEX idx_subscriptions_customer ON subscriptions ("shop_customer_id");
+-- Create index "idx_subscriptions_due" to table: "subscriptions"
+CREATE INDEX idx_subscriptions_due ON subscriptions ((COALESCE(next_retry_at, next_renewal_at))) WHERE (status = ANY (ARRAY['trialing'::subscription_status, 'active'::subscription_status, 'past_due'::subscription_status]));
+-- Create index "idx_subscriptions_paused" to table: "subscriptions"
+CREATE INDEX idx_subscriptions_paused ON subscriptions ("paused_until") WHERE (status = 'paused'::subscription_status);
+-- Create "subscription_renewals" table
+CREATE TABLE subscription_renewals ("renewal_id" bigserial NOT NULL, "subscription_id" bigint NOT NULL, "order_id" bigint NULL, "status" subscription_renewal_status NOT NULL, "renewal_date" timestamptz NOT NULL, "payment_reference" text NULL, "error" text NULL, "created_at" timestamptz NOT NULL DEFAULT now(), "shop_id" bigint NOT NULL, PRIMARY KEY ("renewal_id"), CONSTRAINT "fk_subscription" FOREIGN KEY ("subscription_id") REFERENCES subscriptions ("subscription_id") ON UPDATE NO ACTION ON DELETE CASCADE, CONSTRAINT "fk_order" FOREIGN KEY ("order_id") REFERENCES orders ("order_id") ON UPDATE NO ACTION ON DELETE SET NULL, CONSTRAINT "fk_shop" FOREIGN KEY ("shop_id") REFERENCES shops ("shop_id") ON UPDATE NO ACTION ON DELETE CASCADE);
+-- Create index "idx_subscription_renewals_subscription" to table: "subscription_renewals"
+CREATE INDEX idx_subscription_renewals_subscription ON subscription_renewals ("subscription_id", "created_at");
+
+-- SET RLS for subscription_plans
+ALTER TABLE subscription_plans ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON subscription_plans
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
+
+-- SET RLS for customer_payment_methods
+ALTER TABLE customer_payment_methods ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON customer_payment_methods
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
+
+-- SET RLS for subscriptions
+ALTER TABLE subscriptions ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON subscriptions
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
+
+-- SET RLS for subscription_renewals
+ALTER TABLE subscription_renewals ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON subscription_renewals
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
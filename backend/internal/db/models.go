@@ -142,227 +142,1016 @@ func (ns NullAttributeUnit) Value() (driver.Value, error) {
 	return string(ns.AttributeUnit), nil
 }
 
+type CollectionType string
+
+const (
+	CollectionTypeManual CollectionType = "manual"
+	CollectionTypeSmart  CollectionType = "smart"
+)
+
+func (e *CollectionType) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = CollectionType(s)
+	case string:
+		*e = CollectionType(s)
+	default:
+		return fmt.Errorf("unsupported scan type for CollectionType: %T", src)
+	}
+	return nil
+}
+
+type NullCollectionType struct {
+	CollectionType CollectionType `json:"collection_type"`
+	Valid          bool           `json:"valid"` // Valid is true if CollectionType is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullCollectionType) Scan(value interface{}) error {
+	if value == nil {
+		ns.CollectionType, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.CollectionType.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullCollectionType) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.CollectionType), nil
+}
+
+type CreditTransactionKind string
+
+const (
+	CreditTransactionKindIssue  CreditTransactionKind = "issue"
+	CreditTransactionKindRedeem CreditTransactionKind = "redeem"
+	CreditTransactionKindRefund CreditTransactionKind = "refund"
+	CreditTransactionKindAdjust CreditTransactionKind = "adjust"
+)
+
+func (e *CreditTransactionKind) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = CreditTransactionKind(s)
+	case string:
+		*e = CreditTransactionKind(s)
+	default:
+		return fmt.Errorf("unsupported scan type for CreditTransactionKind: %T", src)
+	}
+	return nil
+}
+
+type NullCreditTransactionKind struct {
+	CreditTransactionKind CreditTransactionKind `json:"credit_transaction_kind"`
+	Valid                 bool                  `json:"valid"` // Valid is true if CreditTransactionKind is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullCreditTransactionKind) Scan(value interface{}) error {
+	if value == nil {
+		ns.CreditTransactionKind, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.CreditTransactionKind.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullCreditTransactionKind) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.CreditTransactionKind), nil
+}
+
+type CustomerDataRequestKind string
+
+const (
+	CustomerDataRequestKindExport  CustomerDataRequestKind = "export"
+	CustomerDataRequestKindErasure CustomerDataRequestKind = "erasure"
+)
+
+func (e *CustomerDataRequestKind) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = CustomerDataRequestKind(s)
+	case string:
+		*e = CustomerDataRequestKind(s)
+	default:
+		return fmt.Errorf("unsupported scan type for CustomerDataRequestKind: %T", src)
+	}
+	return nil
+}
+
+type NullCustomerDataRequestKind struct {
+	CustomerDataRequestKind CustomerDataRequestKind `json:"customer_data_request_kind"`
+	Valid                   bool                    `json:"valid"` // Valid is true if CustomerDataRequestKind is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullCustomerDataRequestKind) Scan(value interface{}) error {
+	if value == nil {
+		ns.CustomerDataRequestKind, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.CustomerDataRequestKind.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullCustomerDataRequestKind) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.CustomerDataRequestKind), nil
+}
+
+type DigitalAssetKind string
+
+const (
+	DigitalAssetKindFile       DigitalAssetKind = "file"
+	DigitalAssetKindLicenseKey DigitalAssetKind = "license_key"
+)
+
+func (e *DigitalAssetKind) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = DigitalAssetKind(s)
+	case string:
+		*e = DigitalAssetKind(s)
+	default:
+		return fmt.Errorf("unsupported scan type for DigitalAssetKind: %T", src)
+	}
+	return nil
+}
+
+type NullDigitalAssetKind struct {
+	DigitalAssetKind DigitalAssetKind `json:"digital_asset_kind"`
+	Valid            bool             `json:"valid"` // Valid is true if DigitalAssetKind is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullDigitalAssetKind) Scan(value interface{}) error {
+	if value == nil {
+		ns.DigitalAssetKind, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.DigitalAssetKind.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullDigitalAssetKind) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.DigitalAssetKind), nil
+}
+
+type InventoryPolicy string
+
+const (
+	InventoryPolicyDeny      InventoryPolicy = "deny"
+	InventoryPolicyBackorder InventoryPolicy = "backorder"
+	InventoryPolicyPreorder  InventoryPolicy = "preorder"
+)
+
+func (e *InventoryPolicy) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = InventoryPolicy(s)
+	case string:
+		*e = InventoryPolicy(s)
+	default:
+		return fmt.Errorf("unsupported scan type for InventoryPolicy: %T", src)
+	}
+	return nil
+}
+
+type NullInventoryPolicy struct {
+	InventoryPolicy InventoryPolicy `json:"inventory_policy"`
+	Valid           bool            `json:"valid"` // Valid is true if InventoryPolicy is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullInventoryPolicy) Scan(value interface{}) error {
+	if value == nil {
+		ns.InventoryPolicy, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.InventoryPolicy.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullInventoryPolicy) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.InventoryPolicy), nil
+}
+
+type InventoryTransferStatus string
+
+const (
+	InventoryTransferStatusInTransit InventoryTransferStatus = "in_transit"
+	InventoryTransferStatusReceived  InventoryTransferStatus = "received"
+	InventoryTransferStatusCancelled InventoryTransferStatus = "cancelled"
+)
+
+func (e *InventoryTransferStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = InventoryTransferStatus(s)
+	case string:
+		*e = InventoryTransferStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for InventoryTransferStatus: %T", src)
+	}
+	return nil
+}
+
+type NullInventoryTransferStatus struct {
+	InventoryTransferStatus InventoryTransferStatus `json:"inventory_transfer_status"`
+	Valid                   bool                    `json:"valid"` // Valid is true if InventoryTransferStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullInventoryTransferStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.InventoryTransferStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.InventoryTransferStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullInventoryTransferStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.InventoryTransferStatus), nil
+}
+
+type LoyaltyTransactionKind string
+
+const (
+	LoyaltyTransactionKindEarn     LoyaltyTransactionKind = "earn"
+	LoyaltyTransactionKindRedeem   LoyaltyTransactionKind = "redeem"
+	LoyaltyTransactionKindRefund   LoyaltyTransactionKind = "refund"
+	LoyaltyTransactionKindClawback LoyaltyTransactionKind = "clawback"
+	LoyaltyTransactionKindExpire   LoyaltyTransactionKind = "expire"
+	LoyaltyTransactionKindAdjust   LoyaltyTransactionKind = "adjust"
+)
+
+func (e *LoyaltyTransactionKind) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = LoyaltyTransactionKind(s)
+	case string:
+		*e = LoyaltyTransactionKind(s)
+	default:
+		return fmt.Errorf("unsupported scan type for LoyaltyTransactionKind: %T", src)
+	}
+	return nil
+}
+
+type NullLoyaltyTransactionKind struct {
+	LoyaltyTransactionKind LoyaltyTransactionKind `json:"loyalty_transaction_kind"`
+	Valid                  bool                   `json:"valid"` // Valid is true if LoyaltyTransactionKind is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullLoyaltyTransactionKind) Scan(value interface{}) error {
+	if value == nil {
+		ns.LoyaltyTransactionKind, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.LoyaltyTransactionKind.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullLoyaltyTransactionKind) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.LoyaltyTransactionKind), nil
+}
+
+type MetafieldOwnerType string
+
+const (
+	MetafieldOwnerTypeProduct          MetafieldOwnerType = "product"
+	MetafieldOwnerTypeProductVariation MetafieldOwnerType = "product_variation"
+	MetafieldOwnerTypeCategory         MetafieldOwnerType = "category"
+	MetafieldOwnerTypeShop             MetafieldOwnerType = "shop"
+	MetafieldOwnerTypeCustomer         MetafieldOwnerType = "customer"
+)
+
+func (e *MetafieldOwnerType) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = MetafieldOwnerType(s)
+	case string:
+		*e = MetafieldOwnerType(s)
+	default:
+		return fmt.Errorf("unsupported scan type for MetafieldOwnerType: %T", src)
+	}
+	return nil
+}
+
+type NullMetafieldOwnerType struct {
+	MetafieldOwnerType MetafieldOwnerType `json:"metafield_owner_type"`
+	Valid              bool               `json:"valid"` // Valid is true if MetafieldOwnerType is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullMetafieldOwnerType) Scan(value interface{}) error {
+	if value == nil {
+		ns.MetafieldOwnerType, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.MetafieldOwnerType.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullMetafieldOwnerType) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.MetafieldOwnerType), nil
+}
+
+type MetafieldType string
+
+const (
+	MetafieldTypeSingleLineText MetafieldType = "single_line_text"
+	MetafieldTypeMultiLineText  MetafieldType = "multi_line_text"
+	MetafieldTypeInteger        MetafieldType = "integer"
+	MetafieldTypeDecimal        MetafieldType = "decimal"
+	MetafieldTypeBoolean        MetafieldType = "boolean"
+	MetafieldTypeDate           MetafieldType = "date"
+	MetafieldTypeDateTime       MetafieldType = "date_time"
+	MetafieldTypeJson           MetafieldType = "json"
+	MetafieldTypeUrl            MetafieldType = "url"
+	MetafieldTypeColor          MetafieldType = "color"
+)
+
+func (e *MetafieldType) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = MetafieldType(s)
+	case string:
+		*e = MetafieldType(s)
+	default:
+		return fmt.Errorf("unsupported scan type for MetafieldType: %T", src)
+	}
+	return nil
+}
+
+type NullMetafieldType struct {
+	MetafieldType MetafieldType `json:"metafield_type"`
+	Valid         bool          `json:"valid"` // Valid is true if MetafieldType is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullMetafieldType) Scan(value interface{}) error {
+	if value == nil {
+		ns.MetafieldType, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.MetafieldType.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullMetafieldType) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.MetafieldType), nil
+}
+
+type NotificationStatus string
+
+const (
+	NotificationStatusPending NotificationStatus = "pending"
+	NotificationStatusSent    NotificationStatus = "sent"
+	NotificationStatusFailed  NotificationStatus = "failed"
+)
+
+func (e *NotificationStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = NotificationStatus(s)
+	case string:
+		*e = NotificationStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for NotificationStatus: %T", src)
+	}
+	return nil
+}
+
+type NullNotificationStatus struct {
+	NotificationStatus NotificationStatus `json:"notification_status"`
+	Valid              bool               `json:"valid"` // Valid is true if NotificationStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullNotificationStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.NotificationStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.NotificationStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullNotificationStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.NotificationStatus), nil
+}
+
 type OrderStatusType string
 
 const (
-	OrderStatusTypePending    OrderStatusType = "pending"
-	OrderStatusTypeProcessing OrderStatusType = "processing"
-	OrderStatusTypeCompleted  OrderStatusType = "completed"
-	OrderStatusTypeCancelled  OrderStatusType = "cancelled"
-	OrderStatusTypeRefunded   OrderStatusType = "refunded"
+	OrderStatusTypePending    OrderStatusType = "pending"
+	OrderStatusTypeProcessing OrderStatusType = "processing"
+	OrderStatusTypeCompleted  OrderStatusType = "completed"
+	OrderStatusTypeCancelled  OrderStatusType = "cancelled"
+	OrderStatusTypeRefunded   OrderStatusType = "refunded"
+)
+
+func (e *OrderStatusType) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = OrderStatusType(s)
+	case string:
+		*e = OrderStatusType(s)
+	default:
+		return fmt.Errorf("unsupported scan type for OrderStatusType: %T", src)
+	}
+	return nil
+}
+
+type NullOrderStatusType struct {
+	OrderStatusType OrderStatusType `json:"order_status_type"`
+	Valid           bool            `json:"valid"` // Valid is true if OrderStatusType is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullOrderStatusType) Scan(value interface{}) error {
+	if value == nil {
+		ns.OrderStatusType, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.OrderStatusType.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullOrderStatusType) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.OrderStatusType), nil
+}
+
+type PaymentMethodType string
+
+const (
+	PaymentMethodTypeFlutterwave   PaymentMethodType = "flutterwave"
+	PaymentMethodTypePaystack      PaymentMethodType = "paystack"
+	PaymentMethodTypePaypal        PaymentMethodType = "paypal"
+	PaymentMethodTypeStripe        PaymentMethodType = "stripe"
+	PaymentMethodTypePayOnDelivery PaymentMethodType = "pay_on_delivery"
+)
+
+func (e *PaymentMethodType) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = PaymentMethodType(s)
+	case string:
+		*e = PaymentMethodType(s)
+	default:
+		return fmt.Errorf("unsupported scan type for PaymentMethodType: %T", src)
+	}
+	return nil
+}
+
+type NullPaymentMethodType struct {
+	PaymentMethodType PaymentMethodType `json:"payment_method_type"`
+	Valid             bool              `json:"valid"` // Valid is true if PaymentMethodType is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullPaymentMethodType) Scan(value interface{}) error {
+	if value == nil {
+		ns.PaymentMethodType, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.PaymentMethodType.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullPaymentMethodType) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.PaymentMethodType), nil
+}
+
+type PaymentStatusType string
+
+const (
+	PaymentStatusTypePending       PaymentStatusType = "pending"
+	PaymentStatusTypePaid          PaymentStatusType = "paid"
+	PaymentStatusTypeFailed        PaymentStatusType = "failed"
+	PaymentStatusTypeRefunded      PaymentStatusType = "refunded"
+	PaymentStatusTypePartialRefund PaymentStatusType = "partial_refund"
 )
 
-func (e *OrderStatusType) Scan(src interface{}) error {
+func (e *PaymentStatusType) Scan(src interface{}) error {
 	switch s := src.(type) {
 	case []byte:
-		*e = OrderStatusType(s)
+		*e = PaymentStatusType(s)
 	case string:
-		*e = OrderStatusType(s)
+		*e = PaymentStatusType(s)
 	default:
-		return fmt.Errorf("unsupported scan type for OrderStatusType: %T", src)
+		return fmt.Errorf("unsupported scan type for PaymentStatusType: %T", src)
 	}
 	return nil
 }
 
-type NullOrderStatusType struct {
-	OrderStatusType OrderStatusType `json:"order_status_type"`
-	Valid           bool            `json:"valid"` // Valid is true if OrderStatusType is not NULL
+type NullPaymentStatusType struct {
+	PaymentStatusType PaymentStatusType `json:"payment_status_type"`
+	Valid             bool              `json:"valid"` // Valid is true if PaymentStatusType is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullPaymentStatusType) Scan(value interface{}) error {
+	if value == nil {
+		ns.PaymentStatusType, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.PaymentStatusType.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullPaymentStatusType) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.PaymentStatusType), nil
+}
+
+type ProductStatus string
+
+const (
+	ProductStatusDRAFT     ProductStatus = "DRAFT"
+	ProductStatusPUBLISHED ProductStatus = "PUBLISHED"
+	ProductStatusARCHIVED  ProductStatus = "ARCHIVED"
+)
+
+func (e *ProductStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ProductStatus(s)
+	case string:
+		*e = ProductStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ProductStatus: %T", src)
+	}
+	return nil
+}
+
+type NullProductStatus struct {
+	ProductStatus ProductStatus `json:"product_status"`
+	Valid         bool          `json:"valid"` // Valid is true if ProductStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullProductStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.ProductStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ProductStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullProductStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ProductStatus), nil
+}
+
+type PurchaseOrderStatus string
+
+const (
+	PurchaseOrderStatusDraft             PurchaseOrderStatus = "draft"
+	PurchaseOrderStatusOrdered           PurchaseOrderStatus = "ordered"
+	PurchaseOrderStatusPartiallyReceived PurchaseOrderStatus = "partially_received"
+	PurchaseOrderStatusReceived          PurchaseOrderStatus = "received"
+)
+
+func (e *PurchaseOrderStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = PurchaseOrderStatus(s)
+	case string:
+		*e = PurchaseOrderStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for PurchaseOrderStatus: %T", src)
+	}
+	return nil
+}
+
+type NullPurchaseOrderStatus struct {
+	PurchaseOrderStatus PurchaseOrderStatus `json:"purchase_order_status"`
+	Valid               bool                `json:"valid"` // Valid is true if PurchaseOrderStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullPurchaseOrderStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.PurchaseOrderStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.PurchaseOrderStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullPurchaseOrderStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.PurchaseOrderStatus), nil
+}
+
+type ReviewStatus string
+
+const (
+	ReviewStatusPending  ReviewStatus = "pending"
+	ReviewStatusApproved ReviewStatus = "approved"
+	ReviewStatusRejected ReviewStatus = "rejected"
+)
+
+func (e *ReviewStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ReviewStatus(s)
+	case string:
+		*e = ReviewStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ReviewStatus: %T", src)
+	}
+	return nil
+}
+
+type NullReviewStatus struct {
+	ReviewStatus ReviewStatus `json:"review_status"`
+	Valid        bool         `json:"valid"` // Valid is true if ReviewStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullReviewStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.ReviewStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ReviewStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullReviewStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ReviewStatus), nil
+}
+
+type ShippingStatusType string
+
+const (
+	ShippingStatusTypePending   ShippingStatusType = "pending"
+	ShippingStatusTypeShipped   ShippingStatusType = "shipped"
+	ShippingStatusTypeDelivered ShippingStatusType = "delivered"
+	ShippingStatusTypeCancelled ShippingStatusType = "cancelled"
+	ShippingStatusTypeReturned  ShippingStatusType = "returned"
+)
+
+func (e *ShippingStatusType) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ShippingStatusType(s)
+	case string:
+		*e = ShippingStatusType(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ShippingStatusType: %T", src)
+	}
+	return nil
+}
+
+type NullShippingStatusType struct {
+	ShippingStatusType ShippingStatusType `json:"shipping_status_type"`
+	Valid              bool               `json:"valid"` // Valid is true if ShippingStatusType is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullShippingStatusType) Scan(value interface{}) error {
+	if value == nil {
+		ns.ShippingStatusType, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ShippingStatusType.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullShippingStatusType) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ShippingStatusType), nil
+}
+
+type StockAlertKind string
+
+const (
+	StockAlertKindLowStock   StockAlertKind = "low_stock"
+	StockAlertKindOutOfStock StockAlertKind = "out_of_stock"
+)
+
+func (e *StockAlertKind) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = StockAlertKind(s)
+	case string:
+		*e = StockAlertKind(s)
+	default:
+		return fmt.Errorf("unsupported scan type for StockAlertKind: %T", src)
+	}
+	return nil
+}
+
+type NullStockAlertKind struct {
+	StockAlertKind StockAlertKind `json:"stock_alert_kind"`
+	Valid          bool           `json:"valid"` // Valid is true if StockAlertKind is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullStockAlertKind) Scan(value interface{}) error {
+	if value == nil {
+		ns.StockAlertKind, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.StockAlertKind.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullStockAlertKind) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.StockAlertKind), nil
+}
+
+type StocktakeStatus string
+
+const (
+	StocktakeStatusOpen      StocktakeStatus = "open"
+	StocktakeStatusCommitted StocktakeStatus = "committed"
+	StocktakeStatusCancelled StocktakeStatus = "cancelled"
+)
+
+func (e *StocktakeStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = StocktakeStatus(s)
+	case string:
+		*e = StocktakeStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for StocktakeStatus: %T", src)
+	}
+	return nil
+}
+
+type NullStocktakeStatus struct {
+	StocktakeStatus StocktakeStatus `json:"stocktake_status"`
+	Valid           bool            `json:"valid"` // Valid is true if StocktakeStatus is not NULL
 }
 
 // Scan implements the Scanner interface.
-func (ns *NullOrderStatusType) Scan(value interface{}) error {
+func (ns *NullStocktakeStatus) Scan(value interface{}) error {
 	if value == nil {
-		ns.OrderStatusType, ns.Valid = "", false
+		ns.StocktakeStatus, ns.Valid = "", false
 		return nil
 	}
 	ns.Valid = true
-	return ns.OrderStatusType.Scan(value)
+	return ns.StocktakeStatus.Scan(value)
 }
 
 // Value implements the driver Valuer interface.
-func (ns NullOrderStatusType) Value() (driver.Value, error) {
+func (ns NullStocktakeStatus) Value() (driver.Value, error) {
 	if !ns.Valid {
 		return nil, nil
 	}
-	return string(ns.OrderStatusType), nil
+	return string(ns.StocktakeStatus), nil
 }
 
-type PaymentMethodType string
+type SubscriptionInterval string
 
 const (
-	PaymentMethodTypeFlutterwave   PaymentMethodType = "flutterwave"
-	PaymentMethodTypePaystack      PaymentMethodType = "paystack"
-	PaymentMethodTypePaypal        PaymentMethodType = "paypal"
-	PaymentMethodTypeStripe        PaymentMethodType = "stripe"
-	PaymentMethodTypePayOnDelivery PaymentMethodType = "pay_on_delivery"
+	SubscriptionIntervalDay   SubscriptionInterval = "day"
+	SubscriptionIntervalWeek  SubscriptionInterval = "week"
+	SubscriptionIntervalMonth SubscriptionInterval = "month"
+	SubscriptionIntervalYear  SubscriptionInterval = "year"
 )
 
-func (e *PaymentMethodType) Scan(src interface{}) error {
+func (e *SubscriptionInterval) Scan(src interface{}) error {
 	switch s := src.(type) {
 	case []byte:
-		*e = PaymentMethodType(s)
+		*e = SubscriptionInterval(s)
 	case string:
-		*e = PaymentMethodType(s)
+		*e = SubscriptionInterval(s)
 	default:
-		return fmt.Errorf("unsupported scan type for PaymentMethodType: %T", src)
+		return fmt.Errorf("unsupported scan type for SubscriptionInterval: %T", src)
 	}
 	return nil
 }
 
-type NullPaymentMethodType struct {
-	PaymentMethodType PaymentMethodType `json:"payment_method_type"`
-	Valid             bool              `json:"valid"` // Valid is true if PaymentMethodType is not NULL
+type NullSubscriptionInterval struct {
+	SubscriptionInterval SubscriptionInterval `json:"subscription_interval"`
+	Valid                bool                 `json:"valid"` // Valid is true if SubscriptionInterval is not NULL
 }
 
 // Scan implements the Scanner interface.
-func (ns *NullPaymentMethodType) Scan(value interface{}) error {
+func (ns *NullSubscriptionInterval) Scan(value interface{}) error {
 	if value == nil {
-		ns.PaymentMethodType, ns.Valid = "", false
+		ns.SubscriptionInterval, ns.Valid = "", false
 		return nil
 	}
 	ns.Valid = true
-	return ns.PaymentMethodType.Scan(value)
+	return ns.SubscriptionInterval.Scan(value)
 }
 
 // Value implements the driver Valuer interface.
-func (ns NullPaymentMethodType) Value() (driver.Value, error) {
+func (ns NullSubscriptionInterval) Value() (driver.Value, error) {
 	if !ns.Valid {
 		return nil, nil
 	}
-	return string(ns.PaymentMethodType), nil
+	return string(ns.SubscriptionInterval), nil
 }
 
-type PaymentStatusType string
+type SubscriptionRenewalStatus string
 
 const (
-	PaymentStatusTypePending       PaymentStatusType = "pending"
-	PaymentStatusTypePaid          PaymentStatusType = "paid"
-	PaymentStatusTypeFailed        PaymentStatusType = "failed"
-	PaymentStatusTypeRefunded      PaymentStatusType = "refunded"
-	PaymentStatusTypePartialRefund PaymentStatusType = "partial_refund"
+	SubscriptionRenewalStatusPaid    SubscriptionRenewalStatus = "paid"
+	SubscriptionRenewalStatusFailed  SubscriptionRenewalStatus = "failed"
+	SubscriptionRenewalStatusSkipped SubscriptionRenewalStatus = "skipped"
 )
 
-func (e *PaymentStatusType) Scan(src interface{}) error {
+func (e *SubscriptionRenewalStatus) Scan(src interface{}) error {
 	switch s := src.(type) {
 	case []byte:
-		*e = PaymentStatusType(s)
+		*e = SubscriptionRenewalStatus(s)
 	case string:
-		*e = PaymentStatusType(s)
+		*e = SubscriptionRenewalStatus(s)
 	default:
-		return fmt.Errorf("unsupported scan type for PaymentStatusType: %T", src)
+		return fmt.Errorf("unsupported scan type for SubscriptionRenewalStatus: %T", src)
 	}
 	return nil
 }
 
-type NullPaymentStatusType struct {
-	PaymentStatusType PaymentStatusType `json:"payment_status_type"`
-	Valid             bool              `json:"valid"` // Valid is true if PaymentStatusType is not NULL
+type NullSubscriptionRenewalStatus struct {
+	SubscriptionRenewalStatus SubscriptionRenewalStatus `json:"subscription_renewal_status"`
+	Valid                     bool                      `json:"valid"` // Valid is true if SubscriptionRenewalStatus is not NULL
 }
 
 // Scan implements the Scanner interface.
-func (ns *NullPaymentStatusType) Scan(value interface{}) error {
+func (ns *NullSubscriptionRenewalStatus) Scan(value interface{}) error {
 	if value == nil {
-		ns.PaymentStatusType, ns.Valid = "", false
+		ns.SubscriptionRenewalStatus, ns.Valid = "", false
 		return nil
 	}
 	ns.Valid = true
-	return ns.PaymentStatusType.Scan(value)
+	return ns.SubscriptionRenewalStatus.Scan(value)
 }
 
 // Value implements the driver Valuer interface.
-func (ns NullPaymentStatusType) Value() (driver.Value, error) {
+func (ns NullSubscriptionRenewalStatus) Value() (driver.Value, error) {
 	if !ns.Valid {
 		return nil, nil
 	}
-	return string(ns.PaymentStatusType), nil
+	return string(ns.SubscriptionRenewalStatus), nil
 }
 
-type ProductStatus string
+type SubscriptionStatus string
 
 const (
-	ProductStatusDRAFT     ProductStatus = "DRAFT"
-	ProductStatusPUBLISHED ProductStatus = "PUBLISHED"
-	ProductStatusARCHIVED  ProductStatus = "ARCHIVED"
+	SubscriptionStatusTrialing  SubscriptionStatus = "trialing"
+	SubscriptionStatusActive    SubscriptionStatus = "active"
+	SubscriptionStatusPaused    SubscriptionStatus = "paused"
+	SubscriptionStatusPastDue   SubscriptionStatus = "past_due"
+	SubscriptionStatusCancelled SubscriptionStatus = "cancelled"
 )
 
-func (e *ProductStatus) Scan(src interface{}) error {
+func (e *SubscriptionStatus) Scan(src interface{}) error {
 	switch s := src.(type) {
 	case []byte:
-		*e = ProductStatus(s)
+		*e = SubscriptionStatus(s)
 	case string:
-		*e = ProductStatus(s)
+		*e = SubscriptionStatus(s)
 	default:
-		return fmt.Errorf("unsupported scan type for ProductStatus: %T", src)
+		return fmt.Errorf("unsupported scan type for SubscriptionStatus: %T", src)
 	}
 	return nil
 }
 
-type NullProductStatus struct {
-	ProductStatus ProductStatus `json:"product_status"`
-	Valid         bool          `json:"valid"` // Valid is true if ProductStatus is not NULL
+type NullSubscriptionStatus struct {
+	SubscriptionStatus SubscriptionStatus `json:"subscription_status"`
+	Valid              bool               `json:"valid"` // Valid is true if SubscriptionStatus is not NULL
 }
 
 // Scan implements the Scanner interface.
-func (ns *NullProductStatus) Scan(value interface{}) error {
+func (ns *NullSubscriptionStatus) Scan(value interface{}) error {
 	if value == nil {
-		ns.ProductStatus, ns.Valid = "", false
+		ns.SubscriptionStatus, ns.Valid = "", false
 		return nil
 	}
 	ns.Valid = true
-	return ns.ProductStatus.Scan(value)
+	return ns.SubscriptionStatus.Scan(value)
 }
 
 // Value implements the driver Valuer interface.
-func (ns NullProductStatus) Value() (driver.Value, error) {
+func (ns NullSubscriptionStatus) Value() (driver.Value, error) {
 	if !ns.Valid {
 		return nil, nil
 	}
-	return string(ns.ProductStatus), nil
+	return string(ns.SubscriptionStatus), nil
 }
 
-type ShippingStatusType string
+type TranslatableResourceType string
 
 const (
-	ShippingStatusTypePending   ShippingStatusType = "pending"
-	ShippingStatusTypeShipped   ShippingStatusType = "shipped"
-	ShippingStatusTypeDelivered ShippingStatusType = "delivered"
-	ShippingStatusTypeCancelled ShippingStatusType = "cancelled"
-	ShippingStatusTypeReturned  ShippingStatusType = "returned"
+	TranslatableResourceTypeProduct          TranslatableResourceType = "product"
+	TranslatableResourceTypeProductVariation TranslatableResourceType = "product_variation"
+	TranslatableResourceTypeCategory         TranslatableResourceType = "category"
+	TranslatableResourceTypeShop             TranslatableResourceType = "shop"
+	TranslatableResourceTypeCollection       TranslatableResourceType = "collection"
 )
 
-func (e *ShippingStatusType) Scan(src interface{}) error {
+func (e *TranslatableResourceType) Scan(src interface{}) error {
 	switch s := src.(type) {
 	case []byte:
-		*e = ShippingStatusType(s)
+		*e = TranslatableResourceType(s)
 	case string:
-		*e = ShippingStatusType(s)
+		*e = TranslatableResourceType(s)
 	default:
-		return fmt.Errorf("unsupported scan type for ShippingStatusType: %T", src)
+		return fmt.Errorf("unsupported scan type for TranslatableResourceType: %T", src)
 	}
 	return nil
 }
 
-type NullShippingStatusType struct {
-	ShippingStatusType ShippingStatusType `json:"shipping_status_type"`
-	Valid              bool               `json:"valid"` // Valid is true if ShippingStatusType is not NULL
+type NullTranslatableResourceType struct {
+	TranslatableResourceType TranslatableResourceType `json:"translatable_resource_type"`
+	Valid                    bool                     `json:"valid"` // Valid is true if TranslatableResourceType is not NULL
 }
 
 // Scan implements the Scanner interface.
-func (ns *NullShippingStatusType) Scan(value interface{}) error {
+func (ns *NullTranslatableResourceType) Scan(value interface{}) error {
 	if value == nil {
-		ns.ShippingStatusType, ns.Valid = "", false
+		ns.TranslatableResourceType, ns.Valid = "", false
 		return nil
 	}
 	ns.Valid = true
-	return ns.ShippingStatusType.Scan(value)
+	return ns.TranslatableResourceType.Scan(value)
 }
 
 // Value implements the driver Valuer interface.
-func (ns NullShippingStatusType) Value() (driver.Value, error) {
+func (ns NullTranslatableResourceType) Value() (driver.Value, error) {
 	if !ns.Valid {
 		return nil, nil
 	}
-	return string(ns.ShippingStatusType), nil
+	return string(ns.TranslatableResourceType), nil
 }
 
 type Attribute struct {
@@ -383,6 +1172,23 @@ type AttributeOption struct {
 	AttributeID       int64  `json:"attribute_id"`
 }
 
+type BackInStockSubscription struct {
+	SubscriptionID     int64              `json:"subscription_id"`
+	ProductVariationID int64              `json:"product_variation_id"`
+	Email              string             `json:"email"`
+	ShopCustomerID     pgtype.UUID        `json:"shop_customer_id"`
+	NotifiedAt         pgtype.Timestamptz `json:"notified_at"`
+	CreatedAt          pgtype.Timestamptz `json:"created_at"`
+	ShopID             int64              `json:"shop_id"`
+}
+
+type BundleComponent struct {
+	BundleVariationID    int64 `json:"bundle_variation_id"`
+	ComponentVariationID int64 `json:"component_variation_id"`
+	Quantity             int64 `json:"quantity"`
+	ShopID               int64 `json:"shop_id"`
+}
+
 type Category struct {
 	CategoryID  int64   `json:"category_id"`
 	Slug        string  `json:"slug"`
@@ -390,6 +1196,113 @@ type Category struct {
 	Description *string `json:"description"`
 	ParentID    *int64  `json:"parent_id"`
 	ShopID      int64   `json:"shop_id"`
+	BannerUrl   *string `json:"banner_url"`
+}
+
+type Collection struct {
+	CollectionID   int64              `json:"collection_id"`
+	Handle         string             `json:"handle"`
+	Title          string             `json:"title"`
+	Description    *string            `json:"description"`
+	CollectionType CollectionType     `json:"collection_type"`
+	Rules          []byte             `json:"rules"`
+	MatchAll       bool               `json:"match_all"`
+	SortOrder      string             `json:"sort_order"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt      pgtype.Timestamptz `json:"updated_at"`
+	ShopID         int64              `json:"shop_id"`
+}
+
+type CollectionProduct struct {
+	CollectionID int64              `json:"collection_id"`
+	ProductID    int64              `json:"product_id"`
+	Position     int32              `json:"position"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+	ShopID       int64              `json:"shop_id"`
+}
+
+type CustomerAddress struct {
+	AddressID         int64              `json:"address_id"`
+	ShopCustomerID    uuid.UUID          `json:"shop_customer_id"`
+	FirstName         string             `json:"first_name"`
+	LastName          string             `json:"last_name"`
+	Company           *string            `json:"company"`
+	AddressLine1      string             `json:"address_line_1"`
+	AddressLine2      *string            `json:"address_line_2"`
+	City              string             `json:"city"`
+	State             *string            `json:"state"`
+	PostalCode        string             `json:"postal_code"`
+	Country           string             `json:"country"`
+	Phone             *string            `json:"phone"`
+	IsDefaultShipping bool               `json:"is_default_shipping"`
+	IsDefaultBilling  bool               `json:"is_default_billing"`
+	CreatedAt         pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt         pgtype.Timestamptz `json:"updated_at"`
+	ShopID            int64              `json:"shop_id"`
+}
+
+type CustomerDataRequest struct {
+	RequestID      int64                   `json:"request_id"`
+	Kind           CustomerDataRequestKind `json:"kind"`
+	ShopCustomerID uuid.UUID               `json:"shop_customer_id"`
+	RequestedBy    *string                 `json:"requested_by"`
+	Details        []byte                  `json:"details"`
+	CreatedAt      pgtype.Timestamptz      `json:"created_at"`
+	ShopID         int64                   `json:"shop_id"`
+}
+
+type CustomerGroup struct {
+	GroupID         int64              `json:"group_id"`
+	Name            string             `json:"name"`
+	Description     *string            `json:"description"`
+	DiscountPercent pgtype.Numeric     `json:"discount_percent"`
+	CreatedAt       pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt       pgtype.Timestamptz `json:"updated_at"`
+	ShopID          int64              `json:"shop_id"`
+}
+
+type CustomerGroupMember struct {
+	GroupID        int64              `json:"group_id"`
+	ShopCustomerID uuid.UUID          `json:"shop_customer_id"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+	ShopID         int64              `json:"shop_id"`
+}
+
+type CustomerGroupPrice struct {
+	GroupID            int64              `json:"group_id"`
+	ProductVariationID int64              `json:"product_variation_id"`
+	Price              pgtype.Numeric     `json:"price"`
+	UpdatedAt          pgtype.Timestamptz `json:"updated_at"`
+	ShopID             int64              `json:"shop_id"`
+}
+
+type CustomerPaymentMethod struct {
+	CustomerPaymentMethodID int64              `json:"customer_payment_method_id"`
+	ShopCustomerID          uuid.UUID          `json:"shop_customer_id"`
+	Provider                PaymentMethodType  `json:"provider"`
+	CustomerReference       string             `json:"customer_reference"`
+	Token                   string             `json:"token"`
+	Brand                   *string            `json:"brand"`
+	Last4                   *string            `json:"last4"`
+	ExpMonth                *int32             `json:"exp_month"`
+	ExpYear                 *int32             `json:"exp_year"`
+	CreatedAt               pgtype.Timestamptz `json:"created_at"`
+	ShopID                  int64              `json:"shop_id"`
+}
+
+type CustomerSegment struct {
+	SegmentID     int64              `json:"segment_id"`
+	Name          string             `json:"name"`
+	Description   *string            `json:"description"`
+	MinTotalSpent pgtype.Numeric     `json:"min_total_spent"`
+	MaxTotalSpent pgtype.Numeric     `json:"max_total_spent"`
+	MinOrderCount *int32             `json:"min_order_count"`
+	MaxOrderCount *int32             `json:"max_order_count"`
+	WindowDays    *int32             `json:"window_days"`
+	InactiveDays  *int32             `json:"inactive_days"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt     pgtype.Timestamptz `json:"updated_at"`
+	ShopID        int64              `json:"shop_id"`
 }
 
 type DailySale struct {
@@ -399,6 +1312,214 @@ type DailySale struct {
 	Revenue     int64       `json:"revenue"`
 }
 
+type DigitalAsset struct {
+	DigitalAssetID     int64              `json:"digital_asset_id"`
+	ProductVariationID int64              `json:"product_variation_id"`
+	Kind               DigitalAssetKind   `json:"kind"`
+	Title              string             `json:"title"`
+	StorageKey         *string            `json:"storage_key"`
+	FileName           *string            `json:"file_name"`
+	ContentType        *string            `json:"content_type"`
+	SizeBytes          *int64             `json:"size_bytes"`
+	DownloadLimit      *int32             `json:"download_limit"`
+	ExpiresAfterDays   *int32             `json:"expires_after_days"`
+	CreatedAt          pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt          pgtype.Timestamptz `json:"updated_at"`
+	ShopID             int64              `json:"shop_id"`
+}
+
+type DigitalDownload struct {
+	DownloadID     int64              `json:"download_id"`
+	OrderID        int64              `json:"order_id"`
+	OrderItemID    int64              `json:"order_item_id"`
+	DigitalAssetID int64              `json:"digital_asset_id"`
+	LicenseKeyID   *int64             `json:"license_key_id"`
+	DownloadCount  int32              `json:"download_count"`
+	DownloadLimit  *int32             `json:"download_limit"`
+	ExpiresAt      pgtype.Timestamptz `json:"expires_at"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+	ShopID         int64              `json:"shop_id"`
+}
+
+type DigitalDownloadEvent struct {
+	EventID    int64              `json:"event_id"`
+	DownloadID int64              `json:"download_id"`
+	IpAddress  *string            `json:"ip_address"`
+	UserAgent  *string            `json:"user_agent"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+	ShopID     int64              `json:"shop_id"`
+}
+
+type GiftCard struct {
+	GiftCardID     int64              `json:"gift_card_id"`
+	Code           string             `json:"code"`
+	InitialAmount  pgtype.Numeric     `json:"initial_amount"`
+	Balance        pgtype.Numeric     `json:"balance"`
+	CurrencyCode   string             `json:"currency_code"`
+	ExpiresAt      pgtype.Timestamptz `json:"expires_at"`
+	Disabled       bool               `json:"disabled"`
+	Note           *string            `json:"note"`
+	RecipientEmail *string            `json:"recipient_email"`
+	ShopCustomerID pgtype.UUID        `json:"shop_customer_id"`
+	OrderID        *int64             `json:"order_id"`
+	OrderItemID    *int64             `json:"order_item_id"`
+	OrderItemUnit  *int64             `json:"order_item_unit"`
+	CreatedBy      *string            `json:"created_by"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt      pgtype.Timestamptz `json:"updated_at"`
+	ShopID         int64              `json:"shop_id"`
+}
+
+type GiftCardTransaction struct {
+	TransactionID int64                 `json:"transaction_id"`
+	GiftCardID    int64                 `json:"gift_card_id"`
+	Kind          CreditTransactionKind `json:"kind"`
+	Amount        pgtype.Numeric        `json:"amount"`
+	BalanceAfter  pgtype.Numeric        `json:"balance_after"`
+	OrderID       *int64                `json:"order_id"`
+	Note          *string               `json:"note"`
+	CreatedBy     *string               `json:"created_by"`
+	CreatedAt     pgtype.Timestamptz    `json:"created_at"`
+	ShopID        int64                 `json:"shop_id"`
+}
+
+type ImageAsset struct {
+	ImageAssetID int64              `json:"image_asset_id"`
+	StorageKey   string             `json:"storage_key"`
+	Url          string             `json:"url"`
+	ContentType  string             `json:"content_type"`
+	Width        int32              `json:"width"`
+	Height       int32              `json:"height"`
+	SizeBytes    int64              `json:"size_bytes"`
+	Renditions   []byte             `json:"renditions"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+	ShopID       int64              `json:"shop_id"`
+}
+
+type InventoryLevel struct {
+	ProductVariationID int64              `json:"product_variation_id"`
+	LocationID         int64              `json:"location_id"`
+	Available          int64              `json:"available"`
+	Incoming           int64              `json:"incoming"`
+	UpdatedAt          pgtype.Timestamptz `json:"updated_at"`
+	ShopID             int64              `json:"shop_id"`
+}
+
+type InventoryLot struct {
+	LotID              int64              `json:"lot_id"`
+	ProductVariationID int64              `json:"product_variation_id"`
+	LocationID         int64              `json:"location_id"`
+	LotNumber          string             `json:"lot_number"`
+	ExpiresOn          pgtype.Date        `json:"expires_on"`
+	Quantity           int64              `json:"quantity"`
+	ReceivedAt         pgtype.Timestamptz `json:"received_at"`
+	UpdatedAt          pgtype.Timestamptz `json:"updated_at"`
+	ShopID             int64              `json:"shop_id"`
+}
+
+type InventoryTransfer struct {
+	TransferID            int64                   `json:"transfer_id"`
+	OriginLocationID      int64                   `json:"origin_location_id"`
+	DestinationLocationID int64                   `json:"destination_location_id"`
+	Status                InventoryTransferStatus `json:"status"`
+	Note                  *string                 `json:"note"`
+	CreatedBy             *string                 `json:"created_by"`
+	CreatedAt             pgtype.Timestamptz      `json:"created_at"`
+	UpdatedAt             pgtype.Timestamptz      `json:"updated_at"`
+	ReceivedAt            pgtype.Timestamptz      `json:"received_at"`
+	ShopID                int64                   `json:"shop_id"`
+}
+
+type InventoryTransferItem struct {
+	TransferID         int64 `json:"transfer_id"`
+	ProductVariationID int64 `json:"product_variation_id"`
+	Quantity           int64 `json:"quantity"`
+	ShopID             int64 `json:"shop_id"`
+}
+
+type LicenseKey struct {
+	LicenseKeyID   int64              `json:"license_key_id"`
+	DigitalAssetID int64              `json:"digital_asset_id"`
+	LicenseKey     string             `json:"license_key"`
+	AllocatedAt    pgtype.Timestamptz `json:"allocated_at"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+	ShopID         int64              `json:"shop_id"`
+}
+
+type Location struct {
+	LocationID  int64              `json:"location_id"`
+	Name        string             `json:"name"`
+	Address     *string            `json:"address"`
+	SellsOnline bool               `json:"sells_online"`
+	IsDefault   bool               `json:"is_default"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt   pgtype.Timestamptz `json:"updated_at"`
+	ShopID      int64              `json:"shop_id"`
+}
+
+type LoyaltyProgram struct {
+	ShopID          int64              `json:"shop_id"`
+	Enabled         bool               `json:"enabled"`
+	EarnRate        pgtype.Numeric     `json:"earn_rate"`
+	BurnRate        pgtype.Numeric     `json:"burn_rate"`
+	MinRedeemPoints int32              `json:"min_redeem_points"`
+	ExpiryDays      *int32             `json:"expiry_days"`
+	CreatedAt       pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt       pgtype.Timestamptz `json:"updated_at"`
+}
+
+type LoyaltyTransaction struct {
+	TransactionID  int64                  `json:"transaction_id"`
+	ShopCustomerID uuid.UUID              `json:"shop_customer_id"`
+	Kind           LoyaltyTransactionKind `json:"kind"`
+	Points         int32                  `json:"points"`
+	BalanceAfter   int32                  `json:"balance_after"`
+	OrderID        *int64                 `json:"order_id"`
+	Note           *string                `json:"note"`
+	CreatedBy      *string                `json:"created_by"`
+	CreatedAt      pgtype.Timestamptz     `json:"created_at"`
+	ShopID         int64                  `json:"shop_id"`
+}
+
+type Metafield struct {
+	MetafieldID int64              `json:"metafield_id"`
+	OwnerType   MetafieldOwnerType `json:"owner_type"`
+	OwnerID     string             `json:"owner_id"`
+	Namespace   string             `json:"namespace"`
+	Key         string             `json:"key"`
+	Type        MetafieldType      `json:"type"`
+	Value       string             `json:"value"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt   pgtype.Timestamptz `json:"updated_at"`
+	ShopID      int64              `json:"shop_id"`
+}
+
+type MetafieldDefinition struct {
+	MetafieldDefinitionID int64              `json:"metafield_definition_id"`
+	OwnerType             MetafieldOwnerType `json:"owner_type"`
+	Namespace             string             `json:"namespace"`
+	Key                   string             `json:"key"`
+	Name                  string             `json:"name"`
+	Description           *string            `json:"description"`
+	Type                  MetafieldType      `json:"type"`
+	Validations           []byte             `json:"validations"`
+	CreatedAt             pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt             pgtype.Timestamptz `json:"updated_at"`
+	ShopID                int64              `json:"shop_id"`
+}
+
+type Notification struct {
+	NotificationID int64              `json:"notification_id"`
+	Kind           string             `json:"kind"`
+	Recipient      string             `json:"recipient"`
+	Payload        []byte             `json:"payload"`
+	Status         NotificationStatus `json:"status"`
+	Attempts       int32              `json:"attempts"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+	SentAt         pgtype.Timestamptz `json:"sent_at"`
+	ShopID         int64              `json:"shop_id"`
+}
+
 type Order struct {
 	OrderID         int64              `json:"order_id"`
 	Status          OrderStatusType    `json:"status"`
@@ -406,7 +1527,7 @@ type Order struct {
 	Discount        pgtype.Numeric     `json:"discount"`
 	ShippingCost    pgtype.Numeric     `json:"shipping_cost"`
 	Tax             pgtype.Numeric     `json:"tax"`
-	ShippingAddress string             `json:"shipping_address"`
+	ShippingAddress []byte             `json:"shipping_address"`
 	PaymentMethod   PaymentMethodType  `json:"payment_method"`
 	PaymentStatus   PaymentStatusType  `json:"payment_status"`
 	ShippingMethod  string             `json:"shipping_method"`
@@ -422,15 +1543,35 @@ type Order struct {
 	CustomerPhone   *string            `json:"customer_phone"`
 }
 
+type OrderFulfillment struct {
+	FulfillmentID int64              `json:"fulfillment_id"`
+	OrderID       int64              `json:"order_id"`
+	LocationID    *int64             `json:"location_id"`
+	CreatedBy     *string            `json:"created_by"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+	ShopID        int64              `json:"shop_id"`
+}
+
 type OrderItem struct {
-	OrderItemID        int64              `json:"order_item_id"`
-	Quantity           int64              `json:"quantity"`
-	Price              pgtype.Numeric     `json:"price"`
-	CreatedAt          pgtype.Timestamptz `json:"created_at"`
-	UpdatedAt          pgtype.Timestamptz `json:"updated_at"`
-	ProductVariationID int64              `json:"product_variation_id"`
-	OrderID            int64              `json:"order_id"`
-	ShopID             int64              `json:"shop_id"`
+	OrderItemID         int64              `json:"order_item_id"`
+	Quantity            int64              `json:"quantity"`
+	Price               pgtype.Numeric     `json:"price"`
+	CreatedAt           pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt           pgtype.Timestamptz `json:"updated_at"`
+	ProductVariationID  int64              `json:"product_variation_id"`
+	OrderID             int64              `json:"order_id"`
+	ShopID              int64              `json:"shop_id"`
+	CostPrice           pgtype.Numeric     `json:"cost_price"`
+	BackorderedQuantity int64              `json:"backordered_quantity"`
+}
+
+type OrderPresentment struct {
+	OrderID      int64              `json:"order_id"`
+	CurrencyCode string             `json:"currency_code"`
+	ExchangeRate pgtype.Numeric     `json:"exchange_rate"`
+	Amount       pgtype.Numeric     `json:"amount"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+	ShopID       int64              `json:"shop_id"`
 }
 
 type Product struct {
@@ -463,13 +1604,62 @@ type ProductImage struct {
 	ShopID         int64  `json:"shop_id"`
 }
 
+type ProductRating struct {
+	ProductID   int64              `json:"product_id"`
+	RatingCount int32              `json:"rating_count"`
+	RatingSum   int32              `json:"rating_sum"`
+	UpdatedAt   pgtype.Timestamptz `json:"updated_at"`
+	ShopID      int64              `json:"shop_id"`
+}
+
+type ProductReview struct {
+	ReviewID           int64              `json:"review_id"`
+	ProductID          int64              `json:"product_id"`
+	ProductVariationID int64              `json:"product_variation_id"`
+	OrderID            int64              `json:"order_id"`
+	Rating             int16              `json:"rating"`
+	Title              *string            `json:"title"`
+	Body               *string            `json:"body"`
+	Photos             []string           `json:"photos"`
+	CustomerName       string             `json:"customer_name"`
+	CustomerEmail      string             `json:"customer_email"`
+	Status             ReviewStatus       `json:"status"`
+	MerchantReply      *string            `json:"merchant_reply"`
+	RepliedAt          pgtype.Timestamptz `json:"replied_at"`
+	CreatedAt          pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt          pgtype.Timestamptz `json:"updated_at"`
+	ShopID             int64              `json:"shop_id"`
+}
+
+type ProductRevision struct {
+	ProductRevisionID   int64              `json:"product_revision_id"`
+	Version             int32              `json:"version"`
+	Snapshot            []byte             `json:"snapshot"`
+	CreatedBy           *string            `json:"created_by"`
+	RestoredFromVersion *int32             `json:"restored_from_version"`
+	CreatedAt           pgtype.Timestamptz `json:"created_at"`
+	ProductID           int64              `json:"product_id"`
+	ShopID              int64              `json:"shop_id"`
+}
+
+type ProductTag struct {
+	ProductID int64              `json:"product_id"`
+	Tag       string             `json:"tag"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+	ShopID    int64              `json:"shop_id"`
+}
+
 type ProductType struct {
-	ProductTypeID int64   `json:"product_type_id"`
-	Title         string  `json:"title"`
-	Shippable     bool    `json:"shippable"`
-	Digital       bool    `json:"digital"`
-	SkuSubstring  *string `json:"sku_substring"`
-	ShopID        int64   `json:"shop_id"`
+	ProductTypeID   int64   `json:"product_type_id"`
+	Title           string  `json:"title"`
+	Shippable       bool    `json:"shippable"`
+	Digital         bool    `json:"digital"`
+	SkuSubstring    *string `json:"sku_substring"`
+	ShopID          int64   `json:"shop_id"`
+	GiftCard        bool    `json:"gift_card"`
+	TracksLots      bool    `json:"tracks_lots"`
+	ReorderPoint    *int64  `json:"reorder_point"`
+	ReorderQuantity *int64  `json:"reorder_quantity"`
 }
 
 type ProductVariation struct {
@@ -486,6 +1676,12 @@ type ProductVariation struct {
 	UpdatedAt          pgtype.Timestamptz `json:"updated_at"`
 	ProductID          int64              `json:"product_id"`
 	ShopID             int64              `json:"shop_id"`
+	CostPrice          pgtype.Numeric     `json:"cost_price"`
+	InventoryPolicy    InventoryPolicy    `json:"inventory_policy"`
+	PreorderShipsOn    pgtype.Date        `json:"preorder_ships_on"`
+	PreorderLimit      *int64             `json:"preorder_limit"`
+	ReorderPoint       *int64             `json:"reorder_point"`
+	ReorderQuantity    *int64             `json:"reorder_quantity"`
 }
 
 type ProductVariationAttributeValue struct {
@@ -497,6 +1693,39 @@ type ProductVariationAttributeValue struct {
 	ShopID                           int64   `json:"shop_id"`
 }
 
+type ProductVariationPrice struct {
+	ProductVariationID int64              `json:"product_variation_id"`
+	CurrencyCode       string             `json:"currency_code"`
+	Price              pgtype.Numeric     `json:"price"`
+	UpdatedAt          pgtype.Timestamptz `json:"updated_at"`
+	ShopID             int64              `json:"shop_id"`
+}
+
+type PurchaseOrder struct {
+	PurchaseOrderID int64               `json:"purchase_order_id"`
+	SupplierID      int64               `json:"supplier_id"`
+	LocationID      *int64              `json:"location_id"`
+	Status          PurchaseOrderStatus `json:"status"`
+	ExpectedAt      pgtype.Timestamptz  `json:"expected_at"`
+	Note            *string             `json:"note"`
+	CreatedBy       *string             `json:"created_by"`
+	OrderedAt       pgtype.Timestamptz  `json:"ordered_at"`
+	ReceivedAt      pgtype.Timestamptz  `json:"received_at"`
+	CreatedAt       pgtype.Timestamptz  `json:"created_at"`
+	UpdatedAt       pgtype.Timestamptz  `json:"updated_at"`
+	ShopID          int64               `json:"shop_id"`
+}
+
+type PurchaseOrderItem struct {
+	PurchaseOrderItemID int64          `json:"purchase_order_item_id"`
+	PurchaseOrderID     int64          `json:"purchase_order_id"`
+	ProductVariationID  int64          `json:"product_variation_id"`
+	QuantityOrdered     int64          `json:"quantity_ordered"`
+	QuantityReceived    int64          `json:"quantity_received"`
+	UnitCost            pgtype.Numeric `json:"unit_cost"`
+	ShopID              int64          `json:"shop_id"`
+}
+
 type Shop struct {
 	ShopID              int64              `json:"shop_id"`
 	OwnerID             uuid.UUID          `json:"owner_id"`
@@ -522,6 +1751,14 @@ type Shop struct {
 	CreatedAt           pgtype.Timestamptz `json:"created_at"`
 }
 
+type ShopCurrency struct {
+	CurrencyCode string             `json:"currency_code"`
+	ExchangeRate pgtype.Numeric     `json:"exchange_rate"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt    pgtype.Timestamptz `json:"updated_at"`
+	ShopID       int64              `json:"shop_id"`
+}
+
 type ShopCustomer struct {
 	ShopCustomerID uuid.UUID          `json:"shop_customer_id"`
 	Sub            *string            `json:"sub"`
@@ -582,6 +1819,13 @@ type ShopImage struct {
 	ShopID            int64   `json:"shop_id"`
 }
 
+type ShopLocale struct {
+	Locale    string             `json:"locale"`
+	IsDefault bool               `json:"is_default"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+	ShopID    int64              `json:"shop_id"`
+}
+
 type ShopPaymentMethod struct {
 	PaymentMethodID int64              `json:"payment_method_id"`
 	ShopID          int64              `json:"shop_id"`
@@ -592,6 +1836,18 @@ type ShopPaymentMethod struct {
 	UpdatedAt       pgtype.Timestamptz `json:"updated_at"`
 }
 
+type StockAlert struct {
+	AlertID            int64              `json:"alert_id"`
+	ProductVariationID int64              `json:"product_variation_id"`
+	Kind               StockAlertKind     `json:"kind"`
+	AvailableQuantity  int64              `json:"available_quantity"`
+	ReorderPoint       int64              `json:"reorder_point"`
+	ReorderQuantity    *int64             `json:"reorder_quantity"`
+	ReadAt             pgtype.Timestamptz `json:"read_at"`
+	CreatedAt          pgtype.Timestamptz `json:"created_at"`
+	ShopID             int64              `json:"shop_id"`
+}
+
 type StockMovement struct {
 	MovementID         int64              `json:"movement_id"`
 	ProductVariationID int64              `json:"product_variation_id"`
@@ -603,6 +1859,118 @@ type StockMovement struct {
 	ReferenceID        *int64             `json:"reference_id"`
 	Notes              *string            `json:"notes"`
 	CreatedAt          pgtype.Timestamptz `json:"created_at"`
+	LocationID         *int64             `json:"location_id"`
+}
+
+type Stocktake struct {
+	StocktakeID   int64              `json:"stocktake_id"`
+	LocationID    int64              `json:"location_id"`
+	CategoryID    *int64             `json:"category_id"`
+	ProductTypeID *int64             `json:"product_type_id"`
+	Status        StocktakeStatus    `json:"status"`
+	Note          *string            `json:"note"`
+	CreatedBy     *string            `json:"created_by"`
+	CommittedAt   pgtype.Timestamptz `json:"committed_at"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt     pgtype.Timestamptz `json:"updated_at"`
+	ShopID        int64              `json:"shop_id"`
+}
+
+type StocktakeItem struct {
+	StocktakeItemID    int64              `json:"stocktake_item_id"`
+	StocktakeID        int64              `json:"stocktake_id"`
+	ProductVariationID int64              `json:"product_variation_id"`
+	ExpectedQuantity   int64              `json:"expected_quantity"`
+	CountedQuantity    *int64             `json:"counted_quantity"`
+	CountedAt          pgtype.Timestamptz `json:"counted_at"`
+	ShopID             int64              `json:"shop_id"`
+}
+
+type StoreCreditTransaction struct {
+	TransactionID  int64                 `json:"transaction_id"`
+	ShopCustomerID uuid.UUID             `json:"shop_customer_id"`
+	Kind           CreditTransactionKind `json:"kind"`
+	Amount         pgtype.Numeric        `json:"amount"`
+	BalanceAfter   pgtype.Numeric        `json:"balance_after"`
+	Reason         *string               `json:"reason"`
+	OrderID        *int64                `json:"order_id"`
+	Note           *string               `json:"note"`
+	CreatedBy      *string               `json:"created_by"`
+	CreatedAt      pgtype.Timestamptz    `json:"created_at"`
+	ShopID         int64                 `json:"shop_id"`
+}
+
+type Subscription struct {
+	SubscriptionID          int64              `json:"subscription_id"`
+	ShopCustomerID          uuid.UUID          `json:"shop_customer_id"`
+	PlanID                  int64              `json:"plan_id"`
+	Quantity                int64              `json:"quantity"`
+	Status                  SubscriptionStatus `json:"status"`
+	CustomerPaymentMethodID int64              `json:"customer_payment_method_id"`
+	ShippingAddress         []byte             `json:"shipping_address"`
+	ShippingMethod          string             `json:"shipping_method"`
+	NextRenewalAt           pgtype.Timestamptz `json:"next_renewal_at"`
+	PausedUntil             pgtype.Timestamptz `json:"paused_until"`
+	PendingOrderID          *int64             `json:"pending_order_id"`
+	FailedAttempts          int32              `json:"failed_attempts"`
+	NextRetryAt             pgtype.Timestamptz `json:"next_retry_at"`
+	ClaimedUntil            pgtype.Timestamptz `json:"claimed_until"`
+	CancelledAt             pgtype.Timestamptz `json:"cancelled_at"`
+	CancelReason            *string            `json:"cancel_reason"`
+	CreatedAt               pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt               pgtype.Timestamptz `json:"updated_at"`
+	ShopID                  int64              `json:"shop_id"`
+}
+
+type SubscriptionPlan struct {
+	PlanID             int64                `json:"plan_id"`
+	ProductVariationID int64                `json:"product_variation_id"`
+	Name               string               `json:"name"`
+	IntervalUnit       SubscriptionInterval `json:"interval_unit"`
+	IntervalCount      int32                `json:"interval_count"`
+	DiscountPercent    pgtype.Numeric       `json:"discount_percent"`
+	TrialDays          int32                `json:"trial_days"`
+	Active             bool                 `json:"active"`
+	CreatedAt          pgtype.Timestamptz   `json:"created_at"`
+	UpdatedAt          pgtype.Timestamptz   `json:"updated_at"`
+	ShopID             int64                `json:"shop_id"`
+}
+
+type SubscriptionRenewal struct {
+	RenewalID        int64                     `json:"renewal_id"`
+	SubscriptionID   int64                     `json:"subscription_id"`
+	OrderID          *int64                    `json:"order_id"`
+	Status           SubscriptionRenewalStatus `json:"status"`
+	RenewalDate      pgtype.Timestamptz        `json:"renewal_date"`
+	PaymentReference *string                   `json:"payment_reference"`
+	Error            *string                   `json:"error"`
+	CreatedAt        pgtype.Timestamptz        `json:"created_at"`
+	ShopID           int64                     `json:"shop_id"`
+}
+
+type Supplier struct {
+	SupplierID  int64              `json:"supplier_id"`
+	Name        string             `json:"name"`
+	ContactName *string            `json:"contact_name"`
+	Email       *string            `json:"email"`
+	Phone       *string            `json:"phone"`
+	Address     *string            `json:"address"`
+	Notes       *string            `json:"notes"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt   pgtype.Timestamptz `json:"updated_at"`
+	ShopID      int64              `json:"shop_id"`
+}
+
+type Translation struct {
+	TranslationID int64                    `json:"translation_id"`
+	ResourceType  TranslatableResourceType `json:"resource_type"`
+	ResourceID    int64                    `json:"resource_id"`
+	Field         string                   `json:"field"`
+	Locale        string                   `json:"locale"`
+	Value         string                   `json:"value"`
+	CreatedAt     pgtype.Timestamptz       `json:"created_at"`
+	UpdatedAt     pgtype.Timestamptz       `json:"updated_at"`
+	ShopID        int64                    `json:"shop_id"`
 }
 
 type User struct {
@@ -618,3 +1986,10 @@ type User struct {
 	CreatedAt      pgtype.Timestamp `json:"created_at"`
 	LastLogin      pgtype.Timestamp `json:"last_login"`
 }
+
+type WishlistItem struct {
+	ShopCustomerID     uuid.UUID          `json:"shop_customer_id"`
+	ProductVariationID int64              `json:"product_variation_id"`
+	CreatedAt          pgtype.Timestamptz `json:"created_at"`
+	ShopID             int64              `json:"shop_id"`
+}
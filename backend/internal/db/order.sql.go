@@ -45,7 +45,7 @@ type CreateOrderParams struct {
 	Discount        pgtype.Numeric     `json:"discount"`
 	ShippingCost    pgtype.Numeric     `json:"shipping_cost"`
 	Tax             pgtype.Numeric     `json:"tax"`
-	ShippingAddress string             `json:"shipping_address"`
+	ShippingAddress []byte             `json:"shipping_address"`
 	PaymentMethod   PaymentMethodType  `json:"payment_method"`
 	PaymentStatus   PaymentStatusType  `json:"payment_status"`
 	ShippingMethod  string             `json:"shipping_method"`
@@ -107,21 +107,24 @@ func (q *Queries) CreateOrder(ctx context.Context, arg CreateOrderParams) (Order
 
 const createOrderItem = `-- name: CreateOrderItem :one
 INSERT INTO order_items (
-    quantity, price, product_variation_id, order_id, shop_id
+    quantity, price, product_variation_id, order_id, shop_id, backordered_quantity, cost_price
 ) VALUES (
-    $1, $2, $3, $4, $5
+    $1, $2, $3, $4, $5, $6,
+    (SELECT cost_price FROM product_variations WHERE product_variation_id = $3 AND shop_id = $5)
 )
-RETURNING order_item_id, quantity, price, created_at, updated_at, product_variation_id, order_id, shop_id
+RETURNING order_item_id, quantity, price, created_at, updated_at, product_variation_id, order_id, shop_id, cost_price, backordered_quantity
 `
 
 type CreateOrderItemParams struct {
-	Quantity           int64          `json:"quantity"`
-	Price              pgtype.Numeric `json:"price"`
-	ProductVariationID int64          `json:"product_variation_id"`
-	OrderID            int64          `json:"order_id"`
-	ShopID             int64          `json:"shop_id"`
+	Quantity            int64          `json:"quantity"`
+	Price               pgtype.Numeric `json:"price"`
+	ProductVariationID  int64          `json:"product_variation_id"`
+	OrderID             int64          `json:"order_id"`
+	ShopID              int64          `json:"shop_id"`
+	BackorderedQuantity int64          `json:"backordered_quantity"`
 }
 
+// Records an order item with the cost price its variant has at the time of sale
 func (q *Queries) CreateOrderItem(ctx context.Context, arg CreateOrderItemParams) (OrderItem, error) {
 	row := q.db.QueryRow(ctx, createOrderItem,
 		arg.Quantity,
@@ -129,6 +132,7 @@ func (q *Queries) CreateOrderItem(ctx context.Context, arg CreateOrderItemParams
 		arg.ProductVariationID,
 		arg.OrderID,
 		arg.ShopID,
+		arg.BackorderedQuantity,
 	)
 	var i OrderItem
 	err := row.Scan(
@@ -140,6 +144,8 @@ func (q *Queries) CreateOrderItem(ctx context.Context, arg CreateOrderItemParams
 		&i.ProductVariationID,
 		&i.OrderID,
 		&i.ShopID,
+		&i.CostPrice,
+		&i.BackorderedQuantity,
 	)
 	return i, err
 }
@@ -228,7 +234,7 @@ func (q *Queries) GetOrder(ctx context.Context, arg GetOrderParams) (Order, erro
 }
 
 const getOrderItemsByOrder = `-- name: GetOrderItemsByOrder :many
-SELECT order_item_id, quantity, price, created_at, updated_at, product_variation_id, order_id, shop_id FROM order_items
+SELECT order_item_id, quantity, price, created_at, updated_at, product_variation_id, order_id, shop_id, cost_price, backordered_quantity FROM order_items
 WHERE order_id = $1 AND shop_id = $2
 ORDER BY order_item_id
 `
@@ -256,6 +262,8 @@ func (q *Queries) GetOrderItemsByOrder(ctx context.Context, arg GetOrderItemsByO
 			&i.ProductVariationID,
 			&i.OrderID,
 			&i.ShopID,
+			&i.CostPrice,
+			&i.BackorderedQuantity,
 		); err != nil {
 			return nil, err
 		}
@@ -349,7 +357,7 @@ type UpdateOrderParams struct {
 	Discount        pgtype.Numeric     `json:"discount"`
 	ShippingCost    pgtype.Numeric     `json:"shipping_cost"`
 	Tax             pgtype.Numeric     `json:"tax"`
-	ShippingAddress string             `json:"shipping_address"`
+	ShippingAddress []byte             `json:"shipping_address"`
 	PaymentMethod   PaymentMethodType  `json:"payment_method"`
 	PaymentStatus   PaymentStatusType  `json:"payment_status"`
 	ShippingMethod  string             `json:"shipping_method"`
@@ -393,7 +401,7 @@ SET
     price = $2,
     updated_at = NOW()
 WHERE order_item_id = $3 AND shop_id = $4
-RETURNING order_item_id, quantity, price, created_at, updated_at, product_variation_id, order_id, shop_id
+RETURNING order_item_id, quantity, price, created_at, updated_at, product_variation_id, order_id, shop_id, cost_price, backordered_quantity
 `
 
 type UpdateOrderItemParams struct {
@@ -10,17 +10,21 @@ import (
 )
 
 const createProductType = `-- name: CreateProductType :one
-INSERT INTO product_types (title, shippable, digital, sku_substring, shop_id)
-VALUES ($1, $2, $3, $4, $5)
-RETURNING product_type_id, title, shippable, digital, sku_substring, shop_id
+INSERT INTO product_types (title, shippable, digital, sku_substring, gift_card, tracks_lots, reorder_point, reorder_quantity, shop_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING product_type_id, title, shippable, digital, sku_substring, shop_id, gift_card, tracks_lots, reorder_point, reorder_quantity
 `
 
 type CreateProductTypeParams struct {
-	Title        string  `json:"title"`
-	Shippable    bool    `json:"shippable"`
-	Digital      bool    `json:"digital"`
-	SkuSubstring *string `json:"sku_substring"`
-	ShopID       int64   `json:"shop_id"`
+	Title           string  `json:"title"`
+	Shippable       bool    `json:"shippable"`
+	Digital         bool    `json:"digital"`
+	SkuSubstring    *string `json:"sku_substring"`
+	GiftCard        bool    `json:"gift_card"`
+	TracksLots      bool    `json:"tracks_lots"`
+	ReorderPoint    *int64  `json:"reorder_point"`
+	ReorderQuantity *int64  `json:"reorder_quantity"`
+	ShopID          int64   `json:"shop_id"`
 }
 
 func (q *Queries) CreateProductType(ctx context.Context, arg CreateProductTypeParams) (ProductType, error) {
@@ -29,6 +33,10 @@ func (q *Queries) CreateProductType(ctx context.Context, arg CreateProductTypePa
 		arg.Shippable,
 		arg.Digital,
 		arg.SkuSubstring,
+		arg.GiftCard,
+		arg.TracksLots,
+		arg.ReorderPoint,
+		arg.ReorderQuantity,
 		arg.ShopID,
 	)
 	var i ProductType
@@ -39,6 +47,10 @@ func (q *Queries) CreateProductType(ctx context.Context, arg CreateProductTypePa
 		&i.Digital,
 		&i.SkuSubstring,
 		&i.ShopID,
+		&i.GiftCard,
+		&i.TracksLots,
+		&i.ReorderPoint,
+		&i.ReorderQuantity,
 	)
 	return i, err
 }
@@ -46,7 +58,7 @@ func (q *Queries) CreateProductType(ctx context.Context, arg CreateProductTypePa
 const deleteProductType = `-- name: DeleteProductType :one
 DELETE FROM product_types
 WHERE product_type_id = $1 AND shop_id = $2
-RETURNING product_type_id, title, shippable, digital, sku_substring, shop_id
+RETURNING product_type_id, title, shippable, digital, sku_substring, shop_id, gift_card, tracks_lots, reorder_point, reorder_quantity
 `
 
 type DeleteProductTypeParams struct {
@@ -64,12 +76,16 @@ func (q *Queries) DeleteProductType(ctx context.Context, arg DeleteProductTypePa
 		&i.Digital,
 		&i.SkuSubstring,
 		&i.ShopID,
+		&i.GiftCard,
+		&i.TracksLots,
+		&i.ReorderPoint,
+		&i.ReorderQuantity,
 	)
 	return i, err
 }
 
 const getProductType = `-- name: GetProductType :one
-SELECT product_type_id, title, shippable, digital, sku_substring, shop_id FROM product_types WHERE product_type_id = $1 AND shop_id = $2
+SELECT product_type_id, title, shippable, digital, sku_substring, shop_id, gift_card, tracks_lots, reorder_point, reorder_quantity FROM product_types WHERE product_type_id = $1 AND shop_id = $2
 `
 
 type GetProductTypeParams struct {
@@ -87,12 +103,16 @@ func (q *Queries) GetProductType(ctx context.Context, arg GetProductTypeParams)
 		&i.Digital,
 		&i.SkuSubstring,
 		&i.ShopID,
+		&i.GiftCard,
+		&i.TracksLots,
+		&i.ReorderPoint,
+		&i.ReorderQuantity,
 	)
 	return i, err
 }
 
 const getProductTypes = `-- name: GetProductTypes :many
-SELECT product_type_id, title, shippable, digital, sku_substring, shop_id FROM product_types WHERE shop_id = $1
+SELECT product_type_id, title, shippable, digital, sku_substring, shop_id, gift_card, tracks_lots, reorder_point, reorder_quantity FROM product_types WHERE shop_id = $1
 `
 
 func (q *Queries) GetProductTypes(ctx context.Context, shopID int64) ([]ProductType, error) {
@@ -111,6 +131,10 @@ func (q *Queries) GetProductTypes(ctx context.Context, shopID int64) ([]ProductT
 			&i.Digital,
 			&i.SkuSubstring,
 			&i.ShopID,
+			&i.GiftCard,
+			&i.TracksLots,
+			&i.ReorderPoint,
+			&i.ReorderQuantity,
 		); err != nil {
 			return nil, err
 		}
@@ -128,18 +152,26 @@ SET
     title = COALESCE($1, title),
     shippable = COALESCE($2, shippable),
     digital = COALESCE($3, digital),
-    sku_substring = COALESCE($4, sku_substring)
-WHERE product_type_id = $5 AND shop_id = $6
-RETURNING product_type_id, title, shippable, digital, sku_substring, shop_id
+    sku_substring = COALESCE($4, sku_substring),
+    gift_card = COALESCE($5, gift_card),
+    tracks_lots = COALESCE($6, tracks_lots),
+    reorder_point = COALESCE($7, reorder_point),
+    reorder_quantity = COALESCE($8, reorder_quantity)
+WHERE product_type_id = $9 AND shop_id = $10
+RETURNING product_type_id, title, shippable, digital, sku_substring, shop_id, gift_card, tracks_lots, reorder_point, reorder_quantity
 `
 
 type UpdateProductTypeParams struct {
-	Title         *string `json:"title"`
-	Shippable     *bool   `json:"shippable"`
-	Digital       *bool   `json:"digital"`
-	SkuSubstring  *string `json:"sku_substring"`
-	ProductTypeID int64   `json:"product_type_id"`
-	ShopID        int64   `json:"shop_id"`
+	Title           *string `json:"title"`
+	Shippable       *bool   `json:"shippable"`
+	Digital         *bool   `json:"digital"`
+	SkuSubstring    *string `json:"sku_substring"`
+	GiftCard        *bool   `json:"gift_card"`
+	TracksLots      *bool   `json:"tracks_lots"`
+	ReorderPoint    *int64  `json:"reorder_point"`
+	ReorderQuantity *int64  `json:"reorder_quantity"`
+	ProductTypeID   int64   `json:"product_type_id"`
+	ShopID          int64   `json:"shop_id"`
 }
 
 func (q *Queries) UpdateProductType(ctx context.Context, arg UpdateProductTypeParams) (ProductType, error) {
@@ -148,6 +180,10 @@ func (q *Queries) UpdateProductType(ctx context.Context, arg UpdateProductTypePa
 		arg.Shippable,
 		arg.Digital,
 		arg.SkuSubstring,
+		arg.GiftCard,
+		arg.TracksLots,
+		arg.ReorderPoint,
+		arg.ReorderQuantity,
 		arg.ProductTypeID,
 		arg.ShopID,
 	)
@@ -159,6 +195,10 @@ func (q *Queries) UpdateProductType(ctx context.Context, arg UpdateProductTypePa
 		&i.Digital,
 		&i.SkuSubstring,
 		&i.ShopID,
+		&i.GiftCard,
+		&i.TracksLots,
+		&i.ReorderPoint,
+		&i.ReorderQuantity,
 	)
 	return i, err
 }
@@ -62,7 +62,7 @@ VALUES (
     $6, $7, $8,
     $9, $10
 )
-RETURNING product_variation_id, sku, description, price, available_quantity, seo_description, seo_keywords, seo_title, is_default, created_at, updated_at, product_id, shop_id
+RETURNING product_variation_id, sku, description, price, available_quantity, seo_description, seo_keywords, seo_title, is_default, created_at, updated_at, product_id, shop_id, cost_price, inventory_policy, preorder_ships_on, preorder_limit, reorder_point, reorder_quantity
 `
 
 type CreateProductVariationParams struct {
@@ -106,6 +106,12 @@ func (q *Queries) CreateProductVariation(ctx context.Context, arg CreateProductV
 		&i.UpdatedAt,
 		&i.ProductID,
 		&i.ShopID,
+		&i.CostPrice,
+		&i.InventoryPolicy,
+		&i.PreorderShipsOn,
+		&i.PreorderLimit,
+		&i.ReorderPoint,
+		&i.ReorderQuantity,
 	)
 	return i, err
 }
@@ -188,6 +194,7 @@ SELECT
                     'sku', pv.sku,
                     'available_quantity', pv.available_quantity,
                     'is_default', pv.is_default,
+                    'stock_status', variant_stock_status(pv),
                     'attributes', (
                         SELECT COALESCE(
                             jsonb_agg(
@@ -331,7 +338,7 @@ func (q *Queries) GetProductTypeByProduct(ctx context.Context, arg GetProductTyp
 }
 
 const getProductVariants = `-- name: GetProductVariants :many
-SELECT product_variation_id, sku, description, price, available_quantity, seo_description, seo_keywords, seo_title, is_default, created_at, updated_at, product_id, shop_id FROM product_variations
+SELECT product_variation_id, sku, description, price, available_quantity, seo_description, seo_keywords, seo_title, is_default, created_at, updated_at, product_id, shop_id, cost_price, inventory_policy, preorder_ships_on, preorder_limit, reorder_point, reorder_quantity FROM product_variations
 WHERE shop_id = $1 AND product_id = $2
 ORDER BY product_variation_id
 `
@@ -364,6 +371,12 @@ func (q *Queries) GetProductVariants(ctx context.Context, arg GetProductVariants
 			&i.UpdatedAt,
 			&i.ProductID,
 			&i.ShopID,
+			&i.CostPrice,
+			&i.InventoryPolicy,
+			&i.PreorderShipsOn,
+			&i.PreorderLimit,
+			&i.ReorderPoint,
+			&i.ReorderQuantity,
 		); err != nil {
 			return nil, err
 		}
@@ -420,6 +433,7 @@ SELECT
                     'sku', pv.sku,
                     'available_quantity', pv.available_quantity,
                     'is_default', pv.is_default,
+                    'stock_status', variant_stock_status(pv),
                     'attributes', (
                         SELECT COALESCE(
                             jsonb_agg(
@@ -630,6 +644,7 @@ SELECT
                     'sku', pv.sku,
                     'available_quantity', pv.available_quantity,
                     'is_default', pv.is_default,
+                    'stock_status', variant_stock_status(pv),
                     'attributes', (
                         SELECT COALESCE(
                             jsonb_agg(
@@ -781,7 +796,7 @@ SET
     is_default = COALESCE($7, is_default),
     updated_at = NOW()
 WHERE product_variation_id = $8 AND shop_id = $9
-RETURNING product_variation_id, sku, description, price, available_quantity, seo_description, seo_keywords, seo_title, is_default, created_at, updated_at, product_id, shop_id
+RETURNING product_variation_id, sku, description, price, available_quantity, seo_description, seo_keywords, seo_title, is_default, created_at, updated_at, product_id, shop_id, cost_price, inventory_policy, preorder_ships_on, preorder_limit, reorder_point, reorder_quantity
 `
 
 type UpdateProductVariationParams struct {
@@ -823,6 +838,12 @@ func (q *Queries) UpdateProductVariation(ctx context.Context, arg UpdateProductV
 		&i.UpdatedAt,
 		&i.ProductID,
 		&i.ShopID,
+		&i.CostPrice,
+		&i.InventoryPolicy,
+		&i.PreorderShipsOn,
+		&i.PreorderLimit,
+		&i.ReorderPoint,
+		&i.ReorderQuantity,
 	)
 	return i, err
 }
@@ -831,7 +852,7 @@ const updateProductVariationSku = `-- name: UpdateProductVariationSku :one
 UPDATE product_variations
 SET sku = $2
 WHERE product_variation_id = $1 AND shop_id = $3
-RETURNING product_variation_id, sku, description, price, available_quantity, seo_description, seo_keywords, seo_title, is_default, created_at, updated_at, product_id, shop_id
+RETURNING product_variation_id, sku, description, price, available_quantity, seo_description, seo_keywords, seo_title, is_default, created_at, updated_at, product_id, shop_id, cost_price, inventory_policy, preorder_ships_on, preorder_limit, reorder_point, reorder_quantity
 `
 
 type UpdateProductVariationSkuParams struct {
@@ -857,6 +878,12 @@ func (q *Queries) UpdateProductVariationSku(ctx context.Context, arg UpdateProdu
 		&i.UpdatedAt,
 		&i.ProductID,
 		&i.ShopID,
+		&i.CostPrice,
+		&i.InventoryPolicy,
+		&i.PreorderShipsOn,
+		&i.PreorderLimit,
+		&i.ReorderPoint,
+		&i.ReorderQuantity,
 	)
 	return i, err
 }
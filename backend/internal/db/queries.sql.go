@@ -13,35 +13,34 @@ import (
 )
 
 const addVariantStock = `-- name: AddVariantStock :one
-UPDATE product_variations
-SET available_quantity = available_quantity + $3,
+UPDATE inventory_levels
+SET available = available + $4,
     updated_at = NOW()
-WHERE product_variation_id = $1 AND shop_id = $2
-RETURNING product_variation_id, sku, description, price, available_quantity, seo_description, seo_keywords, seo_title, is_default, created_at, updated_at, product_id, shop_id
+WHERE product_variation_id = $1 AND location_id = $2 AND shop_id = $3
+RETURNING product_variation_id, location_id, available, incoming, updated_at, shop_id
 `
 
 type AddVariantStockParams struct {
 	ProductVariationID int64 `json:"product_variation_id"`
+	LocationID         int64 `json:"location_id"`
 	ShopID             int64 `json:"shop_id"`
-	AvailableQuantity  int64 `json:"available_quantity"`
+	Available          int64 `json:"available"`
 }
 
-func (q *Queries) AddVariantStock(ctx context.Context, arg AddVariantStockParams) (ProductVariation, error) {
-	row := q.db.QueryRow(ctx, addVariantStock, arg.ProductVariationID, arg.ShopID, arg.AvailableQuantity)
-	var i ProductVariation
+func (q *Queries) AddVariantStock(ctx context.Context, arg AddVariantStockParams) (InventoryLevel, error) {
+	row := q.db.QueryRow(ctx, addVariantStock,
+		arg.ProductVariationID,
+		arg.LocationID,
+		arg.ShopID,
+		arg.Available,
+	)
+	var i InventoryLevel
 	err := row.Scan(
 		&i.ProductVariationID,
-		&i.Sku,
-		&i.Description,
-		&i.Price,
-		&i.AvailableQuantity,
-		&i.SeoDescription,
-		&i.SeoKeywords,
-		&i.SeoTitle,
-		&i.IsDefault,
-		&i.CreatedAt,
+		&i.LocationID,
+		&i.Available,
+		&i.Incoming,
 		&i.UpdatedAt,
-		&i.ProductID,
 		&i.ShopID,
 	)
 	return i, err
@@ -56,9 +55,10 @@ INSERT INTO stock_movements (
     quantity_before, 
     quantity_after, 
     reference_id, 
-    notes
-) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
-RETURNING movement_id, product_variation_id, shop_id, movement_type, quantity_change, quantity_before, quantity_after, reference_id, notes, created_at
+    notes,
+    location_id
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING movement_id, product_variation_id, shop_id, movement_type, quantity_change, quantity_before, quantity_after, reference_id, notes, created_at, location_id
 `
 
 type CreateStockMovementParams struct {
@@ -70,6 +70,7 @@ type CreateStockMovementParams struct {
 	QuantityAfter      int32   `json:"quantity_after"`
 	ReferenceID        *int64  `json:"reference_id"`
 	Notes              *string `json:"notes"`
+	LocationID         *int64  `json:"location_id"`
 }
 
 func (q *Queries) CreateStockMovement(ctx context.Context, arg CreateStockMovementParams) (StockMovement, error) {
@@ -82,6 +83,7 @@ func (q *Queries) CreateStockMovement(ctx context.Context, arg CreateStockMoveme
 		arg.QuantityAfter,
 		arg.ReferenceID,
 		arg.Notes,
+		arg.LocationID,
 	)
 	var i StockMovement
 	err := row.Scan(
@@ -95,40 +97,41 @@ func (q *Queries) CreateStockMovement(ctx context.Context, arg CreateStockMoveme
 		&i.ReferenceID,
 		&i.Notes,
 		&i.CreatedAt,
+		&i.LocationID,
 	)
 	return i, err
 }
 
 const deductVariantStock = `-- name: DeductVariantStock :one
-UPDATE product_variations
-SET available_quantity = GREATEST(available_quantity - $3, 0),
+UPDATE inventory_levels
+SET available = available - $4,
     updated_at = NOW()
-WHERE product_variation_id = $1 AND shop_id = $2
-RETURNING product_variation_id, sku, description, price, available_quantity, seo_description, seo_keywords, seo_title, is_default, created_at, updated_at, product_id, shop_id
+WHERE product_variation_id = $1 AND location_id = $2 AND shop_id = $3
+AND available >= $4
+RETURNING product_variation_id, location_id, available, incoming, updated_at, shop_id
 `
 
 type DeductVariantStockParams struct {
 	ProductVariationID int64 `json:"product_variation_id"`
+	LocationID         int64 `json:"location_id"`
 	ShopID             int64 `json:"shop_id"`
-	AvailableQuantity  int64 `json:"available_quantity"`
+	Available          int64 `json:"available"`
 }
 
-func (q *Queries) DeductVariantStock(ctx context.Context, arg DeductVariantStockParams) (ProductVariation, error) {
-	row := q.db.QueryRow(ctx, deductVariantStock, arg.ProductVariationID, arg.ShopID, arg.AvailableQuantity)
-	var i ProductVariation
+func (q *Queries) DeductVariantStock(ctx context.Context, arg DeductVariantStockParams) (InventoryLevel, error) {
+	row := q.db.QueryRow(ctx, deductVariantStock,
+		arg.ProductVariationID,
+		arg.LocationID,
+		arg.ShopID,
+		arg.Available,
+	)
+	var i InventoryLevel
 	err := row.Scan(
 		&i.ProductVariationID,
-		&i.Sku,
-		&i.Description,
-		&i.Price,
-		&i.AvailableQuantity,
-		&i.SeoDescription,
-		&i.SeoKeywords,
-		&i.SeoTitle,
-		&i.IsDefault,
-		&i.CreatedAt,
+		&i.LocationID,
+		&i.Available,
+		&i.Incoming,
 		&i.UpdatedAt,
-		&i.ProductID,
 		&i.ShopID,
 	)
 	return i, err
@@ -232,7 +235,7 @@ type GetCustomerOrdersRow struct {
 	Discount           pgtype.Numeric     `json:"discount"`
 	ShippingCost       pgtype.Numeric     `json:"shipping_cost"`
 	Tax                pgtype.Numeric     `json:"tax"`
-	ShippingAddress    string             `json:"shipping_address"`
+	ShippingAddress    []byte             `json:"shipping_address"`
 	PaymentMethod      PaymentMethodType  `json:"payment_method"`
 	PaymentStatus      PaymentStatusType  `json:"payment_status"`
 	ShippingMethod     string             `json:"shipping_method"`
@@ -375,18 +378,20 @@ SELECT
     pv.updated_at,
     CASE 
         WHEN pv.available_quantity = 0 THEN 'OUT_OF_STOCK'
-        WHEN pv.available_quantity <= $2 THEN 'LOW_STOCK'
+        WHEN pv.available_quantity <= COALESCE(pv.reorder_point, pt.reorder_point, $1::bigint) THEN 'LOW_STOCK'
         ELSE 'IN_STOCK'
-    END as stock_status
+    END as stock_status,
+    COALESCE(pv.reorder_point, pt.reorder_point, $1::bigint)::bigint as low_stock_threshold
 FROM product_variations pv
 JOIN products p ON pv.product_id = p.product_id
-WHERE pv.shop_id = $1
+JOIN product_types pt ON pt.product_type_id = p.product_type_id
+WHERE pv.shop_id = $2
 ORDER BY pv.available_quantity ASC
 `
 
 type GetInventoryReportParams struct {
-	ShopID            int64 `json:"shop_id"`
-	AvailableQuantity int64 `json:"available_quantity"`
+	DefaultThreshold int64 `json:"default_threshold"`
+	ShopID           int64 `json:"shop_id"`
 }
 
 type GetInventoryReportRow struct {
@@ -402,10 +407,11 @@ type GetInventoryReportRow struct {
 	StockValue         pgtype.Numeric     `json:"stock_value"`
 	UpdatedAt          pgtype.Timestamptz `json:"updated_at"`
 	StockStatus        string             `json:"stock_status"`
+	LowStockThreshold  int64              `json:"low_stock_threshold"`
 }
 
 func (q *Queries) GetInventoryReport(ctx context.Context, arg GetInventoryReportParams) ([]GetInventoryReportRow, error) {
-	rows, err := q.db.Query(ctx, getInventoryReport, arg.ShopID, arg.AvailableQuantity)
+	rows, err := q.db.Query(ctx, getInventoryReport, arg.DefaultThreshold, arg.ShopID)
 	if err != nil {
 		return nil, err
 	}
@@ -426,6 +432,7 @@ func (q *Queries) GetInventoryReport(ctx context.Context, arg GetInventoryReport
 			&i.StockValue,
 			&i.UpdatedAt,
 			&i.StockStatus,
+			&i.LowStockThreshold,
 		); err != nil {
 			return nil, err
 		}
@@ -438,17 +445,19 @@ func (q *Queries) GetInventoryReport(ctx context.Context, arg GetInventoryReport
 }
 
 const getLowStockVariants = `-- name: GetLowStockVariants :many
-SELECT pv.product_variation_id, pv.sku, pv.description, pv.price, pv.available_quantity, pv.seo_description, pv.seo_keywords, pv.seo_title, pv.is_default, pv.created_at, pv.updated_at, pv.product_id, pv.shop_id, p.title as product_title
+SELECT pv.product_variation_id, pv.sku, pv.description, pv.price, pv.available_quantity, pv.seo_description, pv.seo_keywords, pv.seo_title, pv.is_default, pv.created_at, pv.updated_at, pv.product_id, pv.shop_id, pv.cost_price, pv.inventory_policy, pv.preorder_ships_on, pv.preorder_limit, pv.reorder_point, pv.reorder_quantity, p.title as product_title,
+    COALESCE($1::bigint, pv.reorder_point, pt.reorder_point, 10)::bigint AS reorder_level
 FROM product_variations pv
 JOIN products p ON pv.product_id = p.product_id
-WHERE pv.shop_id = $1 
-AND pv.available_quantity <= $2
+JOIN product_types pt ON pt.product_type_id = p.product_type_id
+WHERE pv.shop_id = $2
+AND pv.available_quantity <= COALESCE($1::bigint, pv.reorder_point, pt.reorder_point, 10)
 ORDER BY pv.available_quantity ASC
 `
 
 type GetLowStockVariantsParams struct {
-	ShopID            int64 `json:"shop_id"`
-	AvailableQuantity int64 `json:"available_quantity"`
+	Threshold *int64 `json:"threshold"`
+	ShopID    int64  `json:"shop_id"`
 }
 
 type GetLowStockVariantsRow struct {
@@ -465,11 +474,20 @@ type GetLowStockVariantsRow struct {
 	UpdatedAt          pgtype.Timestamptz `json:"updated_at"`
 	ProductID          int64              `json:"product_id"`
 	ShopID             int64              `json:"shop_id"`
+	CostPrice          pgtype.Numeric     `json:"cost_price"`
+	InventoryPolicy    InventoryPolicy    `json:"inventory_policy"`
+	PreorderShipsOn    pgtype.Date        `json:"preorder_ships_on"`
+	PreorderLimit      *int64             `json:"preorder_limit"`
+	ReorderPoint       *int64             `json:"reorder_point"`
+	ReorderQuantity    *int64             `json:"reorder_quantity"`
 	ProductTitle       string             `json:"product_title"`
+	ReorderLevel       int64              `json:"reorder_level"`
 }
 
+// Variants at or below the threshold, or at or below their reorder point when no
+// threshold is given. Variants without a reorder point use a threshold of 10.
 func (q *Queries) GetLowStockVariants(ctx context.Context, arg GetLowStockVariantsParams) ([]GetLowStockVariantsRow, error) {
-	rows, err := q.db.Query(ctx, getLowStockVariants, arg.ShopID, arg.AvailableQuantity)
+	rows, err := q.db.Query(ctx, getLowStockVariants, arg.Threshold, arg.ShopID)
 	if err != nil {
 		return nil, err
 	}
@@ -491,7 +509,14 @@ func (q *Queries) GetLowStockVariants(ctx context.Context, arg GetLowStockVarian
 			&i.UpdatedAt,
 			&i.ProductID,
 			&i.ShopID,
+			&i.CostPrice,
+			&i.InventoryPolicy,
+			&i.PreorderShipsOn,
+			&i.PreorderLimit,
+			&i.ReorderPoint,
+			&i.ReorderQuantity,
 			&i.ProductTitle,
+			&i.ReorderLevel,
 		); err != nil {
 			return nil, err
 		}
@@ -542,7 +567,7 @@ func (q *Queries) GetOrderByTransactionID(ctx context.Context, arg GetOrderByTra
 }
 
 const getProductVariation = `-- name: GetProductVariation :one
-SELECT product_variation_id, sku, description, price, available_quantity, seo_description, seo_keywords, seo_title, is_default, created_at, updated_at, product_id, shop_id FROM product_variations
+SELECT product_variation_id, sku, description, price, available_quantity, seo_description, seo_keywords, seo_title, is_default, created_at, updated_at, product_id, shop_id, cost_price, inventory_policy, preorder_ships_on, preorder_limit, reorder_point, reorder_quantity FROM product_variations
 WHERE product_variation_id = $1 AND shop_id = $2
 `
 
@@ -568,13 +593,19 @@ func (q *Queries) GetProductVariation(ctx context.Context, arg GetProductVariati
 		&i.UpdatedAt,
 		&i.ProductID,
 		&i.ShopID,
+		&i.CostPrice,
+		&i.InventoryPolicy,
+		&i.PreorderShipsOn,
+		&i.PreorderLimit,
+		&i.ReorderPoint,
+		&i.ReorderQuantity,
 	)
 	return i, err
 }
 
 const getStockMovements = `-- name: GetStockMovements :many
 SELECT 
-    sm.movement_id, sm.product_variation_id, sm.shop_id, sm.movement_type, sm.quantity_change, sm.quantity_before, sm.quantity_after, sm.reference_id, sm.notes, sm.created_at,
+    sm.movement_id, sm.product_variation_id, sm.shop_id, sm.movement_type, sm.quantity_change, sm.quantity_before, sm.quantity_after, sm.reference_id, sm.notes, sm.created_at, sm.location_id,
     p.title as product_title,
     pv.description as variant_title,
     pv.sku
@@ -603,6 +634,7 @@ type GetStockMovementsRow struct {
 	ReferenceID        *int64             `json:"reference_id"`
 	Notes              *string            `json:"notes"`
 	CreatedAt          pgtype.Timestamptz `json:"created_at"`
+	LocationID         *int64             `json:"location_id"`
 	ProductTitle       string             `json:"product_title"`
 	VariantTitle       string             `json:"variant_title"`
 	Sku                string             `json:"sku"`
@@ -628,6 +660,7 @@ func (q *Queries) GetStockMovements(ctx context.Context, arg GetStockMovementsPa
 			&i.ReferenceID,
 			&i.Notes,
 			&i.CreatedAt,
+			&i.LocationID,
 			&i.ProductTitle,
 			&i.VariantTitle,
 			&i.Sku,
@@ -977,35 +1010,34 @@ func (q *Queries) UpdateOrderStatusByTransactionID(ctx context.Context, arg Upda
 }
 
 const updateVariantStock = `-- name: UpdateVariantStock :one
-UPDATE product_variations
-SET available_quantity = $3,
+UPDATE inventory_levels
+SET available = $4,
     updated_at = NOW()
-WHERE product_variation_id = $1 AND shop_id = $2
-RETURNING product_variation_id, sku, description, price, available_quantity, seo_description, seo_keywords, seo_title, is_default, created_at, updated_at, product_id, shop_id
+WHERE product_variation_id = $1 AND location_id = $2 AND shop_id = $3
+RETURNING product_variation_id, location_id, available, incoming, updated_at, shop_id
 `
 
 type UpdateVariantStockParams struct {
 	ProductVariationID int64 `json:"product_variation_id"`
+	LocationID         int64 `json:"location_id"`
 	ShopID             int64 `json:"shop_id"`
-	AvailableQuantity  int64 `json:"available_quantity"`
+	Available          int64 `json:"available"`
 }
 
-func (q *Queries) UpdateVariantStock(ctx context.Context, arg UpdateVariantStockParams) (ProductVariation, error) {
-	row := q.db.QueryRow(ctx, updateVariantStock, arg.ProductVariationID, arg.ShopID, arg.AvailableQuantity)
-	var i ProductVariation
+func (q *Queries) UpdateVariantStock(ctx context.Context, arg UpdateVariantStockParams) (InventoryLevel, error) {
+	row := q.db.QueryRow(ctx, updateVariantStock,
+		arg.ProductVariationID,
+		arg.LocationID,
+		arg.ShopID,
+		arg.Available,
+	)
+	var i InventoryLevel
 	err := row.Scan(
 		&i.ProductVariationID,
-		&i.Sku,
-		&i.Description,
-		&i.Price,
-		&i.AvailableQuantity,
-		&i.SeoDescription,
-		&i.SeoKeywords,
-		&i.SeoTitle,
-		&i.IsDefault,
-		&i.CreatedAt,
+		&i.LocationID,
+		&i.Available,
+		&i.Incoming,
 		&i.UpdatedAt,
-		&i.ProductID,
 		&i.ShopID,
 	)
 	return i, err
@@ -16,19 +16,25 @@ GROUP BY period
 ORDER BY period ASC;
 
 -- name: GetTopProducts :many
+-- Ranks the variants sold by units sold, or by gross profit when sort_by is 'profit'.
+-- Profit only counts the items whose cost price was known when they were sold.
 SELECT
   oi.product_variation_id,
   p.title AS product_name,
   COALESCE(SUM(oi.quantity), 0) AS units_sold,
-  COALESCE(SUM(oi.quantity * oi.price), 0) AS revenue
+  COALESCE(SUM(oi.quantity * oi.price), 0) AS revenue,
+  COALESCE(SUM(oi.quantity * oi.cost_price), 0)::numeric AS cost_of_goods,
+  COALESCE(SUM(oi.quantity * (oi.price - oi.cost_price)), 0)::numeric AS gross_profit
 FROM order_items oi
 JOIN orders o ON oi.order_id = o.order_id
 JOIN product_variations pv ON oi.product_variation_id = pv.product_variation_id
 JOIN products p ON pv.product_id = p.product_id
-WHERE o.shop_id = $1 AND o.created_at BETWEEN $2 AND $3 AND o.status = 'completed'
+WHERE o.shop_id = sqlc.arg('shop_id') AND o.created_at BETWEEN sqlc.arg('start_at') AND sqlc.arg('end_at') AND o.status = 'completed'
 GROUP BY oi.product_variation_id, p.title
-ORDER BY units_sold DESC
-LIMIT $4;
+ORDER BY
+  CASE WHEN sqlc.arg('sort_by')::text = 'profit' THEN COALESCE(SUM(oi.quantity * (oi.price - oi.cost_price)), 0) END DESC,
+  units_sold DESC
+LIMIT sqlc.arg('limit');
 
 -- name: GetCustomerSummaryNewReturning :one
 SELECT
@@ -52,13 +58,78 @@ ORDER BY total_spent DESC
 LIMIT 5;
 
 -- name: GetLowStockProducts :many
+-- Variants at or below the threshold, or at or below their reorder point when no
+-- threshold is given. Variants without a reorder point use a threshold of 5.
 SELECT
   pv.product_variation_id,
   p.title AS product_name,
   pv.sku,
   pv.description,
-  pv.available_quantity AS stock
+  pv.available_quantity AS stock,
+  COALESCE(sqlc.narg('threshold')::bigint, pv.reorder_point, pt.reorder_point, 5)::bigint AS reorder_level
 FROM product_variations pv
 JOIN products p ON pv.product_id = p.product_id
-WHERE pv.shop_id = $1 AND pv.available_quantity <= $2
-ORDER BY pv.available_quantity ASC; 
\ No newline at end of file
+JOIN product_types pt ON pt.product_type_id = p.product_type_id
+WHERE pv.shop_id = sqlc.arg('shop_id') AND pv.available_quantity <= COALESCE(sqlc.narg('threshold')::bigint, pv.reorder_point, pt.reorder_point, 5)
+ORDER BY pv.available_quantity ASC;
+
+-- name: GetProfitSummary :one
+-- Revenue and cost of the items of completed orders. Gross profit and the revenue it
+-- is earned on only count the items whose cost price was known when they were sold.
+SELECT
+  COALESCE(SUM(oi.quantity * oi.price), 0)::numeric AS revenue,
+  COALESCE(SUM(oi.quantity * oi.price) FILTER (WHERE oi.cost_price IS NOT NULL), 0)::numeric AS costed_revenue,
+  COALESCE(SUM(oi.quantity * oi.cost_price), 0)::numeric AS cost_of_goods,
+  COALESCE(SUM(oi.quantity), 0)::bigint AS units_sold,
+  COALESCE(SUM(oi.quantity) FILTER (WHERE oi.cost_price IS NULL), 0)::bigint AS units_without_cost
+FROM order_items oi
+JOIN orders o ON oi.order_id = o.order_id
+WHERE o.shop_id = sqlc.arg('shop_id') AND o.created_at BETWEEN sqlc.arg('start_at') AND sqlc.arg('end_at') AND o.status = 'completed';
+
+-- name: GetProfitByProduct :many
+SELECT
+  p.product_id,
+  p.title AS product_name,
+  COALESCE(SUM(oi.quantity), 0)::bigint AS units_sold,
+  COALESCE(SUM(oi.quantity * oi.price), 0)::numeric AS revenue,
+  COALESCE(SUM(oi.quantity * oi.price) FILTER (WHERE oi.cost_price IS NOT NULL), 0)::numeric AS costed_revenue,
+  COALESCE(SUM(oi.quantity * oi.cost_price), 0)::numeric AS cost_of_goods
+FROM order_items oi
+JOIN orders o ON oi.order_id = o.order_id
+JOIN product_variations pv ON oi.product_variation_id = pv.product_variation_id
+JOIN products p ON pv.product_id = p.product_id
+WHERE o.shop_id = sqlc.arg('shop_id') AND o.created_at BETWEEN sqlc.arg('start_at') AND sqlc.arg('end_at') AND o.status = 'completed'
+GROUP BY p.product_id, p.title
+ORDER BY COALESCE(SUM(oi.quantity * (oi.price - oi.cost_price)), 0) DESC, p.product_id
+LIMIT sqlc.arg('limit');
+
+-- name: GetProfitByCategory :many
+-- Groups profit by the category of each product; products without a category are
+-- grouped under a NULL category
+SELECT
+  c.category_id,
+  c.title AS category_name,
+  COALESCE(SUM(oi.quantity), 0)::bigint AS units_sold,
+  COALESCE(SUM(oi.quantity * oi.price), 0)::numeric AS revenue,
+  COALESCE(SUM(oi.quantity * oi.price) FILTER (WHERE oi.cost_price IS NOT NULL), 0)::numeric AS costed_revenue,
+  COALESCE(SUM(oi.quantity * oi.cost_price), 0)::numeric AS cost_of_goods
+FROM order_items oi
+JOIN orders o ON oi.order_id = o.order_id
+JOIN product_variations pv ON oi.product_variation_id = pv.product_variation_id
+JOIN products p ON pv.product_id = p.product_id
+LEFT JOIN categories c ON p.category_id = c.category_id
+WHERE o.shop_id = sqlc.arg('shop_id') AND o.created_at BETWEEN sqlc.arg('start_at') AND sqlc.arg('end_at') AND o.status = 'completed'
+GROUP BY c.category_id, c.title
+ORDER BY COALESCE(SUM(oi.quantity * (oi.price - oi.cost_price)), 0) DESC, c.category_id;
+
+-- name: GetProfitOverTime :many
+SELECT
+  DATE_TRUNC(sqlc.arg('interval'), o.created_at)::date AS period,
+  COALESCE(SUM(oi.quantity * oi.price), 0)::numeric AS revenue,
+  COALESCE(SUM(oi.quantity * oi.price) FILTER (WHERE oi.cost_price IS NOT NULL), 0)::numeric AS costed_revenue,
+  COALESCE(SUM(oi.quantity * oi.cost_price), 0)::numeric AS cost_of_goods
+FROM order_items oi
+JOIN orders o ON oi.order_id = o.order_id
+WHERE o.shop_id = sqlc.arg('shop_id') AND o.created_at BETWEEN sqlc.arg('start_at') AND sqlc.arg('end_at') AND o.status = 'completed'
+GROUP BY period
+ORDER BY period ASC;
@@ -0,0 +1,57 @@
+-- name: ListBundleComponents :many
+SELECT
+    bc.*,
+    p.product_id,
+    p.title AS product_title,
+    pv.description AS variant_title,
+    pv.sku,
+    pv.available_quantity
+FROM bundle_components bc
+JOIN product_variations pv ON pv.product_variation_id = bc.component_variation_id
+JOIN products p ON p.product_id = pv.product_id
+WHERE bc.bundle_variation_id = $1 AND bc.shop_id = $2
+ORDER BY p.title, pv.sku;
+
+-- name: CreateBundleComponent :one
+INSERT INTO bundle_components (bundle_variation_id, component_variation_id, quantity, shop_id)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: DeleteBundleComponents :exec
+DELETE FROM bundle_components
+WHERE bundle_variation_id = $1 AND shop_id = $2;
+
+-- name: VariantIsBundle :one
+SELECT EXISTS (
+    SELECT 1 FROM bundle_components
+    WHERE bundle_variation_id = $1 AND shop_id = $2
+)::boolean AS is_bundle;
+
+-- name: VariantIsBundleComponent :one
+SELECT EXISTS (
+    SELECT 1 FROM bundle_components
+    WHERE component_variation_id = $1 AND shop_id = $2
+)::boolean AS is_component;
+
+-- name: SyncBundleAvailableQuantities :exec
+-- Sets the available quantity of bundle variants to the number of bundles the
+-- stock of their components at the locations that sell online makes up. Limited
+-- to a bundle, or to the bundles containing a component, when a variant is given.
+UPDATE product_variations pv
+SET available_quantity = (
+        SELECT COALESCE(MIN(COALESCE((
+            SELECT SUM(il.available)
+            FROM inventory_levels il
+            JOIN locations l ON l.location_id = il.location_id
+            WHERE il.product_variation_id = bc.component_variation_id AND l.sells_online
+        ), 0)::bigint / bc.quantity), 0)
+        FROM bundle_components bc
+        WHERE bc.bundle_variation_id = pv.product_variation_id
+    )::bigint,
+    updated_at = NOW()
+WHERE pv.shop_id = sqlc.arg('shop_id')
+AND EXISTS (SELECT 1 FROM bundle_components bc WHERE bc.bundle_variation_id = pv.product_variation_id)
+AND (sqlc.narg('variant_id')::bigint IS NULL OR pv.product_variation_id = sqlc.narg('variant_id') OR EXISTS (
+    SELECT 1 FROM bundle_components bc
+    WHERE bc.bundle_variation_id = pv.product_variation_id AND bc.component_variation_id = sqlc.narg('variant_id')
+));
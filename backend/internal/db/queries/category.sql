@@ -4,7 +4,7 @@ VALUES ($1, $2, $3, $4, $5)
 RETURNING *;
 
 -- name: GetCategory :one
-SELECT category_id, slug, title, description, parent_id
+SELECT category_id, slug, title, description, parent_id, banner_url
 FROM categories
 WHERE shop_id = $1 AND category_id = $2;
 
@@ -17,6 +17,12 @@ SET
 WHERE category_id = sqlc.arg('category_id')
 RETURNING *;
 
+-- name: UpdateCategoryBanner :one
+UPDATE categories
+SET banner_url = $1
+WHERE category_id = $2 AND shop_id = $3
+RETURNING *;
+
 -- name: GetCategories :many
 SELECT category_id, slug, title, description
 FROM categories
@@ -0,0 +1,189 @@
+-- name: CreateCollection :one
+INSERT INTO collections (handle, title, description, collection_type, rules, match_all, sort_order, shop_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING *;
+
+-- name: GetCollection :one
+SELECT * FROM collections
+WHERE collection_id = $1 AND shop_id = $2;
+
+-- name: GetCollectionByHandle :one
+SELECT * FROM collections
+WHERE handle = $1 AND shop_id = $2;
+
+-- name: ListCollections :many
+SELECT * FROM collections
+WHERE shop_id = sqlc.arg('shop_id')
+ORDER BY collection_id
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CountCollections :one
+SELECT COUNT(*) FROM collections
+WHERE shop_id = $1;
+
+-- name: GetCollections :many
+SELECT * FROM collections
+WHERE shop_id = sqlc.arg('shop_id') AND collection_id > sqlc.arg('after')
+ORDER BY collection_id
+LIMIT sqlc.arg('limit');
+
+-- name: UpdateCollection :one
+UPDATE collections
+SET
+    handle = COALESCE(sqlc.narg('handle'), handle),
+    title = COALESCE(sqlc.narg('title'), title),
+    description = COALESCE(sqlc.narg('description'), description),
+    rules = COALESCE(sqlc.narg('rules'), rules),
+    match_all = COALESCE(sqlc.narg('match_all'), match_all),
+    sort_order = COALESCE(sqlc.narg('sort_order'), sort_order),
+    updated_at = NOW()
+WHERE collection_id = sqlc.arg('collection_id') AND shop_id = sqlc.arg('shop_id')
+RETURNING *;
+
+-- name: DeleteCollection :execrows
+DELETE FROM collections
+WHERE collection_id = $1 AND shop_id = $2;
+
+-- name: AddCollectionProduct :one
+INSERT INTO collection_products (collection_id, product_id, position, shop_id)
+VALUES (
+    sqlc.arg('collection_id'),
+    sqlc.arg('product_id'),
+    COALESCE(sqlc.narg('position'), (
+        SELECT COALESCE(MAX(cp.position), 0) + 1
+        FROM collection_products cp
+        WHERE cp.collection_id = sqlc.arg('collection_id')
+    )),
+    sqlc.arg('shop_id')
+)
+ON CONFLICT (collection_id, product_id)
+DO UPDATE SET position = EXCLUDED.position
+RETURNING *;
+
+-- name: RemoveCollectionProduct :execrows
+DELETE FROM collection_products
+WHERE collection_id = $1 AND product_id = $2 AND shop_id = $3;
+
+-- name: ReorderCollectionProducts :exec
+-- Listed products take positions 1..n in the given order, the rest follow in their current order
+UPDATE collection_products cp
+SET position = ranked.new_position
+FROM (
+    SELECT
+        cp2.product_id,
+        ROW_NUMBER() OVER (ORDER BY o.ord NULLS LAST, cp2.position) AS new_position
+    FROM collection_products cp2
+    LEFT JOIN unnest(sqlc.arg('product_ids')::bigint[]) WITH ORDINALITY AS o(product_id, ord)
+        ON o.product_id = cp2.product_id
+    WHERE cp2.collection_id = sqlc.arg('collection_id') AND cp2.shop_id = sqlc.arg('shop_id')
+) ranked
+WHERE cp.collection_id = sqlc.arg('collection_id') AND cp.product_id = ranked.product_id;
+
+-- name: GetCollectionProducts :many
+-- Manual collections return their member products; smart collections evaluate their rules
+-- against every product of the shop. Each rule is {field, operator, value[, attribute_id]}.
+WITH sales AS (
+    SELECT
+        pv.product_id,
+        SUM(oi.quantity)::bigint AS units_sold,
+        RANK() OVER (ORDER BY SUM(oi.quantity) DESC) AS sales_rank
+    FROM order_items oi
+    JOIN product_variations pv ON pv.product_variation_id = oi.product_variation_id
+    JOIN orders o ON o.order_id = oi.order_id
+    WHERE oi.shop_id = sqlc.arg('shop_id') AND o.status NOT IN ('cancelled', 'refunded')
+    GROUP BY pv.product_id
+),
+members AS (
+    SELECT
+        p.product_id,
+        p.slug,
+        p.title,
+        p.status,
+        p.created_at,
+        dv.price,
+        COALESCE(s.units_sold, 0)::bigint AS units_sold,
+        cp.position,
+        c.sort_order
+    FROM collections c
+    JOIN products p ON p.shop_id = c.shop_id
+    LEFT JOIN product_variations dv ON dv.product_id = p.product_id AND dv.is_default = TRUE
+    LEFT JOIN sales s ON s.product_id = p.product_id
+    LEFT JOIN collection_products cp ON cp.collection_id = c.collection_id AND cp.product_id = p.product_id
+    CROSS JOIN LATERAL (
+        SELECT
+            COUNT(*) AS rule_count,
+            COUNT(*) FILTER (WHERE
+                CASE r.rule->>'field'
+                    WHEN 'price' THEN CASE r.rule->>'operator'
+                        WHEN 'eq' THEN dv.price = (r.rule->>'value')::numeric
+                        WHEN 'gt' THEN dv.price > (r.rule->>'value')::numeric
+                        WHEN 'gte' THEN dv.price >= (r.rule->>'value')::numeric
+                        WHEN 'lt' THEN dv.price < (r.rule->>'value')::numeric
+                        WHEN 'lte' THEN dv.price <= (r.rule->>'value')::numeric
+                    END
+                    WHEN 'product_type' THEN CASE r.rule->>'operator'
+                        WHEN 'eq' THEN p.product_type_id = (r.rule->>'value')::bigint
+                        WHEN 'neq' THEN p.product_type_id <> (r.rule->>'value')::bigint
+                    END
+                    -- 'neq' inverts the match, so the product must not carry the value on itself or any variant
+                    WHEN 'attribute' THEN (r.rule->>'operator' = 'neq') <> EXISTS (
+                        SELECT 1
+                        FROM product_attribute_values pav
+                        LEFT JOIN attribute_options ao ON ao.attribute_option_id = pav.attribute_option_id
+                        WHERE pav.product_id = p.product_id
+                        AND pav.attribute_id = (r.rule->>'attribute_id')::bigint
+                        AND LOWER(COALESCE(ao.value, pav.value)) = LOWER(r.rule->>'value')
+                        UNION ALL
+                        SELECT 1
+                        FROM product_variation_attribute_values pvav
+                        JOIN product_variations v ON v.product_variation_id = pvav.product_variation_id
+                        LEFT JOIN attribute_options ao ON ao.attribute_option_id = pvav.attribute_option_id
+                        WHERE v.product_id = p.product_id
+                        AND pvav.attribute_id = (r.rule->>'attribute_id')::bigint
+                        AND LOWER(COALESCE(ao.value, pvav.value)) = LOWER(r.rule->>'value')
+                    )
+                    WHEN 'created_at' THEN CASE r.rule->>'operator'
+                        WHEN 'gt' THEN p.created_at > (r.rule->>'value')::timestamptz
+                        WHEN 'lt' THEN p.created_at < (r.rule->>'value')::timestamptz
+                        WHEN 'within_days' THEN p.created_at >= NOW() - make_interval(days => (r.rule->>'value')::int)
+                    END
+                    WHEN 'sales_rank' THEN CASE r.rule->>'operator'
+                        WHEN 'lte' THEN s.sales_rank <= (r.rule->>'value')::bigint
+                    END
+                    WHEN 'tag' THEN (r.rule->>'operator' = 'neq') <> EXISTS (
+                        SELECT 1 FROM product_tags pt
+                        WHERE pt.product_id = p.product_id AND LOWER(pt.tag) = LOWER(r.rule->>'value')
+                    )
+                END
+            ) AS matched
+        FROM jsonb_array_elements(c.rules) AS r(rule)
+    ) m
+    WHERE c.collection_id = sqlc.arg('collection_id') AND c.shop_id = sqlc.arg('shop_id')
+    AND (
+        (c.collection_type = 'manual' AND cp.product_id IS NOT NULL)
+        OR (
+            c.collection_type = 'smart' AND m.rule_count > 0
+            AND ((c.match_all AND m.matched = m.rule_count) OR (NOT c.match_all AND m.matched > 0))
+        )
+    )
+)
+SELECT
+    product_id,
+    slug,
+    title,
+    status,
+    created_at,
+    price,
+    units_sold,
+    position,
+    COUNT(*) OVER () AS total_count
+FROM members
+ORDER BY
+    CASE WHEN sort_order = 'manual' THEN position END ASC NULLS LAST,
+    CASE WHEN sort_order = 'best_selling' THEN units_sold END DESC,
+    CASE WHEN sort_order = 'price_asc' THEN price END ASC,
+    CASE WHEN sort_order = 'price_desc' THEN price END DESC,
+    CASE WHEN sort_order = 'title_asc' THEN title END ASC,
+    created_at DESC,
+    product_id DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
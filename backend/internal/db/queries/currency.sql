@@ -0,0 +1,53 @@
+-- name: GetShopCurrencies :many
+SELECT * FROM shop_currencies
+WHERE shop_id = $1
+ORDER BY currency_code;
+
+-- name: GetShopCurrency :one
+SELECT * FROM shop_currencies
+WHERE shop_id = $1 AND currency_code = $2;
+
+-- name: UpsertShopCurrency :one
+INSERT INTO shop_currencies (currency_code, exchange_rate, shop_id)
+VALUES ($1, $2, $3)
+ON CONFLICT (shop_id, currency_code)
+DO UPDATE SET exchange_rate = EXCLUDED.exchange_rate, updated_at = NOW()
+RETURNING *;
+
+-- name: DeleteShopCurrency :execrows
+DELETE FROM shop_currencies
+WHERE shop_id = $1 AND currency_code = $2;
+
+-- name: GetProductVariationPrices :many
+SELECT * FROM product_variation_prices
+WHERE product_variation_id = $1 AND shop_id = $2
+ORDER BY currency_code;
+
+-- name: GetVariationPricesForCurrency :many
+-- Fixed prices of the given variants in one presentment currency
+SELECT * FROM product_variation_prices
+WHERE shop_id = sqlc.arg('shop_id')
+  AND currency_code = sqlc.arg('currency_code')
+  AND product_variation_id = ANY(sqlc.arg('product_variation_ids')::bigint[]);
+
+-- name: UpsertProductVariationPrice :one
+INSERT INTO product_variation_prices (product_variation_id, currency_code, price, shop_id)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (product_variation_id, currency_code)
+DO UPDATE SET price = EXCLUDED.price, updated_at = NOW()
+RETURNING *;
+
+-- name: DeleteProductVariationPrice :execrows
+DELETE FROM product_variation_prices
+WHERE product_variation_id = $1 AND currency_code = $2 AND shop_id = $3;
+
+-- name: UpsertOrderPresentment :one
+INSERT INTO order_presentments (order_id, currency_code, exchange_rate, amount, shop_id)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (order_id)
+DO UPDATE SET currency_code = EXCLUDED.currency_code, exchange_rate = EXCLUDED.exchange_rate, amount = EXCLUDED.amount
+RETURNING *;
+
+-- name: GetOrderPresentment :one
+SELECT * FROM order_presentments
+WHERE order_id = $1 AND shop_id = $2;
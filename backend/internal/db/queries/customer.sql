@@ -0,0 +1,80 @@
+-- name: GetShopCustomerByEmail :one
+SELECT * FROM shop_customers
+WHERE email = $1 AND shop_id = $2;
+
+-- name: ListCustomerAddresses :many
+SELECT * FROM customer_addresses
+WHERE shop_customer_id = $1 AND shop_id = $2
+ORDER BY is_default_shipping DESC, is_default_billing DESC, address_id;
+
+-- name: GetCustomerAddress :one
+SELECT * FROM customer_addresses
+WHERE address_id = $1 AND shop_customer_id = $2 AND shop_id = $3;
+
+-- name: CreateCustomerAddress :one
+INSERT INTO customer_addresses (
+    shop_customer_id, first_name, last_name, company, address_line_1, address_line_2,
+    city, state, postal_code, country, phone, is_default_shipping, is_default_billing, shop_id
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14
+)
+RETURNING *;
+
+-- name: UpdateCustomerAddress :one
+UPDATE customer_addresses
+SET first_name = $1,
+    last_name = $2,
+    company = $3,
+    address_line_1 = $4,
+    address_line_2 = $5,
+    city = $6,
+    state = $7,
+    postal_code = $8,
+    country = $9,
+    phone = $10,
+    is_default_shipping = $11,
+    is_default_billing = $12,
+    updated_at = NOW()
+WHERE address_id = $13 AND shop_customer_id = $14 AND shop_id = $15
+RETURNING *;
+
+-- name: DeleteCustomerAddress :execrows
+DELETE FROM customer_addresses
+WHERE address_id = $1 AND shop_customer_id = $2 AND shop_id = $3;
+
+-- name: ClearCustomerDefaultAddresses :exec
+-- Unsets the default flags of a customer's addresses so a new default can be set
+-- without violating the one-default-per-customer indexes
+UPDATE customer_addresses
+SET is_default_shipping = is_default_shipping AND NOT sqlc.arg('clear_shipping')::boolean,
+    is_default_billing = is_default_billing AND NOT sqlc.arg('clear_billing')::boolean,
+    updated_at = NOW()
+WHERE shop_customer_id = sqlc.arg('shop_customer_id') AND shop_id = sqlc.arg('shop_id')
+  AND ((is_default_shipping AND sqlc.arg('clear_shipping')::boolean) OR (is_default_billing AND sqlc.arg('clear_billing')::boolean));
+
+-- name: SetCustomerDefaultAddress :one
+UPDATE customer_addresses
+SET is_default_shipping = is_default_shipping OR sqlc.arg('default_shipping')::boolean,
+    is_default_billing = is_default_billing OR sqlc.arg('default_billing')::boolean,
+    updated_at = NOW()
+WHERE address_id = sqlc.arg('address_id') AND shop_customer_id = sqlc.arg('shop_customer_id') AND shop_id = sqlc.arg('shop_id')
+RETURNING *;
+
+-- name: ListCustomerOrders :many
+-- Orders of a customer, including guest orders placed with the customer's email
+SELECT * FROM orders
+WHERE shop_id = sqlc.arg('shop_id')
+  AND (shop_customer_id = sqlc.arg('shop_customer_id')::uuid OR lower(customer_email) = lower(sqlc.arg('email')::text))
+  AND order_id < sqlc.arg('before')
+ORDER BY order_id DESC
+LIMIT sqlc.arg('limit');
+
+-- name: CountCustomerOrders :one
+SELECT COUNT(*) FROM orders
+WHERE shop_id = sqlc.arg('shop_id')
+  AND (shop_customer_id = sqlc.arg('shop_customer_id')::uuid OR lower(customer_email) = lower(sqlc.arg('email')::text));
+
+-- name: GetCustomerOrder :one
+SELECT * FROM orders
+WHERE order_id = sqlc.arg('order_id') AND shop_id = sqlc.arg('shop_id')
+  AND (shop_customer_id = sqlc.arg('shop_customer_id')::uuid OR lower(customer_email) = lower(sqlc.arg('email')::text));
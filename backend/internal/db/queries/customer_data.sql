@@ -0,0 +1,59 @@
+-- name: ExportCustomerOrders :many
+-- Every order of a customer, placed signed in or as a guest with the same email
+SELECT * FROM orders
+WHERE shop_id = sqlc.arg('shop_id')
+    AND (shop_customer_id = sqlc.arg('shop_customer_id')::uuid OR lower(customer_email) = lower(sqlc.arg('email')::text))
+ORDER BY created_at;
+
+-- name: ExportCustomerReviews :many
+SELECT * FROM product_reviews
+WHERE shop_id = sqlc.arg('shop_id')
+    AND (lower(customer_email) = lower(sqlc.arg('email')::text)
+        OR order_id IN (SELECT o.order_id FROM orders o WHERE o.shop_customer_id = sqlc.arg('shop_customer_id')::uuid AND o.shop_id = sqlc.arg('shop_id')))
+ORDER BY created_at;
+
+-- name: AnonymizeCustomerReviews :execrows
+-- Reviews stay published without the name and email of their author
+UPDATE product_reviews
+SET customer_name = 'Anonymous', customer_email = '', updated_at = NOW()
+WHERE shop_id = sqlc.arg('shop_id')
+    AND (lower(customer_email) = lower(sqlc.arg('email')::text)
+        OR order_id IN (SELECT o.order_id FROM orders o WHERE o.shop_customer_id = sqlc.arg('shop_customer_id')::uuid AND o.shop_id = sqlc.arg('shop_id')));
+
+-- name: AnonymizeCustomerOrders :execrows
+-- Removes the personal data of a customer's orders and keeps the amounts, items
+-- and the shipping country needed for accounting and tax records
+UPDATE orders
+SET customer_name = 'Erased customer',
+    username = 'erased',
+    customer_email = NULL,
+    customer_phone = NULL,
+    shipping_address = jsonb_strip_nulls(jsonb_build_object('country', shipping_address->'country')),
+    shop_customer_id = NULL,
+    updated_at = NOW()
+WHERE shop_id = sqlc.arg('shop_id')
+    AND (shop_customer_id = sqlc.arg('shop_customer_id')::uuid OR lower(customer_email) = lower(sqlc.arg('email')::text));
+
+-- name: DeleteCustomerBackInStockSubscriptions :execrows
+DELETE FROM back_in_stock_subscriptions
+WHERE shop_id = sqlc.arg('shop_id')
+    AND (shop_customer_id = sqlc.arg('shop_customer_id')::uuid OR lower(email) = lower(sqlc.arg('email')::text));
+
+-- name: DeleteCustomerNotifications :execrows
+DELETE FROM notifications
+WHERE shop_id = $1 AND lower(recipient) = lower($2);
+
+-- name: CreateCustomerDataRequest :one
+INSERT INTO customer_data_requests (kind, shop_customer_id, requested_by, details, shop_id)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: ListCustomerDataRequests :many
+SELECT * FROM customer_data_requests
+WHERE shop_id = $1
+ORDER BY created_at DESC
+LIMIT $2 OFFSET $3;
+
+-- name: CountCustomerDataRequests :one
+SELECT COUNT(*) FROM customer_data_requests
+WHERE shop_id = $1;
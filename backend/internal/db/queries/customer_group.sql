@@ -0,0 +1,131 @@
+-- name: ListCustomerGroups :many
+SELECT g.group_id, g.name, g.description, g.discount_percent, g.created_at, g.updated_at, g.shop_id,
+    (SELECT COUNT(*) FROM customer_group_members m WHERE m.group_id = g.group_id) AS member_count
+FROM customer_groups g
+WHERE g.shop_id = $1
+ORDER BY g.name;
+
+-- name: GetCustomerGroup :one
+SELECT * FROM customer_groups
+WHERE group_id = $1 AND shop_id = $2;
+
+-- name: CreateCustomerGroup :one
+INSERT INTO customer_groups (name, description, discount_percent, shop_id)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: UpdateCustomerGroup :one
+UPDATE customer_groups
+SET name = $1, description = $2, discount_percent = $3, updated_at = NOW()
+WHERE group_id = $4 AND shop_id = $5
+RETURNING *;
+
+-- name: DeleteCustomerGroup :execrows
+DELETE FROM customer_groups
+WHERE group_id = $1 AND shop_id = $2;
+
+-- name: ListCustomerGroupMembers :many
+SELECT sc.shop_customer_id, sc.sub, sc.shop_id, sc.email, sc.name, sc.locale, sc.profile_picture, sc.verified_email, sc.auth_provider, sc.auth_provider_id, sc.created_at, sc.last_login
+FROM customer_group_members m
+JOIN shop_customers sc ON sc.shop_customer_id = m.shop_customer_id
+WHERE m.group_id = $1 AND m.shop_id = $2
+ORDER BY m.created_at DESC;
+
+-- name: AddCustomerGroupMember :exec
+INSERT INTO customer_group_members (group_id, shop_customer_id, shop_id)
+VALUES ($1, $2, $3)
+ON CONFLICT (group_id, shop_customer_id) DO NOTHING;
+
+-- name: RemoveCustomerGroupMember :execrows
+DELETE FROM customer_group_members
+WHERE group_id = $1 AND shop_customer_id = $2 AND shop_id = $3;
+
+-- name: ListCustomerGroupPrices :many
+SELECT * FROM customer_group_prices
+WHERE group_id = $1 AND shop_id = $2
+ORDER BY product_variation_id;
+
+-- name: UpsertCustomerGroupPrice :one
+-- Only variants of the shop can be priced; no row is returned for other variants
+INSERT INTO customer_group_prices (group_id, product_variation_id, price, shop_id)
+SELECT sqlc.arg('group_id'), pv.product_variation_id, sqlc.arg('price'), pv.shop_id
+FROM product_variations pv
+WHERE pv.product_variation_id = sqlc.arg('product_variation_id') AND pv.shop_id = sqlc.arg('shop_id')
+ON CONFLICT (group_id, product_variation_id)
+DO UPDATE SET price = EXCLUDED.price, updated_at = NOW()
+RETURNING *;
+
+-- name: DeleteCustomerGroupPrice :execrows
+DELETE FROM customer_group_prices
+WHERE group_id = $1 AND product_variation_id = $2 AND shop_id = $3;
+
+-- name: GetCustomerVariantPrices :many
+-- Group prices of variants for a customer, in the shop currency. A group's price
+-- list takes precedence over its discount and the lowest price across the
+-- customer's groups wins. Variants without a group price are left out.
+SELECT pv.product_variation_id,
+    MIN(COALESCE(gp.price, pv.price * (100 - g.discount_percent) / 100))::numeric AS price
+FROM customer_group_members m
+JOIN customer_groups g ON g.group_id = m.group_id
+JOIN product_variations pv ON pv.product_variation_id = ANY(sqlc.arg('product_variation_ids')::bigint[]) AND pv.shop_id = m.shop_id
+LEFT JOIN customer_group_prices gp ON gp.group_id = g.group_id AND gp.product_variation_id = pv.product_variation_id
+WHERE m.shop_customer_id = sqlc.arg('shop_customer_id') AND m.shop_id = sqlc.arg('shop_id')
+    AND (gp.price IS NOT NULL OR g.discount_percent IS NOT NULL)
+GROUP BY pv.product_variation_id;
+
+-- name: ListCustomerSegments :many
+SELECT * FROM customer_segments
+WHERE shop_id = $1
+ORDER BY name;
+
+-- name: GetCustomerSegment :one
+SELECT * FROM customer_segments
+WHERE segment_id = $1 AND shop_id = $2;
+
+-- name: CreateCustomerSegment :one
+INSERT INTO customer_segments (name, description, min_total_spent, max_total_spent, min_order_count, max_order_count, window_days, inactive_days, shop_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING *;
+
+-- name: UpdateCustomerSegment :one
+UPDATE customer_segments
+SET name = $1, description = $2, min_total_spent = $3, max_total_spent = $4, min_order_count = $5,
+    max_order_count = $6, window_days = $7, inactive_days = $8, updated_at = NOW()
+WHERE segment_id = $9 AND shop_id = $10
+RETURNING *;
+
+-- name: DeleteCustomerSegment :execrows
+DELETE FROM customer_segments
+WHERE segment_id = $1 AND shop_id = $2;
+
+-- name: ListCustomerSegmentMembers :many
+-- Evaluates a segment's rules against the completed orders of each customer of the
+-- shop, matched by customer ID or email. A NULL limit returns every member.
+WITH segment AS (
+    SELECT min_total_spent, max_total_spent, min_order_count, max_order_count, window_days, inactive_days
+    FROM customer_segments
+    WHERE segment_id = sqlc.arg('segment_id') AND shop_id = sqlc.arg('shop_id')
+), stats AS (
+    SELECT sc.shop_customer_id,
+        COUNT(o.order_id) FILTER (WHERE s.window_days IS NULL OR o.created_at >= NOW() - make_interval(days => s.window_days)) AS order_count,
+        COALESCE(SUM(o.amount) FILTER (WHERE s.window_days IS NULL OR o.created_at >= NOW() - make_interval(days => s.window_days)), 0)::numeric AS total_spent,
+        MAX(o.created_at)::timestamptz AS last_order_at
+    FROM shop_customers sc
+    CROSS JOIN segment s
+    LEFT JOIN orders o ON o.shop_id = sc.shop_id AND o.status = 'completed'
+        AND (o.shop_customer_id = sc.shop_customer_id OR lower(o.customer_email) = lower(sc.email))
+    WHERE sc.shop_id = sqlc.arg('shop_id')
+    GROUP BY sc.shop_customer_id
+)
+SELECT sc.shop_customer_id, sc.email, sc.name, st.order_count, st.total_spent, st.last_order_at,
+    COUNT(*) OVER () AS total_count
+FROM stats st
+JOIN shop_customers sc ON sc.shop_customer_id = st.shop_customer_id
+CROSS JOIN segment s
+WHERE (s.min_total_spent IS NULL OR st.total_spent >= s.min_total_spent)
+    AND (s.max_total_spent IS NULL OR st.total_spent <= s.max_total_spent)
+    AND (s.min_order_count IS NULL OR st.order_count >= s.min_order_count)
+    AND (s.max_order_count IS NULL OR st.order_count <= s.max_order_count)
+    AND (s.inactive_days IS NULL OR st.last_order_at IS NULL OR st.last_order_at < NOW() - make_interval(days => s.inactive_days))
+ORDER BY st.total_spent DESC, sc.email
+LIMIT sqlc.narg('limit') OFFSET sqlc.arg('offset');
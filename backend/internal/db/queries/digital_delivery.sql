@@ -0,0 +1,163 @@
+-- name: ListDigitalAssets :many
+SELECT
+    da.*,
+    (SELECT COUNT(*) FROM license_keys lk
+     WHERE lk.digital_asset_id = da.digital_asset_id AND lk.allocated_at IS NULL) AS available_keys
+FROM digital_assets da
+WHERE da.product_variation_id = $1 AND da.shop_id = $2
+ORDER BY da.digital_asset_id;
+
+-- name: GetDigitalAsset :one
+SELECT * FROM digital_assets
+WHERE digital_asset_id = $1 AND shop_id = $2;
+
+-- name: CreateDigitalAsset :one
+INSERT INTO digital_assets (product_variation_id, kind, title, storage_key, file_name, content_type, size_bytes, download_limit, expires_after_days, shop_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+RETURNING *;
+
+-- name: UpdateDigitalAsset :one
+UPDATE digital_assets
+SET title = COALESCE(sqlc.narg('title'), title),
+    download_limit = COALESCE(sqlc.narg('download_limit'), download_limit),
+    expires_after_days = COALESCE(sqlc.narg('expires_after_days'), expires_after_days),
+    updated_at = NOW()
+WHERE digital_asset_id = sqlc.arg('digital_asset_id') AND shop_id = sqlc.arg('shop_id')
+RETURNING *;
+
+-- name: DeleteDigitalAsset :one
+DELETE FROM digital_assets
+WHERE digital_asset_id = $1 AND shop_id = $2
+RETURNING *;
+
+-- name: VariantIsDigital :one
+-- Whether the product type of a variant is digital
+SELECT pt.digital
+FROM product_variations pv
+JOIN products p ON p.product_id = pv.product_id
+JOIN product_types pt ON pt.product_type_id = p.product_type_id
+WHERE pv.product_variation_id = $1 AND pv.shop_id = $2;
+
+-- name: CreateLicenseKeys :execrows
+-- Adds keys to the pool of a license key asset, skipping those already in it
+INSERT INTO license_keys (digital_asset_id, license_key, shop_id)
+SELECT sqlc.arg('digital_asset_id'), key, sqlc.arg('shop_id')
+FROM unnest(sqlc.arg('license_keys')::text[]) AS key
+ON CONFLICT (digital_asset_id, license_key) DO NOTHING;
+
+-- name: CountAvailableLicenseKeys :one
+SELECT COUNT(*) FROM license_keys
+WHERE digital_asset_id = $1 AND shop_id = $2 AND allocated_at IS NULL;
+
+-- name: AllocateLicenseKey :one
+-- Takes the oldest unallocated key from the pool of an asset. Concurrent orders
+-- skip the keys other transactions are allocating.
+UPDATE license_keys
+SET allocated_at = NOW()
+WHERE license_key_id = (
+    SELECT lk.license_key_id FROM license_keys lk
+    WHERE lk.digital_asset_id = $1 AND lk.shop_id = $2 AND lk.allocated_at IS NULL
+    ORDER BY lk.license_key_id
+    LIMIT 1
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: ListPendingLicenseDownloads :many
+-- License key downloads of an asset still waiting for a key, oldest first
+SELECT * FROM digital_downloads
+WHERE digital_asset_id = $1 AND shop_id = $2 AND license_key_id IS NULL
+ORDER BY download_id
+FOR UPDATE;
+
+-- name: SetDownloadLicenseKey :one
+UPDATE digital_downloads
+SET license_key_id = $3
+WHERE download_id = $1 AND shop_id = $2
+RETURNING *;
+
+-- name: LockOrderDigitalDelivery :one
+-- Serializes the delivery of the digital goods of an order for the rest of the
+-- transaction
+SELECT order_id FROM orders
+WHERE order_id = $1 AND shop_id = $2
+FOR UPDATE;
+
+-- name: CountOrderDigitalDownloads :one
+SELECT COUNT(*) FROM digital_downloads
+WHERE order_id = $1 AND shop_id = $2;
+
+-- name: ListOrderDigitalAssets :many
+-- The digital assets of the lines of an order
+SELECT
+    oi.order_item_id,
+    oi.quantity,
+    da.digital_asset_id,
+    da.kind,
+    da.download_limit,
+    da.expires_after_days
+FROM order_items oi
+JOIN digital_assets da ON da.product_variation_id = oi.product_variation_id
+WHERE oi.order_id = $1 AND oi.shop_id = $2
+ORDER BY oi.order_item_id, da.digital_asset_id;
+
+-- name: CreateDigitalDownload :one
+INSERT INTO digital_downloads (order_id, order_item_id, digital_asset_id, license_key_id, download_limit, expires_at, shop_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: ListOrderDigitalDownloads :many
+SELECT
+    dd.*,
+    da.product_variation_id,
+    da.kind,
+    da.title,
+    da.file_name,
+    da.size_bytes,
+    lk.license_key
+FROM digital_downloads dd
+JOIN digital_assets da ON da.digital_asset_id = dd.digital_asset_id
+LEFT JOIN license_keys lk ON lk.license_key_id = dd.license_key_id
+WHERE dd.order_id = $1 AND dd.shop_id = $2
+ORDER BY dd.download_id;
+
+-- name: GetDigitalDownloadFile :one
+-- A download with the file of its asset
+SELECT
+    dd.*,
+    da.kind,
+    da.storage_key,
+    da.file_name,
+    da.content_type
+FROM digital_downloads dd
+JOIN digital_assets da ON da.digital_asset_id = dd.digital_asset_id
+WHERE dd.download_id = $1 AND dd.shop_id = $2;
+
+-- name: RecordDigitalDownload :one
+-- Counts a download, unless it expired or reached its limit
+UPDATE digital_downloads
+SET download_count = download_count + 1
+WHERE download_id = $1 AND shop_id = $2
+  AND (download_limit IS NULL OR download_count < download_limit)
+  AND (expires_at IS NULL OR expires_at > NOW())
+RETURNING *;
+
+-- name: CreateDigitalDownloadEvent :one
+INSERT INTO digital_download_events (download_id, ip_address, user_agent, shop_id)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: ListDigitalDownloadEvents :many
+SELECT * FROM digital_download_events
+WHERE download_id = $1 AND shop_id = $2
+ORDER BY created_at DESC, event_id DESC
+LIMIT $3 OFFSET $4;
+
+-- name: CountDigitalDownloadEvents :one
+SELECT COUNT(*) FROM digital_download_events
+WHERE download_id = $1 AND shop_id = $2;
+
+-- name: QueueDigitalDeliveryNotification :execrows
+-- Queues the email with the downloads and license keys of an order to its customer
+INSERT INTO notifications (kind, recipient, payload, shop_id)
+VALUES ('digital_delivery', $1, $2, $3);
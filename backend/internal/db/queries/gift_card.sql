@@ -0,0 +1,174 @@
+-- name: ListGiftCards :many
+SELECT * FROM gift_cards
+WHERE shop_id = sqlc.arg('shop_id')
+    AND (sqlc.narg('code')::text IS NULL OR code LIKE sqlc.narg('code')::text || '%')
+ORDER BY created_at DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CountGiftCards :one
+SELECT COUNT(*) FROM gift_cards
+WHERE shop_id = sqlc.arg('shop_id')
+    AND (sqlc.narg('code')::text IS NULL OR code LIKE sqlc.narg('code')::text || '%');
+
+-- name: GetGiftCard :one
+SELECT * FROM gift_cards
+WHERE gift_card_id = $1 AND shop_id = $2;
+
+-- name: GetGiftCardByCode :one
+SELECT * FROM gift_cards
+WHERE code = $1 AND shop_id = $2;
+
+-- name: CreateGiftCard :one
+-- No rows means a card was already issued for the unit of the order item
+INSERT INTO gift_cards (
+    code, initial_amount, balance, currency_code, expires_at, note,
+    recipient_email, shop_customer_id, order_id, order_item_id, order_item_unit, created_by, shop_id
+) VALUES (
+    sqlc.arg('code'), sqlc.arg('amount'), sqlc.arg('amount'), sqlc.arg('currency_code'), sqlc.narg('expires_at'), sqlc.narg('note'),
+    sqlc.narg('recipient_email'), sqlc.narg('shop_customer_id'), sqlc.narg('order_id'), sqlc.narg('order_item_id'), sqlc.narg('order_item_unit'),
+    sqlc.narg('created_by'), sqlc.arg('shop_id')
+)
+ON CONFLICT (order_item_id, order_item_unit) DO NOTHING
+RETURNING *;
+
+-- name: UpdateGiftCard :one
+UPDATE gift_cards
+SET
+    expires_at = COALESCE(sqlc.narg('expires_at'), expires_at),
+    disabled = COALESCE(sqlc.narg('disabled'), disabled),
+    note = COALESCE(sqlc.narg('note'), note),
+    recipient_email = COALESCE(sqlc.narg('recipient_email'), recipient_email),
+    updated_at = NOW()
+WHERE gift_card_id = sqlc.arg('gift_card_id') AND shop_id = sqlc.arg('shop_id')
+RETURNING *;
+
+-- name: RedeemGiftCard :one
+-- Takes amount from the balance of a card that can still be spent; no rows
+-- means the card is disabled, expired or short of funds
+UPDATE gift_cards
+SET balance = balance - sqlc.arg('amount'), updated_at = NOW()
+WHERE gift_card_id = sqlc.arg('gift_card_id') AND shop_id = sqlc.arg('shop_id')
+    AND NOT disabled
+    AND (expires_at IS NULL OR expires_at > NOW())
+    AND balance >= sqlc.arg('amount')
+RETURNING *;
+
+-- name: CreditGiftCard :one
+-- Adds a signed amount to the balance, for refunds and staff adjustments
+UPDATE gift_cards
+SET balance = balance + sqlc.arg('amount'), updated_at = NOW()
+WHERE gift_card_id = sqlc.arg('gift_card_id') AND shop_id = sqlc.arg('shop_id')
+RETURNING *;
+
+-- name: CreateGiftCardTransaction :one
+INSERT INTO gift_card_transactions (gift_card_id, kind, amount, balance_after, order_id, note, created_by, shop_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING *;
+
+-- name: ListGiftCardTransactions :many
+SELECT * FROM gift_card_transactions
+WHERE gift_card_id = $1 AND shop_id = $2
+ORDER BY transaction_id;
+
+-- name: LockOrderTender :one
+-- Serializes the gift card and store credit bookkeeping of an order for the rest
+-- of the transaction
+SELECT status, payment_status FROM orders
+WHERE order_id = $1 AND shop_id = $2
+FOR UPDATE;
+
+-- name: ListOrderGiftCardRedemptions :many
+-- What each gift card still pays towards an order, net of refunds
+SELECT gift_card_id, (-SUM(amount))::numeric AS amount
+FROM gift_card_transactions
+WHERE order_id = $1 AND shop_id = $2 AND kind IN ('redeem', 'refund')
+GROUP BY gift_card_id
+HAVING SUM(amount) < 0
+ORDER BY gift_card_id;
+
+-- name: ListOrderGiftCardItems :many
+-- Items of an order whose product type is a gift card
+SELECT oi.order_item_id, oi.quantity, oi.price
+FROM order_items oi
+JOIN product_variations pv ON pv.product_variation_id = oi.product_variation_id
+JOIN products p ON p.product_id = pv.product_id
+JOIN product_types pt ON pt.product_type_id = p.product_type_id
+WHERE oi.order_id = $1 AND oi.shop_id = $2 AND pt.gift_card
+ORDER BY oi.order_item_id;
+
+-- name: CountOrderGiftCards :one
+SELECT COUNT(*) FROM gift_cards
+WHERE order_id = $1 AND shop_id = $2;
+
+-- name: QueueGiftCardNotification :execrows
+-- Queues an email with the code to the recipient of a card, if it has one
+INSERT INTO notifications (kind, recipient, payload, shop_id)
+SELECT
+    'gift_card',
+    g.recipient_email,
+    jsonb_build_object(
+        'gift_card_id', g.gift_card_id,
+        'code', g.code,
+        'amount', g.initial_amount,
+        'currency_code', g.currency_code,
+        'expires_at', g.expires_at,
+        'order_id', g.order_id
+    ),
+    g.shop_id
+FROM gift_cards g
+WHERE g.gift_card_id = $1 AND g.shop_id = $2 AND g.recipient_email IS NOT NULL;
+
+-- name: LockCustomerStoreCredit :one
+-- Serializes ledger writes of a customer for the rest of the transaction
+SELECT shop_customer_id FROM shop_customers
+WHERE shop_customer_id = $1 AND shop_id = $2
+FOR UPDATE;
+
+-- name: GetStoreCreditBalance :one
+SELECT COALESCE((
+    SELECT t.balance_after FROM store_credit_transactions t
+    WHERE t.shop_customer_id = $1 AND t.shop_id = $2
+    ORDER BY t.transaction_id DESC
+    LIMIT 1
+), 0)::numeric AS balance;
+
+-- name: CreateStoreCreditTransaction :one
+-- Appends to the customer's ledger; balance_after carries the running balance
+-- and the check constraint rejects entries that would overdraw it. Callers
+-- hold LockCustomerStoreCredit.
+INSERT INTO store_credit_transactions (shop_customer_id, kind, amount, balance_after, reason, order_id, note, created_by, shop_id)
+SELECT
+    sqlc.arg('shop_customer_id')::uuid,
+    sqlc.arg('kind')::credit_transaction_kind,
+    sqlc.arg('amount')::numeric,
+    COALESCE((
+        SELECT t.balance_after FROM store_credit_transactions t
+        WHERE t.shop_customer_id = sqlc.arg('shop_customer_id')::uuid AND t.shop_id = sqlc.arg('shop_id')::bigint
+        ORDER BY t.transaction_id DESC
+        LIMIT 1
+    ), 0) + sqlc.arg('amount')::numeric,
+    sqlc.narg('reason')::varchar,
+    sqlc.narg('order_id')::bigint,
+    sqlc.narg('note')::text,
+    sqlc.narg('created_by')::varchar,
+    sqlc.arg('shop_id')::bigint
+RETURNING *;
+
+-- name: ListStoreCreditTransactions :many
+SELECT * FROM store_credit_transactions
+WHERE shop_customer_id = $1 AND shop_id = $2
+ORDER BY transaction_id DESC
+LIMIT $3 OFFSET $4;
+
+-- name: CountStoreCreditTransactions :one
+SELECT COUNT(*) FROM store_credit_transactions
+WHERE shop_customer_id = $1 AND shop_id = $2;
+
+-- name: ListOrderStoreCreditRedemptions :many
+-- What store credit still pays towards an order, net of refunds
+SELECT shop_customer_id, (-SUM(amount))::numeric AS amount
+FROM store_credit_transactions
+WHERE order_id = $1 AND shop_id = $2 AND kind IN ('redeem', 'refund')
+GROUP BY shop_customer_id
+HAVING SUM(amount) < 0
+ORDER BY shop_customer_id;
@@ -0,0 +1,8 @@
+-- name: CreateImageAsset :one
+INSERT INTO image_assets (storage_key, url, content_type, width, height, size_bytes, renditions, shop_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING *;
+
+-- name: GetImageAssetsByURLs :many
+SELECT * FROM image_assets
+WHERE shop_id = sqlc.arg('shop_id') AND url = ANY(sqlc.arg('urls')::text[]);
@@ -0,0 +1,328 @@
+-- name: CreateLocation :one
+INSERT INTO locations (name, address, sells_online, shop_id)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: EnsureDefaultLocation :exec
+-- Creates the default location of a shop that has none yet
+INSERT INTO locations (name, is_default, shop_id)
+VALUES ('Default location', TRUE, $1)
+ON CONFLICT DO NOTHING;
+
+-- name: GetDefaultLocation :one
+SELECT * FROM locations
+WHERE shop_id = $1 AND is_default;
+
+-- name: GetLocation :one
+SELECT * FROM locations
+WHERE location_id = $1 AND shop_id = $2;
+
+-- name: ListLocations :many
+SELECT * FROM locations
+WHERE shop_id = $1
+ORDER BY is_default DESC, name;
+
+-- name: UpdateLocation :one
+UPDATE locations
+SET name = $3,
+    address = $4,
+    sells_online = $5,
+    updated_at = NOW()
+WHERE location_id = $1 AND shop_id = $2
+RETURNING *;
+
+-- name: ClearDefaultLocation :exec
+UPDATE locations
+SET is_default = FALSE,
+    updated_at = NOW()
+WHERE shop_id = $1 AND is_default;
+
+-- name: MarkDefaultLocation :one
+UPDATE locations
+SET is_default = TRUE,
+    updated_at = NOW()
+WHERE location_id = $1 AND shop_id = $2
+RETURNING *;
+
+-- name: DeleteLocation :execrows
+-- Deletes a location that is not the default and holds no stock, in place or in transit
+DELETE FROM locations l
+WHERE l.location_id = $1 AND l.shop_id = $2 AND NOT l.is_default
+AND NOT EXISTS (
+    SELECT 1 FROM inventory_levels il
+    WHERE il.location_id = l.location_id AND (il.available > 0 OR il.incoming > 0)
+);
+
+-- name: EnsureInventoryLevel :one
+-- Returns the stock of a variant at a location, creating an empty level when there
+-- is none, and locks it until the end of the transaction
+INSERT INTO inventory_levels (product_variation_id, location_id, shop_id)
+VALUES ($1, $2, $3)
+ON CONFLICT (product_variation_id, location_id) DO UPDATE
+SET updated_at = inventory_levels.updated_at
+RETURNING *;
+
+-- name: ListVariantInventoryLevels :many
+SELECT
+    l.location_id,
+    l.name AS location_name,
+    l.sells_online,
+    COALESCE(il.available, 0)::bigint AS available,
+    COALESCE(il.incoming, 0)::bigint AS incoming
+FROM locations l
+LEFT JOIN inventory_levels il ON il.location_id = l.location_id AND il.product_variation_id = $1
+WHERE l.shop_id = $2
+ORDER BY l.is_default DESC, l.name;
+
+-- name: ListLocationInventoryLevels :many
+SELECT
+    il.*,
+    p.title AS product_title,
+    pv.description AS variant_title,
+    pv.sku
+FROM inventory_levels il
+JOIN product_variations pv ON il.product_variation_id = pv.product_variation_id
+JOIN products p ON pv.product_id = p.product_id
+WHERE il.location_id = $1 AND il.shop_id = $2
+ORDER BY p.title, pv.product_variation_id
+LIMIT $3 OFFSET $4;
+
+-- name: CountLocationInventoryLevels :one
+SELECT COUNT(*) FROM inventory_levels
+WHERE location_id = $1 AND shop_id = $2;
+
+-- name: AddIncomingStock :one
+INSERT INTO inventory_levels (product_variation_id, location_id, incoming, shop_id)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (product_variation_id, location_id) DO UPDATE
+SET incoming = inventory_levels.incoming + EXCLUDED.incoming,
+    updated_at = NOW()
+RETURNING *;
+
+-- name: DeductIncomingStock :one
+UPDATE inventory_levels
+SET incoming = incoming - $4,
+    updated_at = NOW()
+WHERE product_variation_id = $1 AND location_id = $2 AND shop_id = $3
+RETURNING *;
+
+-- name: SyncVariantAvailableQuantity :one
+-- Sets the available quantity of a variant to its stock at the locations that sell online
+UPDATE product_variations pv
+SET available_quantity = COALESCE((
+        SELECT SUM(il.available)
+        FROM inventory_levels il
+        JOIN locations l ON l.location_id = il.location_id
+        WHERE il.product_variation_id = pv.product_variation_id AND l.sells_online
+    ), 0)::bigint,
+    updated_at = NOW()
+WHERE pv.product_variation_id = $1 AND pv.shop_id = $2
+RETURNING *;
+
+-- name: SyncProductAvailableQuantities :exec
+UPDATE product_variations pv
+SET available_quantity = COALESCE((
+        SELECT SUM(il.available)
+        FROM inventory_levels il
+        JOIN locations l ON l.location_id = il.location_id
+        WHERE il.product_variation_id = pv.product_variation_id AND l.sells_online
+    ), 0)::bigint
+WHERE pv.product_id = $1 AND pv.shop_id = $2
+AND NOT EXISTS (SELECT 1 FROM bundle_components bc WHERE bc.bundle_variation_id = pv.product_variation_id);
+
+-- name: SyncShopAvailableQuantities :exec
+-- Recomputes the available quantity of every variant of a shop, e.g. after a
+-- location stops selling online. Bundles are synced from their components.
+UPDATE product_variations pv
+SET available_quantity = COALESCE((
+        SELECT SUM(il.available)
+        FROM inventory_levels il
+        JOIN locations l ON l.location_id = il.location_id
+        WHERE il.product_variation_id = pv.product_variation_id AND l.sells_online
+    ), 0)::bigint,
+    updated_at = NOW()
+WHERE pv.shop_id = $1
+AND NOT EXISTS (SELECT 1 FROM bundle_components bc WHERE bc.bundle_variation_id = pv.product_variation_id);
+
+-- name: ReconcileProductInventoryLevels :exec
+-- Puts the available quantity set directly on the variants of a product at the
+-- default location, less the stock the other online locations hold. Bundles hold
+-- no stock of their own and are skipped.
+INSERT INTO inventory_levels (product_variation_id, location_id, available, shop_id)
+SELECT
+    pv.product_variation_id,
+    l.location_id,
+    GREATEST(pv.available_quantity - COALESCE((
+        SELECT SUM(il.available)
+        FROM inventory_levels il
+        JOIN locations ol ON ol.location_id = il.location_id
+        WHERE il.product_variation_id = pv.product_variation_id
+        AND ol.sells_online AND ol.location_id <> l.location_id
+    ), 0), 0)::bigint,
+    pv.shop_id
+FROM product_variations pv
+JOIN locations l ON l.shop_id = pv.shop_id AND l.is_default
+WHERE pv.product_id = $1 AND pv.shop_id = $2
+AND NOT EXISTS (SELECT 1 FROM bundle_components bc WHERE bc.bundle_variation_id = pv.product_variation_id)
+ON CONFLICT (product_variation_id, location_id) DO UPDATE
+SET available = EXCLUDED.available,
+    updated_at = NOW()
+WHERE inventory_levels.available <> EXCLUDED.available;
+
+-- name: CreateInventoryTransfer :one
+INSERT INTO inventory_transfers (origin_location_id, destination_location_id, note, created_by, shop_id)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: CreateInventoryTransferItem :one
+INSERT INTO inventory_transfer_items (transfer_id, product_variation_id, quantity, shop_id)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: GetInventoryTransfer :one
+SELECT * FROM inventory_transfers
+WHERE transfer_id = $1 AND shop_id = $2;
+
+-- name: ListInventoryTransfers :many
+SELECT * FROM inventory_transfers
+WHERE shop_id = $1
+ORDER BY created_at DESC, transfer_id DESC
+LIMIT $2 OFFSET $3;
+
+-- name: CountInventoryTransfers :one
+SELECT COUNT(*) FROM inventory_transfers
+WHERE shop_id = $1;
+
+-- name: ListInventoryTransferItems :many
+SELECT
+    ti.*,
+    p.title AS product_title,
+    pv.description AS variant_title,
+    pv.sku
+FROM inventory_transfer_items ti
+JOIN product_variations pv ON ti.product_variation_id = pv.product_variation_id
+JOIN products p ON pv.product_id = p.product_id
+WHERE ti.transfer_id = $1 AND ti.shop_id = $2
+ORDER BY ti.product_variation_id;
+
+-- name: ReceiveInventoryTransfer :one
+UPDATE inventory_transfers
+SET status = 'received',
+    received_at = NOW(),
+    updated_at = NOW()
+WHERE transfer_id = $1 AND shop_id = $2 AND status = 'in_transit'
+RETURNING *;
+
+-- name: CancelInventoryTransfer :one
+UPDATE inventory_transfers
+SET status = 'cancelled',
+    updated_at = NOW()
+WHERE transfer_id = $1 AND shop_id = $2 AND status = 'in_transit'
+RETURNING *;
+
+-- name: ListFulfillmentLocations :many
+-- Lists the online locations that hold enough stock for every item of an order,
+-- the default location first. Bundles need the stock of their components.
+SELECT l.* FROM locations l
+WHERE l.shop_id = $2 AND l.sells_online
+AND NOT EXISTS (
+    SELECT 1
+    FROM (
+        SELECT
+            COALESCE(bc.component_variation_id, oi.product_variation_id) AS product_variation_id,
+            SUM(oi.quantity * COALESCE(bc.quantity, 1)) AS quantity
+        FROM order_items oi
+        LEFT JOIN bundle_components bc ON bc.bundle_variation_id = oi.product_variation_id
+        WHERE oi.order_id = $1
+        GROUP BY 1
+    ) oi
+    LEFT JOIN inventory_levels il ON il.product_variation_id = oi.product_variation_id AND il.location_id = l.location_id
+    WHERE COALESCE(il.available, 0) < oi.quantity
+)
+ORDER BY l.is_default DESC, l.name;
+
+-- name: CreateOrderFulfillment :one
+INSERT INTO order_fulfillments (order_id, location_id, created_by, shop_id)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: GetOrderFulfillment :one
+SELECT * FROM order_fulfillments
+WHERE order_id = $1 AND shop_id = $2;
+
+-- name: SetVariantInventoryPolicy :one
+UPDATE product_variations
+SET inventory_policy = $3,
+    preorder_ships_on = $4,
+    preorder_limit = $5,
+    updated_at = NOW()
+WHERE product_variation_id = $1 AND shop_id = $2
+RETURNING *;
+
+-- name: GetVariantBackorderedQuantity :one
+-- Returns the units of a variant ordered beyond its stock by orders that are still
+-- waiting to be fulfilled
+SELECT COALESCE(SUM(backordered_quantity), 0)::bigint
+FROM open_order_items
+WHERE product_variation_id = $1 AND shop_id = $2;
+
+-- name: GetVariantCommittedQuantity :one
+-- Returns the units of a variant promised to orders that are still waiting to be
+-- fulfilled, whether they are met from stock or on backorder
+SELECT COALESCE(SUM(quantity), 0)::bigint
+FROM open_order_items
+WHERE product_variation_id = $1 AND shop_id = $2;
+
+-- name: GetProductVariationForUpdate :one
+-- Serializes the orders placed for a variant for the rest of the transaction, so
+-- that concurrent orders cannot promise the same stock twice
+SELECT * FROM product_variations
+WHERE product_variation_id = $1 AND shop_id = $2
+FOR UPDATE;
+
+-- name: ListBackorderedOrderItems :many
+-- Lists the backordered items of the orders still waiting to be fulfilled, oldest
+-- order first, with the stock their variants have now
+SELECT
+    oi.order_item_id,
+    oi.order_id,
+    oi.product_variation_id,
+    oi.quantity,
+    oi.backordered_quantity,
+    oi.ordered_at,
+    p.title AS product_title,
+    pv.description AS variant_title,
+    pv.sku,
+    pv.available_quantity,
+    pv.inventory_policy,
+    pv.preorder_ships_on
+FROM open_order_items oi
+JOIN product_variations pv ON pv.product_variation_id = oi.product_variation_id
+JOIN products p ON p.product_id = pv.product_id
+WHERE oi.shop_id = $1
+AND oi.backordered_quantity > 0
+ORDER BY oi.ordered_at, oi.order_item_id
+LIMIT $2 OFFSET $3;
+
+-- name: CountBackorderedOrderItems :one
+SELECT COUNT(*)
+FROM open_order_items
+WHERE shop_id = $1
+AND backordered_quantity > 0;
+
+-- name: ClaimExpiredPendingOrders :many
+-- Locks up to limit unpaid pending orders of any shop placed before placed_before,
+-- whose stock nobody is paying for. Orders paid on delivery and the renewal
+-- orders of subscriptions wait for their payment however long it takes.
+-- Concurrent schedulers skip the orders another one holds.
+SELECT o.order_id, o.payment_status, o.shop_id, s.currency_code
+FROM orders o
+JOIN shops s ON s.shop_id = o.shop_id
+WHERE o.status = 'pending'
+    AND o.payment_status IN ('pending', 'failed')
+    AND o.payment_method <> 'pay_on_delivery'
+    AND o.created_at < sqlc.arg('placed_before')
+    AND NOT EXISTS (SELECT 1 FROM subscriptions sub WHERE sub.pending_order_id = o.order_id)
+ORDER BY o.created_at
+LIMIT sqlc.arg('limit')
+FOR UPDATE OF o SKIP LOCKED;
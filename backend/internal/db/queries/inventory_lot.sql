@@ -0,0 +1,81 @@
+-- name: ReceiveInventoryLot :one
+-- Adds stock to a lot of a variant at a location, creating the lot when it is new.
+-- A lot received again keeps its expiry date unless a new one is given.
+INSERT INTO inventory_lots (product_variation_id, location_id, lot_number, expires_on, quantity, shop_id)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (product_variation_id, location_id, lot_number) DO UPDATE
+SET quantity = inventory_lots.quantity + EXCLUDED.quantity,
+    expires_on = COALESCE(EXCLUDED.expires_on, inventory_lots.expires_on),
+    updated_at = NOW()
+RETURNING *;
+
+-- name: GetInventoryLot :one
+SELECT * FROM inventory_lots
+WHERE lot_id = $1 AND shop_id = $2;
+
+-- name: ListAllocatableLots :many
+-- Lists the lots holding stock of a variant at a location in the order stock is
+-- taken from them: earliest expiry first, lots without an expiry date last
+SELECT
+    il.*,
+    COALESCE(il.expires_on < CURRENT_DATE, FALSE)::boolean AS expired
+FROM inventory_lots il
+WHERE il.product_variation_id = $1 AND il.location_id = $2 AND il.shop_id = $3
+AND il.quantity > 0
+ORDER BY il.expires_on ASC NULLS LAST, il.received_at, il.lot_id;
+
+-- name: DeductInventoryLot :one
+UPDATE inventory_lots
+SET quantity = quantity - $5,
+    updated_at = NOW()
+WHERE lot_id = $1 AND product_variation_id = $2 AND location_id = $3 AND shop_id = $4
+AND quantity >= $5
+RETURNING *;
+
+-- name: ListVariantLots :many
+SELECT
+    il.*,
+    l.name AS location_name
+FROM inventory_lots il
+JOIN locations l ON l.location_id = il.location_id
+WHERE il.product_variation_id = $1 AND il.shop_id = $2
+AND il.quantity > 0
+ORDER BY il.expires_on ASC NULLS LAST, il.received_at, il.lot_id;
+
+-- name: ListExpiringLots :many
+-- Lists the lots holding stock that expire within the given number of days,
+-- including the lots that have already expired
+SELECT
+    il.*,
+    (il.expires_on - CURRENT_DATE)::int AS days_until_expiry,
+    l.name AS location_name,
+    p.product_id,
+    p.title AS product_title,
+    pv.description AS variant_title,
+    pv.sku,
+    pv.cost_price
+FROM inventory_lots il
+JOIN locations l ON l.location_id = il.location_id
+JOIN product_variations pv ON pv.product_variation_id = il.product_variation_id
+JOIN products p ON p.product_id = pv.product_id
+WHERE il.shop_id = sqlc.arg('shop_id')
+AND il.quantity > 0
+AND il.expires_on <= CURRENT_DATE + sqlc.arg('days')::int
+AND (sqlc.narg('location_id')::bigint IS NULL OR il.location_id = sqlc.narg('location_id'))
+ORDER BY il.expires_on, p.title, il.lot_id;
+
+-- name: ListExpiredLots :many
+SELECT * FROM inventory_lots
+WHERE shop_id = sqlc.arg('shop_id')
+AND quantity > 0
+AND expires_on < CURRENT_DATE
+AND (sqlc.narg('location_id')::bigint IS NULL OR location_id = sqlc.narg('location_id'))
+ORDER BY expires_on, lot_id;
+
+-- name: GetVariantTracksLots :one
+-- Reports whether the stock of a variant is kept in lots, as set on its product type
+SELECT pt.tracks_lots
+FROM product_variations pv
+JOIN products p ON p.product_id = pv.product_id
+JOIN product_types pt ON pt.product_type_id = p.product_type_id
+WHERE pv.product_variation_id = $1 AND pv.shop_id = $2;
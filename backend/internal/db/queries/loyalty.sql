@@ -0,0 +1,80 @@
+-- name: GetLoyaltyProgram :one
+SELECT * FROM loyalty_programs
+WHERE shop_id = $1;
+
+-- name: UpsertLoyaltyProgram :one
+INSERT INTO loyalty_programs (shop_id, enabled, earn_rate, burn_rate, min_redeem_points, expiry_days)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (shop_id) DO UPDATE SET
+    enabled = EXCLUDED.enabled,
+    earn_rate = EXCLUDED.earn_rate,
+    burn_rate = EXCLUDED.burn_rate,
+    min_redeem_points = EXCLUDED.min_redeem_points,
+    expiry_days = EXCLUDED.expiry_days,
+    updated_at = NOW()
+RETURNING *;
+
+-- name: LockCustomerLoyaltyPoints :one
+-- Serializes ledger writes of a customer for the rest of the transaction
+SELECT shop_customer_id FROM shop_customers
+WHERE shop_customer_id = $1 AND shop_id = $2
+FOR UPDATE;
+
+-- name: GetLoyaltyBalance :one
+-- last_activity_at is when points were last earned, redeemed or otherwise
+-- changed; expiry counts from there
+SELECT
+    COALESCE((
+        SELECT t.balance_after FROM loyalty_transactions t
+        WHERE t.shop_customer_id = $1 AND t.shop_id = $2
+        ORDER BY t.transaction_id DESC
+        LIMIT 1
+    ), 0)::int AS balance,
+    (
+        SELECT MAX(t.created_at) FROM loyalty_transactions t
+        WHERE t.shop_customer_id = $1 AND t.shop_id = $2 AND t.kind <> 'expire'
+    )::timestamptz AS last_activity_at;
+
+-- name: CreateLoyaltyTransaction :one
+-- Appends to the customer's ledger; balance_after carries the running balance
+-- and the check constraint rejects entries that would overdraw it. Callers
+-- hold LockCustomerLoyaltyPoints.
+INSERT INTO loyalty_transactions (shop_customer_id, kind, points, balance_after, order_id, note, created_by, shop_id)
+SELECT
+    sqlc.arg('shop_customer_id')::uuid,
+    sqlc.arg('kind')::loyalty_transaction_kind,
+    sqlc.arg('points')::int,
+    COALESCE((
+        SELECT t.balance_after FROM loyalty_transactions t
+        WHERE t.shop_customer_id = sqlc.arg('shop_customer_id')::uuid AND t.shop_id = sqlc.arg('shop_id')::bigint
+        ORDER BY t.transaction_id DESC
+        LIMIT 1
+    ), 0) + sqlc.arg('points')::int,
+    sqlc.narg('order_id')::bigint,
+    sqlc.narg('note')::text,
+    sqlc.narg('created_by')::varchar,
+    sqlc.arg('shop_id')::bigint
+RETURNING *;
+
+-- name: ListLoyaltyTransactions :many
+SELECT * FROM loyalty_transactions
+WHERE shop_customer_id = $1 AND shop_id = $2
+ORDER BY transaction_id DESC
+LIMIT $3 OFFSET $4;
+
+-- name: CountLoyaltyTransactions :one
+SELECT COUNT(*) FROM loyalty_transactions
+WHERE shop_customer_id = $1 AND shop_id = $2;
+
+-- name: ListOrderLoyaltyPoints :many
+-- Points an order still earns its customer and still takes from them, net of
+-- clawbacks and refunds. awarded tells whether the order ever earned points.
+SELECT
+    shop_customer_id,
+    COALESCE(SUM(points) FILTER (WHERE kind IN ('earn', 'clawback')), 0)::int AS earned,
+    (-COALESCE(SUM(points) FILTER (WHERE kind IN ('redeem', 'refund')), 0))::int AS redeemed,
+    BOOL_OR(kind = 'earn')::boolean AS awarded
+FROM loyalty_transactions
+WHERE order_id = $1 AND shop_id = $2
+GROUP BY shop_customer_id
+ORDER BY shop_customer_id;
@@ -0,0 +1,83 @@
+-- name: CreateMetafieldDefinition :one
+INSERT INTO metafield_definitions (owner_type, namespace, key, name, description, type, validations, shop_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING *;
+
+-- name: GetMetafieldDefinition :one
+SELECT * FROM metafield_definitions
+WHERE metafield_definition_id = $1 AND shop_id = $2;
+
+-- name: GetMetafieldDefinitionByKey :one
+SELECT * FROM metafield_definitions
+WHERE owner_type = $1 AND namespace = $2 AND key = $3 AND shop_id = $4;
+
+-- name: ListMetafieldDefinitions :many
+SELECT * FROM metafield_definitions
+WHERE shop_id = sqlc.arg('shop_id')
+AND (sqlc.narg('owner_type')::metafield_owner_type IS NULL OR owner_type = sqlc.narg('owner_type'))
+ORDER BY owner_type, namespace, key;
+
+-- name: UpdateMetafieldDefinition :one
+UPDATE metafield_definitions
+SET
+    name = COALESCE(sqlc.narg('name'), name),
+    description = COALESCE(sqlc.narg('description'), description),
+    validations = COALESCE(sqlc.narg('validations'), validations),
+    updated_at = NOW()
+WHERE metafield_definition_id = sqlc.arg('metafield_definition_id') AND shop_id = sqlc.arg('shop_id')
+RETURNING *;
+
+-- name: DeleteMetafieldDefinition :execrows
+DELETE FROM metafield_definitions
+WHERE metafield_definition_id = $1 AND shop_id = $2;
+
+-- name: CountMetafieldsWithOtherType :one
+-- Existing values that would violate a new definition for the same namespace and key
+SELECT COUNT(*) FROM metafields
+WHERE owner_type = $1 AND namespace = $2 AND key = $3 AND type <> $4 AND shop_id = $5;
+
+-- name: MetafieldOwnerExists :one
+SELECT (CASE sqlc.arg('owner_type')::metafield_owner_type
+    WHEN 'product' THEN EXISTS (
+        SELECT 1 FROM products
+        WHERE product_id::text = sqlc.arg('owner_id')::text AND shop_id = sqlc.arg('shop_id')
+    )
+    WHEN 'product_variation' THEN EXISTS (
+        SELECT 1 FROM product_variations
+        WHERE product_variation_id::text = sqlc.arg('owner_id')::text AND shop_id = sqlc.arg('shop_id')
+    )
+    WHEN 'category' THEN EXISTS (
+        SELECT 1 FROM categories
+        WHERE category_id::text = sqlc.arg('owner_id')::text AND shop_id = sqlc.arg('shop_id')
+    )
+    WHEN 'shop' THEN EXISTS (
+        SELECT 1 FROM shops
+        WHERE shop_id::text = sqlc.arg('owner_id')::text AND shop_id = sqlc.arg('shop_id')
+    )
+    WHEN 'customer' THEN EXISTS (
+        SELECT 1 FROM shop_customers
+        WHERE shop_customer_id::text = sqlc.arg('owner_id')::text AND shop_id = sqlc.arg('shop_id')
+    )
+    ELSE FALSE
+END)::boolean AS owner_exists;
+
+-- name: UpsertMetafield :one
+INSERT INTO metafields (owner_type, owner_id, namespace, key, type, value, shop_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (owner_type, owner_id, namespace, key, shop_id)
+DO UPDATE SET type = EXCLUDED.type, value = EXCLUDED.value, updated_at = NOW()
+RETURNING *;
+
+-- name: GetMetafield :one
+SELECT * FROM metafields
+WHERE owner_type = $1 AND owner_id = $2 AND namespace = $3 AND key = $4 AND shop_id = $5;
+
+-- name: GetMetafields :many
+SELECT * FROM metafields
+WHERE owner_type = sqlc.arg('owner_type') AND owner_id = sqlc.arg('owner_id') AND shop_id = sqlc.arg('shop_id')
+AND (sqlc.narg('namespace')::varchar IS NULL OR namespace = sqlc.narg('namespace'))
+ORDER BY namespace, key;
+
+-- name: DeleteMetafield :execrows
+DELETE FROM metafields
+WHERE owner_type = $1 AND owner_id = $2 AND namespace = $3 AND key = $4 AND shop_id = $5;
@@ -50,10 +50,12 @@ DELETE FROM orders
 WHERE order_id = $1 AND shop_id = $2;
 
 -- name: CreateOrderItem :one
+-- Records an order item with the cost price its variant has at the time of sale
 INSERT INTO order_items (
-    quantity, price, product_variation_id, order_id, shop_id
+    quantity, price, product_variation_id, order_id, shop_id, backordered_quantity, cost_price
 ) VALUES (
-    $1, $2, $3, $4, $5
+    $1, $2, $3, $4, $5, $6,
+    (SELECT cost_price FROM product_variations WHERE product_variation_id = $3 AND shop_id = $5)
 )
 RETURNING *;
 
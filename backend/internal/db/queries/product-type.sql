@@ -1,6 +1,6 @@
 -- name: CreateProductType :one
-INSERT INTO product_types (title, shippable, digital, sku_substring, shop_id)
-VALUES ($1, $2, $3, $4, $5)
+INSERT INTO product_types (title, shippable, digital, sku_substring, gift_card, tracks_lots, reorder_point, reorder_quantity, shop_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 RETURNING *;
 
 -- name: GetProductTypes :many
@@ -15,7 +15,11 @@ SET
     title = COALESCE(sqlc.narg('title'), title),
     shippable = COALESCE(sqlc.narg('shippable'), shippable),
     digital = COALESCE(sqlc.narg('digital'), digital),
-    sku_substring = COALESCE(sqlc.narg('sku_substring'), sku_substring)
+    sku_substring = COALESCE(sqlc.narg('sku_substring'), sku_substring),
+    gift_card = COALESCE(sqlc.narg('gift_card'), gift_card),
+    tracks_lots = COALESCE(sqlc.narg('tracks_lots'), tracks_lots),
+    reorder_point = COALESCE(sqlc.narg('reorder_point'), reorder_point),
+    reorder_quantity = COALESCE(sqlc.narg('reorder_quantity'), reorder_quantity)
 WHERE product_type_id = sqlc.arg('product_type_id') AND shop_id = sqlc.arg('shop_id')
 RETURNING *;
 
@@ -110,6 +110,7 @@ SELECT
                     'sku', pv.sku,
                     'available_quantity', pv.available_quantity,
                     'is_default', pv.is_default,
+                    'stock_status', variant_stock_status(pv),
                     'attributes', (
                         SELECT COALESCE(
                             jsonb_agg(
@@ -200,6 +201,7 @@ SELECT
                     'sku', pv.sku,
                     'available_quantity', pv.available_quantity,
                     'is_default', pv.is_default,
+                    'stock_status', variant_stock_status(pv),
                     'attributes', (
                         SELECT COALESCE(
                             jsonb_agg(
@@ -293,6 +295,7 @@ SELECT
                     'sku', pv.sku,
                     'available_quantity', pv.available_quantity,
                     'is_default', pv.is_default,
+                    'stock_status', variant_stock_status(pv),
                     'attributes', (
                         SELECT COALESCE(
                             jsonb_agg(
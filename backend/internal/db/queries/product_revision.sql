@@ -0,0 +1,114 @@
+-- name: LockProductRevisions :one
+-- Serializes revision numbering of a product for the rest of the transaction
+SELECT product_id FROM products
+WHERE product_id = $1 AND shop_id = $2
+FOR UPDATE;
+
+-- name: CreateProductRevision :one
+INSERT INTO product_revisions (version, snapshot, created_by, restored_from_version, product_id, shop_id)
+SELECT
+    COALESCE((
+        SELECT MAX(pr.version)
+        FROM product_revisions pr
+        WHERE pr.product_id = p.product_id AND pr.shop_id = p.shop_id
+    ), 0) + 1,
+    jsonb_build_object(
+        'product', to_jsonb(p),
+        'attributes', (
+            SELECT COALESCE(jsonb_agg(to_jsonb(pav) ORDER BY pav.attribute_id), '[]'::jsonb)
+            FROM product_attribute_values pav
+            WHERE pav.product_id = p.product_id
+        ),
+        'variants', (
+            SELECT COALESCE(
+                jsonb_agg(
+                    to_jsonb(pv) || jsonb_build_object(
+                        'attributes', (
+                            SELECT COALESCE(jsonb_agg(to_jsonb(pva) ORDER BY pva.attribute_id), '[]'::jsonb)
+                            FROM product_variation_attribute_values pva
+                            WHERE pva.product_variation_id = pv.product_variation_id
+                        )
+                    )
+                    ORDER BY pv.product_variation_id
+                ),
+                '[]'::jsonb
+            )
+            FROM product_variations pv
+            WHERE pv.product_id = p.product_id
+        ),
+        'images', (
+            SELECT COALESCE(jsonb_agg(to_jsonb(pi) ORDER BY pi.product_image_id), '[]'::jsonb)
+            FROM product_images pi
+            WHERE pi.product_id = p.product_id
+        )
+    ),
+    sqlc.narg('created_by'),
+    sqlc.narg('restored_from_version'),
+    p.product_id,
+    p.shop_id
+FROM products p
+WHERE p.product_id = sqlc.arg('product_id') AND p.shop_id = sqlc.arg('shop_id')
+RETURNING *;
+
+-- name: GetProductRevisions :many
+SELECT product_revision_id, version, created_by, restored_from_version, created_at, product_id, shop_id
+FROM product_revisions
+WHERE product_id = sqlc.arg('product_id') AND shop_id = sqlc.arg('shop_id')
+ORDER BY version DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CountProductRevisions :one
+SELECT COUNT(*) FROM product_revisions
+WHERE product_id = $1 AND shop_id = $2;
+
+-- name: GetProductRevision :one
+SELECT * FROM product_revisions
+WHERE product_id = $1 AND shop_id = $2 AND version = $3;
+
+-- name: RestoreProduct :exec
+UPDATE products
+SET
+    slug = sqlc.arg('slug'),
+    title = sqlc.arg('title'),
+    description = sqlc.arg('description'),
+    status = sqlc.arg('status'),
+    product_type_id = sqlc.arg('product_type_id'),
+    category_id = sqlc.narg('category_id'),
+    updated_at = NOW()
+WHERE product_id = sqlc.arg('product_id') AND shop_id = sqlc.arg('shop_id');
+
+-- name: RestoreProductVariation :exec
+INSERT INTO product_variations (
+    product_variation_id, sku, description, price, available_quantity,
+    seo_description, seo_keywords, seo_title, is_default,
+    product_id, shop_id
+)
+VALUES (
+    $1, $2, $3, $4, $5,
+    $6, $7, $8, $9,
+    $10, $11
+)
+ON CONFLICT (product_variation_id)
+DO UPDATE SET
+    sku = EXCLUDED.sku,
+    description = EXCLUDED.description,
+    price = EXCLUDED.price,
+    seo_description = EXCLUDED.seo_description,
+    seo_keywords = EXCLUDED.seo_keywords,
+    seo_title = EXCLUDED.seo_title,
+    is_default = EXCLUDED.is_default,
+    updated_at = NOW();
+
+-- name: DeleteProductAttributeValuesByProduct :exec
+DELETE FROM product_attribute_values
+WHERE product_id = $1 AND shop_id = $2;
+
+-- name: DeleteProductVariationAttributeValuesByProduct :exec
+DELETE FROM product_variation_attribute_values pva
+USING product_variations pv
+WHERE pva.product_variation_id = pv.product_variation_id
+AND pv.product_id = $1 AND pva.shop_id = $2;
+
+-- name: RestoreProductImage :exec
+INSERT INTO product_images (product_image_id, url, alt, product_id, shop_id)
+VALUES ($1, $2, $3, $4, $5);
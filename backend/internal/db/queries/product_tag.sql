@@ -0,0 +1,24 @@
+-- name: GetProductTags :many
+SELECT tag FROM product_tags
+WHERE product_id = $1 AND shop_id = $2
+ORDER BY tag;
+
+-- name: AddProductTags :exec
+INSERT INTO product_tags (product_id, tag, shop_id)
+SELECT sqlc.arg('product_id'), t.tag, sqlc.arg('shop_id')
+FROM unnest(sqlc.arg('tags')::varchar[]) AS t(tag)
+ON CONFLICT (product_id, tag) DO NOTHING;
+
+-- name: DeleteProductTags :exec
+DELETE FROM product_tags
+WHERE product_id = $1 AND shop_id = $2;
+
+-- name: GetShopTags :many
+-- Tags that differ only by case are reported once, using the first spelling alphabetically
+SELECT
+    MIN(tag)::varchar AS tag,
+    COUNT(DISTINCT product_id) AS product_count
+FROM product_tags
+WHERE shop_id = $1
+GROUP BY LOWER(tag)
+ORDER BY LOWER(tag);
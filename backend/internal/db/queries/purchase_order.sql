@@ -0,0 +1,148 @@
+-- name: CreateSupplier :one
+INSERT INTO suppliers (name, contact_name, email, phone, address, notes, shop_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: GetSupplier :one
+SELECT * FROM suppliers
+WHERE supplier_id = $1 AND shop_id = $2;
+
+-- name: ListSuppliers :many
+SELECT * FROM suppliers
+WHERE shop_id = $1
+ORDER BY name
+LIMIT $2 OFFSET $3;
+
+-- name: CountSuppliers :one
+SELECT COUNT(*) FROM suppliers
+WHERE shop_id = $1;
+
+-- name: UpdateSupplier :one
+UPDATE suppliers
+SET name = $3,
+    contact_name = $4,
+    email = $5,
+    phone = $6,
+    address = $7,
+    notes = $8,
+    updated_at = NOW()
+WHERE supplier_id = $1 AND shop_id = $2
+RETURNING *;
+
+-- name: DeleteSupplier :execrows
+DELETE FROM suppliers
+WHERE supplier_id = $1 AND shop_id = $2;
+
+-- name: CreatePurchaseOrder :one
+INSERT INTO purchase_orders (supplier_id, location_id, expected_at, note, created_by, shop_id)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: GetPurchaseOrder :one
+SELECT * FROM purchase_orders
+WHERE purchase_order_id = $1 AND shop_id = $2;
+
+-- name: ListPurchaseOrders :many
+SELECT * FROM purchase_orders
+WHERE shop_id = $1
+AND ($2::purchase_order_status IS NULL OR status = $2)
+AND ($3::bigint IS NULL OR supplier_id = $3)
+ORDER BY created_at DESC, purchase_order_id DESC
+LIMIT $4 OFFSET $5;
+
+-- name: CountPurchaseOrders :one
+SELECT COUNT(*) FROM purchase_orders
+WHERE shop_id = $1
+AND ($2::purchase_order_status IS NULL OR status = $2)
+AND ($3::bigint IS NULL OR supplier_id = $3);
+
+-- name: UpdatePurchaseOrder :one
+-- Updates a purchase order that has not been fully received yet
+UPDATE purchase_orders
+SET supplier_id = $3,
+    location_id = $4,
+    expected_at = $5,
+    note = $6,
+    updated_at = NOW()
+WHERE purchase_order_id = $1 AND shop_id = $2 AND status <> 'received'
+RETURNING *;
+
+-- name: MarkPurchaseOrderOrdered :one
+UPDATE purchase_orders
+SET status = 'ordered',
+    ordered_at = NOW(),
+    updated_at = NOW()
+WHERE purchase_order_id = $1 AND shop_id = $2 AND status = 'draft'
+RETURNING *;
+
+-- name: SyncPurchaseOrderStatus :one
+-- Marks a purchase order received once every item has arrived in full, and
+-- partially received before that
+UPDATE purchase_orders po
+SET status = CASE WHEN received THEN 'received' ELSE 'partially_received' END::purchase_order_status,
+    received_at = CASE WHEN received THEN NOW() END,
+    updated_at = NOW()
+FROM (
+    SELECT NOT EXISTS (
+        SELECT 1 FROM purchase_order_items
+        WHERE purchase_order_id = $1 AND shop_id = $2 AND quantity_received < quantity_ordered
+    ) AS received
+) items
+WHERE po.purchase_order_id = $1 AND po.shop_id = $2
+RETURNING po.*;
+
+-- name: DeletePurchaseOrder :execrows
+-- Deletes a purchase order that has not been placed with the supplier yet
+DELETE FROM purchase_orders
+WHERE purchase_order_id = $1 AND shop_id = $2 AND status = 'draft';
+
+-- name: CreatePurchaseOrderItem :one
+INSERT INTO purchase_order_items (purchase_order_id, product_variation_id, quantity_ordered, unit_cost, shop_id)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: DeletePurchaseOrderItems :exec
+DELETE FROM purchase_order_items
+WHERE purchase_order_id = $1 AND shop_id = $2;
+
+-- name: ListPurchaseOrderItems :many
+SELECT
+    poi.*,
+    p.title AS product_title,
+    pv.description AS variant_title,
+    pv.sku
+FROM purchase_order_items poi
+JOIN product_variations pv ON poi.product_variation_id = pv.product_variation_id
+JOIN products p ON pv.product_id = p.product_id
+WHERE poi.purchase_order_id = $1 AND poi.shop_id = $2
+ORDER BY poi.purchase_order_item_id;
+
+-- name: ReceivePurchaseOrderItem :one
+-- Counts a quantity of an item as received, as long as no more arrives than was ordered
+UPDATE purchase_order_items
+SET quantity_received = quantity_received + $4
+WHERE purchase_order_item_id = $1 AND purchase_order_id = $2 AND shop_id = $3
+AND quantity_received + $4 <= quantity_ordered
+RETURNING *;
+
+-- name: GetVariantStockOnHand :one
+-- Returns the stock of a variant across all of its locations
+SELECT COALESCE(SUM(available), 0)::bigint AS on_hand
+FROM inventory_levels
+WHERE product_variation_id = $1 AND shop_id = $2;
+
+-- name: AverageVariantCostPrice :one
+-- Averages the unit cost of received stock into the cost price of a variant,
+-- weighted by the stock on hand before it arrived
+UPDATE product_variations
+SET cost_price = ROUND(
+    (COALESCE(cost_price, sqlc.arg('unit_cost')::numeric) * sqlc.arg('on_hand')::bigint + sqlc.arg('unit_cost')::numeric * sqlc.arg('quantity')::bigint)
+    / (sqlc.arg('on_hand')::bigint + sqlc.arg('quantity')::bigint), 4)
+WHERE product_variation_id = sqlc.arg('product_variation_id') AND shop_id = sqlc.arg('shop_id')
+RETURNING *;
+
+-- name: SetVariantCostPrice :one
+UPDATE product_variations
+SET cost_price = $3
+WHERE product_variation_id = $1 AND shop_id = $2
+RETURNING *;
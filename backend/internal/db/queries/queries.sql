@@ -109,11 +109,15 @@ ORDER BY o.created_at DESC
 LIMIT $3 OFFSET $4;
 
 -- name: GetLowStockVariants :many
-SELECT pv.*, p.title as product_title
+-- Variants at or below the threshold, or at or below their reorder point when no
+-- threshold is given. Variants without a reorder point use a threshold of 10.
+SELECT pv.*, p.title as product_title,
+    COALESCE(sqlc.narg('threshold')::bigint, pv.reorder_point, pt.reorder_point, 10)::bigint AS reorder_level
 FROM product_variations pv
 JOIN products p ON pv.product_id = p.product_id
-WHERE pv.shop_id = $1 
-AND pv.available_quantity <= $2
+JOIN product_types pt ON pt.product_type_id = p.product_type_id
+WHERE pv.shop_id = sqlc.arg('shop_id')
+AND pv.available_quantity <= COALESCE(sqlc.narg('threshold')::bigint, pv.reorder_point, pt.reorder_point, 10)
 ORDER BY pv.available_quantity ASC;
 
 -- name: GetProductVariation :one
@@ -121,24 +125,25 @@ SELECT * FROM product_variations
 WHERE product_variation_id = $1 AND shop_id = $2;
 
 -- name: UpdateVariantStock :one
-UPDATE product_variations
-SET available_quantity = $3,
+UPDATE inventory_levels
+SET available = $4,
     updated_at = NOW()
-WHERE product_variation_id = $1 AND shop_id = $2
+WHERE product_variation_id = $1 AND location_id = $2 AND shop_id = $3
 RETURNING *;
 
 -- name: DeductVariantStock :one
-UPDATE product_variations
-SET available_quantity = GREATEST(available_quantity - $3, 0),
+UPDATE inventory_levels
+SET available = available - $4,
     updated_at = NOW()
-WHERE product_variation_id = $1 AND shop_id = $2
+WHERE product_variation_id = $1 AND location_id = $2 AND shop_id = $3
+AND available >= $4
 RETURNING *;
 
 -- name: AddVariantStock :one
-UPDATE product_variations
-SET available_quantity = available_quantity + $3,
+UPDATE inventory_levels
+SET available = available + $4,
     updated_at = NOW()
-WHERE product_variation_id = $1 AND shop_id = $2
+WHERE product_variation_id = $1 AND location_id = $2 AND shop_id = $3
 RETURNING *;
 
 -- name: GetInventoryReport :many
@@ -156,12 +161,14 @@ SELECT
     pv.updated_at,
     CASE 
         WHEN pv.available_quantity = 0 THEN 'OUT_OF_STOCK'
-        WHEN pv.available_quantity <= $2 THEN 'LOW_STOCK'
+        WHEN pv.available_quantity <= COALESCE(pv.reorder_point, pt.reorder_point, sqlc.arg('default_threshold')::bigint) THEN 'LOW_STOCK'
         ELSE 'IN_STOCK'
-    END as stock_status
+    END as stock_status,
+    COALESCE(pv.reorder_point, pt.reorder_point, sqlc.arg('default_threshold')::bigint)::bigint as low_stock_threshold
 FROM product_variations pv
 JOIN products p ON pv.product_id = p.product_id
-WHERE pv.shop_id = $1
+JOIN product_types pt ON pt.product_type_id = p.product_type_id
+WHERE pv.shop_id = sqlc.arg('shop_id')
 ORDER BY pv.available_quantity ASC;
 
 -- name: GetStockMovements :many
@@ -186,8 +193,9 @@ INSERT INTO stock_movements (
     quantity_before, 
     quantity_after, 
     reference_id, 
-    notes
-) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+    notes,
+    location_id
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 RETURNING *;
 
 -- Payment Status Management
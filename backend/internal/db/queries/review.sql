@@ -0,0 +1,64 @@
+-- name: GetReviewableOrderItem :one
+-- Returns the order line a review is left for, together with the order details
+-- used to check that the reviewer bought the variant
+SELECT o.order_id, o.customer_name, o.customer_email, o.payment_status, oi.product_variation_id, pv.product_id
+FROM orders o
+JOIN order_items oi ON oi.order_id = o.order_id
+JOIN product_variations pv ON pv.product_variation_id = oi.product_variation_id
+WHERE o.order_id = sqlc.arg('order_id') AND oi.product_variation_id = sqlc.arg('product_variation_id') AND o.shop_id = sqlc.arg('shop_id')
+LIMIT 1;
+
+-- name: CreateProductReview :one
+INSERT INTO product_reviews (product_id, product_variation_id, order_id, rating, title, body, photos, customer_name, customer_email, shop_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+RETURNING *;
+
+-- name: GetProductReview :one
+SELECT * FROM product_reviews
+WHERE review_id = $1 AND shop_id = $2;
+
+-- name: ListProductReviews :many
+SELECT * FROM product_reviews
+WHERE shop_id = sqlc.arg('shop_id')
+    AND (sqlc.narg('status')::review_status IS NULL OR status = sqlc.narg('status'))
+ORDER BY created_at, review_id
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CountProductReviews :one
+SELECT COUNT(*) FROM product_reviews
+WHERE shop_id = sqlc.arg('shop_id')
+    AND (sqlc.narg('status')::review_status IS NULL OR status = sqlc.narg('status'));
+
+-- name: GetApprovedProductReviews :many
+-- Lists the approved reviews of a product, newest first
+SELECT * FROM product_reviews
+WHERE product_id = sqlc.arg('product_id') AND shop_id = sqlc.arg('shop_id') AND status = 'approved'
+    AND (sqlc.arg('before')::bigint = 0 OR review_id < sqlc.arg('before'))
+ORDER BY review_id DESC
+LIMIT sqlc.arg('limit');
+
+-- name: UpdateProductReviewStatus :one
+UPDATE product_reviews
+SET status = $1, updated_at = NOW()
+WHERE review_id = $2 AND shop_id = $3
+RETURNING *;
+
+-- name: ReplyToProductReview :one
+UPDATE product_reviews
+SET merchant_reply = $1, replied_at = NOW(), updated_at = NOW()
+WHERE review_id = $2 AND shop_id = $3
+RETURNING *;
+
+-- name: RefreshProductRating :one
+-- Recomputes the rating aggregate of a product from its approved reviews
+INSERT INTO product_ratings (product_id, rating_count, rating_sum, shop_id)
+SELECT sqlc.arg('product_id')::bigint, COUNT(*), COALESCE(SUM(r.rating), 0), sqlc.arg('shop_id')::bigint
+FROM product_reviews r
+WHERE r.product_id = sqlc.arg('product_id') AND r.shop_id = sqlc.arg('shop_id') AND r.status = 'approved'
+ON CONFLICT (product_id)
+DO UPDATE SET rating_count = EXCLUDED.rating_count, rating_sum = EXCLUDED.rating_sum, updated_at = NOW()
+RETURNING *;
+
+-- name: GetProductRating :one
+SELECT * FROM product_ratings
+WHERE product_id = $1 AND shop_id = $2;
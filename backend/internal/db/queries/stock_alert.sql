@@ -0,0 +1,149 @@
+-- name: GetVariantReorderPoint :one
+-- The reorder point and quantity of a variant, falling back to those of its
+-- product type
+SELECT
+    COALESCE(pv.reorder_point, pt.reorder_point) AS reorder_point,
+    COALESCE(pv.reorder_quantity, pt.reorder_quantity) AS reorder_quantity
+FROM product_variations pv
+JOIN products p ON p.product_id = pv.product_id
+JOIN product_types pt ON pt.product_type_id = p.product_type_id
+WHERE pv.product_variation_id = $1 AND pv.shop_id = $2;
+
+-- name: SetVariantReorderPoint :one
+UPDATE product_variations
+SET reorder_point = $3,
+    reorder_quantity = $4,
+    updated_at = NOW()
+WHERE product_variation_id = $1 AND shop_id = $2
+RETURNING *;
+
+-- name: CreateStockAlert :one
+INSERT INTO stock_alerts (product_variation_id, kind, available_quantity, reorder_point, reorder_quantity, shop_id)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: QueueStockAlertNotification :execrows
+-- Queues an email about a stock alert to the shop
+INSERT INTO notifications (kind, recipient, payload, shop_id)
+SELECT
+    'stock_alert',
+    s.email,
+    jsonb_build_object(
+        'alert_id', a.alert_id,
+        'kind', a.kind,
+        'product_variation_id', a.product_variation_id,
+        'product_id', p.product_id,
+        'product_title', p.title,
+        'variant_title', pv.description,
+        'sku', pv.sku,
+        'available_quantity', a.available_quantity,
+        'reorder_point', a.reorder_point,
+        'reorder_quantity', a.reorder_quantity
+    ),
+    a.shop_id
+FROM stock_alerts a
+JOIN shops s ON s.shop_id = a.shop_id
+JOIN product_variations pv ON pv.product_variation_id = a.product_variation_id
+JOIN products p ON p.product_id = pv.product_id
+WHERE a.alert_id = $1 AND a.shop_id = $2;
+
+-- name: ListStockAlerts :many
+SELECT
+    a.*,
+    p.product_id,
+    p.title AS product_title,
+    pv.description AS variant_title,
+    pv.sku
+FROM stock_alerts a
+JOIN product_variations pv ON pv.product_variation_id = a.product_variation_id
+JOIN products p ON p.product_id = pv.product_id
+WHERE a.shop_id = sqlc.arg('shop_id')
+AND (NOT sqlc.arg('unread_only')::boolean OR a.read_at IS NULL)
+ORDER BY a.created_at DESC, a.alert_id DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CountStockAlerts :one
+SELECT COUNT(*) FROM stock_alerts a
+WHERE a.shop_id = sqlc.arg('shop_id')
+AND (NOT sqlc.arg('unread_only')::boolean OR a.read_at IS NULL);
+
+-- name: MarkStockAlertRead :one
+UPDATE stock_alerts
+SET read_at = COALESCE(read_at, NOW())
+WHERE alert_id = $1 AND shop_id = $2
+RETURNING *;
+
+-- name: MarkAllStockAlertsRead :execrows
+UPDATE stock_alerts
+SET read_at = NOW()
+WHERE shop_id = $1 AND read_at IS NULL;
+
+-- name: ListReorderVariants :many
+-- Lists the variants at or below their reorder point, lowest stock first, with the
+-- number of units to reorder: the reorder quantity, or enough to get back above
+-- the reorder point
+SELECT
+    pv.product_variation_id,
+    p.product_id,
+    p.title AS product_title,
+    pv.description AS variant_title,
+    pv.sku,
+    pv.available_quantity,
+    pv.cost_price,
+    COALESCE(pv.reorder_point, pt.reorder_point)::bigint AS reorder_point,
+    COALESCE(pv.reorder_quantity, pt.reorder_quantity, COALESCE(pv.reorder_point, pt.reorder_point) - pv.available_quantity + 1)::bigint AS suggested_quantity
+FROM product_variations pv
+JOIN products p ON p.product_id = pv.product_id
+JOIN product_types pt ON pt.product_type_id = p.product_type_id
+WHERE pv.shop_id = $1
+AND pv.available_quantity <= COALESCE(pv.reorder_point, pt.reorder_point)
+ORDER BY pv.available_quantity, pv.product_variation_id;
+
+-- name: ClaimLowStockDigestRun :one
+-- Claims the low stock digest run for the rest of the transaction. Only one
+-- instance of the API gets the claim while the others skip the run.
+SELECT pg_try_advisory_xact_lock(hashtext('low_stock_digest'))::bool AS claimed;
+
+-- name: QueueLowStockDigests :execrows
+-- Queues an email to every shop with variants at or below their reorder point
+-- listing them, unless the shop was sent one in the last 20 hours
+WITH low_stock AS (
+    SELECT
+        pv.shop_id,
+        pv.product_variation_id,
+        p.title AS product_title,
+        pv.description AS variant_title,
+        pv.sku,
+        pv.available_quantity,
+        COALESCE(pv.reorder_point, pt.reorder_point) AS reorder_point,
+        COALESCE(pv.reorder_quantity, pt.reorder_quantity, COALESCE(pv.reorder_point, pt.reorder_point) - pv.available_quantity + 1) AS suggested_quantity
+    FROM product_variations pv
+    JOIN products p ON p.product_id = pv.product_id
+    JOIN product_types pt ON pt.product_type_id = p.product_type_id
+    WHERE pv.available_quantity <= COALESCE(pv.reorder_point, pt.reorder_point)
+)
+INSERT INTO notifications (kind, recipient, payload, shop_id)
+SELECT
+    'low_stock_digest',
+    s.email,
+    jsonb_build_object(
+        'shop_title', s.title,
+        'items', jsonb_agg(jsonb_build_object(
+            'product_variation_id', ls.product_variation_id,
+            'product_title', ls.product_title,
+            'variant_title', ls.variant_title,
+            'sku', ls.sku,
+            'available_quantity', ls.available_quantity,
+            'reorder_point', ls.reorder_point,
+            'suggested_quantity', ls.suggested_quantity
+        ) ORDER BY ls.available_quantity, ls.product_variation_id)
+    ),
+    s.shop_id
+FROM low_stock ls
+JOIN shops s ON s.shop_id = ls.shop_id
+WHERE NOT EXISTS (
+    SELECT 1 FROM notifications n
+    WHERE n.shop_id = s.shop_id AND n.kind = 'low_stock_digest'
+    AND n.created_at > NOW() - INTERVAL '20 hours'
+)
+GROUP BY s.shop_id, s.email, s.title;
@@ -0,0 +1,134 @@
+-- name: CreateStocktake :one
+INSERT INTO stocktakes (location_id, category_id, product_type_id, note, created_by, shop_id)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: AddStocktakeItems :execrows
+-- Adds the variants a stocktake counts with the stock they have at its location:
+-- those of its category and the subcategories below it, of its product type, or
+-- every variant of the shop. Bundles hold no stock of their own and are not counted.
+WITH RECURSIVE counted_categories AS (
+    SELECT c.category_id FROM categories c
+    WHERE c.category_id = $3 AND c.shop_id = $5
+    UNION ALL
+    SELECT c.category_id FROM categories c
+    JOIN counted_categories cc ON c.parent_id = cc.category_id
+)
+INSERT INTO stocktake_items (stocktake_id, product_variation_id, expected_quantity, shop_id)
+SELECT $1, pv.product_variation_id, COALESCE(il.available, 0), pv.shop_id
+FROM product_variations pv
+JOIN products p ON pv.product_id = p.product_id
+LEFT JOIN inventory_levels il ON il.product_variation_id = pv.product_variation_id AND il.location_id = $2
+WHERE pv.shop_id = $5
+AND ($3::bigint IS NULL OR p.category_id IN (SELECT category_id FROM counted_categories))
+AND ($4::bigint IS NULL OR p.product_type_id = $4)
+AND NOT EXISTS (SELECT 1 FROM bundle_components bc WHERE bc.bundle_variation_id = pv.product_variation_id);
+
+-- name: GetStocktake :one
+SELECT * FROM stocktakes
+WHERE stocktake_id = $1 AND shop_id = $2;
+
+-- name: ListStocktakes :many
+SELECT * FROM stocktakes
+WHERE shop_id = $1
+AND ($2::stocktake_status IS NULL OR status = $2)
+ORDER BY created_at DESC, stocktake_id DESC
+LIMIT $3 OFFSET $4;
+
+-- name: CountStocktakes :one
+SELECT COUNT(*) FROM stocktakes
+WHERE shop_id = $1
+AND ($2::stocktake_status IS NULL OR status = $2);
+
+-- name: CommitStocktake :one
+UPDATE stocktakes
+SET status = 'committed',
+    committed_at = NOW(),
+    updated_at = NOW()
+WHERE stocktake_id = $1 AND shop_id = $2 AND status = 'open'
+RETURNING *;
+
+-- name: CancelStocktake :one
+UPDATE stocktakes
+SET status = 'cancelled',
+    updated_at = NOW()
+WHERE stocktake_id = $1 AND shop_id = $2 AND status = 'open'
+RETURNING *;
+
+-- name: SetStocktakeCount :one
+-- Records the quantity counted of a variant, found by its ID or SKU, and the stock
+-- the location holds at the time of the count
+UPDATE stocktake_items si
+SET counted_quantity = $4,
+    expected_quantity = COALESCE((
+        SELECT il.available FROM inventory_levels il
+        WHERE il.product_variation_id = si.product_variation_id AND il.location_id = s.location_id
+    ), 0),
+    counted_at = NOW()
+FROM stocktakes s, product_variations pv
+WHERE s.stocktake_id = si.stocktake_id AND pv.product_variation_id = si.product_variation_id
+AND si.stocktake_id = $1 AND si.shop_id = $5
+AND (si.product_variation_id = $2::bigint OR pv.sku = $3::text)
+RETURNING si.*;
+
+-- name: AddStocktakeCount :one
+-- Adds to the quantity counted of a variant, found by its ID or SKU, for counts
+-- made one scan at a time
+UPDATE stocktake_items si
+SET counted_quantity = COALESCE(si.counted_quantity, 0) + $4,
+    expected_quantity = COALESCE((
+        SELECT il.available FROM inventory_levels il
+        WHERE il.product_variation_id = si.product_variation_id AND il.location_id = s.location_id
+    ), 0),
+    counted_at = NOW()
+FROM stocktakes s, product_variations pv
+WHERE s.stocktake_id = si.stocktake_id AND pv.product_variation_id = si.product_variation_id
+AND si.stocktake_id = $1 AND si.shop_id = $5
+AND (si.product_variation_id = $2::bigint OR pv.sku = $3::text)
+RETURNING si.*;
+
+-- name: ListStocktakeItems :many
+SELECT
+    si.*,
+    p.title AS product_title,
+    pv.description AS variant_title,
+    pv.sku,
+    pv.cost_price
+FROM stocktake_items si
+JOIN product_variations pv ON si.product_variation_id = pv.product_variation_id
+JOIN products p ON pv.product_id = p.product_id
+WHERE si.stocktake_id = $1 AND si.shop_id = $2
+AND ($3::bool IS NULL OR (si.counted_quantity IS NOT NULL) = $3)
+ORDER BY p.title, pv.product_variation_id
+LIMIT $4 OFFSET $5;
+
+-- name: CountStocktakeItems :one
+SELECT COUNT(*) FROM stocktake_items
+WHERE stocktake_id = $1 AND shop_id = $2
+AND ($3::bool IS NULL OR (counted_quantity IS NOT NULL) = $3);
+
+-- name: ListStocktakeVariances :many
+-- Lists the counted variants whose count differs from the stock expected
+SELECT
+    si.*,
+    p.title AS product_title,
+    pv.description AS variant_title,
+    pv.sku,
+    pv.cost_price
+FROM stocktake_items si
+JOIN product_variations pv ON si.product_variation_id = pv.product_variation_id
+JOIN products p ON pv.product_id = p.product_id
+WHERE si.stocktake_id = $1 AND si.shop_id = $2
+AND si.counted_quantity IS NOT NULL AND si.counted_quantity <> si.expected_quantity
+ORDER BY p.title, pv.product_variation_id;
+
+-- name: GetStocktakeSummary :one
+SELECT
+    COUNT(*) AS items,
+    COUNT(si.counted_quantity) AS counted,
+    COUNT(*) FILTER (WHERE si.counted_quantity <> si.expected_quantity) AS variances,
+    COALESCE(SUM(si.counted_quantity - si.expected_quantity), 0)::bigint AS net_variance,
+    COALESCE(SUM((si.counted_quantity - si.expected_quantity) * pv.cost_price), 0)::numeric AS variance_value
+FROM stocktake_items si
+JOIN product_variations pv ON si.product_variation_id = pv.product_variation_id
+WHERE si.stocktake_id = $1 AND si.shop_id = $2;
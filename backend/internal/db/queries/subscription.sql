@@ -0,0 +1,241 @@
+-- name: ListSubscriptionPlans :many
+SELECT * FROM subscription_plans
+WHERE product_variation_id = $1 AND shop_id = $2
+ORDER BY plan_id;
+
+-- name: ListActiveSubscriptionPlans :many
+-- The plans customers can subscribe to on a variant
+SELECT * FROM subscription_plans
+WHERE product_variation_id = $1 AND shop_id = $2 AND active
+ORDER BY plan_id;
+
+-- name: GetSubscriptionPlan :one
+SELECT * FROM subscription_plans
+WHERE plan_id = $1 AND shop_id = $2;
+
+-- name: CreateSubscriptionPlan :one
+INSERT INTO subscription_plans (product_variation_id, name, interval_unit, interval_count, discount_percent, trial_days, active, shop_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING *;
+
+-- name: UpdateSubscriptionPlan :one
+UPDATE subscription_plans
+SET name = COALESCE(sqlc.narg('name'), name),
+    interval_unit = COALESCE(sqlc.narg('interval_unit'), interval_unit),
+    interval_count = COALESCE(sqlc.narg('interval_count'), interval_count),
+    discount_percent = COALESCE(sqlc.narg('discount_percent'), discount_percent),
+    trial_days = COALESCE(sqlc.narg('trial_days'), trial_days),
+    active = COALESCE(sqlc.narg('active'), active),
+    updated_at = NOW()
+WHERE plan_id = sqlc.arg('plan_id') AND shop_id = sqlc.arg('shop_id')
+RETURNING *;
+
+-- name: DeleteSubscriptionPlan :one
+DELETE FROM subscription_plans
+WHERE plan_id = $1 AND shop_id = $2
+RETURNING *;
+
+-- name: ListCustomerPaymentMethods :many
+SELECT * FROM customer_payment_methods
+WHERE shop_customer_id = $1 AND shop_id = $2
+ORDER BY customer_payment_method_id;
+
+-- name: GetCustomerPaymentMethod :one
+SELECT * FROM customer_payment_methods
+WHERE customer_payment_method_id = $1 AND shop_customer_id = $2 AND shop_id = $3;
+
+-- name: CreateCustomerPaymentMethod :one
+-- Saves a payment method, refreshing its details when the customer saves it again.
+-- A payment method saved by another customer is not taken over.
+INSERT INTO customer_payment_methods (shop_customer_id, provider, customer_reference, token, brand, last4, exp_month, exp_year, shop_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+ON CONFLICT (provider, token, shop_id) DO UPDATE
+SET customer_reference = EXCLUDED.customer_reference,
+    brand = EXCLUDED.brand,
+    last4 = EXCLUDED.last4,
+    exp_month = EXCLUDED.exp_month,
+    exp_year = EXCLUDED.exp_year
+WHERE customer_payment_methods.shop_customer_id = EXCLUDED.shop_customer_id
+RETURNING *;
+
+-- name: DeleteCustomerPaymentMethod :execrows
+DELETE FROM customer_payment_methods
+WHERE customer_payment_method_id = $1 AND shop_customer_id = $2 AND shop_id = $3;
+
+-- name: CreateSubscription :one
+INSERT INTO subscriptions (
+    shop_customer_id, plan_id, quantity, status, customer_payment_method_id,
+    shipping_address, shipping_method, next_renewal_at, shop_id
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING *;
+
+-- name: GetSubscription :one
+SELECT s.*, sp.name AS plan_name, sp.product_variation_id, sp.interval_unit, sp.interval_count, sp.discount_percent, sc.email AS customer_email
+FROM subscriptions s
+JOIN subscription_plans sp ON sp.plan_id = s.plan_id
+JOIN shop_customers sc ON sc.shop_customer_id = s.shop_customer_id
+WHERE s.subscription_id = $1 AND s.shop_id = $2;
+
+-- name: GetCustomerSubscription :one
+SELECT s.*, sp.name AS plan_name, sp.product_variation_id, sp.interval_unit, sp.interval_count, sp.discount_percent, sc.email AS customer_email
+FROM subscriptions s
+JOIN subscription_plans sp ON sp.plan_id = s.plan_id
+JOIN shop_customers sc ON sc.shop_customer_id = s.shop_customer_id
+WHERE s.subscription_id = $1 AND s.shop_customer_id = $2 AND s.shop_id = $3;
+
+-- name: ListSubscriptions :many
+SELECT s.*, sp.name AS plan_name, sp.product_variation_id, sp.interval_unit, sp.interval_count, sp.discount_percent, sc.email AS customer_email
+FROM subscriptions s
+JOIN subscription_plans sp ON sp.plan_id = s.plan_id
+JOIN shop_customers sc ON sc.shop_customer_id = s.shop_customer_id
+WHERE s.shop_id = sqlc.arg('shop_id')
+    AND (sqlc.narg('status')::subscription_status IS NULL OR s.status = sqlc.narg('status'))
+ORDER BY s.subscription_id DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CountSubscriptions :one
+SELECT COUNT(*) FROM subscriptions
+WHERE shop_id = sqlc.arg('shop_id')
+    AND (sqlc.narg('status')::subscription_status IS NULL OR status = sqlc.narg('status'));
+
+-- name: ListCustomerSubscriptions :many
+SELECT s.*, sp.name AS plan_name, sp.product_variation_id, sp.interval_unit, sp.interval_count, sp.discount_percent, sc.email AS customer_email
+FROM subscriptions s
+JOIN subscription_plans sp ON sp.plan_id = s.plan_id
+JOIN shop_customers sc ON sc.shop_customer_id = s.shop_customer_id
+WHERE s.shop_customer_id = $1 AND s.shop_id = $2
+ORDER BY s.subscription_id DESC;
+
+-- name: UpdateSubscription :one
+-- Changes the quantity or payment method of a subscription. A past due
+-- subscription given a new payment method is retried straight away.
+UPDATE subscriptions
+SET quantity = COALESCE(sqlc.narg('quantity'), quantity),
+    customer_payment_method_id = COALESCE(sqlc.narg('customer_payment_method_id'), customer_payment_method_id),
+    next_retry_at = CASE
+        WHEN status = 'past_due' AND sqlc.narg('customer_payment_method_id')::bigint IS NOT NULL THEN NOW()
+        ELSE next_retry_at
+    END,
+    updated_at = NOW()
+WHERE subscription_id = sqlc.arg('subscription_id') AND shop_id = sqlc.arg('shop_id') AND status <> 'cancelled'
+RETURNING *;
+
+-- name: PauseSubscription :one
+-- Pauses a subscription that is not past due or cancelled, until paused_until or
+-- indefinitely
+UPDATE subscriptions
+SET status = 'paused', paused_until = $3, updated_at = NOW()
+WHERE subscription_id = $1 AND shop_id = $2 AND status IN ('trialing', 'active')
+RETURNING *;
+
+-- name: ResumeSubscription :one
+-- Resumes a paused subscription. Renewals that fell due while it was paused are
+-- not made up; the next one is due straight away.
+UPDATE subscriptions
+SET status = 'active', paused_until = NULL, next_renewal_at = GREATEST(next_renewal_at, NOW()), updated_at = NOW()
+WHERE subscription_id = $1 AND shop_id = $2 AND status = 'paused'
+RETURNING *;
+
+-- name: ResumeDueSubscriptions :execrows
+-- Resumes the subscriptions of every shop paused until now
+UPDATE subscriptions
+SET status = 'active', paused_until = NULL, next_renewal_at = GREATEST(next_renewal_at, NOW()), updated_at = NOW()
+WHERE status = 'paused' AND paused_until <= NOW();
+
+-- name: SkipSubscriptionRenewal :one
+UPDATE subscriptions
+SET next_renewal_at = $3, updated_at = NOW()
+WHERE subscription_id = $1 AND shop_id = $2 AND status IN ('trialing', 'active') AND pending_order_id IS NULL
+RETURNING *;
+
+-- name: CancelSubscription :one
+UPDATE subscriptions
+SET status = 'cancelled', cancelled_at = NOW(), cancel_reason = $3,
+    pending_order_id = NULL, next_retry_at = NULL, paused_until = NULL, updated_at = NOW()
+WHERE subscription_id = $1 AND shop_id = $2 AND status <> 'cancelled'
+RETURNING *;
+
+-- name: CancelUnpaidOrder :execrows
+-- Cancels a renewal order that was never paid
+UPDATE orders
+SET status = 'cancelled', payment_status = $3, updated_at = NOW()
+WHERE order_id = $1 AND shop_id = $2 AND payment_status <> 'paid';
+
+-- name: ClaimDueSubscriptions :many
+-- Claims up to limit subscriptions of any shop whose renewal or retry is due, for
+-- an hour, so that concurrent schedulers renew each subscription once. Claims
+-- left by a scheduler that stopped expire.
+UPDATE subscriptions
+SET claimed_until = NOW() + INTERVAL '1 hour'
+WHERE subscription_id IN (
+    SELECT s.subscription_id FROM subscriptions s
+    WHERE s.status IN ('trialing', 'active', 'past_due')
+        AND COALESCE(s.next_retry_at, s.next_renewal_at) <= NOW()
+        AND (s.claimed_until IS NULL OR s.claimed_until < NOW())
+    ORDER BY COALESCE(s.next_retry_at, s.next_renewal_at)
+    LIMIT $1
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: GetSubscriptionBilling :one
+-- What the renewal of a subscription orders and charges
+SELECT
+    sp.product_variation_id, sp.interval_unit, sp.interval_count, sp.discount_percent,
+    pv.price, sc.email AS customer_email, sc.name AS customer_name,
+    cpm.provider, cpm.customer_reference, cpm.token, sh.currency_code
+FROM subscriptions s
+JOIN subscription_plans sp ON sp.plan_id = s.plan_id
+JOIN product_variations pv ON pv.product_variation_id = sp.product_variation_id
+JOIN shop_customers sc ON sc.shop_customer_id = s.shop_customer_id
+JOIN customer_payment_methods cpm ON cpm.customer_payment_method_id = s.customer_payment_method_id
+JOIN shops sh ON sh.shop_id = s.shop_id
+WHERE s.subscription_id = $1 AND s.shop_id = $2;
+
+-- name: SetSubscriptionPendingOrder :exec
+UPDATE subscriptions
+SET pending_order_id = $3, updated_at = NOW()
+WHERE subscription_id = $1 AND shop_id = $2;
+
+-- name: CompleteSubscriptionRenewal :one
+-- Moves a subscription renewed by the scheduler on to its next renewal. A
+-- subscription paused or cancelled while it renewed is left as it is.
+UPDATE subscriptions
+SET status = 'active', next_renewal_at = $3, failed_attempts = 0, next_retry_at = NULL,
+    pending_order_id = NULL, claimed_until = NULL, updated_at = NOW()
+WHERE subscription_id = $1 AND shop_id = $2 AND status IN ('trialing', 'active', 'past_due')
+RETURNING *;
+
+-- name: FailSubscriptionRenewal :one
+-- Leaves a subscription whose renewal failed past due until its next retry
+UPDATE subscriptions
+SET status = 'past_due', failed_attempts = $3, next_retry_at = $4, claimed_until = NULL, updated_at = NOW()
+WHERE subscription_id = $1 AND shop_id = $2 AND status IN ('trialing', 'active', 'past_due')
+RETURNING *;
+
+-- name: CreateSubscriptionRenewal :one
+INSERT INTO subscription_renewals (subscription_id, order_id, status, renewal_date, payment_reference, error, shop_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: ListSubscriptionRenewals :many
+SELECT * FROM subscription_renewals
+WHERE subscription_id = $1 AND shop_id = $2
+ORDER BY created_at DESC, renewal_id DESC
+LIMIT $3 OFFSET $4;
+
+-- name: CountSubscriptionRenewals :one
+SELECT COUNT(*) FROM subscription_renewals
+WHERE subscription_id = $1 AND shop_id = $2;
+
+-- name: QueueSubscriptionNotification :execrows
+-- Queues an email about a subscription to its customer
+INSERT INTO notifications (kind, recipient, payload, shop_id)
+VALUES ($1, $2, $3, $4);
+
+-- name: QueueSubscriptionShopNotification :execrows
+-- Queues an email about a subscription to its shop
+INSERT INTO notifications (kind, recipient, payload, shop_id)
+SELECT sqlc.arg('kind')::text, s.email, sqlc.arg('payload')::jsonb, s.shop_id
+FROM shops s
+WHERE s.shop_id = sqlc.arg('shop_id');
@@ -0,0 +1,116 @@
+-- name: GetShopLocales :many
+SELECT * FROM shop_locales
+WHERE shop_id = $1
+ORDER BY is_default DESC, locale;
+
+-- name: DeleteShopLocales :exec
+DELETE FROM shop_locales
+WHERE shop_id = $1;
+
+-- name: AddShopLocale :exec
+INSERT INTO shop_locales (locale, is_default, shop_id)
+VALUES ($1, $2, $3);
+
+-- name: UpsertTranslation :one
+INSERT INTO translations (resource_type, resource_id, field, locale, value, shop_id)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (resource_type, resource_id, field, locale, shop_id)
+DO UPDATE SET value = EXCLUDED.value, updated_at = NOW()
+RETURNING *;
+
+-- name: DeleteTranslation :execrows
+DELETE FROM translations
+WHERE resource_type = $1 AND resource_id = $2 AND field = $3 AND locale = $4 AND shop_id = $5;
+
+-- name: DeleteTranslationsByLocale :execrows
+DELETE FROM translations
+WHERE resource_type = $1 AND resource_id = $2 AND locale = $3 AND shop_id = $4;
+
+-- name: GetTranslation :one
+SELECT value FROM translations
+WHERE resource_type = $1 AND resource_id = $2 AND field = $3 AND locale = $4 AND shop_id = $5;
+
+-- name: GetTranslations :many
+SELECT
+    translation_id,
+    resource_type,
+    resource_id,
+    field,
+    locale,
+    value,
+    created_at,
+    updated_at,
+    COUNT(*) OVER () AS total_count
+FROM translations
+WHERE shop_id = sqlc.arg('shop_id')
+AND (sqlc.narg('resource_type')::translatable_resource_type IS NULL OR resource_type = sqlc.narg('resource_type'))
+AND (sqlc.narg('resource_id')::bigint IS NULL OR resource_id = sqlc.narg('resource_id'))
+AND (sqlc.narg('locale')::varchar IS NULL OR locale = sqlc.narg('locale'))
+ORDER BY resource_type, resource_id, locale, field
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: TranslatableResourceExists :one
+SELECT (CASE sqlc.arg('resource_type')::translatable_resource_type
+    WHEN 'product' THEN EXISTS (
+        SELECT 1 FROM products WHERE product_id = sqlc.arg('resource_id') AND shop_id = sqlc.arg('shop_id')
+    )
+    WHEN 'product_variation' THEN EXISTS (
+        SELECT 1 FROM product_variations WHERE product_variation_id = sqlc.arg('resource_id') AND shop_id = sqlc.arg('shop_id')
+    )
+    WHEN 'category' THEN EXISTS (
+        SELECT 1 FROM categories WHERE category_id = sqlc.arg('resource_id') AND shop_id = sqlc.arg('shop_id')
+    )
+    WHEN 'shop' THEN sqlc.arg('resource_id') = sqlc.arg('shop_id')
+    WHEN 'collection' THEN EXISTS (
+        SELECT 1 FROM collections WHERE collection_id = sqlc.arg('resource_id') AND shop_id = sqlc.arg('shop_id')
+    )
+    ELSE FALSE
+END)::boolean AS resource_exists;
+
+-- name: GetMissingTranslations :many
+-- Translatable fields with a non-empty source value and no translation in the given locale
+WITH sources AS (
+    SELECT 'product'::translatable_resource_type AS resource_type, p.product_id AS resource_id, f.field, f.value
+    FROM products p
+    CROSS JOIN LATERAL (VALUES ('title', p.title::text), ('description', p.description::text)) AS f(field, value)
+    WHERE p.shop_id = sqlc.arg('shop_id')
+    UNION ALL
+    SELECT 'product_variation'::translatable_resource_type, v.product_variation_id, f.field, f.value
+    FROM product_variations v
+    CROSS JOIN LATERAL (VALUES ('description', v.description::text), ('seo_title', v.seo_title::text), ('seo_description', v.seo_description::text)) AS f(field, value)
+    WHERE v.shop_id = sqlc.arg('shop_id')
+    UNION ALL
+    SELECT 'category'::translatable_resource_type, c.category_id, f.field, f.value
+    FROM categories c
+    CROSS JOIN LATERAL (VALUES ('title', c.title::text), ('description', c.description::text)) AS f(field, value)
+    WHERE c.shop_id = sqlc.arg('shop_id')
+    UNION ALL
+    SELECT 'shop'::translatable_resource_type, s.shop_id, f.field, f.value
+    FROM shops s
+    CROSS JOIN LATERAL (VALUES ('about', s.about::text), ('seo_title', s.seo_title::text), ('seo_description', s.seo_description::text)) AS f(field, value)
+    WHERE s.shop_id = sqlc.arg('shop_id')
+    UNION ALL
+    SELECT 'collection'::translatable_resource_type, col.collection_id, f.field, f.value
+    FROM collections col
+    CROSS JOIN LATERAL (VALUES ('title', col.title::text), ('description', col.description::text)) AS f(field, value)
+    WHERE col.shop_id = sqlc.arg('shop_id')
+)
+SELECT
+    src.resource_type,
+    src.resource_id,
+    src.field::varchar AS field,
+    src.value::text AS source_value,
+    COUNT(*) OVER () AS total_count
+FROM sources src
+WHERE COALESCE(src.value, '') <> ''
+AND (sqlc.narg('resource_type')::translatable_resource_type IS NULL OR src.resource_type = sqlc.narg('resource_type'))
+AND NOT EXISTS (
+    SELECT 1 FROM translations t
+    WHERE t.resource_type = src.resource_type
+    AND t.resource_id = src.resource_id
+    AND t.field = src.field
+    AND t.locale = sqlc.arg('locale')
+    AND t.shop_id = sqlc.arg('shop_id')
+)
+ORDER BY src.resource_type, src.resource_id, src.field
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
@@ -0,0 +1,53 @@
+-- name: GetWishlistItems :many
+SELECT w.product_variation_id, pv.product_id, w.created_at
+FROM wishlist_items w
+JOIN product_variations pv ON pv.product_variation_id = w.product_variation_id
+WHERE w.shop_customer_id = $1 AND w.shop_id = $2
+ORDER BY w.created_at DESC;
+
+-- name: AddWishlistItem :exec
+INSERT INTO wishlist_items (shop_customer_id, product_variation_id, shop_id)
+VALUES ($1, $2, $3)
+ON CONFLICT (shop_customer_id, product_variation_id) DO NOTHING;
+
+-- name: RemoveWishlistItem :execrows
+DELETE FROM wishlist_items
+WHERE shop_customer_id = $1 AND product_variation_id = $2 AND shop_id = $3;
+
+-- name: UpsertBackInStockSubscription :one
+-- Subscribing again re-arms a subscription that has already been notified
+INSERT INTO back_in_stock_subscriptions (product_variation_id, email, shop_customer_id, shop_id)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (product_variation_id, email)
+DO UPDATE SET
+    notified_at = NULL,
+    shop_customer_id = COALESCE(EXCLUDED.shop_customer_id, back_in_stock_subscriptions.shop_customer_id)
+RETURNING *;
+
+-- name: DeleteBackInStockSubscription :execrows
+DELETE FROM back_in_stock_subscriptions
+WHERE product_variation_id = $1 AND email = $2 AND shop_id = $3;
+
+-- name: QueueBackInStockNotifications :execrows
+-- Queues a notification for every subscriber of a variant that has not been
+-- notified yet and marks their subscriptions as notified
+WITH subscribers AS (
+    UPDATE back_in_stock_subscriptions s
+    SET notified_at = NOW()
+    WHERE s.product_variation_id = sqlc.arg('product_variation_id') AND s.shop_id = sqlc.arg('shop_id') AND s.notified_at IS NULL
+    RETURNING s.email, s.product_variation_id, s.shop_id
+)
+INSERT INTO notifications (kind, recipient, payload, shop_id)
+SELECT
+    'back_in_stock',
+    sub.email,
+    jsonb_build_object(
+        'product_variation_id', sub.product_variation_id,
+        'product_id', p.product_id,
+        'product_title', p.title,
+        'product_slug', p.slug
+    ),
+    sub.shop_id
+FROM subscribers sub
+JOIN product_variations pv ON pv.product_variation_id = sub.product_variation_id
+JOIN products p ON p.product_id = pv.product_id;
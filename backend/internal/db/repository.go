@@ -7,6 +7,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/jackc/pgx/v5/tracelog"
 	"go.uber.org/zap"
@@ -58,18 +59,217 @@ type Repository interface {
 	GetCustomersCount(ctx context.Context, shopID int64) (int64, error)
 	SearchCustomers(ctx context.Context, arg SearchCustomersParams) ([]ShopCustomer, error)
 	GetCustomerById(ctx context.Context, arg GetCustomerByIdParams) (ShopCustomer, error)
+	GetShopCustomerByEmail(ctx context.Context, arg GetShopCustomerByEmailParams) (ShopCustomer, error)
 	UpdateCustomer(ctx context.Context, arg UpdateCustomerParams) (ShopCustomer, error)
 	DeleteCustomer(ctx context.Context, arg DeleteCustomerParams) error
 	GetCustomerOrders(ctx context.Context, arg GetCustomerOrdersParams) ([]GetCustomerOrdersRow, error)
+	// Customer Addresses
+	ListCustomerAddresses(ctx context.Context, arg ListCustomerAddressesParams) ([]CustomerAddress, error)
+	GetCustomerAddress(ctx context.Context, arg GetCustomerAddressParams) (CustomerAddress, error)
+	CreateCustomerAddress(ctx context.Context, arg CreateCustomerAddressParams) (CustomerAddress, error)
+	UpdateCustomerAddress(ctx context.Context, arg UpdateCustomerAddressParams) (CustomerAddress, error)
+	DeleteCustomerAddress(ctx context.Context, arg DeleteCustomerAddressParams) (int64, error)
+	ClearCustomerDefaultAddresses(ctx context.Context, arg ClearCustomerDefaultAddressesParams) error
+	SetCustomerDefaultAddress(ctx context.Context, arg SetCustomerDefaultAddressParams) (CustomerAddress, error)
+	// Customer Account
+	ListCustomerOrders(ctx context.Context, arg ListCustomerOrdersParams) ([]Order, error)
+	CountCustomerOrders(ctx context.Context, arg CountCustomerOrdersParams) (int64, error)
+	GetCustomerOrder(ctx context.Context, arg GetCustomerOrderParams) (Order, error)
+	// Customer Groups & Segments
+	ListCustomerGroups(ctx context.Context, shopID int64) ([]ListCustomerGroupsRow, error)
+	GetCustomerGroup(ctx context.Context, arg GetCustomerGroupParams) (CustomerGroup, error)
+	CreateCustomerGroup(ctx context.Context, arg CreateCustomerGroupParams) (CustomerGroup, error)
+	UpdateCustomerGroup(ctx context.Context, arg UpdateCustomerGroupParams) (CustomerGroup, error)
+	DeleteCustomerGroup(ctx context.Context, arg DeleteCustomerGroupParams) (int64, error)
+	ListCustomerGroupMembers(ctx context.Context, arg ListCustomerGroupMembersParams) ([]ShopCustomer, error)
+	AddCustomerGroupMember(ctx context.Context, arg AddCustomerGroupMemberParams) error
+	RemoveCustomerGroupMember(ctx context.Context, arg RemoveCustomerGroupMemberParams) (int64, error)
+	ListCustomerGroupPrices(ctx context.Context, arg ListCustomerGroupPricesParams) ([]CustomerGroupPrice, error)
+	UpsertCustomerGroupPrice(ctx context.Context, arg UpsertCustomerGroupPriceParams) (CustomerGroupPrice, error)
+	DeleteCustomerGroupPrice(ctx context.Context, arg DeleteCustomerGroupPriceParams) (int64, error)
+	GetCustomerVariantPrices(ctx context.Context, arg GetCustomerVariantPricesParams) ([]GetCustomerVariantPricesRow, error)
+	ListCustomerSegments(ctx context.Context, shopID int64) ([]CustomerSegment, error)
+	GetCustomerSegment(ctx context.Context, arg GetCustomerSegmentParams) (CustomerSegment, error)
+	CreateCustomerSegment(ctx context.Context, arg CreateCustomerSegmentParams) (CustomerSegment, error)
+	UpdateCustomerSegment(ctx context.Context, arg UpdateCustomerSegmentParams) (CustomerSegment, error)
+	DeleteCustomerSegment(ctx context.Context, arg DeleteCustomerSegmentParams) (int64, error)
+	ListCustomerSegmentMembers(ctx context.Context, arg ListCustomerSegmentMembersParams) ([]ListCustomerSegmentMembersRow, error)
+	// Customer Data Requests
+	ExportCustomerOrders(ctx context.Context, arg ExportCustomerOrdersParams) ([]Order, error)
+	ExportCustomerReviews(ctx context.Context, arg ExportCustomerReviewsParams) ([]ProductReview, error)
+	AnonymizeCustomerReviews(ctx context.Context, arg AnonymizeCustomerReviewsParams) (int64, error)
+	AnonymizeCustomerOrders(ctx context.Context, arg AnonymizeCustomerOrdersParams) (int64, error)
+	DeleteCustomerBackInStockSubscriptions(ctx context.Context, arg DeleteCustomerBackInStockSubscriptionsParams) (int64, error)
+	DeleteCustomerNotifications(ctx context.Context, arg DeleteCustomerNotificationsParams) (int64, error)
+	CreateCustomerDataRequest(ctx context.Context, arg CreateCustomerDataRequestParams) (CustomerDataRequest, error)
+	ListCustomerDataRequests(ctx context.Context, arg ListCustomerDataRequestsParams) ([]CustomerDataRequest, error)
+	CountCustomerDataRequests(ctx context.Context, shopID int64) (int64, error)
+	// Gift Cards & Store Credit
+	ListGiftCards(ctx context.Context, arg ListGiftCardsParams) ([]GiftCard, error)
+	CountGiftCards(ctx context.Context, arg CountGiftCardsParams) (int64, error)
+	GetGiftCard(ctx context.Context, arg GetGiftCardParams) (GiftCard, error)
+	GetGiftCardByCode(ctx context.Context, arg GetGiftCardByCodeParams) (GiftCard, error)
+	CreateGiftCard(ctx context.Context, arg CreateGiftCardParams) (GiftCard, error)
+	UpdateGiftCard(ctx context.Context, arg UpdateGiftCardParams) (GiftCard, error)
+	RedeemGiftCard(ctx context.Context, arg RedeemGiftCardParams) (GiftCard, error)
+	CreditGiftCard(ctx context.Context, arg CreditGiftCardParams) (GiftCard, error)
+	CreateGiftCardTransaction(ctx context.Context, arg CreateGiftCardTransactionParams) (GiftCardTransaction, error)
+	ListGiftCardTransactions(ctx context.Context, arg ListGiftCardTransactionsParams) ([]GiftCardTransaction, error)
+	ListOrderGiftCardRedemptions(ctx context.Context, arg ListOrderGiftCardRedemptionsParams) ([]ListOrderGiftCardRedemptionsRow, error)
+	ListOrderGiftCardItems(ctx context.Context, arg ListOrderGiftCardItemsParams) ([]ListOrderGiftCardItemsRow, error)
+	CountOrderGiftCards(ctx context.Context, arg CountOrderGiftCardsParams) (int64, error)
+	QueueGiftCardNotification(ctx context.Context, arg QueueGiftCardNotificationParams) (int64, error)
+	LockCustomerStoreCredit(ctx context.Context, arg LockCustomerStoreCreditParams) (uuid.UUID, error)
+	GetStoreCreditBalance(ctx context.Context, arg GetStoreCreditBalanceParams) (pgtype.Numeric, error)
+	CreateStoreCreditTransaction(ctx context.Context, arg CreateStoreCreditTransactionParams) (StoreCreditTransaction, error)
+	ListStoreCreditTransactions(ctx context.Context, arg ListStoreCreditTransactionsParams) ([]StoreCreditTransaction, error)
+	CountStoreCreditTransactions(ctx context.Context, arg CountStoreCreditTransactionsParams) (int64, error)
+	ListOrderStoreCreditRedemptions(ctx context.Context, arg ListOrderStoreCreditRedemptionsParams) ([]ListOrderStoreCreditRedemptionsRow, error)
+	// Loyalty Points
+	GetLoyaltyProgram(ctx context.Context, shopID int64) (LoyaltyProgram, error)
+	UpsertLoyaltyProgram(ctx context.Context, arg UpsertLoyaltyProgramParams) (LoyaltyProgram, error)
+	LockCustomerLoyaltyPoints(ctx context.Context, arg LockCustomerLoyaltyPointsParams) (uuid.UUID, error)
+	GetLoyaltyBalance(ctx context.Context, arg GetLoyaltyBalanceParams) (GetLoyaltyBalanceRow, error)
+	CreateLoyaltyTransaction(ctx context.Context, arg CreateLoyaltyTransactionParams) (LoyaltyTransaction, error)
+	ListLoyaltyTransactions(ctx context.Context, arg ListLoyaltyTransactionsParams) ([]LoyaltyTransaction, error)
+	CountLoyaltyTransactions(ctx context.Context, arg CountLoyaltyTransactionsParams) (int64, error)
+	ListOrderLoyaltyPoints(ctx context.Context, arg ListOrderLoyaltyPointsParams) ([]ListOrderLoyaltyPointsRow, error)
 	// Inventory Management
 	GetLowStockVariants(ctx context.Context, arg GetLowStockVariantsParams) ([]GetLowStockVariantsRow, error)
 	GetProductVariation(ctx context.Context, arg GetProductVariationParams) (ProductVariation, error)
-	UpdateVariantStock(ctx context.Context, arg UpdateVariantStockParams) (ProductVariation, error)
-	DeductVariantStock(ctx context.Context, arg DeductVariantStockParams) (ProductVariation, error)
-	AddVariantStock(ctx context.Context, arg AddVariantStockParams) (ProductVariation, error)
+	UpdateVariantStock(ctx context.Context, arg UpdateVariantStockParams) (InventoryLevel, error)
+	DeductVariantStock(ctx context.Context, arg DeductVariantStockParams) (InventoryLevel, error)
+	AddVariantStock(ctx context.Context, arg AddVariantStockParams) (InventoryLevel, error)
 	GetInventoryReport(ctx context.Context, arg GetInventoryReportParams) ([]GetInventoryReportRow, error)
 	GetStockMovements(ctx context.Context, arg GetStockMovementsParams) ([]GetStockMovementsRow, error)
 	CreateStockMovement(ctx context.Context, arg CreateStockMovementParams) (StockMovement, error)
+	// Locations
+	CreateLocation(ctx context.Context, arg CreateLocationParams) (Location, error)
+	EnsureDefaultLocation(ctx context.Context, shopID int64) error
+	GetDefaultLocation(ctx context.Context, shopID int64) (Location, error)
+	GetLocation(ctx context.Context, arg GetLocationParams) (Location, error)
+	ListLocations(ctx context.Context, shopID int64) ([]Location, error)
+	UpdateLocation(ctx context.Context, arg UpdateLocationParams) (Location, error)
+	ClearDefaultLocation(ctx context.Context, shopID int64) error
+	MarkDefaultLocation(ctx context.Context, arg MarkDefaultLocationParams) (Location, error)
+	DeleteLocation(ctx context.Context, arg DeleteLocationParams) (int64, error)
+	EnsureInventoryLevel(ctx context.Context, arg EnsureInventoryLevelParams) (InventoryLevel, error)
+	ListVariantInventoryLevels(ctx context.Context, arg ListVariantInventoryLevelsParams) ([]ListVariantInventoryLevelsRow, error)
+	ListLocationInventoryLevels(ctx context.Context, arg ListLocationInventoryLevelsParams) ([]ListLocationInventoryLevelsRow, error)
+	CountLocationInventoryLevels(ctx context.Context, arg CountLocationInventoryLevelsParams) (int64, error)
+	AddIncomingStock(ctx context.Context, arg AddIncomingStockParams) (InventoryLevel, error)
+	DeductIncomingStock(ctx context.Context, arg DeductIncomingStockParams) (InventoryLevel, error)
+	SyncVariantAvailableQuantity(ctx context.Context, arg SyncVariantAvailableQuantityParams) (ProductVariation, error)
+	SyncProductAvailableQuantities(ctx context.Context, arg SyncProductAvailableQuantitiesParams) error
+	SyncShopAvailableQuantities(ctx context.Context, shopID int64) error
+	ReconcileProductInventoryLevels(ctx context.Context, arg ReconcileProductInventoryLevelsParams) error
+	// Inventory Transfers
+	CreateInventoryTransfer(ctx context.Context, arg CreateInventoryTransferParams) (InventoryTransfer, error)
+	CreateInventoryTransferItem(ctx context.Context, arg CreateInventoryTransferItemParams) (InventoryTransferItem, error)
+	GetInventoryTransfer(ctx context.Context, arg GetInventoryTransferParams) (InventoryTransfer, error)
+	ListInventoryTransfers(ctx context.Context, arg ListInventoryTransfersParams) ([]InventoryTransfer, error)
+	CountInventoryTransfers(ctx context.Context, shopID int64) (int64, error)
+	ListInventoryTransferItems(ctx context.Context, arg ListInventoryTransferItemsParams) ([]ListInventoryTransferItemsRow, error)
+	ReceiveInventoryTransfer(ctx context.Context, arg ReceiveInventoryTransferParams) (InventoryTransfer, error)
+	CancelInventoryTransfer(ctx context.Context, arg CancelInventoryTransferParams) (InventoryTransfer, error)
+	// Order Fulfillment
+	ListFulfillmentLocations(ctx context.Context, arg ListFulfillmentLocationsParams) ([]Location, error)
+	CreateOrderFulfillment(ctx context.Context, arg CreateOrderFulfillmentParams) (OrderFulfillment, error)
+	GetOrderFulfillment(ctx context.Context, arg GetOrderFulfillmentParams) (OrderFulfillment, error)
+	// Suppliers
+	CreateSupplier(ctx context.Context, arg CreateSupplierParams) (Supplier, error)
+	GetSupplier(ctx context.Context, arg GetSupplierParams) (Supplier, error)
+	ListSuppliers(ctx context.Context, arg ListSuppliersParams) ([]Supplier, error)
+	CountSuppliers(ctx context.Context, shopID int64) (int64, error)
+	UpdateSupplier(ctx context.Context, arg UpdateSupplierParams) (Supplier, error)
+	DeleteSupplier(ctx context.Context, arg DeleteSupplierParams) (int64, error)
+	// Purchase Orders
+	CreatePurchaseOrder(ctx context.Context, arg CreatePurchaseOrderParams) (PurchaseOrder, error)
+	GetPurchaseOrder(ctx context.Context, arg GetPurchaseOrderParams) (PurchaseOrder, error)
+	ListPurchaseOrders(ctx context.Context, arg ListPurchaseOrdersParams) ([]PurchaseOrder, error)
+	CountPurchaseOrders(ctx context.Context, arg CountPurchaseOrdersParams) (int64, error)
+	UpdatePurchaseOrder(ctx context.Context, arg UpdatePurchaseOrderParams) (PurchaseOrder, error)
+	MarkPurchaseOrderOrdered(ctx context.Context, arg MarkPurchaseOrderOrderedParams) (PurchaseOrder, error)
+	SyncPurchaseOrderStatus(ctx context.Context, arg SyncPurchaseOrderStatusParams) (PurchaseOrder, error)
+	DeletePurchaseOrder(ctx context.Context, arg DeletePurchaseOrderParams) (int64, error)
+	CreatePurchaseOrderItem(ctx context.Context, arg CreatePurchaseOrderItemParams) (PurchaseOrderItem, error)
+	DeletePurchaseOrderItems(ctx context.Context, arg DeletePurchaseOrderItemsParams) error
+	ListPurchaseOrderItems(ctx context.Context, arg ListPurchaseOrderItemsParams) ([]ListPurchaseOrderItemsRow, error)
+	ReceivePurchaseOrderItem(ctx context.Context, arg ReceivePurchaseOrderItemParams) (PurchaseOrderItem, error)
+	GetVariantStockOnHand(ctx context.Context, arg GetVariantStockOnHandParams) (int64, error)
+	AverageVariantCostPrice(ctx context.Context, arg AverageVariantCostPriceParams) (ProductVariation, error)
+	SetVariantCostPrice(ctx context.Context, arg SetVariantCostPriceParams) (ProductVariation, error)
+	// Stocktakes
+	CreateStocktake(ctx context.Context, arg CreateStocktakeParams) (Stocktake, error)
+	AddStocktakeItems(ctx context.Context, arg AddStocktakeItemsParams) (int64, error)
+	GetStocktake(ctx context.Context, arg GetStocktakeParams) (Stocktake, error)
+	ListStocktakes(ctx context.Context, arg ListStocktakesParams) ([]Stocktake, error)
+	CountStocktakes(ctx context.Context, arg CountStocktakesParams) (int64, error)
+	CommitStocktake(ctx context.Context, arg CommitStocktakeParams) (Stocktake, error)
+	CancelStocktake(ctx context.Context, arg CancelStocktakeParams) (Stocktake, error)
+	SetStocktakeCount(ctx context.Context, arg SetStocktakeCountParams) (StocktakeItem, error)
+	AddStocktakeCount(ctx context.Context, arg AddStocktakeCountParams) (StocktakeItem, error)
+	ListStocktakeItems(ctx context.Context, arg ListStocktakeItemsParams) ([]ListStocktakeItemsRow, error)
+	CountStocktakeItems(ctx context.Context, arg CountStocktakeItemsParams) (int64, error)
+	ListStocktakeVariances(ctx context.Context, arg ListStocktakeVariancesParams) ([]ListStocktakeVariancesRow, error)
+	GetStocktakeSummary(ctx context.Context, arg GetStocktakeSummaryParams) (GetStocktakeSummaryRow, error)
+	// Inventory lots
+	GetInventoryLot(ctx context.Context, arg GetInventoryLotParams) (InventoryLot, error)
+	ListVariantLots(ctx context.Context, arg ListVariantLotsParams) ([]ListVariantLotsRow, error)
+	ListExpiringLots(ctx context.Context, arg ListExpiringLotsParams) ([]ListExpiringLotsRow, error)
+	// Inventory policies
+	SetVariantInventoryPolicy(ctx context.Context, arg SetVariantInventoryPolicyParams) (ProductVariation, error)
+	GetVariantBackorderedQuantity(ctx context.Context, arg GetVariantBackorderedQuantityParams) (int64, error)
+	ListBackorderedOrderItems(ctx context.Context, arg ListBackorderedOrderItemsParams) ([]ListBackorderedOrderItemsRow, error)
+	CountBackorderedOrderItems(ctx context.Context, shopID int64) (int64, error)
+	// Stock alerts
+	GetVariantReorderPoint(ctx context.Context, arg GetVariantReorderPointParams) (GetVariantReorderPointRow, error)
+	SetVariantReorderPoint(ctx context.Context, arg SetVariantReorderPointParams) (ProductVariation, error)
+	ListStockAlerts(ctx context.Context, arg ListStockAlertsParams) ([]ListStockAlertsRow, error)
+	CountStockAlerts(ctx context.Context, arg CountStockAlertsParams) (int64, error)
+	MarkStockAlertRead(ctx context.Context, arg MarkStockAlertReadParams) (StockAlert, error)
+	MarkAllStockAlertsRead(ctx context.Context, shopID int64) (int64, error)
+	ListReorderVariants(ctx context.Context, shopID int64) ([]ListReorderVariantsRow, error)
+	// Bundles
+	ListBundleComponents(ctx context.Context, arg ListBundleComponentsParams) ([]ListBundleComponentsRow, error)
+	// Digital delivery
+	ListDigitalAssets(ctx context.Context, arg ListDigitalAssetsParams) ([]ListDigitalAssetsRow, error)
+	GetDigitalAsset(ctx context.Context, arg GetDigitalAssetParams) (DigitalAsset, error)
+	CreateDigitalAsset(ctx context.Context, arg CreateDigitalAssetParams) (DigitalAsset, error)
+	UpdateDigitalAsset(ctx context.Context, arg UpdateDigitalAssetParams) (DigitalAsset, error)
+	DeleteDigitalAsset(ctx context.Context, arg DeleteDigitalAssetParams) (DigitalAsset, error)
+	VariantIsDigital(ctx context.Context, arg VariantIsDigitalParams) (bool, error)
+	CountAvailableLicenseKeys(ctx context.Context, arg CountAvailableLicenseKeysParams) (int64, error)
+	ListOrderDigitalDownloads(ctx context.Context, arg ListOrderDigitalDownloadsParams) ([]ListOrderDigitalDownloadsRow, error)
+	GetDigitalDownloadFile(ctx context.Context, arg GetDigitalDownloadFileParams) (GetDigitalDownloadFileRow, error)
+	ListDigitalDownloadEvents(ctx context.Context, arg ListDigitalDownloadEventsParams) ([]DigitalDownloadEvent, error)
+	CountDigitalDownloadEvents(ctx context.Context, arg CountDigitalDownloadEventsParams) (int64, error)
+	// Subscriptions
+	ListSubscriptionPlans(ctx context.Context, arg ListSubscriptionPlansParams) ([]SubscriptionPlan, error)
+	ListActiveSubscriptionPlans(ctx context.Context, arg ListActiveSubscriptionPlansParams) ([]SubscriptionPlan, error)
+	GetSubscriptionPlan(ctx context.Context, arg GetSubscriptionPlanParams) (SubscriptionPlan, error)
+	CreateSubscriptionPlan(ctx context.Context, arg CreateSubscriptionPlanParams) (SubscriptionPlan, error)
+	UpdateSubscriptionPlan(ctx context.Context, arg UpdateSubscriptionPlanParams) (SubscriptionPlan, error)
+	DeleteSubscriptionPlan(ctx context.Context, arg DeleteSubscriptionPlanParams) (SubscriptionPlan, error)
+	ListCustomerPaymentMethods(ctx context.Context, arg ListCustomerPaymentMethodsParams) ([]CustomerPaymentMethod, error)
+	GetCustomerPaymentMethod(ctx context.Context, arg GetCustomerPaymentMethodParams) (CustomerPaymentMethod, error)
+	CreateCustomerPaymentMethod(ctx context.Context, arg CreateCustomerPaymentMethodParams) (CustomerPaymentMethod, error)
+	DeleteCustomerPaymentMethod(ctx context.Context, arg DeleteCustomerPaymentMethodParams) (int64, error)
+	CreateSubscription(ctx context.Context, arg CreateSubscriptionParams) (Subscription, error)
+	GetSubscription(ctx context.Context, arg GetSubscriptionParams) (GetSubscriptionRow, error)
+	GetCustomerSubscription(ctx context.Context, arg GetCustomerSubscriptionParams) (GetCustomerSubscriptionRow, error)
+	ListSubscriptions(ctx context.Context, arg ListSubscriptionsParams) ([]ListSubscriptionsRow, error)
+	CountSubscriptions(ctx context.Context, arg CountSubscriptionsParams) (int64, error)
+	ListCustomerSubscriptions(ctx context.Context, arg ListCustomerSubscriptionsParams) ([]ListCustomerSubscriptionsRow, error)
+	UpdateSubscription(ctx context.Context, arg UpdateSubscriptionParams) (Subscription, error)
+	PauseSubscription(ctx context.Context, arg PauseSubscriptionParams) (Subscription, error)
+	ResumeSubscription(ctx context.Context, arg ResumeSubscriptionParams) (Subscription, error)
+	ResumeDueSubscriptions(ctx context.Context) (int64, error)
+	SkipSubscriptionRenewal(ctx context.Context, arg SkipSubscriptionRenewalParams) (Subscription, error)
+	ClaimDueSubscriptions(ctx context.Context, limit int32) ([]Subscription, error)
+	GetSubscriptionBilling(ctx context.Context, arg GetSubscriptionBillingParams) (GetSubscriptionBillingRow, error)
+	ListSubscriptionRenewals(ctx context.Context, arg ListSubscriptionRenewalsParams) ([]SubscriptionRenewal, error)
+	CountSubscriptionRenewals(ctx context.Context, arg CountSubscriptionRenewalsParams) (int64, error)
 	// SHOP
 	CreateShop(ctx context.Context, shopArg CreateShopParams) (Shop, error)
 	GetShop(ctx context.Context, shopID int64) (Shop, error)
@@ -104,6 +304,7 @@ type Repository interface {
 	CreateCategory(ctx context.Context, arg CreateCategoryParams) (Category, error)
 	GetCategory(ctx context.Context, arg GetCategoryParams) (GetCategoryRow, error)
 	UpdateCategory(ctx context.Context, arg UpdateCategoryParams) (Category, error)
+	UpdateCategoryBanner(ctx context.Context, arg UpdateCategoryBannerParams) (Category, error)
 	GetCategoryChildren(ctx context.Context, arg GetCategoryChildrenParams) ([]GetCategoryChildrenRow, error)
 	GetCategories(ctx context.Context, arg GetCategoriesParams) ([]GetCategoriesRow, error)
 	// CreateCategoryAttribute(ctx context.Context, arg CreateCategoryAttributeParams) ([]byte, error)
@@ -122,11 +323,71 @@ type Repository interface {
 	// CreateProductAllowedAttribute(ctx context.Context, arg CreateProductAllowedAttributeParams) ([]byte, error)
 	// DeleteProductAllowedAttribute(ctx context.Context, arg DeleteProductAllowedAttributeParams) ([]byte, error)
 	UpsertProductVariants(ctx context.Context, arg []UpsertProductVariantsParams) *UpsertProductVariantsBatchResults
+	// PRODUCT REVISIONS
+	GetProductRevisions(ctx context.Context, arg GetProductRevisionsParams) ([]GetProductRevisionsRow, error)
+	GetProductRevision(ctx context.Context, arg GetProductRevisionParams) (ProductRevision, error)
+	CountProductRevisions(ctx context.Context, arg CountProductRevisionsParams) (int64, error)
 	// PRODUCT IMAGES
 	CreateProductImage(ctx context.Context, arg CreateProductImageParams) (ProductImage, error)
 	GetProductImages(ctx context.Context, arg GetProductImagesParams) ([]ProductImage, error)
 	DeleteProductImage(ctx context.Context, arg DeleteProductImageParams) error
 	DeleteAllProductImages(ctx context.Context, arg DeleteAllProductImagesParams) error
+	// IMAGE ASSETS
+	CreateImageAsset(ctx context.Context, arg CreateImageAssetParams) (ImageAsset, error)
+	GetImageAssetsByURLs(ctx context.Context, arg GetImageAssetsByURLsParams) ([]ImageAsset, error)
+	// COLLECTIONS
+	CreateCollection(ctx context.Context, arg CreateCollectionParams) (Collection, error)
+	GetCollection(ctx context.Context, arg GetCollectionParams) (Collection, error)
+	GetCollectionByHandle(ctx context.Context, arg GetCollectionByHandleParams) (Collection, error)
+	GetCollections(ctx context.Context, arg GetCollectionsParams) ([]Collection, error)
+	ListCollections(ctx context.Context, arg ListCollectionsParams) ([]Collection, error)
+	CountCollections(ctx context.Context, shopID int64) (int64, error)
+	UpdateCollection(ctx context.Context, arg UpdateCollectionParams) (Collection, error)
+	DeleteCollection(ctx context.Context, arg DeleteCollectionParams) (int64, error)
+	AddCollectionProduct(ctx context.Context, arg AddCollectionProductParams) (CollectionProduct, error)
+	RemoveCollectionProduct(ctx context.Context, arg RemoveCollectionProductParams) (int64, error)
+	ReorderCollectionProducts(ctx context.Context, arg ReorderCollectionProductsParams) error
+	GetCollectionProducts(ctx context.Context, arg GetCollectionProductsParams) ([]GetCollectionProductsRow, error)
+	// PRODUCT TAGS
+	GetProductTags(ctx context.Context, arg GetProductTagsParams) ([]string, error)
+	AddProductTags(ctx context.Context, arg AddProductTagsParams) error
+	DeleteProductTags(ctx context.Context, arg DeleteProductTagsParams) error
+	GetShopTags(ctx context.Context, shopID int64) ([]GetShopTagsRow, error)
+	// METAFIELDS
+	CreateMetafieldDefinition(ctx context.Context, arg CreateMetafieldDefinitionParams) (MetafieldDefinition, error)
+	GetMetafieldDefinition(ctx context.Context, arg GetMetafieldDefinitionParams) (MetafieldDefinition, error)
+	GetMetafieldDefinitionByKey(ctx context.Context, arg GetMetafieldDefinitionByKeyParams) (MetafieldDefinition, error)
+	ListMetafieldDefinitions(ctx context.Context, arg ListMetafieldDefinitionsParams) ([]MetafieldDefinition, error)
+	UpdateMetafieldDefinition(ctx context.Context, arg UpdateMetafieldDefinitionParams) (MetafieldDefinition, error)
+	DeleteMetafieldDefinition(ctx context.Context, arg DeleteMetafieldDefinitionParams) (int64, error)
+	CountMetafieldsWithOtherType(ctx context.Context, arg CountMetafieldsWithOtherTypeParams) (int64, error)
+	MetafieldOwnerExists(ctx context.Context, arg MetafieldOwnerExistsParams) (bool, error)
+	UpsertMetafield(ctx context.Context, arg UpsertMetafieldParams) (Metafield, error)
+	GetMetafield(ctx context.Context, arg GetMetafieldParams) (Metafield, error)
+	GetMetafields(ctx context.Context, arg GetMetafieldsParams) ([]Metafield, error)
+	DeleteMetafield(ctx context.Context, arg DeleteMetafieldParams) (int64, error)
+	// TRANSLATIONS
+	GetShopLocales(ctx context.Context, shopID int64) ([]ShopLocale, error)
+	DeleteShopLocales(ctx context.Context, shopID int64) error
+	AddShopLocale(ctx context.Context, arg AddShopLocaleParams) error
+	UpsertTranslation(ctx context.Context, arg UpsertTranslationParams) (Translation, error)
+	DeleteTranslation(ctx context.Context, arg DeleteTranslationParams) (int64, error)
+	DeleteTranslationsByLocale(ctx context.Context, arg DeleteTranslationsByLocaleParams) (int64, error)
+	GetTranslation(ctx context.Context, arg GetTranslationParams) (string, error)
+	GetTranslations(ctx context.Context, arg GetTranslationsParams) ([]GetTranslationsRow, error)
+	TranslatableResourceExists(ctx context.Context, arg TranslatableResourceExistsParams) (bool, error)
+	GetMissingTranslations(ctx context.Context, arg GetMissingTranslationsParams) ([]GetMissingTranslationsRow, error)
+	// CURRENCIES
+	GetShopCurrencies(ctx context.Context, shopID int64) ([]ShopCurrency, error)
+	GetShopCurrency(ctx context.Context, arg GetShopCurrencyParams) (ShopCurrency, error)
+	UpsertShopCurrency(ctx context.Context, arg UpsertShopCurrencyParams) (ShopCurrency, error)
+	DeleteShopCurrency(ctx context.Context, arg DeleteShopCurrencyParams) (int64, error)
+	GetProductVariationPrices(ctx context.Context, arg GetProductVariationPricesParams) ([]ProductVariationPrice, error)
+	GetVariationPricesForCurrency(ctx context.Context, arg GetVariationPricesForCurrencyParams) ([]ProductVariationPrice, error)
+	UpsertProductVariationPrice(ctx context.Context, arg UpsertProductVariationPriceParams) (ProductVariationPrice, error)
+	DeleteProductVariationPrice(ctx context.Context, arg DeleteProductVariationPriceParams) (int64, error)
+	UpsertOrderPresentment(ctx context.Context, arg UpsertOrderPresentmentParams) (OrderPresentment, error)
+	GetOrderPresentment(ctx context.Context, arg GetOrderPresentmentParams) (OrderPresentment, error)
 	// ORDER
 	CreateOrder(ctx context.Context, arg CreateOrderParams) (Order, error)
 	GetOrder(ctx context.Context, arg GetOrderParams) (Order, error)
@@ -140,6 +401,25 @@ type Repository interface {
 	DeleteOrderItem(ctx context.Context, arg DeleteOrderItemParams) error
 	DeleteOrderItemsByOrder(ctx context.Context, arg DeleteOrderItemsByOrderParams) error
 	CountOrders(ctx context.Context, shopID int64) (int64, error)
+	// REVIEWS
+	GetReviewableOrderItem(ctx context.Context, arg GetReviewableOrderItemParams) (GetReviewableOrderItemRow, error)
+	CreateProductReview(ctx context.Context, arg CreateProductReviewParams) (ProductReview, error)
+	GetProductReview(ctx context.Context, arg GetProductReviewParams) (ProductReview, error)
+	ListProductReviews(ctx context.Context, arg ListProductReviewsParams) ([]ProductReview, error)
+	CountProductReviews(ctx context.Context, arg CountProductReviewsParams) (int64, error)
+	GetApprovedProductReviews(ctx context.Context, arg GetApprovedProductReviewsParams) ([]ProductReview, error)
+	UpdateProductReviewStatus(ctx context.Context, arg UpdateProductReviewStatusParams) (ProductReview, error)
+	ReplyToProductReview(ctx context.Context, arg ReplyToProductReviewParams) (ProductReview, error)
+	RefreshProductRating(ctx context.Context, arg RefreshProductRatingParams) (ProductRating, error)
+	GetProductRating(ctx context.Context, arg GetProductRatingParams) (ProductRating, error)
+	// WISHLISTS
+	GetWishlistItems(ctx context.Context, arg GetWishlistItemsParams) ([]GetWishlistItemsRow, error)
+	AddWishlistItem(ctx context.Context, arg AddWishlistItemParams) error
+	RemoveWishlistItem(ctx context.Context, arg RemoveWishlistItemParams) (int64, error)
+	// BACK IN STOCK
+	UpsertBackInStockSubscription(ctx context.Context, arg UpsertBackInStockSubscriptionParams) (BackInStockSubscription, error)
+	DeleteBackInStockSubscription(ctx context.Context, arg DeleteBackInStockSubscriptionParams) (int64, error)
+	QueueBackInStockNotifications(ctx context.Context, arg QueueBackInStockNotificationsParams) (int64, error)
 	// PAYMENT STATUS MANAGEMENT
 	UpdateOrderPaymentStatus(ctx context.Context, arg UpdateOrderPaymentStatusParams) (Order, error)
 	GetOrderByTransactionID(ctx context.Context, arg GetOrderByTransactionIDParams) (Order, error)
@@ -173,6 +453,10 @@ type Repository interface {
 	GetSalesSummary(ctx context.Context, arg GetSalesSummaryParams) (GetSalesSummaryRow, error)
 	GetOrdersOverTime(ctx context.Context, arg GetOrdersOverTimeParams) ([]GetOrdersOverTimeRow, error)
 	GetTopProducts(ctx context.Context, arg GetTopProductsParams) ([]GetTopProductsRow, error)
+	GetProfitSummary(ctx context.Context, arg GetProfitSummaryParams) (GetProfitSummaryRow, error)
+	GetProfitByProduct(ctx context.Context, arg GetProfitByProductParams) ([]GetProfitByProductRow, error)
+	GetProfitByCategory(ctx context.Context, arg GetProfitByCategoryParams) ([]GetProfitByCategoryRow, error)
+	GetProfitOverTime(ctx context.Context, arg GetProfitOverTimeParams) ([]GetProfitOverTimeRow, error)
 	GetCustomerSummaryNewReturning(ctx context.Context, arg GetCustomerSummaryNewReturningParams) (GetCustomerSummaryNewReturningRow, error)
 	GetCustomerSummaryTop(ctx context.Context, arg GetCustomerSummaryTopParams) ([]GetCustomerSummaryTopRow, error)
 	GetLowStockProducts(ctx context.Context, arg GetLowStockProductsParams) ([]GetLowStockProductsRow, error)
@@ -284,6 +568,62 @@ func (r *repoSvc) CreateCategory(ctx context.Context, arg CreateCategoryParams)
 	return category, err
 }
 
+// CreateCustomerAddress clears the customer's current defaults for the flags set
+// on the new address in the same transaction, so a new default replaces the old one
+func (r *repoSvc) CreateCustomerAddress(ctx context.Context, arg CreateCustomerAddressParams) (CustomerAddress, error) {
+	address := CustomerAddress{}
+	err := r.WithTx(ctx, func(q *Queries) error {
+		err := q.ClearCustomerDefaultAddresses(ctx, ClearCustomerDefaultAddressesParams{
+			ClearShipping:  arg.IsDefaultShipping,
+			ClearBilling:   arg.IsDefaultBilling,
+			ShopCustomerID: arg.ShopCustomerID,
+			ShopID:         arg.ShopID,
+		})
+		if err != nil {
+			return err
+		}
+		address, err = q.CreateCustomerAddress(ctx, arg)
+		return err
+	})
+	return address, err
+}
+
+func (r *repoSvc) UpdateCustomerAddress(ctx context.Context, arg UpdateCustomerAddressParams) (CustomerAddress, error) {
+	address := CustomerAddress{}
+	err := r.WithTx(ctx, func(q *Queries) error {
+		err := q.ClearCustomerDefaultAddresses(ctx, ClearCustomerDefaultAddressesParams{
+			ClearShipping:  arg.IsDefaultShipping,
+			ClearBilling:   arg.IsDefaultBilling,
+			ShopCustomerID: arg.ShopCustomerID,
+			ShopID:         arg.ShopID,
+		})
+		if err != nil {
+			return err
+		}
+		address, err = q.UpdateCustomerAddress(ctx, arg)
+		return err
+	})
+	return address, err
+}
+
+func (r *repoSvc) SetCustomerDefaultAddress(ctx context.Context, arg SetCustomerDefaultAddressParams) (CustomerAddress, error) {
+	address := CustomerAddress{}
+	err := r.WithTx(ctx, func(q *Queries) error {
+		err := q.ClearCustomerDefaultAddresses(ctx, ClearCustomerDefaultAddressesParams{
+			ClearShipping:  arg.DefaultShipping,
+			ClearBilling:   arg.DefaultBilling,
+			ShopCustomerID: arg.ShopCustomerID,
+			ShopID:         arg.ShopID,
+		})
+		if err != nil {
+			return err
+		}
+		address, err = q.SetCustomerDefaultAddress(ctx, arg)
+		return err
+	})
+	return address, err
+}
+
 func (r *repoSvc) GetSalesSummary(ctx context.Context, arg GetSalesSummaryParams) (GetSalesSummaryRow, error) {
 	return r.Queries.GetSalesSummary(ctx, arg)
 }
@@ -296,6 +636,22 @@ func (r *repoSvc) GetTopProducts(ctx context.Context, arg GetTopProductsParams)
 	return r.Queries.GetTopProducts(ctx, arg)
 }
 
+func (r *repoSvc) GetProfitSummary(ctx context.Context, arg GetProfitSummaryParams) (GetProfitSummaryRow, error) {
+	return r.Queries.GetProfitSummary(ctx, arg)
+}
+
+func (r *repoSvc) GetProfitByProduct(ctx context.Context, arg GetProfitByProductParams) ([]GetProfitByProductRow, error) {
+	return r.Queries.GetProfitByProduct(ctx, arg)
+}
+
+func (r *repoSvc) GetProfitByCategory(ctx context.Context, arg GetProfitByCategoryParams) ([]GetProfitByCategoryRow, error) {
+	return r.Queries.GetProfitByCategory(ctx, arg)
+}
+
+func (r *repoSvc) GetProfitOverTime(ctx context.Context, arg GetProfitOverTimeParams) ([]GetProfitOverTimeRow, error) {
+	return r.Queries.GetProfitOverTime(ctx, arg)
+}
+
 func (r *repoSvc) GetCustomerSummaryNewReturning(ctx context.Context, arg GetCustomerSummaryNewReturningParams) (GetCustomerSummaryNewReturningRow, error) {
 	return r.Queries.GetCustomerSummaryNewReturning(ctx, arg)
 }
@@ -77,6 +77,7 @@ CREATE TABLE categories (
     description VARCHAR(255),
     parent_id BIGINT,
     shop_id BIGINT NOT NULL,
+    banner_url TEXT,
     UNIQUE (title, shop_id),
     UNIQUE (slug, shop_id),
     CONSTRAINT fk_parent FOREIGN KEY (parent_id) REFERENCES categories(category_id),
@@ -90,6 +91,13 @@ CREATE TABLE product_types (
     shippable BOOLEAN NOT NULL DEFAULT TRUE,
     digital BOOLEAN NOT NULL DEFAULT FALSE,
     sku_substring VARCHAR(10),
+    -- Paid orders issue a gift card for every unit of a gift card product
+    gift_card BOOLEAN NOT NULL DEFAULT FALSE,
+    -- Stock of the type's products is received into lots, deducted earliest expiry first
+    tracks_lots BOOLEAN NOT NULL DEFAULT FALSE,
+    -- Reorder point and quantity of the type's variants that set none themselves
+    reorder_point BIGINT CHECK (reorder_point >= 0),
+    reorder_quantity BIGINT CHECK (reorder_quantity > 0),
     shop_id BIGINT NOT NULL,
     UNIQUE (title, shop_id),
     CONSTRAINT fk_shop FOREIGN KEY (shop_id) REFERENCES shops(shop_id) ON DELETE CASCADE
@@ -124,6 +132,8 @@ CREATE TABLE product_images(
     CONSTRAINT fk_shop FOREIGN KEY (shop_id) REFERENCES shops(shop_id) ON DELETE CASCADE
 );
 
+CREATE TYPE inventory_policy AS ENUM('deny', 'backorder', 'preorder');
+
 CREATE TABLE product_variations(
     product_variation_id BIGSERIAL PRIMARY KEY,
     sku VARCHAR(50) NOT NULL,
@@ -138,6 +148,16 @@ CREATE TABLE product_variations(
     updated_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
     product_id BIGINT NOT NULL,
     shop_id BIGINT NOT NULL,
+    cost_price DECIMAL(19, 4), -- what a unit costs the shop, averaged over purchase order receipts
+    -- Whether the variant sells once it is out of stock: not at all, on backorder, or
+    -- on preorder shipping on preorder_ships_on, up to preorder_limit units
+    inventory_policy inventory_policy NOT NULL DEFAULT 'deny',
+    preorder_ships_on DATE,
+    preorder_limit BIGINT CHECK (preorder_limit >= 0),
+    -- Stock falling to reorder_point raises a stock alert suggesting reorder_quantity
+    -- more units; the product type's when not set
+    reorder_point BIGINT CHECK (reorder_point >= 0),
+    reorder_quantity BIGINT CHECK (reorder_quantity > 0),
     UNIQUE (sku, shop_id),
     CONSTRAINT fk_product FOREIGN KEY (product_id) REFERENCES products(product_id) ON DELETE CASCADE,
     CONSTRAINT fk_shop FOREIGN KEY (shop_id) REFERENCES shops(shop_id) ON DELETE CASCADE 
@@ -248,11 +268,11 @@ CREATE TYPE shipping_status_type AS ENUM (
 CREATE TABLE orders (
   order_id BIGSERIAL PRIMARY KEY,
   status order_status_type NOT NULL DEFAULT 'pending',
-  amount DECIMAL(10, 2) NOT NULL,
-  discount DECIMAL(10, 2) NOT NULL DEFAULT 0,
-  shipping_cost DECIMAL(10, 2) NOT NULL DEFAULT 0,
-  tax DECIMAL(10, 2) NOT NULL DEFAULT 0,
-  shipping_address TEXT NOT NULL,
+  amount DECIMAL(19, 4) NOT NULL,
+  discount DECIMAL(19, 4) NOT NULL DEFAULT 0,
+  shipping_cost DECIMAL(19, 4) NOT NULL DEFAULT 0,
+  tax DECIMAL(19, 4) NOT NULL DEFAULT 0,
+  shipping_address JSONB NOT NULL, -- snapshot of the address, same shape as models.ShippingAddress
   payment_method payment_method_type NOT NULL, -- Reuse existing ENUM
   payment_status payment_status_type NOT NULL DEFAULT 'pending',
   shipping_method VARCHAR(10) NOT NULL,
@@ -266,19 +286,22 @@ CREATE TABLE orders (
   customer_name VARCHAR(100) NOT NULL,
   customer_email VARCHAR(100),
   customer_phone VARCHAR(50),
-  CONSTRAINT fk_shop_customer FOREIGN KEY (shop_customer_id) REFERENCES shop_customers(shop_customer_id) ON DELETE CASCADE,
+  -- Orders are accounting records and outlive the customer
+  CONSTRAINT fk_shop_customer FOREIGN KEY (shop_customer_id) REFERENCES shop_customers(shop_customer_id) ON DELETE SET NULL,
   CONSTRAINT fk_shop FOREIGN KEY (shop_id) REFERENCES shops(shop_id) ON DELETE CASCADE
 );
 
 CREATE TABLE order_items(
     order_item_id BIGSERIAL PRIMARY KEY,
     quantity BIGINT NOT NULL,
-    price DECIMAL(10, 2) NOT NULL,
+    price DECIMAL(19, 4) NOT NULL,
     created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
     updated_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
     product_variation_id BIGINT NOT NULL,
     order_id BIGINT NOT NULL,
     shop_id BIGINT NOT NULL,
+    cost_price DECIMAL(19, 4), -- cost price of the variant when it was sold
+    backordered_quantity BIGINT NOT NULL DEFAULT 0, -- units ordered beyond the stock held when the order was placed
     CONSTRAINT fk_product_variation FOREIGN KEY (product_variation_id) REFERENCES product_variations(product_variation_id) ON DELETE CASCADE,
     CONSTRAINT fk_order FOREIGN KEY (order_id) REFERENCES orders(order_id) ON DELETE CASCADE,
     CONSTRAINT fk_shop FOREIGN KEY (shop_id) REFERENCES shops(shop_id) ON DELETE CASCADE
@@ -293,9 +316,10 @@ CREATE TABLE stock_movements (
     quantity_change INT NOT NULL, -- positive for increase, negative for decrease
     quantity_before INT NOT NULL,
     quantity_after INT NOT NULL,
-    reference_id BIGINT, -- order_id for sales, adjustment_id for manual adjustments
+    reference_id BIGINT, -- order_id for sales, purchase_order_id for restocks from a purchase order, stocktake_id for stocktake adjustments, lot_id for lot write-offs, adjustment_id for manual adjustments
     notes TEXT,
     created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    location_id BIGINT, -- location whose stock changed; NULL once the location is deleted
     CONSTRAINT fk_product_variation FOREIGN KEY (product_variation_id) REFERENCES product_variations(product_variation_id) ON DELETE CASCADE,
     CONSTRAINT fk_shop FOREIGN KEY (shop_id) REFERENCES shops(shop_id) ON DELETE CASCADE
 );
@@ -501,4 +525,1241 @@ FROM orders
 WHERE status = 'completed'
 GROUP BY shop_id, day;
 
-CREATE UNIQUE INDEX IF NOT EXISTS idx_daily_sales_shop_day ON daily_sales(shop_id, day);
\ No newline at end of file
+CREATE UNIQUE INDEX IF NOT EXISTS idx_daily_sales_shop_day ON daily_sales(shop_id, day);
+
+-- Product revisions: versioned snapshots of a product and its children
+CREATE TABLE product_revisions (
+    product_revision_id BIGSERIAL PRIMARY KEY,
+    version INT NOT NULL,
+    snapshot JSONB NOT NULL, -- product row, attribute values, variants (with attribute values) and images
+    created_by VARCHAR(255), -- X-User-Id of the author
+    restored_from_version INT, -- set when the revision was produced by a rollback
+    created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    product_id BIGINT NOT NULL,
+    shop_id BIGINT NOT NULL,
+    UNIQUE (product_id, version),
+    CONSTRAINT fk_product FOREIGN KEY (product_id) REFERENCES products(product_id) ON DELETE CASCADE,
+    CONSTRAINT fk_shop FOREIGN KEY (shop_id) REFERENCES shops(shop_id) ON DELETE CASCADE
+);
+
+-- SET RLS for product_revisions
+ALTER TABLE product_revisions ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON product_revisions
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
+
+CREATE TABLE image_assets (
+    image_asset_id BIGSERIAL PRIMARY KEY,
+    storage_key TEXT NOT NULL, -- object key of the stored original
+    url TEXT NOT NULL UNIQUE,
+    content_type VARCHAR(50) NOT NULL,
+    width INT NOT NULL,
+    height INT NOT NULL,
+    size_bytes BIGINT NOT NULL,
+    renditions JSONB NOT NULL DEFAULT '[]', -- [{format, width, height, url, key, size_bytes}]
+    created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    shop_id BIGINT NOT NULL,
+    CONSTRAINT fk_shop FOREIGN KEY (shop_id) REFERENCES shops(shop_id) ON DELETE CASCADE
+);
+
+-- SET RLS for image_assets
+ALTER TABLE image_assets ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON image_assets
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
+
+CREATE TYPE collection_type AS ENUM('manual', 'smart');
+CREATE TABLE collections (
+    collection_id BIGSERIAL PRIMARY KEY,
+    handle VARCHAR(100) NOT NULL,
+    title VARCHAR(100) NOT NULL,
+    description TEXT,
+    collection_type collection_type NOT NULL DEFAULT 'manual'::collection_type,
+    rules JSONB NOT NULL DEFAULT '[]', -- smart collections: [{field, operator, value, attribute_id}]
+    match_all BOOLEAN NOT NULL DEFAULT TRUE, -- smart collections: all rules must match (AND) or any (OR)
+    sort_order VARCHAR(20) NOT NULL DEFAULT 'manual', -- 'manual', 'best_selling', 'created_desc', 'price_asc', 'price_desc', 'title_asc'
+    created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    updated_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    shop_id BIGINT NOT NULL,
+    UNIQUE (handle, shop_id),
+    CONSTRAINT fk_shop FOREIGN KEY (shop_id) REFERENCES shops(shop_id) ON DELETE CASCADE
+);
+
+-- Ordered membership of manual collections
+CREATE TABLE collection_products (
+    collection_id BIGINT NOT NULL,
+    product_id BIGINT NOT NULL,
+    position INT NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    shop_id BIGINT NOT NULL,
+    PRIMARY KEY (collection_id, product_id),
+    CONSTRAINT fk_collection FOREIGN KEY (collection_id) REFERENCES collections(collection_id) ON DELETE CASCADE,
+    CONSTRAINT fk_product FOREIGN KEY (product_id) REFERENCES products(product_id) ON DELETE CASCADE,
+    CONSTRAINT fk_shop FOREIGN KEY (shop_id) REFERENCES shops(shop_id) ON DELETE CASCADE
+);
+CREATE INDEX idx_collection_products_position ON collection_products (collection_id, position);
+
+-- SET RLS for collections
+ALTER TABLE collections ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON collections
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
+
+-- SET RLS for collection_products
+ALTER TABLE collection_products ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON collection_products
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
+
+-- Free-form product tags
+CREATE TABLE product_tags (
+    product_id BIGINT NOT NULL,
+    tag VARCHAR(255) NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    shop_id BIGINT NOT NULL,
+    PRIMARY KEY (product_id, tag),
+    CONSTRAINT fk_product FOREIGN KEY (product_id) REFERENCES products(product_id) ON DELETE CASCADE,
+    CONSTRAINT fk_shop FOREIGN KEY (shop_id) REFERENCES shops(shop_id) ON DELETE CASCADE
+);
+CREATE INDEX idx_product_tags_shop_tag ON product_tags (shop_id, LOWER(tag));
+
+-- SET RLS for product_tags
+ALTER TABLE product_tags ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON product_tags
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
+
+CREATE TYPE metafield_owner_type AS ENUM('product', 'product_variation', 'category', 'shop', 'customer');
+CREATE TYPE metafield_type AS ENUM('single_line_text', 'multi_line_text', 'integer', 'decimal', 'boolean', 'date', 'date_time', 'json', 'url', 'color');
+
+-- Optional definitions that fix the type and validations of a namespace/key per owner type
+CREATE TABLE metafield_definitions (
+    metafield_definition_id BIGSERIAL PRIMARY KEY,
+    owner_type metafield_owner_type NOT NULL,
+    namespace VARCHAR(64) NOT NULL,
+    key VARCHAR(64) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    type metafield_type NOT NULL,
+    validations JSONB NOT NULL DEFAULT '{}', -- {min, max, max_length, pattern, choices}
+    created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    updated_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    shop_id BIGINT NOT NULL,
+    UNIQUE (owner_type, namespace, key, shop_id),
+    CONSTRAINT fk_shop FOREIGN KEY (shop_id) REFERENCES shops(shop_id) ON DELETE CASCADE
+);
+
+CREATE TABLE metafields (
+    metafield_id BIGSERIAL PRIMARY KEY,
+    owner_type metafield_owner_type NOT NULL,
+    owner_id VARCHAR(64) NOT NULL, -- product/variation/category/shop ID, or the shop customer UUID
+    namespace VARCHAR(64) NOT NULL,
+    key VARCHAR(64) NOT NULL,
+    type metafield_type NOT NULL,
+    value TEXT NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    updated_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    shop_id BIGINT NOT NULL,
+    UNIQUE (owner_type, owner_id, namespace, key, shop_id),
+    CONSTRAINT fk_shop FOREIGN KEY (shop_id) REFERENCES shops(shop_id) ON DELETE CASCADE
+);
+
+-- SET RLS for metafield_definitions
+ALTER TABLE metafield_definitions ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON metafield_definitions
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
+
+-- SET RLS for metafields
+ALTER TABLE metafields ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON metafields
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
+
+-- Locales enabled for a shop's storefront; exactly one is the default
+CREATE TABLE shop_locales (
+    locale VARCHAR(20) NOT NULL, -- BCP 47 tag, e.g. 'en', 'fr', 'pt-BR'
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    shop_id BIGINT NOT NULL,
+    PRIMARY KEY (shop_id, locale),
+    CONSTRAINT fk_shop FOREIGN KEY (shop_id) REFERENCES shops(shop_id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX idx_shop_locales_default ON shop_locales (shop_id) WHERE is_default;
+
+CREATE TYPE translatable_resource_type AS ENUM('product', 'product_variation', 'category', 'shop', 'collection');
+
+-- Translated values of translatable fields, e.g. products.title in 'fr'
+CREATE TABLE translations (
+    translation_id BIGSERIAL PRIMARY KEY,
+    resource_type translatable_resource_type NOT NULL,
+    resource_id BIGINT NOT NULL,
+    field VARCHAR(50) NOT NULL,
+    locale VARCHAR(20) NOT NULL,
+    value TEXT NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    updated_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    shop_id BIGINT NOT NULL,
+    UNIQUE (resource_type, resource_id, field, locale, shop_id),
+    CONSTRAINT fk_shop FOREIGN KEY (shop_id) REFERENCES shops(shop_id) ON DELETE CASCADE
+);
+
+-- SET RLS for shop_locales
+ALTER TABLE shop_locales ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON shop_locales
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
+
+-- SET RLS for translations
+ALTER TABLE translations ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON translations
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
+
+-- Presentment currencies a shop can show and charge in besides its own currency_code.
+-- A NULL exchange_rate means the rate is taken from the configured rate source.
+CREATE TABLE shop_currencies (
+    currency_code VARCHAR(3) NOT NULL,
+    exchange_rate DECIMAL(18, 8),
+    created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    updated_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    shop_id BIGINT NOT NULL,
+    PRIMARY KEY (shop_id, currency_code),
+    CONSTRAINT fk_shop FOREIGN KEY (shop_id) REFERENCES shops(shop_id) ON DELETE CASCADE
+);
+
+-- Fixed variant prices in a presentment currency, used instead of the converted price
+CREATE TABLE product_variation_prices (
+    product_variation_id BIGINT NOT NULL,
+    currency_code VARCHAR(3) NOT NULL,
+    price DECIMAL(10, 2) NOT NULL,
+    updated_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    shop_id BIGINT NOT NULL,
+    PRIMARY KEY (product_variation_id, currency_code),
+    CONSTRAINT fk_product_variation FOREIGN KEY (product_variation_id) REFERENCES product_variations(product_variation_id) ON DELETE CASCADE,
+    CONSTRAINT fk_shop FOREIGN KEY (shop_id) REFERENCES shops(shop_id) ON DELETE CASCADE
+);
+
+-- Currency an order was charged in and the rate used to convert it from the shop currency
+CREATE TABLE order_presentments (
+    order_id BIGINT PRIMARY KEY,
+    currency_code VARCHAR(3) NOT NULL,
+    exchange_rate DECIMAL(18, 8) NOT NULL,
+    amount DECIMAL(19, 4) NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    shop_id BIGINT NOT NULL,
+    CONSTRAINT fk_order FOREIGN KEY (order_id) REFERENCES orders(order_id) ON DELETE CASCADE,
+    CONSTRAINT fk_shop FOREIGN KEY (shop_id) REFERENCES shops(shop_id) ON DELETE CASCADE
+);
+
+-- SET RLS for shop_currencies
+ALTER TABLE shop_currencies ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON shop_currencies
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
+
+-- SET RLS for product_variation_prices
+ALTER TABLE product_variation_prices ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON product_variation_prices
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
+
+-- SET RLS for order_presentments
+ALTER TABLE order_presentments ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON order_presentments
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
+
+CREATE TYPE review_status AS ENUM('pending', 'approved', 'rejected');
+
+-- Product reviews left by customers for a variant they ordered; only approved reviews are public
+CREATE TABLE product_reviews (
+    review_id BIGSERIAL PRIMARY KEY,
+    product_id BIGINT NOT NULL,
+    product_variation_id BIGINT NOT NULL,
+    order_id BIGINT NOT NULL,
+    rating SMALLINT NOT NULL CHECK (rating BETWEEN 1 AND 5),
+    title VARCHAR(255),
+    body TEXT,
+    photos TEXT[] NOT NULL DEFAULT '{}',
+    customer_name VARCHAR(100) NOT NULL,
+    customer_email VARCHAR(100) NOT NULL,
+    status review_status NOT NULL DEFAULT 'pending'::review_status,
+    merchant_reply TEXT,
+    replied_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    updated_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    shop_id BIGINT NOT NULL,
+    UNIQUE (order_id, product_variation_id),
+    CONSTRAINT fk_order FOREIGN KEY (order_id) REFERENCES orders(order_id) ON DELETE CASCADE,
+    CONSTRAINT fk_product FOREIGN KEY (product_id) REFERENCES products(product_id) ON DELETE CASCADE,
+    CONSTRAINT fk_product_variation FOREIGN KEY (product_variation_id) REFERENCES product_variations(product_variation_id) ON DELETE CASCADE,
+    CONSTRAINT fk_shop FOREIGN KEY (shop_id) REFERENCES shops(shop_id) ON DELETE CASCADE
+);
+CREATE INDEX idx_product_reviews_product_status ON product_reviews (product_id, status);
+CREATE INDEX idx_product_reviews_shop_status ON product_reviews (shop_id, status, created_at);
+
+-- Aggregate of the approved reviews of a product, recomputed whenever a review is moderated
+CREATE TABLE product_ratings (
+    product_id BIGINT PRIMARY KEY,
+    rating_count INT NOT NULL DEFAULT 0,
+    rating_sum INT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    shop_id BIGINT NOT NULL,
+    CONSTRAINT fk_product FOREIGN KEY (product_id) REFERENCES products(product_id) ON DELETE CASCADE,
+    CONSTRAINT fk_shop FOREIGN KEY (shop_id) REFERENCES shops(shop_id) ON DELETE CASCADE
+);
+
+-- SET RLS for product_reviews
+ALTER TABLE product_reviews ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON product_reviews
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
+
+-- SET RLS for product_ratings
+ALTER TABLE product_ratings ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON product_ratings
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
+
+-- Product variants a shop customer saved for later
+CREATE TABLE wishlist_items (
+    shop_customer_id UUID NOT NULL,
+    product_variation_id BIGINT NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    shop_id BIGINT NOT NULL,
+    PRIMARY KEY (shop_customer_id, product_variation_id),
+    CONSTRAINT fk_shop_customer FOREIGN KEY (shop_customer_id) REFERENCES shop_customers(shop_customer_id) ON DELETE CASCADE,
+    CONSTRAINT fk_product_variation FOREIGN KEY (product_variation_id) REFERENCES product_variations(product_variation_id) ON DELETE CASCADE,
+    CONSTRAINT fk_shop FOREIGN KEY (shop_id) REFERENCES shops(shop_id) ON DELETE CASCADE
+);
+
+-- Shoppers, signed in or not, waiting to be told an out of stock variant is available again.
+-- notified_at is set once a notification has been queued and cleared when they subscribe again.
+CREATE TABLE back_in_stock_subscriptions (
+    subscription_id BIGSERIAL PRIMARY KEY,
+    product_variation_id BIGINT NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    shop_customer_id UUID,
+    notified_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    shop_id BIGINT NOT NULL,
+    UNIQUE (product_variation_id, email),
+    CONSTRAINT fk_product_variation FOREIGN KEY (product_variation_id) REFERENCES product_variations(product_variation_id) ON DELETE CASCADE,
+    CONSTRAINT fk_shop_customer FOREIGN KEY (shop_customer_id) REFERENCES shop_customers(shop_customer_id) ON DELETE SET NULL,
+    CONSTRAINT fk_shop FOREIGN KEY (shop_id) REFERENCES shops(shop_id) ON DELETE CASCADE
+);
+CREATE INDEX idx_back_in_stock_subscriptions_pending ON back_in_stock_subscriptions (product_variation_id) WHERE notified_at IS NULL;
+
+CREATE TYPE notification_status AS ENUM('pending', 'sent', 'failed');
+
+-- Outbox of customer notifications waiting to be delivered, e.g. kind 'back_in_stock'
+CREATE TABLE notifications (
+    notification_id BIGSERIAL PRIMARY KEY,
+    kind VARCHAR(50) NOT NULL,
+    recipient VARCHAR(255) NOT NULL, -- email address
+    payload JSONB NOT NULL DEFAULT '{}',
+    status notification_status NOT NULL DEFAULT 'pending'::notification_status,
+    attempts INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    sent_at TIMESTAMPTZ,
+    shop_id BIGINT NOT NULL,
+    CONSTRAINT fk_shop FOREIGN KEY (shop_id) REFERENCES shops(shop_id) ON DELETE CASCADE
+);
+CREATE INDEX idx_notifications_pending ON notifications (created_at) WHERE status = 'pending';
+
+-- SET RLS for wishlist_items
+ALTER TABLE wishlist_items ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON wishlist_items
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
+
+-- SET RLS for back_in_stock_subscriptions
+ALTER TABLE back_in_stock_subscriptions ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON back_in_stock_subscriptions
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
+
+-- SET RLS for notifications
+ALTER TABLE notifications ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON notifications
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
+
+-- Address book of a shop customer; at most one default shipping and one default billing address
+CREATE TABLE customer_addresses (
+    address_id BIGSERIAL PRIMARY KEY,
+    shop_customer_id UUID NOT NULL,
+    first_name VARCHAR(100) NOT NULL,
+    last_name VARCHAR(100) NOT NULL,
+    company VARCHAR(255),
+    address_line_1 VARCHAR(255) NOT NULL,
+    address_line_2 VARCHAR(255),
+    city VARCHAR(100) NOT NULL,
+    state VARCHAR(100),
+    postal_code VARCHAR(20) NOT NULL,
+    country CHAR(2) NOT NULL, -- ISO 3166-1 alpha-2
+    phone VARCHAR(50),
+    is_default_shipping BOOLEAN NOT NULL DEFAULT FALSE,
+    is_default_billing BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    updated_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    shop_id BIGINT NOT NULL,
+    CONSTRAINT fk_shop_customer FOREIGN KEY (shop_customer_id) REFERENCES shop_customers(shop_customer_id) ON DELETE CASCADE,
+    CONSTRAINT fk_shop FOREIGN KEY (shop_id) REFERENCES shops(shop_id) ON DELETE CASCADE
+);
+CREATE INDEX idx_customer_addresses_customer ON customer_addresses (shop_customer_id);
+CREATE UNIQUE INDEX idx_customer_addresses_default_shipping ON customer_addresses (shop_customer_id) WHERE is_default_shipping;
+CREATE UNIQUE INDEX idx_customer_addresses_default_billing ON customer_addresses (shop_customer_id) WHERE is_default_billing;
+
+-- SET RLS for customer_addresses
+ALTER TABLE customer_addresses ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON customer_addresses
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
+
+-- Manual customer groups, e.g. wholesale or VIP. A group may give a percentage
+-- discount and/or fixed prices per variant; the lowest price applies.
+CREATE TABLE customer_groups (
+    group_id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    description TEXT,
+    discount_percent DECIMAL(5, 2) CHECK (discount_percent > 0 AND discount_percent <= 100),
+    created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    updated_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    shop_id BIGINT NOT NULL,
+    UNIQUE (shop_id, name),
+    CONSTRAINT fk_shop FOREIGN KEY (shop_id) REFERENCES shops(shop_id) ON DELETE CASCADE
+);
+
+CREATE TABLE customer_group_members (
+    group_id BIGINT NOT NULL,
+    shop_customer_id UUID NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    shop_id BIGINT NOT NULL,
+    PRIMARY KEY (group_id, shop_customer_id),
+    CONSTRAINT fk_customer_group FOREIGN KEY (group_id) REFERENCES customer_groups(group_id) ON DELETE CASCADE,
+    CONSTRAINT fk_shop_customer FOREIGN KEY (shop_customer_id) REFERENCES shop_customers(shop_customer_id) ON DELETE CASCADE,
+    CONSTRAINT fk_shop FOREIGN KEY (shop_id) REFERENCES shops(shop_id) ON DELETE CASCADE
+);
+CREATE INDEX idx_customer_group_members_customer ON customer_group_members (shop_customer_id);
+
+-- Group price list, in the shop currency
+CREATE TABLE customer_group_prices (
+    group_id BIGINT NOT NULL,
+    product_variation_id BIGINT NOT NULL,
+    price DECIMAL(10, 2) NOT NULL CHECK (price >= 0),
+    updated_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    shop_id BIGINT NOT NULL,
+    PRIMARY KEY (group_id, product_variation_id),
+    CONSTRAINT fk_customer_group FOREIGN KEY (group_id) REFERENCES customer_groups(group_id) ON DELETE CASCADE,
+    CONSTRAINT fk_product_variation FOREIGN KEY (product_variation_id) REFERENCES product_variations(product_variation_id) ON DELETE CASCADE,
+    CONSTRAINT fk_shop FOREIGN KEY (shop_id) REFERENCES shops(shop_id) ON DELETE CASCADE
+);
+
+-- Rule-based segments, evaluated against completed orders when listed. Unset rules
+-- match everyone; window_days limits the orders counted for spend and order count,
+-- inactive_days matches customers without an order in that many days.
+CREATE TABLE customer_segments (
+    segment_id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    description TEXT,
+    min_total_spent DECIMAL(19, 4),
+    max_total_spent DECIMAL(19, 4),
+    min_order_count INTEGER,
+    max_order_count INTEGER,
+    window_days INTEGER,
+    inactive_days INTEGER,
+    created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    updated_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    shop_id BIGINT NOT NULL,
+    UNIQUE (shop_id, name),
+    CONSTRAINT fk_shop FOREIGN KEY (shop_id) REFERENCES shops(shop_id) ON DELETE CASCADE
+);
+
+-- SET RLS for customer_groups
+ALTER TABLE customer_groups ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON customer_groups
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
+
+-- SET RLS for customer_group_members
+ALTER TABLE customer_group_members ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON customer_group_members
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
+
+-- SET RLS for customer_group_prices
+ALTER TABLE customer_group_prices ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON customer_group_prices
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
+
+-- SET RLS for customer_segments
+ALTER TABLE customer_segments ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON customer_segments
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
+
+CREATE TYPE customer_data_request_kind AS ENUM('export', 'erasure');
+
+-- Audit log of data subject requests (GDPR/NDPR). shop_customer_id is not a foreign
+-- key so the record survives the erasure of the customer; details only holds counts.
+CREATE TABLE customer_data_requests (
+    request_id BIGSERIAL PRIMARY KEY,
+    kind customer_data_request_kind NOT NULL,
+    shop_customer_id UUID NOT NULL,
+    requested_by VARCHAR(255),
+    details JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    shop_id BIGINT NOT NULL,
+    CONSTRAINT fk_shop FOREIGN KEY (shop_id) REFERENCES shops(shop_id) ON DELETE CASCADE
+);
+CREATE INDEX idx_customer_data_requests_shop ON customer_data_requests (shop_id, created_at);
+
+-- SET RLS for customer_data_requests
+ALTER TABLE customer_data_requests ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON customer_data_requests
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
+
+-- Ledger entry kinds shared by gift cards and store credit. Amounts are signed:
+-- issue and refund add to the balance, redeem takes from it, adjust goes either way.
+CREATE TYPE credit_transaction_kind AS ENUM('issue', 'redeem', 'refund', 'adjust');
+
+-- Gift cards hold a balance in the shop currency; code is stored normalized
+-- (upper case, no separators)
+CREATE TABLE gift_cards (
+    gift_card_id BIGSERIAL PRIMARY KEY,
+    code VARCHAR(32) NOT NULL,
+    initial_amount DECIMAL(19, 4) NOT NULL,
+    balance DECIMAL(19, 4) NOT NULL CHECK (balance >= 0),
+    currency_code VARCHAR(3) NOT NULL,
+    expires_at TIMESTAMPTZ,
+    disabled BOOLEAN NOT NULL DEFAULT FALSE,
+    note TEXT,
+    recipient_email VARCHAR(255),
+    shop_customer_id UUID,
+    order_id BIGINT, -- the order that bought the card
+    order_item_id BIGINT, -- the order line and unit of it the card was issued for
+    order_item_unit BIGINT,
+    created_by VARCHAR(255),
+    created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    updated_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    shop_id BIGINT NOT NULL,
+    UNIQUE (shop_id, code),
+    UNIQUE (order_item_id, order_item_unit),
+    CONSTRAINT fk_shop_customer FOREIGN KEY (shop_customer_id) REFERENCES shop_customers(shop_customer_id) ON DELETE SET NULL,
+    CONSTRAINT fk_order FOREIGN KEY (order_id) REFERENCES orders(order_id) ON DELETE SET NULL,
+    CONSTRAINT fk_order_item FOREIGN KEY (order_item_id) REFERENCES order_items(order_item_id) ON DELETE SET NULL,
+    CONSTRAINT fk_shop FOREIGN KEY (shop_id) REFERENCES shops(shop_id) ON DELETE CASCADE
+);
+
+CREATE TABLE gift_card_transactions (
+    transaction_id BIGSERIAL PRIMARY KEY,
+    gift_card_id BIGINT NOT NULL,
+    kind credit_transaction_kind NOT NULL,
+    amount DECIMAL(19, 4) NOT NULL,
+    balance_after DECIMAL(19, 4) NOT NULL,
+    order_id BIGINT, -- the order the card paid for
+    note TEXT,
+    created_by VARCHAR(255),
+    created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    shop_id BIGINT NOT NULL,
+    CONSTRAINT fk_gift_card FOREIGN KEY (gift_card_id) REFERENCES gift_cards(gift_card_id) ON DELETE CASCADE,
+    CONSTRAINT fk_order FOREIGN KEY (order_id) REFERENCES orders(order_id) ON DELETE SET NULL,
+    CONSTRAINT fk_shop FOREIGN KEY (shop_id) REFERENCES shops(shop_id) ON DELETE CASCADE
+);
+CREATE INDEX idx_gift_card_transactions_card ON gift_card_transactions (gift_card_id, created_at);
+CREATE INDEX idx_gift_card_transactions_order ON gift_card_transactions (order_id);
+
+-- Store credit has no balance column: the balance is balance_after of the
+-- customer's latest entry
+CREATE TABLE store_credit_transactions (
+    transaction_id BIGSERIAL PRIMARY KEY,
+    shop_customer_id UUID NOT NULL,
+    kind credit_transaction_kind NOT NULL,
+    amount DECIMAL(19, 4) NOT NULL,
+    balance_after DECIMAL(19, 4) NOT NULL CHECK (balance_after >= 0),
+    reason VARCHAR(50), -- return, goodwill, correction
+    order_id BIGINT,
+    note TEXT,
+    created_by VARCHAR(255),
+    created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    shop_id BIGINT NOT NULL,
+    CONSTRAINT fk_shop_customer FOREIGN KEY (shop_customer_id) REFERENCES shop_customers(shop_customer_id) ON DELETE CASCADE,
+    CONSTRAINT fk_order FOREIGN KEY (order_id) REFERENCES orders(order_id) ON DELETE SET NULL,
+    CONSTRAINT fk_shop FOREIGN KEY (shop_id) REFERENCES shops(shop_id) ON DELETE CASCADE
+);
+CREATE INDEX idx_store_credit_transactions_customer ON store_credit_transactions (shop_customer_id, transaction_id);
+CREATE INDEX idx_store_credit_transactions_order ON store_credit_transactions (order_id);
+
+-- SET RLS for gift_cards
+ALTER TABLE gift_cards ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON gift_cards
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
+
+-- SET RLS for gift_card_transactions
+ALTER TABLE gift_card_transactions ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON gift_card_transactions
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
+
+-- SET RLS for store_credit_transactions
+ALTER TABLE store_credit_transactions ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON store_credit_transactions
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
+
+-- Ledger entry kinds of loyalty points. Points are signed: earn and refund add
+-- to the balance, redeem, clawback and expire take from it, adjust goes either way.
+CREATE TYPE loyalty_transaction_kind AS ENUM('earn', 'redeem', 'refund', 'clawback', 'expire', 'adjust');
+
+-- One loyalty program per shop
+CREATE TABLE loyalty_programs (
+    shop_id BIGINT PRIMARY KEY,
+    enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    earn_rate DECIMAL(10, 4) NOT NULL DEFAULT 1 CHECK (earn_rate >= 0), -- points per shop currency unit spent
+    burn_rate DECIMAL(10, 4) NOT NULL DEFAULT 100 CHECK (burn_rate > 0), -- points per shop currency unit of discount
+    min_redeem_points INTEGER NOT NULL DEFAULT 0 CHECK (min_redeem_points >= 0),
+    expiry_days INTEGER CHECK (expiry_days > 0), -- points expire after this many days without activity; NULL never
+    created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    updated_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    CONSTRAINT fk_shop FOREIGN KEY (shop_id) REFERENCES shops(shop_id) ON DELETE CASCADE
+);
+
+CREATE TABLE loyalty_transactions (
+    transaction_id BIGSERIAL PRIMARY KEY,
+    shop_customer_id UUID NOT NULL,
+    kind loyalty_transaction_kind NOT NULL,
+    points INTEGER NOT NULL,
+    balance_after INTEGER NOT NULL CHECK (balance_after >= 0),
+    order_id BIGINT,
+    note TEXT,
+    created_by VARCHAR(255),
+    created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    shop_id BIGINT NOT NULL,
+    CONSTRAINT fk_shop_customer FOREIGN KEY (shop_customer_id) REFERENCES shop_customers(shop_customer_id) ON DELETE CASCADE,
+    CONSTRAINT fk_order FOREIGN KEY (order_id) REFERENCES orders(order_id) ON DELETE SET NULL,
+    CONSTRAINT fk_shop FOREIGN KEY (shop_id) REFERENCES shops(shop_id) ON DELETE CASCADE
+);
+CREATE INDEX idx_loyalty_transactions_customer ON loyalty_transactions (shop_customer_id, transaction_id);
+CREATE INDEX idx_loyalty_transactions_order ON loyalty_transactions (order_id);
+
+-- SET RLS for loyalty_programs
+ALTER TABLE loyalty_programs ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON loyalty_programs
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
+
+-- SET RLS for loyalty_transactions
+ALTER TABLE loyalty_transactions ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON loyalty_transactions
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
+
+-- Places that hold stock, e.g. a shop floor and a warehouse. Each shop has one
+-- default location, which receives the stock set directly on product variants.
+CREATE TABLE locations (
+    location_id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    address TEXT,
+    sells_online BOOLEAN NOT NULL DEFAULT TRUE, -- stock here counts towards the storefront's available quantity
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    updated_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    shop_id BIGINT NOT NULL,
+    CONSTRAINT locations_shop_id_name_key UNIQUE (shop_id, name),
+    CONSTRAINT fk_shop FOREIGN KEY (shop_id) REFERENCES shops(shop_id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX locations_shop_id_default_key ON locations (shop_id) WHERE is_default;
+
+-- Stock of a variant at a location. product_variations.available_quantity is kept
+-- as the sum of available stock at the locations that sell online.
+CREATE TABLE inventory_levels (
+    product_variation_id BIGINT NOT NULL,
+    location_id BIGINT NOT NULL,
+    available BIGINT NOT NULL DEFAULT 0 CHECK (available >= 0),
+    incoming BIGINT NOT NULL DEFAULT 0 CHECK (incoming >= 0), -- in transit to this location
+    updated_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    shop_id BIGINT NOT NULL,
+    PRIMARY KEY (product_variation_id, location_id),
+    CONSTRAINT fk_product_variation FOREIGN KEY (product_variation_id) REFERENCES product_variations(product_variation_id) ON DELETE CASCADE,
+    CONSTRAINT fk_location FOREIGN KEY (location_id) REFERENCES locations(location_id) ON DELETE CASCADE,
+    CONSTRAINT fk_shop FOREIGN KEY (shop_id) REFERENCES shops(shop_id) ON DELETE CASCADE
+);
+CREATE INDEX idx_inventory_levels_location ON inventory_levels (location_id);
+
+ALTER TABLE stock_movements ADD CONSTRAINT fk_location FOREIGN KEY (location_id) REFERENCES locations(location_id) ON DELETE SET NULL;
+
+CREATE TYPE inventory_transfer_status AS ENUM('in_transit', 'received', 'cancelled');
+
+-- Stock moving between two locations. Items leave the origin when the transfer is
+-- created and count as incoming at the destination until it is received.
+CREATE TABLE inventory_transfers (
+    transfer_id BIGSERIAL PRIMARY KEY,
+    origin_location_id BIGINT NOT NULL,
+    destination_location_id BIGINT NOT NULL,
+    status inventory_transfer_status NOT NULL DEFAULT 'in_transit',
+    note TEXT,
+    created_by VARCHAR(255),
+    created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    updated_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    received_at TIMESTAMPTZ,
+    shop_id BIGINT NOT NULL,
+    CHECK (origin_location_id <> destination_location_id),
+    CONSTRAINT fk_origin_location FOREIGN KEY (origin_location_id) REFERENCES locations(location_id) ON DELETE CASCADE,
+    CONSTRAINT fk_destination_location FOREIGN KEY (destination_location_id) REFERENCES locations(location_id) ON DELETE CASCADE,
+    CONSTRAINT fk_shop FOREIGN KEY (shop_id) REFERENCES shops(shop_id) ON DELETE CASCADE
+);
+CREATE INDEX idx_inventory_transfers_shop ON inventory_transfers (shop_id, created_at);
+
+CREATE TABLE inventory_transfer_items (
+    transfer_id BIGINT NOT NULL,
+    product_variation_id BIGINT NOT NULL,
+    quantity BIGINT NOT NULL CHECK (quantity > 0),
+    shop_id BIGINT NOT NULL,
+    PRIMARY KEY (transfer_id, product_variation_id),
+    CONSTRAINT fk_inventory_transfer FOREIGN KEY (transfer_id) REFERENCES inventory_transfers(transfer_id) ON DELETE CASCADE,
+    CONSTRAINT fk_product_variation FOREIGN KEY (product_variation_id) REFERENCES product_variations(product_variation_id) ON DELETE CASCADE,
+    CONSTRAINT fk_shop FOREIGN KEY (shop_id) REFERENCES shops(shop_id) ON DELETE CASCADE
+);
+
+-- The location an order's stock was taken from
+CREATE TABLE order_fulfillments (
+    fulfillment_id BIGSERIAL PRIMARY KEY,
+    order_id BIGINT NOT NULL UNIQUE,
+    location_id BIGINT,
+    created_by VARCHAR(255),
+    created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    shop_id BIGINT NOT NULL,
+    CONSTRAINT fk_order FOREIGN KEY (order_id) REFERENCES orders(order_id) ON DELETE CASCADE,
+    CONSTRAINT fk_location FOREIGN KEY (location_id) REFERENCES locations(location_id) ON DELETE SET NULL,
+    CONSTRAINT fk_shop FOREIGN KEY (shop_id) REFERENCES shops(shop_id) ON DELETE CASCADE
+);
+
+-- SET RLS for locations
+ALTER TABLE locations ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON locations
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
+
+-- SET RLS for inventory_levels
+ALTER TABLE inventory_levels ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON inventory_levels
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
+
+-- SET RLS for inventory_transfers
+ALTER TABLE inventory_transfers ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON inventory_transfers
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
+
+-- SET RLS for inventory_transfer_items
+ALTER TABLE inventory_transfer_items ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON inventory_transfer_items
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
+
+-- SET RLS for order_fulfillments
+ALTER TABLE order_fulfillments ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON order_fulfillments
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
+
+-- Items of the orders still waiting to be fulfilled; their quantity is promised
+-- to the order, out of stock or on backorder
+CREATE VIEW open_order_items WITH (security_invoker = true) AS
+SELECT
+    oi.order_item_id,
+    oi.order_id,
+    oi.product_variation_id,
+    oi.quantity,
+    oi.backordered_quantity,
+    o.created_at AS ordered_at,
+    oi.shop_id
+FROM order_items oi
+JOIN orders o ON o.order_id = oi.order_id
+WHERE o.status IN ('pending', 'processing')
+AND NOT EXISTS (SELECT 1 FROM order_fulfillments f WHERE f.order_id = o.order_id);
+
+-- Stock status a variant shows on the storefront: in stock while its stock is not
+-- all promised to open orders, then on backorder or preorder when its inventory
+-- policy allows it
+CREATE FUNCTION variant_stock_status(variant product_variations) RETURNS text
+LANGUAGE sql STABLE AS $$
+    SELECT CASE
+        WHEN variant.available_quantity > open.quantity THEN 'IN_STOCK'
+        WHEN variant.inventory_policy = 'backorder' THEN 'BACKORDER'
+        WHEN variant.inventory_policy = 'preorder'
+            AND (variant.preorder_limit IS NULL OR variant.preorder_limit > open.backordered_quantity) THEN 'PREORDER'
+        ELSE 'OUT_OF_STOCK'
+    END
+    FROM (
+        SELECT COALESCE(SUM(quantity), 0) AS quantity, COALESCE(SUM(backordered_quantity), 0) AS backordered_quantity
+        FROM open_order_items
+        WHERE product_variation_id = variant.product_variation_id
+    ) open
+$$;
+
+CREATE TABLE suppliers (
+    supplier_id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    contact_name VARCHAR(100),
+    email VARCHAR(255),
+    phone VARCHAR(50),
+    address TEXT,
+    notes TEXT,
+    created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    updated_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    shop_id BIGINT NOT NULL,
+    UNIQUE (shop_id, name),
+    CONSTRAINT fk_shop FOREIGN KEY (shop_id) REFERENCES shops(shop_id) ON DELETE CASCADE
+);
+
+CREATE TYPE purchase_order_status AS ENUM('draft', 'ordered', 'partially_received', 'received');
+
+-- Stock ordered from a supplier. Received items are added to the stock of the
+-- location the order is delivered to.
+CREATE TABLE purchase_orders (
+    purchase_order_id BIGSERIAL PRIMARY KEY,
+    supplier_id BIGINT NOT NULL,
+    location_id BIGINT,
+    status purchase_order_status NOT NULL DEFAULT 'draft',
+    expected_at TIMESTAMPTZ,
+    note TEXT,
+    created_by VARCHAR(255),
+    ordered_at TIMESTAMPTZ,
+    received_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    updated_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    shop_id BIGINT NOT NULL,
+    CONSTRAINT fk_supplier FOREIGN KEY (supplier_id) REFERENCES suppliers(supplier_id) ON DELETE RESTRICT,
+    CONSTRAINT fk_location FOREIGN KEY (location_id) REFERENCES locations(location_id) ON DELETE SET NULL,
+    CONSTRAINT fk_shop FOREIGN KEY (shop_id) REFERENCES shops(shop_id) ON DELETE CASCADE
+);
+CREATE INDEX idx_purchase_orders_shop ON purchase_orders (shop_id, created_at);
+CREATE INDEX idx_purchase_orders_supplier ON purchase_orders (supplier_id);
+
+CREATE TABLE purchase_order_items (
+    purchase_order_item_id BIGSERIAL PRIMARY KEY,
+    purchase_order_id BIGINT NOT NULL,
+    product_variation_id BIGINT NOT NULL,
+    quantity_ordered BIGINT NOT NULL CHECK (quantity_ordered > 0),
+    quantity_received BIGINT NOT NULL DEFAULT 0 CHECK (quantity_received >= 0 AND quantity_received <= quantity_ordered),
+    unit_cost DECIMAL(19, 4) NOT NULL CHECK (unit_cost >= 0),
+    shop_id BIGINT NOT NULL,
+    UNIQUE (purchase_order_id, product_variation_id),
+    CONSTRAINT fk_purchase_order FOREIGN KEY (purchase_order_id) REFERENCES purchase_orders(purchase_order_id) ON DELETE CASCADE,
+    CONSTRAINT fk_product_variation FOREIGN KEY (product_variation_id) REFERENCES product_variations(product_variation_id) ON DELETE CASCADE,
+    CONSTRAINT fk_shop FOREIGN KEY (shop_id) REFERENCES shops(shop_id) ON DELETE CASCADE
+);
+
+-- SET RLS for suppliers
+ALTER TABLE suppliers ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON suppliers
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
+
+-- SET RLS for purchase_orders
+ALTER TABLE purchase_orders ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON purchase_orders
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
+
+-- SET RLS for purchase_order_items
+ALTER TABLE purchase_order_items ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON purchase_order_items
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
+
+CREATE TYPE stocktake_status AS ENUM('open', 'committed', 'cancelled');
+
+-- A count of the stock at a location, of every variant or those of a category or
+-- product type. Committing it adjusts the stock by the variances counted.
+CREATE TABLE stocktakes (
+    stocktake_id BIGSERIAL PRIMARY KEY,
+    location_id BIGINT NOT NULL,
+    category_id BIGINT, -- counts the products of the category and its subcategories
+    product_type_id BIGINT,
+    status stocktake_status NOT NULL DEFAULT 'open',
+    note TEXT,
+    created_by VARCHAR(255),
+    committed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    updated_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    shop_id BIGINT NOT NULL,
+    CONSTRAINT fk_location FOREIGN KEY (location_id) REFERENCES locations(location_id) ON DELETE CASCADE,
+    CONSTRAINT fk_category FOREIGN KEY (category_id) REFERENCES categories(category_id) ON DELETE SET NULL,
+    CONSTRAINT fk_product_type FOREIGN KEY (product_type_id) REFERENCES product_types(product_type_id) ON DELETE SET NULL,
+    CONSTRAINT fk_shop FOREIGN KEY (shop_id) REFERENCES shops(shop_id) ON DELETE CASCADE
+);
+CREATE INDEX idx_stocktakes_shop ON stocktakes (shop_id, created_at);
+
+CREATE TABLE stocktake_items (
+    stocktake_item_id BIGSERIAL PRIMARY KEY,
+    stocktake_id BIGINT NOT NULL,
+    product_variation_id BIGINT NOT NULL,
+    expected_quantity BIGINT NOT NULL DEFAULT 0, -- stock at the location when the variant was last counted
+    counted_quantity BIGINT CHECK (counted_quantity >= 0), -- NULL until the variant is counted
+    counted_at TIMESTAMPTZ,
+    shop_id BIGINT NOT NULL,
+    UNIQUE (stocktake_id, product_variation_id),
+    CONSTRAINT fk_stocktake FOREIGN KEY (stocktake_id) REFERENCES stocktakes(stocktake_id) ON DELETE CASCADE,
+    CONSTRAINT fk_product_variation FOREIGN KEY (product_variation_id) REFERENCES product_variations(product_variation_id) ON DELETE CASCADE,
+    CONSTRAINT fk_shop FOREIGN KEY (shop_id) REFERENCES shops(shop_id) ON DELETE CASCADE
+);
+
+-- SET RLS for stocktakes
+ALTER TABLE stocktakes ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON stocktakes
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
+
+-- SET RLS for stocktake_items
+ALTER TABLE stocktake_items ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON stocktake_items
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
+
+-- Stock of a variant at a location received under a lot number. The lots of a
+-- variant at a location never hold more than its inventory level; the rest of the
+-- level is stock without a lot.
+CREATE TABLE inventory_lots (
+    lot_id BIGSERIAL PRIMARY KEY,
+    product_variation_id BIGINT NOT NULL,
+    location_id BIGINT NOT NULL,
+    lot_number VARCHAR(100) NOT NULL,
+    expires_on DATE,
+    quantity BIGINT NOT NULL DEFAULT 0 CHECK (quantity >= 0),
+    received_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    updated_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    shop_id BIGINT NOT NULL,
+    UNIQUE (product_variation_id, location_id, lot_number),
+    CONSTRAINT fk_product_variation FOREIGN KEY (product_variation_id) REFERENCES product_variations(product_variation_id) ON DELETE CASCADE,
+    CONSTRAINT fk_location FOREIGN KEY (location_id) REFERENCES locations(location_id) ON DELETE CASCADE,
+    CONSTRAINT fk_shop FOREIGN KEY (shop_id) REFERENCES shops(shop_id) ON DELETE CASCADE
+);
+CREATE INDEX idx_inventory_lots_expiry ON inventory_lots (shop_id, expires_on);
+
+-- SET RLS for inventory_lots
+ALTER TABLE inventory_lots ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON inventory_lots
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
+
+-- Alerts raised when the stock of a variant falls to its reorder point, shown in
+-- the dashboard's notification feed until read
+CREATE TYPE stock_alert_kind AS ENUM('low_stock', 'out_of_stock');
+CREATE TABLE stock_alerts (
+    alert_id BIGSERIAL PRIMARY KEY,
+    product_variation_id BIGINT NOT NULL,
+    kind stock_alert_kind NOT NULL,
+    available_quantity BIGINT NOT NULL,
+    reorder_point BIGINT NOT NULL,
+    reorder_quantity BIGINT,
+    read_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    shop_id BIGINT NOT NULL,
+    CONSTRAINT fk_product_variation FOREIGN KEY (product_variation_id) REFERENCES product_variations(product_variation_id) ON DELETE CASCADE,
+    CONSTRAINT fk_shop FOREIGN KEY (shop_id) REFERENCES shops(shop_id) ON DELETE CASCADE
+);
+CREATE INDEX idx_stock_alerts_shop ON stock_alerts (shop_id, created_at);
+
+-- SET RLS for stock_alerts
+ALTER TABLE stock_alerts ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON stock_alerts
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
+
+-- The variants a bundle variant is made of. Bundles hold no stock of their own:
+-- their available quantity is the number of bundles the stock of their components
+-- makes up, and selling one takes its components from stock.
+CREATE TABLE bundle_components (
+    bundle_variation_id BIGINT NOT NULL,
+    component_variation_id BIGINT NOT NULL,
+    quantity BIGINT NOT NULL CHECK (quantity > 0),
+    shop_id BIGINT NOT NULL,
+    PRIMARY KEY (bundle_variation_id, component_variation_id),
+    CHECK (bundle_variation_id <> component_variation_id),
+    CONSTRAINT fk_bundle_variation FOREIGN KEY (bundle_variation_id) REFERENCES product_variations(product_variation_id) ON DELETE CASCADE,
+    CONSTRAINT fk_component_variation FOREIGN KEY (component_variation_id) REFERENCES product_variations(product_variation_id) ON DELETE CASCADE,
+    CONSTRAINT fk_shop FOREIGN KEY (shop_id) REFERENCES shops(shop_id) ON DELETE CASCADE
+);
+CREATE INDEX idx_bundle_components_component ON bundle_components (component_variation_id);
+
+-- SET RLS for bundle_components
+ALTER TABLE bundle_components ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON bundle_components
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
+
+-- Files and license key pools delivered to customers once an order for a variant of
+-- a digital product type is paid
+CREATE TYPE digital_asset_kind AS ENUM('file', 'license_key');
+CREATE TABLE digital_assets (
+    digital_asset_id BIGSERIAL PRIMARY KEY,
+    product_variation_id BIGINT NOT NULL,
+    kind digital_asset_kind NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    storage_key TEXT, -- key of the file in the private digital asset storage
+    file_name VARCHAR(255),
+    content_type VARCHAR(255),
+    size_bytes BIGINT,
+    download_limit INT CHECK (download_limit > 0), -- downloads allowed per order line; unlimited when NULL
+    expires_after_days INT CHECK (expires_after_days > 0), -- days the downloads of an order last; forever when NULL
+    created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    updated_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    shop_id BIGINT NOT NULL,
+    CHECK (kind <> 'file' OR storage_key IS NOT NULL),
+    CONSTRAINT fk_product_variation FOREIGN KEY (product_variation_id) REFERENCES product_variations(product_variation_id) ON DELETE CASCADE,
+    CONSTRAINT fk_shop FOREIGN KEY (shop_id) REFERENCES shops(shop_id) ON DELETE CASCADE
+);
+CREATE INDEX idx_digital_assets_variation ON digital_assets (product_variation_id);
+
+-- The pool of license keys of a license key asset. A key is allocated to one order
+-- line unit at most.
+CREATE TABLE license_keys (
+    license_key_id BIGSERIAL PRIMARY KEY,
+    digital_asset_id BIGINT NOT NULL,
+    license_key VARCHAR(255) NOT NULL,
+    allocated_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    shop_id BIGINT NOT NULL,
+    UNIQUE (digital_asset_id, license_key),
+    CONSTRAINT fk_digital_asset FOREIGN KEY (digital_asset_id) REFERENCES digital_assets(digital_asset_id) ON DELETE CASCADE,
+    CONSTRAINT fk_shop FOREIGN KEY (shop_id) REFERENCES shops(shop_id) ON DELETE CASCADE
+);
+CREATE INDEX idx_license_keys_available ON license_keys (digital_asset_id, license_key_id) WHERE allocated_at IS NULL;
+
+-- What a paid order delivered: a download of a file, or a license key. License key
+-- downloads wait without a key while the pool is empty. Assets that were delivered
+-- cannot be deleted.
+CREATE TABLE digital_downloads (
+    download_id BIGSERIAL PRIMARY KEY,
+    order_id BIGINT NOT NULL,
+    order_item_id BIGINT NOT NULL,
+    digital_asset_id BIGINT NOT NULL,
+    license_key_id BIGINT UNIQUE,
+    download_count INT NOT NULL DEFAULT 0,
+    download_limit INT,
+    expires_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    shop_id BIGINT NOT NULL,
+    CONSTRAINT fk_order FOREIGN KEY (order_id) REFERENCES orders(order_id) ON DELETE CASCADE,
+    CONSTRAINT fk_order_item FOREIGN KEY (order_item_id) REFERENCES order_items(order_item_id) ON DELETE CASCADE,
+    CONSTRAINT fk_digital_asset FOREIGN KEY (digital_asset_id) REFERENCES digital_assets(digital_asset_id),
+    CONSTRAINT fk_license_key FOREIGN KEY (license_key_id) REFERENCES license_keys(license_key_id),
+    CONSTRAINT fk_shop FOREIGN KEY (shop_id) REFERENCES shops(shop_id) ON DELETE CASCADE
+);
+CREATE INDEX idx_digital_downloads_order ON digital_downloads (order_id);
+CREATE INDEX idx_digital_downloads_asset ON digital_downloads (digital_asset_id);
+
+-- Log of the files served through signed download links
+CREATE TABLE digital_download_events (
+    event_id BIGSERIAL PRIMARY KEY,
+    download_id BIGINT NOT NULL,
+    ip_address VARCHAR(45),
+    user_agent TEXT,
+    created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    shop_id BIGINT NOT NULL,
+    CONSTRAINT fk_download FOREIGN KEY (download_id) REFERENCES digital_downloads(download_id) ON DELETE CASCADE,
+    CONSTRAINT fk_shop FOREIGN KEY (shop_id) REFERENCES shops(shop_id) ON DELETE CASCADE
+);
+CREATE INDEX idx_digital_download_events_download ON digital_download_events (download_id, created_at);
+
+-- SET RLS for digital_assets
+ALTER TABLE digital_assets ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON digital_assets
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
+
+-- SET RLS for license_keys
+ALTER TABLE license_keys ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON license_keys
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
+
+-- SET RLS for digital_downloads
+ALTER TABLE digital_downloads ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON digital_downloads
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
+
+-- SET RLS for digital_download_events
+ALTER TABLE digital_download_events ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON digital_download_events
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
+
+-- Subscribe-and-save plans offered on a variant: every interval_count interval_units
+-- the variant is ordered again for discount_percent less, after trial_days free
+CREATE TYPE subscription_interval AS ENUM('day', 'week', 'month', 'year');
+CREATE TABLE subscription_plans (
+    plan_id BIGSERIAL PRIMARY KEY,
+    product_variation_id BIGINT NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    interval_unit subscription_interval NOT NULL,
+    interval_count INT NOT NULL DEFAULT 1 CHECK (interval_count > 0),
+    discount_percent DECIMAL(5, 2) NOT NULL DEFAULT 0 CHECK (discount_percent >= 0 AND discount_percent <= 100),
+    trial_days INT NOT NULL DEFAULT 0 CHECK (trial_days >= 0),
+    active BOOLEAN NOT NULL DEFAULT TRUE, -- inactive plans keep their subscriptions but take no new ones
+    created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    updated_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    shop_id BIGINT NOT NULL,
+    CONSTRAINT fk_product_variation FOREIGN KEY (product_variation_id) REFERENCES product_variations(product_variation_id) ON DELETE CASCADE,
+    CONSTRAINT fk_shop FOREIGN KEY (shop_id) REFERENCES shops(shop_id) ON DELETE CASCADE
+);
+CREATE INDEX idx_subscription_plans_variation ON subscription_plans (product_variation_id);
+
+-- Payment methods customers saved with a payment provider to be charged without
+-- them, e.g. for subscription renewals. customer_reference and token are what the
+-- provider charges: a Stripe customer and payment method, or the email and
+-- authorization code of a Paystack card.
+CREATE TABLE customer_payment_methods (
+    customer_payment_method_id BIGSERIAL PRIMARY KEY,
+    shop_customer_id UUID NOT NULL,
+    provider payment_method_type NOT NULL,
+    customer_reference VARCHAR(255) NOT NULL,
+    token VARCHAR(255) NOT NULL,
+    brand VARCHAR(50),
+    last4 VARCHAR(4),
+    exp_month INT,
+    exp_year INT,
+    created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    shop_id BIGINT NOT NULL,
+    UNIQUE (provider, token, shop_id),
+    CONSTRAINT fk_shop_customer FOREIGN KEY (shop_customer_id) REFERENCES shop_customers(shop_customer_id) ON DELETE CASCADE,
+    CONSTRAINT fk_shop FOREIGN KEY (shop_id) REFERENCES shops(shop_id) ON DELETE CASCADE
+);
+CREATE INDEX idx_customer_payment_methods_customer ON customer_payment_methods (shop_customer_id);
+
+-- A customer's subscription to a plan. It renews at next_renewal_at by ordering the
+-- plan's variant and charging the saved payment method. Failed renewals leave the
+-- subscription past_due with the renewal order pending and are retried at
+-- next_retry_at until the retries run out and the subscription is cancelled.
+CREATE TYPE subscription_status AS ENUM('trialing', 'active', 'paused', 'past_due', 'cancelled');
+CREATE TABLE subscriptions (
+    subscription_id BIGSERIAL PRIMARY KEY,
+    shop_customer_id UUID NOT NULL,
+    plan_id BIGINT NOT NULL,
+    quantity BIGINT NOT NULL DEFAULT 1 CHECK (quantity > 0),
+    status subscription_status NOT NULL,
+    customer_payment_method_id BIGINT NOT NULL,
+    shipping_address JSONB NOT NULL, -- snapshot of the address, same shape as models.ShippingAddress
+    shipping_method VARCHAR(10) NOT NULL,
+    next_renewal_at TIMESTAMPTZ NOT NULL,
+    paused_until TIMESTAMPTZ, -- paused subscriptions resume then; indefinitely when NULL
+    pending_order_id BIGINT, -- the renewal order waiting to be paid
+    failed_attempts INT NOT NULL DEFAULT 0,
+    next_retry_at TIMESTAMPTZ,
+    claimed_until TIMESTAMPTZ, -- set while the scheduler renews the subscription
+    cancelled_at TIMESTAMPTZ,
+    cancel_reason TEXT,
+    created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    updated_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    shop_id BIGINT NOT NULL,
+    CONSTRAINT fk_shop_customer FOREIGN KEY (shop_customer_id) REFERENCES shop_customers(shop_customer_id) ON DELETE CASCADE,
+    -- Plans and payment methods in use cannot be deleted
+    CONSTRAINT fk_plan FOREIGN KEY (plan_id) REFERENCES subscription_plans(plan_id),
+    CONSTRAINT fk_customer_payment_method FOREIGN KEY (customer_payment_method_id) REFERENCES customer_payment_methods(customer_payment_method_id),
+    CONSTRAINT fk_pending_order FOREIGN KEY (pending_order_id) REFERENCES orders(order_id) ON DELETE SET NULL,
+    CONSTRAINT fk_shop FOREIGN KEY (shop_id) REFERENCES shops(shop_id) ON DELETE CASCADE
+);
+CREATE INDEX idx_subscriptions_customer ON subscriptions (shop_customer_id);
+CREATE INDEX idx_subscriptions_due ON subscriptions ((COALESCE(next_retry_at, next_renewal_at))) WHERE status IN ('trialing', 'active', 'past_due');
+CREATE INDEX idx_subscriptions_paused ON subscriptions (paused_until) WHERE status = 'paused';
+
+-- The history of a subscription's renewals: paid and failed charges, and skipped
+-- renewals
+CREATE TYPE subscription_renewal_status AS ENUM('paid', 'failed', 'skipped');
+CREATE TABLE subscription_renewals (
+    renewal_id BIGSERIAL PRIMARY KEY,
+    subscription_id BIGINT NOT NULL,
+    order_id BIGINT,
+    status subscription_renewal_status NOT NULL,
+    renewal_date TIMESTAMPTZ NOT NULL, -- the renewal date the entry is for
+    payment_reference TEXT,
+    error TEXT,
+    created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    shop_id BIGINT NOT NULL,
+    CONSTRAINT fk_subscription FOREIGN KEY (subscription_id) REFERENCES subscriptions(subscription_id) ON DELETE CASCADE,
+    CONSTRAINT fk_order FOREIGN KEY (order_id) REFERENCES orders(order_id) ON DELETE SET NULL,
+    CONSTRAINT fk_shop FOREIGN KEY (shop_id) REFERENCES shops(shop_id) ON DELETE CASCADE
+);
+CREATE INDEX idx_subscription_renewals_subscription ON subscription_renewals (subscription_id, created_at);
+
+-- SET RLS for subscription_plans
+ALTER TABLE subscription_plans ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON subscription_plans
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
+
+-- SET RLS for customer_payment_methods
+ALTER TABLE customer_payment_methods ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON customer_payment_methods
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
+
+-- SET RLS for subscriptions
+ALTER TABLE subscriptions ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON subscriptions
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
+
+-- SET RLS for subscription_renewals
+ALTER TABLE subscription_renewals ENABLE ROW LEVEL SECURITY;
+CREATE POLICY shop_policy ON subscription_renewals
+FOR ALL
+USING (shop_id = current_setting('commerce.current_shop_id')::int)
+WITH CHECK (shop_id = current_setting('commerce.current_shop_id')::int);
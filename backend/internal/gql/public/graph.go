@@ -8,12 +8,16 @@ import (
 	"github.com/petrejonn/naytife/internal/db"
 	"github.com/petrejonn/naytife/internal/gql/public/generated"
 	"github.com/petrejonn/naytife/internal/gql/public/resolver"
+	"github.com/petrejonn/naytife/internal/services"
 )
 
-func NewHandler(repo db.Repository) fiber.Handler {
+func NewHandler(repo db.Repository, currencyService *services.CurrencyService, digitalDelivery *services.DigitalDelivery, payments *services.PaymentProcessorFactory) fiber.Handler {
 	h := handler.NewDefaultServer(generated.NewExecutableSchema(generated.Config{
 		Resolvers: &resolver.Resolver{
-			Repository: repo,
+			Repository:      repo,
+			CurrencyService: currencyService,
+			DigitalDelivery: digitalDelivery,
+			Payments:        payments,
 		},
 	}))
 
@@ -8,6 +8,8 @@ import (
 	"io"
 	"strconv"
 	"time"
+
+	"github.com/petrejonn/naytife/internal/money"
 )
 
 type Node interface {
@@ -22,6 +24,82 @@ type UserError interface {
 	GetPath() []string
 }
 
+type AccountError struct {
+	Message string    `json:"message"`
+	Code    ErrorCode `json:"code"`
+	Path    []string  `json:"path"`
+}
+
+func (AccountError) IsUserError()            {}
+func (this AccountError) GetMessage() string { return this.Message }
+func (this AccountError) GetCode() ErrorCode { return this.Code }
+func (this AccountError) GetPath() []string {
+	if this.Path == nil {
+		return nil
+	}
+	interfaceSlice := make([]string, 0, len(this.Path))
+	for _, concrete := range this.Path {
+		interfaceSlice = append(interfaceSlice, concrete)
+	}
+	return interfaceSlice
+}
+
+type Address struct {
+	FirstName    string  `json:"first_name"`
+	LastName     string  `json:"last_name"`
+	Company      *string `json:"company,omitempty"`
+	AddressLine1 string  `json:"address_line1"`
+	AddressLine2 *string `json:"address_line2,omitempty"`
+	City         string  `json:"city"`
+	State        *string `json:"state,omitempty"`
+	PostalCode   string  `json:"postal_code"`
+	Country      string  `json:"country"`
+	Phone        *string `json:"phone,omitempty"`
+}
+
+type AddressError struct {
+	Message string    `json:"message"`
+	Code    ErrorCode `json:"code"`
+	Path    []string  `json:"path"`
+}
+
+func (AddressError) IsUserError()            {}
+func (this AddressError) GetMessage() string { return this.Message }
+func (this AddressError) GetCode() ErrorCode { return this.Code }
+func (this AddressError) GetPath() []string {
+	if this.Path == nil {
+		return nil
+	}
+	interfaceSlice := make([]string, 0, len(this.Path))
+	for _, concrete := range this.Path {
+		interfaceSlice = append(interfaceSlice, concrete)
+	}
+	return interfaceSlice
+}
+
+type AddressInput struct {
+	FirstName    string  `json:"first_name"`
+	LastName     string  `json:"last_name"`
+	Company      *string `json:"company,omitempty"`
+	AddressLine1 string  `json:"address_line1"`
+	AddressLine2 *string `json:"address_line2,omitempty"`
+	City         string  `json:"city"`
+	State        *string `json:"state,omitempty"`
+	PostalCode   string  `json:"postal_code"`
+	Country      string  `json:"country"`
+	Phone        *string `json:"phone,omitempty"`
+}
+
+type BackInStockInput struct {
+	ProductVariantID string  `json:"product_variant_id"`
+	Email            *string `json:"email,omitempty"`
+}
+
+type BackInStockPayload struct {
+	Subscribed bool        `json:"subscribed"`
+	Errors     []UserError `json:"errors"`
+}
+
 type Category struct {
 	ID          string             `json:"id"`
 	Slug        string             `json:"slug"`
@@ -29,6 +107,7 @@ type Category struct {
 	Description *string            `json:"description,omitempty"`
 	Products    *ProductConnection `json:"products,omitempty"`
 	Images      *CategoryImages    `json:"images,omitempty"`
+	Metafield   *Metafield         `json:"metafield,omitempty"`
 	UpdatedAt   time.Time          `json:"updated_at"`
 	CreatedAt   time.Time          `json:"created_at"`
 }
@@ -71,24 +150,50 @@ func (this CategoryNotFoundError) GetPath() []string {
 	return interfaceSlice
 }
 
+type Collection struct {
+	ID          string             `json:"id"`
+	Handle      string             `json:"handle"`
+	Title       string             `json:"title"`
+	Description *string            `json:"description,omitempty"`
+	Products    *ProductConnection `json:"products"`
+	UpdatedAt   time.Time          `json:"updated_at"`
+	CreatedAt   time.Time          `json:"created_at"`
+}
+
+func (Collection) IsNode()            {}
+func (this Collection) GetID() string { return this.ID }
+
+type CollectionConnection struct {
+	Edges      []CollectionEdge `json:"edges"`
+	PageInfo   *PageInfo        `json:"page_info"`
+	TotalCount int              `json:"total_count"`
+}
+
+type CollectionEdge struct {
+	Cursor string      `json:"cursor"`
+	Node   *Collection `json:"node"`
+}
+
 type CreateOrderInput struct {
-	CustomerID      *string                `json:"customer_id,omitempty"`
-	ShippingAddress string                 `json:"shipping_address"`
-	ShippingMethod  string                 `json:"shipping_method"`
-	PaymentMethod   PaymentMethodType      `json:"payment_method"`
-	Discount        *float64               `json:"discount,omitempty"`
-	ShippingCost    *float64               `json:"shipping_cost,omitempty"`
-	Tax             *float64               `json:"tax,omitempty"`
-	Items           []CreateOrderItemInput `json:"items"`
-	FullName        *string                `json:"full_name,omitempty"`
-	Email           *string                `json:"email,omitempty"`
-	PhoneNumber     *string                `json:"phone_number,omitempty"`
+	CustomerID          *string                `json:"customer_id,omitempty"`
+	ShippingAddress     *AddressInput          `json:"shipping_address,omitempty"`
+	ShippingAddressID   *string                `json:"shipping_address_id,omitempty"`
+	ShippingMethod      string                 `json:"shipping_method"`
+	PaymentMethod       PaymentMethodType      `json:"payment_method"`
+	Discount            *money.Money           `json:"discount,omitempty"`
+	ShippingCost        *money.Money           `json:"shipping_cost,omitempty"`
+	Tax                 *money.Money           `json:"tax,omitempty"`
+	Items               []CreateOrderItemInput `json:"items"`
+	RedeemLoyaltyPoints *int                   `json:"redeem_loyalty_points,omitempty"`
+	FullName            *string                `json:"full_name,omitempty"`
+	Email               *string                `json:"email,omitempty"`
+	PhoneNumber         *string                `json:"phone_number,omitempty"`
 }
 
 type CreateOrderItemInput struct {
-	ProductVariationID string  `json:"product_variation_id"`
-	Quantity           int     `json:"quantity"`
-	Price              float64 `json:"price"`
+	ProductVariationID string      `json:"product_variation_id"`
+	Quantity           int         `json:"quantity"`
+	Price              money.Money `json:"price"`
 }
 
 type CreateOrderPayload struct {
@@ -96,9 +201,82 @@ type CreateOrderPayload struct {
 	Errors []UserError `json:"errors"`
 }
 
+type Customer struct {
+	ID             string          `json:"id"`
+	Email          string          `json:"email"`
+	Name           *string         `json:"name,omitempty"`
+	Locale         *string         `json:"locale,omitempty"`
+	ProfilePicture *string         `json:"profile_picture,omitempty"`
+	VerifiedEmail  bool            `json:"verified_email"`
+	CreatedAt      time.Time       `json:"created_at"`
+	LoyaltyBalance *LoyaltyBalance `json:"loyalty_balance,omitempty"`
+}
+
+func (Customer) IsNode()            {}
+func (this Customer) GetID() string { return this.ID }
+
+type CustomerAddress struct {
+	ID                string    `json:"id"`
+	FirstName         string    `json:"first_name"`
+	LastName          string    `json:"last_name"`
+	Company           *string   `json:"company,omitempty"`
+	AddressLine1      string    `json:"address_line1"`
+	AddressLine2      *string   `json:"address_line2,omitempty"`
+	City              string    `json:"city"`
+	State             *string   `json:"state,omitempty"`
+	PostalCode        string    `json:"postal_code"`
+	Country           string    `json:"country"`
+	Phone             *string   `json:"phone,omitempty"`
+	IsDefaultShipping bool      `json:"is_default_shipping"`
+	IsDefaultBilling  bool      `json:"is_default_billing"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+func (CustomerAddress) IsNode()            {}
+func (this CustomerAddress) GetID() string { return this.ID }
+
+type CustomerAddressInput struct {
+	Address         *AddressInput `json:"address"`
+	DefaultShipping *bool         `json:"default_shipping,omitempty"`
+	DefaultBilling  *bool         `json:"default_billing,omitempty"`
+}
+
+type CustomerAddressPayload struct {
+	Address   *CustomerAddress  `json:"address,omitempty"`
+	Addresses []CustomerAddress `json:"addresses"`
+	Errors    []UserError       `json:"errors"`
+}
+
+type CustomerSubscription struct {
+	ID               string               `json:"id"`
+	PlanID           string               `json:"plan_id"`
+	PlanName         string               `json:"plan_name"`
+	Interval         SubscriptionInterval `json:"interval"`
+	IntervalCount    int                  `json:"interval_count"`
+	DiscountPercent  float64              `json:"discount_percent"`
+	ProductVariantID string               `json:"product_variant_id"`
+	Quantity         int                  `json:"quantity"`
+	Status           SubscriptionStatus   `json:"status"`
+	PaymentMethodID  string               `json:"payment_method_id"`
+	ShippingAddress  *Address             `json:"shipping_address"`
+	ShippingMethod   string               `json:"shipping_method"`
+	NextRenewalAt    time.Time            `json:"next_renewal_at"`
+	PausedUntil      *time.Time           `json:"paused_until,omitempty"`
+	NextRetryAt      *time.Time           `json:"next_retry_at,omitempty"`
+	CancelledAt      *time.Time           `json:"cancelled_at,omitempty"`
+	CreatedAt        time.Time            `json:"created_at"`
+}
+
+func (CustomerSubscription) IsNode()            {}
+func (this CustomerSubscription) GetID() string { return this.ID }
+
 type Image struct {
-	URL     string  `json:"url"`
-	AltText *string `json:"alt_text,omitempty"`
+	URL        string           `json:"url"`
+	AltText    *string          `json:"alt_text,omitempty"`
+	Width      *int             `json:"width,omitempty"`
+	Height     *int             `json:"height,omitempty"`
+	Renditions []ImageRendition `json:"renditions"`
 }
 
 type ImageInput struct {
@@ -106,6 +284,27 @@ type ImageInput struct {
 	AltText *string `json:"alt_text,omitempty"`
 }
 
+type ImageRendition struct {
+	URL    string `json:"url"`
+	Format string `json:"format"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+}
+
+type LoyaltyBalance struct {
+	Points       int         `json:"points"`
+	Value        money.Money `json:"value"`
+	CurrencyCode string      `json:"currency_code"`
+	ExpiresAt    *time.Time  `json:"expires_at,omitempty"`
+}
+
+type Metafield struct {
+	Namespace string `json:"namespace"`
+	Key       string `json:"key"`
+	Type      string `json:"type"`
+	Value     string `json:"value"`
+}
+
 type Mutation struct {
 }
 
@@ -116,11 +315,12 @@ type Order struct {
 	CreatedAt       time.Time          `json:"created_at"`
 	UpdatedAt       time.Time          `json:"updated_at"`
 	CustomerID      *string            `json:"customer_id,omitempty"`
-	Amount          float64            `json:"amount"`
-	Discount        float64            `json:"discount"`
-	ShippingCost    float64            `json:"shipping_cost"`
-	Tax             float64            `json:"tax"`
-	ShippingAddress string             `json:"shipping_address"`
+	Amount          money.Money        `json:"amount"`
+	Discount        money.Money        `json:"discount"`
+	ShippingCost    money.Money        `json:"shipping_cost"`
+	Tax             money.Money        `json:"tax"`
+	CurrencyCode    string             `json:"currency_code"`
+	ShippingAddress *Address           `json:"shipping_address"`
 	PaymentMethod   PaymentMethodType  `json:"payment_method"`
 	PaymentStatus   PaymentStatusType  `json:"payment_status"`
 	ShippingMethod  string             `json:"shipping_method"`
@@ -132,6 +332,7 @@ type Order struct {
 	CustomerName    string             `json:"customer_name"`
 	CustomerEmail   *string            `json:"customer_email,omitempty"`
 	CustomerPhone   *string            `json:"customer_phone,omitempty"`
+	Downloads       []OrderDownload    `json:"downloads"`
 }
 
 func (Order) IsNode()            {}
@@ -143,17 +344,29 @@ type OrderConnection struct {
 	TotalCount int         `json:"total_count"`
 }
 
+type OrderDownload struct {
+	ID            int              `json:"id"`
+	Title         string           `json:"title"`
+	Kind          DigitalAssetKind `json:"kind"`
+	FileName      *string          `json:"file_name,omitempty"`
+	URL           *string          `json:"url,omitempty"`
+	LicenseKey    *string          `json:"license_key,omitempty"`
+	DownloadCount int              `json:"download_count"`
+	DownloadLimit *int             `json:"download_limit,omitempty"`
+	ExpiresAt     *time.Time       `json:"expires_at,omitempty"`
+}
+
 type OrderEdge struct {
 	Cursor string `json:"cursor"`
 	Node   *Order `json:"node"`
 }
 
 type OrderItem struct {
-	ID                 string  `json:"id"`
-	OrderItemID        int     `json:"order_item_id"`
-	Quantity           int     `json:"quantity"`
-	Price              float64 `json:"price"`
-	ProductVariationID string  `json:"product_variation_id"`
+	ID                 string      `json:"id"`
+	OrderItemID        int         `json:"order_item_id"`
+	Quantity           int         `json:"quantity"`
+	Price              money.Money `json:"price"`
+	ProductVariationID string      `json:"product_variation_id"`
 }
 
 func (OrderItem) IsNode()            {}
@@ -186,17 +399,21 @@ type PaymentMethodInfo struct {
 }
 
 type Product struct {
-	ID             string             `json:"id"`
-	ProductID      int                `json:"product_id"`
-	Slug           string             `json:"slug"`
-	Title          string             `json:"title"`
-	Description    string             `json:"description"`
-	Attributes     []ProductAttribute `json:"attributes"`
-	DefaultVariant *ProductVariant    `json:"default_variant"`
-	Variants       []ProductVariant   `json:"variants"`
-	Images         []Image            `json:"images"`
-	UpdatedAt      time.Time          `json:"updated_at"`
-	CreatedAt      time.Time          `json:"created_at"`
+	ID             string                   `json:"id"`
+	ProductID      int                      `json:"product_id"`
+	Slug           string                   `json:"slug"`
+	Title          string                   `json:"title"`
+	Description    string                   `json:"description"`
+	Attributes     []ProductAttribute       `json:"attributes"`
+	DefaultVariant *ProductVariant          `json:"default_variant"`
+	Variants       []ProductVariant         `json:"variants"`
+	Images         []Image                  `json:"images"`
+	Tags           []string                 `json:"tags"`
+	Metafield      *Metafield               `json:"metafield,omitempty"`
+	Rating         *ProductRating           `json:"rating"`
+	Reviews        *ProductReviewConnection `json:"reviews"`
+	UpdatedAt      time.Time                `json:"updated_at"`
+	CreatedAt      time.Time                `json:"created_at"`
 }
 
 func (Product) IsNode()            {}
@@ -238,15 +455,51 @@ func (this ProductNotFoundError) GetPath() []string {
 	return interfaceSlice
 }
 
+type ProductRating struct {
+	Average float64 `json:"average"`
+	Count   int     `json:"count"`
+}
+
+type ProductReview struct {
+	ID               string       `json:"id"`
+	ProductVariantID string       `json:"product_variant_id"`
+	Rating           int          `json:"rating"`
+	Title            *string      `json:"title,omitempty"`
+	Body             *string      `json:"body,omitempty"`
+	Photos           []string     `json:"photos"`
+	CustomerName     string       `json:"customer_name"`
+	Status           ReviewStatus `json:"status"`
+	MerchantReply    *string      `json:"merchant_reply,omitempty"`
+	RepliedAt        *time.Time   `json:"replied_at,omitempty"`
+	CreatedAt        time.Time    `json:"created_at"`
+}
+
+func (ProductReview) IsNode()            {}
+func (this ProductReview) GetID() string { return this.ID }
+
+type ProductReviewConnection struct {
+	Edges      []ProductReviewEdge `json:"edges"`
+	PageInfo   *PageInfo           `json:"page_info"`
+	TotalCount int                 `json:"total_count"`
+}
+
+type ProductReviewEdge struct {
+	Cursor string         `json:"cursor"`
+	Node   *ProductReview `json:"node"`
+}
+
 type ProductVariant struct {
 	ID                string             `json:"id"`
 	VariationID       int                `json:"variation_id"`
-	Price             float64            `json:"price"`
+	Price             money.Money        `json:"price"`
+	CurrencyCode      string             `json:"currency_code"`
 	AvailableQuantity int                `json:"available_quantity"`
 	Description       string             `json:"description"`
 	IsDefault         bool               `json:"is_default"`
 	Attributes        []ProductAttribute `json:"attributes"`
 	StockStatus       ProductStockStatus `json:"stock_status"`
+	Metafield         *Metafield         `json:"metafield,omitempty"`
+	SubscriptionPlans []SubscriptionPlan `json:"subscription_plans"`
 }
 
 func (ProductVariant) IsNode()            {}
@@ -255,27 +508,76 @@ func (this ProductVariant) GetID() string { return this.ID }
 type Query struct {
 }
 
+type ReviewError struct {
+	Message string    `json:"message"`
+	Code    ErrorCode `json:"code"`
+	Path    []string  `json:"path"`
+}
+
+func (ReviewError) IsUserError()            {}
+func (this ReviewError) GetMessage() string { return this.Message }
+func (this ReviewError) GetCode() ErrorCode { return this.Code }
+func (this ReviewError) GetPath() []string {
+	if this.Path == nil {
+		return nil
+	}
+	interfaceSlice := make([]string, 0, len(this.Path))
+	for _, concrete := range this.Path {
+		interfaceSlice = append(interfaceSlice, concrete)
+	}
+	return interfaceSlice
+}
+
+type SavePaymentMethodInput struct {
+	Provider PaymentMethodType `json:"provider"`
+	Token    string            `json:"token"`
+}
+
+type SavedPaymentMethod struct {
+	ID        string            `json:"id"`
+	Provider  PaymentMethodType `json:"provider"`
+	Brand     *string           `json:"brand,omitempty"`
+	Last4     *string           `json:"last4,omitempty"`
+	ExpMonth  *int              `json:"exp_month,omitempty"`
+	ExpYear   *int              `json:"exp_year,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+func (SavedPaymentMethod) IsNode()            {}
+func (this SavedPaymentMethod) GetID() string { return this.ID }
+
+type SavedPaymentMethodPayload struct {
+	PaymentMethod  *SavedPaymentMethod  `json:"payment_method,omitempty"`
+	PaymentMethods []SavedPaymentMethod `json:"payment_methods"`
+	Errors         []UserError          `json:"errors"`
+}
+
 type Shop struct {
-	ID                   string              `json:"id"`
-	Title                string              `json:"title"`
-	DefaultDomain        string              `json:"default_domain"`
-	ContactPhone         *string             `json:"contact_phone,omitempty"`
-	ContactEmail         *string             `json:"contact_email,omitempty"`
-	Address              *ShopAddress        `json:"address"`
-	Products             *ProductConnection  `json:"products,omitempty"`
-	Categories           *CategoryConnection `json:"categories,omitempty"`
-	WhatsAppNumber       *string             `json:"whats_app_number,omitempty"`
-	WhatsAppLink         *string             `json:"whats_app_link,omitempty"`
-	FacebookLink         *string             `json:"facebook_link,omitempty"`
-	InstagramLink        *string             `json:"instagram_link,omitempty"`
-	Images               *ShopImages         `json:"images"`
-	CurrencyCode         string              `json:"currency_code"`
-	About                *string             `json:"about,omitempty"`
-	ShopProductsCategory *string             `json:"shop_products_category,omitempty"`
-	SeoDescription       *string             `json:"seo_description,omitempty"`
-	SeoKeywords          []string            `json:"seo_keywords"`
-	SeoTitle             *string             `json:"seo_title,omitempty"`
-	PaymentMethods       []PaymentMethodInfo `json:"payment_methods"`
+	ID                    string              `json:"id"`
+	Title                 string              `json:"title"`
+	DefaultDomain         string              `json:"default_domain"`
+	ContactPhone          *string             `json:"contact_phone,omitempty"`
+	ContactEmail          *string             `json:"contact_email,omitempty"`
+	Address               *ShopAddress        `json:"address"`
+	Products              *ProductConnection  `json:"products,omitempty"`
+	Categories            *CategoryConnection `json:"categories,omitempty"`
+	WhatsAppNumber        *string             `json:"whats_app_number,omitempty"`
+	WhatsAppLink          *string             `json:"whats_app_link,omitempty"`
+	FacebookLink          *string             `json:"facebook_link,omitempty"`
+	InstagramLink         *string             `json:"instagram_link,omitempty"`
+	Images                *ShopImages         `json:"images"`
+	CurrencyCode          string              `json:"currency_code"`
+	PresentmentCurrencies []string            `json:"presentment_currencies"`
+	About                 *string             `json:"about,omitempty"`
+	ShopProductsCategory  *string             `json:"shop_products_category,omitempty"`
+	SeoDescription        *string             `json:"seo_description,omitempty"`
+	SeoKeywords           []string            `json:"seo_keywords"`
+	SeoTitle              *string             `json:"seo_title,omitempty"`
+	PaymentMethods        []PaymentMethodInfo `json:"payment_methods"`
+	Metafield             *Metafield          `json:"metafield,omitempty"`
+	Locale                string              `json:"locale"`
+	DefaultLocale         string              `json:"default_locale"`
+	Locales               []string            `json:"locales"`
 }
 
 func (Shop) IsNode()            {}
@@ -299,6 +601,68 @@ type ShopImages struct {
 	CoverImageDark *Image `json:"cover_image_dark,omitempty"`
 }
 
+type SubmitProductReviewInput struct {
+	OrderID          string   `json:"order_id"`
+	ProductVariantID string   `json:"product_variant_id"`
+	Email            string   `json:"email"`
+	Rating           int      `json:"rating"`
+	Title            *string  `json:"title,omitempty"`
+	Body             *string  `json:"body,omitempty"`
+	Photos           []string `json:"photos,omitempty"`
+	Name             *string  `json:"name,omitempty"`
+}
+
+type SubmitProductReviewPayload struct {
+	Review *ProductReview `json:"review,omitempty"`
+	Errors []UserError    `json:"errors"`
+}
+
+type SubscribeInput struct {
+	PlanID            string        `json:"plan_id"`
+	Quantity          *int          `json:"quantity,omitempty"`
+	PaymentMethodID   string        `json:"payment_method_id"`
+	ShippingAddressID *string       `json:"shipping_address_id,omitempty"`
+	ShippingAddress   *AddressInput `json:"shipping_address,omitempty"`
+	ShippingMethod    string        `json:"shipping_method"`
+}
+
+type SubscriptionError struct {
+	Message string    `json:"message"`
+	Code    ErrorCode `json:"code"`
+	Path    []string  `json:"path"`
+}
+
+func (SubscriptionError) IsUserError()            {}
+func (this SubscriptionError) GetMessage() string { return this.Message }
+func (this SubscriptionError) GetCode() ErrorCode { return this.Code }
+func (this SubscriptionError) GetPath() []string {
+	if this.Path == nil {
+		return nil
+	}
+	interfaceSlice := make([]string, 0, len(this.Path))
+	for _, concrete := range this.Path {
+		interfaceSlice = append(interfaceSlice, concrete)
+	}
+	return interfaceSlice
+}
+
+type SubscriptionPayload struct {
+	Subscription *CustomerSubscription `json:"subscription,omitempty"`
+	Errors       []UserError           `json:"errors"`
+}
+
+type SubscriptionPlan struct {
+	ID              string               `json:"id"`
+	Name            string               `json:"name"`
+	Interval        SubscriptionInterval `json:"interval"`
+	IntervalCount   int                  `json:"interval_count"`
+	DiscountPercent float64              `json:"discount_percent"`
+	TrialDays       int                  `json:"trial_days"`
+}
+
+func (SubscriptionPlan) IsNode()            {}
+func (this SubscriptionPlan) GetID() string { return this.ID }
+
 type UpdateOrderStatusInput struct {
 	OrderID string          `json:"order_id"`
 	Status  OrderStatusType `json:"status"`
@@ -309,6 +673,108 @@ type UpdateOrderStatusPayload struct {
 	Errors []UserError `json:"errors"`
 }
 
+type UpdateProfileInput struct {
+	Name           *string `json:"name,omitempty"`
+	Locale         *string `json:"locale,omitempty"`
+	ProfilePicture *string `json:"profile_picture,omitempty"`
+}
+
+type UpdateProfilePayload struct {
+	Customer *Customer   `json:"customer,omitempty"`
+	Errors   []UserError `json:"errors"`
+}
+
+type UpdateSubscriptionInput struct {
+	Quantity        *int    `json:"quantity,omitempty"`
+	PaymentMethodID *string `json:"payment_method_id,omitempty"`
+}
+
+type WishlistError struct {
+	Message string    `json:"message"`
+	Code    ErrorCode `json:"code"`
+	Path    []string  `json:"path"`
+}
+
+func (WishlistError) IsUserError()            {}
+func (this WishlistError) GetMessage() string { return this.Message }
+func (this WishlistError) GetCode() ErrorCode { return this.Code }
+func (this WishlistError) GetPath() []string {
+	if this.Path == nil {
+		return nil
+	}
+	interfaceSlice := make([]string, 0, len(this.Path))
+	for _, concrete := range this.Path {
+		interfaceSlice = append(interfaceSlice, concrete)
+	}
+	return interfaceSlice
+}
+
+type WishlistItem struct {
+	ProductVariantID string    `json:"product_variant_id"`
+	Product          *Product  `json:"product"`
+	AddedAt          time.Time `json:"added_at"`
+}
+
+type WishlistPayload struct {
+	Items  []WishlistItem `json:"items"`
+	Errors []UserError    `json:"errors"`
+}
+
+type DigitalAssetKind string
+
+const (
+	DigitalAssetKindFile       DigitalAssetKind = "FILE"
+	DigitalAssetKindLicenseKey DigitalAssetKind = "LICENSE_KEY"
+)
+
+var AllDigitalAssetKind = []DigitalAssetKind{
+	DigitalAssetKindFile,
+	DigitalAssetKindLicenseKey,
+}
+
+func (e DigitalAssetKind) IsValid() bool {
+	switch e {
+	case DigitalAssetKindFile, DigitalAssetKindLicenseKey:
+		return true
+	}
+	return false
+}
+
+func (e DigitalAssetKind) String() string {
+	return string(e)
+}
+
+func (e *DigitalAssetKind) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = DigitalAssetKind(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid DigitalAssetKind", str)
+	}
+	return nil
+}
+
+func (e DigitalAssetKind) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *DigitalAssetKind) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e DigitalAssetKind) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
 type ErrorCode string
 
 const (
@@ -558,18 +1024,20 @@ type ProductStockStatus string
 const (
 	ProductStockStatusInStock    ProductStockStatus = "IN_STOCK"
 	ProductStockStatusOutOfStock ProductStockStatus = "OUT_OF_STOCK"
+	ProductStockStatusBackorder  ProductStockStatus = "BACKORDER"
 	ProductStockStatusPreorder   ProductStockStatus = "PREORDER"
 )
 
 var AllProductStockStatus = []ProductStockStatus{
 	ProductStockStatusInStock,
 	ProductStockStatusOutOfStock,
+	ProductStockStatusBackorder,
 	ProductStockStatusPreorder,
 }
 
 func (e ProductStockStatus) IsValid() bool {
 	switch e {
-	case ProductStockStatusInStock, ProductStockStatusOutOfStock, ProductStockStatusPreorder:
+	case ProductStockStatusInStock, ProductStockStatusOutOfStock, ProductStockStatusBackorder, ProductStockStatusPreorder:
 		return true
 	}
 	return false
@@ -610,6 +1078,63 @@ func (e ProductStockStatus) MarshalJSON() ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
+type ReviewStatus string
+
+const (
+	ReviewStatusPending  ReviewStatus = "PENDING"
+	ReviewStatusApproved ReviewStatus = "APPROVED"
+	ReviewStatusRejected ReviewStatus = "REJECTED"
+)
+
+var AllReviewStatus = []ReviewStatus{
+	ReviewStatusPending,
+	ReviewStatusApproved,
+	ReviewStatusRejected,
+}
+
+func (e ReviewStatus) IsValid() bool {
+	switch e {
+	case ReviewStatusPending, ReviewStatusApproved, ReviewStatusRejected:
+		return true
+	}
+	return false
+}
+
+func (e ReviewStatus) String() string {
+	return string(e)
+}
+
+func (e *ReviewStatus) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ReviewStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ReviewStatus", str)
+	}
+	return nil
+}
+
+func (e ReviewStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *ReviewStatus) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e ReviewStatus) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
 type ShippingStatusType string
 
 const (
@@ -670,3 +1195,123 @@ func (e ShippingStatusType) MarshalJSON() ([]byte, error) {
 	e.MarshalGQL(&buf)
 	return buf.Bytes(), nil
 }
+
+type SubscriptionInterval string
+
+const (
+	SubscriptionIntervalDay   SubscriptionInterval = "DAY"
+	SubscriptionIntervalWeek  SubscriptionInterval = "WEEK"
+	SubscriptionIntervalMonth SubscriptionInterval = "MONTH"
+	SubscriptionIntervalYear  SubscriptionInterval = "YEAR"
+)
+
+var AllSubscriptionInterval = []SubscriptionInterval{
+	SubscriptionIntervalDay,
+	SubscriptionIntervalWeek,
+	SubscriptionIntervalMonth,
+	SubscriptionIntervalYear,
+}
+
+func (e SubscriptionInterval) IsValid() bool {
+	switch e {
+	case SubscriptionIntervalDay, SubscriptionIntervalWeek, SubscriptionIntervalMonth, SubscriptionIntervalYear:
+		return true
+	}
+	return false
+}
+
+func (e SubscriptionInterval) String() string {
+	return string(e)
+}
+
+func (e *SubscriptionInterval) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = SubscriptionInterval(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid SubscriptionInterval", str)
+	}
+	return nil
+}
+
+func (e SubscriptionInterval) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *SubscriptionInterval) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e SubscriptionInterval) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type SubscriptionStatus string
+
+const (
+	SubscriptionStatusTrialing  SubscriptionStatus = "TRIALING"
+	SubscriptionStatusActive    SubscriptionStatus = "ACTIVE"
+	SubscriptionStatusPaused    SubscriptionStatus = "PAUSED"
+	SubscriptionStatusPastDue   SubscriptionStatus = "PAST_DUE"
+	SubscriptionStatusCancelled SubscriptionStatus = "CANCELLED"
+)
+
+var AllSubscriptionStatus = []SubscriptionStatus{
+	SubscriptionStatusTrialing,
+	SubscriptionStatusActive,
+	SubscriptionStatusPaused,
+	SubscriptionStatusPastDue,
+	SubscriptionStatusCancelled,
+}
+
+func (e SubscriptionStatus) IsValid() bool {
+	switch e {
+	case SubscriptionStatusTrialing, SubscriptionStatusActive, SubscriptionStatusPaused, SubscriptionStatusPastDue, SubscriptionStatusCancelled:
+		return true
+	}
+	return false
+}
+
+func (e SubscriptionStatus) String() string {
+	return string(e)
+}
+
+func (e *SubscriptionStatus) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = SubscriptionStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid SubscriptionStatus", str)
+	}
+	return nil
+}
+
+func (e SubscriptionStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *SubscriptionStatus) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e SubscriptionStatus) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
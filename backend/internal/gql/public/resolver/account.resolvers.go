@@ -0,0 +1,147 @@
+package resolver
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.76
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	pgx "github.com/jackc/pgx/v5"
+	"github.com/petrejonn/naytife/internal/db"
+	"github.com/petrejonn/naytife/internal/gql/public/generated"
+	"github.com/petrejonn/naytife/internal/gql/public/model"
+	"github.com/petrejonn/naytife/internal/services"
+)
+
+// LoyaltyBalance is the resolver for the loyaltyBalance field.
+func (r *customerResolver) LoyaltyBalance(ctx context.Context, obj *model.Customer) (*model.LoyaltyBalance, error) {
+	shopID := ctx.Value("shop_id").(int64)
+	// Only the signed-in customer sees their own points
+	customerID, ok := currentCustomer(ctx)
+	if !ok || obj.ID != EncodeUUIDID("Customer", customerID) {
+		return nil, nil
+	}
+
+	program, err := services.LoyaltyProgram(ctx, r.Repository, shopID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch loyalty program: %w", err)
+	}
+	if !program.Enabled {
+		return nil, nil
+	}
+	currency, err := r.shopCurrency(ctx, shopID)
+	if err != nil {
+		return nil, err
+	}
+	balance, err := services.CustomerLoyaltyBalance(ctx, r.Repository, program, customerID, currency)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch loyalty points: %w", err)
+	}
+	return &model.LoyaltyBalance{
+		Points:       int(balance.Points),
+		Value:        balance.Value,
+		CurrencyCode: currency,
+		ExpiresAt:    balance.ExpiresAt,
+	}, nil
+}
+
+// UpdateProfile is the resolver for the updateProfile field.
+func (r *mutationResolver) UpdateProfile(ctx context.Context, input model.UpdateProfileInput) (*model.UpdateProfilePayload, error) {
+	shopID := ctx.Value("shop_id").(int64)
+	fail := func(message string, code model.ErrorCode, field string) *model.UpdateProfilePayload {
+		return &model.UpdateProfilePayload{
+			Errors: []model.UserError{&model.AccountError{Message: message, Code: code, Path: []string{"input", field}}},
+		}
+	}
+
+	customerID, ok := currentCustomer(ctx)
+	if !ok {
+		return &model.UpdateProfilePayload{Errors: []model.UserError{&model.AccountError{
+			Message: "Sign in to update your profile",
+			Code:    model.ErrorCodeAuthInvalidToken,
+			Path:    []string{"input"},
+		}}}, nil
+	}
+
+	params := db.UpdateCustomerParams{
+		ShopCustomerID: customerID,
+		ShopID:         shopID,
+	}
+	if input.Name != nil {
+		name := strings.TrimSpace(*input.Name)
+		if len(name) < 3 || len(name) > 255 {
+			return fail("Name must be between 3 and 255 characters", model.ErrorCodeValidationInvalidInput, "name"), nil
+		}
+		params.Name = &name
+	}
+	if input.Locale != nil {
+		locale, err := services.NormalizeLocale(*input.Locale)
+		if err != nil {
+			return fail("Invalid locale", model.ErrorCodeValidationInvalidInput, "locale"), nil
+		}
+		params.Locale = &locale
+	}
+	if input.ProfilePicture != nil {
+		u, err := url.Parse(*input.ProfilePicture)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fail("Profile picture must be an http or https URL", model.ErrorCodeValidationInvalidInput, "profilePicture"), nil
+		}
+		params.ProfilePicture = input.ProfilePicture
+	}
+
+	customer, err := r.Repository.UpdateCustomer(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update profile: %w", err)
+	}
+	return &model.UpdateProfilePayload{Customer: customerFromDB(customer), Errors: []model.UserError{}}, nil
+}
+
+// Me is the resolver for the me field.
+func (r *queryResolver) Me(ctx context.Context) (*model.Customer, error) {
+	shopID := ctx.Value("shop_id").(int64)
+	customerID, ok := currentCustomer(ctx)
+	if !ok {
+		return nil, nil
+	}
+
+	customer, err := r.Repository.GetCustomerById(ctx, db.GetCustomerByIdParams{
+		ShopCustomerID: customerID,
+		ShopID:         shopID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to fetch customer: %w", err)
+	}
+	return customerFromDB(customer), nil
+}
+
+// MyOrders is the resolver for the myOrders field.
+func (r *queryResolver) MyOrders(ctx context.Context, first *int, after *string) (*model.OrderConnection, error) {
+	return r.customerOrders(ctx, first, after)
+}
+
+// MyAddresses is the resolver for the myAddresses field.
+func (r *queryResolver) MyAddresses(ctx context.Context) ([]model.CustomerAddress, error) {
+	shopID := ctx.Value("shop_id").(int64)
+	customerID, ok := currentCustomer(ctx)
+	if !ok {
+		return nil, errors.New("sign in to view addresses")
+	}
+	return r.customerAddresses(ctx, shopID, customerID)
+}
+
+// Customer returns generated.CustomerResolver implementation.
+func (r *Resolver) Customer() generated.CustomerResolver { return &customerResolver{r} }
+
+// Mutation returns generated.MutationResolver implementation.
+func (r *Resolver) Mutation() generated.MutationResolver { return &mutationResolver{r} }
+
+type customerResolver struct{ *Resolver }
+type mutationResolver struct{ *Resolver }
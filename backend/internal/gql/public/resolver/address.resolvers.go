@@ -0,0 +1,145 @@
+package resolver
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.76
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	pgx "github.com/jackc/pgx/v5"
+	"github.com/petrejonn/naytife/internal/db"
+	"github.com/petrejonn/naytife/internal/gql/public/model"
+)
+
+// CreateCustomerAddress is the resolver for the createCustomerAddress field.
+func (r *mutationResolver) CreateCustomerAddress(ctx context.Context, input model.CustomerAddressInput) (*model.CustomerAddressPayload, error) {
+	shopID := ctx.Value("shop_id").(int64)
+	customerID, ok := currentCustomer(ctx)
+	if !ok {
+		return addressPayloadError("Sign in to manage addresses", model.ErrorCodeAuthInvalidToken, "input"), nil
+	}
+	address, userErr := shippingAddressFromInput(*input.Address, "input", "address")
+	if userErr != nil {
+		return &model.CustomerAddressPayload{Addresses: []model.CustomerAddress{}, Errors: []model.UserError{userErr}}, nil
+	}
+
+	created, err := r.Repository.CreateCustomerAddress(ctx, db.CreateCustomerAddressParams{
+		ShopCustomerID:    customerID,
+		FirstName:         address.FirstName,
+		LastName:          address.LastName,
+		Company:           address.Company,
+		AddressLine1:      address.AddressLine1,
+		AddressLine2:      address.AddressLine2,
+		City:              address.City,
+		State:             address.State,
+		PostalCode:        address.PostalCode,
+		Country:           address.Country,
+		Phone:             address.Phone,
+		IsDefaultShipping: input.DefaultShipping != nil && *input.DefaultShipping,
+		IsDefaultBilling:  input.DefaultBilling != nil && *input.DefaultBilling,
+		ShopID:            shopID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create address: %w", err)
+	}
+	return r.customerAddressPayload(ctx, shopID, customerID, &created)
+}
+
+// UpdateCustomerAddress is the resolver for the updateCustomerAddress field.
+func (r *mutationResolver) UpdateCustomerAddress(ctx context.Context, id string, input model.CustomerAddressInput) (*model.CustomerAddressPayload, error) {
+	shopID := ctx.Value("shop_id").(int64)
+	customerID, ok := currentCustomer(ctx)
+	if !ok {
+		return addressPayloadError("Sign in to manage addresses", model.ErrorCodeAuthInvalidToken, "input"), nil
+	}
+	addressID, err := DecodeRelayID(id)
+	if err != nil || addressID.Type != "CustomerAddress" || addressID.IntID == nil {
+		return addressPayloadError("Invalid address ID", model.ErrorCodeValidationInvalidInput, "id"), nil
+	}
+	address, userErr := shippingAddressFromInput(*input.Address, "input", "address")
+	if userErr != nil {
+		return &model.CustomerAddressPayload{Addresses: []model.CustomerAddress{}, Errors: []model.UserError{userErr}}, nil
+	}
+
+	updated, err := r.Repository.UpdateCustomerAddress(ctx, db.UpdateCustomerAddressParams{
+		FirstName:         address.FirstName,
+		LastName:          address.LastName,
+		Company:           address.Company,
+		AddressLine1:      address.AddressLine1,
+		AddressLine2:      address.AddressLine2,
+		City:              address.City,
+		State:             address.State,
+		PostalCode:        address.PostalCode,
+		Country:           address.Country,
+		Phone:             address.Phone,
+		IsDefaultShipping: input.DefaultShipping != nil && *input.DefaultShipping,
+		IsDefaultBilling:  input.DefaultBilling != nil && *input.DefaultBilling,
+		AddressID:         *addressID.IntID,
+		ShopCustomerID:    customerID,
+		ShopID:            shopID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return addressPayloadError("Address not found", model.ErrorCodeValidationInvalidInput, "id"), nil
+		}
+		return nil, fmt.Errorf("failed to update address: %w", err)
+	}
+	return r.customerAddressPayload(ctx, shopID, customerID, &updated)
+}
+
+// DeleteCustomerAddress is the resolver for the deleteCustomerAddress field.
+func (r *mutationResolver) DeleteCustomerAddress(ctx context.Context, id string) (*model.CustomerAddressPayload, error) {
+	shopID := ctx.Value("shop_id").(int64)
+	customerID, ok := currentCustomer(ctx)
+	if !ok {
+		return addressPayloadError("Sign in to manage addresses", model.ErrorCodeAuthInvalidToken, "id"), nil
+	}
+	addressID, err := DecodeRelayID(id)
+	if err != nil || addressID.Type != "CustomerAddress" || addressID.IntID == nil {
+		return addressPayloadError("Invalid address ID", model.ErrorCodeValidationInvalidInput, "id"), nil
+	}
+
+	deleted, err := r.Repository.DeleteCustomerAddress(ctx, db.DeleteCustomerAddressParams{
+		AddressID:      *addressID.IntID,
+		ShopCustomerID: customerID,
+		ShopID:         shopID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete address: %w", err)
+	}
+	if deleted == 0 {
+		return addressPayloadError("Address not found", model.ErrorCodeValidationInvalidInput, "id"), nil
+	}
+	return r.customerAddressPayload(ctx, shopID, customerID, nil)
+}
+
+// SetDefaultCustomerAddress is the resolver for the setDefaultCustomerAddress field.
+func (r *mutationResolver) SetDefaultCustomerAddress(ctx context.Context, id string, shipping *bool, billing *bool) (*model.CustomerAddressPayload, error) {
+	shopID := ctx.Value("shop_id").(int64)
+	customerID, ok := currentCustomer(ctx)
+	if !ok {
+		return addressPayloadError("Sign in to manage addresses", model.ErrorCodeAuthInvalidToken, "id"), nil
+	}
+	addressID, err := DecodeRelayID(id)
+	if err != nil || addressID.Type != "CustomerAddress" || addressID.IntID == nil {
+		return addressPayloadError("Invalid address ID", model.ErrorCodeValidationInvalidInput, "id"), nil
+	}
+
+	updated, err := r.Repository.SetCustomerDefaultAddress(ctx, db.SetCustomerDefaultAddressParams{
+		DefaultShipping: shipping != nil && *shipping,
+		DefaultBilling:  billing != nil && *billing,
+		AddressID:       *addressID.IntID,
+		ShopCustomerID:  customerID,
+		ShopID:          shopID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return addressPayloadError("Address not found", model.ErrorCodeValidationInvalidInput, "id"), nil
+		}
+		return nil, fmt.Errorf("failed to set default address: %w", err)
+	}
+	return r.customerAddressPayload(ctx, shopID, customerID, &updated)
+}
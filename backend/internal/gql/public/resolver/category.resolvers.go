@@ -21,8 +21,26 @@ func (r *categoryResolver) ID(ctx context.Context, obj *model.Category) (string,
 	return EncodeStringID("Category", obj.ID), nil
 }
 
+// Title is the resolver for the title field.
+func (r *categoryResolver) Title(ctx context.Context, obj *model.Category) (string, error) {
+	categoryID, err := strconv.ParseInt(obj.ID, 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("invalid category ID: %w", err)
+	}
+	return r.localize(ctx, db.TranslatableResourceTypeCategory, categoryID, "title", obj.Title)
+}
+
+// Description is the resolver for the description field.
+func (r *categoryResolver) Description(ctx context.Context, obj *model.Category) (*string, error) {
+	categoryID, err := strconv.ParseInt(obj.ID, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid category ID: %w", err)
+	}
+	return r.localizeOptional(ctx, db.TranslatableResourceTypeCategory, categoryID, "description", obj.Description)
+}
+
 // Products is the resolver for the products field.
-func (r *categoryResolver) Products(ctx context.Context, obj *model.Category, first *int, after *string) (*model.ProductConnection, error) {
+func (r *categoryResolver) Products(ctx context.Context, obj *model.Category, first *int, after *string, currency *string) (*model.ProductConnection, error) {
 	shopID := ctx.Value("shop_id").(int64)
 	limit := 20
 	if first != nil {
@@ -128,6 +146,10 @@ func (r *categoryResolver) Products(ctx context.Context, obj *model.Category, fi
 		edges[i] = model.ProductEdge{Cursor: relayID, Node: product}
 	}
 
+	if err := r.presentProductEdges(ctx, currency, edges); err != nil {
+		return nil, err
+	}
+
 	var startCursor, endCursor string
 	if len(productsDB) > 0 {
 		startCursor = EncodeIntID("Product", productsDB[0].ProductID)
@@ -148,7 +170,34 @@ func (r *categoryResolver) Products(ctx context.Context, obj *model.Category, fi
 
 // Images is the resolver for the images field.
 func (r *categoryResolver) Images(ctx context.Context, obj *model.Category) (*model.CategoryImages, error) {
-	panic(fmt.Errorf("not implemented: Images - images"))
+	shopID := ctx.Value("shop_id").(int64)
+	categoryID, err := strconv.ParseInt(obj.ID, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid category ID: %w", err)
+	}
+
+	cat, err := r.Repository.GetCategory(ctx, db.GetCategoryParams{ShopID: shopID, CategoryID: categoryID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch category: %w", err)
+	}
+	if cat.BannerUrl == nil {
+		return nil, nil
+	}
+
+	banner := &model.Image{URL: *cat.BannerUrl}
+	if err := r.attachImageRenditions(ctx, shopID, banner); err != nil {
+		return nil, err
+	}
+	return &model.CategoryImages{Banner: banner}, nil
+}
+
+// Metafield is the resolver for the metafield field.
+func (r *categoryResolver) Metafield(ctx context.Context, obj *model.Category, namespace string, key string) (*model.Metafield, error) {
+	categoryID, err := strconv.ParseInt(obj.ID, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid category ID: %w", err)
+	}
+	return r.getMetafield(ctx, db.MetafieldOwnerTypeCategory, categoryID, namespace, key)
 }
 
 // Categories is the resolver for the categories field.
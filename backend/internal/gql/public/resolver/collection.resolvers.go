@@ -0,0 +1,204 @@
+package resolver
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.76
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	pgx "github.com/jackc/pgx/v5"
+	"github.com/petrejonn/naytife/internal/db"
+	"github.com/petrejonn/naytife/internal/gql/public/generated"
+	"github.com/petrejonn/naytife/internal/gql/public/model"
+)
+
+// ID is the resolver for the id field.
+func (r *collectionResolver) ID(ctx context.Context, obj *model.Collection) (string, error) {
+	return EncodeStringID("Collection", obj.ID), nil
+}
+
+// Title is the resolver for the title field.
+func (r *collectionResolver) Title(ctx context.Context, obj *model.Collection) (string, error) {
+	collectionID, err := strconv.ParseInt(obj.ID, 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("invalid collection ID: %w", err)
+	}
+	return r.localize(ctx, db.TranslatableResourceTypeCollection, collectionID, "title", obj.Title)
+}
+
+// Description is the resolver for the description field.
+func (r *collectionResolver) Description(ctx context.Context, obj *model.Collection) (*string, error) {
+	collectionID, err := strconv.ParseInt(obj.ID, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid collection ID: %w", err)
+	}
+	return r.localizeOptional(ctx, db.TranslatableResourceTypeCollection, collectionID, "description", obj.Description)
+}
+
+// Products is the resolver for the products field.
+func (r *collectionResolver) Products(ctx context.Context, obj *model.Collection, first *int, after *string, currency *string) (*model.ProductConnection, error) {
+	shopID := ctx.Value("shop_id").(int64)
+	limit := 20
+	if first != nil {
+		limit = *first
+	}
+
+	collectionID, err := strconv.ParseInt(obj.ID, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid collection ID: %w", err)
+	}
+
+	// Collections are ordered by their sort order rather than by ID, so the
+	// cursor carries the offset of the product within the collection
+	offset := int64(0)
+	if after != nil && *after != "" {
+		decodedType, pos, err := decodeRelayID(*after)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cursor format: %w", err)
+		}
+		if decodedType != "CollectionProduct" {
+			return nil, fmt.Errorf("expected cursor type 'CollectionProduct', got '%s'", decodedType)
+		}
+		if pos != nil {
+			offset = *pos
+		}
+	}
+
+	rows, err := r.Repository.GetCollectionProducts(ctx, db.GetCollectionProductsParams{
+		ShopID:       shopID,
+		CollectionID: collectionID,
+		Limit:        int32(limit) + 1,
+		Offset:       int32(offset),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch collection products: %w", err)
+	}
+
+	hasNextPage := len(rows) > limit
+	if hasNextPage {
+		rows = rows[:limit]
+	}
+
+	totalCount := 0
+	edges := make([]model.ProductEdge, len(rows))
+	for i, row := range rows {
+		totalCount = int(row.TotalCount)
+		details, err := r.Repository.GetProduct(ctx, db.GetProductParams{
+			ProductID: row.ProductID,
+			ShopID:    shopID,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch product details for product %d: %w", row.ProductID, err)
+		}
+		product, err := productFromRow(details)
+		if err != nil {
+			return nil, err
+		}
+		edges[i] = model.ProductEdge{
+			Cursor: EncodeIntID("CollectionProduct", offset+int64(i)+1),
+			Node:   product,
+		}
+	}
+
+	if err := r.presentProductEdges(ctx, currency, edges); err != nil {
+		return nil, err
+	}
+
+	var startCursor, endCursor string
+	if len(edges) > 0 {
+		startCursor = edges[0].Cursor
+		endCursor = edges[len(edges)-1].Cursor
+	}
+
+	return &model.ProductConnection{
+		Edges: edges,
+		PageInfo: &model.PageInfo{
+			HasNextPage:     hasNextPage,
+			HasPreviousPage: offset > 0,
+			StartCursor:     startCursor,
+			EndCursor:       endCursor,
+		},
+		TotalCount: totalCount,
+	}, nil
+}
+
+// Collections is the resolver for the collections field.
+func (r *queryResolver) Collections(ctx context.Context, first *int, after *string) (*model.CollectionConnection, error) {
+	shopID := ctx.Value("shop_id").(int64)
+	limit := 20
+	if first != nil {
+		limit = *first
+	}
+	afterID := int64(0)
+	if after != nil && *after != "" {
+		decodedType, id, err := decodeRelayID(*after)
+		if err != nil {
+			return nil, fmt.Errorf("invalid after cursor: %w", err)
+		}
+		if decodedType != "Collection" {
+			return nil, fmt.Errorf("expected after cursor type 'Collection', got '%s'", decodedType)
+		}
+		if id != nil {
+			afterID = *id
+		}
+	}
+
+	collectionsDB, err := r.Repository.GetCollections(ctx, db.GetCollectionsParams{ShopID: shopID, After: afterID, Limit: int32(limit) + 1})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch collections: %w", err)
+	}
+	totalCount, err := r.Repository.CountCollections(ctx, shopID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count collections: %w", err)
+	}
+
+	hasNextPage := len(collectionsDB) > limit
+	if hasNextPage {
+		collectionsDB = collectionsDB[:limit]
+	}
+	edges := make([]model.CollectionEdge, len(collectionsDB))
+	for i, collection := range collectionsDB {
+		edges[i] = model.CollectionEdge{
+			Cursor: EncodeIntID("Collection", collection.CollectionID),
+			Node:   collectionFromDB(collection),
+		}
+	}
+
+	var startCursor, endCursor string
+	if len(edges) > 0 {
+		startCursor = edges[0].Cursor
+		endCursor = edges[len(edges)-1].Cursor
+	}
+
+	return &model.CollectionConnection{
+		Edges: edges,
+		PageInfo: &model.PageInfo{
+			HasNextPage: hasNextPage,
+			StartCursor: startCursor,
+			EndCursor:   endCursor,
+		},
+		TotalCount: int(totalCount),
+	}, nil
+}
+
+// Collection is the resolver for the collection field.
+func (r *queryResolver) Collection(ctx context.Context, handle string) (*model.Collection, error) {
+	shopID := ctx.Value("shop_id").(int64)
+	collection, err := r.Repository.GetCollectionByHandle(ctx, db.GetCollectionByHandleParams{Handle: handle, ShopID: shopID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to fetch collection: %w", err)
+	}
+	return collectionFromDB(collection), nil
+}
+
+// Collection returns generated.CollectionResolver implementation.
+func (r *Resolver) Collection() generated.CollectionResolver { return &collectionResolver{r} }
+
+type collectionResolver struct{ *Resolver }
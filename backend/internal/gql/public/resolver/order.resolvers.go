@@ -8,13 +8,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"math/big"
+	"math"
 	"strings"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/petrejonn/naytife/internal/db"
 	"github.com/petrejonn/naytife/internal/gql/public/generated"
 	"github.com/petrejonn/naytife/internal/gql/public/model"
+	"github.com/petrejonn/naytife/internal/money"
+	"github.com/petrejonn/naytife/internal/services"
 )
 
 // CreateOrder is the resolver for the createOrder field.
@@ -30,30 +33,128 @@ func (r *mutationResolver) CreateOrder(ctx context.Context, input model.CreateOr
 		}, nil
 	}
 
-	// Calculate the total amount from items
-	var total float64
-	for _, item := range input.Items {
-		total += float64(item.Quantity) * item.Price
+	currency, err := r.shopCurrency(ctx, shopID)
+	if err != nil {
+		return &model.CreateOrderPayload{
+			Errors: []model.UserError{&model.CategoryNotFoundError{
+				Message: "Failed to create order: " + err.Error(),
+				Code:    model.ErrorCodeServerErrorInternal,
+			}},
+		}, nil
 	}
 
-	// Apply discounts, shipping costs, and taxes
-	discount := 0.0
-	if input.Discount != nil {
-		discount = *input.Discount
+	// Amounts are given in the shop currency and must fit its minor unit
+	inShopCurrency := func(amount *money.Money) (money.Money, error) {
+		if amount == nil {
+			return money.Zero(currency), nil
+		}
+		converted, err := amount.In(currency)
+		if err != nil {
+			return money.Money{}, err
+		}
+		if converted.IsNegative() {
+			return money.Money{}, errors.New("amounts must not be negative")
+		}
+		return converted, nil
+	}
+
+	itemPrices := make([]money.Money, len(input.Items))
+	for i, item := range input.Items {
+		price, err := inShopCurrency(&item.Price)
+		if err != nil {
+			return &model.CreateOrderPayload{
+				Errors: []model.UserError{&model.CategoryNotFoundError{
+					Message: "Invalid item price: " + err.Error(),
+					Code:    model.ErrorCodeValidationInvalidInput,
+				}},
+			}, nil
+		}
+		itemPrices[i] = price
+	}
+	if err := r.applyCustomerPrices(ctx, shopID, currency, input.Items, itemPrices); err != nil {
+		return &model.CreateOrderPayload{
+			Errors: []model.UserError{&model.CategoryNotFoundError{
+				Message: "Failed to create order: " + err.Error(),
+				Code:    model.ErrorCodeServerErrorInternal,
+			}},
+		}, nil
+	}
+
+	// Calculate the total amount from items
+	total := money.Zero(currency)
+	for i, item := range input.Items {
+		total = total.Add(itemPrices[i].Mul(int64(item.Quantity)))
 	}
 
-	shippingCost := 0.0
-	if input.ShippingCost != nil {
-		shippingCost = *input.ShippingCost
+	// Apply discounts, shipping costs, and taxes
+	var adjustments [3]money.Money
+	for i, amount := range []*money.Money{input.Discount, input.ShippingCost, input.Tax} {
+		if adjustments[i], err = inShopCurrency(amount); err != nil {
+			return &model.CreateOrderPayload{
+				Errors: []model.UserError{&model.CategoryNotFoundError{
+					Message: "Invalid amount: " + err.Error(),
+					Code:    model.ErrorCodeValidationInvalidInput,
+				}},
+			}, nil
+		}
 	}
+	discount, shippingCost, tax := adjustments[0], adjustments[1], adjustments[2]
 
-	tax := 0.0
-	if input.Tax != nil {
-		tax = *input.Tax
+	// Loyalty points of the signed-in customer add to the discount
+	var loyaltyProgram db.LoyaltyProgram
+	var redeemPoints int32
+	if input.RedeemLoyaltyPoints != nil && *input.RedeemLoyaltyPoints != 0 {
+		customerID, ok := currentCustomer(ctx)
+		var relayID RelayID
+		if input.CustomerID != nil {
+			relayID, _ = DecodeRelayID(*input.CustomerID)
+		}
+		if !ok || relayID.UUID == nil || *relayID.UUID != customerID {
+			return &model.CreateOrderPayload{
+				Errors: []model.UserError{&model.CategoryNotFoundError{
+					Message: "Sign in to redeem loyalty points",
+					Code:    model.ErrorCodeAuthInvalidToken,
+				}},
+			}, nil
+		}
+		if *input.RedeemLoyaltyPoints < 0 || *input.RedeemLoyaltyPoints > math.MaxInt32 {
+			return &model.CreateOrderPayload{
+				Errors: []model.UserError{&model.CategoryNotFoundError{
+					Message: "Invalid number of loyalty points",
+					Code:    model.ErrorCodeValidationInvalidInput,
+				}},
+			}, nil
+		}
+		redeemPoints = int32(*input.RedeemLoyaltyPoints)
+		if loyaltyProgram, err = services.LoyaltyProgram(ctx, r.Repository, shopID); err != nil {
+			return &model.CreateOrderPayload{
+				Errors: []model.UserError{&model.CategoryNotFoundError{
+					Message: "Failed to create order: " + err.Error(),
+					Code:    model.ErrorCodeServerErrorInternal,
+				}},
+			}, nil
+		}
+		if err := services.CheckLoyaltyRedemption(loyaltyProgram, redeemPoints); err != nil {
+			return &model.CreateOrderPayload{
+				Errors: []model.UserError{&model.CategoryNotFoundError{
+					Message: "Cannot redeem loyalty points: " + err.Error(),
+					Code:    model.ErrorCodeValidationInvalidInput,
+				}},
+			}, nil
+		}
+		discount = discount.Add(services.LoyaltyPointsValue(loyaltyProgram, redeemPoints, currency))
+		if total.Sub(discount).IsNegative() {
+			return &model.CreateOrderPayload{
+				Errors: []model.UserError{&model.CategoryNotFoundError{
+					Message: "Loyalty points are worth more than the order",
+					Code:    model.ErrorCodeValidationInvalidInput,
+				}},
+			}, nil
+		}
 	}
 
 	// Calculate final amount
-	finalAmount := total - discount + shippingCost + tax
+	finalAmount := total.Sub(discount).Add(shippingCost).Add(tax)
 
 	// Extract or assign user ID for the order (optional for anonymous purchases)
 	var pgUserID pgtype.UUID
@@ -134,41 +235,26 @@ func (r *mutationResolver) CreateOrder(ctx context.Context, input model.CreateOr
 		customerPhone = input.PhoneNumber
 	}
 
-	// Create numeric types for monetary values
-	amountNumeric := pgtype.Numeric{
-		Int:   big.NewInt(int64(finalAmount * 100)),
-		Exp:   0,
-		Valid: true,
-	}
-
-	discountNumeric := pgtype.Numeric{
-		Int:   big.NewInt(int64(discount * 100)),
-		Exp:   0,
-		Valid: true,
-	}
-
-	shippingCostNumeric := pgtype.Numeric{
-		Int:   big.NewInt(int64(shippingCost * 100)),
-		Exp:   0,
-		Valid: true,
+	shippingAddress, userErr, err := r.orderShippingAddress(ctx, shopID, input.ShippingAddressID, input.ShippingAddress)
+	if err != nil {
+		return &model.CreateOrderPayload{
+			Errors: []model.UserError{&model.CategoryNotFoundError{
+				Message: "Failed to create order: " + err.Error(),
+				Code:    model.ErrorCodeServerErrorInternal,
+			}},
+		}, nil
 	}
-
-	taxNumeric := pgtype.Numeric{
-		Int:   big.NewInt(int64(tax * 100)),
-		Exp:   0,
-		Valid: true,
+	if userErr != nil {
+		return &model.CreateOrderPayload{Errors: []model.UserError{userErr}}, nil
 	}
 
-	// Use the original shipping address without appending contact info
-	shippingAddress := input.ShippingAddress
-
 	// Create the order in the database
 	orderParams := db.CreateOrderParams{
 		Status:          db.OrderStatusType(strings.ToLower(string(model.OrderStatusTypePending))),
-		Amount:          amountNumeric,
-		Discount:        discountNumeric,
-		ShippingCost:    shippingCostNumeric,
-		Tax:             taxNumeric,
+		Amount:          finalAmount.Numeric(),
+		Discount:        discount.Numeric(),
+		ShippingCost:    shippingCost.Numeric(),
+		Tax:             tax.Numeric(),
 		ShippingAddress: shippingAddress,
 		PaymentMethod:   db.PaymentMethodType(strings.ToLower(string(input.PaymentMethod))),
 		PaymentStatus:   db.PaymentStatusType(strings.ToLower(string(model.PaymentStatusTypePending))),
@@ -183,21 +269,8 @@ func (r *mutationResolver) CreateOrder(ctx context.Context, input model.CreateOr
 		CustomerPhone:   customerPhone,
 	}
 
-	// Create the order
-	orderDB, err := r.Repository.CreateOrder(ctx, orderParams)
-	if err != nil {
-		return &model.CreateOrderPayload{
-			Errors: []model.UserError{&model.CategoryNotFoundError{
-				Message: "Failed to create order: " + err.Error(),
-				Code:    model.ErrorCodeServerErrorInternal,
-			}},
-		}, nil
-	}
-
-	// Create order items
-	orderItems := make([]model.OrderItem, 0, len(input.Items))
-	for _, itemInput := range input.Items {
-		// Extract product variation ID
+	variantIDs := make([]int64, len(input.Items))
+	for i, itemInput := range input.Items {
 		productVariationID, err := DecodeRelayID(itemInput.ProductVariationID)
 		if err != nil || productVariationID.Type != "ProductVariant" || productVariationID.IntID == nil {
 			return &model.CreateOrderPayload{
@@ -207,41 +280,94 @@ func (r *mutationResolver) CreateOrder(ctx context.Context, input model.CreateOr
 				}},
 			}, nil
 		}
-
-		// Create price numeric
-		priceNumeric := pgtype.Numeric{
-			Int:   big.NewInt(int64(itemInput.Price * 100)),
-			Exp:   0,
-			Valid: true,
+		variantIDs[i] = *productVariationID.IntID
+	}
+
+	// Create the order with its items, taking the redeemed points in the same
+	// transaction. Items beyond the stock are taken on backorder or preorder when
+	// the variant allows it.
+	var orderDB db.Order
+	orderItemsDB := make([]db.OrderItem, len(input.Items))
+	err = r.Repository.WithTx(ctx, func(q *db.Queries) error {
+		var err error
+		if orderDB, err = q.CreateOrder(ctx, orderParams); err != nil {
+			return err
 		}
-
-		// Create the order item
-		itemParams := db.CreateOrderItemParams{
-			Quantity:           int64(itemInput.Quantity),
-			Price:              priceNumeric,
-			ProductVariationID: *productVariationID.IntID,
-			OrderID:            orderDB.OrderID,
-			ShopID:             shopID,
+		if err := services.LockOrderVariants(ctx, q, shopID, variantIDs); err != nil {
+			return err
 		}
-
-		orderItemDB, err := r.Repository.CreateOrderItem(ctx, itemParams)
-		if err != nil {
-			return &model.CreateOrderPayload{
-				Errors: []model.UserError{&model.CategoryNotFoundError{
-					Message: "Failed to create order item: " + err.Error(),
-					Code:    model.ErrorCodeServerErrorInternal,
-				}},
-			}, nil
+		for i, itemInput := range input.Items {
+			backordered, err := services.OrderLineBackorder(ctx, q, shopID, variantIDs[i], int64(itemInput.Quantity))
+			if err != nil {
+				return err
+			}
+			if orderItemsDB[i], err = q.CreateOrderItem(ctx, db.CreateOrderItemParams{
+				Quantity:            int64(itemInput.Quantity),
+				Price:               itemPrices[i].Numeric(),
+				ProductVariationID:  variantIDs[i],
+				OrderID:             orderDB.OrderID,
+				ShopID:              shopID,
+				BackorderedQuantity: backordered,
+			}); err != nil {
+				return err
+			}
 		}
+		if redeemPoints > 0 {
+			_, err = services.RedeemLoyaltyPoints(ctx, q, loyaltyProgram, orderDB, redeemPoints)
+		}
+		return err
+	})
+	if errors.Is(err, services.ErrInsufficientPoints) {
+		return &model.CreateOrderPayload{
+			Errors: []model.UserError{&model.CategoryNotFoundError{
+				Message: "Not enough loyalty points",
+				Code:    model.ErrorCodeValidationInvalidInput,
+			}},
+		}, nil
+	}
+	if errors.Is(err, services.ErrOutOfStock) {
+		return &model.CreateOrderPayload{
+			Errors: []model.UserError{&model.CategoryNotFoundError{
+				Message: "Not enough stock for an item of the order",
+				Code:    model.ErrorCodeValidationInvalidInput,
+			}},
+		}, nil
+	}
+	if errors.Is(err, services.ErrPreorderLimitReached) {
+		return &model.CreateOrderPayload{
+			Errors: []model.UserError{&model.CategoryNotFoundError{
+				Message: "An item of the order can no longer be preordered",
+				Code:    model.ErrorCodeValidationInvalidInput,
+			}},
+		}, nil
+	}
+	if errors.Is(err, pgx.ErrNoRows) {
+		return &model.CreateOrderPayload{
+			Errors: []model.UserError{&model.CategoryNotFoundError{
+				Message: "Product variant not found",
+				Code:    model.ErrorCodeValidationInvalidInput,
+			}},
+		}, nil
+	}
+	if err != nil {
+		return &model.CreateOrderPayload{
+			Errors: []model.UserError{&model.CategoryNotFoundError{
+				Message: "Failed to create order: " + err.Error(),
+				Code:    model.ErrorCodeServerErrorInternal,
+			}},
+		}, nil
+	}
 
-		// Convert to GraphQL model
-		orderItems = append(orderItems, model.OrderItem{
+	// Convert the items to the GraphQL model
+	orderItems := make([]model.OrderItem, len(orderItemsDB))
+	for i, orderItemDB := range orderItemsDB {
+		orderItems[i] = model.OrderItem{
 			ID:                 EncodeIntID("OrderItem", orderItemDB.OrderItemID),
 			OrderItemID:        int(orderItemDB.OrderItemID),
 			Quantity:           int(orderItemDB.Quantity),
-			Price:              numericToFloat64(&orderItemDB.Price) / 100.0,
-			ProductVariationID: itemInput.ProductVariationID,
-		})
+			Price:              numericToMoney(orderItemDB.Price, currency),
+			ProductVariationID: input.Items[i].ProductVariationID,
+		}
 	}
 
 	// Convert to GraphQL model
@@ -252,11 +378,12 @@ func (r *mutationResolver) CreateOrder(ctx context.Context, input model.CreateOr
 		CreatedAt:       orderDB.CreatedAt.Time,
 		UpdatedAt:       orderDB.UpdatedAt.Time,
 		CustomerID:      input.CustomerID,
-		Amount:          numericToFloat64(&orderDB.Amount) / 100.0,
-		Discount:        numericToFloat64(&orderDB.Discount) / 100.0,
-		ShippingCost:    numericToFloat64(&orderDB.ShippingCost) / 100.0,
-		Tax:             numericToFloat64(&orderDB.Tax) / 100.0,
-		ShippingAddress: orderDB.ShippingAddress,
+		Amount:          numericToMoney(orderDB.Amount, currency),
+		Discount:        numericToMoney(orderDB.Discount, currency),
+		ShippingCost:    numericToMoney(orderDB.ShippingCost, currency),
+		Tax:             numericToMoney(orderDB.Tax, currency),
+		CurrencyCode:    currency,
+		ShippingAddress: addressFromSnapshot(orderDB.ShippingAddress),
 		PaymentMethod:   model.PaymentMethodType(strings.ToUpper(string(orderDB.PaymentMethod))),
 		PaymentStatus:   model.PaymentStatusType(strings.ToUpper(string(orderDB.PaymentStatus))),
 		ShippingMethod:  orderDB.ShippingMethod,
@@ -371,6 +498,16 @@ func (r *mutationResolver) UpdateOrderStatus(ctx context.Context, input model.Up
 		}, nil
 	}
 
+	currency, err := r.shopCurrency(ctx, shopID)
+	if err != nil {
+		return &model.UpdateOrderStatusPayload{
+			Errors: []model.UserError{&model.CategoryNotFoundError{
+				Message: "Failed to update order status: " + err.Error(),
+				Code:    model.ErrorCodeServerErrorInternal,
+			}},
+		}, nil
+	}
+
 	// Convert order items to GraphQL model
 	orderItems := make([]model.OrderItem, 0, len(orderItemsDB))
 	for _, item := range orderItemsDB {
@@ -378,7 +515,7 @@ func (r *mutationResolver) UpdateOrderStatus(ctx context.Context, input model.Up
 			ID:                 EncodeIntID("OrderItem", item.OrderItemID),
 			OrderItemID:        int(item.OrderItemID),
 			Quantity:           int(item.Quantity),
-			Price:              numericToFloat64(&item.Price) / 100.0,
+			Price:              numericToMoney(item.Price, currency),
 			ProductVariationID: EncodeIntID("ProductVariant", item.ProductVariationID),
 		})
 	}
@@ -398,11 +535,12 @@ func (r *mutationResolver) UpdateOrderStatus(ctx context.Context, input model.Up
 		CreatedAt:       updatedOrder.CreatedAt.Time,
 		UpdatedAt:       updatedOrder.UpdatedAt.Time,
 		CustomerID:      customerID,
-		Amount:          numericToFloat64(&updatedOrder.Amount) / 100.0,
-		Discount:        numericToFloat64(&updatedOrder.Discount) / 100.0,
-		ShippingCost:    numericToFloat64(&updatedOrder.ShippingCost) / 100.0,
-		Tax:             numericToFloat64(&updatedOrder.Tax) / 100.0,
-		ShippingAddress: updatedOrder.ShippingAddress,
+		Amount:          numericToMoney(updatedOrder.Amount, currency),
+		Discount:        numericToMoney(updatedOrder.Discount, currency),
+		ShippingCost:    numericToMoney(updatedOrder.ShippingCost, currency),
+		Tax:             numericToMoney(updatedOrder.Tax, currency),
+		CurrencyCode:    currency,
+		ShippingAddress: addressFromSnapshot(updatedOrder.ShippingAddress),
 		PaymentMethod:   model.PaymentMethodType(strings.ToUpper(string(updatedOrder.PaymentMethod))),
 		PaymentStatus:   model.PaymentStatusType(strings.ToUpper(string(updatedOrder.PaymentStatus))),
 		ShippingMethod:  updatedOrder.ShippingMethod,
@@ -422,208 +560,83 @@ func (r *mutationResolver) UpdateOrderStatus(ctx context.Context, input model.Up
 	}, nil
 }
 
-// Orders is the resolver for the orders field.
-func (r *queryResolver) Orders(ctx context.Context, first *int, after *string) (*model.OrderConnection, error) {
-	// Get shop ID from context
-	shopID, ok := ctx.Value("shop_id").(int64)
-	if !ok {
-		return nil, errors.New("shop ID not found in context")
-	}
-
-	// Set default limit
-	limit := 20
-	if first != nil {
-		limit = *first
-	}
-
-	// Parse the after cursor if provided
-	var afterID int64
-	if after != nil && *after != "" {
-		decodedID, err := DecodeRelayID(*after)
-		if err != nil || decodedID.Type != "Order" || decodedID.IntID == nil {
-			return nil, errors.New("invalid cursor format")
-		}
-		afterID = *decodedID.IntID
+// Downloads is the resolver for the downloads field.
+func (r *orderResolver) Downloads(ctx context.Context, obj *model.Order) ([]model.OrderDownload, error) {
+	// Only the signed-in customer sees what their orders delivered
+	if _, _, err := currentCustomerOrders(ctx); err != nil {
+		return []model.OrderDownload{}, nil
 	}
-
-	// Fetch orders from database
-	ordersDB, err := r.Repository.ListOrders(ctx, db.ListOrdersParams{
-		ShopID: shopID,
-		Limit:  int32(limit + 1), // Fetch one extra to check for hasNextPage
-		Offset: int32(afterID),
+	downloads, err := r.Repository.ListOrderDigitalDownloads(ctx, db.ListOrderDigitalDownloadsParams{
+		OrderID: int64(obj.OrderID),
+		ShopID:  ctx.Value("shop_id").(int64),
 	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch orders: %w", err)
-	}
-
-	// Check if there are more pages
-	hasNextPage := len(ordersDB) > limit
-	if hasNextPage {
-		ordersDB = ordersDB[:limit] // Remove the extra item
-	}
-
-	// Build the edges
-	edges := make([]model.OrderEdge, 0, len(ordersDB))
-	for _, orderDB := range ordersDB {
-		// Fetch order items for this order
-		orderItemsDB, err := r.Repository.GetOrderItemsByOrder(ctx, db.GetOrderItemsByOrderParams{
-			OrderID: orderDB.OrderID,
-			ShopID:  shopID,
-		})
-		if err != nil {
-			return nil, fmt.Errorf("failed to fetch order items: %w", err)
+		return nil, fmt.Errorf("failed to fetch order downloads: %w", err)
+	}
+	result := make([]model.OrderDownload, len(downloads))
+	for i, download := range downloads {
+		result[i] = model.OrderDownload{
+			ID:            int(download.DownloadID),
+			Title:         download.Title,
+			Kind:          model.DigitalAssetKind(strings.ToUpper(string(download.Kind))),
+			FileName:      download.FileName,
+			LicenseKey:    download.LicenseKey,
+			DownloadCount: int(download.DownloadCount),
 		}
-
-		// Convert order items to GraphQL model
-		orderItems := make([]model.OrderItem, 0, len(orderItemsDB))
-		for _, item := range orderItemsDB {
-			orderItems = append(orderItems, model.OrderItem{
-				ID:                 EncodeIntID("OrderItem", item.OrderItemID),
-				OrderItemID:        int(item.OrderItemID),
-				Quantity:           int(item.Quantity),
-				Price:              numericToFloat64(&item.Price) / 100.0,
-				ProductVariationID: EncodeIntID("ProductVariant", item.ProductVariationID),
-			})
+		if r.DigitalDelivery != nil {
+			result[i].URL = r.DigitalDelivery.OrderDownloadURL(download)
 		}
-
-		// Generate customer ID
-		var customerID *string
-		if pgUUID := ParsePgUUID(orderDB.ShopCustomerID); pgUUID != nil {
-			idStr := EncodeUUIDID("Customer", *pgUUID)
-			customerID = &idStr
+		if download.DownloadLimit != nil {
+			limit := int(*download.DownloadLimit)
+			result[i].DownloadLimit = &limit
 		}
-
-		// Create the order edge
-		order := &model.Order{
-			ID:              EncodeIntID("Order", orderDB.OrderID),
-			OrderID:         int(orderDB.OrderID),
-			Status:          model.OrderStatusType(strings.ToUpper(string(orderDB.Status))),
-			CreatedAt:       orderDB.CreatedAt.Time,
-			UpdatedAt:       orderDB.UpdatedAt.Time,
-			CustomerID:      customerID,
-			Amount:          numericToFloat64(&orderDB.Amount) / 100.0,
-			Discount:        numericToFloat64(&orderDB.Discount) / 100.0,
-			ShippingCost:    numericToFloat64(&orderDB.ShippingCost) / 100.0,
-			Tax:             numericToFloat64(&orderDB.Tax) / 100.0,
-			ShippingAddress: orderDB.ShippingAddress,
-			PaymentMethod:   model.PaymentMethodType(strings.ToUpper(string(orderDB.PaymentMethod))),
-			PaymentStatus:   model.PaymentStatusType(strings.ToUpper(string(orderDB.PaymentStatus))),
-			ShippingMethod:  orderDB.ShippingMethod,
-			ShippingStatus:  model.ShippingStatusType(strings.ToUpper(string(orderDB.ShippingStatus))),
-			TransactionID:   orderDB.TransactionID,
-			Username:        orderDB.Username,
-			ShopID:          EncodeIntID("Shop", orderDB.ShopID),
-			Items:           orderItems,
-			CustomerName:    orderDB.CustomerName,
-			CustomerEmail:   orderDB.CustomerEmail,
-			CustomerPhone:   orderDB.CustomerPhone,
+		if download.ExpiresAt.Valid {
+			result[i].ExpiresAt = &download.ExpiresAt.Time
 		}
-
-		edges = append(edges, model.OrderEdge{
-			Cursor: EncodeIntID("Order", orderDB.OrderID),
-			Node:   order,
-		})
-	}
-
-	// Create page info
-	var startCursor, endCursor *string
-	if len(edges) > 0 {
-		startCursor = &edges[0].Cursor
-		endCursor = &edges[len(edges)-1].Cursor
-	}
-
-	pageInfo := &model.PageInfo{
-		HasNextPage: hasNextPage,
-		StartCursor: stringDereference(startCursor),
-		EndCursor:   stringDereference(endCursor),
 	}
+	return result, nil
+}
 
-	return &model.OrderConnection{
-		Edges:      edges,
-		PageInfo:   pageInfo,
-		TotalCount: len(edges),
-	}, nil
+// Orders is the resolver for the orders field.
+func (r *queryResolver) Orders(ctx context.Context, first *int, after *string) (*model.OrderConnection, error) {
+	return r.customerOrders(ctx, first, after)
 }
 
 // Order is the resolver for the order field.
 func (r *queryResolver) Order(ctx context.Context, id string) (*model.Order, error) {
-	// Get shop ID from context
 	shopID, ok := ctx.Value("shop_id").(int64)
 	if !ok {
 		return nil, errors.New("shop ID not found in context")
 	}
+	customerID, email, err := currentCustomerOrders(ctx)
+	if err != nil {
+		return nil, err
+	}
 
-	// Extract order ID
 	orderID, err := DecodeRelayID(id)
 	if err != nil || orderID.Type != "Order" || orderID.IntID == nil {
 		return nil, errors.New("invalid order ID format")
 	}
 
-	// Fetch the order from database
-	orderDB, err := r.Repository.GetOrder(ctx, db.GetOrderParams{
-		OrderID: *orderID.IntID,
-		ShopID:  shopID,
+	// Only orders of the signed-in customer are visible
+	orderDB, err := r.Repository.GetCustomerOrder(ctx, db.GetCustomerOrderParams{
+		OrderID:        *orderID.IntID,
+		ShopID:         shopID,
+		ShopCustomerID: customerID,
+		Email:          email,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("order not found: %w", err)
 	}
 
-	// Fetch order items
-	orderItemsDB, err := r.Repository.GetOrderItemsByOrder(ctx, db.GetOrderItemsByOrderParams{
-		OrderID: orderDB.OrderID,
-		ShopID:  shopID,
-	})
+	currency, err := r.shopCurrency(ctx, shopID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch order items: %w", err)
+		return nil, err
 	}
-
-	// Convert order items to GraphQL model
-	orderItems := make([]model.OrderItem, 0, len(orderItemsDB))
-	for _, item := range orderItemsDB {
-		orderItems = append(orderItems, model.OrderItem{
-			ID:                 EncodeIntID("OrderItem", item.OrderItemID),
-			OrderItemID:        int(item.OrderItemID),
-			Quantity:           int(item.Quantity),
-			Price:              numericToFloat64(&item.Price) / 100.0,
-			ProductVariationID: EncodeIntID("ProductVariant", item.ProductVariationID),
-		})
-	}
-
-	// Generate customer ID
-	var customerID *string
-	if pgUUID := ParsePgUUID(orderDB.ShopCustomerID); pgUUID != nil {
-		idStr := EncodeUUIDID("Customer", *pgUUID)
-		customerID = &idStr
-	}
-
-	// Return the order with its items
-	return &model.Order{
-		ID:              EncodeIntID("Order", orderDB.OrderID),
-		OrderID:         int(orderDB.OrderID),
-		Status:          model.OrderStatusType(strings.ToUpper(string(orderDB.Status))),
-		CreatedAt:       orderDB.CreatedAt.Time,
-		UpdatedAt:       orderDB.UpdatedAt.Time,
-		CustomerID:      customerID,
-		Amount:          numericToFloat64(&orderDB.Amount) / 100.0,
-		Discount:        numericToFloat64(&orderDB.Discount) / 100.0,
-		ShippingCost:    numericToFloat64(&orderDB.ShippingCost) / 100.0,
-		Tax:             numericToFloat64(&orderDB.Tax) / 100.0,
-		ShippingAddress: orderDB.ShippingAddress,
-		PaymentMethod:   model.PaymentMethodType(strings.ToUpper(string(orderDB.PaymentMethod))),
-		PaymentStatus:   model.PaymentStatusType(strings.ToUpper(string(orderDB.PaymentStatus))),
-		ShippingMethod:  orderDB.ShippingMethod,
-		ShippingStatus:  model.ShippingStatusType(strings.ToUpper(string(orderDB.ShippingStatus))),
-		TransactionID:   orderDB.TransactionID,
-		Username:        orderDB.Username,
-		ShopID:          EncodeIntID("Shop", orderDB.ShopID),
-		Items:           orderItems,
-		CustomerName:    orderDB.CustomerName,
-		CustomerEmail:   orderDB.CustomerEmail,
-		CustomerPhone:   orderDB.CustomerPhone,
-	}, nil
+	return r.orderFromDB(ctx, orderDB, currency)
 }
 
-// Mutation returns generated.MutationResolver implementation.
-func (r *Resolver) Mutation() generated.MutationResolver { return &mutationResolver{r} }
+// Order returns generated.OrderResolver implementation.
+func (r *Resolver) Order() generated.OrderResolver { return &orderResolver{r} }
 
-type mutationResolver struct{ *Resolver }
+type orderResolver struct{ *Resolver }
@@ -9,8 +9,10 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"strconv"
 
+	pgx "github.com/jackc/pgx/v5"
 	"github.com/petrejonn/naytife/internal/db"
 	"github.com/petrejonn/naytife/internal/gql/public/generated"
 	"github.com/petrejonn/naytife/internal/gql/public/model"
@@ -21,6 +23,16 @@ func (r *productResolver) ID(ctx context.Context, obj *model.Product) (string, e
 	return encodeRelayID("Product", strconv.FormatInt(int64(obj.ProductID), 10)), nil
 }
 
+// Title is the resolver for the title field.
+func (r *productResolver) Title(ctx context.Context, obj *model.Product) (string, error) {
+	return r.localize(ctx, db.TranslatableResourceTypeProduct, int64(obj.ProductID), "title", obj.Title)
+}
+
+// Description is the resolver for the description field.
+func (r *productResolver) Description(ctx context.Context, obj *model.Product) (string, error) {
+	return r.localize(ctx, db.TranslatableResourceTypeProduct, int64(obj.ProductID), "description", obj.Description)
+}
+
 // Images is the resolver for the images field.
 func (r *productResolver) Images(ctx context.Context, obj *model.Product) ([]model.Image, error) {
 	shopID := ctx.Value("shop_id").(int64)
@@ -44,11 +56,134 @@ func (r *productResolver) Images(ctx context.Context, obj *model.Product) ([]mod
 		}
 	}
 
+	pointers := make([]*model.Image, len(result))
+	for i := range result {
+		pointers[i] = &result[i]
+	}
+	if err := r.attachImageRenditions(ctx, shopID, pointers...); err != nil {
+		return nil, err
+	}
+
 	return result, nil
 }
 
+// Tags is the resolver for the tags field.
+func (r *productResolver) Tags(ctx context.Context, obj *model.Product) ([]string, error) {
+	shopID := ctx.Value("shop_id").(int64)
+	tags, err := r.Repository.GetProductTags(ctx, db.GetProductTagsParams{
+		ProductID: int64(obj.ProductID),
+		ShopID:    shopID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch product tags: %w", err)
+	}
+	if tags == nil {
+		tags = []string{}
+	}
+	return tags, nil
+}
+
+// Metafield is the resolver for the metafield field.
+func (r *productResolver) Metafield(ctx context.Context, obj *model.Product, namespace string, key string) (*model.Metafield, error) {
+	return r.getMetafield(ctx, db.MetafieldOwnerTypeProduct, int64(obj.ProductID), namespace, key)
+}
+
+// Rating is the resolver for the rating field.
+func (r *productResolver) Rating(ctx context.Context, obj *model.Product) (*model.ProductRating, error) {
+	shopID := ctx.Value("shop_id").(int64)
+	rating, err := r.Repository.GetProductRating(ctx, db.GetProductRatingParams{
+		ProductID: int64(obj.ProductID),
+		ShopID:    shopID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return &model.ProductRating{}, nil
+		}
+		return nil, fmt.Errorf("failed to fetch product rating: %w", err)
+	}
+	result := &model.ProductRating{Count: int(rating.RatingCount)}
+	if rating.RatingCount > 0 {
+		result.Average = math.Round(float64(rating.RatingSum)/float64(rating.RatingCount)*100) / 100
+	}
+	return result, nil
+}
+
+// Reviews is the resolver for the reviews field.
+func (r *productResolver) Reviews(ctx context.Context, obj *model.Product, first *int, after *string) (*model.ProductReviewConnection, error) {
+	shopID := ctx.Value("shop_id").(int64)
+	limit := 10
+	if first != nil {
+		limit = *first
+	}
+	// Reviews are listed newest first, so the cursor is the last review seen
+	before := int64(0)
+	if after != nil && *after != "" {
+		decodedType, id, err := decodeRelayID(*after)
+		if err != nil {
+			return nil, fmt.Errorf("invalid after cursor: %w", err)
+		}
+		if decodedType != "ProductReview" {
+			return nil, fmt.Errorf("expected after cursor type 'ProductReview', got '%s'", decodedType)
+		}
+		if id != nil {
+			before = *id
+		}
+	}
+
+	reviewsDB, err := r.Repository.GetApprovedProductReviews(ctx, db.GetApprovedProductReviewsParams{
+		ProductID: int64(obj.ProductID),
+		ShopID:    shopID,
+		Before:    before,
+		Limit:     int32(limit) + 1,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch product reviews: %w", err)
+	}
+	rating, err := r.Rating(ctx, obj)
+	if err != nil {
+		return nil, err
+	}
+
+	hasNextPage := len(reviewsDB) > limit
+	if hasNextPage {
+		reviewsDB = reviewsDB[:limit]
+	}
+	edges := make([]model.ProductReviewEdge, len(reviewsDB))
+	for i, review := range reviewsDB {
+		node := reviewFromDB(review)
+		edges[i] = model.ProductReviewEdge{Cursor: node.ID, Node: node}
+	}
+
+	var startCursor, endCursor string
+	if len(edges) > 0 {
+		startCursor = edges[0].Cursor
+		endCursor = edges[len(edges)-1].Cursor
+	}
+
+	return &model.ProductReviewConnection{
+		Edges: edges,
+		PageInfo: &model.PageInfo{
+			HasNextPage:     hasNextPage,
+			HasPreviousPage: before > 0,
+			StartCursor:     startCursor,
+			EndCursor:       endCursor,
+		},
+		TotalCount: rating.Count,
+	}, nil
+}
+
+// Description is the resolver for the description field.
+func (r *productVariantResolver) Description(ctx context.Context, obj *model.ProductVariant) (string, error) {
+	return r.localize(ctx, db.TranslatableResourceTypeProductVariation, int64(obj.VariationID), "description", obj.Description)
+}
+
+// Metafield is the resolver for the metafield field.
+func (r *productVariantResolver) Metafield(ctx context.Context, obj *model.ProductVariant, namespace string, key string) (*model.Metafield, error) {
+	return r.getMetafield(ctx, db.MetafieldOwnerTypeProductVariation, int64(obj.VariationID), namespace, key)
+}
+
 // Products is the resolver for the products field.
-func (r *queryResolver) Products(ctx context.Context, first *int, after *string) (*model.ProductConnection, error) {
+func (r *queryResolver) Products(ctx context.Context, first *int, after *string, currency *string) (*model.ProductConnection, error) {
 	shopID := ctx.Value("shop_id").(int64)
 	limit := 20
 	if first != nil {
@@ -110,6 +245,9 @@ func (r *queryResolver) Products(ctx context.Context, first *int, after *string)
 		edges[i].Node.DefaultVariant = &defaultVariant
 
 	}
+	if err := r.presentProductEdges(ctx, currency, edges); err != nil {
+		return nil, err
+	}
 	var startCursor, endCursor *string
 	if len(objsDB) > 0 {
 		firstCursor := encodeRelayID("Product", strconv.FormatInt(objsDB[0].ProductID, 10))
@@ -131,7 +269,7 @@ func (r *queryResolver) Products(ctx context.Context, first *int, after *string)
 }
 
 // Product is the resolver for the product field.
-func (r *queryResolver) Product(ctx context.Context, id string) (*model.Product, error) {
+func (r *queryResolver) Product(ctx context.Context, id string, currency *string) (*model.Product, error) {
 	shopID := ctx.Value("shop_id").(int64)
 	_, objID, err := decodeRelayID(id)
 	if err != nil {
@@ -159,7 +297,7 @@ func (r *queryResolver) Product(ctx context.Context, id string) (*model.Product,
 		}
 	}
 	defaultVariant.ID = encodeRelayID("ProductVariant", strconv.FormatInt(int64(defaultVariant.VariationID), 10))
-	return &model.Product{
+	product := &model.Product{
 		ProductID:      int(objDB.ProductID),
 		Title:          objDB.Title,
 		Description:    objDB.Description,
@@ -169,10 +307,20 @@ func (r *queryResolver) Product(ctx context.Context, id string) (*model.Product,
 		Variants:       variants,
 		DefaultVariant: &defaultVariant,
 		Slug:           objDB.Slug,
-	}, nil
+	}
+	if err := r.presentProducts(ctx, currency, product); err != nil {
+		return nil, err
+	}
+	return product, nil
 }
 
 // Product returns generated.ProductResolver implementation.
 func (r *Resolver) Product() generated.ProductResolver { return &productResolver{r} }
 
+// ProductVariant returns generated.ProductVariantResolver implementation.
+func (r *Resolver) ProductVariant() generated.ProductVariantResolver {
+	return &productVariantResolver{r}
+}
+
 type productResolver struct{ *Resolver }
+type productVariantResolver struct{ *Resolver }
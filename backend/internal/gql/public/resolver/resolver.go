@@ -4,6 +4,7 @@ package resolver
 
 import (
 	"github.com/petrejonn/naytife/internal/db"
+	"github.com/petrejonn/naytife/internal/services"
 )
 
 // This file will not be regenerated automatically.
@@ -11,5 +12,8 @@ import (
 // It serves as dependency injection for your app, add any dependencies you require here.
 
 type Resolver struct {
-	Repository db.Repository
+	Repository      db.Repository
+	CurrencyService *services.CurrencyService
+	DigitalDelivery *services.DigitalDelivery
+	Payments        *services.PaymentProcessorFactory
 }
@@ -0,0 +1,115 @@
+package resolver
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.76
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+
+	pgx "github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/petrejonn/naytife/internal/db"
+	dberrors "github.com/petrejonn/naytife/internal/db/errors"
+	"github.com/petrejonn/naytife/internal/gql/public/model"
+)
+
+// SubmitProductReview is the resolver for the submitProductReview field.
+func (r *mutationResolver) SubmitProductReview(ctx context.Context, input model.SubmitProductReviewInput) (*model.SubmitProductReviewPayload, error) {
+	fail := func(message string, code model.ErrorCode, field string) *model.SubmitProductReviewPayload {
+		reviewErr := &model.ReviewError{Message: message, Code: code, Path: []string{"input"}}
+		if field != "" {
+			reviewErr.Path = append(reviewErr.Path, field)
+		}
+		return &model.SubmitProductReviewPayload{Errors: []model.UserError{reviewErr}}
+	}
+
+	shopID, ok := ctx.Value("shop_id").(int64)
+	if !ok {
+		return fail("Shop ID not found in context", model.ErrorCodeServerErrorInternal, ""), nil
+	}
+
+	orderID, err := DecodeRelayID(input.OrderID)
+	if err != nil || orderID.Type != "Order" || orderID.IntID == nil {
+		return fail("Invalid order ID", model.ErrorCodeValidationInvalidInput, "orderId"), nil
+	}
+	variantID, err := DecodeRelayID(input.ProductVariantID)
+	if err != nil || variantID.Type != "ProductVariant" || variantID.IntID == nil {
+		return fail("Invalid product variant ID", model.ErrorCodeValidationInvalidInput, "productVariantId"), nil
+	}
+	if input.Rating < 1 || input.Rating > 5 {
+		return fail("Rating must be between 1 and 5", model.ErrorCodeValidationInvalidInput, "rating"), nil
+	}
+	if input.Title != nil && len(*input.Title) > 255 {
+		return fail("Title must be at most 255 characters", model.ErrorCodeValidationInvalidInput, "title"), nil
+	}
+	if len(input.Photos) > 10 {
+		return fail("A review can have at most 10 photos", model.ErrorCodeValidationInvalidInput, "photos"), nil
+	}
+	for _, photo := range input.Photos {
+		u, err := url.Parse(photo)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fail("Photos must be http or https URLs", model.ErrorCodeValidationInvalidInput, "photos"), nil
+		}
+	}
+
+	// Only customers who paid for an order containing the variant can review it
+	item, err := r.Repository.GetReviewableOrderItem(ctx, db.GetReviewableOrderItemParams{
+		OrderID:            *orderID.IntID,
+		ProductVariationID: *variantID.IntID,
+		ShopID:             shopID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fail("The order does not contain this product variant", model.ErrorCodeValidationInvalidInput, "productVariantId"), nil
+		}
+		return fail("Failed to fetch order: "+err.Error(), model.ErrorCodeServerErrorInternal, ""), nil
+	}
+	if item.CustomerEmail == nil || !strings.EqualFold(strings.TrimSpace(*item.CustomerEmail), strings.TrimSpace(input.Email)) {
+		return fail("The email does not match the order", model.ErrorCodeValidationInvalidInput, "email"), nil
+	}
+	if item.PaymentStatus != db.PaymentStatusTypePaid {
+		return fail("Only paid orders can be reviewed", model.ErrorCodeValidationInvalidInput, "orderId"), nil
+	}
+
+	name := item.CustomerName
+	if input.Name != nil && strings.TrimSpace(*input.Name) != "" {
+		name = strings.TrimSpace(*input.Name)
+	}
+	if len(name) > 100 {
+		return fail("Name must be at most 100 characters", model.ErrorCodeValidationInvalidInput, "name"), nil
+	}
+
+	photos := input.Photos
+	if photos == nil {
+		photos = []string{}
+	}
+
+	review, err := r.Repository.CreateProductReview(ctx, db.CreateProductReviewParams{
+		ProductID:          item.ProductID,
+		ProductVariationID: item.ProductVariationID,
+		OrderID:            item.OrderID,
+		Rating:             int16(input.Rating),
+		Title:              input.Title,
+		Body:               input.Body,
+		Photos:             photos,
+		CustomerName:       name,
+		CustomerEmail:      *item.CustomerEmail,
+		ShopID:             shopID,
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == dberrors.UniqueViolation {
+			return fail("This product variant has already been reviewed for this order", model.ErrorCodeValidationInvalidInput, ""), nil
+		}
+		return fail("Failed to submit review: "+err.Error(), model.ErrorCodeServerErrorInternal, ""), nil
+	}
+
+	return &model.SubmitProductReviewPayload{
+		Review: reviewFromDB(review),
+		Errors: []model.UserError{},
+	}, nil
+}
@@ -0,0 +1,126 @@
+package resolver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/petrejonn/naytife/internal/db"
+	dberrors "github.com/petrejonn/naytife/internal/db/errors"
+	"github.com/petrejonn/naytife/internal/gql/public/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// reviewRepository serves a single order line and records the review it is asked to create
+type reviewRepository struct {
+	db.Repository
+	item      *db.GetReviewableOrderItemRow
+	createErr error
+	created   *db.CreateProductReviewParams
+}
+
+func (r *reviewRepository) GetReviewableOrderItem(ctx context.Context, arg db.GetReviewableOrderItemParams) (db.GetReviewableOrderItemRow, error) {
+	if r.item == nil || r.item.OrderID != arg.OrderID || r.item.ProductVariationID != arg.ProductVariationID {
+		return db.GetReviewableOrderItemRow{}, pgx.ErrNoRows
+	}
+	return *r.item, nil
+}
+
+func (r *reviewRepository) CreateProductReview(ctx context.Context, arg db.CreateProductReviewParams) (db.ProductReview, error) {
+	if r.createErr != nil {
+		return db.ProductReview{}, r.createErr
+	}
+	r.created = &arg
+	return db.ProductReview{
+		ReviewID:           1,
+		ProductID:          arg.ProductID,
+		ProductVariationID: arg.ProductVariationID,
+		OrderID:            arg.OrderID,
+		Rating:             arg.Rating,
+		Title:              arg.Title,
+		Body:               arg.Body,
+		Photos:             arg.Photos,
+		CustomerName:       arg.CustomerName,
+		CustomerEmail:      arg.CustomerEmail,
+		Status:             db.ReviewStatusPending,
+		ShopID:             arg.ShopID,
+	}, nil
+}
+
+func TestSubmitProductReview(t *testing.T) {
+	email := "ada@example.com"
+	paidItem := func() *db.GetReviewableOrderItemRow {
+		return &db.GetReviewableOrderItemRow{
+			OrderID:            10,
+			CustomerName:       "Ada Lovelace",
+			CustomerEmail:      &email,
+			PaymentStatus:      db.PaymentStatusTypePaid,
+			ProductVariationID: 20,
+			ProductID:          30,
+		}
+	}
+	validInput := func() model.SubmitProductReviewInput {
+		return model.SubmitProductReviewInput{
+			OrderID:          EncodeIntID("Order", 10),
+			ProductVariantID: EncodeIntID("ProductVariant", 20),
+			Email:            " ADA@example.com ",
+			Rating:           4,
+		}
+	}
+	longTitle := string(make([]byte, 256))
+
+	tests := []struct {
+		name      string
+		input     func(*model.SubmitProductReviewInput)
+		item      func(*db.GetReviewableOrderItemRow)
+		createErr error
+		wantPath  []string
+	}{
+		{name: "valid review"},
+		{name: "wrong order id type", input: func(in *model.SubmitProductReviewInput) { in.OrderID = EncodeIntID("Product", 10) }, wantPath: []string{"input", "orderId"}},
+		{name: "rating too low", input: func(in *model.SubmitProductReviewInput) { in.Rating = 0 }, wantPath: []string{"input", "rating"}},
+		{name: "rating too high", input: func(in *model.SubmitProductReviewInput) { in.Rating = 6 }, wantPath: []string{"input", "rating"}},
+		{name: "title too long", input: func(in *model.SubmitProductReviewInput) { in.Title = &longTitle }, wantPath: []string{"input", "title"}},
+		{name: "photo not a url", input: func(in *model.SubmitProductReviewInput) { in.Photos = []string{"javascript:alert(1)"} }, wantPath: []string{"input", "photos"}},
+		{name: "variant not in order", input: func(in *model.SubmitProductReviewInput) { in.ProductVariantID = EncodeIntID("ProductVariant", 21) }, wantPath: []string{"input", "productVariantId"}},
+		{name: "email does not match", input: func(in *model.SubmitProductReviewInput) { in.Email = "eve@example.com" }, wantPath: []string{"input", "email"}},
+		{name: "guest order without email", item: func(item *db.GetReviewableOrderItemRow) { item.CustomerEmail = nil }, wantPath: []string{"input", "email"}},
+		{name: "unpaid order", item: func(item *db.GetReviewableOrderItemRow) { item.PaymentStatus = db.PaymentStatusTypePending }, wantPath: []string{"input", "orderId"}},
+		{name: "already reviewed", createErr: &pgconn.PgError{Code: dberrors.UniqueViolation}, wantPath: []string{"input"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := validInput()
+			if tt.input != nil {
+				tt.input(&input)
+			}
+			item := paidItem()
+			if tt.item != nil {
+				tt.item(item)
+			}
+			repo := &reviewRepository{item: item, createErr: tt.createErr}
+			r := &mutationResolver{&Resolver{Repository: repo}}
+			ctx := context.WithValue(context.Background(), "shop_id", int64(1))
+
+			payload, err := r.SubmitProductReview(ctx, input)
+			require.NoError(t, err)
+
+			if tt.wantPath != nil {
+				require.Len(t, payload.Errors, 1)
+				assert.Equal(t, tt.wantPath, payload.Errors[0].(*model.ReviewError).Path)
+				assert.Nil(t, payload.Review)
+				return
+			}
+			require.Empty(t, payload.Errors)
+			require.NotNil(t, repo.created)
+			assert.Equal(t, "ada@example.com", repo.created.CustomerEmail)
+			assert.Equal(t, "Ada Lovelace", repo.created.CustomerName)
+			assert.Equal(t, int64(30), repo.created.ProductID)
+			assert.Equal(t, []string{}, repo.created.Photos)
+			assert.Equal(t, model.ReviewStatusPending, payload.Review.Status)
+		})
+	}
+}
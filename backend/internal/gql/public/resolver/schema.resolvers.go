@@ -8,13 +8,14 @@ import (
 	"context"
 	"errors"
 
+	"github.com/petrejonn/naytife/internal/db"
 	"github.com/petrejonn/naytife/internal/gql/public/generated"
 	"github.com/petrejonn/naytife/internal/gql/public/model"
 )
 
 // Node is the resolver for the node field.
 func (r *queryResolver) Node(ctx context.Context, id string) (model.Node, error) {
-	typ, _, err := decodeRelayID(id)
+	typ, nodeID, err := decodeRelayID(id)
 	if err != nil {
 		return nil, err
 	}
@@ -24,6 +25,16 @@ func (r *queryResolver) Node(ctx context.Context, id string) (model.Node, error)
 		return r.Shop(ctx)
 	case "Category":
 		return r.Category(ctx, id)
+	case "Collection":
+		if nodeID == nil {
+			return nil, errors.New("invalid collection ID")
+		}
+		shopID := ctx.Value("shop_id").(int64)
+		collection, err := r.Repository.GetCollection(ctx, db.GetCollectionParams{CollectionID: *nodeID, ShopID: shopID})
+		if err != nil {
+			return nil, errors.New("could not find collection")
+		}
+		return collectionFromDB(collection), nil
 	default:
 		return nil, errors.New("unknown node type")
 	}
@@ -15,6 +15,7 @@ import (
 	"github.com/petrejonn/naytife/internal/db"
 	"github.com/petrejonn/naytife/internal/gql/public/generated"
 	"github.com/petrejonn/naytife/internal/gql/public/model"
+	"github.com/petrejonn/naytife/internal/services"
 
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
@@ -177,6 +178,10 @@ func (r *shopResolver) Images(ctx context.Context, obj *model.Shop) (*model.Shop
 		}
 	}
 
+	if err := r.attachImageRenditions(ctx, shopID, siteLogo, siteLogoDark, favicon, banner, bannerDark, coverImage, coverImageDark); err != nil {
+		return nil, err
+	}
+
 	return &model.ShopImages{
 		SiteLogo:       siteLogo,
 		SiteLogoDark:   siteLogoDark,
@@ -188,6 +193,38 @@ func (r *shopResolver) Images(ctx context.Context, obj *model.Shop) (*model.Shop
 	}, nil
 }
 
+// PresentmentCurrencies is the resolver for the presentmentCurrencies field.
+func (r *shopResolver) PresentmentCurrencies(ctx context.Context, obj *model.Shop) ([]string, error) {
+	shopID := ctx.Value("shop_id").(int64)
+	currencies, err := r.Repository.GetShopCurrencies(ctx, shopID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch presentment currencies: %w", err)
+	}
+	codes := make([]string, len(currencies))
+	for i, currency := range currencies {
+		codes[i] = currency.CurrencyCode
+	}
+	return codes, nil
+}
+
+// About is the resolver for the about field.
+func (r *shopResolver) About(ctx context.Context, obj *model.Shop) (*string, error) {
+	shopID := ctx.Value("shop_id").(int64)
+	return r.localizeOptional(ctx, db.TranslatableResourceTypeShop, shopID, "about", obj.About)
+}
+
+// SeoDescription is the resolver for the seoDescription field.
+func (r *shopResolver) SeoDescription(ctx context.Context, obj *model.Shop) (*string, error) {
+	shopID := ctx.Value("shop_id").(int64)
+	return r.localizeOptional(ctx, db.TranslatableResourceTypeShop, shopID, "seo_description", obj.SeoDescription)
+}
+
+// SeoTitle is the resolver for the seoTitle field.
+func (r *shopResolver) SeoTitle(ctx context.Context, obj *model.Shop) (*string, error) {
+	shopID := ctx.Value("shop_id").(int64)
+	return r.localizeOptional(ctx, db.TranslatableResourceTypeShop, shopID, "seo_title", obj.SeoTitle)
+}
+
 // PaymentMethods is the resolver for the paymentMethods field.
 func (r *shopResolver) PaymentMethods(ctx context.Context, obj *model.Shop) ([]model.PaymentMethodInfo, error) {
 	shopID := ctx.Value("shop_id").(int64)
@@ -268,6 +305,35 @@ func (r *shopResolver) PaymentMethods(ctx context.Context, obj *model.Shop) ([]m
 	return result, nil
 }
 
+// Metafield is the resolver for the metafield field.
+func (r *shopResolver) Metafield(ctx context.Context, obj *model.Shop, namespace string, key string) (*model.Metafield, error) {
+	shopID := ctx.Value("shop_id").(int64)
+	return r.getMetafield(ctx, db.MetafieldOwnerTypeShop, shopID, namespace, key)
+}
+
+// Locale is the resolver for the locale field.
+func (r *shopResolver) Locale(ctx context.Context, obj *model.Shop) (string, error) {
+	locale, _ := requestLocales(ctx)
+	return locale, nil
+}
+
+// DefaultLocale is the resolver for the defaultLocale field.
+func (r *shopResolver) DefaultLocale(ctx context.Context, obj *model.Shop) (string, error) {
+	_, defaultLocale := requestLocales(ctx)
+	return defaultLocale, nil
+}
+
+// Locales is the resolver for the locales field.
+func (r *shopResolver) Locales(ctx context.Context, obj *model.Shop) ([]string, error) {
+	shopID := ctx.Value("shop_id").(int64)
+	rows, err := r.Repository.GetShopLocales(ctx, shopID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch shop locales: %w", err)
+	}
+	_, locales := services.ShopLocales(rows)
+	return locales, nil
+}
+
 // Shop returns generated.ShopResolver implementation.
 func (r *Resolver) Shop() generated.ShopResolver { return &shopResolver{r} }
 
@@ -0,0 +1,330 @@
+package resolver
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.76
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/petrejonn/naytife/internal/api/models"
+	"github.com/petrejonn/naytife/internal/db"
+	dberrors "github.com/petrejonn/naytife/internal/db/errors"
+	"github.com/petrejonn/naytife/internal/gql/public/model"
+	"github.com/petrejonn/naytife/internal/services"
+)
+
+// SavePaymentMethod is the resolver for the savePaymentMethod field.
+func (r *mutationResolver) SavePaymentMethod(ctx context.Context, input model.SavePaymentMethodInput) (*model.SavedPaymentMethodPayload, error) {
+	shopID := ctx.Value("shop_id").(int64)
+	customerID, ok := currentCustomer(ctx)
+	email, _ := ctx.Value("shop_customer_email").(string)
+	if !ok || email == "" {
+		return savedPaymentMethodPayloadError("Sign in to save payment methods", model.ErrorCodeAuthInvalidToken, "input"), nil
+	}
+	if strings.TrimSpace(input.Token) == "" {
+		return savedPaymentMethodPayloadError("A payment method token is required", model.ErrorCodeValidationInvalidInput, "input", "token"), nil
+	}
+	provider := db.PaymentMethodType(strings.ToLower(string(input.Provider)))
+	processor := r.Payments.GetProcessor(string(provider))
+	if processor == nil {
+		return savedPaymentMethodPayloadError("Unsupported payment provider", model.ErrorCodeValidationInvalidInput, "input", "provider"), nil
+	}
+
+	saved, err := processor.SavePaymentMethod(ctx, shopID, models.SavePaymentMethodRequest{
+		Email: email,
+		Token: input.Token,
+	})
+	if err != nil {
+		if errors.Is(err, services.ErrRecurringPaymentsUnsupported) {
+			return savedPaymentMethodPayloadError("This payment provider does not support subscriptions", model.ErrorCodeValidationInvalidInput, "input", "provider"), nil
+		}
+		return savedPaymentMethodPayloadError(fmt.Sprintf("Failed to save payment method: %v", err), model.ErrorCodeValidationInvalidInput, "input", "token"), nil
+	}
+
+	method, err := r.Repository.CreateCustomerPaymentMethod(ctx, db.CreateCustomerPaymentMethodParams{
+		ShopCustomerID:    customerID,
+		Provider:          provider,
+		CustomerReference: saved.CustomerReference,
+		Token:             saved.Token,
+		Brand:             &saved.Brand,
+		Last4:             &saved.Last4,
+		ExpMonth:          &saved.ExpMonth,
+		ExpYear:           &saved.ExpYear,
+		ShopID:            shopID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return savedPaymentMethodPayloadError("This payment method is saved to another account", model.ErrorCodeValidationInvalidInput, "input", "token"), nil
+		}
+		return nil, fmt.Errorf("failed to save payment method: %w", err)
+	}
+	return r.savedPaymentMethodPayload(ctx, shopID, customerID, &method)
+}
+
+// DeleteSavedPaymentMethod is the resolver for the deleteSavedPaymentMethod field.
+func (r *mutationResolver) DeleteSavedPaymentMethod(ctx context.Context, id string) (*model.SavedPaymentMethodPayload, error) {
+	shopID := ctx.Value("shop_id").(int64)
+	customerID, ok := currentCustomer(ctx)
+	if !ok {
+		return savedPaymentMethodPayloadError("Sign in to manage payment methods", model.ErrorCodeAuthInvalidToken, "id"), nil
+	}
+	methodID, err := DecodeRelayID(id)
+	if err != nil || methodID.Type != "SavedPaymentMethod" || methodID.IntID == nil {
+		return savedPaymentMethodPayloadError("Invalid payment method ID", model.ErrorCodeValidationInvalidInput, "id"), nil
+	}
+
+	deleted, err := r.Repository.DeleteCustomerPaymentMethod(ctx, db.DeleteCustomerPaymentMethodParams{
+		CustomerPaymentMethodID: *methodID.IntID,
+		ShopCustomerID:          customerID,
+		ShopID:                  shopID,
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == dberrors.ForeignKeyViolation {
+			return savedPaymentMethodPayloadError("This payment method is used by a subscription", model.ErrorCodeValidationInvalidInput, "id"), nil
+		}
+		return nil, fmt.Errorf("failed to delete payment method: %w", err)
+	}
+	if deleted == 0 {
+		return savedPaymentMethodPayloadError("Payment method not found", model.ErrorCodeValidationInvalidInput, "id"), nil
+	}
+	return r.savedPaymentMethodPayload(ctx, shopID, customerID, nil)
+}
+
+// Subscribe is the resolver for the subscribe field.
+func (r *mutationResolver) Subscribe(ctx context.Context, input model.SubscribeInput) (*model.SubscriptionPayload, error) {
+	shopID := ctx.Value("shop_id").(int64)
+	customerID, ok := currentCustomer(ctx)
+	if !ok {
+		return subscriptionPayloadError("Sign in to subscribe", model.ErrorCodeAuthInvalidToken, "input"), nil
+	}
+	planID, err := DecodeRelayID(input.PlanID)
+	if err != nil || planID.Type != "SubscriptionPlan" || planID.IntID == nil {
+		return subscriptionPayloadError("Invalid plan ID", model.ErrorCodeValidationInvalidInput, "input", "planId"), nil
+	}
+	quantity := 1
+	if input.Quantity != nil {
+		quantity = *input.Quantity
+	}
+	if quantity < 1 {
+		return subscriptionPayloadError("Quantity must be at least 1", model.ErrorCodeValidationInvalidInput, "input", "quantity"), nil
+	}
+	methodID, err := DecodeRelayID(input.PaymentMethodID)
+	if err != nil || methodID.Type != "SavedPaymentMethod" || methodID.IntID == nil {
+		return subscriptionPayloadError("Invalid payment method ID", model.ErrorCodeValidationInvalidInput, "input", "paymentMethodId"), nil
+	}
+	if input.ShippingMethod == "" || len(input.ShippingMethod) > 10 {
+		return subscriptionPayloadError("Shipping method must be between 1 and 10 characters", model.ErrorCodeValidationInvalidInput, "input", "shippingMethod"), nil
+	}
+
+	plan, err := r.Repository.GetSubscriptionPlan(ctx, db.GetSubscriptionPlanParams{
+		PlanID: *planID.IntID,
+		ShopID: shopID,
+	})
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("failed to fetch subscription plan: %w", err)
+	}
+	if err != nil || !plan.Active {
+		return subscriptionPayloadError("The subscription plan is not available", model.ErrorCodeValidationInvalidInput, "input", "planId"), nil
+	}
+	method, err := r.Repository.GetCustomerPaymentMethod(ctx, db.GetCustomerPaymentMethodParams{
+		CustomerPaymentMethodID: *methodID.IntID,
+		ShopCustomerID:          customerID,
+		ShopID:                  shopID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return subscriptionPayloadError("Payment method not found", model.ErrorCodeValidationInvalidInput, "input", "paymentMethodId"), nil
+		}
+		return nil, fmt.Errorf("failed to fetch payment method: %w", err)
+	}
+	shippingAddress, userErr, err := r.orderShippingAddress(ctx, shopID, input.ShippingAddressID, input.ShippingAddress)
+	if err != nil {
+		return nil, err
+	}
+	if userErr != nil {
+		return &model.SubscriptionPayload{Errors: []model.UserError{userErr}}, nil
+	}
+
+	status, nextRenewal := services.SubscriptionStart(plan, time.Now())
+	subscription, err := r.Repository.CreateSubscription(ctx, db.CreateSubscriptionParams{
+		ShopCustomerID:          customerID,
+		PlanID:                  plan.PlanID,
+		Quantity:                int64(quantity),
+		Status:                  status,
+		CustomerPaymentMethodID: method.CustomerPaymentMethodID,
+		ShippingAddress:         shippingAddress,
+		ShippingMethod:          input.ShippingMethod,
+		NextRenewalAt:           pgtype.Timestamptz{Time: nextRenewal, Valid: true},
+		ShopID:                  shopID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create subscription: %w", err)
+	}
+	return r.customerSubscriptionPayload(ctx, shopID, customerID, subscription.SubscriptionID)
+}
+
+// UpdateSubscription is the resolver for the updateSubscription field.
+func (r *mutationResolver) UpdateSubscription(ctx context.Context, id string, input model.UpdateSubscriptionInput) (*model.SubscriptionPayload, error) {
+	shopID := ctx.Value("shop_id").(int64)
+	customerID, subscriptionID, payload, err := r.customerSubscriptionID(ctx, shopID, id)
+	if payload != nil || err != nil {
+		return payload, err
+	}
+
+	params := db.UpdateSubscriptionParams{
+		SubscriptionID: subscriptionID,
+		ShopID:         shopID,
+	}
+	if input.Quantity != nil {
+		if *input.Quantity < 1 {
+			return subscriptionPayloadError("Quantity must be at least 1", model.ErrorCodeValidationInvalidInput, "input", "quantity"), nil
+		}
+		quantity := int64(*input.Quantity)
+		params.Quantity = &quantity
+	}
+	if input.PaymentMethodID != nil {
+		methodID, err := DecodeRelayID(*input.PaymentMethodID)
+		if err != nil || methodID.Type != "SavedPaymentMethod" || methodID.IntID == nil {
+			return subscriptionPayloadError("Invalid payment method ID", model.ErrorCodeValidationInvalidInput, "input", "paymentMethodId"), nil
+		}
+		method, err := r.Repository.GetCustomerPaymentMethod(ctx, db.GetCustomerPaymentMethodParams{
+			CustomerPaymentMethodID: *methodID.IntID,
+			ShopCustomerID:          customerID,
+			ShopID:                  shopID,
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return subscriptionPayloadError("Payment method not found", model.ErrorCodeValidationInvalidInput, "input", "paymentMethodId"), nil
+			}
+			return nil, fmt.Errorf("failed to fetch payment method: %w", err)
+		}
+		params.CustomerPaymentMethodID = &method.CustomerPaymentMethodID
+	}
+
+	if _, err := r.Repository.UpdateSubscription(ctx, params); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return subscriptionActionError(services.ErrSubscriptionCancelled)
+		}
+		return nil, fmt.Errorf("failed to update subscription: %w", err)
+	}
+	return r.customerSubscriptionPayload(ctx, shopID, customerID, subscriptionID)
+}
+
+// PauseSubscription is the resolver for the pauseSubscription field.
+func (r *mutationResolver) PauseSubscription(ctx context.Context, id string, resumeOn *time.Time) (*model.SubscriptionPayload, error) {
+	shopID := ctx.Value("shop_id").(int64)
+	customerID, subscriptionID, payload, err := r.customerSubscriptionID(ctx, shopID, id)
+	if payload != nil || err != nil {
+		return payload, err
+	}
+	if resumeOn != nil && !resumeOn.After(time.Now()) {
+		return subscriptionPayloadError("resumeOn must be in the future", model.ErrorCodeValidationInvalidInput, "resumeOn"), nil
+	}
+
+	if _, err := services.PauseSubscription(ctx, r.Repository, shopID, subscriptionID, resumeOn); err != nil {
+		return subscriptionActionError(err)
+	}
+	return r.customerSubscriptionPayload(ctx, shopID, customerID, subscriptionID)
+}
+
+// ResumeSubscription is the resolver for the resumeSubscription field.
+func (r *mutationResolver) ResumeSubscription(ctx context.Context, id string) (*model.SubscriptionPayload, error) {
+	shopID := ctx.Value("shop_id").(int64)
+	customerID, subscriptionID, payload, err := r.customerSubscriptionID(ctx, shopID, id)
+	if payload != nil || err != nil {
+		return payload, err
+	}
+
+	if _, err := services.ResumeSubscription(ctx, r.Repository, shopID, subscriptionID); err != nil {
+		return subscriptionActionError(err)
+	}
+	return r.customerSubscriptionPayload(ctx, shopID, customerID, subscriptionID)
+}
+
+// SkipSubscriptionRenewal is the resolver for the skipSubscriptionRenewal field.
+func (r *mutationResolver) SkipSubscriptionRenewal(ctx context.Context, id string) (*model.SubscriptionPayload, error) {
+	shopID := ctx.Value("shop_id").(int64)
+	customerID, subscriptionID, payload, err := r.customerSubscriptionID(ctx, shopID, id)
+	if payload != nil || err != nil {
+		return payload, err
+	}
+
+	if _, err := services.SkipSubscriptionRenewal(ctx, r.Repository, shopID, subscriptionID); err != nil {
+		return subscriptionActionError(err)
+	}
+	return r.customerSubscriptionPayload(ctx, shopID, customerID, subscriptionID)
+}
+
+// CancelSubscription is the resolver for the cancelSubscription field.
+func (r *mutationResolver) CancelSubscription(ctx context.Context, id string, reason *string) (*model.SubscriptionPayload, error) {
+	shopID := ctx.Value("shop_id").(int64)
+	customerID, subscriptionID, payload, err := r.customerSubscriptionID(ctx, shopID, id)
+	if payload != nil || err != nil {
+		return payload, err
+	}
+	if reason != nil && len(*reason) > 1000 {
+		return subscriptionPayloadError("Reason must be at most 1000 characters", model.ErrorCodeValidationInvalidInput, "reason"), nil
+	}
+
+	if _, err := services.CancelSubscription(ctx, r.Repository, shopID, subscriptionID, reason); err != nil {
+		return subscriptionActionError(err)
+	}
+	return r.customerSubscriptionPayload(ctx, shopID, customerID, subscriptionID)
+}
+
+// SubscriptionPlans is the resolver for the subscriptionPlans field.
+func (r *productVariantResolver) SubscriptionPlans(ctx context.Context, obj *model.ProductVariant) ([]model.SubscriptionPlan, error) {
+	shopID := ctx.Value("shop_id").(int64)
+	rows, err := r.Repository.ListActiveSubscriptionPlans(ctx, db.ListActiveSubscriptionPlansParams{
+		ProductVariationID: int64(obj.VariationID),
+		ShopID:             shopID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch subscription plans: %w", err)
+	}
+	plans := make([]model.SubscriptionPlan, len(rows))
+	for i, row := range rows {
+		plans[i] = subscriptionPlanFromDB(row)
+	}
+	return plans, nil
+}
+
+// MySubscriptions is the resolver for the mySubscriptions field.
+func (r *queryResolver) MySubscriptions(ctx context.Context) ([]model.CustomerSubscription, error) {
+	shopID := ctx.Value("shop_id").(int64)
+	customerID, ok := currentCustomer(ctx)
+	if !ok {
+		return nil, errors.New("sign in to view subscriptions")
+	}
+	rows, err := r.Repository.ListCustomerSubscriptions(ctx, db.ListCustomerSubscriptionsParams{
+		ShopCustomerID: customerID,
+		ShopID:         shopID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch subscriptions: %w", err)
+	}
+	subscriptions := make([]model.CustomerSubscription, len(rows))
+	for i, row := range rows {
+		subscriptions[i] = *customerSubscriptionFromDB(db.GetSubscriptionRow(row))
+	}
+	return subscriptions, nil
+}
+
+// MyPaymentMethods is the resolver for the myPaymentMethods field.
+func (r *queryResolver) MyPaymentMethods(ctx context.Context) ([]model.SavedPaymentMethod, error) {
+	shopID := ctx.Value("shop_id").(int64)
+	customerID, ok := currentCustomer(ctx)
+	if !ok {
+		return nil, errors.New("sign in to view payment methods")
+	}
+	return r.savedPaymentMethods(ctx, shopID, customerID)
+}
@@ -1,6 +1,26 @@
 package resolver
 
-import "github.com/jackc/pgx/v5/pgtype"
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/mail"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	pgx "github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/petrejonn/naytife/internal/api/models"
+	"github.com/petrejonn/naytife/internal/db"
+	"github.com/petrejonn/naytife/internal/gql/public/model"
+	"github.com/petrejonn/naytife/internal/money"
+	"github.com/petrejonn/naytife/internal/services"
+)
 
 func safeStringDereference(ptr *string) string {
 	if ptr != nil {
@@ -17,13 +37,845 @@ func stringDereference(s *string) string {
 	return *s
 }
 
-// numericToFloat64 converts a pgtype.Numeric to a float64
-func numericToFloat64(n *pgtype.Numeric) float64 {
-	if !n.Valid {
-		return 0
+// numericToMoney converts a DECIMAL amount column to money. The columns never hold
+// NaN or infinities, so a value that cannot be converted reads as zero.
+func numericToMoney(n pgtype.Numeric, currency string) money.Money {
+	m, err := money.FromNumeric(n, currency)
+	if err != nil {
+		return money.Zero(currency)
+	}
+	return m
+}
+
+// shopCurrency returns the currency the shop's prices and orders are kept in
+func (r *Resolver) shopCurrency(ctx context.Context, shopID int64) (string, error) {
+	shop, err := r.Repository.GetShop(ctx, shopID)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch shop: %w", err)
+	}
+	return shop.CurrencyCode, nil
+}
+
+// attachImageRenditions fills in dimensions and renditions for images that were uploaded
+// through the API. Externally hosted images are left with an empty rendition set.
+func (r *Resolver) attachImageRenditions(ctx context.Context, shopID int64, images ...*model.Image) error {
+	urls := make([]string, 0, len(images))
+	for _, img := range images {
+		if img == nil {
+			continue
+		}
+		img.Renditions = []model.ImageRendition{}
+		urls = append(urls, img.URL)
+	}
+	if len(urls) == 0 {
+		return nil
+	}
+
+	assets, err := r.Repository.GetImageAssetsByURLs(ctx, db.GetImageAssetsByURLsParams{ShopID: shopID, Urls: urls})
+	if err != nil {
+		return fmt.Errorf("failed to fetch image renditions: %w", err)
+	}
+	byURL := make(map[string]db.ImageAsset, len(assets))
+	for _, asset := range assets {
+		byURL[asset.Url] = asset
+	}
+
+	for _, img := range images {
+		if img == nil {
+			continue
+		}
+		asset, ok := byURL[img.URL]
+		if !ok {
+			continue
+		}
+		width, height := int(asset.Width), int(asset.Height)
+		img.Width = &width
+		img.Height = &height
+		if err := json.Unmarshal(asset.Renditions, &img.Renditions); err != nil {
+			return fmt.Errorf("failed to decode image renditions: %w", err)
+		}
+		sort.SliceStable(img.Renditions, func(i, j int) bool {
+			return img.Renditions[i].Width < img.Renditions[j].Width
+		})
+	}
+	return nil
+}
+
+// productFromRow builds a product model from a GetProduct row, decoding the
+// aggregated attribute, variant and image JSON columns
+func productFromRow(row db.GetProductRow) (*model.Product, error) {
+	product := &model.Product{
+		ID:          strconv.FormatInt(row.ProductID, 10),
+		ProductID:   int(row.ProductID),
+		Title:       row.Title,
+		Description: row.Description,
+		Slug:        row.Slug,
+		CreatedAt:   row.CreatedAt.Time,
+		UpdatedAt:   row.UpdatedAt.Time,
+		Attributes:  []model.ProductAttribute{},
+		Variants:    []model.ProductVariant{},
+		Images:      []model.Image{},
+	}
+
+	if err := json.Unmarshal(row.Attributes, &product.Attributes); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal attributes: %w", err)
+	}
+	if err := json.Unmarshal(row.Variants, &product.Variants); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal variants: %w", err)
+	}
+	for i := range product.Variants {
+		product.Variants[i].ID = EncodeIntID("ProductVariant", int64(product.Variants[i].VariationID))
+		if product.Variants[i].IsDefault {
+			defaultVariant := product.Variants[i]
+			product.DefaultVariant = &defaultVariant
+		}
+	}
+	if err := json.Unmarshal(row.Images, &product.Images); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal images: %w", err)
+	}
+	return product, nil
+}
+
+func collectionFromDB(collection db.Collection) *model.Collection {
+	return &model.Collection{
+		ID:          strconv.FormatInt(collection.CollectionID, 10),
+		Handle:      collection.Handle,
+		Title:       collection.Title,
+		Description: collection.Description,
+		CreatedAt:   collection.CreatedAt.Time,
+		UpdatedAt:   collection.UpdatedAt.Time,
+	}
+}
+
+func reviewFromDB(review db.ProductReview) *model.ProductReview {
+	var repliedAt *time.Time
+	if review.RepliedAt.Valid {
+		repliedAt = &review.RepliedAt.Time
+	}
+	photos := review.Photos
+	if photos == nil {
+		photos = []string{}
+	}
+	return &model.ProductReview{
+		ID:               EncodeIntID("ProductReview", review.ReviewID),
+		ProductVariantID: EncodeIntID("ProductVariant", review.ProductVariationID),
+		Rating:           int(review.Rating),
+		Title:            review.Title,
+		Body:             review.Body,
+		Photos:           photos,
+		CustomerName:     review.CustomerName,
+		Status:           model.ReviewStatus(strings.ToUpper(string(review.Status))),
+		MerchantReply:    review.MerchantReply,
+		RepliedAt:        repliedAt,
+		CreatedAt:        review.CreatedAt.Time,
+	}
+}
+
+// getMetafield returns the metafield of an owner, or nil when it has not been set
+func (r *Resolver) getMetafield(ctx context.Context, ownerType db.MetafieldOwnerType, ownerID int64, namespace, key string) (*model.Metafield, error) {
+	shopID := ctx.Value("shop_id").(int64)
+	metafield, err := r.Repository.GetMetafield(ctx, db.GetMetafieldParams{
+		OwnerType: ownerType,
+		OwnerID:   strconv.FormatInt(ownerID, 10),
+		Namespace: namespace,
+		Key:       key,
+		ShopID:    shopID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to fetch metafield: %w", err)
+	}
+	return &model.Metafield{
+		Namespace: metafield.Namespace,
+		Key:       metafield.Key,
+		Type:      string(metafield.Type),
+		Value:     metafield.Value,
+	}, nil
+}
+
+// requestLocales returns the locale negotiated for the request and the shop's default locale
+func requestLocales(ctx context.Context) (string, string) {
+	defaultLocale, _ := ctx.Value("default_locale").(string)
+	if defaultLocale == "" {
+		defaultLocale = services.DefaultLocale
+	}
+	locale, _ := ctx.Value("locale").(string)
+	if locale == "" {
+		locale = defaultLocale
+	}
+	return locale, defaultLocale
+}
+
+// translation returns the value of a field in the request locale, or nil when the request
+// uses the shop's default locale or the field has not been translated
+func (r *Resolver) translation(ctx context.Context, resourceType db.TranslatableResourceType, resourceID int64, field string) (*string, error) {
+	locale, defaultLocale := requestLocales(ctx)
+	if locale == defaultLocale {
+		return nil, nil
+	}
+	shopID := ctx.Value("shop_id").(int64)
+	value, err := r.Repository.GetTranslation(ctx, db.GetTranslationParams{
+		ResourceType: resourceType,
+		ResourceID:   resourceID,
+		Field:        field,
+		Locale:       locale,
+		ShopID:       shopID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to fetch %s translation: %w", field, err)
+	}
+	return &value, nil
+}
+
+// localize returns the translated value of a field, falling back to source
+func (r *Resolver) localize(ctx context.Context, resourceType db.TranslatableResourceType, resourceID int64, field string, source string) (string, error) {
+	value, err := r.translation(ctx, resourceType, resourceID, field)
+	if err != nil || value == nil {
+		return source, err
+	}
+	return *value, nil
+}
+
+// localizeOptional is localize for nullable fields
+func (r *Resolver) localizeOptional(ctx context.Context, resourceType db.TranslatableResourceType, resourceID int64, field string, source *string) (*string, error) {
+	value, err := r.translation(ctx, resourceType, resourceID, field)
+	if err != nil || value == nil {
+		return source, err
+	}
+	return value, nil
+}
+
+// presentProducts converts the variant prices of products from the shop currency into
+// the requested presentment currency, preferring fixed variant prices where set
+func (r *Resolver) presentProducts(ctx context.Context, currency *string, products ...*model.Product) error {
+	shopID := ctx.Value("shop_id").(int64)
+	presentment, err := r.CurrencyService.Presentment(ctx, shopID, stringDereference(currency))
+	if err != nil {
+		return fmt.Errorf("failed to resolve currency: %w", err)
+	}
+
+	var variantIDs []int64
+	for _, product := range products {
+		for _, variant := range product.Variants {
+			variantIDs = append(variantIDs, int64(variant.VariationID))
+		}
+	}
+	fixedPrices, err := r.CurrencyService.VariantPrices(ctx, shopID, presentment, variantIDs)
+	if err != nil {
+		return fmt.Errorf("failed to fetch variant prices: %w", err)
+	}
+	// Group prices of a signed-in customer are converted like the base price, ahead
+	// of any fixed presentment price
+	groupPrices := map[int64]money.Money{}
+	if customerID, ok := currentCustomer(ctx); ok {
+		groupPrices, err = services.CustomerPrices(ctx, r.Repository, shopID, presentment.ShopCurrency, customerID, variantIDs)
+		if err != nil {
+			return fmt.Errorf("failed to fetch customer prices: %w", err)
+		}
+	}
+
+	present := func(variant *model.ProductVariant) {
+		if price, ok := groupPrices[int64(variant.VariationID)]; ok {
+			variant.Price = presentment.Convert(price)
+		} else if price, ok := fixedPrices[int64(variant.VariationID)]; ok {
+			variant.Price = price
+		} else {
+			// Prices decoded from the variants JSON have no currency yet
+			variant.Price = presentment.Convert(variant.Price.Round(presentment.ShopCurrency))
+		}
+		variant.CurrencyCode = presentment.CurrencyCode
+	}
+	for _, product := range products {
+		for i := range product.Variants {
+			present(&product.Variants[i])
+		}
+		if product.DefaultVariant != nil {
+			present(product.DefaultVariant)
+		}
+	}
+	return nil
+}
+
+// presentProductEdges is presentProducts for the nodes of a product connection
+func (r *Resolver) presentProductEdges(ctx context.Context, currency *string, edges []model.ProductEdge) error {
+	products := make([]*model.Product, len(edges))
+	for i := range edges {
+		products[i] = edges[i].Node
+	}
+	return r.presentProducts(ctx, currency, products...)
+}
+
+// applyCustomerPrices replaces the prices of order items that have a group price
+// for the signed-in customer. Items with invalid variant IDs are left for the
+// caller to reject.
+func (r *Resolver) applyCustomerPrices(ctx context.Context, shopID int64, currency string, items []model.CreateOrderItemInput, prices []money.Money) error {
+	customerID, ok := currentCustomer(ctx)
+	if !ok {
+		return nil
+	}
+
+	variantIDs := make([]int64, len(items))
+	for i, item := range items {
+		if relayID, err := DecodeRelayID(item.ProductVariationID); err == nil && relayID.IntID != nil {
+			variantIDs[i] = *relayID.IntID
+		}
+	}
+	groupPrices, err := services.CustomerPrices(ctx, r.Repository, shopID, currency, customerID, variantIDs)
+	if err != nil {
+		return err
+	}
+	for i, variantID := range variantIDs {
+		if price, ok := groupPrices[variantID]; ok {
+			prices[i] = price
+		}
+	}
+	return nil
+}
+
+// currentCustomer returns the signed-in shop customer of the request, if any
+func currentCustomer(ctx context.Context) (uuid.UUID, bool) {
+	customerID, ok := ctx.Value("shop_customer_id").(uuid.UUID)
+	return customerID, ok
+}
+
+// wishlistItems returns a customer's wishlist, newest first, with the products
+// of its variants priced in currency
+func (r *Resolver) wishlistItems(ctx context.Context, shopID int64, customerID uuid.UUID, currency *string) ([]model.WishlistItem, error) {
+	rows, err := r.Repository.GetWishlistItems(ctx, db.GetWishlistItemsParams{
+		ShopCustomerID: customerID,
+		ShopID:         shopID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch wishlist: %w", err)
+	}
+
+	items := make([]model.WishlistItem, len(rows))
+	products := make([]*model.Product, len(rows))
+	for i, row := range rows {
+		details, err := r.Repository.GetProduct(ctx, db.GetProductParams{
+			ProductID: row.ProductID,
+			ShopID:    shopID,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch product details for product %d: %w", row.ProductID, err)
+		}
+		if products[i], err = productFromRow(details); err != nil {
+			return nil, err
+		}
+		items[i] = model.WishlistItem{
+			ProductVariantID: EncodeIntID("ProductVariant", row.ProductVariationID),
+			Product:          products[i],
+			AddedAt:          row.CreatedAt.Time,
+		}
+	}
+	if err := r.presentProducts(ctx, currency, products...); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// backInStockTarget returns the variant and normalized email address of a back in
+// stock request, taking the email of the signed-in customer when none is given
+func backInStockTarget(ctx context.Context, input model.BackInStockInput) (int64, string, model.UserError) {
+	variantID, err := DecodeRelayID(input.ProductVariantID)
+	if err != nil || variantID.Type != "ProductVariant" || variantID.IntID == nil {
+		return 0, "", &model.WishlistError{
+			Message: "Invalid product variant ID",
+			Code:    model.ErrorCodeValidationInvalidInput,
+			Path:    []string{"input", "productVariantId"},
+		}
 	}
 
-	// Convert the numeric to a float64
-	f, _ := n.Int.Float64()
-	return f
+	email := strings.TrimSpace(stringDereference(input.Email))
+	if email == "" {
+		email, _ = ctx.Value("shop_customer_email").(string)
+	}
+	if email == "" {
+		return 0, "", &model.WishlistError{
+			Message: "Email is required",
+			Code:    model.ErrorCodeValidationInvalidInput,
+			Path:    []string{"input", "email"},
+		}
+	}
+	if addr, err := mail.ParseAddress(email); err != nil || addr.Address != email {
+		return 0, "", &model.WishlistError{
+			Message: "Invalid email address",
+			Code:    model.ErrorCodeValidationInvalidInput,
+			Path:    []string{"input", "email"},
+		}
+	}
+	return *variantID.IntID, strings.ToLower(email), nil
+}
+
+// addressFromSnapshot converts the address snapshot stored on an order
+func addressFromSnapshot(snapshot []byte) *model.Address {
+	address := models.ShippingAddressFromSnapshot(snapshot)
+	return &model.Address{
+		FirstName:    address.FirstName,
+		LastName:     address.LastName,
+		Company:      address.Company,
+		AddressLine1: address.AddressLine1,
+		AddressLine2: address.AddressLine2,
+		City:         address.City,
+		State:        address.State,
+		PostalCode:   address.PostalCode,
+		Country:      address.Country,
+		Phone:        address.Phone,
+	}
+}
+
+func customerAddressFromDB(address db.CustomerAddress) *model.CustomerAddress {
+	return &model.CustomerAddress{
+		ID:                EncodeIntID("CustomerAddress", address.AddressID),
+		FirstName:         address.FirstName,
+		LastName:          address.LastName,
+		Company:           address.Company,
+		AddressLine1:      address.AddressLine1,
+		AddressLine2:      address.AddressLine2,
+		City:              address.City,
+		State:             address.State,
+		PostalCode:        address.PostalCode,
+		Country:           address.Country,
+		Phone:             address.Phone,
+		IsDefaultShipping: address.IsDefaultShipping,
+		IsDefaultBilling:  address.IsDefaultBilling,
+		CreatedAt:         address.CreatedAt.Time,
+		UpdatedAt:         address.UpdatedAt.Time,
+	}
+}
+
+// shippingAddressFromInput normalizes and validates an address input the same
+// way the REST API does. path locates the input in validation errors.
+func shippingAddressFromInput(input model.AddressInput, path ...string) (models.ShippingAddress, model.UserError) {
+	address := models.ShippingAddress{
+		FirstName:    input.FirstName,
+		LastName:     input.LastName,
+		Company:      input.Company,
+		AddressLine1: input.AddressLine1,
+		AddressLine2: input.AddressLine2,
+		City:         input.City,
+		State:        input.State,
+		PostalCode:   input.PostalCode,
+		Country:      input.Country,
+		Phone:        input.Phone,
+	}
+	address.Normalize()
+
+	validator := &models.XValidator{}
+	if errs := validator.Validate(&address); len(errs) > 0 {
+		field := errs[0].Field
+		if field != "" {
+			field = strings.ToLower(field[:1]) + field[1:]
+		}
+		message := field + " is missing or invalid"
+		if field == "country" {
+			message = "country must be an ISO 3166-1 alpha-2 code"
+		}
+		return address, &model.AddressError{
+			Message: message,
+			Code:    model.ErrorCodeValidationInvalidInput,
+			Path:    append(path, field),
+		}
+	}
+	return address, nil
+}
+
+// orderShippingAddress resolves the shipping address of a new order or
+// subscription to the snapshot stored on it, either from the input or from the
+// signed-in customer's address book
+func (r *Resolver) orderShippingAddress(ctx context.Context, shopID int64, shippingAddressID *string, shippingAddress *model.AddressInput) ([]byte, model.UserError, error) {
+	var address models.ShippingAddress
+	switch {
+	case shippingAddressID != nil:
+		addressErr := &model.AddressError{
+			Code: model.ErrorCodeValidationInvalidInput,
+			Path: []string{"input", "shippingAddressId"},
+		}
+		customerID, ok := currentCustomer(ctx)
+		if !ok {
+			addressErr.Message = "Sign in to use a saved address"
+			addressErr.Code = model.ErrorCodeAuthInvalidToken
+			return nil, addressErr, nil
+		}
+		addressID, err := DecodeRelayID(*shippingAddressID)
+		if err != nil || addressID.Type != "CustomerAddress" || addressID.IntID == nil {
+			addressErr.Message = "Invalid address ID"
+			return nil, addressErr, nil
+		}
+		saved, err := r.Repository.GetCustomerAddress(ctx, db.GetCustomerAddressParams{
+			AddressID:      *addressID.IntID,
+			ShopCustomerID: customerID,
+			ShopID:         shopID,
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				addressErr.Message = "Address not found"
+				return nil, addressErr, nil
+			}
+			return nil, nil, fmt.Errorf("failed to fetch address: %w", err)
+		}
+		address = models.NewCustomerAddress(saved).ShippingAddress
+	case shippingAddress != nil:
+		var userErr model.UserError
+		if address, userErr = shippingAddressFromInput(*shippingAddress, "input", "shippingAddress"); userErr != nil {
+			return nil, userErr, nil
+		}
+	default:
+		return nil, &model.AddressError{
+			Message: "A shipping address is required",
+			Code:    model.ErrorCodeValidationInvalidInput,
+			Path:    []string{"input", "shippingAddress"},
+		}, nil
+	}
+
+	snapshot, err := address.Snapshot()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode shipping address: %w", err)
+	}
+	return snapshot, nil, nil
+}
+
+// customerAddresses returns a customer's address book, defaults first
+func (r *Resolver) customerAddresses(ctx context.Context, shopID int64, customerID uuid.UUID) ([]model.CustomerAddress, error) {
+	rows, err := r.Repository.ListCustomerAddresses(ctx, db.ListCustomerAddressesParams{
+		ShopCustomerID: customerID,
+		ShopID:         shopID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch addresses: %w", err)
+	}
+	addresses := make([]model.CustomerAddress, len(rows))
+	for i, row := range rows {
+		addresses[i] = *customerAddressFromDB(row)
+	}
+	return addresses, nil
+}
+
+// customerAddressPayload returns the changed address along with the customer's
+// whole address book
+func (r *Resolver) customerAddressPayload(ctx context.Context, shopID int64, customerID uuid.UUID, changed *db.CustomerAddress) (*model.CustomerAddressPayload, error) {
+	addresses, err := r.customerAddresses(ctx, shopID, customerID)
+	if err != nil {
+		return nil, err
+	}
+	payload := &model.CustomerAddressPayload{Addresses: addresses, Errors: []model.UserError{}}
+	if changed != nil {
+		payload.Address = customerAddressFromDB(*changed)
+	}
+	return payload, nil
+}
+
+func addressPayloadError(message string, code model.ErrorCode, field string) *model.CustomerAddressPayload {
+	return &model.CustomerAddressPayload{
+		Addresses: []model.CustomerAddress{},
+		Errors:    []model.UserError{&model.AddressError{Message: message, Code: code, Path: []string{field}}},
+	}
+}
+
+func customerFromDB(customer db.ShopCustomer) *model.Customer {
+	return &model.Customer{
+		ID:             EncodeUUIDID("Customer", customer.ShopCustomerID),
+		Email:          customer.Email,
+		Name:           customer.Name,
+		Locale:         customer.Locale,
+		ProfilePicture: customer.ProfilePicture,
+		VerifiedEmail:  customer.VerifiedEmail != nil && *customer.VerifiedEmail,
+		CreatedAt:      customer.CreatedAt.Time,
+	}
+}
+
+// currentCustomerOrders returns the signed-in customer and the email their
+// orders are matched by, or an error for anonymous requests
+func currentCustomerOrders(ctx context.Context) (uuid.UUID, string, error) {
+	customerID, ok := currentCustomer(ctx)
+	email, _ := ctx.Value("shop_customer_email").(string)
+	if !ok || email == "" {
+		return uuid.Nil, "", errors.New("sign in to view orders")
+	}
+	return customerID, email, nil
+}
+
+// customerOrders pages through the signed-in customer's orders, newest first
+func (r *Resolver) customerOrders(ctx context.Context, first *int, after *string) (*model.OrderConnection, error) {
+	shopID := ctx.Value("shop_id").(int64)
+	customerID, email, err := currentCustomerOrders(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := 20
+	if first != nil {
+		limit = *first
+	}
+	if limit < 1 || limit > 100 {
+		return nil, errors.New("first must be between 1 and 100")
+	}
+	before := int64(math.MaxInt64)
+	if after != nil && *after != "" {
+		cursor, err := DecodeRelayID(*after)
+		if err != nil || cursor.Type != "Order" || cursor.IntID == nil {
+			return nil, errors.New("invalid cursor format")
+		}
+		before = *cursor.IntID
+	}
+
+	rows, err := r.Repository.ListCustomerOrders(ctx, db.ListCustomerOrdersParams{
+		ShopID:         shopID,
+		ShopCustomerID: customerID,
+		Email:          email,
+		Before:         before,
+		Limit:          int32(limit + 1),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch orders: %w", err)
+	}
+	totalCount, err := r.Repository.CountCustomerOrders(ctx, db.CountCustomerOrdersParams{
+		ShopID:         shopID,
+		ShopCustomerID: customerID,
+		Email:          email,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to count orders: %w", err)
+	}
+	currency, err := r.shopCurrency(ctx, shopID)
+	if err != nil {
+		return nil, err
+	}
+
+	hasNextPage := len(rows) > limit
+	if hasNextPage {
+		rows = rows[:limit]
+	}
+	edges := make([]model.OrderEdge, 0, len(rows))
+	for _, row := range rows {
+		order, err := r.orderFromDB(ctx, row, currency)
+		if err != nil {
+			return nil, err
+		}
+		edges = append(edges, model.OrderEdge{Cursor: order.ID, Node: order})
+	}
+
+	pageInfo := &model.PageInfo{HasNextPage: hasNextPage}
+	if len(edges) > 0 {
+		pageInfo.StartCursor = edges[0].Cursor
+		pageInfo.EndCursor = edges[len(edges)-1].Cursor
+	}
+	return &model.OrderConnection{
+		Edges:      edges,
+		PageInfo:   pageInfo,
+		TotalCount: int(totalCount),
+	}, nil
+}
+
+// orderFromDB converts an order and its items, with amounts in the shop currency
+func (r *Resolver) orderFromDB(ctx context.Context, orderDB db.Order, currency string) (*model.Order, error) {
+	itemsDB, err := r.Repository.GetOrderItemsByOrder(ctx, db.GetOrderItemsByOrderParams{
+		OrderID: orderDB.OrderID,
+		ShopID:  orderDB.ShopID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch order items: %w", err)
+	}
+	items := make([]model.OrderItem, 0, len(itemsDB))
+	for _, item := range itemsDB {
+		items = append(items, model.OrderItem{
+			ID:                 EncodeIntID("OrderItem", item.OrderItemID),
+			OrderItemID:        int(item.OrderItemID),
+			Quantity:           int(item.Quantity),
+			Price:              numericToMoney(item.Price, currency),
+			ProductVariationID: EncodeIntID("ProductVariant", item.ProductVariationID),
+		})
+	}
+
+	var customerID *string
+	if pgUUID := ParsePgUUID(orderDB.ShopCustomerID); pgUUID != nil {
+		idStr := EncodeUUIDID("Customer", *pgUUID)
+		customerID = &idStr
+	}
+
+	return &model.Order{
+		ID:              EncodeIntID("Order", orderDB.OrderID),
+		OrderID:         int(orderDB.OrderID),
+		Status:          model.OrderStatusType(strings.ToUpper(string(orderDB.Status))),
+		CreatedAt:       orderDB.CreatedAt.Time,
+		UpdatedAt:       orderDB.UpdatedAt.Time,
+		CustomerID:      customerID,
+		Amount:          numericToMoney(orderDB.Amount, currency),
+		Discount:        numericToMoney(orderDB.Discount, currency),
+		ShippingCost:    numericToMoney(orderDB.ShippingCost, currency),
+		Tax:             numericToMoney(orderDB.Tax, currency),
+		CurrencyCode:    currency,
+		ShippingAddress: addressFromSnapshot(orderDB.ShippingAddress),
+		PaymentMethod:   model.PaymentMethodType(strings.ToUpper(string(orderDB.PaymentMethod))),
+		PaymentStatus:   model.PaymentStatusType(strings.ToUpper(string(orderDB.PaymentStatus))),
+		ShippingMethod:  orderDB.ShippingMethod,
+		ShippingStatus:  model.ShippingStatusType(strings.ToUpper(string(orderDB.ShippingStatus))),
+		TransactionID:   orderDB.TransactionID,
+		Username:        orderDB.Username,
+		ShopID:          EncodeIntID("Shop", orderDB.ShopID),
+		Items:           items,
+		CustomerName:    orderDB.CustomerName,
+		CustomerEmail:   orderDB.CustomerEmail,
+		CustomerPhone:   orderDB.CustomerPhone,
+	}, nil
+}
+
+func subscriptionPlanFromDB(plan db.SubscriptionPlan) model.SubscriptionPlan {
+	return model.SubscriptionPlan{
+		ID:              EncodeIntID("SubscriptionPlan", plan.PlanID),
+		Name:            plan.Name,
+		Interval:        model.SubscriptionInterval(strings.ToUpper(string(plan.IntervalUnit))),
+		IntervalCount:   int(plan.IntervalCount),
+		DiscountPercent: models.NumericToFloat64(plan.DiscountPercent),
+		TrialDays:       int(plan.TrialDays),
+	}
+}
+
+func customerSubscriptionFromDB(subscription db.GetSubscriptionRow) *model.CustomerSubscription {
+	result := &model.CustomerSubscription{
+		ID:               EncodeIntID("CustomerSubscription", subscription.SubscriptionID),
+		PlanID:           EncodeIntID("SubscriptionPlan", subscription.PlanID),
+		PlanName:         subscription.PlanName,
+		Interval:         model.SubscriptionInterval(strings.ToUpper(string(subscription.IntervalUnit))),
+		IntervalCount:    int(subscription.IntervalCount),
+		DiscountPercent:  models.NumericToFloat64(subscription.DiscountPercent),
+		ProductVariantID: EncodeIntID("ProductVariant", subscription.ProductVariationID),
+		Quantity:         int(subscription.Quantity),
+		Status:           model.SubscriptionStatus(strings.ToUpper(string(subscription.Status))),
+		PaymentMethodID:  EncodeIntID("SavedPaymentMethod", subscription.CustomerPaymentMethodID),
+		ShippingAddress:  addressFromSnapshot(subscription.ShippingAddress),
+		ShippingMethod:   subscription.ShippingMethod,
+		NextRenewalAt:    subscription.NextRenewalAt.Time,
+		CreatedAt:        subscription.CreatedAt.Time,
+	}
+	if subscription.PausedUntil.Valid {
+		result.PausedUntil = &subscription.PausedUntil.Time
+	}
+	if subscription.NextRetryAt.Valid {
+		result.NextRetryAt = &subscription.NextRetryAt.Time
+	}
+	if subscription.CancelledAt.Valid {
+		result.CancelledAt = &subscription.CancelledAt.Time
+	}
+	return result
+}
+
+func savedPaymentMethodFromDB(method db.CustomerPaymentMethod) model.SavedPaymentMethod {
+	result := model.SavedPaymentMethod{
+		ID:        EncodeIntID("SavedPaymentMethod", method.CustomerPaymentMethodID),
+		Provider:  model.PaymentMethodType(strings.ToUpper(string(method.Provider))),
+		Brand:     method.Brand,
+		Last4:     method.Last4,
+		CreatedAt: method.CreatedAt.Time,
+	}
+	if method.ExpMonth != nil {
+		month := int(*method.ExpMonth)
+		result.ExpMonth = &month
+	}
+	if method.ExpYear != nil {
+		year := int(*method.ExpYear)
+		result.ExpYear = &year
+	}
+	return result
+}
+
+// savedPaymentMethods returns a customer's saved payment methods, oldest first
+func (r *Resolver) savedPaymentMethods(ctx context.Context, shopID int64, customerID uuid.UUID) ([]model.SavedPaymentMethod, error) {
+	rows, err := r.Repository.ListCustomerPaymentMethods(ctx, db.ListCustomerPaymentMethodsParams{
+		ShopCustomerID: customerID,
+		ShopID:         shopID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch payment methods: %w", err)
+	}
+	methods := make([]model.SavedPaymentMethod, len(rows))
+	for i, row := range rows {
+		methods[i] = savedPaymentMethodFromDB(row)
+	}
+	return methods, nil
+}
+
+// savedPaymentMethodPayload returns the changed payment method along with all
+// of the customer's saved payment methods
+func (r *Resolver) savedPaymentMethodPayload(ctx context.Context, shopID int64, customerID uuid.UUID, changed *db.CustomerPaymentMethod) (*model.SavedPaymentMethodPayload, error) {
+	methods, err := r.savedPaymentMethods(ctx, shopID, customerID)
+	if err != nil {
+		return nil, err
+	}
+	payload := &model.SavedPaymentMethodPayload{PaymentMethods: methods, Errors: []model.UserError{}}
+	if changed != nil {
+		method := savedPaymentMethodFromDB(*changed)
+		payload.PaymentMethod = &method
+	}
+	return payload, nil
+}
+
+func savedPaymentMethodPayloadError(message string, code model.ErrorCode, path ...string) *model.SavedPaymentMethodPayload {
+	return &model.SavedPaymentMethodPayload{
+		PaymentMethods: []model.SavedPaymentMethod{},
+		Errors:         []model.UserError{&model.SubscriptionError{Message: message, Code: code, Path: path}},
+	}
+}
+
+// customerSubscriptionPayload returns a subscription of the signed-in customer
+func (r *Resolver) customerSubscriptionPayload(ctx context.Context, shopID int64, customerID uuid.UUID, subscriptionID int64) (*model.SubscriptionPayload, error) {
+	subscription, err := r.Repository.GetCustomerSubscription(ctx, db.GetCustomerSubscriptionParams{
+		SubscriptionID: subscriptionID,
+		ShopCustomerID: customerID,
+		ShopID:         shopID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return subscriptionPayloadError("Subscription not found", model.ErrorCodeValidationInvalidInput, "id"), nil
+		}
+		return nil, fmt.Errorf("failed to fetch subscription: %w", err)
+	}
+	return &model.SubscriptionPayload{
+		Subscription: customerSubscriptionFromDB(db.GetSubscriptionRow(subscription)),
+		Errors:       []model.UserError{},
+	}, nil
+}
+
+func subscriptionPayloadError(message string, code model.ErrorCode, path ...string) *model.SubscriptionPayload {
+	return &model.SubscriptionPayload{
+		Errors: []model.UserError{&model.SubscriptionError{Message: message, Code: code, Path: path}},
+	}
+}
+
+// subscriptionActionError turns the errors of the subscription actions customers
+// take into user errors
+func subscriptionActionError(err error) (*model.SubscriptionPayload, error) {
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return subscriptionPayloadError("Subscription not found", model.ErrorCodeValidationInvalidInput, "id"), nil
+	case errors.Is(err, services.ErrSubscriptionNotPausable),
+		errors.Is(err, services.ErrSubscriptionNotPaused),
+		errors.Is(err, services.ErrSubscriptionNotSkippable),
+		errors.Is(err, services.ErrSubscriptionCancelled):
+		return subscriptionPayloadError(err.Error(), model.ErrorCodeValidationInvalidInput, "id"), nil
+	}
+	return nil, fmt.Errorf("failed to update subscription: %w", err)
+}
+
+// customerSubscriptionID decodes the ID of a subscription and checks that it
+// belongs to the signed-in customer. The payload holds the user error otherwise.
+func (r *Resolver) customerSubscriptionID(ctx context.Context, shopID int64, id string) (uuid.UUID, int64, *model.SubscriptionPayload, error) {
+	customerID, ok := currentCustomer(ctx)
+	if !ok {
+		return uuid.Nil, 0, subscriptionPayloadError("Sign in to manage subscriptions", model.ErrorCodeAuthInvalidToken, "id"), nil
+	}
+	subscriptionID, err := DecodeRelayID(id)
+	if err != nil || subscriptionID.Type != "CustomerSubscription" || subscriptionID.IntID == nil {
+		return uuid.Nil, 0, subscriptionPayloadError("Invalid subscription ID", model.ErrorCodeValidationInvalidInput, "id"), nil
+	}
+	payload, err := r.customerSubscriptionPayload(ctx, shopID, customerID, *subscriptionID.IntID)
+	if err != nil || len(payload.Errors) > 0 {
+		return uuid.Nil, 0, payload, err
+	}
+	return customerID, *subscriptionID.IntID, nil, nil
 }
@@ -0,0 +1,238 @@
+package resolver
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/petrejonn/naytife/internal/api/models"
+	"github.com/petrejonn/naytife/internal/db"
+	"github.com/petrejonn/naytife/internal/gql/public/model"
+	"github.com/petrejonn/naytife/internal/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShippingAddressFromInput(t *testing.T) {
+	valid := func() model.AddressInput {
+		return model.AddressInput{
+			FirstName:    " Ada ",
+			LastName:     "Lovelace",
+			AddressLine1: " 12 Marina Road ",
+			City:         "Lagos",
+			PostalCode:   " 101001 ",
+			Country:      " ng ",
+		}
+	}
+
+	tests := []struct {
+		name     string
+		input    func(*model.AddressInput)
+		wantPath []string
+	}{
+		{name: "valid address"},
+		{name: "missing first name", input: func(in *model.AddressInput) { in.FirstName = "  " }, wantPath: []string{"input", "shippingAddress", "firstName"}},
+		{name: "missing postal code", input: func(in *model.AddressInput) { in.PostalCode = "" }, wantPath: []string{"input", "shippingAddress", "postalCode"}},
+		{name: "unknown country", input: func(in *model.AddressInput) { in.Country = "XX" }, wantPath: []string{"input", "shippingAddress", "country"}},
+		{name: "country name instead of code", input: func(in *model.AddressInput) { in.Country = "Nigeria" }, wantPath: []string{"input", "shippingAddress", "country"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := valid()
+			if tt.input != nil {
+				tt.input(&input)
+			}
+			address, userErr := shippingAddressFromInput(input, "input", "shippingAddress")
+			if tt.wantPath != nil {
+				require.NotNil(t, userErr)
+				assert.Equal(t, tt.wantPath, userErr.(*model.AddressError).Path)
+				return
+			}
+			require.Nil(t, userErr)
+			assert.Equal(t, "Ada", address.FirstName)
+			assert.Equal(t, "12 Marina Road", address.AddressLine1)
+			assert.Equal(t, "101001", address.PostalCode)
+			assert.Equal(t, "NG", address.Country)
+		})
+	}
+}
+
+func TestAddressFromSnapshot(t *testing.T) {
+	t.Run("structured address", func(t *testing.T) {
+		company := "Analytical Engines"
+		snapshot, err := models.ShippingAddress{
+			FirstName:    "Ada",
+			LastName:     "Lovelace",
+			Company:      &company,
+			AddressLine1: "12 Marina Road",
+			City:         "Lagos",
+			PostalCode:   "101001",
+			Country:      "NG",
+		}.Snapshot()
+		require.NoError(t, err)
+
+		address := addressFromSnapshot(snapshot)
+		assert.Equal(t, "Ada", address.FirstName)
+		assert.Equal(t, &company, address.Company)
+		assert.Equal(t, "NG", address.Country)
+	})
+
+	t.Run("order placed before addresses were structured", func(t *testing.T) {
+		address := addressFromSnapshot([]byte(`{"address_line_1":"12 Marina Road, Lagos"}`))
+		assert.Equal(t, "12 Marina Road, Lagos", address.AddressLine1)
+		assert.Empty(t, address.Country)
+	})
+
+	t.Run("no snapshot", func(t *testing.T) {
+		assert.Equal(t, &model.Address{}, addressFromSnapshot(nil))
+	})
+}
+
+// addressRepository serves the address book of a single customer
+type addressRepository struct {
+	db.Repository
+	customerID uuid.UUID
+	address    db.CustomerAddress
+}
+
+func (r *addressRepository) GetCustomerAddress(ctx context.Context, arg db.GetCustomerAddressParams) (db.CustomerAddress, error) {
+	if arg.ShopCustomerID != r.customerID || arg.AddressID != r.address.AddressID {
+		return db.CustomerAddress{}, pgx.ErrNoRows
+	}
+	return r.address, nil
+}
+
+func TestOrderShippingAddress(t *testing.T) {
+	customerID := uuid.New()
+	repo := &addressRepository{
+		customerID: customerID,
+		address: db.CustomerAddress{
+			AddressID:      5,
+			ShopCustomerID: customerID,
+			FirstName:      "Ada",
+			LastName:       "Lovelace",
+			AddressLine1:   "12 Marina Road",
+			City:           "Lagos",
+			PostalCode:     "101001",
+			Country:        "NG",
+		},
+	}
+	r := &Resolver{Repository: repo}
+	signedIn := context.WithValue(context.Background(), "shop_customer_id", customerID)
+	savedID := EncodeIntID("CustomerAddress", 5)
+	otherID := EncodeIntID("CustomerAddress", 6)
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		input    model.CreateOrderInput
+		wantCity string
+		wantCode model.ErrorCode
+	}{
+		{
+			name:     "saved address of the signed-in customer",
+			ctx:      signedIn,
+			input:    model.CreateOrderInput{ShippingAddressID: &savedID},
+			wantCity: "Lagos",
+		},
+		{
+			name:     "saved address without signing in",
+			ctx:      context.Background(),
+			input:    model.CreateOrderInput{ShippingAddressID: &savedID},
+			wantCode: model.ErrorCodeAuthInvalidToken,
+		},
+		{
+			name:     "address of another customer",
+			ctx:      signedIn,
+			input:    model.CreateOrderInput{ShippingAddressID: &otherID},
+			wantCode: model.ErrorCodeValidationInvalidInput,
+		},
+		{
+			name: "address input",
+			ctx:  context.Background(),
+			input: model.CreateOrderInput{ShippingAddress: &model.AddressInput{
+				FirstName: "Grace", LastName: "Hopper", AddressLine1: "1 Navy Way", City: "Accra", PostalCode: "00233", Country: "gh",
+			}},
+			wantCity: "Accra",
+		},
+		{
+			name:     "no address",
+			ctx:      context.Background(),
+			wantCode: model.ErrorCodeValidationInvalidInput,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			snapshot, userErr, err := r.orderShippingAddress(tt.ctx, 1, tt.input.ShippingAddressID, tt.input.ShippingAddress)
+			require.NoError(t, err)
+			if tt.wantCode != "" {
+				require.NotNil(t, userErr)
+				assert.Equal(t, tt.wantCode, userErr.(*model.AddressError).Code)
+				return
+			}
+			require.Nil(t, userErr)
+			var address models.ShippingAddress
+			require.NoError(t, json.Unmarshal(snapshot, &address))
+			assert.Equal(t, tt.wantCity, address.City)
+		})
+	}
+}
+
+// customerPriceRepository gives variant 20 a group price for one customer
+type customerPriceRepository struct {
+	db.Repository
+	customerID uuid.UUID
+}
+
+func (r *customerPriceRepository) GetCustomerVariantPrices(ctx context.Context, arg db.GetCustomerVariantPricesParams) ([]db.GetCustomerVariantPricesRow, error) {
+	if arg.ShopCustomerID != r.customerID {
+		return nil, nil
+	}
+	return []db.GetCustomerVariantPricesRow{
+		{ProductVariationID: 20, Price: money.New(1500, "USD").Numeric()},
+	}, nil
+}
+
+func TestApplyCustomerPrices(t *testing.T) {
+	customerID := uuid.New()
+	r := &Resolver{Repository: &customerPriceRepository{customerID: customerID}}
+	items := []model.CreateOrderItemInput{
+		{ProductVariationID: EncodeIntID("ProductVariant", 20), Quantity: 1},
+		{ProductVariationID: EncodeIntID("ProductVariant", 21), Quantity: 1},
+		{ProductVariationID: "not-an-id", Quantity: 1},
+	}
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want []money.Money
+	}{
+		{
+			name: "guest pays the submitted prices",
+			ctx:  context.Background(),
+			want: []money.Money{money.New(2000, "USD"), money.New(3000, "USD"), money.New(4000, "USD")},
+		},
+		{
+			name: "group member pays the group price",
+			ctx:  context.WithValue(context.Background(), "shop_customer_id", customerID),
+			want: []money.Money{money.New(1500, "USD"), money.New(3000, "USD"), money.New(4000, "USD")},
+		},
+		{
+			name: "customer outside the group",
+			ctx:  context.WithValue(context.Background(), "shop_customer_id", uuid.New()),
+			want: []money.Money{money.New(2000, "USD"), money.New(3000, "USD"), money.New(4000, "USD")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prices := []money.Money{money.New(2000, "USD"), money.New(3000, "USD"), money.New(4000, "USD")}
+			require.NoError(t, r.applyCustomerPrices(tt.ctx, 1, "USD", items, prices))
+			assert.Equal(t, tt.want, prices)
+		})
+	}
+}
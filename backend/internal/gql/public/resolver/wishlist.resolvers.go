@@ -0,0 +1,166 @@
+package resolver
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.76
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	pgx "github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/petrejonn/naytife/internal/db"
+	"github.com/petrejonn/naytife/internal/gql/public/model"
+)
+
+// AddToWishlist is the resolver for the addToWishlist field.
+func (r *mutationResolver) AddToWishlist(ctx context.Context, productVariantID string) (*model.WishlistPayload, error) {
+	shopID := ctx.Value("shop_id").(int64)
+	fail := func(message string, code model.ErrorCode) *model.WishlistPayload {
+		return &model.WishlistPayload{
+			Items:  []model.WishlistItem{},
+			Errors: []model.UserError{&model.WishlistError{Message: message, Code: code, Path: []string{"productVariantId"}}},
+		}
+	}
+
+	customerID, ok := currentCustomer(ctx)
+	if !ok {
+		return fail("Sign in to use a wishlist", model.ErrorCodeAuthInvalidToken), nil
+	}
+	variantID, err := DecodeRelayID(productVariantID)
+	if err != nil || variantID.Type != "ProductVariant" || variantID.IntID == nil {
+		return fail("Invalid product variant ID", model.ErrorCodeValidationInvalidInput), nil
+	}
+	if _, err := r.Repository.GetProductVariation(ctx, db.GetProductVariationParams{
+		ProductVariationID: *variantID.IntID,
+		ShopID:             shopID,
+	}); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fail("Product variant not found", model.ErrorCodeValidationInvalidInput), nil
+		}
+		return nil, fmt.Errorf("failed to fetch product variant: %w", err)
+	}
+
+	if err := r.Repository.AddWishlistItem(ctx, db.AddWishlistItemParams{
+		ShopCustomerID:     customerID,
+		ProductVariationID: *variantID.IntID,
+		ShopID:             shopID,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to add wishlist item: %w", err)
+	}
+
+	items, err := r.wishlistItems(ctx, shopID, customerID, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &model.WishlistPayload{Items: items, Errors: []model.UserError{}}, nil
+}
+
+// RemoveFromWishlist is the resolver for the removeFromWishlist field.
+func (r *mutationResolver) RemoveFromWishlist(ctx context.Context, productVariantID string) (*model.WishlistPayload, error) {
+	shopID := ctx.Value("shop_id").(int64)
+	fail := func(message string, code model.ErrorCode) *model.WishlistPayload {
+		return &model.WishlistPayload{
+			Items:  []model.WishlistItem{},
+			Errors: []model.UserError{&model.WishlistError{Message: message, Code: code, Path: []string{"productVariantId"}}},
+		}
+	}
+
+	customerID, ok := currentCustomer(ctx)
+	if !ok {
+		return fail("Sign in to use a wishlist", model.ErrorCodeAuthInvalidToken), nil
+	}
+	variantID, err := DecodeRelayID(productVariantID)
+	if err != nil || variantID.Type != "ProductVariant" || variantID.IntID == nil {
+		return fail("Invalid product variant ID", model.ErrorCodeValidationInvalidInput), nil
+	}
+
+	if _, err := r.Repository.RemoveWishlistItem(ctx, db.RemoveWishlistItemParams{
+		ShopCustomerID:     customerID,
+		ProductVariationID: *variantID.IntID,
+		ShopID:             shopID,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to remove wishlist item: %w", err)
+	}
+
+	items, err := r.wishlistItems(ctx, shopID, customerID, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &model.WishlistPayload{Items: items, Errors: []model.UserError{}}, nil
+}
+
+// SubscribeBackInStock is the resolver for the subscribeBackInStock field.
+func (r *mutationResolver) SubscribeBackInStock(ctx context.Context, input model.BackInStockInput) (*model.BackInStockPayload, error) {
+	shopID := ctx.Value("shop_id").(int64)
+	variantID, email, userErr := backInStockTarget(ctx, input)
+	if userErr != nil {
+		return &model.BackInStockPayload{Errors: []model.UserError{userErr}}, nil
+	}
+
+	variant, err := r.Repository.GetProductVariation(ctx, db.GetProductVariationParams{
+		ProductVariationID: variantID,
+		ShopID:             shopID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return &model.BackInStockPayload{Errors: []model.UserError{&model.WishlistError{
+				Message: "Product variant not found",
+				Code:    model.ErrorCodeValidationInvalidInput,
+				Path:    []string{"input", "productVariantId"},
+			}}}, nil
+		}
+		return nil, fmt.Errorf("failed to fetch product variant: %w", err)
+	}
+	if variant.AvailableQuantity > 0 {
+		return &model.BackInStockPayload{Errors: []model.UserError{&model.WishlistError{
+			Message: "Product variant is in stock",
+			Code:    model.ErrorCodeValidationInvalidInput,
+			Path:    []string{"input", "productVariantId"},
+		}}}, nil
+	}
+
+	var customer pgtype.UUID
+	if customerID, ok := currentCustomer(ctx); ok {
+		customer = GetPgUUID(customerID)
+	}
+	if _, err := r.Repository.UpsertBackInStockSubscription(ctx, db.UpsertBackInStockSubscriptionParams{
+		ProductVariationID: variantID,
+		Email:              email,
+		ShopCustomerID:     customer,
+		ShopID:             shopID,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to subscribe: %w", err)
+	}
+	return &model.BackInStockPayload{Subscribed: true, Errors: []model.UserError{}}, nil
+}
+
+// UnsubscribeBackInStock is the resolver for the unsubscribeBackInStock field.
+func (r *mutationResolver) UnsubscribeBackInStock(ctx context.Context, input model.BackInStockInput) (*model.BackInStockPayload, error) {
+	shopID := ctx.Value("shop_id").(int64)
+	variantID, email, userErr := backInStockTarget(ctx, input)
+	if userErr != nil {
+		return &model.BackInStockPayload{Errors: []model.UserError{userErr}}, nil
+	}
+
+	if _, err := r.Repository.DeleteBackInStockSubscription(ctx, db.DeleteBackInStockSubscriptionParams{
+		ProductVariationID: variantID,
+		Email:              email,
+		ShopID:             shopID,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to unsubscribe: %w", err)
+	}
+	return &model.BackInStockPayload{Subscribed: false, Errors: []model.UserError{}}, nil
+}
+
+// Wishlist is the resolver for the wishlist field.
+func (r *queryResolver) Wishlist(ctx context.Context, currency *string) ([]model.WishlistItem, error) {
+	shopID := ctx.Value("shop_id").(int64)
+	customerID, ok := currentCustomer(ctx)
+	if !ok {
+		return nil, errors.New("sign in to use a wishlist")
+	}
+	return r.wishlistItems(ctx, shopID, customerID, currency)
+}
@@ -1,13 +1,19 @@
 package middleware
 
 import (
+	"encoding/json"
+	"errors"
+	"io"
 	"os"
 	"strconv"
 	"strings"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
 	"github.com/petrejonn/naytife/internal/api"
 	"github.com/petrejonn/naytife/internal/db"
+	"github.com/petrejonn/naytife/internal/services"
+	"go.uber.org/zap"
 )
 
 func ShopIDMiddlewareFiber(repo db.Repository) fiber.Handler {
@@ -53,6 +59,136 @@ func ShopIDMiddlewareFiber(repo db.Repository) fiber.Handler {
 	}
 }
 
+// LocaleMiddlewareFiber resolves the storefront locale of a request from the
+// "locale" query parameter or the Accept-Language header, falling back to the
+// shop's default locale. It must run after ShopIDMiddlewareFiber.
+func LocaleMiddlewareFiber(repo db.Repository) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		shopID, ok := c.Locals("shop_id").(int64)
+		if !ok {
+			return c.Next()
+		}
+
+		rows, err := repo.GetShopLocales(c.UserContext(), shopID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to load shop locales",
+			})
+		}
+		defaultLocale, locales := services.ShopLocales(rows)
+
+		requested := c.Query("locale")
+		if requested == "" {
+			requested = c.Get(fiber.HeaderAcceptLanguage)
+		}
+		locale := services.MatchLocale(locales, requested)
+
+		c.Locals("locale", locale)
+		c.Locals("default_locale", defaultLocale)
+		c.Set(fiber.HeaderContentLanguage, locale)
+		return c.Next()
+	}
+}
+
+// BodyShopIDMiddlewareFiber takes the shop of a request from the shop_id field of
+// its JSON body, for routes that name the shop there rather than in the host, so
+// that CustomerMiddlewareFiber can run after it. The shop is not looked up; the
+// handler still loads everything it needs by that shop. Requests without one
+// continue without a shop.
+func BodyShopIDMiddlewareFiber() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var body struct {
+			ShopID int64 `json:"shop_id"`
+		}
+		if err := json.Unmarshal(c.Body(), &body); err == nil && body.ShopID > 0 {
+			c.Locals("shop_id", body.ShopID)
+		}
+		return c.Next()
+	}
+}
+
+// CustomerSessionCookie holds the signed session of a storefront customer
+const CustomerSessionCookie = "naytife_customer_session"
+
+// CustomerMiddlewareFiber identifies the shop customer making a storefront request.
+// A bearer access token is verified at Hydra and exchanged for a signed session
+// cookie, so later requests may send the cookie alone. Requests without a token,
+// with a stale session or for an unknown customer continue anonymously; invalid
+// tokens and tokens of another shop are rejected. It must run after
+// ShopIDMiddlewareFiber.
+//
+// The storefront route is public, so an X-User-Id header on it was set by the
+// caller rather than the gateway and is dropped before anything can trust it.
+func CustomerMiddlewareFiber(repo db.Repository, auth *services.CustomerAuth) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.Request().Header.Del("X-User-Id")
+
+		shopID, ok := c.Locals("shop_id").(int64)
+		if !ok {
+			return c.Next()
+		}
+
+		var identity services.CustomerIdentity
+		if token, found := strings.CutPrefix(c.Get(fiber.HeaderAuthorization), "Bearer "); found {
+			verified, err := auth.VerifyAccessToken(c.UserContext(), strings.TrimSpace(token))
+			if err != nil && !errors.Is(err, services.ErrInvalidCustomerToken) {
+				zap.L().Error("CustomerMiddlewareFiber: failed to verify access token", zap.Int64("shop_id", shopID), zap.Error(err))
+				return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+					"error": "Failed to verify customer token",
+				})
+			}
+			if err != nil || verified.ShopID != shopID {
+				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+					"error": "Invalid customer token",
+				})
+			}
+			identity = verified
+
+			session, err := auth.SignSession(identity)
+			if err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+					"error": "Failed to create customer session",
+				})
+			}
+			c.Cookie(&fiber.Cookie{
+				Name:     CustomerSessionCookie,
+				Value:    session,
+				Path:     "/",
+				Expires:  identity.ExpiresAt,
+				Secure:   c.Protocol() == "https" || c.Get(fiber.HeaderXForwardedProto) == "https",
+				HTTPOnly: true,
+				SameSite: fiber.CookieSameSiteLaxMode,
+			})
+		} else if session := c.Cookies(CustomerSessionCookie); session != "" {
+			verified, err := auth.VerifySession(session)
+			if err != nil || verified.ShopID != shopID {
+				c.ClearCookie(CustomerSessionCookie)
+				return c.Next()
+			}
+			identity = verified
+		} else {
+			return c.Next()
+		}
+
+		customer, err := repo.GetShopCustomerByEmail(c.UserContext(), db.GetShopCustomerByEmailParams{
+			Email:  identity.Email,
+			ShopID: shopID,
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return c.Next()
+			}
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to load customer",
+			})
+		}
+
+		c.Locals("shop_customer_id", customer.ShopCustomerID)
+		c.Locals("shop_customer_email", customer.Email)
+		return c.Next()
+	}
+}
+
 // Helper function to parse int64 safely
 func parseInt64(s string) int64 {
 	if val, err := strconv.ParseInt(s, 10, 64); err == nil {
@@ -81,6 +217,51 @@ func WebMiddlewareFiber() fiber.Handler {
 	}
 }
 
+// BodyLimitFiber reads request bodies of up to limit bytes into memory and rejects
+// larger ones. The app streams request bodies so that upload routes can take larger
+// files; the requests skip returns true for are left to UploadLimitFiber.
+func BodyLimitFiber(limit int, skip func(c *fiber.Ctx) bool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		req := c.Request()
+		stream := req.BodyStream()
+		if stream == nil || skip(c) {
+			return c.Next()
+		}
+		if req.Header.ContentLength() > limit {
+			c.Context().SetConnectionClose()
+			return fiber.ErrRequestEntityTooLarge
+		}
+		body, err := io.ReadAll(io.LimitReader(stream, int64(limit)+1))
+		if err != nil {
+			return fiber.ErrBadRequest
+		}
+		if len(body) > limit {
+			c.Context().SetConnectionClose()
+			return fiber.ErrRequestEntityTooLarge
+		}
+		req.SetBody(body)
+		return c.Next()
+	}
+}
+
+// UploadLimitFiber limits the body of an upload route to limit bytes. Uploads must
+// declare their length, and their files are read from the stream into temporary
+// files rather than memory.
+func UploadLimitFiber(limit int64) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		length := c.Request().Header.ContentLength()
+		if length < 0 {
+			c.Context().SetConnectionClose()
+			return fiber.ErrLengthRequired
+		}
+		if int64(length) > limit {
+			c.Context().SetConnectionClose()
+			return fiber.ErrRequestEntityTooLarge
+		}
+		return c.Next()
+	}
+}
+
 func GlobalErrorHandler(c *fiber.Ctx) error {
 	// Proceed to the next middleware/handler
 	err := c.Next()
@@ -0,0 +1,288 @@
+// Package money represents amounts of money exactly, as integer minor units of an
+// ISO 4217 currency, and converts them to and from the decimal strings used by the
+// API, the DECIMAL columns used by the database and the minor units used by payment
+// providers.
+package money
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Money is an amount in the minor units of its currency, e.g. {1250, "NGN"} is
+// NGN 12.50 and {500, "XOF"} is XOF 500 because the CFA franc has no minor unit.
+type Money struct {
+	Amount   int64
+	Currency string
+}
+
+// unknownExponent is the scale of amounts parsed before their currency is known,
+// such as API inputs whose currency is the shop's. Use In to move them into a currency.
+const unknownExponent = 4
+
+// exponents lists the ISO 4217 currencies whose minor unit is not a hundredth
+var exponents = map[string]int{
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "ISK": 0, "JPY": 0, "KMF": 0, "KRW": 0, "PYG": 0,
+	"RWF": 0, "UGX": 0, "UYI": 0, "VND": 0, "VUV": 0, "XAF": 0, "XOF": 0, "XPF": 0,
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+}
+
+// Exponent returns the number of decimal places of a currency's minor unit
+func Exponent(currency string) int {
+	if currency == "" {
+		return unknownExponent
+	}
+	if exp, ok := exponents[strings.ToUpper(currency)]; ok {
+		return exp
+	}
+	return 2
+}
+
+// New returns an amount given in minor units
+func New(minor int64, currency string) Money {
+	return Money{Amount: minor, Currency: currency}
+}
+
+// Zero returns a zero amount of currency
+func Zero(currency string) Money {
+	return Money{Currency: currency}
+}
+
+// Parse parses a decimal amount in major units such as "12.50". It fails when the
+// amount has more decimal places than the currency's minor unit allows.
+func Parse(s string, currency string) (Money, error) {
+	s = strings.TrimSpace(s)
+	if strings.Contains(s, "/") {
+		return Money{}, fmt.Errorf("invalid amount %q", s)
+	}
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return Money{}, fmt.Errorf("invalid amount %q", s)
+	}
+	return fromRat(r, currency, true)
+}
+
+// FromFloat converts an amount in major units, rounding half away from zero to the
+// currency's minor unit. It is meant for values that arrive as floats, such as
+// amounts in payment provider payloads.
+func FromFloat(f float64, currency string) Money {
+	r, ok := floatRat(f)
+	if !ok {
+		return Zero(currency)
+	}
+	m, _ := fromRat(r, currency, false)
+	return m
+}
+
+// FromNumeric converts a DECIMAL column value in major units, rounding half away
+// from zero to the currency's minor unit. NULL converts to zero.
+func FromNumeric(n pgtype.Numeric, currency string) (Money, error) {
+	if !n.Valid {
+		return Zero(currency), nil
+	}
+	if n.NaN || n.InfinityModifier != pgtype.Finite {
+		return Money{}, fmt.Errorf("amount is not a finite number")
+	}
+	r := new(big.Rat).SetInt(n.Int)
+	if n.Exp > 0 {
+		r.Mul(r, new(big.Rat).SetInt(pow10(int(n.Exp))))
+	} else if n.Exp < 0 {
+		r.Quo(r, new(big.Rat).SetInt(pow10(int(-n.Exp))))
+	}
+	return fromRat(r, currency, false)
+}
+
+// Numeric returns the amount in major units for a DECIMAL column
+func (m Money) Numeric() pgtype.Numeric {
+	return pgtype.Numeric{Int: big.NewInt(m.Amount), Exp: -int32(Exponent(m.Currency)), Valid: true}
+}
+
+// Rat returns the amount in major units
+func (m Money) Rat() *big.Rat {
+	return new(big.Rat).SetFrac(big.NewInt(m.Amount), pow10(Exponent(m.Currency)))
+}
+
+// Float64 returns the nearest float to the amount in major units, for providers
+// whose APIs take floats
+func (m Money) Float64() float64 {
+	f, _ := m.Rat().Float64()
+	return f
+}
+
+// String formats the amount in major units with all of the currency's decimal places, e.g. "12.50"
+func (m Money) String() string {
+	exp := Exponent(m.Currency)
+	digits := new(big.Int).Abs(big.NewInt(m.Amount)).String()
+	if len(digits) <= exp {
+		digits = strings.Repeat("0", exp-len(digits)+1) + digits
+	}
+	s := digits
+	if exp > 0 {
+		s = digits[:len(digits)-exp] + "." + digits[len(digits)-exp:]
+	}
+	if m.Amount < 0 {
+		s = "-" + s
+	}
+	return s
+}
+
+// In returns the same amount expressed in the minor units of currency. It is used
+// to give amounts parsed without a currency the currency they belong to, and fails
+// when the amount has more decimal places than that currency allows.
+func (m Money) In(currency string) (Money, error) {
+	if m.Currency == currency {
+		return m, nil
+	}
+	return fromRat(m.Rat(), currency, true)
+}
+
+// Round returns the amount expressed in the minor units of currency, rounding half
+// away from zero where the amount has more decimal places than currency allows
+func (m Money) Round(currency string) Money {
+	rounded, _ := fromRat(m.Rat(), currency, false)
+	return rounded
+}
+
+// Add returns m + o. Both amounts must be in the same currency.
+func (m Money) Add(o Money) Money {
+	return Money{Amount: m.Amount + o.Amount, Currency: m.Currency}
+}
+
+// Sub returns m - o. Both amounts must be in the same currency.
+func (m Money) Sub(o Money) Money {
+	return Money{Amount: m.Amount - o.Amount, Currency: m.Currency}
+}
+
+// Mul returns m multiplied by a quantity
+func (m Money) Mul(quantity int64) Money {
+	return Money{Amount: m.Amount * quantity, Currency: m.Currency}
+}
+
+// IsZero reports whether the amount is zero
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+// IsNegative reports whether the amount is below zero
+func (m Money) IsNegative() bool {
+	return m.Amount < 0
+}
+
+// Convert converts m into currency at rate, the number of units of currency one unit
+// of m's currency buys, rounding half away from zero to the minor unit
+func (m Money) Convert(rate float64, currency string) Money {
+	r, ok := floatRat(rate)
+	if !ok {
+		return Zero(currency)
+	}
+	converted, _ := fromRat(r.Mul(r, m.Rat()), currency, false)
+	return converted
+}
+
+// MarshalJSON encodes the amount as a decimal string in major units, e.g. "12.50"
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(m.String())
+}
+
+// UnmarshalJSON accepts a decimal string or a JSON number in major units. The amount
+// is read in m's currency when it is already set, otherwise use In to assign one.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+	if unquoted, err := strconv.Unquote(s); err == nil {
+		s = unquoted
+	}
+	parsed, err := Parse(s, m.Currency)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// MarshalGQL encodes the Money scalar as a decimal string in major units
+func (m Money) MarshalGQL(w io.Writer) {
+	_, _ = io.WriteString(w, strconv.Quote(m.String()))
+}
+
+// UnmarshalGQL reads the Money scalar from a decimal string or a number. The result
+// has no currency; resolvers assign the shop's with In.
+func (m *Money) UnmarshalGQL(v interface{}) error {
+	var s string
+	switch v := v.(type) {
+	case string:
+		s = v
+	case json.Number:
+		s = v.String()
+	case int:
+		s = strconv.Itoa(v)
+	case int64:
+		s = strconv.FormatInt(v, 10)
+	case float64:
+		s = strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Errorf("money must be a decimal string or a number, got %T", v)
+	}
+	parsed, err := Parse(s, "")
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+func fromRat(r *big.Rat, currency string, exact bool) (Money, error) {
+	scaled := new(big.Rat).Mul(r, new(big.Rat).SetInt(pow10(Exponent(currency))))
+	if exact && !scaled.IsInt() {
+		return Money{}, fmt.Errorf("amount %s has more decimal places than %s allows", ratString(r), currencyName(currency))
+	}
+	minor := roundHalfAwayFromZero(scaled)
+	if !minor.IsInt64() {
+		return Money{}, fmt.Errorf("amount %s is out of range", ratString(r))
+	}
+	return Money{Amount: minor.Int64(), Currency: currency}, nil
+}
+
+// floatRat converts a float through its shortest decimal representation, so that
+// 2.675 is read as 2.675 rather than the nearest binary value just below it
+func floatRat(f float64) (*big.Rat, bool) {
+	return new(big.Rat).SetString(strconv.FormatFloat(f, 'f', -1, 64))
+}
+
+func ratString(r *big.Rat) string {
+	s := r.FloatString(unknownExponent + 2)
+	s = strings.TrimRight(s, "0")
+	return strings.TrimSuffix(s, ".")
+}
+
+func roundHalfAwayFromZero(r *big.Rat) *big.Int {
+	quo, rem := new(big.Int).QuoRem(r.Num(), r.Denom(), new(big.Int))
+	twiceRem := new(big.Int).Mul(new(big.Int).Abs(rem), big.NewInt(2))
+	if twiceRem.Cmp(r.Denom()) >= 0 {
+		if r.Sign() < 0 {
+			quo.Sub(quo, big.NewInt(1))
+		} else {
+			quo.Add(quo, big.NewInt(1))
+		}
+	}
+	return quo
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+func currencyName(currency string) string {
+	if currency == "" {
+		return "an amount"
+	}
+	return currency
+}
@@ -0,0 +1,194 @@
+package money
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExponent(t *testing.T) {
+	tests := []struct {
+		currency string
+		want     int
+	}{
+		{"JPY", 0},
+		{"xof", 0},
+		{"NGN", 2},
+		{"USD", 2},
+		{"KWD", 3},
+		{"BHD", 3},
+		{"ZZZ", 2},
+		{"", unknownExponent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.currency, func(t *testing.T) {
+			assert.Equal(t, tt.want, Exponent(tt.currency))
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		currency string
+		want     int64
+		wantErr  bool
+	}{
+		{"two decimals", "12.50", "USD", 1250, false},
+		{"whole number", "12", "USD", 1200, false},
+		{"surrounding spaces", " 0.99 ", "USD", 99, false},
+		{"negative", "-3.10", "USD", -310, false},
+		{"zero decimals", "500", "JPY", 500, false},
+		{"three decimals", "1.234", "KWD", 1234, false},
+		{"no currency keeps four places", "1.2345", "", 12345, false},
+		{"too precise for currency", "12.345", "USD", 0, true},
+		{"decimals on zero exponent currency", "1.5", "JPY", 0, true},
+		{"fraction", "1/3", "USD", 0, true},
+		{"not a number", "abc", "USD", 0, true},
+		{"empty", "", "USD", 0, true},
+		{"out of range", "100000000000000000000", "USD", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.input, tt.currency)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, New(tt.want, tt.currency), got)
+		})
+	}
+}
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		money Money
+		want  string
+	}{
+		{New(1250, "USD"), "12.50"},
+		{New(5, "USD"), "0.05"},
+		{New(-5, "USD"), "-0.05"},
+		{New(500, "JPY"), "500"},
+		{New(1234, "KWD"), "1.234"},
+		{New(12345, ""), "1.2345"},
+		{Zero("USD"), "0.00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.money.String())
+		})
+	}
+}
+
+func TestNumericRoundTrip(t *testing.T) {
+	for _, m := range []Money{New(1250, "USD"), New(-99, "USD"), New(500, "JPY"), New(1234, "KWD"), New(12345, ""), Zero("NGN")} {
+		t.Run(m.Currency+" "+m.String(), func(t *testing.T) {
+			got, err := FromNumeric(m.Numeric(), m.Currency)
+			require.NoError(t, err)
+			assert.Equal(t, m, got)
+		})
+	}
+}
+
+func TestFromNumeric(t *testing.T) {
+	tests := []struct {
+		name     string
+		numeric  pgtype.Numeric
+		currency string
+		want     int64
+		wantErr  bool
+	}{
+		{"numeric(19,4) to two decimals", pgtype.Numeric{Int: big.NewInt(125000), Exp: -4, Valid: true}, "USD", 1250, false},
+		{"rounds half away from zero", pgtype.Numeric{Int: big.NewInt(12345), Exp: -3, Valid: true}, "USD", 1235, false},
+		{"rounds negative half away from zero", pgtype.Numeric{Int: big.NewInt(-12345), Exp: -3, Valid: true}, "USD", -1235, false},
+		{"positive exponent", pgtype.Numeric{Int: big.NewInt(12), Exp: 2, Valid: true}, "JPY", 1200, false},
+		{"null is zero", pgtype.Numeric{}, "USD", 0, false},
+		{"nan", pgtype.Numeric{NaN: true, Valid: true}, "USD", 0, true},
+		{"infinity", pgtype.Numeric{InfinityModifier: pgtype.Infinity, Valid: true}, "USD", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FromNumeric(tt.numeric, tt.currency)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, New(tt.want, tt.currency), got)
+		})
+	}
+}
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		name     string
+		money    Money
+		rate     float64
+		currency string
+		want     int64
+	}{
+		{"same exponent", New(1000, "USD"), 1500.5, "NGN", 1500500},
+		{"rounds half up", New(1, "USD"), 0.5, "EUR", 1},
+		{"rounds below half down", New(1, "USD"), 0.49, "EUR", 0},
+		{"rounds negative half away from zero", New(-1, "USD"), 0.5, "EUR", -1},
+		{"to zero exponent currency", New(1999, "USD"), 150.25, "JPY", 3003},
+		{"to three decimal currency", New(1000, "USD"), 0.30712, "KWD", 3071},
+		{"decimal rate read exactly", New(100, "USD"), 2.675, "EUR", 268},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, New(tt.want, tt.currency), tt.money.Convert(tt.rate, tt.currency))
+		})
+	}
+}
+
+func TestIn(t *testing.T) {
+	tests := []struct {
+		name     string
+		money    Money
+		currency string
+		want     int64
+		wantErr  bool
+	}{
+		{"same currency", New(1250, "USD"), "USD", 1250, false},
+		{"unknown to two decimals", New(125000, ""), "USD", 1250, false},
+		{"unknown to zero decimals", New(5000000, ""), "JPY", 500, false},
+		{"unknown to three decimals", New(12340, ""), "KWD", 1234, false},
+		{"does not fit two decimals", New(12345, ""), "USD", 0, true},
+		{"does not fit zero decimals", New(15000, ""), "JPY", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.money.In(tt.currency)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, New(tt.want, tt.currency), got)
+		})
+	}
+}
+
+func TestUnmarshalJSON(t *testing.T) {
+	m := Zero("USD")
+	require.NoError(t, m.UnmarshalJSON([]byte(`"12.50"`)))
+	assert.Equal(t, New(1250, "USD"), m)
+
+	var unknown Money
+	require.NoError(t, unknown.UnmarshalJSON([]byte(`12.5`)))
+	assert.Equal(t, New(125000, ""), unknown)
+
+	assert.Error(t, m.UnmarshalJSON([]byte(`"12.505"`)))
+}
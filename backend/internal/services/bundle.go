@@ -0,0 +1,131 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/petrejonn/naytife/internal/db"
+)
+
+var (
+	ErrBundleStock            = errors.New("the stock of a bundle is the stock of its components")
+	ErrBundleHoldsStock       = errors.New("a variant that holds stock cannot become a bundle")
+	ErrInvalidBundleComponent = errors.New("bundle components must be other variants of the shop that are not bundles")
+	ErrNestedBundle           = errors.New("a component of another bundle cannot become a bundle")
+)
+
+// BundleComponent is a quantity of a variant that a unit of a bundle is made of
+type BundleComponent struct {
+	VariantID int64
+	Quantity  int64
+}
+
+// bundleQueries are the queries that change the components of a bundle, within the
+// caller's transaction. *db.Queries implements them.
+type bundleQueries interface {
+	inventoryQueries
+	VariantIsBundleComponent(ctx context.Context, arg db.VariantIsBundleComponentParams) (bool, error)
+	GetVariantStockOnHand(ctx context.Context, arg db.GetVariantStockOnHandParams) (int64, error)
+	DeleteBundleComponents(ctx context.Context, arg db.DeleteBundleComponentsParams) error
+	CreateBundleComponent(ctx context.Context, arg db.CreateBundleComponentParams) (db.BundleComponent, error)
+}
+
+// SetBundleComponents replaces the components of a bundle variant and syncs its
+// available quantity with their stock. Without components the variant stops being
+// a bundle and holds stock of its own again.
+func SetBundleComponents(ctx context.Context, q bundleQueries, shopID, bundleID int64, components []BundleComponent) error {
+	if len(components) > 0 {
+		isComponent, err := q.VariantIsBundleComponent(ctx, db.VariantIsBundleComponentParams{
+			ComponentVariationID: bundleID,
+			ShopID:               shopID,
+		})
+		if err != nil {
+			return err
+		}
+		if isComponent {
+			return ErrNestedBundle
+		}
+		onHand, err := q.GetVariantStockOnHand(ctx, db.GetVariantStockOnHandParams{
+			ProductVariationID: bundleID,
+			ShopID:             shopID,
+		})
+		if err != nil {
+			return err
+		}
+		if onHand > 0 {
+			return ErrBundleHoldsStock
+		}
+	}
+
+	if err := q.DeleteBundleComponents(ctx, db.DeleteBundleComponentsParams{
+		BundleVariationID: bundleID,
+		ShopID:            shopID,
+	}); err != nil {
+		return err
+	}
+	for _, component := range components {
+		if component.VariantID == bundleID {
+			return ErrInvalidBundleComponent
+		}
+		if _, err := q.GetProductVariation(ctx, db.GetProductVariationParams{
+			ProductVariationID: component.VariantID,
+			ShopID:             shopID,
+		}); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrInvalidBundleComponent
+			}
+			return err
+		}
+		isBundle, err := q.VariantIsBundle(ctx, db.VariantIsBundleParams{
+			BundleVariationID: component.VariantID,
+			ShopID:            shopID,
+		})
+		if err != nil {
+			return err
+		}
+		if isBundle {
+			return ErrInvalidBundleComponent
+		}
+		if _, err := q.CreateBundleComponent(ctx, db.CreateBundleComponentParams{
+			BundleVariationID:    bundleID,
+			ComponentVariationID: component.VariantID,
+			Quantity:             component.Quantity,
+			ShopID:               shopID,
+		}); err != nil {
+			return err
+		}
+	}
+
+	if len(components) == 0 {
+		_, err := q.SyncVariantAvailableQuantity(ctx, db.SyncVariantAvailableQuantityParams{
+			ProductVariationID: bundleID,
+			ShopID:             shopID,
+		})
+		return err
+	}
+	return q.SyncBundleAvailableQuantities(ctx, db.SyncBundleAvailableQuantitiesParams{
+		ShopID:    shopID,
+		VariantID: &bundleID,
+	})
+}
+
+// stockedVariants returns the variants and quantities a unit of a variant takes
+// from stock when sold: the components of a bundle, or the variant itself
+func stockedVariants(ctx context.Context, q inventoryQueries, shopID, variantID int64) ([]BundleComponent, error) {
+	components, err := q.ListBundleComponents(ctx, db.ListBundleComponentsParams{
+		BundleVariationID: variantID,
+		ShopID:            shopID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(components) == 0 {
+		return []BundleComponent{{VariantID: variantID, Quantity: 1}}, nil
+	}
+	stocked := make([]BundleComponent, len(components))
+	for i, component := range components {
+		stocked[i] = BundleComponent{VariantID: component.ComponentVariationID, Quantity: component.Quantity}
+	}
+	return stocked, nil
+}
@@ -0,0 +1,199 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/petrejonn/naytife/internal/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeBundles builds bundles from the variants of a shop, with stock kept by
+// fakeStock
+type fakeBundles struct {
+	*fakeStock
+	variants map[int64]bool
+	onHand   map[int64]int64
+}
+
+func newFakeBundles() *fakeBundles {
+	f := &fakeBundles{
+		fakeStock: newFakeStock(warehouse, shopFloor, backRoom),
+		variants:  map[int64]bool{10: true, 11: true, 20: true, 21: true, 22: true},
+		onHand:    map[int64]int64{},
+	}
+	f.setStock(20, warehouse.LocationID, 5)
+	f.setStock(20, shopFloor.LocationID, 2)
+	f.setStock(20, backRoom.LocationID, 30)
+	f.setStock(21, warehouse.LocationID, 5)
+	return f
+}
+
+func (f *fakeBundles) GetProductVariation(ctx context.Context, arg db.GetProductVariationParams) (db.ProductVariation, error) {
+	if !f.variants[arg.ProductVariationID] {
+		return db.ProductVariation{}, pgx.ErrNoRows
+	}
+	return f.fakeStock.GetProductVariation(ctx, arg)
+}
+
+func (f *fakeBundles) VariantIsBundleComponent(ctx context.Context, arg db.VariantIsBundleComponentParams) (bool, error) {
+	for _, components := range f.bundles {
+		for _, component := range components {
+			if component.ComponentVariationID == arg.ComponentVariationID {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+func (f *fakeBundles) GetVariantStockOnHand(ctx context.Context, arg db.GetVariantStockOnHandParams) (int64, error) {
+	return f.onHand[arg.ProductVariationID], nil
+}
+
+func (f *fakeBundles) DeleteBundleComponents(ctx context.Context, arg db.DeleteBundleComponentsParams) error {
+	delete(f.bundles, arg.BundleVariationID)
+	return nil
+}
+
+func (f *fakeBundles) CreateBundleComponent(ctx context.Context, arg db.CreateBundleComponentParams) (db.BundleComponent, error) {
+	component := db.BundleComponent{
+		BundleVariationID:    arg.BundleVariationID,
+		ComponentVariationID: arg.ComponentVariationID,
+		Quantity:             arg.Quantity,
+		ShopID:               arg.ShopID,
+	}
+	f.bundles[arg.BundleVariationID] = append(f.bundles[arg.BundleVariationID], component)
+	return component, nil
+}
+
+func TestSetBundleComponents(t *testing.T) {
+	tests := []struct {
+		name          string
+		setup         func(f *fakeBundles)
+		components    []BundleComponent
+		wantErr       error
+		wantAvailable int64
+	}{
+		{
+			name:          "made up from the online stock of its components",
+			components:    []BundleComponent{{VariantID: 20, Quantity: 2}, {VariantID: 21, Quantity: 1}},
+			wantAvailable: 3,
+		},
+		{
+			name:          "component in short supply",
+			components:    []BundleComponent{{VariantID: 20, Quantity: 1}, {VariantID: 22, Quantity: 1}},
+			wantAvailable: 0,
+		},
+		{
+			name:       "bundle of itself",
+			components: []BundleComponent{{VariantID: 10, Quantity: 1}},
+			wantErr:    ErrInvalidBundleComponent,
+		},
+		{
+			name:       "variant of another shop",
+			components: []BundleComponent{{VariantID: 99, Quantity: 1}},
+			wantErr:    ErrInvalidBundleComponent,
+		},
+		{
+			name: "bundle as a component",
+			setup: func(f *fakeBundles) {
+				f.bundles[11] = []db.BundleComponent{{BundleVariationID: 11, ComponentVariationID: 21, Quantity: 1}}
+			},
+			components: []BundleComponent{{VariantID: 11, Quantity: 1}},
+			wantErr:    ErrInvalidBundleComponent,
+		},
+		{
+			name: "component of another bundle",
+			setup: func(f *fakeBundles) {
+				f.bundles[11] = []db.BundleComponent{{BundleVariationID: 11, ComponentVariationID: 10, Quantity: 1}}
+			},
+			components: []BundleComponent{{VariantID: 20, Quantity: 1}},
+			wantErr:    ErrNestedBundle,
+		},
+		{
+			name:       "variant holding stock",
+			setup:      func(f *fakeBundles) { f.onHand[10] = 3 },
+			components: []BundleComponent{{VariantID: 20, Quantity: 1}},
+			wantErr:    ErrBundleHoldsStock,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFakeBundles()
+			if tt.setup != nil {
+				tt.setup(f)
+			}
+
+			err := SetBundleComponents(context.Background(), f, 1, 10, tt.components)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, f.bundles[10], len(tt.components))
+			assert.Equal(t, tt.wantAvailable, f.bundleStock[10])
+		})
+	}
+
+	t.Run("without components the variant is no longer a bundle", func(t *testing.T) {
+		f := newFakeBundles()
+		require.NoError(t, SetBundleComponents(context.Background(), f, 1, 10, []BundleComponent{{VariantID: 20, Quantity: 1}}))
+		require.NoError(t, SetBundleComponents(context.Background(), f, 1, 10, nil))
+		assert.Empty(t, f.bundles[10])
+
+		_, err := ChangeStock(context.Background(), f, StockChange{
+			ShopID: 1, VariantID: 10, LocationID: warehouse.LocationID,
+			Operation: StockAdd, Quantity: 4, MovementType: "adjustment",
+		})
+		assert.NoError(t, err)
+	})
+}
+
+func TestBundleStock(t *testing.T) {
+	ctx := context.Background()
+	newBundle := func(t *testing.T) *fakeBundles {
+		f := newFakeBundles()
+		require.NoError(t, SetBundleComponents(ctx, f, 1, 10, []BundleComponent{{VariantID: 20, Quantity: 2}, {VariantID: 21, Quantity: 1}}))
+		require.Equal(t, int64(3), f.bundleStock[10])
+		return f
+	}
+
+	t.Run("bundles hold no stock of their own", func(t *testing.T) {
+		f := newBundle(t)
+		_, err := ChangeStock(ctx, f, StockChange{
+			ShopID: 1, VariantID: 10, LocationID: warehouse.LocationID,
+			Operation: StockAdd, Quantity: 1, MovementType: "adjustment",
+		})
+		assert.ErrorIs(t, err, ErrBundleStock)
+	})
+
+	t.Run("component stock changes resync the bundle", func(t *testing.T) {
+		f := newBundle(t)
+		_, err := ChangeStock(ctx, f, StockChange{
+			ShopID: 1, VariantID: 21, LocationID: warehouse.LocationID,
+			Operation: StockDeduct, Quantity: 4, MovementType: "adjustment",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), f.bundleStock[10])
+	})
+
+	t.Run("fulfilling a bundle takes its components", func(t *testing.T) {
+		f := newBundle(t)
+		f.items = []db.OrderItem{
+			{OrderItemID: 1, OrderID: 9, ProductVariationID: 10, Quantity: 2},
+			{OrderItemID: 2, OrderID: 9, ProductVariationID: 21, Quantity: 1},
+		}
+		locationID := warehouse.LocationID
+
+		_, err := FulfillOrder(ctx, f, db.Order{OrderID: 9, ShopID: 1}, &locationID, nil)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), f.stock(20, warehouse.LocationID).Available)
+		assert.Equal(t, int64(2), f.stock(21, warehouse.LocationID).Available)
+		assert.Equal(t, int64(1), f.bundleStock[10])
+		assert.Zero(t, f.stock(10, warehouse.LocationID).Available)
+	})
+}
@@ -0,0 +1,200 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/petrejonn/naytife/internal/db"
+	"github.com/petrejonn/naytife/internal/money"
+	"golang.org/x/text/currency"
+)
+
+// ErrCurrencyNotEnabled is returned when a presentment currency has not been enabled for the shop
+var ErrCurrencyNotEnabled = errors.New("currency is not enabled for this shop")
+
+// ExchangeRateSource provides exchange rates for currencies that have no manual rate.
+// Implementations backed by a rates API can be plugged in through NewCurrencyService.
+type ExchangeRateSource interface {
+	// Rate returns how many units of quote one unit of base buys
+	Rate(ctx context.Context, base, quote string) (float64, error)
+}
+
+// DefaultFixtureRates are indicative rates quoted against USD, used when no
+// rates file is configured
+var DefaultFixtureRates = map[string]float64{
+	"USD": 1,
+	"NGN": 1550,
+	"GHS": 15.5,
+	"KES": 129,
+	"ZAR": 18.3,
+	"XOF": 605,
+	"EUR": 0.92,
+	"GBP": 0.79,
+}
+
+// FixtureRateSource serves static rates quoted against USD and derives cross rates from them
+type FixtureRateSource struct {
+	rates map[string]float64
+}
+
+func NewFixtureRateSource(rates map[string]float64) *FixtureRateSource {
+	return &FixtureRateSource{rates: rates}
+}
+
+func (f *FixtureRateSource) Rate(ctx context.Context, base, quote string) (float64, error) {
+	baseRate, ok := f.rates[base]
+	if !ok || baseRate <= 0 {
+		return 0, fmt.Errorf("no exchange rate for %s", base)
+	}
+	quoteRate, ok := f.rates[quote]
+	if !ok || quoteRate <= 0 {
+		return 0, fmt.Errorf("no exchange rate for %s", quote)
+	}
+	return quoteRate / baseRate, nil
+}
+
+// ExchangeRateSourceFromEnv returns the fixture rate source, loading its USD
+// rates from the JSON file in EXCHANGE_RATES_FILE when set
+func ExchangeRateSourceFromEnv() (ExchangeRateSource, error) {
+	path := os.Getenv("EXCHANGE_RATES_FILE")
+	if path == "" {
+		return NewFixtureRateSource(DefaultFixtureRates), nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read exchange rates file: %w", err)
+	}
+	rates := map[string]float64{}
+	if err := json.Unmarshal(data, &rates); err != nil {
+		return nil, fmt.Errorf("failed to parse exchange rates file: %w", err)
+	}
+	return NewFixtureRateSource(rates), nil
+}
+
+// NormalizeCurrencyCode validates an ISO 4217 currency code and returns it in upper case
+func NormalizeCurrencyCode(code string) (string, error) {
+	unit, err := currency.ParseISO(strings.TrimSpace(code))
+	if err != nil {
+		return "", fmt.Errorf("invalid currency code %q", code)
+	}
+	return unit.String(), nil
+}
+
+// Presentment describes how amounts in the shop currency are shown and charged in CurrencyCode
+type Presentment struct {
+	ShopCurrency string
+	CurrencyCode string
+	Rate         float64
+}
+
+// IsShopCurrency reports whether no conversion takes place
+func (p Presentment) IsShopCurrency() bool {
+	return p.CurrencyCode == p.ShopCurrency
+}
+
+// Convert converts an amount in the shop currency, rounded to the minor unit of the
+// presentment currency
+func (p Presentment) Convert(amount money.Money) money.Money {
+	if p.IsShopCurrency() {
+		return amount
+	}
+	return amount.Convert(p.Rate, p.CurrencyCode)
+}
+
+type CurrencyService struct {
+	repository db.Repository
+	RateSource ExchangeRateSource
+}
+
+func NewCurrencyService(repo db.Repository, rateSource ExchangeRateSource) *CurrencyService {
+	return &CurrencyService{
+		repository: repo,
+		RateSource: rateSource,
+	}
+}
+
+// Presentment resolves the exchange rate from the shop currency to currencyCode.
+// An empty currency code or the shop's own currency gives a rate of 1; any other
+// currency must be enabled for the shop and uses its manual rate when one is set.
+func (s *CurrencyService) Presentment(ctx context.Context, shopID int64, currencyCode string) (Presentment, error) {
+	shop, err := s.repository.GetShop(ctx, shopID)
+	if err != nil {
+		return Presentment{}, err
+	}
+	p := Presentment{ShopCurrency: shop.CurrencyCode, CurrencyCode: shop.CurrencyCode, Rate: 1}
+	if currencyCode == "" {
+		return p, nil
+	}
+	code, err := NormalizeCurrencyCode(currencyCode)
+	if err != nil {
+		return Presentment{}, err
+	}
+	if code == shop.CurrencyCode {
+		return p, nil
+	}
+
+	enabled, err := s.repository.GetShopCurrency(ctx, db.GetShopCurrencyParams{ShopID: shopID, CurrencyCode: code})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return Presentment{}, ErrCurrencyNotEnabled
+		}
+		return Presentment{}, err
+	}
+	p.CurrencyCode = code
+	if enabled.ExchangeRate.Valid {
+		rate, err := enabled.ExchangeRate.Float64Value()
+		if err != nil {
+			return Presentment{}, err
+		}
+		p.Rate = rate.Float64
+		return p, nil
+	}
+	if s.RateSource == nil {
+		return Presentment{}, fmt.Errorf("no exchange rate source configured for %s", code)
+	}
+	p.Rate, err = s.RateSource.Rate(ctx, shop.CurrencyCode, code)
+	if err != nil {
+		return Presentment{}, err
+	}
+	return p, nil
+}
+
+// VariantPrices returns the fixed prices of the given variants in the presentment
+// currency, keyed by variant ID. Variants without a fixed price are left out.
+func (s *CurrencyService) VariantPrices(ctx context.Context, shopID int64, p Presentment, variantIDs []int64) (map[int64]money.Money, error) {
+	prices := map[int64]money.Money{}
+	if p.IsShopCurrency() || len(variantIDs) == 0 {
+		return prices, nil
+	}
+	rows, err := s.repository.GetVariationPricesForCurrency(ctx, db.GetVariationPricesForCurrencyParams{
+		ShopID:              shopID,
+		CurrencyCode:        p.CurrencyCode,
+		ProductVariationIds: variantIDs,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		price, err := money.FromNumeric(row.Price, p.CurrencyCode)
+		if err != nil {
+			return nil, err
+		}
+		prices[row.ProductVariationID] = price
+	}
+	return prices, nil
+}
+
+// FloatToNumeric converts a float such as an exchange rate to a pgtype.Numeric without
+// losing its decimal places. Amounts of money use money.Money.Numeric instead.
+func FloatToNumeric(f float64) pgtype.Numeric {
+	var n pgtype.Numeric
+	_ = n.Scan(strconv.FormatFloat(f, 'f', -1, 64))
+	return n
+}
@@ -0,0 +1,127 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/petrejonn/naytife/internal/db"
+	"github.com/petrejonn/naytife/internal/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// currencyRepository serves the shop and its enabled currencies for Presentment
+type currencyRepository struct {
+	db.Repository
+	shopCurrency string
+	enabled      map[string]db.ShopCurrency
+}
+
+func (r *currencyRepository) GetShop(ctx context.Context, shopID int64) (db.Shop, error) {
+	return db.Shop{ShopID: shopID, CurrencyCode: r.shopCurrency}, nil
+}
+
+func (r *currencyRepository) GetShopCurrency(ctx context.Context, arg db.GetShopCurrencyParams) (db.ShopCurrency, error) {
+	enabled, ok := r.enabled[arg.CurrencyCode]
+	if !ok {
+		return db.ShopCurrency{}, pgx.ErrNoRows
+	}
+	return enabled, nil
+}
+
+func TestPresentment(t *testing.T) {
+	repo := &currencyRepository{
+		shopCurrency: "NGN",
+		enabled: map[string]db.ShopCurrency{
+			"USD": {CurrencyCode: "USD", ExchangeRate: FloatToNumeric(0.0007)},
+			"GHS": {CurrencyCode: "GHS"},
+			"JPY": {CurrencyCode: "JPY"},
+		},
+	}
+	service := NewCurrencyService(repo, NewFixtureRateSource(map[string]float64{"USD": 1, "NGN": 1600, "GHS": 16}))
+
+	tests := []struct {
+		name     string
+		currency string
+		want     Presentment
+		wantErr  error
+	}{
+		{"no currency", "", Presentment{ShopCurrency: "NGN", CurrencyCode: "NGN", Rate: 1}, nil},
+		{"shop currency", "ngn", Presentment{ShopCurrency: "NGN", CurrencyCode: "NGN", Rate: 1}, nil},
+		{"manual rate", "usd", Presentment{ShopCurrency: "NGN", CurrencyCode: "USD", Rate: 0.0007}, nil},
+		{"rate from source", "GHS", Presentment{ShopCurrency: "NGN", CurrencyCode: "GHS", Rate: 0.01}, nil},
+		{"not enabled", "EUR", Presentment{}, ErrCurrencyNotEnabled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := service.Presentment(context.Background(), 1, tt.currency)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want.ShopCurrency, got.ShopCurrency)
+			assert.Equal(t, tt.want.CurrencyCode, got.CurrencyCode)
+			assert.InDelta(t, tt.want.Rate, got.Rate, 1e-12)
+		})
+	}
+
+	// Enabled without a manual rate and missing from the rate source
+	_, err := service.Presentment(context.Background(), 1, "JPY")
+	assert.Error(t, err)
+	// Not a currency at all
+	_, err = service.Presentment(context.Background(), 1, "XYZ1")
+	assert.Error(t, err)
+}
+
+func TestFixtureRateSource(t *testing.T) {
+	source := NewFixtureRateSource(map[string]float64{"USD": 1, "NGN": 1550, "EUR": 0.92, "ZZZ": 0})
+
+	rate, err := source.Rate(context.Background(), "USD", "NGN")
+	require.NoError(t, err)
+	assert.Equal(t, 1550.0, rate)
+
+	// Cross rates go through USD
+	rate, err = source.Rate(context.Background(), "EUR", "NGN")
+	require.NoError(t, err)
+	assert.InDelta(t, 1550/0.92, rate, 1e-9)
+
+	_, err = source.Rate(context.Background(), "USD", "GBP")
+	assert.Error(t, err)
+	_, err = source.Rate(context.Background(), "ZZZ", "USD")
+	assert.Error(t, err)
+}
+
+func TestPresentmentConvert(t *testing.T) {
+	tests := []struct {
+		name   string
+		p      Presentment
+		amount money.Money
+		want   money.Money
+	}{
+		{"shop currency is untouched", Presentment{ShopCurrency: "NGN", CurrencyCode: "NGN", Rate: 1}, money.New(123457, "NGN"), money.New(123457, "NGN")},
+		{"rounds to cents", Presentment{ShopCurrency: "NGN", CurrencyCode: "USD", Rate: 0.00065}, money.New(1250000, "NGN"), money.New(813, "USD")},
+		{"rounds half away from zero", Presentment{ShopCurrency: "USD", CurrencyCode: "EUR", Rate: 0.5}, money.New(25, "USD"), money.New(13, "EUR")},
+		{"to a currency without minor unit", Presentment{ShopCurrency: "USD", CurrencyCode: "JPY", Rate: 150.25}, money.New(1999, "USD"), money.New(3003, "JPY")},
+		{"zero", Presentment{ShopCurrency: "USD", CurrencyCode: "EUR", Rate: 0.92}, money.Zero("USD"), money.Zero("EUR")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.p.Convert(tt.amount))
+		})
+	}
+}
+
+func TestNormalizeCurrencyCode(t *testing.T) {
+	code, err := NormalizeCurrencyCode(" ngn ")
+	require.NoError(t, err)
+	assert.Equal(t, "NGN", code)
+
+	for _, invalid := range []string{"", "NG", "NAIRA", "XYZ"} {
+		_, err := NormalizeCurrencyCode(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
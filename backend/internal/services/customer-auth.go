@@ -0,0 +1,149 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	retryablehttp "github.com/hashicorp/go-retryablehttp"
+)
+
+// ErrInvalidCustomerToken is returned for tokens and sessions that are expired,
+// malformed or were not issued to a storefront
+var ErrInvalidCustomerToken = errors.New("invalid customer token")
+
+// CustomerIdentity is a storefront customer proven by a token or session
+type CustomerIdentity struct {
+	ShopID    int64     `json:"shop_id"`
+	Email     string    `json:"email"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// CustomerAuth verifies storefront customers. Access tokens are introspected at
+// Hydra and must carry the app_type and shop_id claims the auth handler adds for
+// storefront logins. Sessions are identities signed by the backend once a token
+// was verified, so later requests do not need another introspection.
+type CustomerAuth struct {
+	HydraAdminURL string
+	SessionSecret []byte
+	HTTPClient    *retryablehttp.Client
+}
+
+// CustomerAuthFromEnv reads HYDRA_ADMIN_URL and CUSTOMER_SESSION_SECRET. The secret
+// is required: sessions signed with a key of one instance must verify on the others
+// and survive restarts.
+func CustomerAuthFromEnv(client *retryablehttp.Client) (*CustomerAuth, error) {
+	adminURL := os.Getenv("HYDRA_ADMIN_URL")
+	if adminURL == "" {
+		adminURL = "http://hydra-admin.naytife-auth.svc.cluster.local:4445"
+	}
+
+	secret := []byte(os.Getenv("CUSTOMER_SESSION_SECRET"))
+	if len(secret) == 0 {
+		return nil, errors.New("CUSTOMER_SESSION_SECRET is not set")
+	}
+
+	return &CustomerAuth{
+		HydraAdminURL: strings.TrimRight(adminURL, "/"),
+		SessionSecret: secret,
+		HTTPClient:    client,
+	}, nil
+}
+
+// VerifyAccessToken introspects a Hydra access token and returns the storefront
+// customer it was issued to
+func (a *CustomerAuth) VerifyAccessToken(ctx context.Context, token string) (CustomerIdentity, error) {
+	form := url.Values{"token": {token}}
+	req, err := retryablehttp.NewRequest(http.MethodPost, a.HydraAdminURL+"/admin/oauth2/introspect", strings.NewReader(form.Encode()))
+	if err != nil {
+		return CustomerIdentity{}, fmt.Errorf("create introspection request: %w", err)
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := a.HTTPClient.Do(req)
+	if err != nil {
+		return CustomerIdentity{}, fmt.Errorf("introspect token: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return CustomerIdentity{}, fmt.Errorf("introspect token: unexpected status %d", resp.StatusCode)
+	}
+
+	var introspection struct {
+		Active bool   `json:"active"`
+		Sub    string `json:"sub"`
+		Exp    int64  `json:"exp"`
+		Ext    struct {
+			AppType string `json:"app_type"`
+			ShopID  string `json:"shop_id"`
+		} `json:"ext"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&introspection); err != nil {
+		return CustomerIdentity{}, fmt.Errorf("decode introspection response: %w", err)
+	}
+	if !introspection.Active || introspection.Ext.AppType != "storefront" || introspection.Sub == "" {
+		return CustomerIdentity{}, ErrInvalidCustomerToken
+	}
+	shopID, err := strconv.ParseInt(introspection.Ext.ShopID, 10, 64)
+	if err != nil {
+		return CustomerIdentity{}, ErrInvalidCustomerToken
+	}
+
+	return CustomerIdentity{
+		ShopID:    shopID,
+		Email:     introspection.Sub,
+		ExpiresAt: time.Unix(introspection.Exp, 0),
+	}, nil
+}
+
+// SignSession encodes an identity as "<payload>.<signature>", both base64url encoded
+func (a *CustomerAuth) SignSession(identity CustomerIdentity) (string, error) {
+	payload, err := json.Marshal(identity)
+	if err != nil {
+		return "", fmt.Errorf("encode session: %w", err)
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(a.sign(encoded)), nil
+}
+
+// VerifySession checks the signature and expiry of a session from SignSession
+func (a *CustomerAuth) VerifySession(session string) (CustomerIdentity, error) {
+	encoded, signature, ok := strings.Cut(session, ".")
+	if !ok {
+		return CustomerIdentity{}, ErrInvalidCustomerToken
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(mac, a.sign(encoded)) {
+		return CustomerIdentity{}, ErrInvalidCustomerToken
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return CustomerIdentity{}, ErrInvalidCustomerToken
+	}
+
+	var identity CustomerIdentity
+	if err := json.Unmarshal(payload, &identity); err != nil {
+		return CustomerIdentity{}, ErrInvalidCustomerToken
+	}
+	if !time.Now().Before(identity.ExpiresAt) {
+		return CustomerIdentity{}, ErrInvalidCustomerToken
+	}
+	return identity, nil
+}
+
+func (a *CustomerAuth) sign(encoded string) []byte {
+	mac := hmac.New(sha256.New, a.SessionSecret)
+	mac.Write([]byte(encoded))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,151 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	retryablehttp "github.com/hashicorp/go-retryablehttp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyAccessToken(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		response string
+		want     CustomerIdentity
+		wantErr  error
+	}{
+		{
+			name:     "storefront token",
+			status:   http.StatusOK,
+			response: `{"active":true,"sub":"ada@example.com","exp":1900000000,"ext":{"app_type":"storefront","shop_id":"7"}}`,
+			want:     CustomerIdentity{ShopID: 7, Email: "ada@example.com", ExpiresAt: time.Unix(1900000000, 0)},
+		},
+		{
+			name:     "inactive token",
+			status:   http.StatusOK,
+			response: `{"active":false}`,
+			wantErr:  ErrInvalidCustomerToken,
+		},
+		{
+			name:     "dashboard token",
+			status:   http.StatusOK,
+			response: `{"active":true,"sub":"ada@example.com","exp":1900000000,"ext":{"app_type":"dashboard"}}`,
+			wantErr:  ErrInvalidCustomerToken,
+		},
+		{
+			name:     "storefront token without shop",
+			status:   http.StatusOK,
+			response: `{"active":true,"sub":"ada@example.com","exp":1900000000,"ext":{"app_type":"storefront"}}`,
+			wantErr:  ErrInvalidCustomerToken,
+		},
+		{
+			name:     "storefront token without subject",
+			status:   http.StatusOK,
+			response: `{"active":true,"exp":1900000000,"ext":{"app_type":"storefront","shop_id":"7"}}`,
+			wantErr:  ErrInvalidCustomerToken,
+		},
+		{
+			name:   "hydra unavailable",
+			status: http.StatusBadGateway,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/admin/oauth2/introspect", r.URL.Path)
+				require.NoError(t, r.ParseForm())
+				assert.Equal(t, "token-123", r.PostForm.Get("token"))
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.response))
+			}))
+			defer server.Close()
+
+			client := retryablehttp.NewClient()
+			client.RetryMax = 0
+			client.Logger = nil
+			auth := &CustomerAuth{HydraAdminURL: server.URL, HTTPClient: client}
+
+			got, err := auth.VerifyAccessToken(context.Background(), "token-123")
+			switch {
+			case tt.wantErr != nil:
+				assert.ErrorIs(t, err, tt.wantErr)
+			case tt.status != http.StatusOK:
+				require.Error(t, err)
+				assert.NotErrorIs(t, err, ErrInvalidCustomerToken)
+			default:
+				require.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestCustomerSession(t *testing.T) {
+	auth := &CustomerAuth{SessionSecret: []byte("test-secret")}
+	identity := CustomerIdentity{ShopID: 7, Email: "ada@example.com", ExpiresAt: time.Now().Add(time.Hour).Truncate(time.Second)}
+
+	session, err := auth.SignSession(identity)
+	require.NoError(t, err)
+
+	t.Run("round trip", func(t *testing.T) {
+		got, err := auth.VerifySession(session)
+		require.NoError(t, err)
+		assert.Equal(t, identity.ShopID, got.ShopID)
+		assert.Equal(t, identity.Email, got.Email)
+		assert.True(t, identity.ExpiresAt.Equal(got.ExpiresAt))
+	})
+
+	expired, err := auth.SignSession(CustomerIdentity{ShopID: 7, Email: "ada@example.com", ExpiresAt: time.Now().Add(-time.Minute)})
+	require.NoError(t, err)
+	encoded, signature, _ := strings.Cut(session, ".")
+	otherShop, err := auth.SignSession(CustomerIdentity{ShopID: 8, Email: "ada@example.com", ExpiresAt: identity.ExpiresAt})
+	require.NoError(t, err)
+	otherEncoded, _, _ := strings.Cut(otherShop, ".")
+
+	tests := []struct {
+		name    string
+		session string
+		auth    *CustomerAuth
+	}{
+		{"expired", expired, auth},
+		{"signed with another secret", session, &CustomerAuth{SessionSecret: []byte("other-secret")}},
+		{"payload swapped", otherEncoded + "." + signature, auth},
+		{"signature missing", encoded, auth},
+		{"signature not base64", encoded + ".***", auth},
+		{"empty", "", auth},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.auth.VerifySession(tt.session)
+			assert.ErrorIs(t, err, ErrInvalidCustomerToken)
+		})
+	}
+}
+
+func TestCustomerAuthFromEnv(t *testing.T) {
+	t.Run("session secret", func(t *testing.T) {
+		t.Setenv("HYDRA_ADMIN_URL", "http://hydra:4445/")
+		t.Setenv("CUSTOMER_SESSION_SECRET", "test-secret")
+
+		auth, err := CustomerAuthFromEnv(retryablehttp.NewClient())
+		require.NoError(t, err)
+		assert.Equal(t, "http://hydra:4445", auth.HydraAdminURL)
+		assert.Equal(t, []byte("test-secret"), auth.SessionSecret)
+	})
+
+	t.Run("no session secret", func(t *testing.T) {
+		t.Setenv("CUSTOMER_SESSION_SECRET", "")
+
+		_, err := CustomerAuthFromEnv(retryablehttp.NewClient())
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,35 @@
+package services
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/petrejonn/naytife/internal/db"
+	"github.com/petrejonn/naytife/internal/money"
+)
+
+// CustomerPrices returns the customer group prices of the given variants in the
+// shop currency, keyed by variant ID. Variants without a group price for the
+// customer are left out.
+func CustomerPrices(ctx context.Context, repo db.Repository, shopID int64, currency string, customerID uuid.UUID, variantIDs []int64) (map[int64]money.Money, error) {
+	prices := map[int64]money.Money{}
+	if len(variantIDs) == 0 {
+		return prices, nil
+	}
+	rows, err := repo.GetCustomerVariantPrices(ctx, db.GetCustomerVariantPricesParams{
+		ProductVariationIds: variantIDs,
+		ShopCustomerID:      customerID,
+		ShopID:              shopID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		price, err := money.FromNumeric(row.Price, currency)
+		if err != nil {
+			return nil, err
+		}
+		prices[row.ProductVariationID] = price
+	}
+	return prices, nil
+}
@@ -0,0 +1,50 @@
+package services
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/petrejonn/naytife/internal/db"
+	"github.com/petrejonn/naytife/internal/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// customerPriceRepository returns fixed group prices and counts the lookups made
+type customerPriceRepository struct {
+	db.Repository
+	rows  []db.GetCustomerVariantPricesRow
+	calls int
+}
+
+func (r *customerPriceRepository) GetCustomerVariantPrices(ctx context.Context, arg db.GetCustomerVariantPricesParams) ([]db.GetCustomerVariantPricesRow, error) {
+	r.calls++
+	return r.rows, nil
+}
+
+func TestCustomerPrices(t *testing.T) {
+	repo := &customerPriceRepository{rows: []db.GetCustomerVariantPricesRow{
+		// Price list entry
+		{ProductVariationID: 1, Price: pgtype.Numeric{Int: big.NewInt(150000), Exp: -4, Valid: true}},
+		// 10% group discount on 19.99, which the query leaves unrounded
+		{ProductVariationID: 2, Price: pgtype.Numeric{Int: big.NewInt(17991), Exp: -3, Valid: true}},
+	}}
+
+	prices, err := CustomerPrices(context.Background(), repo, 1, "USD", uuid.New(), []int64{1, 2, 3})
+	require.NoError(t, err)
+	assert.Equal(t, map[int64]money.Money{
+		1: money.New(1500, "USD"),
+		2: money.New(1799, "USD"),
+	}, prices)
+
+	t.Run("no variants skips the lookup", func(t *testing.T) {
+		repo := &customerPriceRepository{}
+		prices, err := CustomerPrices(context.Background(), repo, 1, "USD", uuid.New(), nil)
+		require.NoError(t, err)
+		assert.Empty(t, prices)
+		assert.Zero(t, repo.calls)
+	})
+}
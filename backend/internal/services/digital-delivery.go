@@ -0,0 +1,303 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/petrejonn/naytife/internal/db"
+)
+
+var (
+	ErrInvalidDownloadLink = errors.New("invalid or expired download link")
+	ErrDownloadUnavailable = errors.New("download expired or reached its limit")
+	ErrDigitalFileTooLarge = errors.New("digital file too large")
+)
+
+// DigitalDelivery holds what delivering digital goods needs: the private storage of
+// their files and the key their download links are signed with
+type DigitalDelivery struct {
+	Storage  DigitalAssetStorage
+	MaxBytes int64
+	// BaseURL is the public URL of the API the download links point to
+	BaseURL string
+	Secret  []byte
+	// LinkTTL is how long a download link stays valid. Links are signed again every
+	// time the customer views their order, so they can be short-lived.
+	LinkTTL time.Duration
+}
+
+// DigitalDeliveryFromEnv reads DIGITAL_MAX_UPLOAD_BYTES, API_URL, DOWNLOAD_SIGNING_SECRET
+// and DOWNLOAD_LINK_TTL. The secret is required: links signed by one instance must
+// verify on the others and survive restarts.
+func DigitalDeliveryFromEnv(storage DigitalAssetStorage) (*DigitalDelivery, error) {
+	delivery := &DigitalDelivery{
+		Storage:  storage,
+		MaxBytes: 100 << 20,
+		BaseURL:  strings.TrimRight(os.Getenv("API_URL"), "/"),
+		Secret:   []byte(os.Getenv("DOWNLOAD_SIGNING_SECRET")),
+		LinkTTL:  24 * time.Hour,
+	}
+	if v, err := strconv.ParseInt(os.Getenv("DIGITAL_MAX_UPLOAD_BYTES"), 10, 64); err == nil && v > 0 {
+		delivery.MaxBytes = v
+	}
+	if v, err := time.ParseDuration(os.Getenv("DOWNLOAD_LINK_TTL")); err == nil && v > 0 {
+		delivery.LinkTTL = v
+	}
+	if len(delivery.Secret) == 0 {
+		return nil, errors.New("DOWNLOAD_SIGNING_SECRET is not set")
+	}
+	return delivery, nil
+}
+
+// DownloadURL returns a signed link to a file download. The link expires after the
+// link TTL, or with the download when that is sooner.
+func (d *DigitalDelivery) DownloadURL(shopID, downloadID int64, expiresAt pgtype.Timestamptz) string {
+	expires := time.Now().Add(d.LinkTTL)
+	if expiresAt.Valid && expiresAt.Time.Before(expires) {
+		expires = expiresAt.Time
+	}
+	return fmt.Sprintf("%s/v1/downloads/%d/%d?expires=%d&signature=%s",
+		d.BaseURL, shopID, downloadID, expires.Unix(), d.sign(shopID, downloadID, expires.Unix()))
+}
+
+// VerifyDownloadLink checks the signature and expiry of a link from DownloadURL
+func (d *DigitalDelivery) VerifyDownloadLink(shopID, downloadID, expires int64, signature string) error {
+	mac, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(mac, d.mac(shopID, downloadID, expires)) {
+		return ErrInvalidDownloadLink
+	}
+	if !time.Now().Before(time.Unix(expires, 0)) {
+		return ErrInvalidDownloadLink
+	}
+	return nil
+}
+
+func (d *DigitalDelivery) sign(shopID, downloadID, expires int64) string {
+	return base64.RawURLEncoding.EncodeToString(d.mac(shopID, downloadID, expires))
+}
+
+func (d *DigitalDelivery) mac(shopID, downloadID, expires int64) []byte {
+	mac := hmac.New(sha256.New, d.Secret)
+	fmt.Fprintf(mac, "%d:%d:%d", shopID, downloadID, expires)
+	return mac.Sum(nil)
+}
+
+// UploadBodyLimit is the largest request body a file upload is accepted with,
+// leaving room for multipart overhead on top of the largest accepted file
+func (d *DigitalDelivery) UploadBodyLimit() int64 {
+	return d.MaxBytes + 1<<20
+}
+
+// StoreFile streams the size bytes of the file of a digital asset of a variant to
+// storage under a random key and returns the key
+func (d *DigitalDelivery) StoreFile(ctx context.Context, shopID, variantID int64, fileName, contentType string, body io.Reader, size int64) (string, error) {
+	if size > d.MaxBytes {
+		return "", ErrDigitalFileTooLarge
+	}
+	name := strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r < ' ' {
+			return '_'
+		}
+		return r
+	}, fileName)
+	key := fmt.Sprintf("shops/%d/digital/%d/%s/%s", shopID, variantID, uuid.NewString(), name)
+	if err := d.Storage.PutReader(ctx, key, body, size, contentType); err != nil {
+		return "", err
+	}
+	return key, nil
+}
+
+// DownloadAvailable reports whether a download can still be fetched
+func DownloadAvailable(count int32, limit *int32, expiresAt pgtype.Timestamptz) bool {
+	if limit != nil && count >= *limit {
+		return false
+	}
+	return !expiresAt.Valid || time.Now().Before(expiresAt.Time)
+}
+
+// OrderDownloadURL returns the signed link to a download of an order, or nil for
+// license keys and downloads that expired or reached their limit
+func (d *DigitalDelivery) OrderDownloadURL(download db.ListOrderDigitalDownloadsRow) *string {
+	if download.Kind != db.DigitalAssetKindFile || !DownloadAvailable(download.DownloadCount, download.DownloadLimit, download.ExpiresAt) {
+		return nil
+	}
+	url := d.DownloadURL(download.ShopID, download.DownloadID, download.ExpiresAt)
+	return &url
+}
+
+// DeliverOrderDigitalGoods creates a download for every file and allocates a license
+// key for every unit of the digital lines of a paid order, and queues the email
+// with them to the order's customer. License keys wait for the pool to be topped up
+// when it runs out. Orders that were already delivered are left alone, so it is
+// safe to call on every payment confirmation, even concurrently.
+func DeliverOrderDigitalGoods(ctx context.Context, q *db.Queries, delivery *DigitalDelivery, order db.Order) ([]db.DigitalDownload, error) {
+	if _, err := q.LockOrderDigitalDelivery(ctx, db.LockOrderDigitalDeliveryParams{OrderID: order.OrderID, ShopID: order.ShopID}); err != nil {
+		return nil, err
+	}
+	delivered, err := q.CountOrderDigitalDownloads(ctx, db.CountOrderDigitalDownloadsParams{OrderID: order.OrderID, ShopID: order.ShopID})
+	if err != nil || delivered > 0 {
+		return nil, err
+	}
+	assets, err := q.ListOrderDigitalAssets(ctx, db.ListOrderDigitalAssetsParams{OrderID: order.OrderID, ShopID: order.ShopID})
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var downloads []db.DigitalDownload
+	for _, asset := range assets {
+		var expiresAt pgtype.Timestamptz
+		if asset.ExpiresAfterDays != nil {
+			expiresAt = pgtype.Timestamptz{Time: now.AddDate(0, 0, int(*asset.ExpiresAfterDays)), Valid: true}
+		}
+		// A file is downloaded once per line, but every unit gets its own key
+		units := int64(1)
+		if asset.Kind == db.DigitalAssetKindLicenseKey {
+			units = asset.Quantity
+		}
+		for i := int64(0); i < units; i++ {
+			var licenseKeyID *int64
+			if asset.Kind == db.DigitalAssetKindLicenseKey {
+				key, err := q.AllocateLicenseKey(ctx, db.AllocateLicenseKeyParams{DigitalAssetID: asset.DigitalAssetID, ShopID: order.ShopID})
+				if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+					return nil, fmt.Errorf("failed to allocate license key: %w", err)
+				}
+				if err == nil {
+					licenseKeyID = &key.LicenseKeyID
+				}
+			}
+			download, err := q.CreateDigitalDownload(ctx, db.CreateDigitalDownloadParams{
+				OrderID:        order.OrderID,
+				OrderItemID:    asset.OrderItemID,
+				DigitalAssetID: asset.DigitalAssetID,
+				LicenseKeyID:   licenseKeyID,
+				DownloadLimit:  asset.DownloadLimit,
+				ExpiresAt:      expiresAt,
+				ShopID:         order.ShopID,
+			})
+			if err != nil {
+				return nil, err
+			}
+			downloads = append(downloads, download)
+		}
+	}
+	if len(downloads) == 0 {
+		return nil, nil
+	}
+	if err := queueDigitalDeliveryNotification(ctx, q, delivery, order); err != nil {
+		return nil, err
+	}
+	return downloads, nil
+}
+
+// AllocatePendingLicenseKeys gives the keys of an asset's pool to the downloads that
+// have been waiting for one, oldest first, and emails their orders again. It returns
+// the number of keys allocated.
+func AllocatePendingLicenseKeys(ctx context.Context, q *db.Queries, delivery *DigitalDelivery, shopID, assetID int64) (int64, error) {
+	pending, err := q.ListPendingLicenseDownloads(ctx, db.ListPendingLicenseDownloadsParams{DigitalAssetID: assetID, ShopID: shopID})
+	if err != nil {
+		return 0, err
+	}
+	var allocated int64
+	var orderIDs []int64
+	seen := map[int64]bool{}
+	for _, download := range pending {
+		key, err := q.AllocateLicenseKey(ctx, db.AllocateLicenseKeyParams{DigitalAssetID: assetID, ShopID: shopID})
+		if errors.Is(err, pgx.ErrNoRows) {
+			break
+		}
+		if err != nil {
+			return allocated, fmt.Errorf("failed to allocate license key: %w", err)
+		}
+		if _, err := q.SetDownloadLicenseKey(ctx, db.SetDownloadLicenseKeyParams{
+			DownloadID:   download.DownloadID,
+			ShopID:       shopID,
+			LicenseKeyID: &key.LicenseKeyID,
+		}); err != nil {
+			return allocated, err
+		}
+		allocated++
+		if !seen[download.OrderID] {
+			seen[download.OrderID] = true
+			orderIDs = append(orderIDs, download.OrderID)
+		}
+	}
+
+	for _, orderID := range orderIDs {
+		order, err := q.GetOrder(ctx, db.GetOrderParams{OrderID: orderID, ShopID: shopID})
+		if err != nil {
+			return allocated, err
+		}
+		if err := queueDigitalDeliveryNotification(ctx, q, delivery, order); err != nil {
+			return allocated, err
+		}
+	}
+	return allocated, nil
+}
+
+// digitalDeliveryItem is a download as listed in the delivery email
+type digitalDeliveryItem struct {
+	DownloadID    int64      `json:"download_id"`
+	Title         string     `json:"title"`
+	Kind          string     `json:"kind"`
+	FileName      *string    `json:"file_name,omitempty"`
+	URL           *string    `json:"url,omitempty"`
+	LicenseKey    *string    `json:"license_key,omitempty"`
+	DownloadLimit *int32     `json:"download_limit,omitempty"`
+	ExpiresAt     *time.Time `json:"expires_at,omitempty"`
+}
+
+// queueDigitalDeliveryNotification queues the email listing every download of an
+// order, if the order has an email address
+func queueDigitalDeliveryNotification(ctx context.Context, q *db.Queries, delivery *DigitalDelivery, order db.Order) error {
+	if order.CustomerEmail == nil || *order.CustomerEmail == "" {
+		return nil
+	}
+	downloads, err := q.ListOrderDigitalDownloads(ctx, db.ListOrderDigitalDownloadsParams{OrderID: order.OrderID, ShopID: order.ShopID})
+	if err != nil {
+		return err
+	}
+	items := make([]digitalDeliveryItem, len(downloads))
+	for i, download := range downloads {
+		items[i] = digitalDeliveryItem{
+			DownloadID:    download.DownloadID,
+			Title:         download.Title,
+			Kind:          string(download.Kind),
+			FileName:      download.FileName,
+			URL:           delivery.OrderDownloadURL(download),
+			LicenseKey:    download.LicenseKey,
+			DownloadLimit: download.DownloadLimit,
+		}
+		if download.ExpiresAt.Valid {
+			items[i].ExpiresAt = &download.ExpiresAt.Time
+		}
+	}
+	payload, err := json.Marshal(map[string]any{
+		"order_id":  order.OrderID,
+		"downloads": items,
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := q.QueueDigitalDeliveryNotification(ctx, db.QueueDigitalDeliveryNotificationParams{
+		Recipient: *order.CustomerEmail,
+		Payload:   payload,
+		ShopID:    order.ShopID,
+	}); err != nil {
+		return fmt.Errorf("failed to queue digital delivery notification: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyDownloadLink(t *testing.T) {
+	delivery := &DigitalDelivery{BaseURL: "https://api.example.com", Secret: []byte("secret"), LinkTTL: time.Hour}
+	future := time.Now().Add(time.Hour).Unix()
+	past := time.Now().Add(-time.Minute).Unix()
+
+	tests := []struct {
+		name       string
+		shopID     int64
+		downloadID int64
+		expires    int64
+		signature  string
+		wantErr    bool
+	}{
+		{"valid", 1, 2, future, delivery.sign(1, 2, future), false},
+		{"expired", 1, 2, past, delivery.sign(1, 2, past), true},
+		{"other download", 1, 3, future, delivery.sign(1, 2, future), true},
+		{"other shop", 9, 2, future, delivery.sign(1, 2, future), true},
+		{"extended expiry", 1, 2, future + 3600, delivery.sign(1, 2, future), true},
+		{"signed with another secret", 1, 2, future, (&DigitalDelivery{Secret: []byte("other")}).sign(1, 2, future), true},
+		{"malformed signature", 1, 2, future, "not base64!", true},
+		{"empty signature", 1, 2, future, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := delivery.VerifyDownloadLink(tt.shopID, tt.downloadID, tt.expires, tt.signature)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidDownloadLink)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestDownloadURLVerifies(t *testing.T) {
+	delivery := &DigitalDelivery{BaseURL: "https://api.example.com", Secret: []byte("secret"), LinkTTL: time.Hour}
+	soon := time.Now().Add(10 * time.Minute)
+
+	link, err := url.Parse(delivery.DownloadURL(4, 5, pgtype.Timestamptz{Time: soon, Valid: true}))
+	require.NoError(t, err)
+	assert.True(t, strings.HasSuffix(link.Path, "/v1/downloads/4/5"))
+
+	expires, err := strconv.ParseInt(link.Query().Get("expires"), 10, 64)
+	require.NoError(t, err)
+	// The link expires with the download rather than after LinkTTL
+	assert.Equal(t, soon.Unix(), expires)
+	assert.NoError(t, delivery.VerifyDownloadLink(4, 5, expires, link.Query().Get("signature")))
+}
+
+func TestDigitalDeliveryFromEnv(t *testing.T) {
+	t.Run("signing secret", func(t *testing.T) {
+		t.Setenv("API_URL", "https://api.example.com/")
+		t.Setenv("DOWNLOAD_SIGNING_SECRET", "test-secret")
+		t.Setenv("DOWNLOAD_LINK_TTL", "30m")
+
+		delivery, err := DigitalDeliveryFromEnv(nil)
+		require.NoError(t, err)
+		assert.Equal(t, "https://api.example.com", delivery.BaseURL)
+		assert.Equal(t, []byte("test-secret"), delivery.Secret)
+		assert.Equal(t, 30*time.Minute, delivery.LinkTTL)
+	})
+
+	t.Run("no signing secret", func(t *testing.T) {
+		t.Setenv("DOWNLOAD_SIGNING_SECRET", "")
+
+		_, err := DigitalDeliveryFromEnv(nil)
+		assert.Error(t, err)
+	})
+}
@@ -13,6 +13,7 @@ import (
 
 	"github.com/petrejonn/naytife/internal/api/models"
 	"github.com/petrejonn/naytife/internal/db"
+	"github.com/petrejonn/naytife/internal/money"
 
 	retryablehttp "github.com/hashicorp/go-retryablehttp"
 	"github.com/petrejonn/naytife/internal/observability"
@@ -273,7 +274,7 @@ func (f *FlutterwaveService) generateTxRef(shopID int64, checkoutSessionID strin
 }
 
 // ProcessPayment processes a payment request and returns the payment response
-func (f *FlutterwaveService) ProcessPayment(ctx context.Context, shopID int64, req models.PaymentRequest, amount float64, currencyCode string) (*models.PaymentResponse, error) {
+func (f *FlutterwaveService) ProcessPayment(ctx context.Context, shopID int64, req models.PaymentRequest, amount money.Money) (*models.PaymentResponse, error) {
 	config, err := f.GetFlutterwaveConfig(ctx, shopID)
 	if err != nil {
 		return nil, err
@@ -285,8 +286,8 @@ func (f *FlutterwaveService) ProcessPayment(ctx context.Context, shopID int64, r
 	// Create payment request
 	paymentReq := FlutterwavePaymentRequest{
 		TxRef:    txRef,
-		Amount:   fmt.Sprintf("%.2f", amount),
-		Currency: currencyCode,
+		Amount:   amount.String(),
+		Currency: amount.Currency,
 		Customer: FlutterwaveCustomer{
 			Email: "customer@example.com", // TODO: Get customer email from checkout session
 		},
@@ -353,7 +354,7 @@ func (f *FlutterwaveService) ProcessPayment(ctx context.Context, shopID int64, r
 		PaymentID:     txRef,
 		Status:        "pending",
 		Amount:        amount,
-		CurrencyCode:  currencyCode,
+		CurrencyCode:  amount.Currency,
 		PaymentMethod: "flutterwave",
 		TransactionID: txRef,
 		NextAction: &models.PaymentNextAction{
@@ -380,8 +381,8 @@ func (f *FlutterwaveService) CreatePaymentIntent(ctx context.Context, shopID int
 	// Create payment request
 	paymentReq := FlutterwavePaymentRequest{
 		TxRef:    txRef,
-		Amount:   fmt.Sprintf("%.2f", req.Amount),
-		Currency: req.CurrencyCode,
+		Amount:   req.Amount.String(),
+		Currency: req.Amount.Currency,
 		Customer: FlutterwaveCustomer{
 			Email: "customer@example.com", // TODO: Get customer email from checkout session
 		},
@@ -442,7 +443,7 @@ func (f *FlutterwaveService) CreatePaymentIntent(ctx context.Context, shopID int
 		ClientSecret:    txRef, // Flutterwave doesn't have client secrets like Stripe
 		Status:          "pending",
 		Amount:          req.Amount,
-		CurrencyCode:    req.CurrencyCode,
+		CurrencyCode:    req.Amount.Currency,
 		NextAction: &models.PaymentNextAction{
 			Type: "redirect",
 			Data: map[string]interface{}{
@@ -505,7 +506,7 @@ func (f *FlutterwaveService) GetPaymentStatus(ctx context.Context, shopID int64,
 	response := &models.PaymentResponse{
 		PaymentID:     verifyResp.Data.TxRef,
 		Status:        f.ConvertFlutterwaveStatusToPaymentStatus(verifyResp.Data.Status),
-		Amount:        verifyResp.Data.Amount,
+		Amount:        money.FromFloat(verifyResp.Data.Amount, verifyResp.Data.Currency),
 		CurrencyCode:  verifyResp.Data.Currency,
 		PaymentMethod: "flutterwave",
 		TransactionID: verifyResp.Data.FlwRef,
@@ -522,7 +523,7 @@ func (f *FlutterwaveService) GetPaymentStatus(ctx context.Context, shopID int64,
 }
 
 // RefundPayment processes a refund for a completed payment
-func (f *FlutterwaveService) RefundPayment(ctx context.Context, shopID int64, paymentID string, amount float64, reason string) (*models.PaymentResponse, error) {
+func (f *FlutterwaveService) RefundPayment(ctx context.Context, shopID int64, paymentID string, amount money.Money, reason string) (*models.PaymentResponse, error) {
 	config, err := f.GetFlutterwaveConfig(ctx, shopID)
 	if err != nil {
 		return nil, err
@@ -542,8 +543,8 @@ func (f *FlutterwaveService) RefundPayment(ctx context.Context, shopID int64, pa
 
 	// Create refund request
 	refundReq := FlutterwaveRefundRequest{}
-	if amount > 0 {
-		refundReq.Amount = fmt.Sprintf("%.2f", amount)
+	if amount.Amount > 0 {
+		refundReq.Amount = amount.String()
 	}
 
 	refundReqJSON, err := json.Marshal(refundReq)
@@ -591,13 +592,12 @@ func (f *FlutterwaveService) RefundPayment(ctx context.Context, shopID int64, pa
 		return nil, fmt.Errorf("failed to create refund: %s", refundResp.Message)
 	}
 
-	refundAmount := refundResp.Data.AmountRefunded
 	now := time.Now()
 
 	response := &models.PaymentResponse{
 		PaymentID:     paymentID,
 		Status:        "refunded",
-		Amount:        refundAmount,
+		Amount:        money.FromFloat(refundResp.Data.AmountRefunded, paymentResp.CurrencyCode),
 		CurrencyCode:  paymentResp.CurrencyCode,
 		PaymentMethod: "flutterwave",
 		TransactionID: refundResp.Data.FlwRef,
@@ -643,12 +643,12 @@ func (f *FlutterwaveService) HandleWebhook(ctx context.Context, payload []byte,
 				webhookPayload.PaymentID = txRef
 				webhookPayload.TransactionID = txRef
 			}
-			if amount, exists := data["amount"].(float64); exists {
-				webhookPayload.Amount = amount
-			}
 			if currency, exists := data["currency"].(string); exists {
 				webhookPayload.Currency = currency
 			}
+			if amount, exists := data["amount"].(float64); exists {
+				webhookPayload.Amount = money.FromFloat(amount, webhookPayload.Currency)
+			}
 			if status, exists := data["status"].(string); exists {
 				webhookPayload.Status = f.ConvertFlutterwaveStatusToPaymentStatus(status)
 			}
@@ -660,3 +660,13 @@ func (f *FlutterwaveService) HandleWebhook(ctx context.Context, payload []byte,
 
 	return webhookPayload, nil
 }
+
+// SavePaymentMethod is not supported; Flutterwave payments cannot be charged off session here
+func (f *FlutterwaveService) SavePaymentMethod(ctx context.Context, shopID int64, req models.SavePaymentMethodRequest) (*models.SavedPaymentMethod, error) {
+	return nil, ErrRecurringPaymentsUnsupported
+}
+
+// ChargeSavedPaymentMethod is not supported; Flutterwave payments cannot be charged off session here
+func (f *FlutterwaveService) ChargeSavedPaymentMethod(ctx context.Context, shopID int64, req models.SavedPaymentChargeRequest) (*models.PaymentResponse, error) {
+	return nil, ErrRecurringPaymentsUnsupported
+}
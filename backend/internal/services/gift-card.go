@@ -0,0 +1,438 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/petrejonn/naytife/internal/db"
+	dberrors "github.com/petrejonn/naytife/internal/db/errors"
+	"github.com/petrejonn/naytife/internal/money"
+)
+
+var (
+	ErrGiftCardNotFound        = errors.New("gift card not found")
+	ErrGiftCardUnusable        = errors.New("gift card is disabled, expired or has no balance")
+	ErrInsufficientGiftCard    = errors.New("insufficient gift card balance")
+	ErrInsufficientStoreCredit = errors.New("insufficient store credit")
+	ErrOrderNotPayable         = errors.New("order is not awaiting payment")
+)
+
+// Gift card codes leave out characters that are easily mistaken for one another
+const (
+	giftCardCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	giftCardCodeLength   = 16
+)
+
+// GenerateGiftCardCode returns a random gift card code
+func GenerateGiftCardCode() (string, error) {
+	max := big.NewInt(int64(len(giftCardCodeAlphabet)))
+	var b strings.Builder
+	for i := 0; i < giftCardCodeLength; i++ {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		b.WriteByte(giftCardCodeAlphabet[n.Int64()])
+	}
+	return b.String(), nil
+}
+
+// NormalizeGiftCardCode returns a code the way it is stored, so shoppers can type
+// it in any case and with the spaces or dashes it is printed with
+func NormalizeGiftCardCode(code string) string {
+	return strings.Map(func(r rune) rune {
+		if r == ' ' || r == '-' {
+			return -1
+		}
+		return r
+	}, strings.ToUpper(strings.TrimSpace(code)))
+}
+
+// ledgerQueries are the queries gift card and store credit bookkeeping runs, all
+// within the caller's transaction. *db.Queries implements them.
+type ledgerQueries interface {
+	GetGiftCardByCode(ctx context.Context, arg db.GetGiftCardByCodeParams) (db.GiftCard, error)
+	CreateGiftCard(ctx context.Context, arg db.CreateGiftCardParams) (db.GiftCard, error)
+	RedeemGiftCard(ctx context.Context, arg db.RedeemGiftCardParams) (db.GiftCard, error)
+	CreditGiftCard(ctx context.Context, arg db.CreditGiftCardParams) (db.GiftCard, error)
+	CreateGiftCardTransaction(ctx context.Context, arg db.CreateGiftCardTransactionParams) (db.GiftCardTransaction, error)
+	QueueGiftCardNotification(ctx context.Context, arg db.QueueGiftCardNotificationParams) (int64, error)
+	ListOrderGiftCardRedemptions(ctx context.Context, arg db.ListOrderGiftCardRedemptionsParams) ([]db.ListOrderGiftCardRedemptionsRow, error)
+	ListOrderGiftCardItems(ctx context.Context, arg db.ListOrderGiftCardItemsParams) ([]db.ListOrderGiftCardItemsRow, error)
+	CountOrderGiftCards(ctx context.Context, arg db.CountOrderGiftCardsParams) (int64, error)
+	LockOrderTender(ctx context.Context, arg db.LockOrderTenderParams) (db.LockOrderTenderRow, error)
+	LockCustomerStoreCredit(ctx context.Context, arg db.LockCustomerStoreCreditParams) (uuid.UUID, error)
+	GetStoreCreditBalance(ctx context.Context, arg db.GetStoreCreditBalanceParams) (pgtype.Numeric, error)
+	CreateStoreCreditTransaction(ctx context.Context, arg db.CreateStoreCreditTransactionParams) (db.StoreCreditTransaction, error)
+	ListOrderStoreCreditRedemptions(ctx context.Context, arg db.ListOrderStoreCreditRedemptionsParams) ([]db.ListOrderStoreCreditRedemptionsRow, error)
+}
+
+// IssueGiftCard creates a gift card with the opening entry of its ledger and queues
+// the email to its recipient. A code is generated when arg.Code is empty.
+func IssueGiftCard(ctx context.Context, q ledgerQueries, arg db.CreateGiftCardParams) (db.GiftCard, error) {
+	if arg.Code == "" {
+		code, err := GenerateGiftCardCode()
+		if err != nil {
+			return db.GiftCard{}, fmt.Errorf("failed to generate gift card code: %w", err)
+		}
+		arg.Code = code
+	} else {
+		arg.Code = NormalizeGiftCardCode(arg.Code)
+	}
+	card, err := q.CreateGiftCard(ctx, arg)
+	if err != nil {
+		return db.GiftCard{}, err
+	}
+	if _, err := q.CreateGiftCardTransaction(ctx, db.CreateGiftCardTransactionParams{
+		GiftCardID:   card.GiftCardID,
+		Kind:         db.CreditTransactionKindIssue,
+		Amount:       card.InitialAmount,
+		BalanceAfter: card.Balance,
+		OrderID:      card.OrderID,
+		Note:         card.Note,
+		CreatedBy:    card.CreatedBy,
+		ShopID:       card.ShopID,
+	}); err != nil {
+		return db.GiftCard{}, fmt.Errorf("failed to record gift card issue: %w", err)
+	}
+	if _, err := q.QueueGiftCardNotification(ctx, db.QueueGiftCardNotificationParams{
+		GiftCardID: card.GiftCardID,
+		ShopID:     card.ShopID,
+	}); err != nil {
+		return db.GiftCard{}, fmt.Errorf("failed to queue gift card notification: %w", err)
+	}
+	return card, nil
+}
+
+// AdjustGiftCard adds a signed amount to the balance of a gift card and records it
+// in the ledger. It fails with ErrInsufficientGiftCard when the balance would go negative.
+func AdjustGiftCard(ctx context.Context, q ledgerQueries, shopID, giftCardID int64, kind db.CreditTransactionKind, amount money.Money, orderID *int64, note, createdBy *string) (db.GiftCard, error) {
+	card, err := q.CreditGiftCard(ctx, db.CreditGiftCardParams{
+		Amount:     amount.Numeric(),
+		GiftCardID: giftCardID,
+		ShopID:     shopID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return db.GiftCard{}, ErrGiftCardNotFound
+		}
+		if isCheckViolation(err) {
+			return db.GiftCard{}, ErrInsufficientGiftCard
+		}
+		return db.GiftCard{}, err
+	}
+	if _, err := q.CreateGiftCardTransaction(ctx, db.CreateGiftCardTransactionParams{
+		GiftCardID:   card.GiftCardID,
+		Kind:         kind,
+		Amount:       amount.Numeric(),
+		BalanceAfter: card.Balance,
+		OrderID:      orderID,
+		Note:         note,
+		CreatedBy:    createdBy,
+		ShopID:       shopID,
+	}); err != nil {
+		return db.GiftCard{}, fmt.Errorf("failed to record gift card transaction: %w", err)
+	}
+	return card, nil
+}
+
+// AdjustStoreCredit appends a signed amount to a customer's store credit ledger.
+// It fails with ErrInsufficientStoreCredit when the balance would go negative.
+func AdjustStoreCredit(ctx context.Context, q ledgerQueries, shopID int64, customerID uuid.UUID, kind db.CreditTransactionKind, amount money.Money, reason *string, orderID *int64, note, createdBy *string) (db.StoreCreditTransaction, error) {
+	if _, err := q.LockCustomerStoreCredit(ctx, db.LockCustomerStoreCreditParams{
+		ShopCustomerID: customerID,
+		ShopID:         shopID,
+	}); err != nil {
+		return db.StoreCreditTransaction{}, err
+	}
+	entry, err := q.CreateStoreCreditTransaction(ctx, db.CreateStoreCreditTransactionParams{
+		ShopCustomerID: customerID,
+		Kind:           kind,
+		Amount:         amount.Numeric(),
+		ShopID:         shopID,
+		Reason:         reason,
+		OrderID:        orderID,
+		Note:           note,
+		CreatedBy:      createdBy,
+	})
+	if err != nil {
+		if isCheckViolation(err) {
+			return db.StoreCreditTransaction{}, ErrInsufficientStoreCredit
+		}
+		return db.StoreCreditTransaction{}, err
+	}
+	return entry, nil
+}
+
+// StoreCreditBalance returns the store credit balance of a customer
+func StoreCreditBalance(ctx context.Context, repo db.Repository, shopID int64, customerID uuid.UUID, currency string) (money.Money, error) {
+	balance, err := repo.GetStoreCreditBalance(ctx, db.GetStoreCreditBalanceParams{
+		ShopCustomerID: customerID,
+		ShopID:         shopID,
+	})
+	if err != nil {
+		return money.Money{}, err
+	}
+	return money.FromNumeric(balance, currency)
+}
+
+// OrderTender is what gift cards and store credit pay towards an order, in the
+// shop currency. The payment processor charges the rest.
+type OrderTender struct {
+	GiftCards   money.Money
+	StoreCredit money.Money
+}
+
+// Total returns the amount covered by gift cards and store credit together
+func (t OrderTender) Total() money.Money {
+	return t.GiftCards.Add(t.StoreCredit)
+}
+
+// AppliedOrderTender returns what gift cards and store credit already pay towards
+// an order
+func AppliedOrderTender(ctx context.Context, q ledgerQueries, shopID, orderID int64, currency string) (OrderTender, error) {
+	tender := OrderTender{GiftCards: money.Zero(currency), StoreCredit: money.Zero(currency)}
+	cards, err := q.ListOrderGiftCardRedemptions(ctx, db.ListOrderGiftCardRedemptionsParams{OrderID: orderID, ShopID: shopID})
+	if err != nil {
+		return tender, err
+	}
+	for _, card := range cards {
+		amount, err := money.FromNumeric(card.Amount, currency)
+		if err != nil {
+			return tender, err
+		}
+		tender.GiftCards = tender.GiftCards.Add(amount)
+	}
+	credits, err := q.ListOrderStoreCreditRedemptions(ctx, db.ListOrderStoreCreditRedemptionsParams{OrderID: orderID, ShopID: shopID})
+	if err != nil {
+		return tender, err
+	}
+	for _, credit := range credits {
+		amount, err := money.FromNumeric(credit.Amount, currency)
+		if err != nil {
+			return tender, err
+		}
+		tender.StoreCredit = tender.StoreCredit.Add(amount)
+	}
+	return tender, nil
+}
+
+// ApplyOrderTender redeems the gift cards with the given codes, in order, and then
+// the store credit of the order's customer when useStoreCredit is set, towards the
+// part of the order total they do not cover yet. Applying it again for the same
+// order only charges what is still due, so a retried checkout never overpays.
+// It fails with ErrOrderNotPayable unless the order is pending and unpaid.
+func ApplyOrderTender(ctx context.Context, q ledgerQueries, order db.Order, currency string, codes []string, useStoreCredit bool) (OrderTender, error) {
+	state, err := q.LockOrderTender(ctx, db.LockOrderTenderParams{OrderID: order.OrderID, ShopID: order.ShopID})
+	if err != nil {
+		return OrderTender{}, err
+	}
+	if state.Status != db.OrderStatusTypePending || (state.PaymentStatus != db.PaymentStatusTypePending && state.PaymentStatus != db.PaymentStatusTypeFailed) {
+		return OrderTender{}, ErrOrderNotPayable
+	}
+	tender, err := AppliedOrderTender(ctx, q, order.ShopID, order.OrderID, currency)
+	if err != nil {
+		return tender, err
+	}
+	total, err := money.FromNumeric(order.Amount, currency)
+	if err != nil {
+		return tender, err
+	}
+	due := total.Sub(tender.Total())
+
+	// Cards of an earlier attempt were already drawn down as far as the order needed
+	redeemed, err := q.ListOrderGiftCardRedemptions(ctx, db.ListOrderGiftCardRedemptionsParams{OrderID: order.OrderID, ShopID: order.ShopID})
+	if err != nil {
+		return tender, err
+	}
+	applied := map[int64]bool{}
+	for _, card := range redeemed {
+		applied[card.GiftCardID] = true
+	}
+
+	seen := map[string]bool{}
+	for _, code := range codes {
+		code = NormalizeGiftCardCode(code)
+		if code == "" || seen[code] {
+			continue
+		}
+		seen[code] = true
+		if due.IsZero() || due.IsNegative() {
+			break
+		}
+		card, err := q.GetGiftCardByCode(ctx, db.GetGiftCardByCodeParams{Code: code, ShopID: order.ShopID})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return tender, fmt.Errorf("%w: %s", ErrGiftCardNotFound, code)
+			}
+			return tender, err
+		}
+		if applied[card.GiftCardID] {
+			continue
+		}
+		if card.CurrencyCode != currency {
+			return tender, fmt.Errorf("%w: %s", ErrGiftCardUnusable, code)
+		}
+		balance, err := money.FromNumeric(card.Balance, currency)
+		if err != nil {
+			return tender, err
+		}
+		if balance.IsZero() {
+			return tender, fmt.Errorf("%w: %s", ErrGiftCardUnusable, code)
+		}
+		take := minMoney(balance, due)
+		card, err = q.RedeemGiftCard(ctx, db.RedeemGiftCardParams{
+			Amount:     take.Numeric(),
+			GiftCardID: card.GiftCardID,
+			ShopID:     order.ShopID,
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return tender, fmt.Errorf("%w: %s", ErrGiftCardUnusable, code)
+			}
+			return tender, err
+		}
+		if _, err := q.CreateGiftCardTransaction(ctx, db.CreateGiftCardTransactionParams{
+			GiftCardID:   card.GiftCardID,
+			Kind:         db.CreditTransactionKindRedeem,
+			Amount:       take.Mul(-1).Numeric(),
+			BalanceAfter: card.Balance,
+			OrderID:      &order.OrderID,
+			ShopID:       order.ShopID,
+		}); err != nil {
+			return tender, fmt.Errorf("failed to record gift card redemption: %w", err)
+		}
+		tender.GiftCards = tender.GiftCards.Add(take)
+		due = due.Sub(take)
+	}
+
+	if useStoreCredit && order.ShopCustomerID.Valid && !due.IsZero() && !due.IsNegative() {
+		customerID := uuid.UUID(order.ShopCustomerID.Bytes)
+		if _, err := q.LockCustomerStoreCredit(ctx, db.LockCustomerStoreCreditParams{
+			ShopCustomerID: customerID,
+			ShopID:         order.ShopID,
+		}); err != nil {
+			return tender, err
+		}
+		balanceNum, err := q.GetStoreCreditBalance(ctx, db.GetStoreCreditBalanceParams{
+			ShopCustomerID: customerID,
+			ShopID:         order.ShopID,
+		})
+		if err != nil {
+			return tender, err
+		}
+		balance, err := money.FromNumeric(balanceNum, currency)
+		if err != nil {
+			return tender, err
+		}
+		if !balance.IsZero() && !balance.IsNegative() {
+			take := minMoney(balance, due)
+			reason := "order"
+			if _, err := AdjustStoreCredit(ctx, q, order.ShopID, customerID, db.CreditTransactionKindRedeem, take.Mul(-1), &reason, &order.OrderID, nil, nil); err != nil {
+				return tender, err
+			}
+			tender.StoreCredit = tender.StoreCredit.Add(take)
+		}
+	}
+	return tender, nil
+}
+
+// ReleaseOrderTender gives back to the gift cards and store credit everything they
+// still pay towards an order, e.g. when its payment fails or it is refunded
+func ReleaseOrderTender(ctx context.Context, q ledgerQueries, shopID, orderID int64, currency string) (OrderTender, error) {
+	tender := OrderTender{GiftCards: money.Zero(currency), StoreCredit: money.Zero(currency)}
+	if _, err := q.LockOrderTender(ctx, db.LockOrderTenderParams{OrderID: orderID, ShopID: shopID}); err != nil {
+		return tender, err
+	}
+	cards, err := q.ListOrderGiftCardRedemptions(ctx, db.ListOrderGiftCardRedemptionsParams{OrderID: orderID, ShopID: shopID})
+	if err != nil {
+		return tender, err
+	}
+	for _, card := range cards {
+		amount, err := money.FromNumeric(card.Amount, currency)
+		if err != nil {
+			return tender, err
+		}
+		if _, err := AdjustGiftCard(ctx, q, shopID, card.GiftCardID, db.CreditTransactionKindRefund, amount, &orderID, nil, nil); err != nil {
+			return tender, err
+		}
+		tender.GiftCards = tender.GiftCards.Add(amount)
+	}
+	credits, err := q.ListOrderStoreCreditRedemptions(ctx, db.ListOrderStoreCreditRedemptionsParams{OrderID: orderID, ShopID: shopID})
+	if err != nil {
+		return tender, err
+	}
+	for _, credit := range credits {
+		amount, err := money.FromNumeric(credit.Amount, currency)
+		if err != nil {
+			return tender, err
+		}
+		reason := "order"
+		if _, err := AdjustStoreCredit(ctx, q, shopID, credit.ShopCustomerID, db.CreditTransactionKindRefund, amount, &reason, &orderID, nil, nil); err != nil {
+			return tender, err
+		}
+		tender.StoreCredit = tender.StoreCredit.Add(amount)
+	}
+	return tender, nil
+}
+
+// IssueOrderGiftCards issues a gift card for every unit of the gift card products of
+// a paid order, worth the price paid and sent to the order's email. Orders that
+// already issued their cards are left alone, so it is safe to call on every
+// payment confirmation; each unit of an order item gets at most one card.
+func IssueOrderGiftCards(ctx context.Context, q ledgerQueries, order db.Order, currency string) ([]db.GiftCard, error) {
+	if _, err := q.LockOrderTender(ctx, db.LockOrderTenderParams{OrderID: order.OrderID, ShopID: order.ShopID}); err != nil {
+		return nil, err
+	}
+	issued, err := q.CountOrderGiftCards(ctx, db.CountOrderGiftCardsParams{OrderID: order.OrderID, ShopID: order.ShopID})
+	if err != nil || issued > 0 {
+		return nil, err
+	}
+	items, err := q.ListOrderGiftCardItems(ctx, db.ListOrderGiftCardItemsParams{OrderID: order.OrderID, ShopID: order.ShopID})
+	if err != nil {
+		return nil, err
+	}
+	var cards []db.GiftCard
+	for _, item := range items {
+		for unit := int64(0); unit < item.Quantity; unit++ {
+			card, err := IssueGiftCard(ctx, q, db.CreateGiftCardParams{
+				Amount:         item.Price,
+				CurrencyCode:   currency,
+				RecipientEmail: order.CustomerEmail,
+				ShopCustomerID: order.ShopCustomerID,
+				OrderID:        &order.OrderID,
+				OrderItemID:    &item.OrderItemID,
+				OrderItemUnit:  &unit,
+				ShopID:         order.ShopID,
+			})
+			if errors.Is(err, pgx.ErrNoRows) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			cards = append(cards, card)
+		}
+	}
+	return cards, nil
+}
+
+func minMoney(a, b money.Money) money.Money {
+	if a.Sub(b).IsNegative() {
+		return a
+	}
+	return b
+}
+
+func isCheckViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == dberrors.CheckViolation
+}
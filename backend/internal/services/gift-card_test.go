@@ -0,0 +1,532 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/petrejonn/naytife/internal/db"
+	dberrors "github.com/petrejonn/naytife/internal/db/errors"
+	"github.com/petrejonn/naytife/internal/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeLedger keeps gift cards and both ledgers in memory, following the queries'
+// rules: redemptions need a usable card with enough balance, store credit
+// entries must not overdraw the running balance and an order item unit gets one
+// card at most. Orders are pending and unpaid unless orders says otherwise.
+type fakeLedger struct {
+	orders        map[int64]db.LockOrderTenderRow
+	cards         []db.GiftCard
+	cardTxns      []db.GiftCardTransaction
+	creditTxns    []db.StoreCreditTransaction
+	giftCardItems []db.ListOrderGiftCardItemsRow
+	notifications int
+}
+
+func usd(n pgtype.Numeric) money.Money {
+	m, err := money.FromNumeric(n, "USD")
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+func (l *fakeLedger) card(id int64) *db.GiftCard {
+	for i := range l.cards {
+		if l.cards[i].GiftCardID == id {
+			return &l.cards[i]
+		}
+	}
+	return nil
+}
+
+func (l *fakeLedger) GetGiftCardByCode(ctx context.Context, arg db.GetGiftCardByCodeParams) (db.GiftCard, error) {
+	for _, card := range l.cards {
+		if card.Code == arg.Code && card.ShopID == arg.ShopID {
+			return card, nil
+		}
+	}
+	return db.GiftCard{}, pgx.ErrNoRows
+}
+
+func (l *fakeLedger) LockOrderTender(ctx context.Context, arg db.LockOrderTenderParams) (db.LockOrderTenderRow, error) {
+	if state, ok := l.orders[arg.OrderID]; ok {
+		return state, nil
+	}
+	return db.LockOrderTenderRow{Status: db.OrderStatusTypePending, PaymentStatus: db.PaymentStatusTypePending}, nil
+}
+
+func (l *fakeLedger) CreateGiftCard(ctx context.Context, arg db.CreateGiftCardParams) (db.GiftCard, error) {
+	if arg.OrderItemID != nil {
+		for _, card := range l.cards {
+			if card.OrderItemID != nil && *card.OrderItemID == *arg.OrderItemID && *card.OrderItemUnit == *arg.OrderItemUnit {
+				return db.GiftCard{}, pgx.ErrNoRows
+			}
+		}
+	}
+	card := db.GiftCard{
+		GiftCardID:     int64(len(l.cards) + 1),
+		Code:           arg.Code,
+		InitialAmount:  arg.Amount,
+		Balance:        arg.Amount,
+		CurrencyCode:   arg.CurrencyCode,
+		RecipientEmail: arg.RecipientEmail,
+		ShopCustomerID: arg.ShopCustomerID,
+		OrderID:        arg.OrderID,
+		OrderItemID:    arg.OrderItemID,
+		OrderItemUnit:  arg.OrderItemUnit,
+		ShopID:         arg.ShopID,
+	}
+	l.cards = append(l.cards, card)
+	return card, nil
+}
+
+func (l *fakeLedger) RedeemGiftCard(ctx context.Context, arg db.RedeemGiftCardParams) (db.GiftCard, error) {
+	card := l.card(arg.GiftCardID)
+	if card == nil || card.Disabled || usd(card.Balance).Sub(usd(arg.Amount)).IsNegative() {
+		return db.GiftCard{}, pgx.ErrNoRows
+	}
+	card.Balance = usd(card.Balance).Sub(usd(arg.Amount)).Numeric()
+	return *card, nil
+}
+
+func (l *fakeLedger) CreditGiftCard(ctx context.Context, arg db.CreditGiftCardParams) (db.GiftCard, error) {
+	card := l.card(arg.GiftCardID)
+	if card == nil {
+		return db.GiftCard{}, pgx.ErrNoRows
+	}
+	balance := usd(card.Balance).Add(usd(arg.Amount))
+	if balance.IsNegative() {
+		return db.GiftCard{}, &pgconn.PgError{Code: dberrors.CheckViolation}
+	}
+	card.Balance = balance.Numeric()
+	return *card, nil
+}
+
+func (l *fakeLedger) CreateGiftCardTransaction(ctx context.Context, arg db.CreateGiftCardTransactionParams) (db.GiftCardTransaction, error) {
+	txn := db.GiftCardTransaction{
+		TransactionID: int64(len(l.cardTxns) + 1),
+		GiftCardID:    arg.GiftCardID,
+		Kind:          arg.Kind,
+		Amount:        arg.Amount,
+		BalanceAfter:  arg.BalanceAfter,
+		OrderID:       arg.OrderID,
+		ShopID:        arg.ShopID,
+	}
+	l.cardTxns = append(l.cardTxns, txn)
+	return txn, nil
+}
+
+func (l *fakeLedger) QueueGiftCardNotification(ctx context.Context, arg db.QueueGiftCardNotificationParams) (int64, error) {
+	if card := l.card(arg.GiftCardID); card == nil || card.RecipientEmail == nil {
+		return 0, nil
+	}
+	l.notifications++
+	return 1, nil
+}
+
+func (l *fakeLedger) ListOrderGiftCardRedemptions(ctx context.Context, arg db.ListOrderGiftCardRedemptionsParams) ([]db.ListOrderGiftCardRedemptionsRow, error) {
+	net := map[int64]money.Money{}
+	for _, txn := range l.cardTxns {
+		if txn.OrderID != nil && *txn.OrderID == arg.OrderID && (txn.Kind == db.CreditTransactionKindRedeem || txn.Kind == db.CreditTransactionKindRefund) {
+			if _, ok := net[txn.GiftCardID]; !ok {
+				net[txn.GiftCardID] = money.Zero("USD")
+			}
+			net[txn.GiftCardID] = net[txn.GiftCardID].Add(usd(txn.Amount))
+		}
+	}
+	var rows []db.ListOrderGiftCardRedemptionsRow
+	for id, sum := range net {
+		if sum.IsNegative() {
+			rows = append(rows, db.ListOrderGiftCardRedemptionsRow{GiftCardID: id, Amount: sum.Mul(-1).Numeric()})
+		}
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].GiftCardID < rows[j].GiftCardID })
+	return rows, nil
+}
+
+func (l *fakeLedger) ListOrderGiftCardItems(ctx context.Context, arg db.ListOrderGiftCardItemsParams) ([]db.ListOrderGiftCardItemsRow, error) {
+	return l.giftCardItems, nil
+}
+
+func (l *fakeLedger) CountOrderGiftCards(ctx context.Context, arg db.CountOrderGiftCardsParams) (int64, error) {
+	var count int64
+	for _, card := range l.cards {
+		if card.OrderID != nil && *card.OrderID == arg.OrderID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (l *fakeLedger) LockCustomerStoreCredit(ctx context.Context, arg db.LockCustomerStoreCreditParams) (uuid.UUID, error) {
+	return arg.ShopCustomerID, nil
+}
+
+func (l *fakeLedger) creditBalance(customerID uuid.UUID) money.Money {
+	balance := money.Zero("USD")
+	for _, txn := range l.creditTxns {
+		if txn.ShopCustomerID == customerID {
+			balance = usd(txn.BalanceAfter)
+		}
+	}
+	return balance
+}
+
+func (l *fakeLedger) GetStoreCreditBalance(ctx context.Context, arg db.GetStoreCreditBalanceParams) (pgtype.Numeric, error) {
+	return l.creditBalance(arg.ShopCustomerID).Numeric(), nil
+}
+
+func (l *fakeLedger) CreateStoreCreditTransaction(ctx context.Context, arg db.CreateStoreCreditTransactionParams) (db.StoreCreditTransaction, error) {
+	balance := l.creditBalance(arg.ShopCustomerID).Add(usd(arg.Amount))
+	if balance.IsNegative() {
+		return db.StoreCreditTransaction{}, &pgconn.PgError{Code: dberrors.CheckViolation}
+	}
+	txn := db.StoreCreditTransaction{
+		TransactionID:  int64(len(l.creditTxns) + 1),
+		ShopCustomerID: arg.ShopCustomerID,
+		Kind:           arg.Kind,
+		Amount:         arg.Amount,
+		BalanceAfter:   balance.Numeric(),
+		OrderID:        arg.OrderID,
+		ShopID:         arg.ShopID,
+	}
+	l.creditTxns = append(l.creditTxns, txn)
+	return txn, nil
+}
+
+func (l *fakeLedger) ListOrderStoreCreditRedemptions(ctx context.Context, arg db.ListOrderStoreCreditRedemptionsParams) ([]db.ListOrderStoreCreditRedemptionsRow, error) {
+	net := map[uuid.UUID]money.Money{}
+	for _, txn := range l.creditTxns {
+		if txn.OrderID != nil && *txn.OrderID == arg.OrderID && (txn.Kind == db.CreditTransactionKindRedeem || txn.Kind == db.CreditTransactionKindRefund) {
+			if _, ok := net[txn.ShopCustomerID]; !ok {
+				net[txn.ShopCustomerID] = money.Zero("USD")
+			}
+			net[txn.ShopCustomerID] = net[txn.ShopCustomerID].Add(usd(txn.Amount))
+		}
+	}
+	var rows []db.ListOrderStoreCreditRedemptionsRow
+	for id, sum := range net {
+		if sum.IsNegative() {
+			rows = append(rows, db.ListOrderStoreCreditRedemptionsRow{ShopCustomerID: id, Amount: sum.Mul(-1).Numeric()})
+		}
+	}
+	return rows, nil
+}
+
+// newLedger returns a ledger with two USD cards, a EUR card and a customer
+// holding store credit
+func newLedger(t *testing.T, customerID uuid.UUID, credit int64) *fakeLedger {
+	l := &fakeLedger{cards: []db.GiftCard{
+		{GiftCardID: 1, Code: "AAAABBBBCCCCDDDD", Balance: money.New(3000, "USD").Numeric(), CurrencyCode: "USD", ShopID: 1},
+		{GiftCardID: 2, Code: "EEEEFFFFGGGGHHHH", Balance: money.New(4000, "USD").Numeric(), CurrencyCode: "USD", ShopID: 1},
+		{GiftCardID: 3, Code: "JJJJKKKKLLLLMMMM", Balance: money.New(4000, "EUR").Numeric(), CurrencyCode: "EUR", ShopID: 1},
+		{GiftCardID: 4, Code: "NNNNPPPPQQQQRRRR", Balance: money.Zero("USD").Numeric(), CurrencyCode: "USD", ShopID: 1},
+	}}
+	if credit > 0 {
+		_, err := AdjustStoreCredit(context.Background(), l, 1, customerID, db.CreditTransactionKindAdjust, money.New(credit, "USD"), nil, nil, nil, nil)
+		require.NoError(t, err)
+	}
+	return l
+}
+
+func TestApplyOrderTender(t *testing.T) {
+	customerID := uuid.New()
+	customerOrder := db.Order{
+		OrderID:        7,
+		Amount:         money.New(5000, "USD").Numeric(),
+		ShopCustomerID: pgtype.UUID{Bytes: customerID, Valid: true},
+		ShopID:         1,
+	}
+	guestOrder := customerOrder
+	guestOrder.ShopCustomerID = pgtype.UUID{}
+
+	tests := []struct {
+		name            string
+		order           db.Order
+		codes           []string
+		useStoreCredit  bool
+		wantGiftCards   int64
+		wantStoreCredit int64
+		wantBalances    map[int64]int64
+		wantCredit      int64
+		wantErr         error
+	}{
+		{
+			name:          "one card pays part of the order",
+			order:         customerOrder,
+			codes:         []string{"AAAABBBBCCCCDDDD"},
+			wantGiftCards: 3000,
+			wantBalances:  map[int64]int64{1: 0, 2: 4000},
+			wantCredit:    10000,
+		},
+		{
+			name:          "second card only pays what is still due",
+			order:         customerOrder,
+			codes:         []string{"AAAABBBBCCCCDDDD", "EEEEFFFFGGGGHHHH"},
+			wantGiftCards: 5000,
+			wantBalances:  map[int64]int64{1: 0, 2: 2000},
+			wantCredit:    10000,
+		},
+		{
+			name:          "codes are normalized and used once",
+			order:         customerOrder,
+			codes:         []string{"eeee-ffff-gggg-hhhh", " EEEE FFFF GGGG HHHH "},
+			wantGiftCards: 4000,
+			wantBalances:  map[int64]int64{1: 3000, 2: 0},
+			wantCredit:    10000,
+		},
+		{
+			name:            "store credit pays the rest after gift cards",
+			order:           customerOrder,
+			codes:           []string{"AAAABBBBCCCCDDDD"},
+			useStoreCredit:  true,
+			wantGiftCards:   3000,
+			wantStoreCredit: 2000,
+			wantBalances:    map[int64]int64{1: 0, 2: 4000},
+			wantCredit:      8000,
+		},
+		{
+			name:           "guest orders cannot use store credit",
+			order:          guestOrder,
+			codes:          []string{"AAAABBBBCCCCDDDD"},
+			useStoreCredit: true,
+			wantGiftCards:  3000,
+			wantBalances:   map[int64]int64{1: 0},
+			wantCredit:     10000,
+		},
+		{
+			name:    "unknown code",
+			order:   customerOrder,
+			codes:   []string{"ZZZZZZZZZZZZZZZZ"},
+			wantErr: ErrGiftCardNotFound,
+		},
+		{
+			name:    "card in another currency",
+			order:   customerOrder,
+			codes:   []string{"JJJJKKKKLLLLMMMM"},
+			wantErr: ErrGiftCardUnusable,
+		},
+		{
+			name:    "card without balance",
+			order:   customerOrder,
+			codes:   []string{"NNNNPPPPQQQQRRRR"},
+			wantErr: ErrGiftCardUnusable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := newLedger(t, customerID, 10000)
+
+			tender, err := ApplyOrderTender(context.Background(), l, tt.order, "USD", tt.codes, tt.useStoreCredit)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, money.New(tt.wantGiftCards, "USD"), tender.GiftCards)
+			assert.Equal(t, money.New(tt.wantStoreCredit, "USD"), tender.StoreCredit)
+			for id, want := range tt.wantBalances {
+				assert.Equal(t, money.New(want, "USD"), usd(l.card(id).Balance), "balance of card %d", id)
+			}
+			assert.Equal(t, money.New(tt.wantCredit, "USD"), l.creditBalance(customerID))
+
+			// Every redemption is in the ledger with the balance it left
+			for _, txn := range l.cardTxns {
+				if txn.Kind == db.CreditTransactionKindRedeem {
+					assert.True(t, usd(txn.Amount).IsNegative())
+					assert.Equal(t, *txn.OrderID, tt.order.OrderID)
+				}
+			}
+		})
+	}
+}
+
+func TestApplyOrderTenderNotPayable(t *testing.T) {
+	customerID := uuid.New()
+	order := db.Order{
+		OrderID:        7,
+		Amount:         money.New(5000, "USD").Numeric(),
+		ShopCustomerID: pgtype.UUID{Bytes: customerID, Valid: true},
+		ShopID:         1,
+	}
+
+	tests := []struct {
+		name    string
+		state   db.LockOrderTenderRow
+		wantErr error
+	}{
+		{"pending and unpaid", db.LockOrderTenderRow{Status: db.OrderStatusTypePending, PaymentStatus: db.PaymentStatusTypePending}, nil},
+		{"earlier payment failed", db.LockOrderTenderRow{Status: db.OrderStatusTypePending, PaymentStatus: db.PaymentStatusTypeFailed}, nil},
+		{"already paid", db.LockOrderTenderRow{Status: db.OrderStatusTypePending, PaymentStatus: db.PaymentStatusTypePaid}, ErrOrderNotPayable},
+		{"processing", db.LockOrderTenderRow{Status: db.OrderStatusTypeProcessing, PaymentStatus: db.PaymentStatusTypePaid}, ErrOrderNotPayable},
+		{"cancelled", db.LockOrderTenderRow{Status: db.OrderStatusTypeCancelled, PaymentStatus: db.PaymentStatusTypePending}, ErrOrderNotPayable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := newLedger(t, customerID, 10000)
+			l.orders = map[int64]db.LockOrderTenderRow{order.OrderID: tt.state}
+
+			_, err := ApplyOrderTender(context.Background(), l, order, "USD", []string{"AAAABBBBCCCCDDDD"}, true)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Equal(t, money.New(3000, "USD"), usd(l.card(1).Balance))
+				assert.Equal(t, money.New(10000, "USD"), l.creditBalance(customerID))
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestApplyOrderTenderRetry(t *testing.T) {
+	customerID := uuid.New()
+	l := newLedger(t, customerID, 10000)
+	order := db.Order{
+		OrderID:        7,
+		Amount:         money.New(5000, "USD").Numeric(),
+		ShopCustomerID: pgtype.UUID{Bytes: customerID, Valid: true},
+		ShopID:         1,
+	}
+
+	first, err := ApplyOrderTender(context.Background(), l, order, "USD", []string{"AAAABBBBCCCCDDDD"}, false)
+	require.NoError(t, err)
+	assert.Equal(t, money.New(3000, "USD"), first.Total())
+
+	// A retried checkout keeps what was already applied and only charges the rest
+	second, err := ApplyOrderTender(context.Background(), l, order, "USD", []string{"AAAABBBBCCCCDDDD", "EEEEFFFFGGGGHHHH"}, true)
+	require.NoError(t, err)
+	assert.Equal(t, money.New(5000, "USD"), second.GiftCards)
+	assert.Equal(t, money.Zero("USD"), second.StoreCredit)
+	assert.Equal(t, money.New(2000, "USD"), usd(l.card(2).Balance))
+	assert.Equal(t, money.New(10000, "USD"), l.creditBalance(customerID))
+
+	third, err := ApplyOrderTender(context.Background(), l, order, "USD", nil, true)
+	require.NoError(t, err)
+	assert.Equal(t, second, third)
+}
+
+func TestReleaseOrderTender(t *testing.T) {
+	customerID := uuid.New()
+	l := newLedger(t, customerID, 1000)
+	order := db.Order{
+		OrderID:        7,
+		Amount:         money.New(5000, "USD").Numeric(),
+		ShopCustomerID: pgtype.UUID{Bytes: customerID, Valid: true},
+		ShopID:         1,
+	}
+
+	applied, err := ApplyOrderTender(context.Background(), l, order, "USD", []string{"EEEEFFFFGGGGHHHH"}, true)
+	require.NoError(t, err)
+	assert.Equal(t, OrderTender{GiftCards: money.New(4000, "USD"), StoreCredit: money.New(1000, "USD")}, applied)
+	assert.Equal(t, money.Zero("USD"), l.creditBalance(customerID))
+
+	released, err := ReleaseOrderTender(context.Background(), l, 1, order.OrderID, "USD")
+	require.NoError(t, err)
+	assert.Equal(t, applied, released)
+	assert.Equal(t, money.New(4000, "USD"), usd(l.card(2).Balance))
+	assert.Equal(t, money.New(1000, "USD"), l.creditBalance(customerID))
+
+	remaining, err := AppliedOrderTender(context.Background(), l, 1, order.OrderID, "USD")
+	require.NoError(t, err)
+	assert.True(t, remaining.Total().IsZero())
+
+	// Releasing twice gives nothing back the second time
+	again, err := ReleaseOrderTender(context.Background(), l, 1, order.OrderID, "USD")
+	require.NoError(t, err)
+	assert.True(t, again.Total().IsZero())
+	assert.Equal(t, money.New(4000, "USD"), usd(l.card(2).Balance))
+	assert.Equal(t, money.New(1000, "USD"), l.creditBalance(customerID))
+}
+
+func TestAdjustStoreCredit(t *testing.T) {
+	customerID := uuid.New()
+	l := newLedger(t, customerID, 1000)
+
+	entry, err := AdjustStoreCredit(context.Background(), l, 1, customerID, db.CreditTransactionKindAdjust, money.New(-400, "USD"), nil, nil, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, money.New(600, "USD"), usd(entry.BalanceAfter))
+
+	_, err = AdjustStoreCredit(context.Background(), l, 1, customerID, db.CreditTransactionKindAdjust, money.New(-601, "USD"), nil, nil, nil, nil)
+	assert.ErrorIs(t, err, ErrInsufficientStoreCredit)
+	assert.Equal(t, money.New(600, "USD"), l.creditBalance(customerID))
+}
+
+func TestAdjustGiftCard(t *testing.T) {
+	l := newLedger(t, uuid.New(), 0)
+
+	card, err := AdjustGiftCard(context.Background(), l, 1, 1, db.CreditTransactionKindAdjust, money.New(-1000, "USD"), nil, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, money.New(2000, "USD"), usd(card.Balance))
+	require.Len(t, l.cardTxns, 1)
+	assert.Equal(t, card.Balance, l.cardTxns[0].BalanceAfter)
+
+	_, err = AdjustGiftCard(context.Background(), l, 1, 1, db.CreditTransactionKindAdjust, money.New(-2001, "USD"), nil, nil, nil)
+	assert.ErrorIs(t, err, ErrInsufficientGiftCard)
+
+	_, err = AdjustGiftCard(context.Background(), l, 1, 99, db.CreditTransactionKindAdjust, money.New(100, "USD"), nil, nil, nil)
+	assert.ErrorIs(t, err, ErrGiftCardNotFound)
+}
+
+func TestIssueOrderGiftCards(t *testing.T) {
+	email := "ada@example.com"
+	l := &fakeLedger{giftCardItems: []db.ListOrderGiftCardItemsRow{
+		{OrderItemID: 1, Quantity: 2, Price: money.New(2500, "USD").Numeric()},
+		{OrderItemID: 2, Quantity: 1, Price: money.New(10000, "USD").Numeric()},
+	}}
+	order := db.Order{OrderID: 7, CustomerEmail: &email, ShopID: 1}
+
+	cards, err := IssueOrderGiftCards(context.Background(), l, order, "USD")
+	require.NoError(t, err)
+	require.Len(t, cards, 3)
+	for i, want := range []int64{2500, 2500, 10000} {
+		assert.Equal(t, money.New(want, "USD"), usd(cards[i].Balance))
+		assert.Equal(t, &email, cards[i].RecipientEmail)
+		assert.Len(t, cards[i].Code, giftCardCodeLength)
+	}
+	assert.NotEqual(t, cards[0].Code, cards[1].Code)
+	assert.Equal(t, []int64{0, 1}, []int64{*cards[0].OrderItemUnit, *cards[1].OrderItemUnit})
+	assert.Len(t, l.cardTxns, 3)
+	assert.Equal(t, 3, l.notifications)
+
+	// Confirming the payment again issues nothing new
+	again, err := IssueOrderGiftCards(context.Background(), l, order, "USD")
+	require.NoError(t, err)
+	assert.Empty(t, again)
+	assert.Len(t, l.cards, 3)
+}
+
+func TestNormalizeGiftCardCode(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"ABCD-EFGH-JKLM-NPQR", "ABCDEFGHJKLMNPQR"},
+		{" abcd efgh jklm npqr ", "ABCDEFGHJKLMNPQR"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			assert.Equal(t, tt.want, NormalizeGiftCardCode(tt.input))
+		})
+	}
+}
+
+func TestGenerateGiftCardCode(t *testing.T) {
+	code, err := GenerateGiftCardCode()
+	require.NoError(t, err)
+	assert.Len(t, code, giftCardCodeLength)
+	assert.Equal(t, code, NormalizeGiftCardCode(code))
+	for _, r := range code {
+		assert.Contains(t, giftCardCodeAlphabet, string(r))
+	}
+}
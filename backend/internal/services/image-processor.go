@@ -0,0 +1,411 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/HugoSmits86/nativewebp"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+var (
+	ErrImageTooLarge      = errors.New("image exceeds the maximum upload size")
+	ErrImageTooManyPixels = errors.New("image dimensions exceed the maximum allowed")
+	ErrUnsupportedImage   = errors.New("unsupported image format, expected JPEG, PNG or WebP")
+)
+
+// ImageProcessorConfig controls upload limits and the renditions generated for each image
+type ImageProcessorConfig struct {
+	Widths    []int
+	Formats   []string
+	MaxBytes  int64
+	MaxPixels int
+}
+
+// ImageProcessorConfigFromEnv reads IMAGE_RENDITION_WIDTHS, IMAGE_RENDITION_FORMATS,
+// IMAGE_MAX_UPLOAD_BYTES and IMAGE_MAX_PIXELS, falling back to sensible defaults
+func ImageProcessorConfigFromEnv() ImageProcessorConfig {
+	cfg := ImageProcessorConfig{
+		Widths:    []int{320, 640, 1024, 1600},
+		Formats:   []string{"webp", "avif"},
+		MaxBytes:  10 << 20,
+		MaxPixels: 25_000_000,
+	}
+	if v := os.Getenv("IMAGE_RENDITION_WIDTHS"); v != "" {
+		var widths []int
+		for _, part := range strings.Split(v, ",") {
+			if w, err := strconv.Atoi(strings.TrimSpace(part)); err == nil && w > 0 {
+				widths = append(widths, w)
+			}
+		}
+		cfg.Widths = widths
+	}
+	if v := os.Getenv("IMAGE_RENDITION_FORMATS"); v != "" {
+		var formats []string
+		for _, part := range strings.Split(v, ",") {
+			if f := strings.ToLower(strings.TrimSpace(part)); f == "webp" || f == "avif" {
+				formats = append(formats, f)
+			}
+		}
+		cfg.Formats = formats
+	}
+	if v, err := strconv.ParseInt(os.Getenv("IMAGE_MAX_UPLOAD_BYTES"), 10, 64); err == nil && v > 0 {
+		cfg.MaxBytes = v
+	}
+	if v, err := strconv.Atoi(os.Getenv("IMAGE_MAX_PIXELS")); err == nil && v > 0 {
+		cfg.MaxPixels = v
+	}
+	return cfg
+}
+
+// ImageRendition is a resized and re-encoded copy of an uploaded image
+type ImageRendition struct {
+	Format    string `json:"format"`
+	Width     int    `json:"width"`
+	Height    int    `json:"height"`
+	URL       string `json:"url"`
+	Key       string `json:"key"`
+	SizeBytes int64  `json:"size_bytes"`
+}
+
+// UploadedImage describes a stored original together with its renditions
+type UploadedImage struct {
+	Key         string
+	URL         string
+	ContentType string
+	Width       int
+	Height      int
+	SizeBytes   int64
+	Renditions  []ImageRendition
+}
+
+// ImageUploadService validates uploaded images, strips their metadata and stores
+// the original plus responsive renditions in object storage
+type ImageUploadService struct {
+	Storage ObjectStorage
+	Config  ImageProcessorConfig
+}
+
+func NewImageUploadService(storage ObjectStorage, cfg ImageProcessorConfig) *ImageUploadService {
+	return &ImageUploadService{
+		Storage: storage,
+		Config:  cfg,
+	}
+}
+
+// UploadBodyLimit is the largest request body an image upload is accepted with,
+// leaving room for multipart overhead on top of the largest accepted image
+func (s *ImageUploadService) UploadBodyLimit() int64 {
+	return s.Config.MaxBytes + 1<<20
+}
+
+// Upload stores data under prefix/{uuid}/. The original is re-encoded, which drops
+// EXIF and any other embedded metadata and applies the EXIF orientation to the pixels.
+func (s *ImageUploadService) Upload(ctx context.Context, prefix string, data []byte) (*UploadedImage, error) {
+	if int64(len(data)) > s.Config.MaxBytes {
+		return nil, ErrImageTooLarge
+	}
+
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedImage
+	}
+	if format != "jpeg" && format != "png" && format != "webp" {
+		return nil, ErrUnsupportedImage
+	}
+	if cfg.Width*cfg.Height > s.Config.MaxPixels {
+		return nil, ErrImageTooManyPixels
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedImage
+	}
+	if format == "jpeg" {
+		img = applyOrientation(img, jpegOrientation(data))
+	}
+
+	original, contentType, ext, err := encodeOriginal(img, format)
+	if err != nil {
+		return nil, fmt.Errorf("encode original: %w", err)
+	}
+
+	dir := strings.TrimRight(prefix, "/") + "/" + uuid.NewString()
+	uploaded := &UploadedImage{
+		Key:         dir + "/original." + ext,
+		ContentType: contentType,
+		Width:       img.Bounds().Dx(),
+		Height:      img.Bounds().Dy(),
+		SizeBytes:   int64(len(original)),
+		Renditions:  []ImageRendition{},
+	}
+
+	uploaded.URL, err = s.Storage.Put(ctx, uploaded.Key, original, contentType)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, width := range s.renditionWidths(uploaded.Width) {
+		resized := resizeImage(img, width)
+		for _, f := range s.Config.Formats {
+			encoded, err := encodeRendition(ctx, resized, f)
+			if errors.Is(err, errEncoderUnavailable) {
+				continue
+			}
+			if err != nil {
+				s.Remove(context.Background(), uploaded)
+				return nil, fmt.Errorf("encode %s rendition: %w", f, err)
+			}
+
+			key := fmt.Sprintf("%s/%dw.%s", dir, width, f)
+			url, err := s.Storage.Put(ctx, key, encoded, "image/"+f)
+			if err != nil {
+				s.Remove(context.Background(), uploaded)
+				return nil, err
+			}
+
+			uploaded.Renditions = append(uploaded.Renditions, ImageRendition{
+				Format:    f,
+				Width:     resized.Bounds().Dx(),
+				Height:    resized.Bounds().Dy(),
+				URL:       url,
+				Key:       key,
+				SizeBytes: int64(len(encoded)),
+			})
+		}
+	}
+
+	return uploaded, nil
+}
+
+// Remove deletes the original and all renditions of an uploaded image. Failures are
+// logged rather than returned since this is only used to clean up after another error.
+func (s *ImageUploadService) Remove(ctx context.Context, uploaded *UploadedImage) {
+	keys := []string{uploaded.Key}
+	for _, r := range uploaded.Renditions {
+		keys = append(keys, r.Key)
+	}
+	for _, key := range keys {
+		if err := s.Storage.Delete(ctx, key); err != nil {
+			zap.L().Warn("failed to remove uploaded image", zap.String("key", key), zap.Error(err))
+		}
+	}
+}
+
+// renditionWidths returns the configured widths that do not upscale the original.
+// Images narrower than every configured width get a single rendition at their own width.
+func (s *ImageUploadService) renditionWidths(originalWidth int) []int {
+	var widths []int
+	for _, w := range s.Config.Widths {
+		if w <= originalWidth {
+			widths = append(widths, w)
+		}
+	}
+	if len(widths) == 0 && len(s.Config.Widths) > 0 {
+		widths = append(widths, originalWidth)
+	}
+	return widths
+}
+
+func encodeOriginal(img image.Image, format string) ([]byte, string, string, error) {
+	var buf bytes.Buffer
+	switch format {
+	case "jpeg":
+		if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 92}); err != nil {
+			return nil, "", "", err
+		}
+		return buf.Bytes(), "image/jpeg", "jpg", nil
+	case "png":
+		if err := png.Encode(&buf, img); err != nil {
+			return nil, "", "", err
+		}
+		return buf.Bytes(), "image/png", "png", nil
+	default:
+		if err := nativewebp.Encode(&buf, img, nil); err != nil {
+			return nil, "", "", err
+		}
+		return buf.Bytes(), "image/webp", "webp", nil
+	}
+}
+
+func resizeImage(img image.Image, width int) image.Image {
+	bounds := img.Bounds()
+	if width >= bounds.Dx() {
+		return img
+	}
+	height := bounds.Dy() * width / bounds.Dx()
+	if height < 1 {
+		height = 1
+	}
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, bounds, draw.Src, nil)
+	return dst
+}
+
+var errEncoderUnavailable = errors.New("encoder unavailable")
+
+// encodeRendition prefers the libwebp/libavif command line encoders when they are
+// installed. WebP falls back to a pure Go lossless encoder; AVIF renditions are
+// skipped when avifenc is missing.
+func encodeRendition(ctx context.Context, img image.Image, format string) ([]byte, error) {
+	switch format {
+	case "webp":
+		if _, err := exec.LookPath("cwebp"); err == nil {
+			return encodeWithTool(ctx, img, "webp", func(in, out string) []string {
+				return []string{"cwebp", "-quiet", "-q", "80", "-metadata", "none", in, "-o", out}
+			})
+		}
+		var buf bytes.Buffer
+		if err := nativewebp.Encode(&buf, img, nil); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	case "avif":
+		if _, err := exec.LookPath("avifenc"); err != nil {
+			zap.L().Warn("avifenc not found, skipping AVIF rendition")
+			return nil, errEncoderUnavailable
+		}
+		return encodeWithTool(ctx, img, "avif", func(in, out string) []string {
+			return []string{"avifenc", "-q", "60", "-s", "6", in, out}
+		})
+	default:
+		return nil, fmt.Errorf("unknown rendition format %q", format)
+	}
+}
+
+func encodeWithTool(ctx context.Context, img image.Image, ext string, command func(in, out string) []string) ([]byte, error) {
+	dir, err := os.MkdirTemp("", "image-rendition-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	in := filepath.Join(dir, "in.png")
+	out := filepath.Join(dir, "out."+ext)
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(in, buf.Bytes(), 0o600); err != nil {
+		return nil, err
+	}
+
+	args := command(in, out)
+	if output, err := exec.CommandContext(ctx, args[0], args[1:]...).CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("%s: %w: %s", args[0], err, strings.TrimSpace(string(output)))
+	}
+	return os.ReadFile(out)
+}
+
+// jpegOrientation returns the EXIF orientation tag of a JPEG, or 1 when absent
+func jpegOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+	for i := 2; i+4 <= len(data); {
+		if data[i] != 0xFF {
+			return 1
+		}
+		marker := data[i+1]
+		if marker == 0xDA || marker == 0xD9 {
+			return 1
+		}
+		length := int(binary.BigEndian.Uint16(data[i+2:]))
+		end := i + 2 + length
+		if length < 2 || end > len(data) {
+			return 1
+		}
+		segment := data[i+4 : end]
+		if marker == 0xE1 && len(segment) > 6 && string(segment[:6]) == "Exif\x00\x00" {
+			return exifOrientation(segment[6:])
+		}
+		i = end
+	}
+	return 1
+}
+
+func exifOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+	offset := int(order.Uint32(tiff[4:]))
+	if offset+2 > len(tiff) {
+		return 1
+	}
+	count := int(order.Uint16(tiff[offset:]))
+	for n := 0; n < count; n++ {
+		entry := offset + 2 + n*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+		if order.Uint16(tiff[entry:]) == 0x0112 {
+			v := int(order.Uint16(tiff[entry+8:]))
+			if v >= 1 && v <= 8 {
+				return v
+			}
+			return 1
+		}
+	}
+	return 1
+}
+
+// applyOrientation rotates and flips img so it displays upright for the given EXIF orientation
+func applyOrientation(img image.Image, orientation int) image.Image {
+	if orientation <= 1 || orientation > 8 {
+		return img
+	}
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	dw, dh := w, h
+	if orientation >= 5 {
+		dw, dh = h, w
+	}
+
+	dst := image.NewNRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < dh; y++ {
+		for x := 0; x < dw; x++ {
+			var sx, sy int
+			switch orientation {
+			case 2:
+				sx, sy = w-1-x, y
+			case 3:
+				sx, sy = w-1-x, h-1-y
+			case 4:
+				sx, sy = x, h-1-y
+			case 5:
+				sx, sy = y, x
+			case 6:
+				sx, sy = y, h-1-x
+			case 7:
+				sx, sy = w-1-y, h-1-x
+			case 8:
+				sx, sy = w-1-y, x
+			}
+			dst.Set(x, y, img.At(b.Min.X+sx, b.Min.Y+sy))
+		}
+	}
+	return dst
+}
@@ -0,0 +1,59 @@
+package services
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// exifJPEG builds the start of a JPEG whose APP1 segment holds a TIFF header in
+// the given byte order with a single IFD entry for tag
+func exifJPEG(order binary.ByteOrder, tag, value uint16) []byte {
+	tiff := make([]byte, 8+2+12)
+	if order == binary.LittleEndian {
+		copy(tiff, "II")
+	} else {
+		copy(tiff, "MM")
+	}
+	order.PutUint16(tiff[2:], 42)
+	order.PutUint32(tiff[4:], 8)
+	order.PutUint16(tiff[8:], 1)
+	order.PutUint16(tiff[10:], tag)
+	order.PutUint16(tiff[12:], 3) // SHORT
+	order.PutUint32(tiff[14:], 1)
+	order.PutUint16(tiff[18:], value)
+
+	segment := append([]byte("Exif\x00\x00"), tiff...)
+	data := []byte{0xFF, 0xD8, 0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(data[4:], uint16(len(segment)+2))
+	data = append(data, segment...)
+	return append(data, 0xFF, 0xDA, 0, 2)
+}
+
+func TestJPEGOrientation(t *testing.T) {
+	app0 := []byte{0xFF, 0xD8, 0xFF, 0xE0, 0, 4, 'J', 'F'}
+	truncated := exifJPEG(binary.BigEndian, 0x0112, 6)
+
+	tests := []struct {
+		name string
+		data []byte
+		want int
+	}{
+		{"not a jpeg", []byte("\x89PNG\r\n\x1a\n"), 1},
+		{"too short", []byte{0xFF, 0xD8}, 1},
+		{"no exif segment", append(app0, 0xFF, 0xDA, 0, 2), 1},
+		{"big endian rotated", exifJPEG(binary.BigEndian, 0x0112, 6), 6},
+		{"little endian mirrored", exifJPEG(binary.LittleEndian, 0x0112, 2), 2},
+		{"upright", exifJPEG(binary.LittleEndian, 0x0112, 1), 1},
+		{"out of range value", exifJPEG(binary.BigEndian, 0x0112, 9), 1},
+		{"other tag only", exifJPEG(binary.BigEndian, 0x010F, 6), 1},
+		{"truncated segment", truncated[:len(truncated)-10], 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, jpegOrientation(tt.data))
+		})
+	}
+}
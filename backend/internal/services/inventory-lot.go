@@ -0,0 +1,157 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/petrejonn/naytife/internal/db"
+)
+
+var (
+	ErrLotRequired          = errors.New("stock of the variant is received into lots")
+	ErrLotsNotTracked       = errors.New("stock of the variant is not received into lots")
+	ErrInsufficientLotStock = errors.New("not enough stock in the lot")
+)
+
+// StockLot is the lot a stock change receives stock into
+type StockLot struct {
+	Number    string
+	ExpiresOn *time.Time
+}
+
+// CheckStockLot reports whether stock of a variant received with the given lot
+// suits its product type: types that track lots need one, other types take none
+func CheckStockLot(ctx context.Context, q inventoryQueries, shopID, variantID int64, lot *StockLot) error {
+	tracksLots, err := q.GetVariantTracksLots(ctx, db.GetVariantTracksLotsParams{
+		ProductVariationID: variantID,
+		ShopID:             shopID,
+	})
+	if err != nil {
+		return err
+	}
+	switch {
+	case tracksLots && lot == nil:
+		return ErrLotRequired
+	case !tracksLots && lot != nil:
+		return ErrLotsNotTracked
+	}
+	return nil
+}
+
+// WriteOffLot takes a quantity of a lot out of stock as a write-off movement that
+// references the lot
+func WriteOffLot(ctx context.Context, q inventoryQueries, lot db.InventoryLot, quantity int64, notes *string) (StockChangeResult, error) {
+	return ChangeStock(ctx, q, StockChange{
+		ShopID:       lot.ShopID,
+		VariantID:    lot.ProductVariationID,
+		LocationID:   lot.LocationID,
+		Operation:    StockDeduct,
+		Quantity:     quantity,
+		MovementType: "write_off",
+		ReferenceID:  &lot.LotID,
+		Notes:        notes,
+		LotID:        &lot.LotID,
+	})
+}
+
+// WriteOffExpiredLots writes off the stock left in every lot of a shop that has
+// expired, only at the given location when one is given
+func WriteOffExpiredLots(ctx context.Context, q inventoryQueries, shopID int64, locationID *int64, notes *string) ([]StockChangeResult, error) {
+	lots, err := q.ListExpiredLots(ctx, db.ListExpiredLotsParams{ShopID: shopID, LocationID: locationID})
+	if err != nil {
+		return nil, err
+	}
+	results := make([]StockChangeResult, 0, len(lots))
+	for _, lot := range lots {
+		result, err := WriteOffLot(ctx, q, lot, lot.Quantity, notes)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// changeLots keeps the lots of a variant at a location in step with a change to
+// its stock there. Stock added with a lot is received into it. Stock taken without
+// a lot comes from the lots that have not expired, earliest expiry first, then
+// from the stock held outside lots, and only then from expired lots, so that the
+// lots never hold more than the location.
+func changeLots(ctx context.Context, q inventoryQueries, change StockChange, before, after int64) error {
+	if after > before {
+		if change.Lot == nil {
+			return nil
+		}
+		expiresOn := pgtype.Date{}
+		if change.Lot.ExpiresOn != nil {
+			expiresOn = pgtype.Date{Time: *change.Lot.ExpiresOn, Valid: true}
+		}
+		_, err := q.ReceiveInventoryLot(ctx, db.ReceiveInventoryLotParams{
+			ProductVariationID: change.VariantID,
+			LocationID:         change.LocationID,
+			LotNumber:          change.Lot.Number,
+			ExpiresOn:          expiresOn,
+			Quantity:           after - before,
+			ShopID:             change.ShopID,
+		})
+		return err
+	}
+
+	remaining := before - after
+	if remaining == 0 {
+		return nil
+	}
+	if change.LotID != nil {
+		return deductLot(ctx, q, change, *change.LotID, remaining)
+	}
+	lots, err := q.ListAllocatableLots(ctx, db.ListAllocatableLotsParams{
+		ProductVariationID: change.VariantID,
+		LocationID:         change.LocationID,
+		ShopID:             change.ShopID,
+	})
+	if err != nil || len(lots) == 0 {
+		return err
+	}
+
+	unlotted := before
+	for _, lot := range lots {
+		unlotted -= lot.Quantity
+	}
+	for _, expired := range []bool{false, true} {
+		if expired {
+			remaining -= min(remaining, max(unlotted, 0))
+		}
+		for _, lot := range lots {
+			if remaining == 0 {
+				return nil
+			}
+			if lot.Expired != expired {
+				continue
+			}
+			quantity := min(remaining, lot.Quantity)
+			if err := deductLot(ctx, q, change, lot.LotID, quantity); err != nil {
+				return err
+			}
+			remaining -= quantity
+		}
+	}
+	return nil
+}
+
+// deductLot takes a quantity of the stock a change takes from a lot
+func deductLot(ctx context.Context, q inventoryQueries, change StockChange, lotID, quantity int64) error {
+	_, err := q.DeductInventoryLot(ctx, db.DeductInventoryLotParams{
+		LotID:              lotID,
+		ProductVariationID: change.VariantID,
+		LocationID:         change.LocationID,
+		ShopID:             change.ShopID,
+		Quantity:           quantity,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrInsufficientLotStock
+	}
+	return err
+}
@@ -0,0 +1,150 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/petrejonn/naytife/internal/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func expiresIn(days int) *time.Time {
+	date := time.Now().Truncate(24*time.Hour).AddDate(0, 0, days)
+	return &date
+}
+
+func TestCheckStockLot(t *testing.T) {
+	lot := &StockLot{Number: "L-1"}
+
+	tests := []struct {
+		name       string
+		tracksLots bool
+		lot        *StockLot
+		wantErr    error
+	}{
+		{"lot of a tracked variant", true, lot, nil},
+		{"untracked variant without a lot", false, nil, nil},
+		{"tracked variant without a lot", true, nil, ErrLotRequired},
+		{"lot of an untracked variant", false, lot, ErrLotsNotTracked},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFakeStock(warehouse)
+			f.tracksLots = tt.tracksLots
+			err := CheckStockLot(context.Background(), f, 1, 20, tt.lot)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestChangeStockLots(t *testing.T) {
+	ctx := context.Background()
+	// receive adds stock of variant 20 at the warehouse into the given lots
+	receive := func(t *testing.T, f *fakeStock, lots ...StockLot) {
+		for _, lot := range lots {
+			_, err := ChangeStock(ctx, f, StockChange{
+				ShopID: 1, VariantID: 20, LocationID: warehouse.LocationID,
+				Operation: StockAdd, Quantity: 5, MovementType: "purchase", Lot: &lot,
+			})
+			require.NoError(t, err)
+		}
+	}
+	take := func(f *fakeStock, quantity int64) error {
+		_, err := ChangeStock(ctx, f, StockChange{
+			ShopID: 1, VariantID: 20, LocationID: warehouse.LocationID,
+			Operation: StockDeduct, Quantity: quantity, MovementType: "sale",
+		})
+		return err
+	}
+	quantities := func(f *fakeStock) map[string]int64 {
+		lots := map[string]int64{}
+		for _, lot := range f.lots {
+			lots[lot.LotNumber] = lot.Quantity
+		}
+		return lots
+	}
+
+	tests := []struct {
+		name     string
+		unlotted int64
+		lots     []StockLot
+		take     int64
+		want     map[string]int64
+	}{
+		{
+			name: "earliest expiry first",
+			lots: []StockLot{{"LATE", expiresIn(30)}, {"SOON", expiresIn(3)}, {"NEVER", nil}},
+			take: 7,
+			want: map[string]int64{"LATE": 3, "SOON": 0, "NEVER": 5},
+		},
+		{
+			name: "lots without an expiry date last",
+			lots: []StockLot{{"NEVER", nil}, {"LATE", expiresIn(30)}},
+			take: 6,
+			want: map[string]int64{"LATE": 0, "NEVER": 4},
+		},
+		{
+			name:     "stock outside lots before expired lots",
+			unlotted: 2,
+			lots:     []StockLot{{"EXPIRED", expiresIn(-1)}, {"SOON", expiresIn(3)}},
+			take:     8,
+			want:     map[string]int64{"EXPIRED": 4, "SOON": 0},
+		},
+		{
+			name: "expired lots only once nothing else is left",
+			lots: []StockLot{{"EXPIRED", expiresIn(-1)}, {"SOON", expiresIn(3)}},
+			take: 8,
+			want: map[string]int64{"EXPIRED": 2, "SOON": 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFakeStock(warehouse)
+			f.setStock(20, warehouse.LocationID, tt.unlotted)
+			receive(t, f, tt.lots...)
+
+			require.NoError(t, take(f, tt.take))
+			assert.Equal(t, tt.want, quantities(f))
+		})
+	}
+
+	t.Run("lot received again", func(t *testing.T) {
+		f := newFakeStock(warehouse)
+		receive(t, f, StockLot{"L-1", expiresIn(10)}, StockLot{"L-1", nil})
+		require.Len(t, f.lots, 1)
+		assert.Equal(t, int64(10), f.lots[0].Quantity)
+		assert.True(t, f.lots[0].ExpiresOn.Valid)
+	})
+}
+
+func TestWriteOffExpiredLots(t *testing.T) {
+	f := newFakeStock(warehouse, shopFloor)
+	f.setStock(20, warehouse.LocationID, 9)
+	f.setStock(20, shopFloor.LocationID, 4)
+	f.lots = []db.InventoryLot{
+		{LotID: 1, ProductVariationID: 20, LocationID: warehouse.LocationID, LotNumber: "OLD", ExpiresOn: pgtype.Date{Time: *expiresIn(-2), Valid: true}, Quantity: 3, ShopID: 1},
+		{LotID: 2, ProductVariationID: 20, LocationID: warehouse.LocationID, LotNumber: "NEW", ExpiresOn: pgtype.Date{Time: *expiresIn(20), Valid: true}, Quantity: 6, ShopID: 1},
+		{LotID: 3, ProductVariationID: 20, LocationID: shopFloor.LocationID, LotNumber: "OLD", ExpiresOn: pgtype.Date{Time: *expiresIn(-2), Valid: true}, Quantity: 4, ShopID: 1},
+	}
+	locationID := warehouse.LocationID
+
+	results, err := WriteOffExpiredLots(context.Background(), f, 1, &locationID, nil)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+
+	// Only the expired lot at the warehouse is written off
+	assert.Equal(t, int64(6), f.stock(20, warehouse.LocationID).Available)
+	assert.Equal(t, []int64{0, 6, 4}, []int64{f.lots[0].Quantity, f.lots[1].Quantity, f.lots[2].Quantity})
+	require.Len(t, f.movements, 1)
+	assert.Equal(t, "write_off", f.movements[0].MovementType)
+	assert.Equal(t, int64(1), *f.movements[0].ReferenceID)
+}
@@ -0,0 +1,182 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/petrejonn/naytife/internal/db"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrOutOfStock           = errors.New("not enough stock of the variant for the order")
+	ErrPreorderLimitReached = errors.New("the variant has taken all the preorders it allows")
+)
+
+// PendingOrderTTL is how long an unpaid pending order holds its stock before it
+// is cancelled
+const PendingOrderTTL = 24 * time.Hour
+
+// PendingOrderExpiryInterval is how often expired pending orders are cancelled
+const PendingOrderExpiryInterval = 15 * time.Minute
+
+// pendingOrderExpiryBatch is how many expired orders are cancelled at a time
+const pendingOrderExpiryBatch = 100
+
+// orderLineQueries are the queries placing the lines of an order runs, within the
+// caller's transaction. *db.Queries implements them.
+type orderLineQueries interface {
+	GetProductVariationForUpdate(ctx context.Context, arg db.GetProductVariationForUpdateParams) (db.ProductVariation, error)
+	GetVariantCommittedQuantity(ctx context.Context, arg db.GetVariantCommittedQuantityParams) (int64, error)
+	GetVariantBackorderedQuantity(ctx context.Context, arg db.GetVariantBackorderedQuantityParams) (int64, error)
+}
+
+// pendingOrderQueries are the queries cancelling expired pending orders runs, all
+// within one transaction. *db.Queries implements them.
+type pendingOrderQueries interface {
+	ledgerQueries
+	loyaltyQueries
+	ClaimExpiredPendingOrders(ctx context.Context, arg db.ClaimExpiredPendingOrdersParams) ([]db.ClaimExpiredPendingOrdersRow, error)
+	CancelUnpaidOrder(ctx context.Context, arg db.CancelUnpaidOrderParams) (int64, error)
+}
+
+// LockOrderVariants locks the variants of an order until the transaction ends, in
+// ascending ID order, so that orders sharing variants wait for one another rather
+// than deadlock. Orders of more than one line take the locks before working out
+// the backorder of any line.
+func LockOrderVariants(ctx context.Context, q orderLineQueries, shopID int64, variantIDs []int64) error {
+	ids := slices.Clone(variantIDs)
+	slices.Sort(ids)
+	for _, id := range slices.Compact(ids) {
+		if _, err := q.GetProductVariationForUpdate(ctx, db.GetProductVariationForUpdateParams{
+			ProductVariationID: id,
+			ShopID:             shopID,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// OrderLineBackorder returns how many units of an order line are ordered beyond the
+// stock of its variant. Stock already promised to orders that are waiting to be
+// fulfilled does not count, and the variant stays locked until the transaction
+// ends so that concurrent orders cannot promise the same stock; orders of more
+// than one line lock their variants with LockOrderVariants first. Variants that
+// deny orders once out of stock fail with ErrOutOfStock, and preorders beyond the
+// preorder limit of a variant fail with ErrPreorderLimitReached.
+func OrderLineBackorder(ctx context.Context, q orderLineQueries, shopID, variantID, quantity int64) (int64, error) {
+	variant, err := q.GetProductVariationForUpdate(ctx, db.GetProductVariationForUpdateParams{
+		ProductVariationID: variantID,
+		ShopID:             shopID,
+	})
+	if err != nil {
+		return 0, err
+	}
+	committed, err := q.GetVariantCommittedQuantity(ctx, db.GetVariantCommittedQuantityParams{
+		ProductVariationID: variantID,
+		ShopID:             shopID,
+	})
+	if err != nil {
+		return 0, err
+	}
+	backordered, err := q.GetVariantBackorderedQuantity(ctx, db.GetVariantBackorderedQuantityParams{
+		ProductVariationID: variantID,
+		ShopID:             shopID,
+	})
+	if err != nil {
+		return 0, err
+	}
+	return lineBackorder(variant, committed, backordered, quantity)
+}
+
+// lineBackorder works out the backorder of an order line for quantity units of
+// variant, given the units committed to open orders and how many of those are
+// already backordered.
+func lineBackorder(variant db.ProductVariation, committed, backordered, quantity int64) (int64, error) {
+	shortfall := max(quantity-max(variant.AvailableQuantity-committed, 0), 0)
+	if shortfall == 0 {
+		return 0, nil
+	}
+	switch variant.InventoryPolicy {
+	case db.InventoryPolicyBackorder:
+		return shortfall, nil
+	case db.InventoryPolicyPreorder:
+		if variant.PreorderLimit != nil && backordered+shortfall > *variant.PreorderLimit {
+			return 0, ErrPreorderLimitReached
+		}
+		return shortfall, nil
+	}
+	return 0, ErrOutOfStock
+}
+
+// RunPendingOrderExpiry cancels the pending orders left unpaid for longer than
+// PendingOrderTTL once every PendingOrderExpiryInterval, starting straight away,
+// until ctx is done, so that abandoned checkouts give their stock back.
+func RunPendingOrderExpiry(ctx context.Context, repo db.Repository) {
+	ticker := time.NewTicker(PendingOrderExpiryInterval)
+	defer ticker.Stop()
+	for {
+		expirePendingOrders(ctx, repo)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// expirePendingOrders cancels the expired pending orders a batch at a time, each
+// batch in its own transaction
+func expirePendingOrders(ctx context.Context, repo db.Repository) {
+	for ctx.Err() == nil {
+		var cancelled int
+		err := repo.WithTx(ctx, func(q *db.Queries) error {
+			var err error
+			cancelled, err = cancelExpiredOrders(ctx, q, time.Now().Add(-PendingOrderTTL), pendingOrderExpiryBatch)
+			return err
+		})
+		if err != nil {
+			zap.L().Error("RunPendingOrderExpiry: failed to cancel expired orders", zap.Error(err))
+			return
+		}
+		if cancelled > 0 {
+			zap.L().Info("RunPendingOrderExpiry: cancelled expired orders", zap.Int("count", cancelled))
+		}
+		if cancelled < pendingOrderExpiryBatch {
+			return
+		}
+	}
+}
+
+// cancelExpiredOrders cancels up to limit unpaid pending orders placed before
+// placedBefore and gives back what gift cards, store credit and loyalty points
+// paid towards them. It returns how many orders it cancelled.
+func cancelExpiredOrders(ctx context.Context, q pendingOrderQueries, placedBefore time.Time, limit int32) (int, error) {
+	orders, err := q.ClaimExpiredPendingOrders(ctx, db.ClaimExpiredPendingOrdersParams{
+		PlacedBefore: pgtype.Timestamptz{Time: placedBefore, Valid: true},
+		Limit:        limit,
+	})
+	if err != nil {
+		return 0, err
+	}
+	for _, order := range orders {
+		if _, err := q.CancelUnpaidOrder(ctx, db.CancelUnpaidOrderParams{
+			OrderID:       order.OrderID,
+			ShopID:        order.ShopID,
+			PaymentStatus: order.PaymentStatus,
+		}); err != nil {
+			return 0, err
+		}
+		if _, err := ReleaseOrderTender(ctx, q, order.ShopID, order.OrderID, order.CurrencyCode); err != nil {
+			return 0, err
+		}
+		if err := ReverseOrderLoyaltyPoints(ctx, q, order.ShopID, order.OrderID); err != nil {
+			return 0, err
+		}
+	}
+	return len(orders), nil
+}
@@ -0,0 +1,124 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/petrejonn/naytife/internal/db"
+	"github.com/petrejonn/naytife/internal/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLineBackorder(t *testing.T) {
+	limit := int64(10)
+	variant := func(policy db.InventoryPolicy, available int64, preorderLimit *int64) db.ProductVariation {
+		return db.ProductVariation{InventoryPolicy: policy, AvailableQuantity: available, PreorderLimit: preorderLimit}
+	}
+
+	tests := []struct {
+		name        string
+		variant     db.ProductVariation
+		committed   int64
+		backordered int64
+		quantity    int64
+		want        int64
+		wantErr     error
+	}{
+		{"in stock", variant(db.InventoryPolicyDeny, 5, nil), 0, 0, 5, 0, nil},
+		{"deny beyond stock", variant(db.InventoryPolicyDeny, 5, nil), 0, 0, 6, 0, ErrOutOfStock},
+		{"stock promised to open orders", variant(db.InventoryPolicyDeny, 5, nil), 3, 0, 3, 0, ErrOutOfStock},
+		{"rest of stock after open orders", variant(db.InventoryPolicyDeny, 5, nil), 3, 0, 2, 0, nil},
+		{"backorder beyond open orders", variant(db.InventoryPolicyBackorder, 5, nil), 3, 0, 4, 2, nil},
+		{"backorder with stock all promised", variant(db.InventoryPolicyBackorder, 5, nil), 8, 3, 2, 2, nil},
+		{"backorder without stock", variant(db.InventoryPolicyBackorder, 0, nil), 0, 0, 3, 3, nil},
+		{"negative stock", variant(db.InventoryPolicyBackorder, -2, nil), 0, 0, 1, 1, nil},
+		{"preorder without limit", variant(db.InventoryPolicyPreorder, 0, nil), 40, 40, 5, 5, nil},
+		{"preorder up to limit", variant(db.InventoryPolicyPreorder, 0, &limit), 6, 6, 4, 4, nil},
+		{"preorder over limit", variant(db.InventoryPolicyPreorder, 0, &limit), 6, 6, 5, 0, ErrPreorderLimitReached},
+		{"preorder from stock ignores limit", variant(db.InventoryPolicyPreorder, 20, &limit), 5, 0, 15, 0, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := lineBackorder(tt.variant, tt.committed, tt.backordered, tt.quantity)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+// fakeVariantLocks records the order variants are locked in
+type fakeVariantLocks struct {
+	orderLineQueries
+	locked []int64
+}
+
+func (f *fakeVariantLocks) GetProductVariationForUpdate(ctx context.Context, arg db.GetProductVariationForUpdateParams) (db.ProductVariation, error) {
+	f.locked = append(f.locked, arg.ProductVariationID)
+	return db.ProductVariation{ProductVariationID: arg.ProductVariationID, ShopID: arg.ShopID}, nil
+}
+
+func TestLockOrderVariants(t *testing.T) {
+	f := &fakeVariantLocks{}
+	variantIDs := []int64{30, 10, 20, 10}
+
+	require.NoError(t, LockOrderVariants(context.Background(), f, 1, variantIDs))
+	assert.Equal(t, []int64{10, 20, 30}, f.locked)
+	assert.Equal(t, []int64{30, 10, 20, 10}, variantIDs)
+}
+
+// fakePendingOrders holds unpaid pending orders with their gift card and points
+// ledgers
+type fakePendingOrders struct {
+	*fakeLedger
+	*fakePoints
+	orders    []db.ClaimExpiredPendingOrdersRow
+	cancelled []int64
+}
+
+func (f *fakePendingOrders) ClaimExpiredPendingOrders(ctx context.Context, arg db.ClaimExpiredPendingOrdersParams) ([]db.ClaimExpiredPendingOrdersRow, error) {
+	return f.orders[:min(len(f.orders), int(arg.Limit))], nil
+}
+
+func (f *fakePendingOrders) CancelUnpaidOrder(ctx context.Context, arg db.CancelUnpaidOrderParams) (int64, error) {
+	f.cancelled = append(f.cancelled, arg.OrderID)
+	return 1, nil
+}
+
+func TestCancelExpiredOrders(t *testing.T) {
+	ctx := context.Background()
+	customerID := uuid.New()
+	f := &fakePendingOrders{
+		fakeLedger: newLedger(t, customerID, 1000),
+		fakePoints: &fakePoints{},
+		orders: []db.ClaimExpiredPendingOrdersRow{
+			{OrderID: 7, PaymentStatus: db.PaymentStatusTypePending, ShopID: 1, CurrencyCode: "USD"},
+			{OrderID: 8, PaymentStatus: db.PaymentStatusTypeFailed, ShopID: 1, CurrencyCode: "USD"},
+		},
+	}
+	order := db.Order{
+		OrderID:        7,
+		Amount:         money.New(5000, "USD").Numeric(),
+		ShopCustomerID: pgtype.UUID{Bytes: customerID, Valid: true},
+		ShopID:         1,
+	}
+	_, err := ApplyOrderTender(ctx, f, order, "USD", []string{"AAAABBBBCCCCDDDD"}, true)
+	require.NoError(t, err)
+
+	cancelled, err := cancelExpiredOrders(ctx, f, time.Now().Add(-PendingOrderTTL), 100)
+	require.NoError(t, err)
+	assert.Equal(t, 2, cancelled)
+	assert.Equal(t, []int64{7, 8}, f.cancelled)
+
+	// The gift card and store credit the abandoned order took are given back
+	assert.Equal(t, money.New(3000, "USD"), usd(f.card(1).Balance))
+	assert.Equal(t, money.New(1000, "USD"), f.creditBalance(customerID))
+}
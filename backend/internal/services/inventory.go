@@ -0,0 +1,410 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/petrejonn/naytife/internal/db"
+	dberrors "github.com/petrejonn/naytife/internal/db/errors"
+)
+
+var (
+	ErrLocationNotFound      = errors.New("location not found")
+	ErrInsufficientStock     = errors.New("not enough stock at the location")
+	ErrNoFulfillmentLocation = errors.New("no location that sells online has enough stock for the order")
+	ErrOrderAlreadyFulfilled = errors.New("order has already been fulfilled")
+	ErrTransferNotInTransit  = errors.New("transfer is no longer in transit")
+)
+
+// StockOperation is how a stock change applies its quantity to a location
+type StockOperation int
+
+const (
+	StockSet StockOperation = iota
+	StockAdd
+	StockDeduct
+)
+
+// StockChange is a change to the stock of a variant at a location. Every change
+// is recorded as a stock movement.
+type StockChange struct {
+	ShopID       int64
+	VariantID    int64
+	LocationID   int64
+	Operation    StockOperation
+	Quantity     int64
+	MovementType string
+	ReferenceID  *int64
+	Notes        *string
+	// Lot is the lot stock added by the change is received into
+	Lot *StockLot
+	// LotID is the lot stock taken by the change comes from; without it stock is
+	// taken from the lots earliest expiry first
+	LotID *int64
+}
+
+// StockChangeResult is the stock of a variant after a change: at the location, and
+// across the locations that sell online before and after it
+type StockChangeResult struct {
+	Level        db.InventoryLevel
+	Variant      db.ProductVariation
+	OnlineBefore int64
+}
+
+// TransferItem is a quantity of a variant moved by an inventory transfer
+type TransferItem struct {
+	VariantID int64
+	Quantity  int64
+}
+
+// inventoryQueries are the queries stock changes run, all within the caller's
+// transaction. *db.Queries implements them.
+type inventoryQueries interface {
+	EnsureDefaultLocation(ctx context.Context, shopID int64) error
+	GetDefaultLocation(ctx context.Context, shopID int64) (db.Location, error)
+	GetLocation(ctx context.Context, arg db.GetLocationParams) (db.Location, error)
+	GetProductVariation(ctx context.Context, arg db.GetProductVariationParams) (db.ProductVariation, error)
+	EnsureInventoryLevel(ctx context.Context, arg db.EnsureInventoryLevelParams) (db.InventoryLevel, error)
+	UpdateVariantStock(ctx context.Context, arg db.UpdateVariantStockParams) (db.InventoryLevel, error)
+	AddVariantStock(ctx context.Context, arg db.AddVariantStockParams) (db.InventoryLevel, error)
+	DeductVariantStock(ctx context.Context, arg db.DeductVariantStockParams) (db.InventoryLevel, error)
+	CreateStockMovement(ctx context.Context, arg db.CreateStockMovementParams) (db.StockMovement, error)
+	SyncVariantAvailableQuantity(ctx context.Context, arg db.SyncVariantAvailableQuantityParams) (db.ProductVariation, error)
+	ReconcileProductInventoryLevels(ctx context.Context, arg db.ReconcileProductInventoryLevelsParams) error
+	SyncProductAvailableQuantities(ctx context.Context, arg db.SyncProductAvailableQuantitiesParams) error
+	CreateInventoryTransfer(ctx context.Context, arg db.CreateInventoryTransferParams) (db.InventoryTransfer, error)
+	CreateInventoryTransferItem(ctx context.Context, arg db.CreateInventoryTransferItemParams) (db.InventoryTransferItem, error)
+	ListInventoryTransferItems(ctx context.Context, arg db.ListInventoryTransferItemsParams) ([]db.ListInventoryTransferItemsRow, error)
+	AddIncomingStock(ctx context.Context, arg db.AddIncomingStockParams) (db.InventoryLevel, error)
+	DeductIncomingStock(ctx context.Context, arg db.DeductIncomingStockParams) (db.InventoryLevel, error)
+	GetOrderFulfillment(ctx context.Context, arg db.GetOrderFulfillmentParams) (db.OrderFulfillment, error)
+	ListFulfillmentLocations(ctx context.Context, arg db.ListFulfillmentLocationsParams) ([]db.Location, error)
+	GetOrderItemsByOrder(ctx context.Context, arg db.GetOrderItemsByOrderParams) ([]db.OrderItem, error)
+	CreateOrderFulfillment(ctx context.Context, arg db.CreateOrderFulfillmentParams) (db.OrderFulfillment, error)
+	GetVariantTracksLots(ctx context.Context, arg db.GetVariantTracksLotsParams) (bool, error)
+	ReceiveInventoryLot(ctx context.Context, arg db.ReceiveInventoryLotParams) (db.InventoryLot, error)
+	ListAllocatableLots(ctx context.Context, arg db.ListAllocatableLotsParams) ([]db.ListAllocatableLotsRow, error)
+	DeductInventoryLot(ctx context.Context, arg db.DeductInventoryLotParams) (db.InventoryLot, error)
+	ListExpiredLots(ctx context.Context, arg db.ListExpiredLotsParams) ([]db.InventoryLot, error)
+	GetVariantReorderPoint(ctx context.Context, arg db.GetVariantReorderPointParams) (db.GetVariantReorderPointRow, error)
+	CreateStockAlert(ctx context.Context, arg db.CreateStockAlertParams) (db.StockAlert, error)
+	QueueStockAlertNotification(ctx context.Context, arg db.QueueStockAlertNotificationParams) (int64, error)
+	VariantIsBundle(ctx context.Context, arg db.VariantIsBundleParams) (bool, error)
+	ListBundleComponents(ctx context.Context, arg db.ListBundleComponentsParams) ([]db.ListBundleComponentsRow, error)
+	SyncBundleAvailableQuantities(ctx context.Context, arg db.SyncBundleAvailableQuantitiesParams) error
+}
+
+// DefaultLocation returns the default location of a shop, creating it for shops
+// that have none yet
+func DefaultLocation(ctx context.Context, q inventoryQueries, shopID int64) (db.Location, error) {
+	if err := q.EnsureDefaultLocation(ctx, shopID); err != nil {
+		return db.Location{}, err
+	}
+	return q.GetDefaultLocation(ctx, shopID)
+}
+
+// StockLocation returns the location a stock change applies to: the given
+// location, or the default location of the shop when none is given
+func StockLocation(ctx context.Context, q inventoryQueries, shopID int64, locationID *int64) (db.Location, error) {
+	if locationID == nil {
+		return DefaultLocation(ctx, q, shopID)
+	}
+	location, err := q.GetLocation(ctx, db.GetLocationParams{LocationID: *locationID, ShopID: shopID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return db.Location{}, ErrLocationNotFound
+	}
+	return location, err
+}
+
+// ChangeStock applies a stock change to a location, records the movement and
+// keeps the available quantity of the variant, its lots and the bundles it is a
+// component of in step, raising a stock alert when the variant falls to its
+// reorder point. Deducting more than the location holds fails with
+// ErrInsufficientStock, and bundles, which hold no stock, fail with ErrBundleStock.
+func ChangeStock(ctx context.Context, q inventoryQueries, change StockChange) (StockChangeResult, error) {
+	variant, err := q.GetProductVariation(ctx, db.GetProductVariationParams{
+		ProductVariationID: change.VariantID,
+		ShopID:             change.ShopID,
+	})
+	if err != nil {
+		return StockChangeResult{}, err
+	}
+	isBundle, err := q.VariantIsBundle(ctx, db.VariantIsBundleParams{
+		BundleVariationID: change.VariantID,
+		ShopID:            change.ShopID,
+	})
+	if err != nil {
+		return StockChangeResult{}, err
+	}
+	if isBundle {
+		return StockChangeResult{}, ErrBundleStock
+	}
+	before, err := q.EnsureInventoryLevel(ctx, db.EnsureInventoryLevelParams{
+		ProductVariationID: change.VariantID,
+		LocationID:         change.LocationID,
+		ShopID:             change.ShopID,
+	})
+	if err != nil {
+		return StockChangeResult{}, err
+	}
+
+	var level db.InventoryLevel
+	switch change.Operation {
+	case StockSet:
+		level, err = q.UpdateVariantStock(ctx, db.UpdateVariantStockParams{
+			ProductVariationID: change.VariantID,
+			LocationID:         change.LocationID,
+			ShopID:             change.ShopID,
+			Available:          change.Quantity,
+		})
+	case StockAdd:
+		level, err = q.AddVariantStock(ctx, db.AddVariantStockParams{
+			ProductVariationID: change.VariantID,
+			LocationID:         change.LocationID,
+			ShopID:             change.ShopID,
+			Available:          change.Quantity,
+		})
+	case StockDeduct:
+		level, err = q.DeductVariantStock(ctx, db.DeductVariantStockParams{
+			ProductVariationID: change.VariantID,
+			LocationID:         change.LocationID,
+			ShopID:             change.ShopID,
+			Available:          change.Quantity,
+		})
+		if errors.Is(err, pgx.ErrNoRows) {
+			err = ErrInsufficientStock
+		}
+	}
+	if err != nil {
+		return StockChangeResult{}, err
+	}
+	if err := changeLots(ctx, q, change, before.Available, level.Available); err != nil {
+		return StockChangeResult{}, err
+	}
+
+	if _, err := q.CreateStockMovement(ctx, db.CreateStockMovementParams{
+		ProductVariationID: change.VariantID,
+		ShopID:             change.ShopID,
+		MovementType:       change.MovementType,
+		QuantityChange:     int32(level.Available - before.Available),
+		QuantityBefore:     int32(before.Available),
+		QuantityAfter:      int32(level.Available),
+		ReferenceID:        change.ReferenceID,
+		Notes:              change.Notes,
+		LocationID:         &change.LocationID,
+	}); err != nil {
+		return StockChangeResult{}, err
+	}
+
+	synced, err := q.SyncVariantAvailableQuantity(ctx, db.SyncVariantAvailableQuantityParams{
+		ProductVariationID: change.VariantID,
+		ShopID:             change.ShopID,
+	})
+	if err != nil {
+		return StockChangeResult{}, err
+	}
+	if err := q.SyncBundleAvailableQuantities(ctx, db.SyncBundleAvailableQuantitiesParams{
+		ShopID:    change.ShopID,
+		VariantID: &change.VariantID,
+	}); err != nil {
+		return StockChangeResult{}, err
+	}
+	if err := checkReorderPoint(ctx, q, change.ShopID, change.VariantID, variant.AvailableQuantity, synced.AvailableQuantity); err != nil {
+		return StockChangeResult{}, err
+	}
+	return StockChangeResult{Level: level, Variant: synced, OnlineBefore: variant.AvailableQuantity}, nil
+}
+
+// ReconcileProductStock moves the available quantity set directly on the variants
+// of a product to the default location, so that product edits and the stock held
+// at locations agree
+func ReconcileProductStock(ctx context.Context, q inventoryQueries, shopID, productID int64) error {
+	if _, err := DefaultLocation(ctx, q, shopID); err != nil {
+		return err
+	}
+	if err := q.ReconcileProductInventoryLevels(ctx, db.ReconcileProductInventoryLevelsParams{
+		ProductID: productID,
+		ShopID:    shopID,
+	}); err != nil {
+		return err
+	}
+	if err := q.SyncProductAvailableQuantities(ctx, db.SyncProductAvailableQuantitiesParams{
+		ProductID: productID,
+		ShopID:    shopID,
+	}); err != nil {
+		return err
+	}
+	return q.SyncBundleAvailableQuantities(ctx, db.SyncBundleAvailableQuantitiesParams{ShopID: shopID})
+}
+
+// CreateInventoryTransfer takes the items of a transfer from the origin location
+// and counts them as incoming at the destination until the transfer is received
+func CreateInventoryTransfer(ctx context.Context, q inventoryQueries, arg db.CreateInventoryTransferParams, items []TransferItem) (db.InventoryTransfer, []StockChangeResult, error) {
+	transfer, err := q.CreateInventoryTransfer(ctx, arg)
+	if err != nil {
+		return db.InventoryTransfer{}, nil, err
+	}
+	results := make([]StockChangeResult, 0, len(items))
+	for _, item := range items {
+		if _, err := q.CreateInventoryTransferItem(ctx, db.CreateInventoryTransferItemParams{
+			TransferID:         transfer.TransferID,
+			ProductVariationID: item.VariantID,
+			Quantity:           item.Quantity,
+			ShopID:             arg.ShopID,
+		}); err != nil {
+			return db.InventoryTransfer{}, nil, err
+		}
+		result, err := ChangeStock(ctx, q, StockChange{
+			ShopID:       arg.ShopID,
+			VariantID:    item.VariantID,
+			LocationID:   arg.OriginLocationID,
+			Operation:    StockDeduct,
+			Quantity:     item.Quantity,
+			MovementType: "transfer",
+			ReferenceID:  &transfer.TransferID,
+			Notes:        arg.Note,
+		})
+		if err != nil {
+			return db.InventoryTransfer{}, nil, err
+		}
+		if _, err := q.AddIncomingStock(ctx, db.AddIncomingStockParams{
+			ProductVariationID: item.VariantID,
+			LocationID:         arg.DestinationLocationID,
+			Incoming:           item.Quantity,
+			ShopID:             arg.ShopID,
+		}); err != nil {
+			return db.InventoryTransfer{}, nil, err
+		}
+		results = append(results, result)
+	}
+	return transfer, results, nil
+}
+
+// ReceiveInventoryTransfer makes the incoming stock of a transfer available at its
+// destination
+func ReceiveInventoryTransfer(ctx context.Context, q *db.Queries, shopID, transferID int64) (db.InventoryTransfer, []StockChangeResult, error) {
+	transfer, err := q.ReceiveInventoryTransfer(ctx, db.ReceiveInventoryTransferParams{TransferID: transferID, ShopID: shopID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return db.InventoryTransfer{}, nil, ErrTransferNotInTransit
+		}
+		return db.InventoryTransfer{}, nil, err
+	}
+	results, err := settleInventoryTransfer(ctx, q, transfer, transfer.DestinationLocationID)
+	return transfer, results, err
+}
+
+// CancelInventoryTransfer returns the stock of a transfer that is still in transit
+// to its origin
+func CancelInventoryTransfer(ctx context.Context, q *db.Queries, shopID, transferID int64) (db.InventoryTransfer, []StockChangeResult, error) {
+	transfer, err := q.CancelInventoryTransfer(ctx, db.CancelInventoryTransferParams{TransferID: transferID, ShopID: shopID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return db.InventoryTransfer{}, nil, ErrTransferNotInTransit
+		}
+		return db.InventoryTransfer{}, nil, err
+	}
+	results, err := settleInventoryTransfer(ctx, q, transfer, transfer.OriginLocationID)
+	return transfer, results, err
+}
+
+// settleInventoryTransfer takes the items of a transfer off the destination's
+// incoming stock and adds them to the given location
+func settleInventoryTransfer(ctx context.Context, q inventoryQueries, transfer db.InventoryTransfer, locationID int64) ([]StockChangeResult, error) {
+	items, err := q.ListInventoryTransferItems(ctx, db.ListInventoryTransferItemsParams{
+		TransferID: transfer.TransferID,
+		ShopID:     transfer.ShopID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	results := make([]StockChangeResult, 0, len(items))
+	for _, item := range items {
+		if _, err := q.DeductIncomingStock(ctx, db.DeductIncomingStockParams{
+			ProductVariationID: item.ProductVariationID,
+			LocationID:         transfer.DestinationLocationID,
+			ShopID:             transfer.ShopID,
+			Incoming:           item.Quantity,
+		}); err != nil {
+			return nil, err
+		}
+		result, err := ChangeStock(ctx, q, StockChange{
+			ShopID:       transfer.ShopID,
+			VariantID:    item.ProductVariationID,
+			LocationID:   locationID,
+			Operation:    StockAdd,
+			Quantity:     item.Quantity,
+			MovementType: "transfer",
+			ReferenceID:  &transfer.TransferID,
+			Notes:        transfer.Note,
+		})
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// FulfillOrder takes the items of an order from a location, bundles as their
+// components. Without a location it picks the first location that sells online
+// and holds enough stock for the whole order, starting with the default location.
+func FulfillOrder(ctx context.Context, q inventoryQueries, order db.Order, locationID *int64, createdBy *string) (db.OrderFulfillment, error) {
+	if _, err := q.GetOrderFulfillment(ctx, db.GetOrderFulfillmentParams{OrderID: order.OrderID, ShopID: order.ShopID}); err == nil {
+		return db.OrderFulfillment{}, ErrOrderAlreadyFulfilled
+	} else if !errors.Is(err, pgx.ErrNoRows) {
+		return db.OrderFulfillment{}, err
+	}
+
+	if locationID == nil {
+		locations, err := q.ListFulfillmentLocations(ctx, db.ListFulfillmentLocationsParams{OrderID: order.OrderID, ShopID: order.ShopID})
+		if err != nil {
+			return db.OrderFulfillment{}, err
+		}
+		if len(locations) == 0 {
+			return db.OrderFulfillment{}, ErrNoFulfillmentLocation
+		}
+		locationID = &locations[0].LocationID
+	}
+
+	items, err := q.GetOrderItemsByOrder(ctx, db.GetOrderItemsByOrderParams{OrderID: order.OrderID, ShopID: order.ShopID})
+	if err != nil {
+		return db.OrderFulfillment{}, err
+	}
+	for _, item := range items {
+		stocked, err := stockedVariants(ctx, q, order.ShopID, item.ProductVariationID)
+		if err != nil {
+			return db.OrderFulfillment{}, err
+		}
+		for _, variant := range stocked {
+			if _, err := ChangeStock(ctx, q, StockChange{
+				ShopID:       order.ShopID,
+				VariantID:    variant.VariantID,
+				LocationID:   *locationID,
+				Operation:    StockDeduct,
+				Quantity:     item.Quantity * variant.Quantity,
+				MovementType: "sale",
+				ReferenceID:  &order.OrderID,
+			}); err != nil {
+				return db.OrderFulfillment{}, err
+			}
+		}
+	}
+
+	fulfillment, err := q.CreateOrderFulfillment(ctx, db.CreateOrderFulfillmentParams{
+		OrderID:    order.OrderID,
+		LocationID: locationID,
+		CreatedBy:  createdBy,
+		ShopID:     order.ShopID,
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == dberrors.UniqueViolation {
+			return db.OrderFulfillment{}, ErrOrderAlreadyFulfilled
+		}
+		return db.OrderFulfillment{}, err
+	}
+	return fulfillment, nil
+}
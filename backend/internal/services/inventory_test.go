@@ -0,0 +1,528 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/petrejonn/naytife/internal/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stockKey struct {
+	variantID  int64
+	locationID int64
+}
+
+// fakeStock keeps the stock of a shop's locations in memory. Locations are listed
+// with the default first, as the queries order them.
+type fakeStock struct {
+	inventoryQueries
+	locations    []db.Location
+	levels       map[stockKey]db.InventoryLevel
+	movements    []db.CreateStockMovementParams
+	items        []db.OrderItem
+	fulfillment  *db.OrderFulfillment
+	transfer     db.InventoryTransfer
+	transferred  []db.InventoryTransferItem
+	nextTransfer int64
+	lots         []db.InventoryLot
+	tracksLots   bool
+	reorder      db.GetVariantReorderPointRow
+	alerts       []db.CreateStockAlertParams
+	notified     int
+	bundles      map[int64][]db.BundleComponent
+	bundleStock  map[int64]int64
+}
+
+func newFakeStock(locations ...db.Location) *fakeStock {
+	return &fakeStock{
+		locations:   locations,
+		levels:      map[stockKey]db.InventoryLevel{},
+		bundles:     map[int64][]db.BundleComponent{},
+		bundleStock: map[int64]int64{},
+	}
+}
+
+func (f *fakeStock) stock(variantID, locationID int64) db.InventoryLevel {
+	return f.levels[stockKey{variantID, locationID}]
+}
+
+func (f *fakeStock) setStock(variantID, locationID, available int64) {
+	f.levels[stockKey{variantID, locationID}] = db.InventoryLevel{ProductVariationID: variantID, LocationID: locationID, Available: available, ShopID: 1}
+}
+
+func (f *fakeStock) online(variantID int64) int64 {
+	var total int64
+	for _, location := range f.locations {
+		if location.SellsOnline {
+			total += f.stock(variantID, location.LocationID).Available
+		}
+	}
+	return total
+}
+
+func (f *fakeStock) GetProductVariation(ctx context.Context, arg db.GetProductVariationParams) (db.ProductVariation, error) {
+	return db.ProductVariation{ProductVariationID: arg.ProductVariationID, AvailableQuantity: f.online(arg.ProductVariationID), ShopID: arg.ShopID}, nil
+}
+
+func (f *fakeStock) EnsureInventoryLevel(ctx context.Context, arg db.EnsureInventoryLevelParams) (db.InventoryLevel, error) {
+	key := stockKey{arg.ProductVariationID, arg.LocationID}
+	if _, ok := f.levels[key]; !ok {
+		f.setStock(arg.ProductVariationID, arg.LocationID, 0)
+	}
+	return f.levels[key], nil
+}
+
+func (f *fakeStock) UpdateVariantStock(ctx context.Context, arg db.UpdateVariantStockParams) (db.InventoryLevel, error) {
+	level := f.stock(arg.ProductVariationID, arg.LocationID)
+	level.Available = arg.Available
+	f.levels[stockKey{arg.ProductVariationID, arg.LocationID}] = level
+	return level, nil
+}
+
+func (f *fakeStock) AddVariantStock(ctx context.Context, arg db.AddVariantStockParams) (db.InventoryLevel, error) {
+	level := f.stock(arg.ProductVariationID, arg.LocationID)
+	level.Available += arg.Available
+	f.levels[stockKey{arg.ProductVariationID, arg.LocationID}] = level
+	return level, nil
+}
+
+func (f *fakeStock) DeductVariantStock(ctx context.Context, arg db.DeductVariantStockParams) (db.InventoryLevel, error) {
+	level := f.stock(arg.ProductVariationID, arg.LocationID)
+	if level.Available < arg.Available {
+		return db.InventoryLevel{}, pgx.ErrNoRows
+	}
+	level.Available -= arg.Available
+	f.levels[stockKey{arg.ProductVariationID, arg.LocationID}] = level
+	return level, nil
+}
+
+func (f *fakeStock) CreateStockMovement(ctx context.Context, arg db.CreateStockMovementParams) (db.StockMovement, error) {
+	f.movements = append(f.movements, arg)
+	return db.StockMovement{}, nil
+}
+
+func (f *fakeStock) SyncVariantAvailableQuantity(ctx context.Context, arg db.SyncVariantAvailableQuantityParams) (db.ProductVariation, error) {
+	return db.ProductVariation{ProductVariationID: arg.ProductVariationID, AvailableQuantity: f.online(arg.ProductVariationID), ShopID: arg.ShopID}, nil
+}
+
+func (f *fakeStock) GetOrderFulfillment(ctx context.Context, arg db.GetOrderFulfillmentParams) (db.OrderFulfillment, error) {
+	if f.fulfillment == nil {
+		return db.OrderFulfillment{}, pgx.ErrNoRows
+	}
+	return *f.fulfillment, nil
+}
+
+func (f *fakeStock) GetOrderItemsByOrder(ctx context.Context, arg db.GetOrderItemsByOrderParams) ([]db.OrderItem, error) {
+	return f.items, nil
+}
+
+func (f *fakeStock) ListFulfillmentLocations(ctx context.Context, arg db.ListFulfillmentLocationsParams) ([]db.Location, error) {
+	quantities := map[int64]int64{}
+	for _, item := range f.items {
+		quantities[item.ProductVariationID] += item.Quantity
+	}
+	var locations []db.Location
+	for _, location := range f.locations {
+		holdsOrder := location.SellsOnline
+		for variantID, quantity := range quantities {
+			holdsOrder = holdsOrder && f.stock(variantID, location.LocationID).Available >= quantity
+		}
+		if holdsOrder {
+			locations = append(locations, location)
+		}
+	}
+	return locations, nil
+}
+
+func (f *fakeStock) CreateOrderFulfillment(ctx context.Context, arg db.CreateOrderFulfillmentParams) (db.OrderFulfillment, error) {
+	f.fulfillment = &db.OrderFulfillment{FulfillmentID: 1, OrderID: arg.OrderID, LocationID: arg.LocationID, ShopID: arg.ShopID}
+	return *f.fulfillment, nil
+}
+
+func (f *fakeStock) CreateInventoryTransfer(ctx context.Context, arg db.CreateInventoryTransferParams) (db.InventoryTransfer, error) {
+	f.nextTransfer++
+	f.transfer = db.InventoryTransfer{
+		TransferID:            f.nextTransfer,
+		OriginLocationID:      arg.OriginLocationID,
+		DestinationLocationID: arg.DestinationLocationID,
+		Status:                db.InventoryTransferStatusInTransit,
+		ShopID:                arg.ShopID,
+	}
+	f.transferred = nil
+	return f.transfer, nil
+}
+
+func (f *fakeStock) CreateInventoryTransferItem(ctx context.Context, arg db.CreateInventoryTransferItemParams) (db.InventoryTransferItem, error) {
+	item := db.InventoryTransferItem{TransferID: arg.TransferID, ProductVariationID: arg.ProductVariationID, Quantity: arg.Quantity, ShopID: arg.ShopID}
+	f.transferred = append(f.transferred, item)
+	return item, nil
+}
+
+func (f *fakeStock) ListInventoryTransferItems(ctx context.Context, arg db.ListInventoryTransferItemsParams) ([]db.ListInventoryTransferItemsRow, error) {
+	var rows []db.ListInventoryTransferItemsRow
+	for _, item := range f.transferred {
+		rows = append(rows, db.ListInventoryTransferItemsRow{TransferID: item.TransferID, ProductVariationID: item.ProductVariationID, Quantity: item.Quantity, ShopID: item.ShopID})
+	}
+	return rows, nil
+}
+
+func (f *fakeStock) AddIncomingStock(ctx context.Context, arg db.AddIncomingStockParams) (db.InventoryLevel, error) {
+	key := stockKey{arg.ProductVariationID, arg.LocationID}
+	level := f.levels[key]
+	level.Incoming += arg.Incoming
+	f.levels[key] = level
+	return level, nil
+}
+
+func (f *fakeStock) DeductIncomingStock(ctx context.Context, arg db.DeductIncomingStockParams) (db.InventoryLevel, error) {
+	key := stockKey{arg.ProductVariationID, arg.LocationID}
+	level := f.levels[key]
+	level.Incoming -= arg.Incoming
+	f.levels[key] = level
+	return level, nil
+}
+
+func (f *fakeStock) GetVariantTracksLots(ctx context.Context, arg db.GetVariantTracksLotsParams) (bool, error) {
+	return f.tracksLots, nil
+}
+
+func (f *fakeStock) ReceiveInventoryLot(ctx context.Context, arg db.ReceiveInventoryLotParams) (db.InventoryLot, error) {
+	for i, lot := range f.lots {
+		if lot.ProductVariationID == arg.ProductVariationID && lot.LocationID == arg.LocationID && lot.LotNumber == arg.LotNumber {
+			f.lots[i].Quantity += arg.Quantity
+			if arg.ExpiresOn.Valid {
+				f.lots[i].ExpiresOn = arg.ExpiresOn
+			}
+			return f.lots[i], nil
+		}
+	}
+	lot := db.InventoryLot{
+		LotID:              int64(len(f.lots) + 1),
+		ProductVariationID: arg.ProductVariationID,
+		LocationID:         arg.LocationID,
+		LotNumber:          arg.LotNumber,
+		ExpiresOn:          arg.ExpiresOn,
+		Quantity:           arg.Quantity,
+		ShopID:             arg.ShopID,
+	}
+	f.lots = append(f.lots, lot)
+	return lot, nil
+}
+
+func (f *fakeStock) ListAllocatableLots(ctx context.Context, arg db.ListAllocatableLotsParams) ([]db.ListAllocatableLotsRow, error) {
+	today := time.Now().Truncate(24 * time.Hour)
+	var rows []db.ListAllocatableLotsRow
+	for _, lot := range f.lots {
+		if lot.ProductVariationID != arg.ProductVariationID || lot.LocationID != arg.LocationID || lot.Quantity <= 0 {
+			continue
+		}
+		rows = append(rows, db.ListAllocatableLotsRow{
+			LotID:              lot.LotID,
+			ProductVariationID: lot.ProductVariationID,
+			LocationID:         lot.LocationID,
+			LotNumber:          lot.LotNumber,
+			ExpiresOn:          lot.ExpiresOn,
+			Quantity:           lot.Quantity,
+			ShopID:             lot.ShopID,
+			Expired:            lot.ExpiresOn.Valid && lot.ExpiresOn.Time.Before(today),
+		})
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		a, b := rows[i].ExpiresOn, rows[j].ExpiresOn
+		return a.Valid && (!b.Valid || a.Time.Before(b.Time))
+	})
+	return rows, nil
+}
+
+func (f *fakeStock) DeductInventoryLot(ctx context.Context, arg db.DeductInventoryLotParams) (db.InventoryLot, error) {
+	for i, lot := range f.lots {
+		if lot.LotID == arg.LotID && lot.ProductVariationID == arg.ProductVariationID && lot.LocationID == arg.LocationID {
+			if lot.Quantity < arg.Quantity {
+				return db.InventoryLot{}, pgx.ErrNoRows
+			}
+			f.lots[i].Quantity -= arg.Quantity
+			return f.lots[i], nil
+		}
+	}
+	return db.InventoryLot{}, pgx.ErrNoRows
+}
+
+func (f *fakeStock) ListExpiredLots(ctx context.Context, arg db.ListExpiredLotsParams) ([]db.InventoryLot, error) {
+	today := time.Now().Truncate(24 * time.Hour)
+	var lots []db.InventoryLot
+	for _, lot := range f.lots {
+		if lot.Quantity > 0 && lot.ExpiresOn.Valid && lot.ExpiresOn.Time.Before(today) && (arg.LocationID == nil || *arg.LocationID == lot.LocationID) {
+			lots = append(lots, lot)
+		}
+	}
+	return lots, nil
+}
+
+func (f *fakeStock) GetVariantReorderPoint(ctx context.Context, arg db.GetVariantReorderPointParams) (db.GetVariantReorderPointRow, error) {
+	return f.reorder, nil
+}
+
+func (f *fakeStock) CreateStockAlert(ctx context.Context, arg db.CreateStockAlertParams) (db.StockAlert, error) {
+	f.alerts = append(f.alerts, arg)
+	return db.StockAlert{AlertID: int64(len(f.alerts)), Kind: arg.Kind, ShopID: arg.ShopID}, nil
+}
+
+func (f *fakeStock) QueueStockAlertNotification(ctx context.Context, arg db.QueueStockAlertNotificationParams) (int64, error) {
+	f.notified++
+	return 1, nil
+}
+
+func (f *fakeStock) VariantIsBundle(ctx context.Context, arg db.VariantIsBundleParams) (bool, error) {
+	return len(f.bundles[arg.BundleVariationID]) > 0, nil
+}
+
+func (f *fakeStock) ListBundleComponents(ctx context.Context, arg db.ListBundleComponentsParams) ([]db.ListBundleComponentsRow, error) {
+	var rows []db.ListBundleComponentsRow
+	for _, component := range f.bundles[arg.BundleVariationID] {
+		rows = append(rows, db.ListBundleComponentsRow{
+			BundleVariationID:    component.BundleVariationID,
+			ComponentVariationID: component.ComponentVariationID,
+			Quantity:             component.Quantity,
+			ShopID:               component.ShopID,
+		})
+	}
+	return rows, nil
+}
+
+// SyncBundleAvailableQuantities makes up the bundles like the query does: as many
+// as the component in shortest supply allows
+func (f *fakeStock) SyncBundleAvailableQuantities(ctx context.Context, arg db.SyncBundleAvailableQuantitiesParams) error {
+	for bundleID, components := range f.bundles {
+		if len(components) == 0 {
+			continue
+		}
+		affected := arg.VariantID == nil || *arg.VariantID == bundleID
+		var available int64 = -1
+		for _, component := range components {
+			affected = affected || *arg.VariantID == component.ComponentVariationID
+			if made := f.online(component.ComponentVariationID) / component.Quantity; available < 0 || made < available {
+				available = made
+			}
+		}
+		if affected {
+			f.bundleStock[bundleID] = available
+		}
+	}
+	return nil
+}
+
+var (
+	warehouse = db.Location{LocationID: 1, Name: "Warehouse", SellsOnline: true, IsDefault: true, ShopID: 1}
+	shopFloor = db.Location{LocationID: 2, Name: "Shop floor", SellsOnline: true, ShopID: 1}
+	backRoom  = db.Location{LocationID: 3, Name: "Back room", ShopID: 1}
+)
+
+func TestChangeStock(t *testing.T) {
+	tests := []struct {
+		name       string
+		operation  StockOperation
+		quantity   int64
+		wantErr    error
+		wantStock  int64
+		wantChange int32
+	}{
+		{"set", StockSet, 4, nil, 4, -6},
+		{"add", StockAdd, 5, nil, 15, 5},
+		{"deduct", StockDeduct, 10, nil, 0, -10},
+		{"deduct more than the location holds", StockDeduct, 11, ErrInsufficientStock, 10, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFakeStock(warehouse, shopFloor, backRoom)
+			f.setStock(20, warehouse.LocationID, 10)
+			f.setStock(20, shopFloor.LocationID, 3)
+			f.setStock(20, backRoom.LocationID, 7)
+
+			result, err := ChangeStock(context.Background(), f, StockChange{
+				ShopID:       1,
+				VariantID:    20,
+				LocationID:   warehouse.LocationID,
+				Operation:    tt.operation,
+				Quantity:     tt.quantity,
+				MovementType: "adjustment",
+			})
+			assert.Equal(t, tt.wantStock, f.stock(20, warehouse.LocationID).Available)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Empty(t, f.movements)
+				return
+			}
+			require.NoError(t, err)
+
+			// Stock at locations that do not sell online is left out of the storefront
+			assert.Equal(t, int64(13), result.OnlineBefore)
+			assert.Equal(t, tt.wantStock+3, result.Variant.AvailableQuantity)
+			require.Len(t, f.movements, 1)
+			assert.Equal(t, tt.wantChange, f.movements[0].QuantityChange)
+			assert.Equal(t, int32(10), f.movements[0].QuantityBefore)
+			assert.Equal(t, warehouse.LocationID, *f.movements[0].LocationID)
+		})
+	}
+}
+
+func TestFulfillOrder(t *testing.T) {
+	order := db.Order{OrderID: 9, ShopID: 1}
+	items := []db.OrderItem{
+		{OrderItemID: 1, OrderID: 9, ProductVariationID: 20, Quantity: 2},
+		{OrderItemID: 2, OrderID: 9, ProductVariationID: 21, Quantity: 1},
+	}
+	backRoomID := backRoom.LocationID
+
+	tests := []struct {
+		name         string
+		stock        map[stockKey]int64
+		locationID   *int64
+		wantErr      error
+		wantLocation int64
+	}{
+		{
+			name:         "default location holds the order",
+			stock:        map[stockKey]int64{{20, 1}: 2, {21, 1}: 1, {20, 2}: 5, {21, 2}: 5},
+			wantLocation: warehouse.LocationID,
+		},
+		{
+			name:         "next location that holds every item",
+			stock:        map[stockKey]int64{{20, 1}: 2, {21, 1}: 0, {20, 2}: 2, {21, 2}: 1},
+			wantLocation: shopFloor.LocationID,
+		},
+		{
+			name:    "stock split across locations",
+			stock:   map[stockKey]int64{{20, 1}: 2, {21, 2}: 1},
+			wantErr: ErrNoFulfillmentLocation,
+		},
+		{
+			name:    "only a location that does not sell online holds it",
+			stock:   map[stockKey]int64{{20, 3}: 2, {21, 3}: 1},
+			wantErr: ErrNoFulfillmentLocation,
+		},
+		{
+			name:         "chosen location",
+			stock:        map[stockKey]int64{{20, 1}: 2, {21, 1}: 1, {20, 3}: 2, {21, 3}: 1},
+			locationID:   &backRoomID,
+			wantLocation: backRoom.LocationID,
+		},
+		{
+			name:       "chosen location without enough stock",
+			stock:      map[stockKey]int64{{20, 1}: 2, {21, 1}: 1, {20, 3}: 1, {21, 3}: 1},
+			locationID: &backRoomID,
+			wantErr:    ErrInsufficientStock,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFakeStock(warehouse, shopFloor, backRoom)
+			f.items = items
+			for key, available := range tt.stock {
+				f.setStock(key.variantID, key.locationID, available)
+			}
+
+			fulfillment, err := FulfillOrder(context.Background(), f, order, tt.locationID, nil)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, f.fulfillment)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantLocation, *fulfillment.LocationID)
+			assert.Equal(t, tt.stock[stockKey{20, tt.wantLocation}]-2, f.stock(20, tt.wantLocation).Available)
+			assert.Equal(t, tt.stock[stockKey{21, tt.wantLocation}]-1, f.stock(21, tt.wantLocation).Available)
+			for _, movement := range f.movements {
+				assert.Equal(t, "sale", movement.MovementType)
+				assert.Equal(t, order.OrderID, *movement.ReferenceID)
+			}
+
+			_, err = FulfillOrder(context.Background(), f, order, tt.locationID, nil)
+			assert.ErrorIs(t, err, ErrOrderAlreadyFulfilled)
+		})
+	}
+}
+
+func TestInventoryTransfer(t *testing.T) {
+	ctx := context.Background()
+	items := []TransferItem{{VariantID: 20, Quantity: 4}, {VariantID: 21, Quantity: 1}}
+	newStock := func() *fakeStock {
+		f := newFakeStock(warehouse, shopFloor, backRoom)
+		f.setStock(20, warehouse.LocationID, 10)
+		f.setStock(21, warehouse.LocationID, 1)
+		return f
+	}
+	send := func(t *testing.T, f *fakeStock) db.InventoryTransfer {
+		transfer, results, err := CreateInventoryTransfer(ctx, f, db.CreateInventoryTransferParams{
+			OriginLocationID:      warehouse.LocationID,
+			DestinationLocationID: backRoom.LocationID,
+			ShopID:                1,
+		}, items)
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+
+		// In transit the stock is incoming at the destination and no longer sold online
+		assert.Equal(t, int64(6), f.stock(20, warehouse.LocationID).Available)
+		assert.Equal(t, int64(4), f.stock(20, backRoom.LocationID).Incoming)
+		assert.Equal(t, int64(1), f.stock(21, backRoom.LocationID).Incoming)
+		assert.Equal(t, int64(6), results[0].Variant.AvailableQuantity)
+		return transfer
+	}
+
+	t.Run("received", func(t *testing.T) {
+		f := newStock()
+		transfer := send(t, f)
+
+		results, err := settleInventoryTransfer(ctx, f, transfer, transfer.DestinationLocationID)
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		assert.Equal(t, int64(4), f.stock(20, backRoom.LocationID).Available)
+		assert.Equal(t, int64(1), f.stock(21, backRoom.LocationID).Available)
+		assert.Zero(t, f.stock(21, backRoom.LocationID).Incoming)
+		assert.Equal(t, int64(6), f.online(20))
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		f := newStock()
+		transfer := send(t, f)
+
+		_, err := settleInventoryTransfer(ctx, f, transfer, transfer.OriginLocationID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(10), f.stock(20, warehouse.LocationID).Available)
+		assert.Equal(t, int64(1), f.stock(21, warehouse.LocationID).Available)
+		assert.Zero(t, f.stock(20, backRoom.LocationID).Incoming)
+		assert.Zero(t, f.stock(20, backRoom.LocationID).Available)
+	})
+
+	t.Run("more than the origin holds", func(t *testing.T) {
+		f := newStock()
+		_, _, err := CreateInventoryTransfer(ctx, f, db.CreateInventoryTransferParams{
+			OriginLocationID:      warehouse.LocationID,
+			DestinationLocationID: backRoom.LocationID,
+			ShopID:                1,
+		}, []TransferItem{{VariantID: 21, Quantity: 2}})
+		assert.ErrorIs(t, err, ErrInsufficientStock)
+	})
+
+	t.Run("movements", func(t *testing.T) {
+		f := newStock()
+		transfer := send(t, f)
+		_, err := settleInventoryTransfer(ctx, f, transfer, transfer.DestinationLocationID)
+		require.NoError(t, err)
+
+		var changes []int32
+		for _, movement := range f.movements {
+			assert.Equal(t, "transfer", movement.MovementType)
+			assert.Equal(t, transfer.TransferID, *movement.ReferenceID)
+			changes = append(changes, movement.QuantityChange)
+		}
+		sort.Slice(changes, func(i, j int) bool { return changes[i] < changes[j] })
+		assert.Equal(t, []int32{-4, -1, 1, 4}, changes)
+	})
+}
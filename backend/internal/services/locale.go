@@ -0,0 +1,68 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/petrejonn/naytife/internal/db"
+	"golang.org/x/text/language"
+)
+
+// DefaultLocale is used for shops that have not configured any locales
+const DefaultLocale = "en"
+
+// NormalizeLocale validates a BCP 47 language tag and returns its canonical form, e.g. "pt-br" becomes "pt-BR"
+func NormalizeLocale(locale string) (string, error) {
+	tag, err := language.Parse(locale)
+	if err != nil || tag == language.Und {
+		return "", fmt.Errorf("invalid locale %q", locale)
+	}
+	return tag.String(), nil
+}
+
+// ShopLocales returns the default locale of a shop and all of its enabled
+// locales, default first, from the shop_locales rows of the shop
+func ShopLocales(rows []db.ShopLocale) (string, []string) {
+	if len(rows) == 0 {
+		return DefaultLocale, []string{DefaultLocale}
+	}
+	defaultLocale := rows[0].Locale
+	locales := make([]string, 0, len(rows))
+	for _, row := range rows {
+		if row.IsDefault {
+			defaultLocale = row.Locale
+		}
+	}
+	locales = append(locales, defaultLocale)
+	for _, row := range rows {
+		if row.Locale != defaultLocale {
+			locales = append(locales, row.Locale)
+		}
+	}
+	return defaultLocale, locales
+}
+
+// MatchLocale picks the enabled locale that best satisfies the requested
+// locale, which may be a single tag or an Accept-Language header value.
+// The first enabled locale is the default and is returned when nothing matches.
+func MatchLocale(locales []string, requested string) string {
+	if len(locales) == 0 {
+		return DefaultLocale
+	}
+	if requested == "" {
+		return locales[0]
+	}
+	prefs, _, err := language.ParseAcceptLanguage(requested)
+	if err != nil || len(prefs) == 0 {
+		return locales[0]
+	}
+
+	supported := make([]language.Tag, len(locales))
+	for i, locale := range locales {
+		supported[i] = language.Make(locale)
+	}
+	_, index, confidence := language.NewMatcher(supported).Match(prefs...)
+	if confidence == language.No {
+		return locales[0]
+	}
+	return locales[index]
+}
@@ -0,0 +1,82 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/petrejonn/naytife/internal/db"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeLocale(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"en", "en", false},
+		{"pt-br", "pt-BR", false},
+		{"zh-hant-tw", "zh-Hant-TW", false},
+		{"FR", "fr", false},
+		{"und", "", true},
+		{"", "", true},
+		{"not a locale", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := NormalizeLocale(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestShopLocales(t *testing.T) {
+	defaultLocale, locales := ShopLocales(nil)
+	assert.Equal(t, DefaultLocale, defaultLocale)
+	assert.Equal(t, []string{DefaultLocale}, locales)
+
+	// The default locale comes first whatever order the rows are in
+	defaultLocale, locales = ShopLocales([]db.ShopLocale{
+		{Locale: "de"},
+		{Locale: "fr", IsDefault: true},
+		{Locale: "en"},
+	})
+	assert.Equal(t, "fr", defaultLocale)
+	assert.Equal(t, []string{"fr", "de", "en"}, locales)
+
+	// Without a default row the first locale is the default
+	defaultLocale, locales = ShopLocales([]db.ShopLocale{{Locale: "es"}, {Locale: "en"}})
+	assert.Equal(t, "es", defaultLocale)
+	assert.Equal(t, []string{"es", "en"}, locales)
+}
+
+func TestMatchLocale(t *testing.T) {
+	locales := []string{"en", "fr", "pt-BR"}
+
+	tests := []struct {
+		name      string
+		locales   []string
+		requested string
+		want      string
+	}{
+		{"no locales", nil, "fr", DefaultLocale},
+		{"nothing requested", locales, "", "en"},
+		{"exact tag", locales, "fr", "fr"},
+		{"regional variant of enabled language", locales, "fr-CA", "fr"},
+		{"language of enabled regional locale", locales, "pt", "pt-BR"},
+		{"accept language preference order", locales, "de-DE,fr;q=0.8,en;q=0.5", "fr"},
+		{"unsupported language", locales, "ja", "en"},
+		{"malformed header", locales, ";;q=abc", "en"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, MatchLocale(tt.locales, tt.requested))
+		})
+	}
+}
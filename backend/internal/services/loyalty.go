@@ -0,0 +1,285 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/petrejonn/naytife/internal/db"
+	"github.com/petrejonn/naytife/internal/money"
+)
+
+var (
+	ErrLoyaltyDisabled        = errors.New("loyalty program is not enabled")
+	ErrInsufficientPoints     = errors.New("insufficient loyalty points")
+	ErrBelowMinimumRedemption = errors.New("fewer points than the minimum redemption")
+)
+
+// LoyaltyBalance is the points balance of a customer and what it is worth as a
+// discount. ExpiresAt is set when the points expire without further activity.
+type LoyaltyBalance struct {
+	Points    int32
+	Value     money.Money
+	ExpiresAt *time.Time
+}
+
+// loyaltyQueries are the queries of the points ledger, run within the caller's
+// transaction. *db.Queries implements them.
+type loyaltyQueries interface {
+	GetLoyaltyProgram(ctx context.Context, shopID int64) (db.LoyaltyProgram, error)
+	LockCustomerLoyaltyPoints(ctx context.Context, arg db.LockCustomerLoyaltyPointsParams) (uuid.UUID, error)
+	GetLoyaltyBalance(ctx context.Context, arg db.GetLoyaltyBalanceParams) (db.GetLoyaltyBalanceRow, error)
+	CreateLoyaltyTransaction(ctx context.Context, arg db.CreateLoyaltyTransactionParams) (db.LoyaltyTransaction, error)
+	ListOrderLoyaltyPoints(ctx context.Context, arg db.ListOrderLoyaltyPointsParams) ([]db.ListOrderLoyaltyPointsRow, error)
+}
+
+// LoyaltyProgram returns the loyalty program of a shop. Shops that never set one up
+// get a disabled program with the default rates.
+func LoyaltyProgram(ctx context.Context, repo db.Repository, shopID int64) (db.LoyaltyProgram, error) {
+	return loyaltyProgram(ctx, repo.GetLoyaltyProgram, shopID)
+}
+
+func loyaltyProgram(ctx context.Context, get func(context.Context, int64) (db.LoyaltyProgram, error), shopID int64) (db.LoyaltyProgram, error) {
+	program, err := get(ctx, shopID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return db.LoyaltyProgram{
+			ShopID:   shopID,
+			EarnRate: pgtype.Numeric{Int: big.NewInt(1), Valid: true},
+			BurnRate: pgtype.Numeric{Int: big.NewInt(100), Valid: true},
+		}, nil
+	}
+	return program, err
+}
+
+// LoyaltyPointsEarned returns the points earned by spending an amount, rounded down
+func LoyaltyPointsEarned(program db.LoyaltyProgram, spent money.Money) int32 {
+	if spent.IsZero() || spent.IsNegative() {
+		return 0
+	}
+	points := new(big.Rat).Mul(spent.Rat(), numericRat(program.EarnRate))
+	return clampPoints(new(big.Int).Quo(points.Num(), points.Denom()))
+}
+
+// LoyaltyPointsValue returns the discount that points are worth, rounded down to
+// the minor unit of currency
+func LoyaltyPointsValue(program db.LoyaltyProgram, points int32, currency string) money.Money {
+	burnRate := numericRat(program.BurnRate)
+	if points <= 0 || burnRate.Sign() <= 0 {
+		return money.Zero(currency)
+	}
+	minor := new(big.Rat).SetInt64(int64(points))
+	minor.Quo(minor, burnRate)
+	minor.Mul(minor, new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(money.Exponent(currency))), nil)))
+	return money.New(new(big.Int).Quo(minor.Num(), minor.Denom()).Int64(), currency)
+}
+
+// CheckLoyaltyRedemption reports whether a shop's program lets customers redeem
+// the given number of points at once
+func CheckLoyaltyRedemption(program db.LoyaltyProgram, points int32) error {
+	if !program.Enabled {
+		return ErrLoyaltyDisabled
+	}
+	if points <= 0 || points < program.MinRedeemPoints {
+		return fmt.Errorf("%w of %d", ErrBelowMinimumRedemption, program.MinRedeemPoints)
+	}
+	return nil
+}
+
+// CustomerLoyaltyBalance returns the points balance of a customer. Points past the
+// expiry of the program count as zero until the ledger records their expiry.
+func CustomerLoyaltyBalance(ctx context.Context, repo db.Repository, program db.LoyaltyProgram, customerID uuid.UUID, currency string) (LoyaltyBalance, error) {
+	row, err := repo.GetLoyaltyBalance(ctx, db.GetLoyaltyBalanceParams{
+		ShopCustomerID: customerID,
+		ShopID:         program.ShopID,
+	})
+	if err != nil {
+		return LoyaltyBalance{}, err
+	}
+	balance := LoyaltyBalance{Points: row.Balance}
+	if expiresAt := loyaltyExpiry(program, row); expiresAt != nil {
+		if time.Now().After(*expiresAt) {
+			balance.Points = 0
+		} else {
+			balance.ExpiresAt = expiresAt
+		}
+	}
+	balance.Value = LoyaltyPointsValue(program, balance.Points, currency)
+	return balance, nil
+}
+
+// AdjustLoyaltyPoints appends signed points to a customer's ledger, first expiring
+// the balance when the customer has been inactive for longer than the program
+// allows. It fails with ErrInsufficientPoints when the balance would go negative.
+func AdjustLoyaltyPoints(ctx context.Context, q loyaltyQueries, program db.LoyaltyProgram, customerID uuid.UUID, kind db.LoyaltyTransactionKind, points int32, orderID *int64, note, createdBy *string) (db.LoyaltyTransaction, error) {
+	if _, err := settleLoyaltyExpiry(ctx, q, program, customerID); err != nil {
+		return db.LoyaltyTransaction{}, err
+	}
+	entry, err := q.CreateLoyaltyTransaction(ctx, db.CreateLoyaltyTransactionParams{
+		ShopCustomerID: customerID,
+		Kind:           kind,
+		Points:         points,
+		ShopID:         program.ShopID,
+		OrderID:        orderID,
+		Note:           note,
+		CreatedBy:      createdBy,
+	})
+	if err != nil {
+		if isCheckViolation(err) {
+			return db.LoyaltyTransaction{}, ErrInsufficientPoints
+		}
+		return db.LoyaltyTransaction{}, err
+	}
+	return entry, nil
+}
+
+// RedeemLoyaltyPoints takes points from the customer of an order towards its discount
+func RedeemLoyaltyPoints(ctx context.Context, q loyaltyQueries, program db.LoyaltyProgram, order db.Order, points int32) (db.LoyaltyTransaction, error) {
+	if err := CheckLoyaltyRedemption(program, points); err != nil {
+		return db.LoyaltyTransaction{}, err
+	}
+	if !order.ShopCustomerID.Valid {
+		return db.LoyaltyTransaction{}, ErrInsufficientPoints
+	}
+	return AdjustLoyaltyPoints(ctx, q, program, uuid.UUID(order.ShopCustomerID.Bytes), db.LoyaltyTransactionKindRedeem, -points, &order.OrderID, nil, nil)
+}
+
+// AwardOrderLoyaltyPoints gives the customer of a completed order the points its
+// merchandise earns, i.e. the order total less shipping and tax. Orders that
+// already earned points are left alone, so it is safe to call on every status change.
+func AwardOrderLoyaltyPoints(ctx context.Context, q loyaltyQueries, order db.Order, currency string) (int32, error) {
+	if !order.ShopCustomerID.Valid {
+		return 0, nil
+	}
+	program, err := loyaltyProgram(ctx, q.GetLoyaltyProgram, order.ShopID)
+	if err != nil || !program.Enabled {
+		return 0, err
+	}
+	rows, err := q.ListOrderLoyaltyPoints(ctx, db.ListOrderLoyaltyPointsParams{OrderID: order.OrderID, ShopID: order.ShopID})
+	if err != nil {
+		return 0, err
+	}
+	for _, row := range rows {
+		if row.Awarded {
+			return 0, nil
+		}
+	}
+
+	var amounts [3]money.Money
+	for i, n := range []pgtype.Numeric{order.Amount, order.ShippingCost, order.Tax} {
+		if amounts[i], err = money.FromNumeric(n, currency); err != nil {
+			return 0, err
+		}
+	}
+	points := LoyaltyPointsEarned(program, amounts[0].Sub(amounts[1]).Sub(amounts[2]))
+	if points == 0 {
+		return 0, nil
+	}
+	if _, err := AdjustLoyaltyPoints(ctx, q, program, uuid.UUID(order.ShopCustomerID.Bytes), db.LoyaltyTransactionKindEarn, points, &order.OrderID, nil, nil); err != nil {
+		return 0, err
+	}
+	return points, nil
+}
+
+// ReverseOrderLoyaltyPoints gives back the points redeemed on an order and claws
+// back the points it earned, e.g. when it is cancelled or refunded. Earned points
+// the customer has already spent are only clawed back as far as the balance allows.
+func ReverseOrderLoyaltyPoints(ctx context.Context, q loyaltyQueries, shopID, orderID int64) error {
+	program, err := loyaltyProgram(ctx, q.GetLoyaltyProgram, shopID)
+	if err != nil {
+		return err
+	}
+	rows, err := q.ListOrderLoyaltyPoints(ctx, db.ListOrderLoyaltyPointsParams{OrderID: orderID, ShopID: shopID})
+	if err != nil {
+		return err
+	}
+	for _, row := range rows {
+		if row.Redeemed > 0 {
+			if _, err := AdjustLoyaltyPoints(ctx, q, program, row.ShopCustomerID, db.LoyaltyTransactionKindRefund, row.Redeemed, &orderID, nil, nil); err != nil {
+				return err
+			}
+		}
+		if row.Earned > 0 {
+			balance, err := settleLoyaltyExpiry(ctx, q, program, row.ShopCustomerID)
+			if err != nil {
+				return err
+			}
+			if take := min(row.Earned, balance); take > 0 {
+				if _, err := AdjustLoyaltyPoints(ctx, q, program, row.ShopCustomerID, db.LoyaltyTransactionKindClawback, -take, &orderID, nil, nil); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// settleLoyaltyExpiry locks the ledger of a customer, records the expiry of a
+// balance past the program's expiry and returns the balance left
+func settleLoyaltyExpiry(ctx context.Context, q loyaltyQueries, program db.LoyaltyProgram, customerID uuid.UUID) (int32, error) {
+	if _, err := q.LockCustomerLoyaltyPoints(ctx, db.LockCustomerLoyaltyPointsParams{
+		ShopCustomerID: customerID,
+		ShopID:         program.ShopID,
+	}); err != nil {
+		return 0, err
+	}
+	row, err := q.GetLoyaltyBalance(ctx, db.GetLoyaltyBalanceParams{
+		ShopCustomerID: customerID,
+		ShopID:         program.ShopID,
+	})
+	if err != nil {
+		return 0, err
+	}
+	if expiresAt := loyaltyExpiry(program, row); expiresAt == nil || time.Now().Before(*expiresAt) {
+		return row.Balance, nil
+	}
+	if _, err := q.CreateLoyaltyTransaction(ctx, db.CreateLoyaltyTransactionParams{
+		ShopCustomerID: customerID,
+		Kind:           db.LoyaltyTransactionKindExpire,
+		Points:         -row.Balance,
+		ShopID:         program.ShopID,
+	}); err != nil {
+		return 0, fmt.Errorf("failed to record loyalty points expiry: %w", err)
+	}
+	return 0, nil
+}
+
+// loyaltyExpiry returns when a positive balance expires, or nil when it does not
+func loyaltyExpiry(program db.LoyaltyProgram, row db.GetLoyaltyBalanceRow) *time.Time {
+	if program.ExpiryDays == nil || row.Balance <= 0 || !row.LastActivityAt.Valid {
+		return nil
+	}
+	expiresAt := row.LastActivityAt.Time.AddDate(0, 0, int(*program.ExpiryDays))
+	return &expiresAt
+}
+
+func numericRat(n pgtype.Numeric) *big.Rat {
+	if !n.Valid || n.Int == nil {
+		return new(big.Rat)
+	}
+	r := new(big.Rat).SetInt(n.Int)
+	scale := new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(abs32(n.Exp))), nil))
+	if n.Exp > 0 {
+		return r.Mul(r, scale)
+	}
+	return r.Quo(r, scale)
+}
+
+func clampPoints(n *big.Int) int32 {
+	if !n.IsInt64() || n.Int64() > math.MaxInt32 {
+		return math.MaxInt32
+	}
+	return int32(n.Int64())
+}
+
+func abs32(n int32) int32 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
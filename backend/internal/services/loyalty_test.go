@@ -0,0 +1,363 @@
+package services
+
+import (
+	"context"
+	"math"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/petrejonn/naytife/internal/db"
+	dberrors "github.com/petrejonn/naytife/internal/db/errors"
+	"github.com/petrejonn/naytife/internal/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func rate(n int64, exp int32) pgtype.Numeric {
+	return pgtype.Numeric{Int: big.NewInt(n), Exp: exp, Valid: true}
+}
+
+func TestLoyaltyPointsEarned(t *testing.T) {
+	tests := []struct {
+		name     string
+		earnRate pgtype.Numeric
+		spent    money.Money
+		want     int32
+	}{
+		{"one point per unit rounds down", rate(1, 0), money.New(12599, "USD"), 125},
+		{"half a point per unit", rate(5, -1), money.New(12599, "USD"), 62},
+		{"zero decimal currency", rate(1, 0), money.New(500, "JPY"), 500},
+		{"nothing spent", rate(1, 0), money.Zero("USD"), 0},
+		{"negative amount", rate(1, 0), money.New(-500, "USD"), 0},
+		{"clamped to the largest balance", rate(1, 0), money.New(math.MaxInt64, "JPY"), math.MaxInt32},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			program := db.LoyaltyProgram{EarnRate: tt.earnRate}
+			assert.Equal(t, tt.want, LoyaltyPointsEarned(program, tt.spent))
+		})
+	}
+}
+
+func TestLoyaltyPointsValue(t *testing.T) {
+	tests := []struct {
+		name     string
+		burnRate pgtype.Numeric
+		points   int32
+		currency string
+		want     int64
+	}{
+		{"hundred points per unit", rate(100, 0), 250, "USD", 250},
+		{"single point", rate(100, 0), 1, "USD", 1},
+		{"rounds down to the minor unit", rate(3, 0), 10, "USD", 333},
+		{"zero decimal currency rounds down", rate(100, 0), 250, "JPY", 2},
+		{"no points", rate(100, 0), 0, "USD", 0},
+		{"no burn rate", pgtype.Numeric{}, 250, "USD", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			program := db.LoyaltyProgram{BurnRate: tt.burnRate}
+			assert.Equal(t, money.New(tt.want, tt.currency), LoyaltyPointsValue(program, tt.points, tt.currency))
+		})
+	}
+}
+
+func TestCheckLoyaltyRedemption(t *testing.T) {
+	tests := []struct {
+		name    string
+		program db.LoyaltyProgram
+		points  int32
+		wantErr error
+	}{
+		{"enabled", db.LoyaltyProgram{Enabled: true, MinRedeemPoints: 100}, 100, nil},
+		{"disabled", db.LoyaltyProgram{MinRedeemPoints: 100}, 500, ErrLoyaltyDisabled},
+		{"below minimum", db.LoyaltyProgram{Enabled: true, MinRedeemPoints: 100}, 99, ErrBelowMinimumRedemption},
+		{"zero points", db.LoyaltyProgram{Enabled: true}, 0, ErrBelowMinimumRedemption},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckLoyaltyRedemption(tt.program, tt.points)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// fakePoints keeps the points ledger in memory. Like the check constraint, it
+// rejects entries that would overdraw the running balance.
+type fakePoints struct {
+	program *db.LoyaltyProgram
+	txns    []db.LoyaltyTransaction
+}
+
+func (f *fakePoints) GetLoyaltyProgram(ctx context.Context, shopID int64) (db.LoyaltyProgram, error) {
+	if f.program == nil {
+		return db.LoyaltyProgram{}, pgx.ErrNoRows
+	}
+	return *f.program, nil
+}
+
+func (f *fakePoints) LockCustomerLoyaltyPoints(ctx context.Context, arg db.LockCustomerLoyaltyPointsParams) (uuid.UUID, error) {
+	return arg.ShopCustomerID, nil
+}
+
+func (f *fakePoints) balance(customerID uuid.UUID) int32 {
+	var balance int32
+	for _, txn := range f.txns {
+		if txn.ShopCustomerID == customerID {
+			balance = txn.BalanceAfter
+		}
+	}
+	return balance
+}
+
+func (f *fakePoints) GetLoyaltyBalance(ctx context.Context, arg db.GetLoyaltyBalanceParams) (db.GetLoyaltyBalanceRow, error) {
+	row := db.GetLoyaltyBalanceRow{Balance: f.balance(arg.ShopCustomerID)}
+	for _, txn := range f.txns {
+		if txn.ShopCustomerID == arg.ShopCustomerID && txn.Kind != db.LoyaltyTransactionKindExpire {
+			row.LastActivityAt = txn.CreatedAt
+		}
+	}
+	return row, nil
+}
+
+func (f *fakePoints) CreateLoyaltyTransaction(ctx context.Context, arg db.CreateLoyaltyTransactionParams) (db.LoyaltyTransaction, error) {
+	balance := f.balance(arg.ShopCustomerID) + arg.Points
+	if balance < 0 {
+		return db.LoyaltyTransaction{}, &pgconn.PgError{Code: dberrors.CheckViolation}
+	}
+	txn := db.LoyaltyTransaction{
+		TransactionID:  int64(len(f.txns) + 1),
+		ShopCustomerID: arg.ShopCustomerID,
+		Kind:           arg.Kind,
+		Points:         arg.Points,
+		BalanceAfter:   balance,
+		OrderID:        arg.OrderID,
+		CreatedAt:      pgtype.Timestamptz{Time: time.Now(), Valid: true},
+		ShopID:         arg.ShopID,
+	}
+	f.txns = append(f.txns, txn)
+	return txn, nil
+}
+
+func (f *fakePoints) ListOrderLoyaltyPoints(ctx context.Context, arg db.ListOrderLoyaltyPointsParams) ([]db.ListOrderLoyaltyPointsRow, error) {
+	var rows []db.ListOrderLoyaltyPointsRow
+	index := map[uuid.UUID]int{}
+	for _, txn := range f.txns {
+		if txn.OrderID == nil || *txn.OrderID != arg.OrderID {
+			continue
+		}
+		i, ok := index[txn.ShopCustomerID]
+		if !ok {
+			i = len(rows)
+			index[txn.ShopCustomerID] = i
+			rows = append(rows, db.ListOrderLoyaltyPointsRow{ShopCustomerID: txn.ShopCustomerID})
+		}
+		switch txn.Kind {
+		case db.LoyaltyTransactionKindEarn, db.LoyaltyTransactionKindClawback:
+			rows[i].Earned += txn.Points
+		case db.LoyaltyTransactionKindRedeem, db.LoyaltyTransactionKindRefund:
+			rows[i].Redeemed -= txn.Points
+		}
+		rows[i].Awarded = rows[i].Awarded || txn.Kind == db.LoyaltyTransactionKindEarn
+	}
+	return rows, nil
+}
+
+func (f *fakePoints) kinds(customerID uuid.UUID) []db.LoyaltyTransactionKind {
+	var kinds []db.LoyaltyTransactionKind
+	for _, txn := range f.txns {
+		if txn.ShopCustomerID == customerID {
+			kinds = append(kinds, txn.Kind)
+		}
+	}
+	return kinds
+}
+
+func enabledProgram() *db.LoyaltyProgram {
+	return &db.LoyaltyProgram{ShopID: 1, Enabled: true, EarnRate: rate(1, 0), BurnRate: rate(100, 0), MinRedeemPoints: 10}
+}
+
+func TestAwardOrderLoyaltyPoints(t *testing.T) {
+	customerID := uuid.New()
+	order := db.Order{
+		OrderID:        7,
+		Amount:         money.New(12000, "USD").Numeric(),
+		ShippingCost:   money.New(1000, "USD").Numeric(),
+		Tax:            money.New(550, "USD").Numeric(),
+		ShopCustomerID: pgtype.UUID{Bytes: customerID, Valid: true},
+		ShopID:         1,
+	}
+	guestOrder := order
+	guestOrder.ShopCustomerID = pgtype.UUID{}
+
+	tests := []struct {
+		name    string
+		program *db.LoyaltyProgram
+		order   db.Order
+		want    int32
+	}{
+		{"merchandise earns points", enabledProgram(), order, 104},
+		{"guest orders earn nothing", enabledProgram(), guestOrder, 0},
+		{"disabled program", &db.LoyaltyProgram{ShopID: 1, EarnRate: rate(1, 0)}, order, 0},
+		{"shop without a program", nil, order, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &fakePoints{program: tt.program}
+			points, err := AwardOrderLoyaltyPoints(context.Background(), f, tt.order, "USD")
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, points)
+			assert.Equal(t, tt.want, f.balance(customerID))
+
+			// Every later status change leaves the award alone
+			again, err := AwardOrderLoyaltyPoints(context.Background(), f, tt.order, "USD")
+			require.NoError(t, err)
+			assert.Zero(t, again)
+			assert.Equal(t, tt.want, f.balance(customerID))
+		})
+	}
+}
+
+func TestRedeemLoyaltyPoints(t *testing.T) {
+	customerID := uuid.New()
+	order := db.Order{OrderID: 7, ShopCustomerID: pgtype.UUID{Bytes: customerID, Valid: true}, ShopID: 1}
+	guestOrder := db.Order{OrderID: 8, ShopID: 1}
+
+	tests := []struct {
+		name    string
+		order   db.Order
+		points  int32
+		wantErr error
+		want    int32
+	}{
+		{"redeems from the balance", order, 150, nil, 50},
+		{"whole balance", order, 200, nil, 0},
+		{"more than the balance", order, 201, ErrInsufficientPoints, 200},
+		{"below the minimum", order, 5, ErrBelowMinimumRedemption, 200},
+		{"guest order", guestOrder, 50, ErrInsufficientPoints, 200},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			program := enabledProgram()
+			f := &fakePoints{program: program}
+			_, err := AdjustLoyaltyPoints(context.Background(), f, *program, customerID, db.LoyaltyTransactionKindAdjust, 200, nil, nil, nil)
+			require.NoError(t, err)
+
+			_, err = RedeemLoyaltyPoints(context.Background(), f, *program, tt.order, tt.points)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, f.balance(customerID))
+		})
+	}
+}
+
+func TestReverseOrderLoyaltyPoints(t *testing.T) {
+	ctx := context.Background()
+	customerID := uuid.New()
+	program := enabledProgram()
+	order := db.Order{
+		OrderID:        7,
+		Amount:         money.New(10500, "USD").Numeric(),
+		ShopCustomerID: pgtype.UUID{Bytes: customerID, Valid: true},
+		ShopID:         1,
+	}
+	otherOrderID := int64(8)
+
+	t.Run("refunds redemptions and claws back earned points", func(t *testing.T) {
+		f := &fakePoints{program: program}
+		_, err := AdjustLoyaltyPoints(ctx, f, *program, customerID, db.LoyaltyTransactionKindAdjust, 100, nil, nil, nil)
+		require.NoError(t, err)
+		_, err = RedeemLoyaltyPoints(ctx, f, *program, order, 50)
+		require.NoError(t, err)
+		earned, err := AwardOrderLoyaltyPoints(ctx, f, order, "USD")
+		require.NoError(t, err)
+		require.Equal(t, int32(105), earned)
+		require.Equal(t, int32(155), f.balance(customerID))
+
+		require.NoError(t, ReverseOrderLoyaltyPoints(ctx, f, 1, order.OrderID))
+		assert.Equal(t, int32(100), f.balance(customerID))
+
+		// Reversing again changes nothing
+		require.NoError(t, ReverseOrderLoyaltyPoints(ctx, f, 1, order.OrderID))
+		assert.Equal(t, int32(100), f.balance(customerID))
+	})
+
+	t.Run("claws back only what is left of spent points", func(t *testing.T) {
+		f := &fakePoints{program: program}
+		_, err := AdjustLoyaltyPoints(ctx, f, *program, customerID, db.LoyaltyTransactionKindAdjust, 100, nil, nil, nil)
+		require.NoError(t, err)
+		_, err = RedeemLoyaltyPoints(ctx, f, *program, order, 50)
+		require.NoError(t, err)
+		_, err = AwardOrderLoyaltyPoints(ctx, f, order, "USD")
+		require.NoError(t, err)
+		// The customer spends most of the earned points on another order
+		_, err = RedeemLoyaltyPoints(ctx, f, *program, db.Order{OrderID: otherOrderID, ShopCustomerID: order.ShopCustomerID, ShopID: 1}, 130)
+		require.NoError(t, err)
+		require.Equal(t, int32(25), f.balance(customerID))
+
+		require.NoError(t, ReverseOrderLoyaltyPoints(ctx, f, 1, order.OrderID))
+		// 50 redeemed points come back and all 75 points left are clawed back
+		assert.Equal(t, int32(0), f.balance(customerID))
+		assert.Equal(t, []db.LoyaltyTransactionKind{
+			db.LoyaltyTransactionKindAdjust,
+			db.LoyaltyTransactionKindRedeem,
+			db.LoyaltyTransactionKindEarn,
+			db.LoyaltyTransactionKindRedeem,
+			db.LoyaltyTransactionKindRefund,
+			db.LoyaltyTransactionKindClawback,
+		}, f.kinds(customerID))
+
+		// The points the customer already spent stay unclawed
+		require.NoError(t, ReverseOrderLoyaltyPoints(ctx, f, 1, order.OrderID))
+		assert.Equal(t, int32(0), f.balance(customerID))
+		assert.Len(t, f.txns, 6)
+	})
+}
+
+func TestLoyaltyExpiry(t *testing.T) {
+	ctx := context.Background()
+	customerID := uuid.New()
+	expiryDays := int32(365)
+	program := enabledProgram()
+	program.ExpiryDays = &expiryDays
+
+	f := &fakePoints{program: program}
+	_, err := AdjustLoyaltyPoints(ctx, f, *program, customerID, db.LoyaltyTransactionKindAdjust, 300, nil, nil, nil)
+	require.NoError(t, err)
+
+	// Still within the expiry window
+	_, err = AdjustLoyaltyPoints(ctx, f, *program, customerID, db.LoyaltyTransactionKindEarn, 20, nil, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int32(320), f.balance(customerID))
+
+	// A year and a day without activity
+	for i := range f.txns {
+		f.txns[i].CreatedAt.Time = time.Now().AddDate(0, 0, -366)
+	}
+	_, err = AdjustLoyaltyPoints(ctx, f, *program, customerID, db.LoyaltyTransactionKindEarn, 40, nil, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int32(40), f.balance(customerID))
+	assert.Equal(t, []db.LoyaltyTransactionKind{
+		db.LoyaltyTransactionKindAdjust,
+		db.LoyaltyTransactionKindEarn,
+		db.LoyaltyTransactionKindExpire,
+		db.LoyaltyTransactionKindEarn,
+	}, f.kinds(customerID))
+}
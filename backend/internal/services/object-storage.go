@@ -0,0 +1,279 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// ObjectStorage stores uploaded files and returns the public URL they are served from
+type ObjectStorage interface {
+	Put(ctx context.Context, key string, body []byte, contentType string) (string, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// NewObjectStorage builds the storage backend selected by IMAGE_STORAGE_DRIVER.
+// "s3" uses the same R2 credentials and images bucket as the store-deployer, which
+// also works against MinIO or any other S3-compatible endpoint. "filesystem" writes
+// under IMAGE_STORAGE_PATH and is meant for local development and tests. When the
+// driver is not set, s3 is used if an endpoint is configured.
+func NewObjectStorage(ctx context.Context) (ObjectStorage, error) {
+	driver := strings.ToLower(strings.TrimSpace(os.Getenv("IMAGE_STORAGE_DRIVER")))
+	if driver == "" {
+		driver = "filesystem"
+		if os.Getenv("CLOUDFLARE_R2_ENDPOINT") != "" {
+			driver = "s3"
+		}
+	}
+
+	switch driver {
+	case "s3":
+		return NewS3ObjectStorage(ctx)
+	case "filesystem":
+		root := os.Getenv("IMAGE_STORAGE_PATH")
+		if root == "" {
+			root = "uploads"
+		}
+		publicURL := os.Getenv("IMAGE_STORAGE_PUBLIC_URL")
+		if publicURL == "" {
+			publicURL = strings.TrimRight(os.Getenv("API_URL"), "/") + "/uploads"
+		}
+		return NewFilesystemObjectStorage(root, publicURL), nil
+	default:
+		return nil, fmt.Errorf("unknown image storage driver %q", driver)
+	}
+}
+
+// DigitalAssetStorage stores the files of digital products. Unlike images they are
+// never served publicly: customers download them through signed links checked by
+// the API, which reads them back with Get.
+type DigitalAssetStorage interface {
+	ObjectStorage
+	// PutReader stores size bytes read from body, so that large files are not held in memory
+	PutReader(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+}
+
+// NewDigitalAssetStorage builds the storage backend selected by DIGITAL_STORAGE_DRIVER,
+// defaulting like NewObjectStorage. "s3" uses the private bucket named by
+// CLOUDFLARE_R2_DIGITAL_BUCKET_NAME. "filesystem" writes under DIGITAL_STORAGE_PATH,
+// which unlike the image directory is not served statically.
+func NewDigitalAssetStorage(ctx context.Context) (DigitalAssetStorage, error) {
+	driver := strings.ToLower(strings.TrimSpace(os.Getenv("DIGITAL_STORAGE_DRIVER")))
+	if driver == "" {
+		driver = "filesystem"
+		if os.Getenv("CLOUDFLARE_R2_ENDPOINT") != "" {
+			driver = "s3"
+		}
+	}
+
+	switch driver {
+	case "s3":
+		bucket := strings.TrimSpace(os.Getenv("CLOUDFLARE_R2_DIGITAL_BUCKET_NAME"))
+		if bucket == "" {
+			return nil, fmt.Errorf("missing R2 configuration for digital asset storage")
+		}
+		return newS3ObjectStorage(ctx, bucket, "", "private, no-store")
+	case "filesystem":
+		root := os.Getenv("DIGITAL_STORAGE_PATH")
+		if root == "" {
+			root = "digital"
+		}
+		return NewFilesystemObjectStorage(root, ""), nil
+	default:
+		return nil, fmt.Errorf("unknown digital storage driver %q", driver)
+	}
+}
+
+// S3ObjectStorage stores objects in an S3-compatible bucket
+type S3ObjectStorage struct {
+	Client       *s3.Client
+	Bucket       string
+	PublicURL    string
+	CacheControl string
+}
+
+func NewS3ObjectStorage(ctx context.Context) (*S3ObjectStorage, error) {
+	bucket := strings.TrimSpace(os.Getenv("CLOUDFLARE_R2_IMAGES_BUCKET_NAME"))
+	if bucket == "" {
+		return nil, fmt.Errorf("missing R2 configuration for image storage")
+	}
+	return newS3ObjectStorage(ctx, bucket, strings.TrimSpace(os.Getenv("IMAGE_STORAGE_PUBLIC_URL")), "public, max-age=31536000, immutable")
+}
+
+func newS3ObjectStorage(ctx context.Context, bucket, publicURL, cacheControl string) (*S3ObjectStorage, error) {
+	accessKey := strings.TrimSpace(os.Getenv("CLOUDFLARE_R2_ACCESS_KEY_ID"))
+	secretKey := strings.TrimSpace(os.Getenv("CLOUDFLARE_R2_SECRET_ACCESS_KEY"))
+	endpoint := strings.TrimSpace(os.Getenv("CLOUDFLARE_R2_ENDPOINT"))
+	if accessKey == "" || secretKey == "" || endpoint == "" {
+		return nil, fmt.Errorf("missing R2 configuration for bucket %s", bucket)
+	}
+	if publicURL == "" {
+		publicURL = strings.TrimRight(endpoint, "/") + "/" + bucket
+	}
+
+	region := os.Getenv("IMAGE_STORAGE_REGION")
+	if region == "" {
+		region = "auto"
+	}
+	cfg, err := config.LoadDefaultConfig(ctx,
+		config.WithRegion(region),
+		config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(accessKey, secretKey, "")),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("load s3 config: %w", err)
+	}
+
+	client := s3.NewFromConfig(cfg, func(o *s3.Options) {
+		o.BaseEndpoint = aws.String(endpoint)
+		o.UsePathStyle = true
+	})
+	return &S3ObjectStorage{
+		Client:       client,
+		Bucket:       bucket,
+		PublicURL:    strings.TrimRight(publicURL, "/"),
+		CacheControl: cacheControl,
+	}, nil
+}
+
+func (s *S3ObjectStorage) Put(ctx context.Context, key string, body []byte, contentType string) (string, error) {
+	_, err := s.Client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:       aws.String(s.Bucket),
+		Key:          aws.String(key),
+		Body:         bytes.NewReader(body),
+		ContentType:  aws.String(contentType),
+		CacheControl: aws.String(s.CacheControl),
+	})
+	if err != nil {
+		return "", fmt.Errorf("put object %s: %w", key, err)
+	}
+	return s.PublicURL + "/" + key, nil
+}
+
+func (s *S3ObjectStorage) PutReader(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	_, err := s.Client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:        aws.String(s.Bucket),
+		Key:           aws.String(key),
+		Body:          body,
+		ContentLength: aws.Int64(size),
+		ContentType:   aws.String(contentType),
+		CacheControl:  aws.String(s.CacheControl),
+	})
+	if err != nil {
+		return fmt.Errorf("put object %s: %w", key, err)
+	}
+	return nil
+}
+
+func (s *S3ObjectStorage) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	out, err := s.Client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.Bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("get object %s: %w", key, err)
+	}
+	return out.Body, nil
+}
+
+func (s *S3ObjectStorage) Delete(ctx context.Context, key string) error {
+	_, err := s.Client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s.Bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return fmt.Errorf("delete object %s: %w", key, err)
+	}
+	return nil
+}
+
+// FilesystemObjectStorage stores objects on the local disk
+type FilesystemObjectStorage struct {
+	Root      string
+	PublicURL string
+}
+
+func NewFilesystemObjectStorage(root, publicURL string) *FilesystemObjectStorage {
+	return &FilesystemObjectStorage{
+		Root:      root,
+		PublicURL: strings.TrimRight(publicURL, "/"),
+	}
+}
+
+func (s *FilesystemObjectStorage) path(key string) (string, error) {
+	clean := filepath.Clean("/" + key)
+	if clean == "/" {
+		return "", fmt.Errorf("invalid object key %q", key)
+	}
+	return filepath.Join(s.Root, clean), nil
+}
+
+func (s *FilesystemObjectStorage) Put(ctx context.Context, key string, body []byte, contentType string) (string, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", fmt.Errorf("create directory for %s: %w", key, err)
+	}
+	if err := os.WriteFile(path, body, 0o644); err != nil {
+		return "", fmt.Errorf("write object %s: %w", key, err)
+	}
+	return s.PublicURL + "/" + key, nil
+}
+
+func (s *FilesystemObjectStorage) PutReader(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create directory for %s: %w", key, err)
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("create object %s: %w", key, err)
+	}
+	if _, err := io.Copy(file, io.LimitReader(body, size)); err != nil {
+		file.Close()
+		os.Remove(path)
+		return fmt.Errorf("write object %s: %w", key, err)
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(path)
+		return fmt.Errorf("write object %s: %w", key, err)
+	}
+	return nil
+}
+
+func (s *FilesystemObjectStorage) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open object %s: %w", key, err)
+	}
+	return file, nil
+}
+
+func (s *FilesystemObjectStorage) Delete(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("delete object %s: %w", key, err)
+	}
+	return nil
+}
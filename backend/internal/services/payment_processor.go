@@ -2,16 +2,22 @@ package services
 
 import (
 	"context"
+	"errors"
 
 	"github.com/petrejonn/naytife/internal/api/models"
+	"github.com/petrejonn/naytife/internal/money"
 )
 
+// ErrRecurringPaymentsUnsupported is returned by processors that cannot save or charge payment methods
+var ErrRecurringPaymentsUnsupported = errors.New("payment provider does not support saved payment methods")
+
 // PaymentProcessor defines the interface that all payment service providers must implement
 type PaymentProcessor interface {
 	// ProcessPayment processes a payment request and returns the payment response
-	ProcessPayment(ctx context.Context, shopID int64, req models.PaymentRequest, amount float64, currencyCode string) (*models.PaymentResponse, error)
+	ProcessPayment(ctx context.Context, shopID int64, req models.PaymentRequest, amount money.Money) (*models.PaymentResponse, error)
 
-	// CreatePaymentIntent creates a payment intent for deferred payment processing
+	// CreatePaymentIntent creates a payment intent for deferred payment processing.
+	// req.Amount must already be in req.CurrencyCode.
 	CreatePaymentIntent(ctx context.Context, shopID int64, req models.PaymentIntentRequest) (*models.PaymentIntentResponse, error)
 
 	// ConfirmPayment confirms a payment intent or transaction
@@ -21,11 +27,17 @@ type PaymentProcessor interface {
 	GetPaymentStatus(ctx context.Context, shopID int64, paymentID string) (*models.PaymentResponse, error)
 
 	// RefundPayment processes a refund for a completed payment
-	RefundPayment(ctx context.Context, shopID int64, paymentID string, amount float64, reason string) (*models.PaymentResponse, error)
+	RefundPayment(ctx context.Context, shopID int64, paymentID string, amount money.Money, reason string) (*models.PaymentResponse, error)
 
 	// HandleWebhook processes webhook events from the payment provider
 	HandleWebhook(ctx context.Context, payload []byte, signature string) (*models.PaymentWebhookPayload, error)
 
+	// SavePaymentMethod saves a payment method with the provider so that it can be charged later
+	SavePaymentMethod(ctx context.Context, shopID int64, req models.SavePaymentMethodRequest) (*models.SavedPaymentMethod, error)
+
+	// ChargeSavedPaymentMethod charges a saved payment method without the customer present
+	ChargeSavedPaymentMethod(ctx context.Context, shopID int64, req models.SavedPaymentChargeRequest) (*models.PaymentResponse, error)
+
 	// ValidateConfig validates the payment provider configuration
 	ValidateConfig(config map[string]interface{}) error
 }
@@ -12,6 +12,7 @@ import (
 
 	"github.com/petrejonn/naytife/internal/api/models"
 	"github.com/petrejonn/naytife/internal/db"
+	"github.com/petrejonn/naytife/internal/money"
 
 	retryablehttp "github.com/hashicorp/go-retryablehttp"
 	"github.com/petrejonn/naytife/internal/observability"
@@ -184,7 +185,7 @@ func (p *PayPalService) ValidateConfig(config map[string]interface{}) error {
 }
 
 // ProcessPayment processes a payment request and returns the payment response
-func (p *PayPalService) ProcessPayment(ctx context.Context, shopID int64, req models.PaymentRequest, amount float64, currencyCode string) (*models.PaymentResponse, error) {
+func (p *PayPalService) ProcessPayment(ctx context.Context, shopID int64, req models.PaymentRequest, amount money.Money) (*models.PaymentResponse, error) {
 	config, err := p.GetPayPalConfig(ctx, shopID)
 	if err != nil {
 		return nil, err
@@ -202,8 +203,8 @@ func (p *PayPalService) ProcessPayment(ctx context.Context, shopID int64, req mo
 		PurchaseUnits: []PayPalPurchaseUnit{
 			{
 				Amount: PayPalAmount{
-					CurrencyCode: currencyCode,
-					Value:        fmt.Sprintf("%.2f", amount),
+					CurrencyCode: amount.Currency,
+					Value:        amount.String(),
 				},
 				Description: "Payment for order",
 				CustomID:    req.CheckoutSessionID,
@@ -239,7 +240,7 @@ func (p *PayPalService) ProcessPayment(ctx context.Context, shopID int64, req mo
 		PaymentID:     orderResp.ID,
 		Status:        status,
 		Amount:        amount,
-		CurrencyCode:  currencyCode,
+		CurrencyCode:  amount.Currency,
 		PaymentMethod: "paypal",
 		TransactionID: orderResp.ID,
 	}
@@ -286,8 +287,8 @@ func (p *PayPalService) CreatePaymentIntent(ctx context.Context, shopID int64, r
 		PurchaseUnits: []PayPalPurchaseUnit{
 			{
 				Amount: PayPalAmount{
-					CurrencyCode: req.CurrencyCode,
-					Value:        fmt.Sprintf("%.2f", req.Amount),
+					CurrencyCode: req.Amount.Currency,
+					Value:        req.Amount.String(),
 				},
 				Description: req.Description,
 				CustomID:    req.CheckoutSessionID,
@@ -308,7 +309,7 @@ func (p *PayPalService) CreatePaymentIntent(ctx context.Context, shopID int64, r
 		PaymentIntentID: orderResp.ID,
 		Status:          p.ConvertPayPalStatusToPaymentStatus(orderResp.Status),
 		Amount:          req.Amount,
-		CurrencyCode:    req.CurrencyCode,
+		CurrencyCode:    req.Amount.Currency,
 	}
 
 	// Add next action for approval
@@ -346,16 +347,13 @@ func (p *PayPalService) ConfirmPayment(ctx context.Context, shopID int64, paymen
 		return nil, err
 	}
 
-	var amount float64
-	if captureResp.Amount != nil {
-		amount, _ = strconv.ParseFloat(captureResp.Amount.Value, 64)
-	}
+	amount := payPalAmountToMoney(captureResp.Amount)
 
 	response := &models.PaymentResponse{
 		PaymentID:     captureResp.ID,
 		Status:        p.ConvertPayPalStatusToPaymentStatus(captureResp.Status),
 		Amount:        amount,
-		CurrencyCode:  captureResp.Amount.CurrencyCode,
+		CurrencyCode:  amount.Currency,
 		PaymentMethod: "paypal",
 		TransactionID: captureResp.ID,
 	}
@@ -387,16 +385,13 @@ func (p *PayPalService) GetPaymentStatus(ctx context.Context, shopID int64, paym
 		return nil, err
 	}
 
-	var amount float64
-	if orderResp.Amount != nil {
-		amount, _ = strconv.ParseFloat(orderResp.Amount.Value, 64)
-	}
+	amount := payPalAmountToMoney(orderResp.Amount)
 
 	response := &models.PaymentResponse{
 		PaymentID:     orderResp.ID,
 		Status:        p.ConvertPayPalStatusToPaymentStatus(orderResp.Status),
 		Amount:        amount,
-		CurrencyCode:  orderResp.Amount.CurrencyCode,
+		CurrencyCode:  amount.Currency,
 		PaymentMethod: "paypal",
 		TransactionID: orderResp.ID,
 	}
@@ -410,13 +405,14 @@ func (p *PayPalService) GetPaymentStatus(ctx context.Context, shopID int64, paym
 }
 
 // RefundPayment processes a refund for a completed payment
-func (p *PayPalService) RefundPayment(ctx context.Context, shopID int64, paymentID string, amount float64, reason string) (*models.PaymentResponse, error) {
+func (p *PayPalService) RefundPayment(ctx context.Context, shopID int64, paymentID string, amount money.Money, reason string) (*models.PaymentResponse, error) {
 	// PayPal refunds require the capture ID, not the order ID
 	// This is a simplified implementation
 	return &models.PaymentResponse{
 		PaymentID:     paymentID,
 		Status:        "refund_pending",
 		Amount:        amount,
+		CurrencyCode:  amount.Currency,
 		PaymentMethod: "paypal",
 		PaymentDetails: map[string]interface{}{
 			"message": "PayPal refunds require additional implementation with capture IDs",
@@ -452,14 +448,14 @@ func (p *PayPalService) HandleWebhook(ctx context.Context, payload []byte, signa
 			webhookPayload.Status = p.ConvertPayPalStatusToPaymentStatus(status)
 		}
 		if amount, exists := resource["amount"].(map[string]interface{}); exists {
-			if value, ok := amount["value"].(string); ok {
-				if amountFloat, err := strconv.ParseFloat(value, 64); err == nil {
-					webhookPayload.Amount = amountFloat
-				}
-			}
 			if currency, ok := amount["currency_code"].(string); ok {
 				webhookPayload.Currency = currency
 			}
+			if value, ok := amount["value"].(string); ok {
+				if parsed, err := money.Parse(value, webhookPayload.Currency); err == nil {
+					webhookPayload.Amount = parsed
+				}
+			}
 		}
 	}
 
@@ -468,6 +464,19 @@ func (p *PayPalService) HandleWebhook(ctx context.Context, payload []byte, signa
 
 // Helper methods
 
+// payPalAmountToMoney converts a PayPal amount, whose value is a decimal string in
+// major units, returning zero when the amount is missing or malformed
+func payPalAmountToMoney(amount *PayPalAmount) money.Money {
+	if amount == nil {
+		return money.Money{}
+	}
+	m, err := money.Parse(amount.Value, amount.CurrencyCode)
+	if err != nil {
+		return money.Zero(amount.CurrencyCode)
+	}
+	return m
+}
+
 func (p *PayPalService) createPayPalOrder(ctx context.Context, config *PayPalConfig, accessToken string, orderReq *PayPalOrderRequest) (*PayPalOrderResponse, error) {
 	baseURL := p.GetPayPalBaseURL(config)
 
@@ -608,3 +617,13 @@ func (p *PayPalService) ConvertPayPalStatusToPaymentStatus(status string) string
 		return "pending"
 	}
 }
+
+// SavePaymentMethod is not supported; PayPal payments cannot be charged off session here
+func (p *PayPalService) SavePaymentMethod(ctx context.Context, shopID int64, req models.SavePaymentMethodRequest) (*models.SavedPaymentMethod, error) {
+	return nil, ErrRecurringPaymentsUnsupported
+}
+
+// ChargeSavedPaymentMethod is not supported; PayPal payments cannot be charged off session here
+func (p *PayPalService) ChargeSavedPaymentMethod(ctx context.Context, shopID int64, req models.SavedPaymentChargeRequest) (*models.PaymentResponse, error) {
+	return nil, ErrRecurringPaymentsUnsupported
+}
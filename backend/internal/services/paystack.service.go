@@ -13,6 +13,7 @@ import (
 
 	"github.com/petrejonn/naytife/internal/api/models"
 	"github.com/petrejonn/naytife/internal/db"
+	"github.com/petrejonn/naytife/internal/money"
 
 	retryablehttp "github.com/hashicorp/go-retryablehttp"
 	"github.com/petrejonn/naytife/internal/observability"
@@ -67,9 +68,30 @@ type PaystackVerifyResponse struct {
 		Customer        struct {
 			Email string `json:"email"`
 		} `json:"customer"`
+		Authorization PaystackAuthorization `json:"authorization"`
 	} `json:"data"`
 }
 
+// PaystackAuthorization is the card a transaction was paid with. Reusable
+// authorizations can be charged again with their authorization code.
+type PaystackAuthorization struct {
+	AuthorizationCode string `json:"authorization_code"`
+	CardType          string `json:"card_type"`
+	Last4             string `json:"last4"`
+	ExpMonth          string `json:"exp_month"`
+	ExpYear           string `json:"exp_year"`
+	Reusable          bool   `json:"reusable"`
+}
+
+type PaystackChargeAuthorizationRequest struct {
+	Email             string            `json:"email"`
+	Amount            string            `json:"amount"` // Amount in kobo
+	Currency          string            `json:"currency,omitempty"`
+	AuthorizationCode string            `json:"authorization_code"`
+	Reference         string            `json:"reference,omitempty"`
+	Metadata          map[string]string `json:"metadata,omitempty"`
+}
+
 type PaystackRefundRequest struct {
 	Transaction string `json:"transaction"`
 	Amount      int64  `json:"amount,omitempty"` // Amount in kobo
@@ -92,6 +114,7 @@ type PaystackRefundResponse struct {
 			Amount int64 `json:"amount"`
 		} `json:"deducted"`
 		Channel    string `json:"channel"`
+		Currency   string `json:"currency"`
 		FullRefund bool   `json:"full_refund"`
 	} `json:"data"`
 }
@@ -217,7 +240,7 @@ func (p *PaystackService) ConvertPaystackStatusToPaymentStatus(status string) st
 }
 
 // ProcessPayment processes a payment request and returns the payment response
-func (p *PaystackService) ProcessPayment(ctx context.Context, shopID int64, req models.PaymentRequest, amount float64, currencyCode string) (*models.PaymentResponse, error) {
+func (p *PaystackService) ProcessPayment(ctx context.Context, shopID int64, req models.PaymentRequest, amount money.Money) (*models.PaymentResponse, error) {
 	config, err := p.GetPaystackConfig(ctx, shopID)
 	if err != nil {
 		return nil, err
@@ -225,15 +248,11 @@ func (p *PaystackService) ProcessPayment(ctx context.Context, shopID int64, req
 
 	baseURL := p.getBaseURL(config.TestMode)
 
-	// Convert amount to kobo (smallest currency unit for NGN)
-	// For other currencies, we might need different conversion logic
-	amountKobo := int64(amount * 100)
-
-	// Create transaction initialization request
+	// Paystack takes amounts in the currency's subunit (kobo for NGN)
 	initReq := PaystackInitializeRequest{
 		Email:    "customer@example.com", // TODO: Get customer email from checkout session
-		Amount:   strconv.FormatInt(amountKobo, 10),
-		Currency: currencyCode,
+		Amount:   strconv.FormatInt(amount.Amount, 10),
+		Currency: amount.Currency,
 		Metadata: map[string]interface{}{
 			"shop_id":             strconv.FormatInt(shopID, 10),
 			"checkout_session_id": req.CheckoutSessionID,
@@ -289,7 +308,7 @@ func (p *PaystackService) ProcessPayment(ctx context.Context, shopID int64, req
 		PaymentID:     initResp.Data.Reference,
 		Status:        "pending",
 		Amount:        amount,
-		CurrencyCode:  currencyCode,
+		CurrencyCode:  amount.Currency,
 		PaymentMethod: "paystack",
 		TransactionID: initResp.Data.Reference,
 		NextAction: &models.PaymentNextAction{
@@ -312,14 +331,11 @@ func (p *PaystackService) CreatePaymentIntent(ctx context.Context, shopID int64,
 
 	baseURL := p.getBaseURL(config.TestMode)
 
-	// Convert amount to kobo
-	amountKobo := int64(req.Amount * 100)
-
-	// Create transaction initialization request
+	// Paystack takes amounts in the currency's subunit (kobo for NGN)
 	initReq := PaystackInitializeRequest{
 		Email:    "customer@example.com", // TODO: Get customer email from checkout session
-		Amount:   strconv.FormatInt(amountKobo, 10),
-		Currency: req.CurrencyCode,
+		Amount:   strconv.FormatInt(req.Amount.Amount, 10),
+		Currency: req.Amount.Currency,
 		Metadata: map[string]interface{}{
 			"shop_id":             strconv.FormatInt(shopID, 10),
 			"checkout_session_id": req.CheckoutSessionID,
@@ -376,7 +392,7 @@ func (p *PaystackService) CreatePaymentIntent(ctx context.Context, shopID int64,
 		ClientSecret:    initResp.Data.AccessCode,
 		Status:          "pending",
 		Amount:          req.Amount,
-		CurrencyCode:    req.CurrencyCode,
+		CurrencyCode:    req.Amount.Currency,
 		NextAction: &models.PaymentNextAction{
 			Type: "redirect",
 			Data: map[string]interface{}{
@@ -401,48 +417,15 @@ func (p *PaystackService) GetPaymentStatus(ctx context.Context, shopID int64, pa
 		return nil, err
 	}
 
-	baseURL := p.getBaseURL(config.TestMode)
-
-	// Make request to verify transaction
-	headers := map[string]string{
-		"Authorization": "Bearer " + config.SecretKey,
-	}
-
-	resp, err := p.makePaystackRequest(ctx, "GET", baseURL+"/transaction/verify/"+paymentID, headers, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to verify transaction: %w", err)
-	}
-	defer resp.Body.Close()
-
-	respBody, err := io.ReadAll(resp.Body)
+	verifyResp, err := p.verifyTransaction(ctx, config, paymentID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		var errorResp PaystackErrorResponse
-		if jsonErr := json.Unmarshal(respBody, &errorResp); jsonErr == nil {
-			return nil, fmt.Errorf("paystack api error: %s", errorResp.Message)
-		}
-		return nil, fmt.Errorf("paystack api error: status %d", resp.StatusCode)
-	}
-
-	var verifyResp PaystackVerifyResponse
-	if err := json.Unmarshal(respBody, &verifyResp); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
-	}
-
-	if !verifyResp.Status {
-		return nil, fmt.Errorf("failed to verify transaction: %s", verifyResp.Message)
+		return nil, err
 	}
 
-	// Convert amount from kobo to main currency unit
-	amount := float64(verifyResp.Data.Amount) / 100
-
 	response := &models.PaymentResponse{
 		PaymentID:     verifyResp.Data.Reference,
 		Status:        p.ConvertPaystackStatusToPaymentStatus(verifyResp.Data.Status),
-		Amount:        amount,
+		Amount:        money.New(verifyResp.Data.Amount, verifyResp.Data.Currency),
 		CurrencyCode:  verifyResp.Data.Currency,
 		PaymentMethod: "paystack",
 		TransactionID: verifyResp.Data.Reference,
@@ -459,7 +442,7 @@ func (p *PaystackService) GetPaymentStatus(ctx context.Context, shopID int64, pa
 }
 
 // RefundPayment processes a refund for a completed payment
-func (p *PaystackService) RefundPayment(ctx context.Context, shopID int64, paymentID string, amount float64, reason string) (*models.PaymentResponse, error) {
+func (p *PaystackService) RefundPayment(ctx context.Context, shopID int64, paymentID string, amount money.Money, reason string) (*models.PaymentResponse, error) {
 	config, err := p.GetPaystackConfig(ctx, shopID)
 	if err != nil {
 		return nil, err
@@ -467,19 +450,13 @@ func (p *PaystackService) RefundPayment(ctx context.Context, shopID int64, payme
 
 	baseURL := p.getBaseURL(config.TestMode)
 
-	// Convert amount to kobo (if amount is provided, otherwise do full refund)
-	var amountKobo int64
-	if amount > 0 {
-		amountKobo = int64(amount * 100)
-	}
-
-	// Create refund request
+	// Create refund request, refunding the full transaction when no amount is given
 	refundReq := PaystackRefundRequest{
 		Transaction: paymentID,
 	}
 
-	if amountKobo > 0 {
-		refundReq.Amount = amountKobo
+	if amount.Amount > 0 {
+		refundReq.Amount = amount.Amount
 	}
 
 	refundReqJSON, err := json.Marshal(refundReq)
@@ -520,15 +497,17 @@ func (p *PaystackService) RefundPayment(ctx context.Context, shopID int64, payme
 		return nil, fmt.Errorf("failed to create refund: %s", refundResp.Message)
 	}
 
-	// Convert amount from kobo to main currency unit
-	refundAmount := float64(refundResp.Data.Deducted.Amount) / 100
+	currencyCode := refundResp.Data.Currency
+	if currencyCode == "" {
+		currencyCode = "NGN" // Paystack primarily deals with NGN
+	}
 	now := time.Now()
 
 	response := &models.PaymentResponse{
 		PaymentID:     paymentID,
 		Status:        "refunded",
-		Amount:        refundAmount,
-		CurrencyCode:  "NGN", // Paystack primarily deals with NGN
+		Amount:        money.New(refundResp.Data.Deducted.Amount, currencyCode),
+		CurrencyCode:  currencyCode,
 		PaymentMethod: "paystack",
 		TransactionID: refundResp.Data.Transaction.Reference,
 		ProcessedAt:   now.Format(time.RFC3339),
@@ -573,12 +552,13 @@ func (p *PaystackService) HandleWebhook(ctx context.Context, payload []byte, sig
 				webhookPayload.PaymentID = reference
 				webhookPayload.TransactionID = reference
 			}
-			if amount, exists := data["amount"].(float64); exists {
-				webhookPayload.Amount = amount / 100 // Convert from kobo
-			}
 			if currency, exists := data["currency"].(string); exists {
 				webhookPayload.Currency = currency
 			}
+			if amount, exists := data["amount"].(float64); exists {
+				// Paystack reports amounts in the currency's subunit
+				webhookPayload.Amount = money.New(int64(amount), webhookPayload.Currency)
+			}
 			if status, exists := data["status"].(string); exists {
 				webhookPayload.Status = p.ConvertPaystackStatusToPaymentStatus(status)
 			}
@@ -590,3 +570,158 @@ func (p *PaystackService) HandleWebhook(ctx context.Context, payload []byte, sig
 
 	return webhookPayload, nil
 }
+
+// verifyTransaction fetches a transaction by its reference
+func (p *PaystackService) verifyTransaction(ctx context.Context, config *PaystackConfig, reference string) (*PaystackVerifyResponse, error) {
+	baseURL := p.getBaseURL(config.TestMode)
+
+	// Make request to verify transaction
+	headers := map[string]string{
+		"Authorization": "Bearer " + config.SecretKey,
+	}
+
+	resp, err := p.makePaystackRequest(ctx, "GET", baseURL+"/transaction/verify/"+reference, headers, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify transaction: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var errorResp PaystackErrorResponse
+		if jsonErr := json.Unmarshal(respBody, &errorResp); jsonErr == nil {
+			return nil, fmt.Errorf("paystack api error: %s", errorResp.Message)
+		}
+		return nil, fmt.Errorf("paystack api error: status %d", resp.StatusCode)
+	}
+
+	var verifyResp PaystackVerifyResponse
+	if err := json.Unmarshal(respBody, &verifyResp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	if !verifyResp.Status {
+		return nil, fmt.Errorf("failed to verify transaction: %s", verifyResp.Message)
+	}
+
+	return &verifyResp, nil
+}
+
+// SavePaymentMethod saves the card of a successful Paystack transaction. req.Token is the
+// transaction reference; the card's authorization code is what later charges use.
+func (p *PaystackService) SavePaymentMethod(ctx context.Context, shopID int64, req models.SavePaymentMethodRequest) (*models.SavedPaymentMethod, error) {
+	config, err := p.GetPaystackConfig(ctx, shopID)
+	if err != nil {
+		return nil, err
+	}
+
+	verifyResp, err := p.verifyTransaction(ctx, config, req.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	if verifyResp.Data.Status != "success" {
+		return nil, fmt.Errorf("transaction %s was not successful", req.Token)
+	}
+	if !strings.EqualFold(verifyResp.Data.Customer.Email, req.Email) {
+		return nil, fmt.Errorf("transaction %s was paid by another customer", req.Token)
+	}
+	authorization := verifyResp.Data.Authorization
+	if !authorization.Reusable || authorization.AuthorizationCode == "" {
+		return nil, fmt.Errorf("the card used for transaction %s cannot be charged again", req.Token)
+	}
+
+	saved := &models.SavedPaymentMethod{
+		CustomerReference: verifyResp.Data.Customer.Email,
+		Token:             authorization.AuthorizationCode,
+		Brand:             strings.TrimSpace(authorization.CardType),
+		Last4:             authorization.Last4,
+	}
+	if month, err := strconv.Atoi(authorization.ExpMonth); err == nil {
+		saved.ExpMonth = int32(month)
+	}
+	if year, err := strconv.Atoi(authorization.ExpYear); err == nil {
+		saved.ExpYear = int32(year)
+	}
+
+	return saved, nil
+}
+
+// ChargeSavedPaymentMethod charges a saved card by its authorization code. The
+// idempotency key is used as the transaction reference, which Paystack keeps unique.
+func (p *PaystackService) ChargeSavedPaymentMethod(ctx context.Context, shopID int64, req models.SavedPaymentChargeRequest) (*models.PaymentResponse, error) {
+	config, err := p.GetPaystackConfig(ctx, shopID)
+	if err != nil {
+		return nil, err
+	}
+
+	baseURL := p.getBaseURL(config.TestMode)
+
+	chargeReq := PaystackChargeAuthorizationRequest{
+		Email:             req.CustomerReference,
+		Amount:            strconv.FormatInt(req.Amount.Amount, 10),
+		Currency:          req.Amount.Currency,
+		AuthorizationCode: req.Token,
+		Reference:         req.IdempotencyKey,
+		Metadata:          req.Metadata,
+	}
+
+	chargeReqJSON, err := json.Marshal(chargeReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal charge request: %w", err)
+	}
+
+	headers := map[string]string{
+		"Authorization": "Bearer " + config.SecretKey,
+	}
+
+	resp, err := p.makePaystackRequest(ctx, "POST", baseURL+"/transaction/charge_authorization", headers, bytes.NewBuffer(chargeReqJSON))
+	if err != nil {
+		return nil, fmt.Errorf("failed to charge authorization: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var errorResp PaystackErrorResponse
+		if jsonErr := json.Unmarshal(respBody, &errorResp); jsonErr == nil {
+			return nil, fmt.Errorf("paystack api error: %s", errorResp.Message)
+		}
+		return nil, fmt.Errorf("paystack api error: status %d", resp.StatusCode)
+	}
+
+	var chargeResp PaystackVerifyResponse
+	if err := json.Unmarshal(respBody, &chargeResp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	if !chargeResp.Status {
+		return nil, fmt.Errorf("failed to charge authorization: %s", chargeResp.Message)
+	}
+
+	response := &models.PaymentResponse{
+		PaymentID:     chargeResp.Data.Reference,
+		Status:        p.ConvertPaystackStatusToPaymentStatus(chargeResp.Data.Status),
+		Amount:        money.New(chargeResp.Data.Amount, chargeResp.Data.Currency),
+		CurrencyCode:  chargeResp.Data.Currency,
+		PaymentMethod: "paystack",
+		TransactionID: chargeResp.Data.Reference,
+		PaymentDetails: map[string]interface{}{
+			"gateway_response": chargeResp.Data.GatewayResponse,
+		},
+	}
+
+	if chargeResp.Data.Status == "success" {
+		response.ProcessedAt = time.Now().Format(time.RFC3339)
+	}
+
+	return response, nil
+}
@@ -121,6 +121,23 @@ func (s *PredefinedProductTypeService) GetPredefinedProductTypes() []models.Pred
 				}},
 			},
 		},
+		{
+			ID:           "gift_card",
+			Title:        "Gift Card",
+			Description:  "Gift cards emailed to the buyer with a code that pays for future orders",
+			SkuSubstring: "GIFT",
+			Shippable:    false,
+			Digital:      true,
+			GiftCard:     true,
+			Category:     "Gift Cards",
+			Icon:         "🎁",
+			Attributes: []models.PredefinedAttributeTemplate{
+				// Variant Attributes
+				{Title: "Design", DataType: "Option", Required: false, AppliesTo: "ProductVariation", Options: []models.PredefinedAttributeOption{
+					{Value: "Classic"}, {Value: "Birthday"}, {Value: "Holiday"}, {Value: "Thank You"},
+				}},
+			},
+		},
 		{
 			ID:           "electronics",
 			Title:        "Electronics",
@@ -0,0 +1,158 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/petrejonn/naytife/internal/db"
+)
+
+var (
+	ErrPurchaseOrderNotReceivable = errors.New("purchase order is not awaiting stock")
+	ErrPurchaseOrderReceived      = errors.New("purchase order has been received")
+	ErrPurchaseOrderItemNotFound  = errors.New("purchase order item not found")
+	ErrPurchaseOrderOverReceipt   = errors.New("more received than was ordered")
+)
+
+// PurchaseOrderLine is a quantity of a variant ordered from a supplier at a unit cost
+type PurchaseOrderLine struct {
+	VariantID int64
+	Quantity  int64
+	UnitCost  pgtype.Numeric
+}
+
+// PurchaseOrderReceipt is a quantity of a purchase order item that arrived, and the
+// lot it arrived in
+type PurchaseOrderReceipt struct {
+	ItemID   int64
+	Quantity int64
+	Lot      *StockLot
+}
+
+// SetPurchaseOrderItems replaces the items of a purchase order. It fails with
+// pgx.ErrNoRows when a variant does not belong to the shop.
+func SetPurchaseOrderItems(ctx context.Context, q *db.Queries, order db.PurchaseOrder, lines []PurchaseOrderLine) error {
+	if err := q.DeletePurchaseOrderItems(ctx, db.DeletePurchaseOrderItemsParams{
+		PurchaseOrderID: order.PurchaseOrderID,
+		ShopID:          order.ShopID,
+	}); err != nil {
+		return err
+	}
+	for _, line := range lines {
+		if _, err := q.GetProductVariation(ctx, db.GetProductVariationParams{
+			ProductVariationID: line.VariantID,
+			ShopID:             order.ShopID,
+		}); err != nil {
+			return err
+		}
+		if _, err := q.CreatePurchaseOrderItem(ctx, db.CreatePurchaseOrderItemParams{
+			PurchaseOrderID:    order.PurchaseOrderID,
+			ProductVariationID: line.VariantID,
+			QuantityOrdered:    line.Quantity,
+			UnitCost:           line.UnitCost,
+			ShopID:             order.ShopID,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReceivePurchaseOrder adds the received items of a purchase order to the stock of
+// the location it is delivered to, the default location when it has none. Without
+// receipts everything still outstanding is received. The unit cost of each item is
+// averaged into the cost price of its variant.
+func ReceivePurchaseOrder(ctx context.Context, q *db.Queries, order db.PurchaseOrder, receipts []PurchaseOrderReceipt) (db.PurchaseOrder, []StockChangeResult, error) {
+	if order.Status != db.PurchaseOrderStatusOrdered && order.Status != db.PurchaseOrderStatusPartiallyReceived {
+		return db.PurchaseOrder{}, nil, ErrPurchaseOrderNotReceivable
+	}
+	location, err := StockLocation(ctx, q, order.ShopID, order.LocationID)
+	if err != nil {
+		return db.PurchaseOrder{}, nil, err
+	}
+	items, err := q.ListPurchaseOrderItems(ctx, db.ListPurchaseOrderItemsParams{
+		PurchaseOrderID: order.PurchaseOrderID,
+		ShopID:          order.ShopID,
+	})
+	if err != nil {
+		return db.PurchaseOrder{}, nil, err
+	}
+	if len(receipts) == 0 {
+		for _, item := range items {
+			if outstanding := item.QuantityOrdered - item.QuantityReceived; outstanding > 0 {
+				receipts = append(receipts, PurchaseOrderReceipt{ItemID: item.PurchaseOrderItemID, Quantity: outstanding})
+			}
+		}
+	}
+
+	results := make([]StockChangeResult, 0, len(receipts))
+	for _, receipt := range receipts {
+		var item *db.ListPurchaseOrderItemsRow
+		for i := range items {
+			if items[i].PurchaseOrderItemID == receipt.ItemID {
+				item = &items[i]
+				break
+			}
+		}
+		if item == nil {
+			return db.PurchaseOrder{}, nil, ErrPurchaseOrderItemNotFound
+		}
+		if _, err := q.ReceivePurchaseOrderItem(ctx, db.ReceivePurchaseOrderItemParams{
+			PurchaseOrderItemID: item.PurchaseOrderItemID,
+			PurchaseOrderID:     order.PurchaseOrderID,
+			ShopID:              order.ShopID,
+			QuantityReceived:    receipt.Quantity,
+		}); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return db.PurchaseOrder{}, nil, ErrPurchaseOrderOverReceipt
+			}
+			return db.PurchaseOrder{}, nil, err
+		}
+
+		if err := CheckStockLot(ctx, q, order.ShopID, item.ProductVariationID, receipt.Lot); err != nil {
+			return db.PurchaseOrder{}, nil, err
+		}
+		onHand, err := q.GetVariantStockOnHand(ctx, db.GetVariantStockOnHandParams{
+			ProductVariationID: item.ProductVariationID,
+			ShopID:             order.ShopID,
+		})
+		if err != nil {
+			return db.PurchaseOrder{}, nil, err
+		}
+		result, err := ChangeStock(ctx, q, StockChange{
+			ShopID:       order.ShopID,
+			VariantID:    item.ProductVariationID,
+			LocationID:   location.LocationID,
+			Operation:    StockAdd,
+			Quantity:     receipt.Quantity,
+			MovementType: "restock",
+			ReferenceID:  &order.PurchaseOrderID,
+			Notes:        order.Note,
+			Lot:          receipt.Lot,
+		})
+		if err != nil {
+			return db.PurchaseOrder{}, nil, err
+		}
+		if result.Variant, err = q.AverageVariantCostPrice(ctx, db.AverageVariantCostPriceParams{
+			UnitCost:           item.UnitCost,
+			OnHand:             onHand,
+			Quantity:           receipt.Quantity,
+			ProductVariationID: item.ProductVariationID,
+			ShopID:             order.ShopID,
+		}); err != nil {
+			return db.PurchaseOrder{}, nil, err
+		}
+		results = append(results, result)
+	}
+
+	order, err = q.SyncPurchaseOrderStatus(ctx, db.SyncPurchaseOrderStatusParams{
+		PurchaseOrderID: order.PurchaseOrderID,
+		ShopID:          order.ShopID,
+	})
+	if err != nil {
+		return db.PurchaseOrder{}, nil, err
+	}
+	return order, results, nil
+}
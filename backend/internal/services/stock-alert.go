@@ -0,0 +1,96 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/petrejonn/naytife/internal/db"
+	"go.uber.org/zap"
+)
+
+// LowStockDigestInterval is how often shops are emailed the variants at or below
+// their reorder point
+const LowStockDigestInterval = 24 * time.Hour
+
+// checkReorderPoint raises a stock alert when a change takes the available
+// quantity of a variant down to its reorder point, or out of stock, and queues an
+// email about it to the shop. Variants without a reorder point raise no alerts.
+func checkReorderPoint(ctx context.Context, q inventoryQueries, shopID, variantID, before, after int64) error {
+	if after >= before {
+		return nil
+	}
+	reorder, err := q.GetVariantReorderPoint(ctx, db.GetVariantReorderPointParams{
+		ProductVariationID: variantID,
+		ShopID:             shopID,
+	})
+	if err != nil {
+		return err
+	}
+	if reorder.ReorderPoint == nil {
+		return nil
+	}
+
+	kind := db.StockAlertKindLowStock
+	if after <= 0 {
+		kind = db.StockAlertKindOutOfStock
+	}
+	crossedReorderPoint := before > *reorder.ReorderPoint && after <= *reorder.ReorderPoint
+	if !crossedReorderPoint && !(before > 0 && after <= 0) {
+		return nil
+	}
+
+	alert, err := q.CreateStockAlert(ctx, db.CreateStockAlertParams{
+		ProductVariationID: variantID,
+		Kind:               kind,
+		AvailableQuantity:  after,
+		ReorderPoint:       *reorder.ReorderPoint,
+		ReorderQuantity:    reorder.ReorderQuantity,
+		ShopID:             shopID,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = q.QueueStockAlertNotification(ctx, db.QueueStockAlertNotificationParams{
+		AlertID: alert.AlertID,
+		ShopID:  shopID,
+	})
+	return err
+}
+
+// RunLowStockDigests emails every shop the variants at or below their reorder
+// point once every LowStockDigestInterval, starting straight away, until ctx is
+// done. Shops are sent at most one digest a day however often it runs, and only
+// one instance of the API queues them at a time.
+func RunLowStockDigests(ctx context.Context, repo db.Repository) {
+	ticker := time.NewTicker(LowStockDigestInterval)
+	defer ticker.Stop()
+	for {
+		queued, err := queueLowStockDigests(ctx, repo)
+		if err != nil {
+			zap.L().Error("RunLowStockDigests: failed to queue digests", zap.Error(err))
+		} else if queued > 0 {
+			zap.L().Info("RunLowStockDigests: queued digests", zap.Int64("count", queued))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// queueLowStockDigests queues the digests when this instance claims the run. An
+// instance that is beaten to the claim queues nothing, and the next run sees the
+// digests the other instance queued.
+func queueLowStockDigests(ctx context.Context, repo db.Repository) (int64, error) {
+	var queued int64
+	err := repo.WithTx(ctx, func(q *db.Queries) error {
+		claimed, err := q.ClaimLowStockDigestRun(ctx)
+		if err != nil || !claimed {
+			return err
+		}
+		queued, err = q.QueueLowStockDigests(ctx)
+		return err
+	})
+	return queued, err
+}
@@ -0,0 +1,46 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/petrejonn/naytife/internal/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckReorderPoint(t *testing.T) {
+	reorderPoint := int64(5)
+
+	tests := []struct {
+		name         string
+		reorderPoint *int64
+		before       int64
+		after        int64
+		want         []db.StockAlertKind
+	}{
+		{"falls to the reorder point", &reorderPoint, 8, 5, []db.StockAlertKind{db.StockAlertKindLowStock}},
+		{"already below the reorder point", &reorderPoint, 4, 2, nil},
+		{"sells out", &reorderPoint, 4, 0, []db.StockAlertKind{db.StockAlertKindOutOfStock}},
+		{"sells out past the reorder point", &reorderPoint, 9, 0, []db.StockAlertKind{db.StockAlertKindOutOfStock}},
+		{"stays above the reorder point", &reorderPoint, 9, 6, nil},
+		{"stock goes up", &reorderPoint, 2, 9, nil},
+		{"no reorder point", nil, 9, 0, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFakeStock(warehouse)
+			f.reorder = db.GetVariantReorderPointRow{ReorderPoint: tt.reorderPoint}
+
+			require.NoError(t, checkReorderPoint(context.Background(), f, 1, 20, tt.before, tt.after))
+			var kinds []db.StockAlertKind
+			for _, alert := range f.alerts {
+				kinds = append(kinds, alert.Kind)
+				assert.Equal(t, tt.after, alert.AvailableQuantity)
+			}
+			assert.Equal(t, tt.want, kinds)
+			assert.Equal(t, len(tt.want), f.notified)
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/petrejonn/naytife/internal/db"
+)
+
+var (
+	ErrStocktakeNotOpen      = errors.New("stocktake is no longer open")
+	ErrStocktakeEmpty        = errors.New("no variants match the stocktake")
+	ErrStocktakeItemNotFound = errors.New("variant is not counted by the stocktake")
+)
+
+// StocktakeCount is a quantity of a variant counted, the variant named by its ID or
+// its SKU
+type StocktakeCount struct {
+	VariantID *int64
+	SKU       *string
+	Quantity  int64
+}
+
+// stocktakeQueries are the queries a stocktake runs, within the caller's
+// transaction. *db.Queries implements them.
+type stocktakeQueries interface {
+	inventoryQueries
+	AddStocktakeCount(ctx context.Context, arg db.AddStocktakeCountParams) (db.StocktakeItem, error)
+	SetStocktakeCount(ctx context.Context, arg db.SetStocktakeCountParams) (db.StocktakeItem, error)
+	CommitStocktake(ctx context.Context, arg db.CommitStocktakeParams) (db.Stocktake, error)
+	ListStocktakeVariances(ctx context.Context, arg db.ListStocktakeVariancesParams) ([]db.ListStocktakeVariancesRow, error)
+}
+
+// OpenStocktake creates a stocktake and adds the variants it counts, each with the
+// stock its location holds of it
+func OpenStocktake(ctx context.Context, q *db.Queries, arg db.CreateStocktakeParams) (db.Stocktake, error) {
+	stocktake, err := q.CreateStocktake(ctx, arg)
+	if err != nil {
+		return db.Stocktake{}, err
+	}
+	added, err := q.AddStocktakeItems(ctx, db.AddStocktakeItemsParams{
+		StocktakeID:   stocktake.StocktakeID,
+		LocationID:    stocktake.LocationID,
+		CategoryID:    stocktake.CategoryID,
+		ProductTypeID: stocktake.ProductTypeID,
+		ShopID:        stocktake.ShopID,
+	})
+	if err != nil {
+		return db.Stocktake{}, err
+	}
+	if added == 0 {
+		return db.Stocktake{}, ErrStocktakeEmpty
+	}
+	return stocktake, nil
+}
+
+// RecordStocktakeCounts records counts against an open stocktake. Counts replace
+// what was counted of a variant before, or add to it for counts made one scan at a
+// time. Each count also takes the stock the location holds at that moment as the
+// stock expected.
+func RecordStocktakeCounts(ctx context.Context, q stocktakeQueries, stocktake db.Stocktake, counts []StocktakeCount, add bool) ([]db.StocktakeItem, error) {
+	if stocktake.Status != db.StocktakeStatusOpen {
+		return nil, ErrStocktakeNotOpen
+	}
+	items := make([]db.StocktakeItem, 0, len(counts))
+	for _, count := range counts {
+		var item db.StocktakeItem
+		var err error
+		if add {
+			item, err = q.AddStocktakeCount(ctx, db.AddStocktakeCountParams{
+				StocktakeID:        stocktake.StocktakeID,
+				ProductVariationID: count.VariantID,
+				Sku:                count.SKU,
+				Quantity:           count.Quantity,
+				ShopID:             stocktake.ShopID,
+			})
+		} else {
+			item, err = q.SetStocktakeCount(ctx, db.SetStocktakeCountParams{
+				StocktakeID:        stocktake.StocktakeID,
+				ProductVariationID: count.VariantID,
+				Sku:                count.SKU,
+				CountedQuantity:    count.Quantity,
+				ShopID:             stocktake.ShopID,
+			})
+		}
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil, ErrStocktakeItemNotFound
+			}
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// CommitStocktake closes an open stocktake and adjusts the stock of every variant
+// counted with a variance by that variance. Stock that moved after a variant was
+// counted, such as sales, is kept; the stock never drops below zero.
+func CommitStocktake(ctx context.Context, q stocktakeQueries, stocktake db.Stocktake) (db.Stocktake, []StockChangeResult, error) {
+	committed, err := q.CommitStocktake(ctx, db.CommitStocktakeParams{
+		StocktakeID: stocktake.StocktakeID,
+		ShopID:      stocktake.ShopID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return db.Stocktake{}, nil, ErrStocktakeNotOpen
+		}
+		return db.Stocktake{}, nil, err
+	}
+	variances, err := q.ListStocktakeVariances(ctx, db.ListStocktakeVariancesParams{
+		StocktakeID: committed.StocktakeID,
+		ShopID:      committed.ShopID,
+	})
+	if err != nil {
+		return db.Stocktake{}, nil, err
+	}
+
+	results := make([]StockChangeResult, 0, len(variances))
+	for _, item := range variances {
+		level, err := q.EnsureInventoryLevel(ctx, db.EnsureInventoryLevelParams{
+			ProductVariationID: item.ProductVariationID,
+			LocationID:         committed.LocationID,
+			ShopID:             committed.ShopID,
+		})
+		if err != nil {
+			return db.Stocktake{}, nil, err
+		}
+		quantity := max(level.Available+*item.CountedQuantity-item.ExpectedQuantity, 0)
+		if quantity == level.Available {
+			continue
+		}
+		result, err := ChangeStock(ctx, q, StockChange{
+			ShopID:       committed.ShopID,
+			VariantID:    item.ProductVariationID,
+			LocationID:   committed.LocationID,
+			Operation:    StockSet,
+			Quantity:     quantity,
+			MovementType: "adjustment",
+			ReferenceID:  &committed.StocktakeID,
+			Notes:        committed.Note,
+		})
+		if err != nil {
+			return db.Stocktake{}, nil, err
+		}
+		results = append(results, result)
+	}
+	return committed, results, nil
+}
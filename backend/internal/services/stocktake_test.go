@@ -0,0 +1,202 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/petrejonn/naytife/internal/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeStocktake counts the variants of one stocktake against the stock held in
+// memory
+type fakeStocktake struct {
+	*fakeStock
+	stocktake db.Stocktake
+	items     map[int64]*db.StocktakeItem
+	skus      map[string]int64
+}
+
+func newFakeStocktake() *fakeStocktake {
+	return &fakeStocktake{
+		fakeStock: newFakeStock(warehouse, shopFloor, backRoom),
+		stocktake: db.Stocktake{StocktakeID: 4, LocationID: warehouse.LocationID, Status: db.StocktakeStatusOpen, ShopID: 1},
+		items:     map[int64]*db.StocktakeItem{},
+		skus:      map[string]int64{},
+	}
+}
+
+// stocked adds a variant to the stocktake with the stock the location holds of it
+func (f *fakeStocktake) stocked(variantID int64, sku string, available int64) {
+	f.setStock(variantID, warehouse.LocationID, available)
+	f.items[variantID] = &db.StocktakeItem{StocktakeID: 4, ProductVariationID: variantID, ExpectedQuantity: available, ShopID: 1}
+	f.skus[sku] = variantID
+}
+
+func (f *fakeStocktake) item(variantID *int64, sku *string) (*db.StocktakeItem, error) {
+	if variantID == nil {
+		id, ok := f.skus[*sku]
+		if !ok {
+			return nil, pgx.ErrNoRows
+		}
+		variantID = &id
+	}
+	item, ok := f.items[*variantID]
+	if !ok {
+		return nil, pgx.ErrNoRows
+	}
+	// Counting takes the stock held at that moment as the stock expected
+	item.ExpectedQuantity = f.stock(*variantID, warehouse.LocationID).Available
+	return item, nil
+}
+
+func (f *fakeStocktake) SetStocktakeCount(ctx context.Context, arg db.SetStocktakeCountParams) (db.StocktakeItem, error) {
+	item, err := f.item(arg.ProductVariationID, arg.Sku)
+	if err != nil {
+		return db.StocktakeItem{}, err
+	}
+	item.CountedQuantity = &arg.CountedQuantity
+	return *item, nil
+}
+
+func (f *fakeStocktake) AddStocktakeCount(ctx context.Context, arg db.AddStocktakeCountParams) (db.StocktakeItem, error) {
+	item, err := f.item(arg.ProductVariationID, arg.Sku)
+	if err != nil {
+		return db.StocktakeItem{}, err
+	}
+	counted := arg.Quantity
+	if item.CountedQuantity != nil {
+		counted += *item.CountedQuantity
+	}
+	item.CountedQuantity = &counted
+	return *item, nil
+}
+
+func (f *fakeStocktake) CommitStocktake(ctx context.Context, arg db.CommitStocktakeParams) (db.Stocktake, error) {
+	if f.stocktake.Status != db.StocktakeStatusOpen {
+		return db.Stocktake{}, pgx.ErrNoRows
+	}
+	f.stocktake.Status = db.StocktakeStatusCommitted
+	return f.stocktake, nil
+}
+
+func (f *fakeStocktake) ListStocktakeVariances(ctx context.Context, arg db.ListStocktakeVariancesParams) ([]db.ListStocktakeVariancesRow, error) {
+	var rows []db.ListStocktakeVariancesRow
+	for _, item := range f.items {
+		if item.CountedQuantity != nil && *item.CountedQuantity != item.ExpectedQuantity {
+			rows = append(rows, db.ListStocktakeVariancesRow{
+				ProductVariationID: item.ProductVariationID,
+				ExpectedQuantity:   item.ExpectedQuantity,
+				CountedQuantity:    item.CountedQuantity,
+				ShopID:             item.ShopID,
+			})
+		}
+	}
+	return rows, nil
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestRecordStocktakeCounts(t *testing.T) {
+	tests := []struct {
+		name    string
+		counts  []StocktakeCount
+		add     bool
+		wantErr error
+		want    int64
+	}{
+		{
+			name:   "counts replace earlier counts",
+			counts: []StocktakeCount{{VariantID: ptr(int64(20)), Quantity: 3}, {VariantID: ptr(int64(20)), Quantity: 5}},
+			want:   5,
+		},
+		{
+			name:   "scans add to earlier counts",
+			counts: []StocktakeCount{{SKU: ptr("TEE-M"), Quantity: 1}, {SKU: ptr("TEE-M"), Quantity: 1}, {VariantID: ptr(int64(20)), Quantity: 1}},
+			add:    true,
+			want:   3,
+		},
+		{
+			name:    "variant outside the stocktake",
+			counts:  []StocktakeCount{{VariantID: ptr(int64(99)), Quantity: 1}},
+			wantErr: ErrStocktakeItemNotFound,
+		},
+		{
+			name:    "unknown SKU",
+			counts:  []StocktakeCount{{SKU: ptr("MUG"), Quantity: 1}},
+			wantErr: ErrStocktakeItemNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFakeStocktake()
+			f.stocked(20, "TEE-M", 8)
+
+			items, err := RecordStocktakeCounts(context.Background(), f, f.stocktake, tt.counts, tt.add)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, items, len(tt.counts))
+			assert.Equal(t, tt.want, *f.items[20].CountedQuantity)
+			assert.Equal(t, int64(8), f.items[20].ExpectedQuantity)
+		})
+	}
+
+	t.Run("stocktake no longer open", func(t *testing.T) {
+		f := newFakeStocktake()
+		f.stocked(20, "TEE-M", 8)
+		f.stocktake.Status = db.StocktakeStatusCommitted
+
+		_, err := RecordStocktakeCounts(context.Background(), f, f.stocktake, []StocktakeCount{{VariantID: ptr(int64(20)), Quantity: 1}}, false)
+		assert.ErrorIs(t, err, ErrStocktakeNotOpen)
+	})
+}
+
+func TestCommitStocktake(t *testing.T) {
+	ctx := context.Background()
+	f := newFakeStocktake()
+	f.stocked(20, "TEE-S", 10)
+	f.stocked(21, "TEE-M", 6)
+	f.stocked(22, "TEE-L", 4)
+	f.stocked(23, "TEE-XL", 2)
+	f.stocked(24, "MUG", 5)
+
+	_, err := RecordStocktakeCounts(ctx, f, f.stocktake, []StocktakeCount{
+		{VariantID: ptr(int64(20)), Quantity: 7},
+		{VariantID: ptr(int64(21)), Quantity: 9},
+		{VariantID: ptr(int64(22)), Quantity: 4},
+		{VariantID: ptr(int64(23)), Quantity: 0},
+	}, false)
+	require.NoError(t, err)
+
+	// Two of variant 21 and both of variant 23 sell after they were counted
+	f.setStock(21, warehouse.LocationID, 4)
+	f.setStock(23, warehouse.LocationID, 0)
+
+	committed, results, err := CommitStocktake(ctx, f, f.stocktake)
+	require.NoError(t, err)
+	assert.Equal(t, db.StocktakeStatusCommitted, committed.Status)
+
+	// Variances apply to the stock held now, never below zero; variants without a
+	// variance or without a count are left alone
+	assert.Equal(t, int64(7), f.stock(20, warehouse.LocationID).Available)
+	assert.Equal(t, int64(7), f.stock(21, warehouse.LocationID).Available)
+	assert.Equal(t, int64(4), f.stock(22, warehouse.LocationID).Available)
+	assert.Equal(t, int64(0), f.stock(23, warehouse.LocationID).Available)
+	assert.Equal(t, int64(5), f.stock(24, warehouse.LocationID).Available)
+	assert.Len(t, results, 2)
+	for _, movement := range f.movements {
+		assert.Equal(t, "adjustment", movement.MovementType)
+		assert.Equal(t, committed.StocktakeID, *movement.ReferenceID)
+	}
+
+	_, _, err = CommitStocktake(ctx, f, f.stocktake)
+	assert.ErrorIs(t, err, ErrStocktakeNotOpen)
+}
@@ -5,12 +5,16 @@ import (
 	"encoding/json"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/petrejonn/naytife/internal/api/models"
 	"github.com/petrejonn/naytife/internal/db"
+	"github.com/petrejonn/naytife/internal/money"
 	"github.com/stripe/stripe-go/v81"
+	"github.com/stripe/stripe-go/v81/customer"
 	"github.com/stripe/stripe-go/v81/paymentintent"
+	"github.com/stripe/stripe-go/v81/paymentmethod"
 	"github.com/stripe/stripe-go/v81/refund"
 	"github.com/stripe/stripe-go/v81/webhook"
 	"go.uber.org/zap"
@@ -118,7 +122,7 @@ func (s *StripeService) ValidateConfig(config map[string]interface{}) error {
 }
 
 // ProcessPayment processes a payment request and returns the payment response
-func (s *StripeService) ProcessPayment(ctx context.Context, shopID int64, req models.PaymentRequest, amount float64, currencyCode string) (*models.PaymentResponse, error) {
+func (s *StripeService) ProcessPayment(ctx context.Context, shopID int64, req models.PaymentRequest, amount money.Money) (*models.PaymentResponse, error) {
 	config, err := s.GetStripeConfig(ctx, shopID)
 	if err != nil {
 		return nil, err
@@ -127,12 +131,10 @@ func (s *StripeService) ProcessPayment(ctx context.Context, shopID int64, req mo
 	// Set the Stripe API key
 	stripe.Key = config.SecretKey
 
-	// Convert amount to cents for Stripe
-	amountCents := int64(amount * 100)
-
+	// Stripe takes amounts in the currency's minor unit
 	params := &stripe.PaymentIntentParams{
-		Amount:   stripe.Int64(amountCents),
-		Currency: stripe.String(currencyCode),
+		Amount:   stripe.Int64(amount.Amount),
+		Currency: stripe.String(strings.ToLower(amount.Currency)),
 		AutomaticPaymentMethods: &stripe.PaymentIntentAutomaticPaymentMethodsParams{
 			Enabled: stripe.Bool(true),
 		},
@@ -160,7 +162,7 @@ func (s *StripeService) ProcessPayment(ctx context.Context, shopID int64, req mo
 		PaymentID:     intent.ID,
 		Status:        s.ConvertStripeStatusToPaymentStatus(intent.Status),
 		Amount:        amount,
-		CurrencyCode:  currencyCode,
+		CurrencyCode:  amount.Currency,
 		PaymentMethod: "stripe",
 		TransactionID: intent.ID,
 	}
@@ -188,12 +190,10 @@ func (s *StripeService) CreatePaymentIntent(ctx context.Context, shopID int64, r
 	// Set the Stripe API key
 	stripe.Key = config.SecretKey
 
-	// Convert amount to cents for Stripe
-	amountCents := int64(req.Amount * 100)
-
+	// Stripe takes amounts in the currency's minor unit
 	params := &stripe.PaymentIntentParams{
-		Amount:   stripe.Int64(amountCents),
-		Currency: stripe.String(req.CurrencyCode),
+		Amount:   stripe.Int64(req.Amount.Amount),
+		Currency: stripe.String(strings.ToLower(req.Amount.Currency)),
 		AutomaticPaymentMethods: &stripe.PaymentIntentAutomaticPaymentMethodsParams{
 			Enabled: stripe.Bool(true),
 		},
@@ -236,7 +236,7 @@ func (s *StripeService) CreatePaymentIntent(ctx context.Context, shopID int64, r
 		ClientSecret:    intent.ClientSecret,
 		Status:          s.ConvertStripeStatusToPaymentStatus(intent.Status),
 		Amount:          req.Amount,
-		CurrencyCode:    req.CurrencyCode,
+		CurrencyCode:    req.Amount.Currency,
 	}
 
 	// Add next action if required
@@ -263,13 +263,13 @@ func (s *StripeService) ConfirmPayment(ctx context.Context, shopID int64, paymen
 		return nil, fmt.Errorf("failed to confirm payment intent: %w", err)
 	}
 
-	amount := float64(intent.Amount) / 100 // Convert from cents
+	currencyCode := strings.ToUpper(string(intent.Currency))
 
 	response := &models.PaymentResponse{
 		PaymentID:     intent.ID,
 		Status:        s.ConvertStripeStatusToPaymentStatus(intent.Status),
-		Amount:        amount,
-		CurrencyCode:  string(intent.Currency),
+		Amount:        money.New(intent.Amount, currencyCode),
+		CurrencyCode:  currencyCode,
 		PaymentMethod: "stripe",
 		TransactionID: intent.ID,
 	}
@@ -302,13 +302,13 @@ func (s *StripeService) GetPaymentStatus(ctx context.Context, shopID int64, paym
 		return nil, fmt.Errorf("failed to retrieve payment intent: %w", err)
 	}
 
-	amount := float64(intent.Amount) / 100 // Convert from cents
+	currencyCode := strings.ToUpper(string(intent.Currency))
 
 	response := &models.PaymentResponse{
 		PaymentID:     intent.ID,
 		Status:        s.ConvertStripeStatusToPaymentStatus(intent.Status),
-		Amount:        amount,
-		CurrencyCode:  string(intent.Currency),
+		Amount:        money.New(intent.Amount, currencyCode),
+		CurrencyCode:  currencyCode,
 		PaymentMethod: "stripe",
 		TransactionID: intent.ID,
 	}
@@ -327,7 +327,7 @@ func (s *StripeService) GetPaymentStatus(ctx context.Context, shopID int64, paym
 }
 
 // RefundPayment processes a refund for a completed payment
-func (s *StripeService) RefundPayment(ctx context.Context, shopID int64, paymentID string, amount float64, reason string) (*models.PaymentResponse, error) {
+func (s *StripeService) RefundPayment(ctx context.Context, shopID int64, paymentID string, amount money.Money, reason string) (*models.PaymentResponse, error) {
 	config, err := s.GetStripeConfig(ctx, shopID)
 	if err != nil {
 		return nil, err
@@ -336,12 +336,9 @@ func (s *StripeService) RefundPayment(ctx context.Context, shopID int64, payment
 	// Set the Stripe API key
 	stripe.Key = config.SecretKey
 
-	// Convert amount to cents for Stripe
-	amountCents := int64(amount * 100)
-
 	params := &stripe.RefundParams{
 		PaymentIntent: stripe.String(paymentID),
-		Amount:        stripe.Int64(amountCents),
+		Amount:        stripe.Int64(amount.Amount),
 	}
 
 	if reason != "" {
@@ -353,14 +350,14 @@ func (s *StripeService) RefundPayment(ctx context.Context, shopID int64, payment
 		return nil, fmt.Errorf("failed to create refund: %w", err)
 	}
 
-	refundAmount := float64(refundObj.Amount) / 100 // Convert from cents
+	currencyCode := strings.ToUpper(string(refundObj.Currency))
 	now := time.Now()
 
 	response := &models.PaymentResponse{
 		PaymentID:     paymentID,
 		Status:        "refunded",
-		Amount:        refundAmount,
-		CurrencyCode:  string(refundObj.Currency),
+		Amount:        money.New(refundObj.Amount, currencyCode),
+		CurrencyCode:  currencyCode,
 		PaymentMethod: "stripe",
 		TransactionID: refundObj.ID,
 		ProcessedAt:   now.Format(time.RFC3339),
@@ -408,11 +405,12 @@ func (s *StripeService) HandleWebhook(ctx context.Context, payload []byte, signa
 				webhookPayload.PaymentID = id
 				webhookPayload.TransactionID = id
 			}
-			if amount, exists := paymentIntent["amount"].(float64); exists {
-				webhookPayload.Amount = amount / 100 // Convert from cents
-			}
 			if currency, exists := paymentIntent["currency"].(string); exists {
-				webhookPayload.Currency = currency
+				webhookPayload.Currency = strings.ToUpper(currency)
+			}
+			if amount, exists := paymentIntent["amount"].(float64); exists {
+				// Stripe reports amounts in the currency's minor unit
+				webhookPayload.Amount = money.New(int64(amount), webhookPayload.Currency)
 			}
 			if status, exists := paymentIntent["status"].(string); exists {
 				webhookPayload.Status = s.ConvertStripeStatusToPaymentStatus(stripe.PaymentIntentStatus(status))
@@ -429,7 +427,7 @@ func (s *StripeService) HandleWebhook(ctx context.Context, payload []byte, signa
 // Backward compatibility methods for existing code
 
 // CreatePaymentIntentFromOrder creates a Stripe PaymentIntent from order data (for backward compatibility)
-func (s *StripeService) CreatePaymentIntentFromOrder(ctx context.Context, shopID int64, req models.PaymentRequest, amount float64, currencyCode string) (*stripe.PaymentIntent, error) {
+func (s *StripeService) CreatePaymentIntentFromOrder(ctx context.Context, shopID int64, req models.PaymentRequest, amount money.Money) (*stripe.PaymentIntent, error) {
 	config, err := s.GetStripeConfig(ctx, shopID)
 	if err != nil {
 		return nil, err
@@ -438,12 +436,9 @@ func (s *StripeService) CreatePaymentIntentFromOrder(ctx context.Context, shopID
 	// Set the Stripe API key
 	stripe.Key = config.SecretKey
 
-	// Convert amount to cents for Stripe
-	amountCents := int64(amount * 100)
-
 	params := &stripe.PaymentIntentParams{
-		Amount:   stripe.Int64(amountCents),
-		Currency: stripe.String(currencyCode),
+		Amount:   stripe.Int64(amount.Amount),
+		Currency: stripe.String(strings.ToLower(amount.Currency)),
 		AutomaticPaymentMethods: &stripe.PaymentIntentAutomaticPaymentMethodsParams{
 			Enabled: stripe.Bool(true),
 		},
@@ -506,3 +501,102 @@ func (s *StripeService) RetrievePaymentIntent(ctx context.Context, shopID int64,
 
 	return intent, nil
 }
+
+// SavePaymentMethod attaches a Stripe payment method to a new Stripe customer so that
+// it can be charged off session. req.Token is the payment method ID.
+func (s *StripeService) SavePaymentMethod(ctx context.Context, shopID int64, req models.SavePaymentMethodRequest) (*models.SavedPaymentMethod, error) {
+	config, err := s.GetStripeConfig(ctx, shopID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Set the Stripe API key
+	stripe.Key = config.SecretKey
+
+	customerParams := &stripe.CustomerParams{
+		Email: stripe.String(req.Email),
+	}
+	if req.Name != "" {
+		customerParams.Name = stripe.String(req.Name)
+	}
+	customerParams.AddMetadata("shop_id", strconv.FormatInt(shopID, 10))
+
+	stripeCustomer, err := customer.New(customerParams)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create customer: %w", err)
+	}
+
+	method, err := paymentmethod.Attach(req.Token, &stripe.PaymentMethodAttachParams{
+		Customer: stripe.String(stripeCustomer.ID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to attach payment method: %w", err)
+	}
+
+	saved := &models.SavedPaymentMethod{
+		CustomerReference: stripeCustomer.ID,
+		Token:             method.ID,
+	}
+	if method.Card != nil {
+		saved.Brand = string(method.Card.Brand)
+		saved.Last4 = method.Card.Last4
+		saved.ExpMonth = int32(method.Card.ExpMonth)
+		saved.ExpYear = int32(method.Card.ExpYear)
+	}
+
+	return saved, nil
+}
+
+// ChargeSavedPaymentMethod charges a saved payment method off session. Charges that
+// need the customer to authenticate fail rather than wait for them.
+func (s *StripeService) ChargeSavedPaymentMethod(ctx context.Context, shopID int64, req models.SavedPaymentChargeRequest) (*models.PaymentResponse, error) {
+	config, err := s.GetStripeConfig(ctx, shopID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Set the Stripe API key
+	stripe.Key = config.SecretKey
+
+	// Stripe takes amounts in the currency's minor unit
+	params := &stripe.PaymentIntentParams{
+		Amount:        stripe.Int64(req.Amount.Amount),
+		Currency:      stripe.String(strings.ToLower(req.Amount.Currency)),
+		Customer:      stripe.String(req.CustomerReference),
+		PaymentMethod: stripe.String(req.Token),
+		OffSession:    stripe.Bool(true),
+		Confirm:       stripe.Bool(true),
+	}
+	if req.Description != "" {
+		params.Description = stripe.String(req.Description)
+	}
+	params.Metadata = map[string]string{
+		"shop_id": strconv.FormatInt(shopID, 10),
+	}
+	for key, value := range req.Metadata {
+		params.Metadata[key] = value
+	}
+	if req.IdempotencyKey != "" {
+		params.SetIdempotencyKey(req.IdempotencyKey)
+	}
+
+	intent, err := paymentintent.New(params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to charge payment method: %w", err)
+	}
+
+	response := &models.PaymentResponse{
+		PaymentID:     intent.ID,
+		Status:        s.ConvertStripeStatusToPaymentStatus(intent.Status),
+		Amount:        req.Amount,
+		CurrencyCode:  req.Amount.Currency,
+		PaymentMethod: "stripe",
+		TransactionID: intent.ID,
+	}
+
+	if intent.Status == stripe.PaymentIntentStatusSucceeded {
+		response.ProcessedAt = time.Now().Format(time.RFC3339)
+	}
+
+	return response, nil
+}
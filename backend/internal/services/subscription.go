@@ -0,0 +1,555 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/petrejonn/naytife/internal/api/models"
+	"github.com/petrejonn/naytife/internal/db"
+	"github.com/petrejonn/naytife/internal/money"
+	"go.uber.org/zap"
+)
+
+// SubscriptionRenewalInterval is how often due subscriptions are renewed
+const SubscriptionRenewalInterval = 15 * time.Minute
+
+// subscriptionRenewalBatch is how many subscriptions are claimed at a time
+const subscriptionRenewalBatch = 50
+
+// SubscriptionRetrySchedule is how long after each failed renewal it is retried.
+// A subscription whose renewal still fails after the last retry is cancelled.
+var SubscriptionRetrySchedule = []time.Duration{24 * time.Hour, 3 * 24 * time.Hour, 5 * 24 * time.Hour}
+
+var (
+	ErrSubscriptionPlanInactive  = errors.New("the subscription plan is not available")
+	ErrSubscriptionNotPausable   = errors.New("only trialing or active subscriptions can be paused")
+	ErrSubscriptionNotPaused     = errors.New("the subscription is not paused")
+	ErrSubscriptionNotSkippable  = errors.New("only trialing or active subscriptions without a renewal in progress can skip a renewal")
+	ErrSubscriptionCancelled     = errors.New("the subscription is cancelled")
+	errSubscriptionPaymentFailed = errors.New("payment was not completed")
+)
+
+// NextRenewal returns the renewal count intervals after from. Monthly and yearly
+// renewals that would fall past the end of a month fall on its last day instead.
+func NextRenewal(from time.Time, unit db.SubscriptionInterval, count int32) time.Time {
+	n := int(count)
+	switch unit {
+	case db.SubscriptionIntervalDay:
+		return from.AddDate(0, 0, n)
+	case db.SubscriptionIntervalWeek:
+		return from.AddDate(0, 0, 7*n)
+	case db.SubscriptionIntervalYear:
+		n *= 12
+	}
+	firstOfMonth := time.Date(from.Year(), from.Month()+time.Month(n), 1, from.Hour(), from.Minute(), from.Second(), from.Nanosecond(), from.Location())
+	lastDay := firstOfMonth.AddDate(0, 1, -1).Day()
+	return firstOfMonth.AddDate(0, 0, min(from.Day(), lastDay)-1)
+}
+
+// SubscriptionStart returns the status and first renewal of a subscription to the
+// plan. Plans with a trial renew when the trial ends; others renew straight away,
+// which places the first order.
+func SubscriptionStart(plan db.SubscriptionPlan, now time.Time) (db.SubscriptionStatus, time.Time) {
+	if plan.TrialDays > 0 {
+		return db.SubscriptionStatusTrialing, now.AddDate(0, 0, int(plan.TrialDays))
+	}
+	return db.SubscriptionStatusActive, now
+}
+
+// PauseSubscription pauses a subscription until resumeOn, or until it is resumed
+// when resumeOn is nil
+func PauseSubscription(ctx context.Context, repo db.Repository, shopID, subscriptionID int64, resumeOn *time.Time) (db.Subscription, error) {
+	pausedUntil := pgtype.Timestamptz{}
+	if resumeOn != nil {
+		pausedUntil = pgtype.Timestamptz{Time: *resumeOn, Valid: true}
+	}
+	subscription, err := repo.PauseSubscription(ctx, db.PauseSubscriptionParams{
+		SubscriptionID: subscriptionID,
+		ShopID:         shopID,
+		PausedUntil:    pausedUntil,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return db.Subscription{}, ErrSubscriptionNotPausable
+	}
+	return subscription, err
+}
+
+// ResumeSubscription resumes a paused subscription
+func ResumeSubscription(ctx context.Context, repo db.Repository, shopID, subscriptionID int64) (db.Subscription, error) {
+	subscription, err := repo.ResumeSubscription(ctx, db.ResumeSubscriptionParams{
+		SubscriptionID: subscriptionID,
+		ShopID:         shopID,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return db.Subscription{}, ErrSubscriptionNotPaused
+	}
+	return subscription, err
+}
+
+// SkipSubscriptionRenewal moves the next renewal of a subscription on by one interval
+// of its plan. A missing subscription fails with pgx.ErrNoRows.
+func SkipSubscriptionRenewal(ctx context.Context, repo db.Repository, shopID, subscriptionID int64) (db.Subscription, error) {
+	current, err := repo.GetSubscription(ctx, db.GetSubscriptionParams{
+		SubscriptionID: subscriptionID,
+		ShopID:         shopID,
+	})
+	if err != nil {
+		return db.Subscription{}, err
+	}
+	subscription, err := repo.SkipSubscriptionRenewal(ctx, db.SkipSubscriptionRenewalParams{
+		SubscriptionID: subscriptionID,
+		ShopID:         shopID,
+		NextRenewalAt: pgtype.Timestamptz{
+			Time:  NextRenewal(current.NextRenewalAt.Time, current.IntervalUnit, current.IntervalCount),
+			Valid: true,
+		},
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return db.Subscription{}, ErrSubscriptionNotSkippable
+	}
+	return subscription, err
+}
+
+// CancelSubscription cancels a subscription along with the renewal order it has
+// not paid for yet. A missing subscription fails with pgx.ErrNoRows.
+func CancelSubscription(ctx context.Context, repo db.Repository, shopID, subscriptionID int64, reason *string) (db.Subscription, error) {
+	var subscription db.Subscription
+	err := repo.WithTx(ctx, func(q *db.Queries) error {
+		current, err := q.GetSubscription(ctx, db.GetSubscriptionParams{
+			SubscriptionID: subscriptionID,
+			ShopID:         shopID,
+		})
+		if err != nil {
+			return err
+		}
+		subscription, err = q.CancelSubscription(ctx, db.CancelSubscriptionParams{
+			SubscriptionID: subscriptionID,
+			ShopID:         shopID,
+			CancelReason:   reason,
+		})
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrSubscriptionCancelled
+		}
+		if err != nil || current.PendingOrderID == nil {
+			return err
+		}
+		_, err = q.CancelUnpaidOrder(ctx, db.CancelUnpaidOrderParams{
+			OrderID:       *current.PendingOrderID,
+			ShopID:        shopID,
+			PaymentStatus: db.PaymentStatusTypeFailed,
+		})
+		return err
+	})
+	return subscription, err
+}
+
+// RunSubscriptionRenewals renews due subscriptions once every
+// SubscriptionRenewalInterval, starting straight away, until ctx is done.
+// Subscriptions paused until now are resumed first.
+func RunSubscriptionRenewals(ctx context.Context, repo db.Repository, payments *PaymentProcessorFactory, delivery *DigitalDelivery) {
+	ticker := time.NewTicker(SubscriptionRenewalInterval)
+	defer ticker.Stop()
+	for {
+		resumed, err := repo.ResumeDueSubscriptions(ctx)
+		if err != nil {
+			zap.L().Error("RunSubscriptionRenewals: failed to resume subscriptions", zap.Error(err))
+		} else if resumed > 0 {
+			zap.L().Info("RunSubscriptionRenewals: resumed subscriptions", zap.Int64("count", resumed))
+		}
+		RenewDueSubscriptions(ctx, repo, payments, delivery)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RenewDueSubscriptions renews every subscription whose renewal or retry is due.
+// A subscription out of stock skips the renewal; one that could not be renewed
+// for want of anything but payment or stock keeps its claim, so it is tried again
+// once the claim expires.
+func RenewDueSubscriptions(ctx context.Context, repo db.Repository, payments *PaymentProcessorFactory, delivery *DigitalDelivery) {
+	for ctx.Err() == nil {
+		subscriptions, err := repo.ClaimDueSubscriptions(ctx, subscriptionRenewalBatch)
+		if err != nil {
+			zap.L().Error("RenewDueSubscriptions: failed to claim subscriptions", zap.Error(err))
+			return
+		}
+		for _, subscription := range subscriptions {
+			if err := renewSubscription(ctx, repo, payments, delivery, subscription); err != nil {
+				zap.L().Error("RenewDueSubscriptions: failed to renew subscription",
+					zap.Int64("subscription_id", subscription.SubscriptionID),
+					zap.Int64("shop_id", subscription.ShopID),
+					zap.Error(err))
+			}
+		}
+		if len(subscriptions) < subscriptionRenewalBatch {
+			return
+		}
+	}
+}
+
+// renewSubscription places the renewal order of a subscription, or takes the one a
+// failed renewal left, and charges its saved payment method for it
+func renewSubscription(ctx context.Context, repo db.Repository, payments *PaymentProcessorFactory, delivery *DigitalDelivery, subscription db.Subscription) error {
+	billing, err := repo.GetSubscriptionBilling(ctx, db.GetSubscriptionBillingParams{
+		SubscriptionID: subscription.SubscriptionID,
+		ShopID:         subscription.ShopID,
+	})
+	if err != nil {
+		return err
+	}
+
+	order, err := subscriptionRenewalOrder(ctx, repo, subscription, billing)
+	if errors.Is(err, ErrOutOfStock) || errors.Is(err, ErrPreorderLimitReached) {
+		return skipOutOfStockRenewal(ctx, repo, subscription, billing, err)
+	}
+	if err != nil {
+		return err
+	}
+
+	amount, err := money.FromNumeric(order.Amount, billing.CurrencyCode)
+	if err != nil {
+		return err
+	}
+	processor := payments.GetProcessor(string(billing.Provider))
+	if processor == nil {
+		return failSubscriptionRenewal(ctx, repo, subscription, billing, &order.OrderID, ErrRecurringPaymentsUnsupported)
+	}
+	// The key changes with every attempt, so that a retry is a new charge but a
+	// renewal interrupted after charging is not charged twice
+	payment, err := processor.ChargeSavedPaymentMethod(ctx, subscription.ShopID, models.SavedPaymentChargeRequest{
+		Amount:            amount,
+		CustomerReference: billing.CustomerReference,
+		Token:             billing.Token,
+		IdempotencyKey:    fmt.Sprintf("subscription-%d-order-%d-attempt-%d", subscription.SubscriptionID, order.OrderID, subscription.FailedAttempts+1),
+		Description:       fmt.Sprintf("Order #%d", order.OrderID),
+		Metadata: map[string]string{
+			"order_id":        fmt.Sprint(order.OrderID),
+			"subscription_id": fmt.Sprint(subscription.SubscriptionID),
+		},
+	})
+	if err == nil && payment.Status != "completed" {
+		err = fmt.Errorf("%w: %s", errSubscriptionPaymentFailed, payment.Status)
+	}
+	if err != nil {
+		return failSubscriptionRenewal(ctx, repo, subscription, billing, &order.OrderID, err)
+	}
+	return completeSubscriptionRenewal(ctx, repo, delivery, subscription, billing, order, payment.TransactionID)
+}
+
+// subscriptionRenewalOrder returns the order a failed renewal of the subscription
+// left unpaid, or places a new one. Renewal orders are priced at the customer's
+// price for the variant less the plan discount, with nothing added for shipping
+// or tax.
+func subscriptionRenewalOrder(ctx context.Context, repo db.Repository, subscription db.Subscription, billing db.GetSubscriptionBillingRow) (db.Order, error) {
+	if subscription.PendingOrderID != nil {
+		return repo.GetOrder(ctx, db.GetOrderParams{
+			OrderID: *subscription.PendingOrderID,
+			ShopID:  subscription.ShopID,
+		})
+	}
+
+	currency := billing.CurrencyCode
+	price, err := money.FromNumeric(billing.Price, currency)
+	if err != nil {
+		return db.Order{}, err
+	}
+	customerPrices, err := CustomerPrices(ctx, repo, subscription.ShopID, currency, subscription.ShopCustomerID, []int64{billing.ProductVariationID})
+	if err != nil {
+		return db.Order{}, err
+	}
+	if customerPrice, ok := customerPrices[billing.ProductVariationID]; ok {
+		price = customerPrice
+	}
+	total := price.Mul(subscription.Quantity)
+	discountPercent, err := billing.DiscountPercent.Float64Value()
+	if err != nil {
+		return db.Order{}, err
+	}
+	discount := total.Convert(discountPercent.Float64/100, currency)
+	zero := money.Zero(currency)
+
+	customerName := billing.CustomerEmail
+	if billing.CustomerName != nil && *billing.CustomerName != "" {
+		customerName = *billing.CustomerName
+	}
+
+	var order db.Order
+	err = repo.WithTx(ctx, func(q *db.Queries) error {
+		var err error
+		if order, err = q.CreateOrder(ctx, db.CreateOrderParams{
+			Status:          db.OrderStatusTypePending,
+			Amount:          total.Sub(discount).Numeric(),
+			Discount:        discount.Numeric(),
+			ShippingCost:    zero.Numeric(),
+			Tax:             zero.Numeric(),
+			ShippingAddress: subscription.ShippingAddress,
+			PaymentMethod:   billing.Provider,
+			PaymentStatus:   db.PaymentStatusTypePending,
+			ShippingMethod:  subscription.ShippingMethod,
+			ShippingStatus:  db.ShippingStatusTypePending,
+			Username:        customerName,
+			ShopCustomerID:  pgtype.UUID{Bytes: subscription.ShopCustomerID, Valid: true},
+			ShopID:          subscription.ShopID,
+			CustomerName:    customerName,
+			CustomerEmail:   &billing.CustomerEmail,
+		}); err != nil {
+			return err
+		}
+		backordered, err := OrderLineBackorder(ctx, q, subscription.ShopID, billing.ProductVariationID, subscription.Quantity)
+		if err != nil {
+			return err
+		}
+		if _, err := q.CreateOrderItem(ctx, db.CreateOrderItemParams{
+			Quantity:            subscription.Quantity,
+			Price:               price.Numeric(),
+			ProductVariationID:  billing.ProductVariationID,
+			OrderID:             order.OrderID,
+			ShopID:              subscription.ShopID,
+			BackorderedQuantity: backordered,
+		}); err != nil {
+			return err
+		}
+		return q.SetSubscriptionPendingOrder(ctx, db.SetSubscriptionPendingOrderParams{
+			SubscriptionID: subscription.SubscriptionID,
+			ShopID:         subscription.ShopID,
+			PendingOrderID: &order.OrderID,
+		})
+	})
+	return order, err
+}
+
+// completeSubscriptionRenewal marks the renewal order paid, the same way a
+// checkout payment does, and moves the subscription on to its next renewal
+func completeSubscriptionRenewal(ctx context.Context, repo db.Repository, delivery *DigitalDelivery, subscription db.Subscription, billing db.GetSubscriptionBillingRow, order db.Order, transactionID string) error {
+	nextRenewal := followingRenewal(subscription, billing, time.Now())
+
+	return repo.WithTx(ctx, func(q *db.Queries) error {
+		if err := q.UpdateOrder(ctx, db.UpdateOrderParams{
+			Status:          db.OrderStatusTypeProcessing,
+			Amount:          order.Amount,
+			Discount:        order.Discount,
+			ShippingCost:    order.ShippingCost,
+			Tax:             order.Tax,
+			ShippingAddress: order.ShippingAddress,
+			PaymentMethod:   order.PaymentMethod,
+			PaymentStatus:   db.PaymentStatusTypePaid,
+			ShippingMethod:  order.ShippingMethod,
+			ShippingStatus:  order.ShippingStatus,
+			TransactionID:   &transactionID,
+			Username:        order.Username,
+			CustomerName:    order.CustomerName,
+			CustomerEmail:   order.CustomerEmail,
+			CustomerPhone:   order.CustomerPhone,
+			OrderID:         order.OrderID,
+			ShopID:          order.ShopID,
+		}); err != nil {
+			return err
+		}
+		if _, err := IssueOrderGiftCards(ctx, q, order, billing.CurrencyCode); err != nil {
+			return err
+		}
+		if _, err := DeliverOrderDigitalGoods(ctx, q, delivery, order); err != nil {
+			return err
+		}
+		// A subscription paused or cancelled while its renewal was charged keeps
+		// the order it paid for but is otherwise left as it is
+		_, err := q.CompleteSubscriptionRenewal(ctx, db.CompleteSubscriptionRenewalParams{
+			SubscriptionID: subscription.SubscriptionID,
+			ShopID:         subscription.ShopID,
+			NextRenewalAt:  pgtype.Timestamptz{Time: nextRenewal, Valid: true},
+		})
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return err
+		}
+		if _, err := q.CreateSubscriptionRenewal(ctx, db.CreateSubscriptionRenewalParams{
+			SubscriptionID:   subscription.SubscriptionID,
+			OrderID:          &order.OrderID,
+			Status:           db.SubscriptionRenewalStatusPaid,
+			RenewalDate:      subscription.NextRenewalAt,
+			PaymentReference: &transactionID,
+			ShopID:           subscription.ShopID,
+		}); err != nil {
+			return err
+		}
+		return queueSubscriptionNotification(ctx, q, "subscription_renewed", subscription, billing, map[string]any{
+			"order_id":        order.OrderID,
+			"amount":          order.Amount,
+			"next_renewal_at": nextRenewal,
+		})
+	})
+}
+
+// followingRenewal returns when a subscription renews after its current renewal.
+// A subscription renewed so late that its next renewal has already passed renews
+// one interval from now instead.
+func followingRenewal(subscription db.Subscription, billing db.GetSubscriptionBillingRow, now time.Time) time.Time {
+	nextRenewal := NextRenewal(subscription.NextRenewalAt.Time, billing.IntervalUnit, billing.IntervalCount)
+	if !nextRenewal.After(now) {
+		nextRenewal = NextRenewal(now, billing.IntervalUnit, billing.IntervalCount)
+	}
+	return nextRenewal
+}
+
+// skipOutOfStockRenewal records a renewal that could not be ordered for want of
+// stock as skipped and moves the subscription on to its next renewal. It is not a
+// failed payment, so it takes no retry and does not count towards cancelling the
+// subscription. The shop is emailed so that it can restock, and the customer so
+// that they know no order is coming.
+func skipOutOfStockRenewal(ctx context.Context, repo db.Repository, subscription db.Subscription, billing db.GetSubscriptionBillingRow, cause error) error {
+	nextRenewal := followingRenewal(subscription, billing, time.Now())
+	message := cause.Error()
+
+	return repo.WithTx(ctx, func(q *db.Queries) error {
+		// A subscription paused or cancelled while it renewed is left as it is
+		_, err := q.SkipSubscriptionRenewal(ctx, db.SkipSubscriptionRenewalParams{
+			SubscriptionID: subscription.SubscriptionID,
+			ShopID:         subscription.ShopID,
+			NextRenewalAt:  pgtype.Timestamptz{Time: nextRenewal, Valid: true},
+		})
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if _, err := q.CreateSubscriptionRenewal(ctx, db.CreateSubscriptionRenewalParams{
+			SubscriptionID: subscription.SubscriptionID,
+			Status:         db.SubscriptionRenewalStatusSkipped,
+			RenewalDate:    subscription.NextRenewalAt,
+			Error:          &message,
+			ShopID:         subscription.ShopID,
+		}); err != nil {
+			return err
+		}
+
+		details := map[string]any{
+			"quantity":        subscription.Quantity,
+			"renewal_date":    subscription.NextRenewalAt.Time,
+			"next_renewal_at": nextRenewal,
+			"error":           message,
+		}
+		if err := queueSubscriptionShopNotification(ctx, q, "subscription_renewal_out_of_stock", subscription, billing, details); err != nil {
+			return err
+		}
+		return queueSubscriptionNotification(ctx, q, "subscription_renewal_skipped", subscription, billing, details)
+	})
+}
+
+// failSubscriptionRenewal records a failed renewal and leaves the subscription past
+// due until its next retry, or cancels it along with its unpaid order once the
+// retries run out. The customer is emailed either way.
+func failSubscriptionRenewal(ctx context.Context, repo db.Repository, subscription db.Subscription, billing db.GetSubscriptionBillingRow, orderID *int64, cause error) error {
+	attempts := subscription.FailedAttempts + 1
+	message := cause.Error()
+
+	return repo.WithTx(ctx, func(q *db.Queries) error {
+		if _, err := q.CreateSubscriptionRenewal(ctx, db.CreateSubscriptionRenewalParams{
+			SubscriptionID: subscription.SubscriptionID,
+			OrderID:        orderID,
+			Status:         db.SubscriptionRenewalStatusFailed,
+			RenewalDate:    subscription.NextRenewalAt,
+			Error:          &message,
+			ShopID:         subscription.ShopID,
+		}); err != nil {
+			return err
+		}
+
+		if int(attempts) <= len(SubscriptionRetrySchedule) {
+			nextRetry := time.Now().Add(SubscriptionRetrySchedule[attempts-1])
+			_, err := q.FailSubscriptionRenewal(ctx, db.FailSubscriptionRenewalParams{
+				SubscriptionID: subscription.SubscriptionID,
+				ShopID:         subscription.ShopID,
+				FailedAttempts: attempts,
+				NextRetryAt:    pgtype.Timestamptz{Time: nextRetry, Valid: true},
+			})
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			return queueSubscriptionNotification(ctx, q, "subscription_payment_failed", subscription, billing, map[string]any{
+				"order_id":      orderID,
+				"attempt":       attempts,
+				"next_retry_at": nextRetry,
+				"error":         message,
+			})
+		}
+
+		reason := "Renewal payment failed"
+		_, err := q.CancelSubscription(ctx, db.CancelSubscriptionParams{
+			SubscriptionID: subscription.SubscriptionID,
+			ShopID:         subscription.ShopID,
+			CancelReason:   &reason,
+		})
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if orderID != nil {
+			if _, err := q.CancelUnpaidOrder(ctx, db.CancelUnpaidOrderParams{
+				OrderID:       *orderID,
+				ShopID:        subscription.ShopID,
+				PaymentStatus: db.PaymentStatusTypeFailed,
+			}); err != nil {
+				return err
+			}
+		}
+		return queueSubscriptionNotification(ctx, q, "subscription_cancelled", subscription, billing, map[string]any{
+			"order_id": orderID,
+			"reason":   reason,
+			"error":    message,
+		})
+	})
+}
+
+// queueSubscriptionNotification queues an email of the given kind about a
+// subscription to its customer
+func queueSubscriptionNotification(ctx context.Context, q *db.Queries, kind string, subscription db.Subscription, billing db.GetSubscriptionBillingRow, details map[string]any) error {
+	payload, err := subscriptionNotificationPayload(subscription, billing, details)
+	if err != nil {
+		return err
+	}
+	if _, err := q.QueueSubscriptionNotification(ctx, db.QueueSubscriptionNotificationParams{
+		Kind:      kind,
+		Recipient: billing.CustomerEmail,
+		Payload:   payload,
+		ShopID:    subscription.ShopID,
+	}); err != nil {
+		return fmt.Errorf("failed to queue %s notification: %w", kind, err)
+	}
+	return nil
+}
+
+// queueSubscriptionShopNotification queues an email of the given kind about a
+// subscription to its shop
+func queueSubscriptionShopNotification(ctx context.Context, q *db.Queries, kind string, subscription db.Subscription, billing db.GetSubscriptionBillingRow, details map[string]any) error {
+	payload, err := subscriptionNotificationPayload(subscription, billing, details)
+	if err != nil {
+		return err
+	}
+	if _, err := q.QueueSubscriptionShopNotification(ctx, db.QueueSubscriptionShopNotificationParams{
+		Kind:    kind,
+		Payload: payload,
+		ShopID:  subscription.ShopID,
+	}); err != nil {
+		return fmt.Errorf("failed to queue %s notification: %w", kind, err)
+	}
+	return nil
+}
+
+func subscriptionNotificationPayload(subscription db.Subscription, billing db.GetSubscriptionBillingRow, details map[string]any) ([]byte, error) {
+	details["subscription_id"] = subscription.SubscriptionID
+	details["product_variation_id"] = billing.ProductVariationID
+	return json.Marshal(details)
+}
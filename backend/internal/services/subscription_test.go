@@ -0,0 +1,54 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/petrejonn/naytife/internal/db"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNextRenewal(t *testing.T) {
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 9, 30, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name  string
+		from  time.Time
+		unit  db.SubscriptionInterval
+		count int32
+		want  time.Time
+	}{
+		{"one day", date(2025, 3, 31), db.SubscriptionIntervalDay, 1, date(2025, 4, 1)},
+		{"two weeks", date(2025, 12, 25), db.SubscriptionIntervalWeek, 2, date(2026, 1, 8)},
+		{"one month", date(2025, 1, 15), db.SubscriptionIntervalMonth, 1, date(2025, 2, 15)},
+		{"month end into february", date(2025, 1, 31), db.SubscriptionIntervalMonth, 1, date(2025, 2, 28)},
+		{"month end into leap february", date(2024, 1, 31), db.SubscriptionIntervalMonth, 1, date(2024, 2, 29)},
+		{"month end into thirty day month", date(2025, 3, 31), db.SubscriptionIntervalMonth, 1, date(2025, 4, 30)},
+		{"clamped day is not carried on", date(2025, 2, 28), db.SubscriptionIntervalMonth, 1, date(2025, 3, 28)},
+		{"three months across a year", date(2025, 11, 30), db.SubscriptionIntervalMonth, 3, date(2026, 2, 28)},
+		{"one year", date(2025, 6, 1), db.SubscriptionIntervalYear, 1, date(2026, 6, 1)},
+		{"leap day yearly", date(2024, 2, 29), db.SubscriptionIntervalYear, 1, date(2025, 2, 28)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, NextRenewal(tt.from, tt.unit, tt.count))
+		})
+	}
+}
+
+func TestFollowingRenewal(t *testing.T) {
+	billing := db.GetSubscriptionBillingRow{IntervalUnit: db.SubscriptionIntervalMonth, IntervalCount: 1}
+	renewal := time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC)
+	subscription := db.Subscription{NextRenewalAt: pgtype.Timestamptz{Time: renewal, Valid: true}}
+
+	// On time, the next renewal keeps to the schedule
+	assert.Equal(t, time.Date(2025, 2, 28, 0, 0, 0, 0, time.UTC), followingRenewal(subscription, billing, renewal.Add(time.Hour)))
+
+	// Late enough to miss the next renewal, it is one interval from now
+	late := time.Date(2025, 3, 2, 12, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2025, 4, 2, 12, 0, 0, 0, time.UTC), followingRenewal(subscription, billing, late))
+}
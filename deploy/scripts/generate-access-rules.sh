@@ -201,6 +201,20 @@ data:
         "authorizer": {"handler": "allow"},
         "mutators": [{"handler": "noop"}]
       },
+      {
+        "id": "api:downloads-public",
+        "upstream": {
+          "preserve_host": true,
+          "url": "$BACKEND_URL"
+        },
+        "match": {
+          "url": "$BASE_URL/v1/downloads/<**>",
+          "methods": ["GET"]
+        },
+        "authenticators": [{"handler": "anonymous"}],
+        "authorizer": {"handler": "allow"},
+        "mutators": [{"handler": "noop"}]
+      },
       {
         "id": "api:health-endpoints",
         "upstream": {
@@ -532,7 +546,7 @@ data:
         },
         "authenticators": [
           {"handler": "anonymous"},
-          {"handler": "jwt"}
+          {"handler": "oauth2_introspection"}
         ],
         "authorizer": {"handler": "allow"},
         "mutators": [{"handler": "noop"}]
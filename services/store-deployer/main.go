@@ -7,6 +7,7 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -365,7 +366,7 @@ func (sd *StoreDeployer) fetchStoreData() (map[string]interface{}, error) {
 	go func() {
 		defer wg.Done()
 		logger.Debug("sending shop GraphQL request", zap.String("backend_url", backendURL), zap.String("subdomain", sd.Subdomain))
-		shopQuery := `query GetShop { shop { id title defaultDomain contactPhone contactEmail address { address } whatsAppNumber whatsAppLink facebookLink instagramLink images { siteLogo { url altText } siteLogoDark { url altText } favicon { url altText } banner { url altText } bannerDark { url altText } coverImage { url altText } coverImageDark { url altText } } currencyCode about shopProductsCategory seoDescription seoKeywords seoTitle paymentMethods { id name provider enabled config { publishableKey testMode } } categories(first: 100) { edges { node { id slug title description images { banner { url altText } } } } } } }`
+		shopQuery := `query GetShop { shop { id title defaultDomain contactPhone contactEmail address { address } whatsAppNumber whatsAppLink facebookLink instagramLink images { siteLogo { url altText renditions { url format width height } } siteLogoDark { url altText renditions { url format width height } } favicon { url altText renditions { url format width height } } banner { url altText renditions { url format width height } } bannerDark { url altText renditions { url format width height } } coverImage { url altText renditions { url format width height } } coverImageDark { url altText renditions { url format width height } } } currencyCode about shopProductsCategory seoDescription seoKeywords seoTitle paymentMethods { id name provider enabled config { publishableKey testMode } } categories(first: 100) { edges { node { id slug title description images { banner { url altText renditions { url format width height } } } } } } } }`
 		shopReq := map[string]interface{}{
 			"query":     shopQuery,
 			"variables": map[string]interface{}{},
@@ -382,7 +383,7 @@ func (sd *StoreDeployer) fetchStoreData() (map[string]interface{}, error) {
 	go func() {
 		defer wg.Done()
 		logger.Debug("sending products GraphQL request", zap.String("backend_url", backendURL), zap.String("subdomain", sd.Subdomain))
-		productsQuery := `query GetProducts($first: Int) { products(first: $first) { edges { node { id productId slug title description attributes { title value } defaultVariant { id variationId price availableQuantity description isDefault attributes { title value } stockStatus } variants { id variationId price availableQuantity description isDefault attributes { title value } stockStatus } images { url altText } updatedAt createdAt } } pageInfo { hasNextPage endCursor } totalCount } }`
+		productsQuery := `query GetProducts($first: Int) { products(first: $first) { edges { node { id productId slug title description attributes { title value } defaultVariant { id variationId price availableQuantity description isDefault attributes { title value } stockStatus } variants { id variationId price availableQuantity description isDefault attributes { title value } stockStatus } images { url altText renditions { url format width height } } rating { average count } updatedAt createdAt } } pageInfo { hasNextPage endCursor } totalCount } }`
 		productsReq := map[string]interface{}{
 			"query":     productsQuery,
 			"variables": map[string]interface{}{"first": 100},
@@ -610,7 +611,7 @@ func transformProductNode(node map[string]interface{}) map[string]interface{} {
 
 	// Get default variant data
 	defaultVariant, _ := node["defaultVariant"].(map[string]interface{})
-	price, _ := defaultVariant["price"].(float64)
+	price := moneyAmount(defaultVariant["price"])
 	stock, _ := defaultVariant["availableQuantity"].(float64)
 	defaultVarId, _ := defaultVariant["variationId"].(float64)
 
@@ -619,6 +620,7 @@ func transformProductNode(node map[string]interface{}) map[string]interface{} {
 
 	// Transform images to simple URL array
 	images := transformImages(node["images"])
+	imageSets := transformImageSets(node["images"])
 
 	// Build optimized product
 	optimized := map[string]interface{}{
@@ -631,11 +633,27 @@ func transformProductNode(node map[string]interface{}) map[string]interface{} {
 		"attributes": attributes,
 	}
 
+	// Only include responsive renditions when at least one image has them
+	if imageSets != nil {
+		optimized["imageSets"] = imageSets
+	}
+
 	// Only include non-empty description
 	if description != "" {
 		optimized["description"] = description
 	}
 
+	// Only include the rating once the product has approved reviews
+	if rating, ok := node["rating"].(map[string]interface{}); ok {
+		if count, _ := rating["count"].(float64); count > 0 {
+			average, _ := rating["average"].(float64)
+			optimized["rating"] = map[string]interface{}{
+				"average": average,
+				"count":   int(count),
+			}
+		}
+	}
+
 	// Simplify timestamp (remove milliseconds)
 	if updatedAt != "" {
 		if t, err := time.Parse(time.RFC3339Nano, updatedAt); err == nil {
@@ -660,7 +678,7 @@ func transformProductNode(node map[string]interface{}) map[string]interface{} {
 			}
 
 			varId, _ := varMap["variationId"].(float64)
-			varPrice, _ := varMap["price"].(float64)
+			varPrice := moneyAmount(varMap["price"])
 			varStock, _ := varMap["availableQuantity"].(float64)
 			varAttrs := transformAttributes(varMap["attributes"])
 
@@ -690,6 +708,20 @@ func transformProductNode(node map[string]interface{}) map[string]interface{} {
 	return optimized
 }
 
+// moneyAmount reads a Money scalar, which the backend sends as a decimal string such
+// as "12.50", as a number for the static JSON
+func moneyAmount(value interface{}) float64 {
+	switch v := value.(type) {
+	case string:
+		amount, _ := strconv.ParseFloat(v, 64)
+		return amount
+	case float64:
+		return v
+	default:
+		return 0
+	}
+}
+
 // transformAttributes converts attribute array to key-value map
 func transformAttributes(attrsData interface{}) map[string]interface{} {
 	attrs := make(map[string]interface{})
@@ -740,6 +772,64 @@ func transformImages(imagesData interface{}) []string {
 	return urls
 }
 
+// transformImageSets extracts the rendition set of each image, parallel to transformImages.
+// Returns nil when no image has renditions so externally hosted images add no payload.
+func transformImageSets(imagesData interface{}) []interface{} {
+	imagesList, ok := imagesData.([]interface{})
+	if !ok {
+		return nil
+	}
+
+	sets := make([]interface{}, 0, len(imagesList))
+	hasRenditions := false
+	for _, img := range imagesList {
+		imgMap, ok := img.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		url, _ := imgMap["url"].(string)
+		if url == "" {
+			continue
+		}
+
+		renditions := make([]interface{}, 0)
+		renditionList, _ := imgMap["renditions"].([]interface{})
+		for _, r := range renditionList {
+			rMap, ok := r.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			rURL, _ := rMap["url"].(string)
+			format, _ := rMap["format"].(string)
+			width, _ := rMap["width"].(float64)
+			height, _ := rMap["height"].(float64)
+			if rURL == "" {
+				continue
+			}
+			renditions = append(renditions, map[string]interface{}{
+				"url":    rURL,
+				"format": format,
+				"width":  int(width),
+				"height": int(height),
+			})
+		}
+		if len(renditions) > 0 {
+			hasRenditions = true
+		}
+
+		sets = append(sets, map[string]interface{}{
+			"url":        url,
+			"renditions": renditions,
+		})
+	}
+
+	if !hasRenditions {
+		return nil
+	}
+	return sets
+}
+
 // updateSelectiveData updates only specific data files based on the data type
 func (sd *StoreDeployer) updateSelectiveData(dataType string) error {
 	logger.Info("updating selective data", zap.String("subdomain", sd.Subdomain), zap.String("data_type", dataType))
@@ -836,30 +926,72 @@ func (sd *StoreDeployer) fetchShopDataOnly() (interface{}, error) {
 						siteLogo {
 							url
 							altText
+							renditions {
+								url
+								format
+								width
+								height
+							}
 						}
 						siteLogoDark {
 							url
 							altText
+							renditions {
+								url
+								format
+								width
+								height
+							}
 						}
 						favicon {
 							url
 							altText
+							renditions {
+								url
+								format
+								width
+								height
+							}
 						}
 						banner {
 							url
 							altText
+							renditions {
+								url
+								format
+								width
+								height
+							}
 						}
 						bannerDark {
 							url
 							altText
+							renditions {
+								url
+								format
+								width
+								height
+							}
 						}
 						coverImage {
 							url
 							altText
+							renditions {
+								url
+								format
+								width
+								height
+							}
 						}
 						coverImageDark {
 							url
 							altText
+							renditions {
+								url
+								format
+								width
+								height
+							}
 						}
 					}
 					currencyCode
@@ -936,6 +1068,16 @@ func (sd *StoreDeployer) fetchProductsDataOnly() (interface{}, error) {
 							images {
 								url
 								altText
+								renditions {
+									url
+									format
+									width
+									height
+								}
+							}
+							rating {
+								average
+								count
 							}
 							updatedAt
 							createdAt